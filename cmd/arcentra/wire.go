@@ -24,6 +24,7 @@ import (
 	"github.com/arcentrix/arcentra/internal/control/router"
 	"github.com/arcentrix/arcentra/internal/control/service"
 	"github.com/arcentrix/arcentra/internal/shared/grpc"
+	"github.com/arcentrix/arcentra/internal/shared/notify"
 	"github.com/arcentrix/arcentra/internal/shared/storage"
 	"github.com/arcentrix/arcentra/pkg/cache"
 	"github.com/arcentrix/arcentra/pkg/database"
@@ -51,7 +52,9 @@ func initApp(configPath string, pluginConfigs map[string]any) (*bootstrap.App, f
 		storage.ProviderSet,
 		// 插件层（依赖 config, database）
 		plugin.ProviderSet,
-		// 服务层（依赖 repo, storage, plugin, database, cache）
+		// 通知层（依赖 repo）
+		notify.ProviderSet,
+		// 服务层（依赖 repo, storage, plugin, database, cache, notify）
 		service.ProviderSet,
 		// 路由层（依赖 config, repo, service, storage, plugin）
 		router.ProviderSet,
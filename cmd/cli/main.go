@@ -15,28 +15,18 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
+	"github.com/arcentrix/arcentra/internal/cli"
 	"github.com/arcentrix/arcentra/pkg/version"
-	"github.com/spf13/cobra"
 )
 
-var rootCmd = &cobra.Command{
-	Use:   "arcentra-cli",
-	Short: "arcentra cli is a command line tool",
-	Long:  "arcentra cli is a command line tool",
-	Run: func(cmd *cobra.Command, _ []string) {
-		err := cmd.Help()
-		if err != nil {
-			return
-		}
-	},
-}
-
-func init() {
-	rootCmd.AddCommand(version.Cmd)
-}
-
 func main() {
+	rootCmd := cli.NewCommand()
+	rootCmd.AddCommand(version.Cmd)
 	if err := rootCmd.Execute(); err != nil {
-		panic(err)
+		_, _ = fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,25 @@
+-- Copyright 2026 Arcentra Authors.
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--      http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- ============================================
+-- Job / Step 输出 — 数据库迁移
+-- ============================================
+-- 步骤通过 $ARCENTRA_OUTPUT (dotenv 格式) 写出输出，Job 通过 outputs 声明对外暴露的值，
+-- 下游 Job 通过 ${{ jobs.<name>.outputs.<key> }} 引用。
+
+-- 1. step_run 表新增 outputs 字段，记录步骤导出的键值对
+ALTER TABLE step_run ADD COLUMN outputs JSON DEFAULT NULL COMMENT '步骤输出（JSON 键值对）' AFTER secrets;
+
+-- 2. job_run 表新增 outputs 字段，记录 Job 声明并解析后的输出
+ALTER TABLE job_run ADD COLUMN outputs JSON DEFAULT NULL COMMENT 'Job 输出（JSON 键值对）' AFTER artifact_uris;
@@ -0,0 +1,36 @@
+-- Copyright 2026 Arcentra Authors.
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--      http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- ============================================
+-- 流水线依赖/构建缓存 — 数据库迁移
+-- ============================================
+-- cache 内置步骤（restore / save）将缓存目录打包为 tar.gz 存入对象存储，
+-- 本表记录缓存索引。缓存键由键前缀与锁文件哈希组成，按项目 / 分支划分作用域，
+-- 过期与超额的缓存由 CacheCleaner 按最近访问时间淘汰。
+
+CREATE TABLE IF NOT EXISTS pipeline_cache (
+    id               BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主键',
+    cache_id         VARCHAR(64)   NOT NULL COMMENT '缓存 ID',
+    project_id       VARCHAR(64)   NOT NULL COMMENT '项目 ID',
+    branch           VARCHAR(255)  NOT NULL DEFAULT '' COMMENT '分支，空表示项目级缓存',
+    cache_key        VARCHAR(512)  NOT NULL COMMENT '缓存键',
+    object_name      VARCHAR(1024) NOT NULL COMMENT '对象存储中的 tar.gz 路径',
+    size             BIGINT        NOT NULL DEFAULT 0 COMMENT '大小（字节）',
+    last_accessed_at DATETIME      DEFAULT NULL COMMENT '最近一次保存或恢复的时间',
+    created_at       DATETIME      DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at       DATETIME      DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    UNIQUE INDEX uk_cache_id (cache_id),
+    INDEX idx_cache_scope (project_id, branch),
+    INDEX idx_last_accessed_at (last_accessed_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='流水线依赖/构建缓存索引表';
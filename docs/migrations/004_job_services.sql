@@ -0,0 +1,22 @@
+-- Copyright 2026 Arcentra Authors.
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--      http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- ============================================
+-- Job 服务容器 — 数据库迁移
+-- ============================================
+-- Job 通过 services 声明服务容器（如 Postgres / Redis），由 Agent 在步骤执行前通过沙箱启动，
+-- Job 结束时回收，并将各服务日志随 Job 状态一并上报。
+
+-- 1. job_run 表新增 service_logs 字段，记录各服务容器的日志尾部（按服务名）
+ALTER TABLE job_run ADD COLUMN service_logs JSON DEFAULT NULL COMMENT '服务容器日志（JSON，服务名 -> 日志）' AFTER outputs;
@@ -0,0 +1,27 @@
+-- Copyright 2026 Arcentra Authors.
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--      http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- ============================================
+-- Pipeline Run 重跑 — 数据库迁移
+-- ============================================
+-- 重跑（all / failed-only / from-job）创建新的 PipelineRun 并关联来源 Run，
+-- 未被选中重新执行的成功 Job 直接复用来源 JobRun 的结果（outputs / artifact_uris）。
+
+-- 1. pipeline_run 表新增重跑来源与模式
+ALTER TABLE pipeline_run ADD COLUMN rerun_of_run_id VARCHAR(64) NOT NULL DEFAULT '' COMMENT '重跑来源 Run ID' AFTER duration;
+ALTER TABLE pipeline_run ADD COLUMN rerun_mode VARCHAR(32) NOT NULL DEFAULT '' COMMENT '重跑模式：all / failed-only / from-job' AFTER rerun_of_run_id;
+CREATE INDEX idx_pipeline_run_rerun_of ON pipeline_run (rerun_of_run_id);
+
+-- 2. job_run 表新增 reused_from 字段，记录复用的来源 JobRun ID
+ALTER TABLE job_run ADD COLUMN reused_from VARCHAR(64) NOT NULL DEFAULT '' COMMENT '复用的来源 JobRun ID，为空表示实际执行' AFTER duration;
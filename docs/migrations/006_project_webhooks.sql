@@ -0,0 +1,42 @@
+-- Copyright 2026 Arcentra Authors.
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--      http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- ============================================
+-- 项目 Webhook 投递 — 数据库迁移
+-- ============================================
+-- 流水线 / Job / Step / 审批事件以签名 JSON（X-Arcentra-Signature-256: sha256=<hex>）
+-- POST 到订阅了该事件的项目 Webhook。每次投递先落库再异步发送，失败按指数退避重试，
+-- 控制面重启后未完成的投递会被重新发送；投递记录可通过接口查询和重新投递。
+
+CREATE TABLE IF NOT EXISTS project_webhook_delivery (
+    id            BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主键',
+    delivery_id   VARCHAR(64)   NOT NULL COMMENT '投递 ID',
+    webhook_id    VARCHAR(64)   NOT NULL COMMENT 'Webhook ID',
+    project_id    VARCHAR(64)   NOT NULL COMMENT '项目 ID',
+    event         VARCHAR(128)  NOT NULL COMMENT '事件名，如 pipeline.completed',
+    payload       JSON          NOT NULL COMMENT '请求体',
+    status        TINYINT       NOT NULL DEFAULT 0 COMMENT '0:等待 1:成功 2:失败',
+    attempts      INT           NOT NULL DEFAULT 0 COMMENT '已尝试次数',
+    response_code INT           NOT NULL DEFAULT 0 COMMENT '最后一次响应状态码',
+    response_body TEXT          COMMENT '最后一次响应体（截断）',
+    error_message TEXT          COMMENT '最后一次错误信息',
+    duration      BIGINT        NOT NULL DEFAULT 0 COMMENT '最后一次请求耗时（毫秒）',
+    redelivery_of VARCHAR(64)   NOT NULL DEFAULT '' COMMENT '重新投递的来源投递 ID',
+    delivered_at  DATETIME      DEFAULT NULL COMMENT '最后一次尝试时间',
+    created_at    DATETIME      DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at    DATETIME      DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    UNIQUE INDEX uk_delivery_id (delivery_id),
+    INDEX idx_webhook_created (webhook_id, created_at),
+    INDEX idx_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='项目 Webhook 投递记录表';
@@ -0,0 +1,46 @@
+-- Copyright 2026 Arcentra Authors.
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--      http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- ============================================
+-- 调试终端会话记录 — 数据库迁移
+-- ============================================
+-- 用户可通过 WebSocket（/ws/terminal）在运行中或失败后保活的步骤工作目录中打开交互式终端，
+-- 终端经由 Agent 的 Connect 双向流转发。每个会话的完整输出（已掩码）、退出码与时长
+-- 记录到本表用于审计。Job 可通过 keep_alive_on_failure 设置失败后保留工作目录与服务的时长。
+
+CREATE TABLE IF NOT EXISTS terminal_output_records (
+    session_id        VARCHAR(64)   NOT NULL PRIMARY KEY COMMENT '会话 ID',
+    session_type      VARCHAR(32)   NOT NULL DEFAULT '' COMMENT '会话类型：build/deploy/release/debug',
+    environment       VARCHAR(32)   NOT NULL DEFAULT '' COMMENT '环境',
+    step_run_id       VARCHAR(64)   NOT NULL DEFAULT '' COMMENT '步骤执行 ID',
+    pipeline_id       VARCHAR(64)   NOT NULL DEFAULT '' COMMENT '流水线 ID',
+    pipeline_run_id   VARCHAR(64)   NOT NULL DEFAULT '' COMMENT '流水线执行 ID',
+    user_id           VARCHAR(64)   NOT NULL DEFAULT '' COMMENT '打开终端的用户 ID',
+    hostname          VARCHAR(255)  NOT NULL DEFAULT '' COMMENT '所在 Agent',
+    working_directory VARCHAR(255)  NOT NULL DEFAULT '' COMMENT '工作目录',
+    command           TEXT          COMMENT '启动命令',
+    exit_code         INT           DEFAULT NULL COMMENT '退出码',
+    logs              JSON          COMMENT '会话输出',
+    metadata          JSON          COMMENT '统计信息',
+    status            VARCHAR(32)   NOT NULL DEFAULT '' COMMENT 'running/completed/failed/timeout',
+    start_time        DATETIME      DEFAULT NULL COMMENT '开始时间',
+    end_time          DATETIME      DEFAULT NULL COMMENT '结束时间',
+    created_at        DATETIME      DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at        DATETIME      DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    INDEX idx_step_run_id (step_run_id),
+    INDEX idx_pipeline_run_id (pipeline_run_id),
+    INDEX idx_user_id (user_id),
+    INDEX idx_status (status),
+    INDEX idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='调试终端会话记录表';
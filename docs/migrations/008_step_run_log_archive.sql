@@ -0,0 +1,37 @@
+-- Copyright 2026 Arcentra Authors.
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--      http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- ============================================
+-- 步骤日志归档 — 数据库迁移
+-- ============================================
+-- 步骤结束一段时间后（logArchive.archiveDelay），其 step_run_logs 中的日志行按
+-- logArchive.chunkLines 分块，压缩为 gzip 的 JSON Lines 存入对象存储
+-- （logs/<step_run_id>/<首行行号>.jsonl.gz），本表记录分块的行号索引。
+-- 已归档步骤的日志按行号范围从归档读取；归档超过 logArchive.retentionHours 后，
+-- step_run_logs 中对应的行被删除。
+
+CREATE TABLE IF NOT EXISTS step_run_log_archive (
+    id           BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主键',
+    step_run_id  VARCHAR(64)   NOT NULL COMMENT '步骤执行 ID',
+    total_lines  INT           NOT NULL DEFAULT 0 COMMENT '日志总行数',
+    size         BIGINT        NOT NULL DEFAULT 0 COMMENT '压缩后总字节数',
+    chunks       JSON          COMMENT '分块索引：对象路径与行号范围',
+    purged       TINYINT       NOT NULL DEFAULT 0 COMMENT '0:数据库仍保留日志行 1:已清理',
+    archived_at  DATETIME      NOT NULL COMMENT '归档时间',
+    purged_at    DATETIME      DEFAULT NULL COMMENT '数据库日志行清理时间',
+    created_at   DATETIME      DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at   DATETIME      DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    UNIQUE INDEX uk_step_run_id (step_run_id),
+    INDEX idx_purged_archived_at (purged, archived_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='步骤日志归档索引表';
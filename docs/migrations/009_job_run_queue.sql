@@ -0,0 +1,42 @@
+-- Copyright 2026 Arcentra Authors.
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--      http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- ============================================
+-- 作业调度队列 — 数据库迁移
+-- ============================================
+-- Agent 作业不再直接投递到任务队列，而是先写入 job_run_queue 排队。调度器按
+-- 优先级（1 最高、10 最低）、项目并发上限（project.settings.max_concurrent）与
+-- 项目间公平份额，在在线 Agent 还有空闲容量时投递；作业结束后记录被清理。
+-- Agent 注册时上报可同时执行的作业数，记入 agent.max_concurrent_jobs 作为容量。
+
+CREATE TABLE IF NOT EXISTS job_run_queue (
+    id               BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主键',
+    job_run_id       VARCHAR(64)   NOT NULL COMMENT '作业执行 ID',
+    project_id       VARCHAR(64)   NOT NULL DEFAULT '' COMMENT '项目 ID，公平份额与并发上限按项目计算',
+    pipeline_id      VARCHAR(64)   NOT NULL DEFAULT '' COMMENT '流水线 ID',
+    pipeline_run_id  VARCHAR(64)   NOT NULL DEFAULT '' COMMENT '流水线执行 ID',
+    job_name         VARCHAR(255)  NOT NULL DEFAULT '' COMMENT '作业名称',
+    priority         INT           NOT NULL DEFAULT 5 COMMENT '优先级 1:最高 5:普通 10:最低',
+    payload          MEDIUMBLOB    NOT NULL COMMENT '投递到任务队列的作业负载',
+    enqueued_at      DATETIME(3)   NOT NULL COMMENT '入队时间',
+    dispatched_at    DATETIME(3)   DEFAULT NULL COMMENT '投递时间，为空表示仍在排队',
+    created_at       DATETIME      DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at       DATETIME      DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    UNIQUE INDEX uk_job_run_id (job_run_id),
+    INDEX idx_dispatched_priority (dispatched_at, priority, enqueued_at),
+    INDEX idx_pipeline_run_id (pipeline_run_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='作业调度队列表';
+
+ALTER TABLE agent
+    ADD COLUMN max_concurrent_jobs INT NOT NULL DEFAULT 1 COMMENT '可同时执行的作业数，注册时上报';
@@ -0,0 +1,37 @@
+-- Copyright 2026 Arcentra Authors.
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--      http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- ============================================
+-- 并发组 — 数据库迁移
+-- ============================================
+-- 流水线与作业可声明并发组（如 deploy-${{ branch }}），同一项目内组名相同的执行按
+-- 先后顺序依次运行（queue），或由新执行取消旧执行（cancel-in-progress）。
+-- 每个等待或持有并发组的执行在 concurrency_lease 中有一条租约，持有者定期续约，
+-- 控制面副本崩溃后租约过期即被清理，从而在多副本间互斥。
+
+CREATE TABLE IF NOT EXISTS concurrency_lease (
+    id           BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主键，决定组内持有顺序',
+    project_id   VARCHAR(64)   NOT NULL DEFAULT '' COMMENT '项目 ID，并发组按项目隔离',
+    group_key    VARCHAR(255)  NOT NULL COMMENT '表达式求值后的组名',
+    run_id       VARCHAR(64)   NOT NULL COMMENT '流水线执行 ID',
+    job_name     VARCHAR(255)  NOT NULL DEFAULT '' COMMENT '作业名称，为空表示流水线级并发组',
+    policy       VARCHAR(32)   NOT NULL DEFAULT 'queue' COMMENT 'queue / cancel-in-progress',
+    held         TINYINT       NOT NULL DEFAULT 0 COMMENT '0:等待 1:持有',
+    superseded   TINYINT       NOT NULL DEFAULT 0 COMMENT '1:已被更新的执行取代',
+    expires_at   DATETIME(3)   NOT NULL COMMENT '租约过期时间',
+    created_at   DATETIME      DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at   DATETIME      DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    INDEX idx_project_group (project_id, group_key, id),
+    INDEX idx_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='并发组租约表';
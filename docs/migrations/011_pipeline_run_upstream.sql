@@ -0,0 +1,23 @@
+-- Copyright 2026 Arcentra Authors.
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--      http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- ============================================
+-- 流水线间触发（pipeline 触发器）— 数据库迁移
+-- ============================================
+-- 上游流水线运行结束后，按下游流水线的 pipeline 触发器创建下游 Run，
+-- 下游 Run 记录触发它的上游 Run，运行图（上下游关系）由此关联。
+
+-- 1. pipeline_run 表新增上游 Run 字段
+ALTER TABLE pipeline_run ADD COLUMN upstream_run_id VARCHAR(64) NOT NULL DEFAULT '' COMMENT '触发本次运行的上游 Run ID' AFTER rerun_mode;
+CREATE INDEX idx_pipeline_run_upstream ON pipeline_run (upstream_run_id);
@@ -0,0 +1,31 @@
+-- Copyright 2026 Arcentra Authors.
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--      http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+
+-- ============================================
+-- 调试终端会话记录分块 — 数据库迁移
+-- ============================================
+-- 终端会话记录不再只在会话结束时整体写入 terminal_output_records.logs：
+-- 会话进行中每满一定行数或每隔数秒即写入一个分块，控制面重启或崩溃时已产生的
+-- 记录不会丢失。用户输入（stdin）与输出一同记录。读取会话时按 seq 顺序拼接分块；
+-- 旧会话仍读取 logs 列。
+
+CREATE TABLE IF NOT EXISTS terminal_transcript_chunks (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY COMMENT '主键',
+    session_id VARCHAR(64)     NOT NULL COMMENT '会话 ID',
+    seq        INT             NOT NULL COMMENT '块序号，从 1 开始',
+    `lines`    JSON            COMMENT '本块记录的行（stdout/stdin）',
+    created_at DATETIME        DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    UNIQUE KEY uk_session_seq (session_id, seq)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='调试终端会话记录分块表';
@@ -0,0 +1,22 @@
+-- Copyright 2026 Arcentra Authors.
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--      http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+
+-- ============================================
+-- PR/MR 触发的运行标记 — 数据库迁移
+-- ============================================
+-- PR/MR 事件构建源分支，源分支名可能与受保护分支相同（例如来自 fork），
+-- 因此由 PR/MR 触发的运行（及其重跑）一律不视为受保护分支，不注入受保护变量。
+
+ALTER TABLE pipeline_run ADD COLUMN change_request TINYINT NOT NULL DEFAULT 0 COMMENT '是否由 PR/MR 触发 0:否 1:是' AFTER upstream_run_id;
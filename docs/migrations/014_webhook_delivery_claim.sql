@@ -0,0 +1,24 @@
+-- Copyright 2026 Arcentra Authors.
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--      http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+
+-- ============================================
+-- Webhook 投递认领 — 数据库迁移
+-- ============================================
+-- 多副本部署时每个副本都会定时补发未完成的投递。发送前先以条件更新认领投递
+-- （仅当状态为等待且未被认领或认领已过期），只有认领成功的副本发送，避免重复投递；
+-- 发送方每次尝试都会刷新认领时间，认领过期即表示发送方已退出。
+
+ALTER TABLE project_webhook_delivery ADD COLUMN claimed_at DATETIME DEFAULT NULL COMMENT '发送方认领时间' AFTER delivered_at;
+CREATE INDEX idx_status_claimed ON project_webhook_delivery (status, claimed_at);
@@ -0,0 +1,33 @@
+-- Copyright 2026 Arcentra Authors.
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--      http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+
+-- ============================================
+-- 流水线触发器登记 — 数据库迁移
+-- ============================================
+-- 下游流水线的 pipeline 触发器在定义保存或推送到默认分支时登记到本表，上游运行结束时
+-- 按上游项目查询匹配，各副本共享同一份登记，无需定时拉取全部流水线定义。
+-- 跨项目触发需上游项目在 settings.downstream_projects 中列出下游项目 ID。
+
+CREATE TABLE IF NOT EXISTS pipeline_upstream (
+    id                  BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY COMMENT '主键',
+    pipeline_id         VARCHAR(64)     NOT NULL COMMENT '下游 Pipeline ID',
+    project_id          VARCHAR(64)     NOT NULL COMMENT '下游 Pipeline 所在项目 ID',
+    upstream_project_id VARCHAR(64)     NOT NULL COMMENT '上游项目 ID',
+    options             JSON            COMMENT '触发器 options',
+    created_at          DATETIME        DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at          DATETIME        DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    KEY idx_pipeline_upstream_pipeline (pipeline_id),
+    KEY idx_pipeline_upstream_project (upstream_project_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='流水线 pipeline 触发器登记表';
@@ -0,0 +1,31 @@
+-- Copyright 2026 Arcentra Authors.
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--      http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+
+-- ============================================
+-- 作业调度按 Agent 选择器计算容量 — 数据库迁移
+-- ============================================
+-- job_run_queue 记录作业各步骤合并后的 Agent 选择器，调度器只用匹配该选择器的在线 Agent
+-- 的并发容量投递作业，避免带标签的作业占用其他 Agent 的空闲容量。
+-- scheduler_lease 为调度租约，多副本部署时每轮调度前抢占，同一时刻只有一个副本投递作业。
+
+ALTER TABLE job_run_queue ADD COLUMN agent_selector JSON DEFAULT NULL COMMENT 'Agent 选择器，为空表示任意 Agent' AFTER payload;
+
+CREATE TABLE IF NOT EXISTS scheduler_lease (
+    name       VARCHAR(64)  NOT NULL PRIMARY KEY COMMENT '租约名称',
+    holder     VARCHAR(64)  NOT NULL DEFAULT '' COMMENT '持有者',
+    expires_at DATETIME(3)  NOT NULL COMMENT '过期时间'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='调度租约表';
+
+INSERT IGNORE INTO scheduler_lease (name, holder, expires_at) VALUES ('job-queue', '', '1970-01-01 00:00:01');
@@ -0,0 +1,35 @@
+-- Copyright 2026 Arcentra Authors.
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--      http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+
+-- ============================================
+-- 流水线自动发现去重 — 数据库迁移
+-- ============================================
+-- 自动发现创建的流水线记录定义文件路径 discovery_path，(project_id, discovery_path) 唯一，
+-- 并发推送同一定义文件时只创建一条流水线。手动创建的流水线该列为 NULL，不受约束，
+-- 同一项目内不同仓库的流水线仍可使用相同的定义文件路径。
+-- 已有的重复自动发现流水线只保留最早的一条参与自动发现，其余按普通流水线保留，可手动删除。
+
+ALTER TABLE pipeline ADD COLUMN discovery_path VARCHAR(512) DEFAULT NULL COMMENT '自动发现的定义文件路径' AFTER pipeline_file_path;
+
+UPDATE pipeline AS p
+JOIN (
+    SELECT MIN(id) AS id
+    FROM pipeline
+    WHERE JSON_UNQUOTE(JSON_EXTRACT(metadata, '$.source')) = 'discovery'
+    GROUP BY project_id, pipeline_file_path
+) AS first_found ON first_found.id = p.id
+SET p.discovery_path = p.pipeline_file_path;
+
+ALTER TABLE pipeline ADD UNIQUE KEY uk_pipeline_discovery_path (project_id, discovery_path);
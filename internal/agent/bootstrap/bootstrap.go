@@ -27,6 +27,7 @@ import (
 	"github.com/arcentrix/arcentra/internal/agent/router"
 	"github.com/arcentrix/arcentra/internal/agent/service"
 	"github.com/arcentrix/arcentra/internal/agent/taskqueue"
+	"github.com/arcentrix/arcentra/internal/agent/terminal"
 	"github.com/arcentrix/arcentra/internal/shared/executor"
 	"github.com/arcentrix/arcentra/internal/shared/grpc"
 	"github.com/arcentrix/arcentra/pkg/cron"
@@ -34,6 +35,7 @@ import (
 	"github.com/arcentrix/arcentra/pkg/metrics"
 	"github.com/arcentrix/arcentra/pkg/outbox"
 	"github.com/arcentrix/arcentra/pkg/safe"
+	"github.com/arcentrix/arcentra/pkg/sandbox"
 	"github.com/arcentrix/arcentra/pkg/shutdown"
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
@@ -51,7 +53,8 @@ type Agent struct {
 	ShutdownMgr   *shutdown.Manager
 	TaskQueue     interface{ Stop() error }
 	Outbox        *outbox.Outbox    // local outbox for reliable event sending; nil when agent id not set
-	ExecManager   *executor.Manager // step executor (ShellExecutor + events via Outbox)
+	ExecManager   *executor.Manager // step executor (Container/ShellExecutor + events via Outbox)
+	Terminals     *terminal.Manager // debug terminals opened in job workspaces
 }
 
 type InitAppFunc func(configPath string) (*Agent, func(), error)
@@ -70,6 +73,33 @@ func NewAgent(
 
 	// Create agent service
 	agentService := service.NewAgentServiceImpl(agentConf, grpcClient, metricsServer)
+	// 沙箱用于运行 Job 服务容器（services）以及指定了 runtime 镜像的步骤，
+	// 未启用时这类 Job 会失败
+	var sb sandbox.Sandbox
+	if agentConf != nil && agentConf.Agent.Sandbox.Enable && logger != nil {
+		var err error
+		if sb, err = sandbox.NewSandboxFromConfig(agentConf, *logger); err != nil {
+			log.Warnw("sandbox init failed, job services and container steps are unavailable", "error", err)
+		} else {
+			taskqueue.SetSandbox(sb)
+			if execManager != nil {
+				execManager.Register(executor.NewContainerExecutor(sb))
+			}
+		}
+	}
+	// 调试终端在 Job 的沙箱容器中打开 shell，输出按 Job 的密钥掩码
+	terminals := terminal.NewManager(sb)
+	taskqueue.SetTerminalManager(terminals)
+	// 构建日志发布到 BUILD_LOGS，容器步骤的输出会实时推送
+	var logPub *executor.KafkaLogPublisher
+	if agentConf != nil && execManager != nil && agentConf.MessageQueue.Kafka.BootstrapServers != "" {
+		var err error
+		if logPub, err = executor.NewKafkaLogPublisher(agentConf.MessageQueue.Kafka, "arcentra-agent-logs"); err != nil {
+			log.Warnw("failed to create build log publisher", "error", err)
+		} else {
+			execManager.SetLogPublisher(logPub)
+		}
+	}
 	taskQueue, err := taskqueue.StartWorker(context.Background(), agentConf, grpcClient, execManager)
 	if err != nil {
 		return nil, nil, err
@@ -106,6 +136,14 @@ func NewAgent(
 				log.Errorw("failed to close outbox", "error", err)
 			}
 		}
+		if logPub != nil {
+			logPub.Close()
+		}
+		if sb != nil {
+			if err := sb.Close(); err != nil {
+				log.Errorw("failed to close sandbox", "error", err)
+			}
+		}
 	}
 
 	app := &Agent{
@@ -119,6 +157,7 @@ func NewAgent(
 		TaskQueue:     taskQueue,
 		Outbox:        ob,
 		ExecManager:   execManager,
+		Terminals:     terminals,
 	}
 	return app, cleanup, nil
 }
@@ -290,6 +329,10 @@ func (app *Agent) waitForRegistrationAndStartHeartbeat() {
 					log.Warnw("heartbeat failed", "error", resp.Message)
 					return
 				}
+				// Connect 双向流承载控制面下发的取消与调试终端指令
+				safe.Go(func() {
+					app.AgentService.RunConnect(context.Background(), app.Terminals)
+				})
 				return
 			}
 		}
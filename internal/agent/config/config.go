@@ -79,6 +79,7 @@ type AgentInfo struct {
 	Labels            map[string]string `mapstructure:"labels"`
 	MaxConcurrentJobs int               `mapstructure:"maxConcurrentJobs"` // Maximum concurrent jobs
 	JobTimeout        int               `mapstructure:"jobTimeout"`        // Job timeout in seconds
+	MaxKeepAlive      int               `mapstructure:"maxKeepAlive"`      // Maximum keep-alive of a failed job in seconds (default: 3600)
 	WorkspaceDir      string            `mapstructure:"workspaceDir"`      // Workspace directory
 	TempDir           string            `mapstructure:"tempDir"`           // Temporary directory
 	LogLevel          string            `mapstructure:"logLevel"`          // Log level
@@ -118,10 +119,12 @@ type ResourceConfig struct {
 
 // KubernetesConfig kubernetes configuration
 type KubernetesConfig struct {
-	Namespace string         `mapstructure:"namespace"` // Kubernetes namespace
-	PodName   string         `mapstructure:"podName"`   // Kubernetes pod name
-	Image     string         `mapstructure:"image"`     // Kubernetes pod image
-	Resources ResourceConfig `mapstructure:"resources"` // Kubernetes pod resources
+	Kubeconfig string         `mapstructure:"kubeconfig"` // Kubeconfig file, empty uses the in-cluster config
+	Namespace  string         `mapstructure:"namespace"`  // Kubernetes namespace
+	PodName    string         `mapstructure:"podName"`    // Kubernetes pod name prefix
+	Image      string         `mapstructure:"image"`      // Kubernetes pod image
+	Resources  ResourceConfig `mapstructure:"resources"`  // Kubernetes pod resources
+	NodeName   string         `mapstructure:"nodeName"`   // Node the agent runs on, empty uses $NODE_NAME
 }
 
 var (
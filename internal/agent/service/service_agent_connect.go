@@ -0,0 +1,121 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	agentv1 "github.com/arcentrix/arcentra/api/agent/v1"
+	"github.com/arcentrix/arcentra/internal/agent/taskqueue"
+	"github.com/arcentrix/arcentra/internal/agent/terminal"
+	"github.com/arcentrix/arcentra/pkg/log"
+)
+
+const (
+	connectMinBackoff = time.Second
+	connectMaxBackoff = 30 * time.Second
+)
+
+// RunConnect keeps the bidirectional Connect stream to the control plane open
+// and serves the commands pushed over it (task cancel, debug terminals). The
+// stream is re-established with backoff until ctx is done.
+func (s *AgentServiceImpl) RunConnect(ctx context.Context, terminals *terminal.Manager) {
+	if s == nil || s.agentConf == nil || s.grpcClient == nil || s.grpcClient.AgentClient == nil {
+		log.Warn("AgentService or grpc client is nil, skipping connect stream")
+		return
+	}
+	backoff := connectMinBackoff
+	for {
+		started := time.Now()
+		err := s.connect(ctx, terminals)
+		if ctx.Err() != nil {
+			return
+		}
+		// a stream that stayed up for a while starts over with a short wait
+		if time.Since(started) > connectMaxBackoff {
+			backoff = connectMinBackoff
+		}
+		log.Warnw("connect stream closed, reconnecting", "error", err, "backoff", backoff.String())
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, connectMaxBackoff)
+	}
+}
+
+// connect runs one Connect stream until it breaks.
+func (s *AgentServiceImpl) connect(ctx context.Context, terminals *terminal.Manager) error {
+	streamCtx, cancel := context.WithCancel(s.grpcClient.WithAuthContext(ctx))
+	defer cancel()
+	stream, err := s.grpcClient.AgentClient.Connect(streamCtx)
+	if err != nil {
+		return fmt.Errorf("open connect stream: %w", err)
+	}
+
+	var mu sync.Mutex
+	send := func(req *agentv1.ConnectRequest) error {
+		mu.Lock()
+		defer mu.Unlock()
+		req.AgentId = s.agentConf.Agent.ID
+		return stream.Send(req)
+	}
+	// the first message identifies the agent to the control plane
+	if err := send(&agentv1.ConnectRequest{Payload: &agentv1.ConnectRequest_Heartbeat{Heartbeat: &agentv1.ConnectHeartbeat{
+		Timestamp:            time.Now().Unix(),
+		Status:               agentv1.AgentStatus_AGENT_STATUS_ONLINE,
+		RunningStepRunsCount: getRunningStepRunsCount(s.metricsServer),
+	}}}); err != nil {
+		return fmt.Errorf("send connect hello: %w", err)
+	}
+	log.Info("connect stream established")
+
+	if terminals != nil {
+		terminals.Attach(send)
+		defer terminals.Detach()
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return errors.New("stream closed by control plane")
+			}
+			return err
+		}
+		if terminals != nil && terminals.Handle(resp) {
+			continue
+		}
+		if cancelReq := resp.GetTaskCancel(); cancelReq != nil {
+			log.Infow("task cancel received over connect", "step_run_id", cancelReq.StepRunId, "reason", cancelReq.Reason)
+			status := agentv1.AckStatus_ACK_STATUS_OK
+			if !taskqueue.CancelStepRun(cancelReq.StepRunId) {
+				status = agentv1.AckStatus_ACK_STATUS_FAILED
+			}
+			_ = send(&agentv1.ConnectRequest{Payload: &agentv1.ConnectRequest_TaskAck{TaskAck: &agentv1.TaskAck{
+				CommandId: resp.CommandId,
+				Status:    status,
+			}}})
+			continue
+		}
+		log.Debugw("unhandled connect command", "command_id", resp.CommandId)
+	}
+}
@@ -26,6 +26,7 @@ func PayloadToExecutionRequest(payload *taskqueue.StepRunTaskPayload, defaultJob
 	}
 	step := &executor.StepInfo{
 		Name:    payload.StepName,
+		RunID:   payload.StepRunID,
 		Uses:    payload.Uses,
 		Action:  payload.Action,
 		Args:    payload.Args,
@@ -55,6 +56,21 @@ func PayloadToExecutionRequest(payload *taskqueue.StepRunTaskPayload, defaultJob
 	if req.Env == nil {
 		req.Env = make(map[string]string)
 	}
+	if rt := payload.Runtime; rt != nil {
+		req.Runtime = &executor.RuntimeInfo{
+			Type:  rt.Type,
+			Image: rt.Image,
+			Env:   rt.Env,
+		}
+		if res := rt.Resources; res != nil {
+			req.Runtime.Resources = &executor.ResourceInfo{
+				CPURequest:    res.CPURequest,
+				CPULimit:      res.CPULimit,
+				MemoryRequest: res.MemoryRequest,
+				MemoryLimit:   res.MemoryLimit,
+			}
+		}
+	}
 	req.Options = &executor.ExecutionOptions{
 		Timeout: executor.ParseTimeout(payload.Timeout, defaultJobTimeoutSec),
 		Extra:   make(map[string]any),
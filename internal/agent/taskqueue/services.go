@@ -0,0 +1,302 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taskqueue
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/shared/executor"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/arcentrix/arcentra/pkg/sandbox"
+	"github.com/arcentrix/arcentra/pkg/taskqueue"
+)
+
+const (
+	// defaultHealthCheckInterval is the wait between two health probes.
+	defaultHealthCheckInterval = 2 * time.Second
+	// defaultHealthCheckTimeout bounds a single health probe.
+	defaultHealthCheckTimeout = 30 * time.Second
+	// defaultHealthCheckRetries is the number of failed probes before giving up.
+	defaultHealthCheckRetries = 30
+	// serviceLogLimit caps the log bytes kept per service on the JobRun.
+	serviceLogLimit = 64 << 10
+	// serviceStopTimeout is the grace period before a service is killed.
+	serviceStopTimeout = 10 * time.Second
+)
+
+var sandboxInstance sandbox.Sandbox
+
+// SetSandbox sets the sandbox used to run job service containers.
+func SetSandbox(sb sandbox.Sandbox) {
+	sandboxInstance = sb
+}
+
+// jobNetworkImage runs the container that owns the network namespace of a job.
+const jobNetworkImage = "registry.k8s.io/pause:3.10"
+
+// jobService is a running service container of a job.
+type jobService struct {
+	name        string
+	containerID string
+}
+
+// jobServices holds the service containers of a job. A pause container owns
+// the job's network namespace and workspace mount; the services, their health
+// probes and the containerized steps join it, so they reach each other on
+// localhost and by service name. On Kubernetes the pause container is the job
+// pod and the others run as its ephemeral containers.
+type jobServices struct {
+	sb        sandbox.Sandbox
+	networkID string
+	hosts     map[string]string
+	services  []jobService
+}
+
+// startJobServices starts the job network and the services of a job in
+// declaration order and waits for their health checks. Jobs without services
+// get the network when their steps run in containers. On error the already
+// started containers are torn down.
+func startJobServices(ctx context.Context, sb sandbox.Sandbox, payload *taskqueue.JobRunTaskPayload, workspace string) (*jobServices, error) {
+	specs := payload.Services
+	if len(specs) == 0 && (sb == nil || !containerRuntime(payload.Runtime)) {
+		return nil, nil
+	}
+	if sb == nil {
+		return nil, fmt.Errorf("job declares services but the agent sandbox is not enabled")
+	}
+	jobRunID := payload.JobRunID
+
+	js := &jobServices{sb: sb}
+	for _, svc := range specs {
+		if js.hosts == nil {
+			js.hosts = make(map[string]string, len(specs))
+		}
+		js.hosts[svc.Name] = "127.0.0.1"
+	}
+
+	network := &sandbox.CreateOptions{
+		Image: jobNetworkImage,
+		Hosts: js.hosts,
+		Labels: map[string]string{
+			"arcentra.job-run-id": jobRunID,
+			"arcentra.role":       "network",
+		},
+	}
+	if workspace != "" {
+		abs, err := filepath.Abs(workspace)
+		if err != nil {
+			return nil, fmt.Errorf("resolve workspace: %w", err)
+		}
+		network.Mounts = []sandbox.Mount{{Source: abs, Target: executor.ContainerWorkspace, Type: "bind"}}
+	}
+	networkID, err := sb.Create(ctx, network)
+	if err != nil {
+		return nil, fmt.Errorf("create job network: %w", err)
+	}
+	js.networkID = networkID
+	if err := sb.Start(ctx, networkID); err != nil {
+		js.teardown(context.Background())
+		return nil, fmt.Errorf("start job network: %w", err)
+	}
+
+	for _, svc := range specs {
+		id, err := sb.Create(ctx, &sandbox.CreateOptions{
+			Image:       svc.Image,
+			Command:     svc.Command,
+			Args:        svc.Args,
+			Env:         svc.Env,
+			NetworkMode: js.networkMode(),
+			Hosts:       js.hosts,
+			Hostname:    svc.Name,
+			Labels: map[string]string{
+				"arcentra.job-run-id": jobRunID,
+				"arcentra.service":    svc.Name,
+			},
+		})
+		if err != nil {
+			js.teardown(context.Background())
+			return nil, fmt.Errorf("create service %s: %w", svc.Name, err)
+		}
+		js.services = append(js.services, jobService{name: svc.Name, containerID: id})
+		if err := sb.Start(ctx, id); err != nil {
+			js.teardown(context.Background())
+			return nil, fmt.Errorf("start service %s: %w", svc.Name, err)
+		}
+		log.Infow("service started", "jobRunId", jobRunID, "service", svc.Name, "containerId", id)
+	}
+
+	for _, svc := range specs {
+		if err := waitServiceHealthy(ctx, sb, js, &svc); err != nil {
+			js.teardown(context.Background())
+			return nil, fmt.Errorf("service %s is unhealthy: %w", svc.Name, err)
+		}
+	}
+	return js, nil
+}
+
+// containerRuntime reports whether the steps of a job run in containers.
+func containerRuntime(rt *taskqueue.RuntimePayload) bool {
+	return rt != nil && strings.TrimSpace(rt.Image) != "" && !strings.EqualFold(rt.Type, executor.RuntimeTypeHost)
+}
+
+// networkMode returns the network mode that joins the job network.
+func (js *jobServices) networkMode() string {
+	return sandbox.NetworkModeContainerPrefix + js.networkID
+}
+
+// attach makes a containerized step join the job network.
+func (js *jobServices) attach(req *executor.ExecutionRequest) {
+	if js == nil || js.networkID == "" || req == nil || req.Runtime == nil {
+		return
+	}
+	req.Runtime.NetworkMode = js.networkMode()
+	req.Runtime.Hosts = js.hosts
+}
+
+// waitServiceHealthy runs the health check command in a probe container on the
+// job network until it exits with 0. Each probe is bounded by the timeout and
+// at most retries probes run.
+func waitServiceHealthy(ctx context.Context, sb sandbox.Sandbox, js *jobServices, svc *taskqueue.ServicePayload) error {
+	hc := svc.HealthCheck
+	if hc == nil || len(hc.Command) == 0 {
+		return nil
+	}
+	interval := parseDurationOr(hc.Interval, defaultHealthCheckInterval)
+	timeout := parseDurationOr(hc.Timeout, defaultHealthCheckTimeout)
+	retries := int(hc.Retries)
+	if retries <= 0 {
+		retries = defaultHealthCheckRetries
+	}
+
+	probeID, err := sb.Create(ctx, &sandbox.CreateOptions{
+		Image:       svc.Image,
+		Command:     hc.Command,
+		Env:         svc.Env,
+		NetworkMode: js.networkMode(),
+		Hosts:       js.hosts,
+	})
+	if err != nil {
+		return fmt.Errorf("create health probe: %w", err)
+	}
+	defer func() { _ = sb.Remove(context.Background(), probeID) }()
+
+	var lastErr error
+	for attempt := 1; attempt <= retries; attempt++ {
+		res, err := sb.Execute(ctx, probeID, hc.Command, &sandbox.ExecuteOptions{Timeout: timeout})
+		switch {
+		case err != nil:
+			lastErr = err
+		case res.ExitCode != 0:
+			lastErr = fmt.Errorf("health check exited with code %d", res.ExitCode)
+		default:
+			return nil
+		}
+		if attempt == retries {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+	return fmt.Errorf("%d health checks failed: %w", retries, lastErr)
+}
+
+// collectLogs returns the tail of each service's logs, keyed by service name.
+func (js *jobServices) collectLogs(ctx context.Context) map[string]string {
+	if js == nil || len(js.services) == 0 {
+		return nil
+	}
+	logs := make(map[string]string, len(js.services))
+	for _, svc := range js.services {
+		rc, err := js.sb.GetLogs(ctx, svc.containerID, nil)
+		if err != nil {
+			log.Warnw("get service logs failed", "service", svc.name, "error", err)
+			continue
+		}
+		data, err := readTail(rc, serviceLogLimit)
+		_ = rc.Close()
+		if err != nil {
+			log.Warnw("read service logs failed", "service", svc.name, "error", err)
+			continue
+		}
+		logs[svc.name] = string(data)
+	}
+	return logs
+}
+
+// teardown stops and removes the service containers in reverse order, then
+// the job network.
+func (js *jobServices) teardown(ctx context.Context) {
+	if js == nil {
+		return
+	}
+	for i := len(js.services) - 1; i >= 0; i-- {
+		svc := js.services[i]
+		if err := js.sb.Stop(ctx, svc.containerID, serviceStopTimeout); err != nil {
+			log.Warnw("stop service failed", "service", svc.name, "error", err)
+		}
+		if err := js.sb.Remove(ctx, svc.containerID); err != nil {
+			log.Warnw("remove service failed", "service", svc.name, "error", err)
+		}
+	}
+	js.services = nil
+	if js.networkID != "" {
+		if err := js.sb.Stop(ctx, js.networkID, serviceStopTimeout); err != nil {
+			log.Warnw("stop job network failed", "containerId", js.networkID, "error", err)
+		}
+		if err := js.sb.Remove(ctx, js.networkID); err != nil {
+			log.Warnw("remove job network failed", "containerId", js.networkID, "error", err)
+		}
+		js.networkID = ""
+	}
+}
+
+// readTail reads r and keeps at most the last limit bytes.
+func readTail(r io.Reader, limit int) ([]byte, error) {
+	buf := make([]byte, 0, limit)
+	chunk := make([]byte, 32<<10)
+	for {
+		n, err := r.Read(chunk)
+		buf = append(buf, chunk[:n]...)
+		if len(buf) > limit {
+			buf = append(buf[:0], buf[len(buf)-limit:]...)
+		}
+		if err == io.EOF {
+			return buf, nil
+		}
+		if err != nil {
+			return buf, err
+		}
+	}
+}
+
+// parseDurationOr parses a Go duration string, returning def when empty or invalid.
+func parseDurationOr(raw string, def time.Duration) time.Duration {
+	if raw == "" {
+		return def
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return def
+	}
+	return d
+}
@@ -0,0 +1,203 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taskqueue
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arcentrix/arcentra/pkg/sandbox"
+	"github.com/arcentrix/arcentra/pkg/taskqueue"
+)
+
+type fakeSandbox struct {
+	created  []*sandbox.CreateOptions
+	removed  []string
+	probes   int
+	healthy  int // probes needed before the health check passes
+	failFrom int // Create fails from this call on (0 = never)
+}
+
+func (f *fakeSandbox) Create(_ context.Context, opts *sandbox.CreateOptions) (string, error) {
+	if f.failFrom > 0 && len(f.created)+1 >= f.failFrom {
+		return "", fmt.Errorf("pull failed")
+	}
+	f.created = append(f.created, opts)
+	return fmt.Sprintf("c%d", len(f.created)), nil
+}
+
+func (f *fakeSandbox) Start(context.Context, string) error { return nil }
+
+func (f *fakeSandbox) Execute(context.Context, string, []string, *sandbox.ExecuteOptions) (*sandbox.ExecuteResult, error) {
+	f.probes++
+	if f.probes < f.healthy {
+		return &sandbox.ExecuteResult{ExitCode: 1}, nil
+	}
+	return &sandbox.ExecuteResult{}, nil
+}
+
+func (f *fakeSandbox) Stop(context.Context, string, time.Duration) error { return nil }
+
+func (f *fakeSandbox) Remove(_ context.Context, id string) error {
+	f.removed = append(f.removed, id)
+	return nil
+}
+
+func (f *fakeSandbox) GetLogs(_ context.Context, id string, _ *sandbox.LogOptions) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("log of " + id)), nil
+}
+
+func (f *fakeSandbox) Cleanup(context.Context) error { return nil }
+func (f *fakeSandbox) Close() error                  { return nil }
+
+func TestStartJobServices(t *testing.T) {
+	sb := &fakeSandbox{healthy: 2}
+	specs := []taskqueue.ServicePayload{
+		{Name: "postgres", Image: "postgres:16", HealthCheck: &taskqueue.HealthCheckPayload{
+			Command: []string{"pg_isready"}, Interval: "1ms", Retries: 3,
+		}},
+		{Name: "redis", Image: "redis:7"},
+	}
+
+	js, err := startJobServices(context.Background(), sb, &taskqueue.JobRunTaskPayload{JobRunID: "jr1", Services: specs}, "/data/ws")
+	if err != nil {
+		t.Fatalf("startJobServices() error = %v", err)
+	}
+	if got := sb.created[0]; got.Image != jobNetworkImage || got.NetworkMode != "" {
+		t.Errorf("job network = %+v, want a pause container", got)
+	}
+	if m := sb.created[0].Mounts; len(m) != 1 || m[0].Source != "/data/ws" || m[0].Target != "/workspace" {
+		t.Errorf("job network mounts = %+v, want the workspace", m)
+	}
+	// services c2, c3 and the health probe c4 join the job network
+	for _, opts := range sb.created[1:] {
+		if opts.NetworkMode != sandbox.NetworkModeContainerPrefix+"c1" {
+			t.Errorf("network mode = %q, want to join c1", opts.NetworkMode)
+		}
+		if opts.Hosts["postgres"] != "127.0.0.1" || opts.Hosts["redis"] != "127.0.0.1" {
+			t.Errorf("hosts = %v, want service names on localhost", opts.Hosts)
+		}
+	}
+	if sb.probes != 2 {
+		t.Errorf("health probes = %d, want 2", sb.probes)
+	}
+
+	logs := js.collectLogs(context.Background())
+	if logs["postgres"] != "log of c2" || logs["redis"] != "log of c3" {
+		t.Errorf("collectLogs() = %v", logs)
+	}
+
+	js.teardown(context.Background())
+	// probe c4 was removed after the health check, then services in reverse order and the network
+	if got := strings.Join(sb.removed, ","); got != "c4,c3,c2,c1" {
+		t.Errorf("removed = %s, want c4,c3,c2,c1", got)
+	}
+}
+
+func TestJobServicesAttach(t *testing.T) {
+	sb := &fakeSandbox{}
+	specs := []taskqueue.ServicePayload{{Name: "postgres", Image: "postgres:16"}}
+	js, err := startJobServices(context.Background(), sb, &taskqueue.JobRunTaskPayload{JobRunID: "jr1", Services: specs}, "")
+	if err != nil {
+		t.Fatalf("startJobServices() error = %v", err)
+	}
+
+	req := PayloadToExecutionRequest(&taskqueue.StepRunTaskPayload{
+		StepName: "test",
+		Runtime:  &taskqueue.RuntimePayload{Type: "docker", Image: "golang:1.24"},
+	}, 60)
+	js.attach(req)
+	if req.Runtime.NetworkMode != sandbox.NetworkModeContainerPrefix+"c1" {
+		t.Errorf("step network mode = %q, want to join c1", req.Runtime.NetworkMode)
+	}
+	if req.Runtime.Hosts["postgres"] != "127.0.0.1" {
+		t.Errorf("step hosts = %v", req.Runtime.Hosts)
+	}
+
+	// Jobs without services keep the default network
+	var none *jobServices
+	req = PayloadToExecutionRequest(&taskqueue.StepRunTaskPayload{
+		StepName: "test",
+		Runtime:  &taskqueue.RuntimePayload{Type: "docker", Image: "golang:1.24"},
+	}, 60)
+	none.attach(req)
+	if req.Runtime.NetworkMode != "" || req.Runtime.Hosts != nil {
+		t.Errorf("runtime = %+v, want default network", req.Runtime)
+	}
+}
+
+func TestStartJobServicesForContainerSteps(t *testing.T) {
+	sb := &fakeSandbox{}
+	payload := &taskqueue.JobRunTaskPayload{JobRunID: "jr1"}
+	if js, err := startJobServices(context.Background(), sb, payload, ""); err != nil || js != nil {
+		t.Fatalf("host job = %v, %v, want no job network", js, err)
+	}
+
+	// Containerized steps share one job network, and so one pod on Kubernetes
+	payload.Runtime = &taskqueue.RuntimePayload{Type: "docker", Image: "golang:1.24"}
+	js, err := startJobServices(context.Background(), sb, payload, "")
+	if err != nil {
+		t.Fatalf("startJobServices() error = %v", err)
+	}
+	if len(sb.created) != 1 || sb.created[0].Image != jobNetworkImage {
+		t.Fatalf("created = %+v, want only the job network", sb.created)
+	}
+	if js.networkMode() != sandbox.NetworkModeContainerPrefix+"c1" || js.hosts != nil {
+		t.Errorf("job network = %+v", js)
+	}
+	if js, err := startJobServices(context.Background(), nil, payload, ""); err != nil || js != nil {
+		t.Errorf("without sandbox = %v, %v, want steps on the host", js, err)
+	}
+}
+
+func TestStartJobServicesFailure(t *testing.T) {
+	specs := []taskqueue.ServicePayload{{Name: "db", Image: "postgres:16"}, {Name: "cache", Image: "redis:7"}}
+
+	payload := &taskqueue.JobRunTaskPayload{JobRunID: "jr1", Services: specs}
+	if _, err := startJobServices(context.Background(), nil, payload, ""); err == nil {
+		t.Errorf("startJobServices() without sandbox expected error")
+	}
+
+	sb := &fakeSandbox{failFrom: 3}
+	if _, err := startJobServices(context.Background(), sb, payload, ""); err == nil {
+		t.Fatalf("startJobServices() expected error")
+	}
+	if got := strings.Join(sb.removed, ","); got != "c2,c1" {
+		t.Errorf("removed = %s, want started services and network torn down", got)
+	}
+
+	sb = &fakeSandbox{healthy: 10}
+	specs[0].HealthCheck = &taskqueue.HealthCheckPayload{Command: []string{"pg_isready"}, Interval: "1ms", Retries: 2}
+	if _, err := startJobServices(context.Background(), sb, payload, ""); err == nil {
+		t.Fatalf("startJobServices() expected unhealthy error")
+	}
+	if sb.probes != 2 {
+		t.Errorf("health probes = %d, want 2", sb.probes)
+	}
+}
+
+func TestReadTail(t *testing.T) {
+	data, err := readTail(strings.NewReader(strings.Repeat("a", 100)+"end"), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "aaaaaaaend" {
+		t.Errorf("readTail() = %q", data)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taskqueue
+
+import (
+	"context"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/agent/terminal"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/arcentrix/arcentra/pkg/taskqueue"
+)
+
+// defaultMaxKeepAlive caps keep_alive_on_failure when the agent sets no maximum.
+const defaultMaxKeepAlive = time.Hour
+
+var terminalManager *terminal.Manager
+
+// SetTerminalManager sets the manager that opens debug terminals in the
+// workspaces of running jobs.
+func SetTerminalManager(m *terminal.Manager) {
+	terminalManager = m
+}
+
+// trackJobWorkspace makes the job available to debug terminals. Terminals
+// run in the job's runtime image with its workspace mounted and join the job
+// network when there is one, so they see what the steps saw.
+func trackJobWorkspace(payload *taskqueue.JobRunTaskPayload, workspace string, services *jobServices) {
+	if terminalManager == nil {
+		return
+	}
+	stepRunIDs := make([]string, 0, len(payload.Steps))
+	for _, step := range payload.Steps {
+		stepRunIDs = append(stepRunIDs, step.StepRunID)
+	}
+	job := terminal.Job{
+		Workspace:  workspace,
+		Env:        payload.Env,
+		StepRunIDs: stepRunIDs,
+		Secrets:    payload.Secrets,
+	}
+	if containerRuntime(payload.Runtime) {
+		job.Image = payload.Runtime.Image
+	}
+	if services != nil && services.networkID != "" {
+		// Match the workspace mount of the job network.
+		job.Workspace = payload.Workspace
+		job.NetworkMode = services.networkMode()
+		job.Hosts = services.hosts
+	}
+	terminalManager.TrackJob(payload.JobRunID, job)
+}
+
+// untrackJobWorkspace closes the debug terminals of the job.
+func untrackJobWorkspace(jobRunID string) {
+	if terminalManager != nil {
+		terminalManager.UntrackJob(jobRunID)
+	}
+}
+
+// keepAliveDuration returns how long a failed job keeps its workspace and
+// services around for debugging, zero when it does not. The pipeline's value
+// is capped at maxSeconds, or defaultMaxKeepAlive when it is not positive,
+// since the job keeps its slot on the agent meanwhile.
+func keepAliveDuration(payload *taskqueue.JobRunTaskPayload, maxSeconds int) time.Duration {
+	if payload.KeepAliveOnFailure == "" || terminalManager == nil {
+		return 0
+	}
+	d, err := time.ParseDuration(payload.KeepAliveOnFailure)
+	if err != nil || d <= 0 {
+		return 0
+	}
+	limit := defaultMaxKeepAlive
+	if maxSeconds > 0 {
+		limit = time.Duration(maxSeconds) * time.Second
+	}
+	if d > limit {
+		log.Warnw("keep_alive_on_failure exceeds the agent maximum", "jobRunId", payload.JobRunID, "requested", d.String(), "max", limit.String())
+		return limit
+	}
+	return d
+}
+
+// holdForDebug blocks until the keep-alive window of a failed job ends or the
+// worker shuts down. The job slot stays taken meanwhile, as its services do.
+func holdForDebug(ctx context.Context, jobRunID string, d time.Duration) {
+	log.Infow("keeping failed job alive for debugging", "jobRunId", jobRunID, "duration", d.String())
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taskqueue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/agent/terminal"
+	"github.com/arcentrix/arcentra/pkg/taskqueue"
+)
+
+func TestKeepAliveDuration(t *testing.T) {
+	prev := terminalManager
+	defer func() { terminalManager = prev }()
+	terminalManager = terminal.NewManager(nil)
+
+	tests := []struct {
+		keepAlive  string
+		maxSeconds int
+		want       time.Duration
+	}{
+		{"", 0, 0},
+		{"bogus", 0, 0},
+		{"15m", 0, 15 * time.Minute},
+		{"48h", 0, defaultMaxKeepAlive},
+		{"15m", 600, 10 * time.Minute},
+		{"5m", 600, 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		payload := &taskqueue.JobRunTaskPayload{JobRunID: "jr1", KeepAliveOnFailure: tt.keepAlive}
+		if got := keepAliveDuration(payload, tt.maxSeconds); got != tt.want {
+			t.Errorf("keepAliveDuration(%q, %d) = %v, want %v", tt.keepAlive, tt.maxSeconds, got, tt.want)
+		}
+	}
+
+	terminalManager = nil
+	if got := keepAliveDuration(&taskqueue.JobRunTaskPayload{KeepAliveOnFailure: "15m"}, 0); got != 0 {
+		t.Errorf("keepAliveDuration() without terminals = %v, want 0", got)
+	}
+}
@@ -29,9 +29,13 @@ import (
 	"github.com/arcentrix/arcentra/internal/agent/config"
 	"github.com/arcentrix/arcentra/internal/shared/executor"
 	"github.com/arcentrix/arcentra/internal/shared/grpc"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/builtin"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/interceptor"
 	"github.com/arcentrix/arcentra/internal/shared/storage"
 	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/arcentrix/arcentra/pkg/logstream"
 	"github.com/arcentrix/arcentra/pkg/nova"
+	"github.com/arcentrix/arcentra/pkg/safe"
 	"github.com/arcentrix/arcentra/pkg/taskqueue"
 	"github.com/bytedance/sonic"
 )
@@ -88,6 +92,11 @@ func StartWorker(
 		return nil, fmt.Errorf("create task queue: %w", err)
 	}
 
+	// Job runs execute concurrently up to maxConcurrentJobs, the capacity
+	// the agent reports at registration. The consumer blocks while all
+	// slots are busy so further jobs stay on the topic.
+	jobSlots := make(chan struct{}, max(agentConf.Agent.MaxConcurrentJobs, 1))
+
 	handler := nova.HandlerFunc(func(ctx context.Context, task *nova.Task) error {
 		if task == nil {
 			return nil
@@ -103,7 +112,18 @@ func StartWorker(
 				"jobName", payload.JobName,
 				"steps", len(payload.Steps),
 			)
-			return executeJobRun(ctx, agentConf, grpcClient, execManager, &payload)
+			select {
+			case jobSlots <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			safe.Go(func() {
+				defer func() { <-jobSlots }()
+				if err := executeJobRun(ctx, agentConf, grpcClient, execManager, &payload); err != nil {
+					log.Warnw("job run failed", "jobRunId", payload.JobRunID, "error", err)
+				}
+			})
+			return nil
 		case taskqueue.TaskTypeStepRun:
 			var payload taskqueue.StepRunTaskPayload
 			if err := sonic.Unmarshal(task.Payload, &payload); err != nil {
@@ -115,7 +135,7 @@ func StartWorker(
 				"stepName", payload.StepName,
 			)
 			if execManager != nil {
-				return executeStepRunViaExecutor(ctx, agentConf, grpcClient, execManager, &payload)
+				return executeStepRunViaExecutor(ctx, agentConf, grpcClient, execManager, &payload, nil)
 			}
 			return executeStepRun(ctx, agentConf, grpcClient, &payload)
 		default:
@@ -157,6 +177,7 @@ func executeStepRunViaExecutor(
 	grpcClient *grpc.ClientWrapper,
 	execManager *executor.Manager,
 	payload *taskqueue.StepRunTaskPayload,
+	services *jobServices,
 ) error {
 	if payload == nil {
 		return nil
@@ -169,9 +190,12 @@ func executeStepRunViaExecutor(
 	}()
 
 	start := time.Now()
-	_ = reportStepRunStatus(grpcClient, agentConf, payload.StepRunID, steprunv1.StepRunStatus_STEP_RUN_STATUS_RUNNING, 0, "", start, 0, nil)
+	_ = reportStepRunStatus(grpcClient, agentConf, payload.StepRunID, steprunv1.StepRunStatus_STEP_RUN_STATUS_RUNNING, 0, "", start, 0, nil, nil)
 
 	req := PayloadToExecutionRequest(payload, agentConf.Agent.JobTimeout)
+	// Secrets received with the task are masked in everything the step emits.
+	req.Masker = logstream.NewMasker(payload.Secrets...)
+	services.attach(req)
 	result, execErr := execManager.Execute(stepCtx, req)
 	end := time.Now().Unix()
 	metrics := map[string]string{"executor": "agent-shell"}
@@ -186,7 +210,11 @@ func executeStepRunViaExecutor(
 		metrics["outputBytes"] = fmt.Sprintf("%d", len(result.Output))
 	}
 	status, exitCode, errMsg := resultToStepRunStatus(stepCtx, result, execErr)
-	_ = reportStepRunStatus(grpcClient, agentConf, payload.StepRunID, status, exitCode, errMsg, start, end, metrics)
+	var outputs map[string]string
+	if status == steprunv1.StepRunStatus_STEP_RUN_STATUS_SUCCESS {
+		outputs = readStepOutputs(payload)
+	}
+	_ = reportStepRunStatus(grpcClient, agentConf, payload.StepRunID, status, exitCode, errMsg, start, end, metrics, outputs)
 	return execErr
 }
 
@@ -270,7 +298,7 @@ func executeJobRun(
 		grpcClient, agentConf, payload.JobRunID,
 		agentv1.AgentStatus_AGENT_STATUS_BUSY,
 		int32(steprunv1.StepRunStatus_STEP_RUN_STATUS_RUNNING),
-		"", start, 0,
+		"", start, 0, nil, nil,
 	)
 
 	workspace := payload.Workspace
@@ -281,20 +309,37 @@ func executeJobRun(
 	if err := handleSourceClone(jobCtx, payload.Source, workspace); err != nil {
 		end := time.Now().Unix()
 		reportJobRunStatus(grpcClient, agentConf, payload.JobRunID, agentv1.AgentStatus_AGENT_STATUS_IDLE,
-			int32(steprunv1.StepRunStatus_STEP_RUN_STATUS_FAILED), err.Error(), start, end)
+			int32(steprunv1.StepRunStatus_STEP_RUN_STATUS_FAILED), err.Error(), start, end, nil, nil)
 		return fmt.Errorf("source clone failed: %w", err)
 	}
 
 	handleArtifactDownload(jobCtx, payload.ArtifactURIs, workspace, storageInstance)
 
+	services, err := startJobServices(jobCtx, sandboxInstance, payload, payload.Workspace)
+	if err != nil {
+		end := time.Now().Unix()
+		reportJobRunStatus(grpcClient, agentConf, payload.JobRunID, agentv1.AgentStatus_AGENT_STATUS_IDLE,
+			int32(steprunv1.StepRunStatus_STEP_RUN_STATUS_FAILED), err.Error(), start, end, nil, nil)
+		return fmt.Errorf("start services failed: %w", err)
+	}
+
+	trackJobWorkspace(payload, workspace, services)
+
+	outputsDir := filepath.Join(workspace, ".arcentra", "outputs")
+	if err := os.MkdirAll(outputsDir, 0o755); err != nil {
+		log.Warnw("create step outputs dir failed", "jobRunId", payload.JobRunID, "error", err)
+	}
+	stepOutputs := make(map[string]map[string]string, len(payload.Steps))
+
 	var jobErr error
 	for _, step := range payload.Steps {
+		stepOutputs[step.Name] = map[string]string{}
 		if jobCtx.Err() != nil {
 			break
 		}
 		stepStart := time.Now()
 		_ = reportStepRunStatus(grpcClient, agentConf, step.StepRunID,
-			steprunv1.StepRunStatus_STEP_RUN_STATUS_RUNNING, 0, "", stepStart, 0, nil)
+			steprunv1.StepRunStatus_STEP_RUN_STATUS_RUNNING, 0, "", stepStart, 0, nil, nil)
 
 		stepPayload := &taskqueue.StepRunTaskPayload{
 			PipelineID:    payload.PipelineID,
@@ -310,21 +355,41 @@ func executeJobRun(
 			Env:           mergeEnv(payload.Env, step.Env),
 			Workspace:     payload.Workspace,
 			Timeout:       step.Timeout,
+			Outputs:       step.Outputs,
+			Runtime:       payload.Runtime,
+			Secrets:       payload.Secrets,
+		}
+		if stepPayload.Env == nil {
+			stepPayload.Env = make(map[string]string, 1)
 		}
+		stepPayload.Env[builtin.OutputFileEnv] = filepath.Join(outputsDir, step.StepRunID+".env")
 
 		var stepErr error
 		if execManager != nil {
-			stepErr = executeStepRunViaExecutor(jobCtx, agentConf, grpcClient, execManager, stepPayload)
+			stepErr = executeStepRunViaExecutor(jobCtx, agentConf, grpcClient, execManager, stepPayload, services)
 		} else {
 			stepErr = executeStepRun(jobCtx, agentConf, grpcClient, stepPayload)
 		}
 
+		if stepErr == nil {
+			stepOutputs[step.Name] = readStepOutputs(stepPayload)
+		}
 		if stepErr != nil && !step.ContinueOnError {
 			jobErr = fmt.Errorf("step %s failed: %w", step.Name, stepErr)
 			break
 		}
 	}
 
+	var jobOutputs map[string]string
+	if jobErr == nil && jobCtx.Err() == nil {
+		var err error
+		if jobOutputs, err = interceptor.EvaluateJobOutputs(payload.Outputs, stepOutputs); err != nil {
+			jobErr = fmt.Errorf("resolve job outputs: %w", err)
+		}
+	}
+
+	serviceLogs := services.collectLogs(context.Background())
+
 	end := time.Now().Unix()
 	status := int32(steprunv1.StepRunStatus_STEP_RUN_STATUS_SUCCESS)
 	errMsg := ""
@@ -341,10 +406,35 @@ func executeJobRun(
 		errMsg = "job cancelled"
 	}
 
-	reportJobRunStatus(grpcClient, agentConf, payload.JobRunID, agentv1.AgentStatus_AGENT_STATUS_IDLE, status, errMsg, start, end)
+	reportJobRunStatus(grpcClient, agentConf, payload.JobRunID, agentv1.AgentStatus_AGENT_STATUS_IDLE, status, errMsg, start, end, jobOutputs, serviceLogs)
+
+	// A failed job may keep its workspace and services for a debug terminal.
+	if status == int32(steprunv1.StepRunStatus_STEP_RUN_STATUS_FAILED) {
+		if d := keepAliveDuration(payload, agentConf.Agent.MaxKeepAlive); d > 0 {
+			holdForDebug(ctx, payload.JobRunID, d)
+		}
+	}
+	untrackJobWorkspace(payload.JobRunID)
+	// Services are torn down with a fresh context so a cancelled job still cleans up.
+	services.teardown(context.Background())
 	return jobErr
 }
 
+// readStepOutputs reads the dotenv file the step wrote to $ARCENTRA_OUTPUT
+// and applies the step's declared outputs.
+func readStepOutputs(payload *taskqueue.StepRunTaskPayload) map[string]string {
+	path := payload.Env[builtin.OutputFileEnv]
+	if path == "" {
+		return nil
+	}
+	values, err := builtin.ParseDotenvFile(path)
+	if err != nil {
+		log.Warnw("read step outputs failed", "stepRunId", payload.StepRunID, "error", err)
+		return nil
+	}
+	return builtin.SelectOutputs(payload.Outputs, values)
+}
+
 // reportJobRunStatus reports job run status to the control plane using the
 // dedicated ReportJobRunStatus RPC.
 func reportJobRunStatus(
@@ -356,6 +446,8 @@ func reportJobRunStatus(
 	errMsg string,
 	start time.Time,
 	endUnix int64,
+	outputs map[string]string,
+	serviceLogs map[string]string,
 ) {
 	if grpcClient == nil || grpcClient.AgentClient == nil {
 		return
@@ -374,6 +466,8 @@ func reportJobRunStatus(
 		ErrorMessage: errMsg,
 		StartTime:    start.Unix(),
 		EndTime:      endUnix,
+		Outputs:      outputs,
+		ServiceLogs:  serviceLogs,
 	}
 	if _, err := grpcClient.AgentClient.ReportJobRunStatus(ctx, req); err != nil {
 		log.Warnw("report job run status failed", "jobRunId", jobRunID, "error", err)
@@ -413,7 +507,7 @@ func executeStepRun(
 	}()
 
 	start := time.Now()
-	_ = reportStepRunStatus(grpcClient, agentConf, payload.StepRunID, steprunv1.StepRunStatus_STEP_RUN_STATUS_RUNNING, 0, "", start, 0, nil)
+	_ = reportStepRunStatus(grpcClient, agentConf, payload.StepRunID, steprunv1.StepRunStatus_STEP_RUN_STATUS_RUNNING, 0, "", start, 0, nil, nil)
 
 	timeout := parseTimeout(payload.Timeout, agentConf.Agent.JobTimeout)
 	runCtx := stepCtx
@@ -436,6 +530,7 @@ func executeStepRun(
 			start,
 			time.Now().Unix(),
 			map[string]string{"executor": "agent-shell"},
+			nil,
 		)
 		return err
 	}
@@ -465,11 +560,11 @@ func executeStepRun(
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			exitCode = int32(exitErr.ExitCode())
 		}
-		errMsg := strings.TrimSpace(string(output))
+		errMsg := strings.TrimSpace(logstream.NewMasker(payload.Secrets...).Mask(string(output)))
 		if errMsg == "" {
 			errMsg = err.Error()
 		}
-		_ = reportStepRunStatus(grpcClient, agentConf, payload.StepRunID, status, exitCode, errMsg, start, end, metrics)
+		_ = reportStepRunStatus(grpcClient, agentConf, payload.StepRunID, status, exitCode, errMsg, start, end, metrics, nil)
 		return err
 	}
 
@@ -483,6 +578,7 @@ func executeStepRun(
 		start,
 		end,
 		metrics,
+		readStepOutputs(payload),
 	)
 	return nil
 }
@@ -497,6 +593,7 @@ func reportStepRunStatus(
 	start time.Time,
 	endUnix int64,
 	metrics map[string]string,
+	outputs map[string]string,
 ) error {
 	if grpcClient == nil || grpcClient.AgentClient == nil || agentConf == nil {
 		return nil
@@ -512,6 +609,7 @@ func reportStepRunStatus(
 		StartTime:    start.Unix(),
 		EndTime:      endUnix,
 		Metrics:      metrics,
+		Outputs:      outputs,
 	}
 	_, err := grpcClient.AgentClient.ReportStepRunStatus(ctx, req)
 	return err
@@ -0,0 +1,367 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+
+	agentv1 "github.com/arcentrix/arcentra/api/agent/v1"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/arcentrix/arcentra/pkg/logstream"
+	"github.com/arcentrix/arcentra/pkg/safe"
+	"github.com/arcentrix/arcentra/pkg/sandbox"
+)
+
+const (
+	// inputBuffer is the number of input chunks queued per terminal.
+	inputBuffer = 256
+	// resizeBuffer is the number of size changes queued per terminal.
+	resizeBuffer = 4
+	// workspaceDir is where the workspace is mounted, as for container steps.
+	workspaceDir = "/workspace"
+)
+
+// shellCommand starts bash when the image has it and sh otherwise.
+var shellCommand = []string{"/bin/sh", "-c", "if command -v bash >/dev/null 2>&1; then exec bash; fi; exec sh"}
+
+// Sender sends a message to the control plane over the Connect stream.
+type Sender func(req *agentv1.ConnectRequest) error
+
+// Manager runs the debug terminals of an agent. It tracks the jobs the agent
+// is running (or keeping alive after a failure) and, when the control plane
+// asks for a terminal, starts a shell in a sandbox container of the job: the
+// job's runtime image with its workspace mounted, on the job network. Nothing
+// runs on the agent host, so terminals need the sandbox.
+type Manager struct {
+	sb sandbox.Sandbox
+
+	mu       sync.Mutex
+	send     Sender
+	jobs     map[string]*Job     // jobRunID -> job
+	steps    map[string]string   // stepRunID -> jobRunID
+	sessions map[string]*session // sessionID -> session
+}
+
+// Job is the execution context a terminal attaches to.
+type Job struct {
+	Workspace   string            // host workspace, mounted at /workspace
+	Env         map[string]string // job environment
+	StepRunIDs  []string          // step runs terminals may be opened for
+	Image       string            // runtime image, empty uses the sandbox default
+	NetworkMode string            // job network, "container:<id>" when the job has one
+	Hosts       map[string]string // service hostnames on the job network
+	Secrets     []string          // values masked in terminal output
+
+	masker *logstream.Masker
+}
+
+// session is an open debug terminal.
+type session struct {
+	id       string
+	jobRunID string
+	input    chan []byte
+	resize   chan sandbox.TerminalSize
+	cancel   context.CancelFunc
+}
+
+// NewManager creates a terminal manager that runs shells in sb. Output is
+// masked with the secrets of the job before it leaves the agent.
+func NewManager(sb sandbox.Sandbox) *Manager {
+	return &Manager{
+		sb:       sb,
+		jobs:     make(map[string]*Job),
+		steps:    make(map[string]string),
+		sessions: make(map[string]*session),
+	}
+}
+
+// Attach sets the sender used to stream terminal output. Called whenever the
+// Connect stream is (re)established.
+func (m *Manager) Attach(send Sender) {
+	m.mu.Lock()
+	m.send = send
+	m.mu.Unlock()
+}
+
+// Detach drops the sender and closes every open terminal, since their output
+// has nowhere to go once the Connect stream is gone.
+func (m *Manager) Detach() {
+	m.mu.Lock()
+	m.send = nil
+	sessions := make([]*session, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		sessions = append(sessions, s)
+	}
+	m.mu.Unlock()
+	for _, s := range sessions {
+		s.cancel()
+	}
+}
+
+// TrackJob registers a job so terminals can be opened in it for the job or
+// any of its step runs.
+func (m *Manager) TrackJob(jobRunID string, j Job) {
+	if j.Workspace != "" {
+		if abs, err := filepath.Abs(j.Workspace); err == nil {
+			j.Workspace = abs
+		}
+	}
+	j.masker = logstream.NewMasker(j.Secrets...)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[jobRunID] = &j
+	for _, id := range j.StepRunIDs {
+		m.steps[id] = jobRunID
+	}
+}
+
+// UntrackJob forgets a job and closes the terminals opened in it.
+func (m *Manager) UntrackJob(jobRunID string) {
+	m.mu.Lock()
+	if j, ok := m.jobs[jobRunID]; ok {
+		for _, id := range j.StepRunIDs {
+			delete(m.steps, id)
+		}
+		delete(m.jobs, jobRunID)
+	}
+	var sessions []*session
+	for _, s := range m.sessions {
+		if s.jobRunID == jobRunID {
+			sessions = append(sessions, s)
+		}
+	}
+	m.mu.Unlock()
+	for _, s := range sessions {
+		s.cancel()
+	}
+}
+
+// Handle serves a terminal command pushed by the control plane. It reports
+// whether resp carried a terminal command.
+func (m *Manager) Handle(resp *agentv1.ConnectResponse) bool {
+	switch p := resp.GetPayload().(type) {
+	case *agentv1.ConnectResponse_TerminalOpen:
+		if err := m.open(p.TerminalOpen); err != nil {
+			log.Warnw("open debug terminal failed", "sessionId", p.TerminalOpen.GetSessionId(), "error", err)
+			m.sendExit(p.TerminalOpen.GetSessionId(), -1, err.Error())
+		}
+	case *agentv1.ConnectResponse_TerminalInput:
+		if s := m.session(p.TerminalInput.GetSessionId()); s != nil {
+			select {
+			case s.input <- p.TerminalInput.GetData():
+			default:
+				log.Debugw("debug terminal input dropped", "sessionId", s.id)
+			}
+		}
+	case *agentv1.ConnectResponse_TerminalResize:
+		if s := m.session(p.TerminalResize.GetSessionId()); s != nil {
+			s.setSize(p.TerminalResize.GetCols(), p.TerminalResize.GetRows())
+		}
+	case *agentv1.ConnectResponse_TerminalClose:
+		if s := m.session(p.TerminalClose.GetSessionId()); s != nil {
+			s.cancel()
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func (m *Manager) open(req *agentv1.TerminalOpen) error {
+	if req.GetSessionId() == "" {
+		return errors.New("session id is required")
+	}
+	if m.sb == nil {
+		return errors.New("debug terminals need the agent sandbox")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	jobRunID := req.GetJobRunId()
+	if jobRunID == "" {
+		jobRunID = m.steps[req.GetStepRunId()]
+	}
+	j, ok := m.jobs[jobRunID]
+	if !ok {
+		return fmt.Errorf("job run %q is not running on this agent", jobRunID)
+	}
+	if _, exists := m.sessions[req.GetSessionId()]; exists {
+		return fmt.Errorf("session %s already open", req.GetSessionId())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &session{
+		id:       req.GetSessionId(),
+		jobRunID: jobRunID,
+		input:    make(chan []byte, inputBuffer),
+		resize:   make(chan sandbox.TerminalSize, resizeBuffer),
+		cancel:   cancel,
+	}
+	s.setSize(req.GetCols(), req.GetRows())
+	m.sessions[s.id] = s
+	log.Infow("debug terminal opened", "sessionId", s.id, "jobRunId", jobRunID, "stepRunId", req.GetStepRunId())
+
+	job := *j
+	safe.Go(func() { m.run(ctx, s, &job) })
+	return nil
+}
+
+// run runs the shell of a session until it exits or the session is closed,
+// then reports the exit code and releases the session.
+func (m *Manager) run(ctx context.Context, s *session, j *Job) {
+	defer s.cancel()
+	exitCode, errMsg := m.exec(ctx, s, j)
+
+	m.mu.Lock()
+	delete(m.sessions, s.id)
+	m.mu.Unlock()
+	m.sendExit(s.id, exitCode, errMsg)
+	log.Infow("debug terminal closed", "sessionId", s.id, "exitCode", exitCode)
+}
+
+// exec creates the terminal container and attaches the session to its shell.
+func (m *Manager) exec(ctx context.Context, s *session, j *Job) (int32, string) {
+	env := make(map[string]string, len(j.Env)+1)
+	for k, v := range j.Env {
+		env[k] = v
+	}
+	env["TERM"] = "xterm-256color"
+	opts := &sandbox.CreateOptions{
+		Image:       j.Image,
+		Command:     shellCommand,
+		Env:         env,
+		NetworkMode: j.NetworkMode,
+		Hosts:       j.Hosts,
+		Labels: map[string]string{
+			"arcentra.job-run-id": s.jobRunID,
+			"arcentra.terminal":   s.id,
+		},
+	}
+	if j.Workspace != "" {
+		opts.WorkingDir = workspaceDir
+		opts.Mounts = []sandbox.Mount{{Source: j.Workspace, Target: workspaceDir, Type: "bind"}}
+	}
+	containerID, err := m.sb.Create(ctx, opts)
+	if err != nil {
+		return -1, fmt.Sprintf("create terminal container: %v", err)
+	}
+	defer func() {
+		if err := m.sb.Remove(context.Background(), containerID); err != nil {
+			log.Warnw("remove debug terminal container failed", "sessionId", s.id, "error", err)
+		}
+	}()
+
+	out := &outputWriter{m: m, sessionID: s.id, sm: logstream.NewStreamMasker(j.masker)}
+	res, err := m.sb.Execute(ctx, containerID, shellCommand, &sandbox.ExecuteOptions{
+		TTY:    true,
+		Stdin:  &inputReader{ctx: ctx, input: s.input},
+		Stdout: out,
+		Resize: s.resize,
+	})
+	out.flush()
+	if err != nil {
+		if ctx.Err() != nil {
+			return -1, "terminal closed"
+		}
+		return -1, err.Error()
+	}
+	return res.ExitCode, ""
+}
+
+func (m *Manager) session(id string) *session {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sessions[id]
+}
+
+func (m *Manager) sendOutput(sessionID string, data []byte) {
+	m.sendMsg(&agentv1.ConnectRequest{Payload: &agentv1.ConnectRequest_TerminalOutput{
+		TerminalOutput: &agentv1.TerminalOutput{SessionId: sessionID, Data: data},
+	}})
+}
+
+func (m *Manager) sendExit(sessionID string, exitCode int32, errMsg string) {
+	m.sendMsg(&agentv1.ConnectRequest{Payload: &agentv1.ConnectRequest_TerminalExit{
+		TerminalExit: &agentv1.TerminalExit{SessionId: sessionID, ExitCode: exitCode, Error: errMsg},
+	}})
+}
+
+func (m *Manager) sendMsg(req *agentv1.ConnectRequest) {
+	m.mu.Lock()
+	send := m.send
+	m.mu.Unlock()
+	if send == nil {
+		return
+	}
+	if err := send(req); err != nil {
+		log.Debugw("send debug terminal message failed", "error", err)
+	}
+}
+
+// setSize queues a terminal size change, dropping it when the queue is full.
+func (s *session) setSize(cols, rows uint32) {
+	if cols == 0 || rows == 0 {
+		return
+	}
+	select {
+	case s.resize <- sandbox.TerminalSize{Cols: uint16(cols), Rows: uint16(rows)}:
+	default:
+	}
+}
+
+// inputReader reads the queued terminal input until the session is closed.
+type inputReader struct {
+	ctx     context.Context
+	input   <-chan []byte
+	pending []byte
+}
+
+func (r *inputReader) Read(p []byte) (int, error) {
+	if len(r.pending) == 0 {
+		select {
+		case data := <-r.input:
+			r.pending = data
+		case <-r.ctx.Done():
+			return 0, io.EOF
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// outputWriter masks terminal output and sends it to the control plane.
+type outputWriter struct {
+	m         *Manager
+	sessionID string
+	sm        *logstream.StreamMasker
+}
+
+func (w *outputWriter) Write(p []byte) (int, error) {
+	if out := w.sm.Write(p); len(out) > 0 {
+		w.m.sendOutput(w.sessionID, out)
+	}
+	return len(p), nil
+}
+
+func (w *outputWriter) flush() {
+	if out := w.sm.Flush(); len(out) > 0 {
+		w.m.sendOutput(w.sessionID, out)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	agentv1 "github.com/arcentrix/arcentra/api/agent/v1"
+	"github.com/arcentrix/arcentra/pkg/sandbox"
+)
+
+type recorder struct {
+	mu     sync.Mutex
+	output bytes.Buffer
+	exit   chan *agentv1.TerminalExit
+}
+
+func (r *recorder) send(req *agentv1.ConnectRequest) error {
+	switch p := req.GetPayload().(type) {
+	case *agentv1.ConnectRequest_TerminalOutput:
+		r.mu.Lock()
+		r.output.Write(p.TerminalOutput.GetData())
+		r.mu.Unlock()
+	case *agentv1.ConnectRequest_TerminalExit:
+		r.exit <- p.TerminalExit
+	}
+	return nil
+}
+
+// fakeSandbox runs a tiny shell that understands "pwd", "echo $VAR" and
+// "exit N".
+type fakeSandbox struct {
+	mu      sync.Mutex
+	created *sandbox.CreateOptions
+	size    sandbox.TerminalSize
+	removed []string
+}
+
+func (f *fakeSandbox) Create(_ context.Context, opts *sandbox.CreateOptions) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.created = opts
+	return "term-1", nil
+}
+
+func (f *fakeSandbox) Start(context.Context, string) error { return nil }
+
+func (f *fakeSandbox) Execute(_ context.Context, _ string, _ []string, opts *sandbox.ExecuteOptions) (*sandbox.ExecuteResult, error) {
+	f.mu.Lock()
+	f.size = <-opts.Resize
+	env := f.created.Env
+	dir := f.created.WorkingDir
+	f.mu.Unlock()
+	scanner := bufio.NewScanner(opts.Stdin)
+	for scanner.Scan() {
+		for _, cmd := range strings.Split(scanner.Text(), ";") {
+			switch fields := strings.Fields(cmd); {
+			case len(fields) == 0:
+			case fields[0] == "pwd":
+				fmt.Fprintln(opts.Stdout, dir)
+			case fields[0] == "echo" && len(fields) > 1:
+				fmt.Fprintln(opts.Stdout, env[strings.TrimPrefix(fields[1], "$")])
+			case fields[0] == "exit" && len(fields) > 1:
+				var code int32
+				fmt.Sscan(fields[1], &code)
+				return &sandbox.ExecuteResult{ExitCode: code}, nil
+			}
+		}
+	}
+	return nil, io.ErrUnexpectedEOF
+}
+
+func (f *fakeSandbox) Stop(context.Context, string, time.Duration) error { return nil }
+
+func (f *fakeSandbox) Remove(_ context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.removed = append(f.removed, id)
+	return nil
+}
+
+func (f *fakeSandbox) GetLogs(context.Context, string, *sandbox.LogOptions) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func (f *fakeSandbox) Cleanup(context.Context) error { return nil }
+
+func (f *fakeSandbox) Close() error { return nil }
+
+func TestManagerRunsShellInJobContainer(t *testing.T) {
+	sb := &fakeSandbox{}
+	m := NewManager(sb)
+	rec := &recorder{exit: make(chan *agentv1.TerminalExit, 1)}
+	m.Attach(rec.send)
+
+	dir := t.TempDir()
+	m.TrackJob("job-1", Job{
+		Workspace:   dir,
+		Env:         map[string]string{"TOKEN": "s3cr3t-value"},
+		Secrets:     []string{"s3cr3t-value"},
+		StepRunIDs:  []string{"step-1"},
+		Image:       "golang:1.25",
+		NetworkMode: "container:net-1",
+		Hosts:       map[string]string{"db": "127.0.0.1"},
+	})
+	m.Handle(&agentv1.ConnectResponse{Payload: &agentv1.ConnectResponse_TerminalOpen{
+		TerminalOpen: &agentv1.TerminalOpen{SessionId: "s1", StepRunId: "step-1", Cols: 80, Rows: 24},
+	}})
+	m.Handle(&agentv1.ConnectResponse{Payload: &agentv1.ConnectResponse_TerminalInput{
+		TerminalInput: &agentv1.TerminalInput{SessionId: "s1", Data: []byte("pwd; echo $TOKEN; exit 3\n")},
+	}})
+
+	select {
+	case exit := <-rec.exit:
+		if exit.GetExitCode() != 3 {
+			t.Fatalf("exit code = %d (%s), want 3", exit.GetExitCode(), exit.GetError())
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("terminal did not exit")
+	}
+	rec.mu.Lock()
+	out := rec.output.String()
+	rec.mu.Unlock()
+	if !strings.Contains(out, "/workspace") {
+		t.Errorf("output %q does not show the workspace", out)
+	}
+	if strings.Contains(out, "s3cr3t-value") {
+		t.Errorf("output %q leaks the secret", out)
+	}
+
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	opts := sb.created
+	if opts.Image != "golang:1.25" || opts.NetworkMode != "container:net-1" || opts.Hosts["db"] != "127.0.0.1" {
+		t.Errorf("create options = %+v, want the job image and network", opts)
+	}
+	if len(opts.Mounts) != 1 || opts.Mounts[0].Source != dir || opts.Mounts[0].Target != "/workspace" {
+		t.Errorf("mounts = %+v, want the workspace at /workspace", opts.Mounts)
+	}
+	if sb.size != (sandbox.TerminalSize{Cols: 80, Rows: 24}) {
+		t.Errorf("initial size = %+v, want 80x24", sb.size)
+	}
+	if len(sb.removed) != 1 || sb.removed[0] != "term-1" {
+		t.Errorf("removed = %v, want the terminal container", sb.removed)
+	}
+}
+
+func TestManagerRejectsUnknownJob(t *testing.T) {
+	m := NewManager(&fakeSandbox{})
+	rec := &recorder{exit: make(chan *agentv1.TerminalExit, 1)}
+	m.Attach(rec.send)
+
+	m.Handle(&agentv1.ConnectResponse{Payload: &agentv1.ConnectResponse_TerminalOpen{
+		TerminalOpen: &agentv1.TerminalOpen{SessionId: "s1", StepRunId: "gone"},
+	}})
+	exit := <-rec.exit
+	if exit.GetExitCode() != -1 || exit.GetError() == "" {
+		t.Fatalf("exit = %+v, want a start failure", exit)
+	}
+}
+
+func TestManagerNeedsSandbox(t *testing.T) {
+	m := NewManager(nil)
+	rec := &recorder{exit: make(chan *agentv1.TerminalExit, 1)}
+	m.Attach(rec.send)
+	m.TrackJob("job-1", Job{Workspace: t.TempDir()})
+
+	m.Handle(&agentv1.ConnectResponse{Payload: &agentv1.ConnectResponse_TerminalOpen{
+		TerminalOpen: &agentv1.TerminalOpen{SessionId: "s1", JobRunId: "job-1"},
+	}})
+	exit := <-rec.exit
+	if exit.GetExitCode() != -1 || !strings.Contains(exit.GetError(), "sandbox") {
+		t.Fatalf("exit = %+v, want a sandbox error", exit)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// agent is the agent record returned by the agent API.
+type agent struct {
+	AgentID       string          `json:"agentId"`
+	AgentName     string          `json:"agentName"`
+	Address       string          `json:"address"`
+	OS            string          `json:"os"`
+	Arch          string          `json:"arch"`
+	Version       string          `json:"version"`
+	Status        int             `json:"status"`
+	Labels        json.RawMessage `json:"labels"`
+	LastHeartbeat *time.Time      `json:"lastHeartbeat,omitempty"`
+	IsEnabled     int             `json:"isEnabled"`
+}
+
+// agentStatusNames follows the status column of model.Agent.
+var agentStatusNames = map[int]string{
+	0: "unknown",
+	1: "online",
+	2: "offline",
+	3: "busy",
+	4: "idle",
+}
+
+func newAgentCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "agent",
+		Aliases: []string{"agents"},
+		Short:   "Inspect agents",
+	}
+	cmd.AddCommand(newAgentListCommand(a))
+	return cmd
+}
+
+func newAgentListCommand(a *app) *cobra.Command {
+	var (
+		page     int
+		pageSize int
+	)
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List agents",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			query := url.Values{}
+			query.Set("pageNum", strconv.Itoa(page))
+			query.Set("pageSize", strconv.Itoa(pageSize))
+			var result struct {
+				Agents   []agent `json:"agents"`
+				Count    int64   `json:"count"`
+				PageNum  int     `json:"pageNum"`
+				PageSize int     `json:"pageSize"`
+			}
+			if err := c.Get(cmd.Context(), "/agent", query, &result); err != nil {
+				return err
+			}
+			return a.printer(cmd).Print(result, func() [][]string {
+				rows := [][]string{{"ID", "NAME", "STATUS", "ENABLED", "OS/ARCH", "VERSION", "LABELS", "LAST HEARTBEAT"}}
+				for _, ag := range result.Agents {
+					heartbeat := "-"
+					if ag.LastHeartbeat != nil {
+						heartbeat = ag.LastHeartbeat.Local().Format(time.DateTime)
+					}
+					rows = append(rows, []string{
+						ag.AgentID,
+						orDash(ag.AgentName),
+						agentStatusName(ag.Status),
+						strconv.FormatBool(ag.IsEnabled == 1),
+						ag.OS + "/" + ag.Arch,
+						orDash(ag.Version),
+						formatLabels(ag.Labels),
+						heartbeat,
+					})
+				}
+				return rows
+			})
+		},
+	}
+	cmd.Flags().IntVar(&page, "page", 1, "page number")
+	cmd.Flags().IntVar(&pageSize, "page-size", 50, "page size")
+	return cmd
+}
+
+func agentStatusName(status int) string {
+	if name, ok := agentStatusNames[status]; ok {
+		return name
+	}
+	return strconv.Itoa(status)
+}
+
+// formatLabels renders a JSON label object as sorted k=v pairs.
+func formatLabels(raw json.RawMessage) string {
+	var labels map[string]any
+	if len(raw) == 0 || json.Unmarshal(raw, &labels) != nil || len(labels) == 0 {
+		return "-"
+	}
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+strings.TrimSpace(toString(v)))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func toString(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
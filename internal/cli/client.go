@@ -0,0 +1,149 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	apihttp "github.com/arcentrix/arcentra/pkg/http"
+)
+
+const apiPrefix = "/api/v1"
+
+// Client calls the control plane HTTP API and unwraps its response envelope.
+type Client struct {
+	server string
+	token  string
+	http   *http.Client
+}
+
+// APIError is an error response of the control plane.
+type APIError struct {
+	Code    int
+	Message string
+	Path    string
+}
+
+func (e *APIError) Error() string {
+	switch e.Code {
+	case apihttp.TokenBeEmpty.Code, apihttp.TokenExpired.Code, apihttp.InvalidToken.Code:
+		return fmt.Sprintf("%s (code %d), run `arcentra-cli login` first", e.Message, e.Code)
+	}
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// envelope is the union of the success and error response bodies.
+type envelope struct {
+	Code   int             `json:"code"`
+	Msg    string          `json:"msg"`
+	ErrMsg any             `json:"errMsg"`
+	Path   string          `json:"path"`
+	Detail json.RawMessage `json:"detail"`
+}
+
+// NewClient creates a client for server authenticating with token.
+func NewClient(server, token string) *Client {
+	return &Client{
+		server: strings.TrimRight(server, "/"),
+		token:  token,
+		http:   &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Get sends a GET request; query may be nil.
+func (c *Client) Get(ctx context.Context, path string, query url.Values, out any) error {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return c.Do(ctx, http.MethodGet, path, nil, out)
+}
+
+// Post sends a POST request with a JSON body.
+func (c *Client) Post(ctx context.Context, path string, body, out any) error {
+	return c.Do(ctx, http.MethodPost, path, body, out)
+}
+
+// Do sends a request to apiPrefix+path. body is JSON encoded unless it is
+// already a json.RawMessage. On success the response detail is decoded into
+// out (when not nil).
+func (c *Client) Do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		raw, ok := body.(json.RawMessage)
+		if !ok {
+			var err error
+			if raw, err = json.Marshal(body); err != nil {
+				return fmt.Errorf("encode request: %w", err)
+			}
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.server+apiPrefix+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("request %s %s: %w", method, path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return fmt.Errorf("unexpected response (HTTP %d): %s", resp.StatusCode, truncate(string(data), 200))
+	}
+	if env.Code != apihttp.Success.Code {
+		msg := env.Msg
+		if env.ErrMsg != nil {
+			msg = fmt.Sprint(env.ErrMsg)
+		}
+		return &APIError{Code: env.Code, Message: msg, Path: env.Path}
+	}
+	if out == nil || len(env.Detail) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(env.Detail, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
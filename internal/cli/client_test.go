@@ -0,0 +1,95 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestClientDecodesDetail(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/pipelines/p1" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("unexpected authorization %q", got)
+		}
+		_, _ = w.Write([]byte(`{"code":200,"msg":"Request Success","detail":{"name":"build"},"timestamp":1}`))
+	}))
+	defer srv.Close()
+
+	var out struct {
+		Name string `json:"name"`
+	}
+	if err := NewClient(srv.URL, "tok").Get(context.Background(), "/pipelines/p1", nil, &out); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if out.Name != "build" {
+		t.Errorf("name = %q, want build", out.Name)
+	}
+}
+
+func TestClientReturnsAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"code":4407,"errMsg":"Token is expired","path":"/api/v1/agent","timestamp":1}`))
+	}))
+	defer srv.Close()
+
+	err := NewClient(srv.URL, "tok").Get(context.Background(), "/agent", nil, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if apiErr.Code != 4407 || apiErr.Message != "Token is expired" {
+		t.Errorf("unexpected error %+v", apiErr)
+	}
+}
+
+func TestConfigSaveAndLoad(t *testing.T) {
+	t.Setenv(serverEnv, "")
+	t.Setenv(tokenEnv, "")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig missing file: %v", err)
+	}
+	if cfg.Server != defaultServer {
+		t.Errorf("server = %q, want default", cfg.Server)
+	}
+
+	cfg.Server = "https://ci.example.com"
+	cfg.Token = "secret"
+	if err := cfg.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if loaded.Server != cfg.Server || loaded.Token != cfg.Token {
+		t.Errorf("loaded %+v, want %+v", loaded, cfg)
+	}
+
+	t.Setenv(tokenEnv, "from-env")
+	if loaded, _ = LoadConfig(path); loaded.Token != "from-env" {
+		t.Errorf("token = %q, want env override", loaded.Token)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// configEnv overrides the config file location.
+	configEnv = "ARCENTRA_CONFIG"
+	// serverEnv and tokenEnv override the stored server and token.
+	serverEnv = "ARCENTRA_SERVER"
+	tokenEnv  = "ARCENTRA_TOKEN"
+
+	defaultServer = "http://127.0.0.1:8080"
+)
+
+// Config is the persisted CLI configuration.
+type Config struct {
+	// Server is the control plane base URL, e.g. https://ci.example.com.
+	Server string `yaml:"server"`
+	// Token is the access token returned by login.
+	Token string `yaml:"token"`
+	// RefreshToken is stored for future token refresh.
+	RefreshToken string `yaml:"refreshToken,omitempty"`
+	// Username is the user that logged in, for display only.
+	Username string `yaml:"username,omitempty"`
+	// Output is the default output format (table or json).
+	Output string `yaml:"output,omitempty"`
+}
+
+// DefaultConfigPath returns $ARCENTRA_CONFIG or ~/.arcentra/config.yaml.
+func DefaultConfigPath() string {
+	if p := strings.TrimSpace(os.Getenv(configEnv)); p != "" {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".arcentra", "config.yaml")
+	}
+	return filepath.Join(home, ".arcentra", "config.yaml")
+}
+
+// LoadConfig reads the config file. A missing file yields an empty config.
+// ARCENTRA_SERVER and ARCENTRA_TOKEN take precedence over the file.
+func LoadConfig(path string) (*Config, error) {
+	cfg := &Config{}
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("parse config %s: %w", path, err)
+		}
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("read config %s: %w", path, err)
+	}
+
+	if v := strings.TrimSpace(os.Getenv(serverEnv)); v != "" {
+		cfg.Server = v
+	}
+	if v := strings.TrimSpace(os.Getenv(tokenEnv)); v != "" {
+		cfg.Token = v
+	}
+	if cfg.Server == "" {
+		cfg.Server = defaultServer
+	}
+	return cfg, nil
+}
+
+// Save writes the config file with owner-only permissions since it holds
+// the access token.
+func (c *Config) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("marshal config: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write config %s: %w", path, err)
+	}
+	return nil
+}
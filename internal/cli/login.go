@@ -0,0 +1,141 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const passwordEnv = "ARCENTRA_PASSWORD"
+
+func newLoginCommand(a *app) *cobra.Command {
+	var (
+		username string
+		password string
+		token    string
+	)
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log in and store the access token in the config file",
+		Long: "Log in with a username and password, or store an existing access token with --token.\n" +
+			"The password is read from --password, $" + passwordEnv + " or standard input.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := a.loadConfig()
+			if err != nil {
+				return err
+			}
+
+			if token = strings.TrimSpace(token); token != "" {
+				cfg.Token = token
+				cfg.RefreshToken = ""
+				cfg.Username = ""
+			} else {
+				if username == "" {
+					if username, err = prompt(cmd, "Username: "); err != nil {
+						return err
+					}
+				}
+				if password == "" {
+					password = os.Getenv(passwordEnv)
+				}
+				if password == "" {
+					if password, err = prompt(cmd, "Password: "); err != nil {
+						return err
+					}
+				}
+				var resp struct {
+					Token map[string]string `json:"token"`
+				}
+				body := map[string]string{"username": username, "password": password}
+				if err := NewClient(cfg.Server, "").Post(cmd.Context(), "/users/login", body, &resp); err != nil {
+					return fmt.Errorf("login failed: %w", err)
+				}
+				if resp.Token["accessToken"] == "" {
+					return fmt.Errorf("login failed: no access token in response")
+				}
+				cfg.Token = resp.Token["accessToken"]
+				cfg.RefreshToken = resp.Token["refreshToken"]
+				cfg.Username = username
+			}
+
+			if err := cfg.Save(a.configPath); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Logged in to %s, credentials saved to %s\n", cfg.Server, a.configPath)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&username, "username", "u", "", "username")
+	cmd.Flags().StringVarP(&password, "password", "p", "", "password")
+	cmd.Flags().StringVar(&token, "token", "", "store this access token instead of logging in")
+	return cmd
+}
+
+func newLogoutCommand(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "logout",
+		Short: "Remove the stored access token",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := a.loadConfig()
+			if err != nil {
+				return err
+			}
+			if cfg.Token != "" {
+				// Best effort: the token is dropped locally either way.
+				_ = NewClient(cfg.Server, cfg.Token).Post(cmd.Context(), "/users/logout", nil, nil)
+			}
+			cfg.Token = ""
+			cfg.RefreshToken = ""
+			if err := cfg.Save(a.configPath); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Logged out")
+			return nil
+		},
+	}
+}
+
+// prompt reads one line from the command input. It reads byte by byte so
+// that consecutive prompts do not lose buffered input.
+func prompt(cmd *cobra.Command, label string) (string, error) {
+	_, _ = fmt.Fprint(cmd.ErrOrStderr(), label)
+	var (
+		line strings.Builder
+		buf  [1]byte
+	)
+	in := cmd.InOrStdin()
+	for {
+		n, err := in.Read(buf[:])
+		if n > 0 {
+			if buf[0] == '\n' {
+				break
+			}
+			line.WriteByte(buf[0])
+		}
+		if err != nil {
+			if line.Len() == 0 {
+				return "", fmt.Errorf("read %s: %w", strings.TrimSuffix(strings.ToLower(label), ": "), err)
+			}
+			break
+		}
+	}
+	return strings.TrimSpace(line.String()), nil
+}
@@ -0,0 +1,375 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	pipelinev1 "github.com/arcentrix/arcentra/api/pipeline/v1"
+	steprunv1 "github.com/arcentrix/arcentra/api/steprun/v1"
+	"github.com/fasthttp/websocket"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// logDrainWait is how long trailing lines are awaited after a step ends.
+	logDrainWait = time.Second
+	// logPollInterval is how often step and run status is checked while following.
+	logPollInterval = 2 * time.Second
+)
+
+// logLine mirrors the LogEntry sent on the websocket log channel.
+type logLine struct {
+	StepRunID  string `json:"step_run_id"`
+	Timestamp  int64  `json:"timestamp"`
+	LineNumber int32  `json:"line_number"`
+	Level      string `json:"level"`
+	Content    string `json:"content"`
+	Stream     string `json:"stream"`
+}
+
+// wsMessage is a message of the control plane websocket.
+type wsMessage struct {
+	Channel string `json:"channel"`
+	Type    string `json:"type"`
+	Params  struct {
+		StepRunID string `json:"stepRunId"`
+	} `json:"params"`
+	Data  json.RawMessage `json:"data"`
+	Error string          `json:"error"`
+}
+
+func newLogsCommand(a *app) *cobra.Command {
+	var (
+		job        string
+		step       string
+		follow     bool
+		timestamps bool
+	)
+	cmd := &cobra.Command{
+		Use:   "logs <run-id>",
+		Short: "Print the step logs of a run",
+		Long: "Print the step logs of a run in step order. With -f, follows running steps and new\n" +
+			"steps until the run finishes. Logs are read from the control plane websocket.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			cfg, _ := a.loadConfig()
+			conn, err := dialLogs(cmd.Context(), cfg.Server, cfg.Token)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = conn.Close() }()
+
+			f := &logFollower{
+				client:     c,
+				stream:     newLogStream(conn),
+				out:        cmd.OutOrStdout(),
+				runID:      args[0],
+				job:        job,
+				step:       step,
+				follow:     follow,
+				timestamps: timestamps,
+				json:       a.printer(cmd).JSON(),
+				done:       make(map[string]bool),
+			}
+			return f.run(cmd.Context())
+		},
+	}
+	cmd.Flags().StringVar(&job, "job", "", "only show steps of this job")
+	cmd.Flags().StringVar(&step, "step", "", "only show this step (step name or step run ID)")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "follow the logs until the run finishes")
+	cmd.Flags().BoolVar(&timestamps, "timestamps", false, "prefix lines with their timestamp")
+	return cmd
+}
+
+// dialLogs opens the websocket of server, authenticated with token.
+func dialLogs(ctx context.Context, server, token string) (*websocket.Conn, error) {
+	wsURL := strings.TrimRight(server, "/") + apiPrefix + "/ws"
+	switch {
+	case strings.HasPrefix(wsURL, "https://"):
+		wsURL = "wss://" + strings.TrimPrefix(wsURL, "https://")
+	case strings.HasPrefix(wsURL, "http://"):
+		wsURL = "ws://" + strings.TrimPrefix(wsURL, "http://")
+	}
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+token)
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, wsURL, header)
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("connect log stream %s: %w", wsURL, err)
+	}
+	return conn, nil
+}
+
+// logStream reads websocket messages in the background.
+type logStream struct {
+	conn *websocket.Conn
+	msgs chan wsMessage
+	err  chan error
+}
+
+func newLogStream(conn *websocket.Conn) *logStream {
+	s := &logStream{conn: conn, msgs: make(chan wsMessage, 256), err: make(chan error, 1)}
+	go func() {
+		for {
+			var msg wsMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				s.err <- err
+				close(s.msgs)
+				return
+			}
+			s.msgs <- msg
+		}
+	}()
+	return s
+}
+
+func (s *logStream) subscribe(st stepRun) error {
+	return s.conn.WriteJSON(map[string]any{
+		"channel": "channel_log",
+		"action":  "subscribe",
+		"params": map[string]string{
+			"pipelineId": st.PipelineID,
+			"jobId":      st.JobID,
+			"stepRunId":  st.StepRunID,
+		},
+	})
+}
+
+// logFollower prints the logs of the steps of one run.
+type logFollower struct {
+	client     *Client
+	stream     *logStream
+	out        io.Writer
+	runID      string
+	job        string
+	step       string
+	follow     bool
+	timestamps bool
+	json       bool
+
+	// done records the step runs whose logs were printed completely.
+	done map[string]bool
+}
+
+func (f *logFollower) run(ctx context.Context) error {
+	for {
+		steps, err := listStepRuns(ctx, f.client, f.runID)
+		if err != nil {
+			return err
+		}
+		printed := false
+		for _, st := range steps {
+			if f.done[st.StepRunID] || !f.matches(st) {
+				continue
+			}
+			if err := f.printStep(ctx, st); err != nil {
+				return err
+			}
+			printed = true
+		}
+		if !f.follow {
+			return nil
+		}
+
+		run, err := getRun(ctx, f.client, f.runID)
+		if err != nil {
+			return err
+		}
+		if isRunFinished(run.GetStatus()) && !printed {
+			return nil
+		}
+		if !printed {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(logPollInterval):
+			}
+		}
+	}
+}
+
+func (f *logFollower) matches(st stepRun) bool {
+	if f.job != "" && st.JobName != f.job {
+		return false
+	}
+	if f.step != "" && st.StepName != f.step && st.StepRunID != f.step {
+		return false
+	}
+	return true
+}
+
+// printStep prints the history of a step and, when following, its live
+// lines until the step finishes.
+func (f *logFollower) printStep(ctx context.Context, st stepRun) error {
+	finished := isStepFinished(st.Status)
+	if !finished && !f.follow {
+		// Without -f a running step still prints what it has so far.
+		finished = true
+	}
+	if !f.json {
+		_, _ = fmt.Fprintf(f.out, "==> %s/%s (%s)\n", st.JobName, st.StepName, stepStatusName(st.Status))
+	}
+	if err := f.stream.subscribe(st); err != nil {
+		return fmt.Errorf("subscribe logs of %s: %w", st.StepRunID, err)
+	}
+
+	var (
+		lastLine    int32 = -1
+		historyDone bool
+		pending     []logLine
+		drainUntil  time.Time
+	)
+	poll := time.NewTicker(logPollInterval)
+	defer poll.Stop()
+	for {
+		var timeout <-chan time.Time
+		if historyDone && finished {
+			if drainUntil.IsZero() {
+				drainUntil = time.Now().Add(logDrainWait)
+			}
+			timeout = time.After(time.Until(drainUntil))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-f.stream.err:
+			return fmt.Errorf("log stream closed: %w", err)
+		case <-timeout:
+			f.done[st.StepRunID] = true
+			return nil
+		case <-poll.C:
+			if finished {
+				continue
+			}
+			if latest, err := f.stepStatus(ctx, st); err == nil && isStepFinished(latest) {
+				finished = true
+			}
+		case msg, ok := <-f.stream.msgs:
+			if !ok {
+				return fmt.Errorf("log stream closed")
+			}
+			if msg.Channel != "channel_log" || (msg.Params.StepRunID != "" && msg.Params.StepRunID != st.StepRunID) {
+				continue
+			}
+			switch msg.Type {
+			case "error":
+				return fmt.Errorf("log stream: %s", msg.Error)
+			case "log_chunk":
+				var lines []logLine
+				if err := json.Unmarshal(msg.Data, &lines); err != nil {
+					return fmt.Errorf("decode log chunk: %w", err)
+				}
+				for _, l := range lines {
+					if l.StepRunID == st.StepRunID {
+						lastLine = f.printLine(st, l, lastLine)
+					}
+				}
+			case "history_done":
+				historyDone = true
+				for _, l := range pending {
+					lastLine = f.printLine(st, l, lastLine)
+				}
+				pending = nil
+			case "log":
+				var l logLine
+				if err := json.Unmarshal(msg.Data, &l); err != nil || l.StepRunID != st.StepRunID {
+					continue
+				}
+				// Live lines may overtake the history; hold them until it is done.
+				if !historyDone {
+					pending = append(pending, l)
+					continue
+				}
+				lastLine = f.printLine(st, l, lastLine)
+			}
+		}
+	}
+}
+
+// printLine prints l unless it was already printed and returns the new last
+// printed line number.
+func (f *logFollower) printLine(st stepRun, l logLine, lastLine int32) int32 {
+	if l.LineNumber <= lastLine {
+		return lastLine
+	}
+	if f.json {
+		_ = json.NewEncoder(f.out).Encode(map[string]any{
+			"job":       st.JobName,
+			"step":      st.StepName,
+			"stepRunId": st.StepRunID,
+			"line":      l.LineNumber,
+			"timestamp": l.Timestamp,
+			"stream":    l.Stream,
+			"content":   l.Content,
+		})
+		return l.LineNumber
+	}
+	content := strings.TrimRight(l.Content, "\n")
+	if f.timestamps {
+		_, _ = fmt.Fprintf(f.out, "%s %s\n", formatUnix(l.Timestamp), content)
+	} else {
+		_, _ = fmt.Fprintln(f.out, content)
+	}
+	return l.LineNumber
+}
+
+func (f *logFollower) stepStatus(ctx context.Context, st stepRun) (steprunv1.StepRunStatus, error) {
+	steps, err := listStepRuns(ctx, f.client, f.runID)
+	if err != nil {
+		return st.Status, err
+	}
+	for _, s := range steps {
+		if s.StepRunID == st.StepRunID {
+			return s.Status, nil
+		}
+	}
+	return st.Status, nil
+}
+
+func isStepFinished(s steprunv1.StepRunStatus) bool {
+	switch s {
+	case steprunv1.StepRunStatus_STEP_RUN_STATUS_SUCCESS,
+		steprunv1.StepRunStatus_STEP_RUN_STATUS_FAILED,
+		steprunv1.StepRunStatus_STEP_RUN_STATUS_CANCELLED,
+		steprunv1.StepRunStatus_STEP_RUN_STATUS_TIMEOUT,
+		steprunv1.StepRunStatus_STEP_RUN_STATUS_SKIPPED:
+		return true
+	}
+	return false
+}
+
+func isRunFinished(s pipelinev1.PipelineStatus) bool {
+	switch s {
+	case pipelinev1.PipelineStatus_PIPELINE_STATUS_SUCCESS,
+		pipelinev1.PipelineStatus_PIPELINE_STATUS_FAILED,
+		pipelinev1.PipelineStatus_PIPELINE_STATUS_CANCELLED:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Output formats.
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+)
+
+// Printer renders command results either as an aligned table or as JSON.
+type Printer struct {
+	out    io.Writer
+	format string
+}
+
+// NewPrinter creates a printer; unknown formats fall back to table.
+func NewPrinter(out io.Writer, format string) *Printer {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != OutputJSON {
+		format = OutputTable
+	}
+	return &Printer{out: out, format: format}
+}
+
+// JSON reports whether the printer emits JSON.
+func (p *Printer) JSON() bool {
+	return p.format == OutputJSON
+}
+
+// Print writes v as indented JSON in JSON mode, or the table built by rows
+// otherwise. rows returns the header followed by the data rows.
+func (p *Printer) Print(v any, rows func() [][]string) error {
+	if p.JSON() {
+		return p.PrintJSON(v)
+	}
+	return p.PrintTable(rows())
+}
+
+// PrintJSON writes v as indented JSON.
+func (p *Printer) PrintJSON(v any) error {
+	enc := json.NewEncoder(p.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// PrintTable writes rows as tab-aligned columns; the first row is the header.
+func (p *Printer) PrintTable(rows [][]string) error {
+	tw := tabwriter.NewWriter(p.out, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		if _, err := fmt.Fprintln(tw, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// PrintFields writes key/value pairs of a single object, one per line.
+func (p *Printer) PrintFields(v any, fields [][2]string) error {
+	if p.JSON() {
+		return p.PrintJSON(v)
+	}
+	rows := make([][]string, 0, len(fields))
+	for _, f := range fields {
+		rows = append(rows, []string{f[0] + ":", f[1]})
+	}
+	return p.PrintTable(rows)
+}
+
+// formatUnix formats a unix timestamp in seconds or milliseconds.
+func formatUnix(ts int64) string {
+	if ts <= 0 {
+		return "-"
+	}
+	if ts > 1e12 {
+		return time.UnixMilli(ts).Local().Format(time.DateTime)
+	}
+	return time.Unix(ts, 0).Local().Format(time.DateTime)
+}
+
+// formatMillis formats a duration in milliseconds.
+func formatMillis(ms int64) string {
+	if ms <= 0 {
+		return "-"
+	}
+	return (time.Duration(ms) * time.Millisecond).Round(time.Second).String()
+}
+
+func orDash(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "-"
+	}
+	return s
+}
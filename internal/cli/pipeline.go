@@ -0,0 +1,281 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	pipelinev1 "github.com/arcentrix/arcentra/api/pipeline/v1"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func newPipelineCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "pipeline",
+		Aliases: []string{"pipelines", "pl"},
+		Short:   "Manage pipelines",
+	}
+	cmd.AddCommand(
+		newPipelineListCommand(a),
+		newPipelineGetCommand(a),
+		newPipelineValidateCommand(a),
+		newPipelineApplyCommand(a),
+	)
+	return cmd
+}
+
+// pageResult is the detail of paginated list endpoints.
+type pageResult[T any] struct {
+	List     []T   `json:"list"`
+	Total    int64 `json:"total"`
+	Page     int32 `json:"page"`
+	PageSize int32 `json:"pageSize"`
+}
+
+func newPipelineListCommand(a *app) *cobra.Command {
+	var (
+		projectID string
+		name      string
+		status    string
+		page      int
+		pageSize  int
+	)
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List pipelines",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			query := url.Values{}
+			setQuery(query, "projectId", projectID)
+			setQuery(query, "name", name)
+			setQuery(query, "status", status)
+			query.Set("page", strconv.Itoa(page))
+			query.Set("pageSize", strconv.Itoa(pageSize))
+
+			var result pageResult[*pipelinev1.PipelineDetail]
+			if err := c.Get(cmd.Context(), "/pipelines", query, &result); err != nil {
+				return err
+			}
+			return a.printer(cmd).Print(result, func() [][]string {
+				rows := [][]string{{"ID", "NAME", "PROJECT", "STATUS", "BRANCH", "RUNS", "UPDATED"}}
+				for _, p := range result.List {
+					rows = append(rows, []string{
+						p.GetPipelineId(),
+						p.GetName(),
+						p.GetProjectId(),
+						pipelineStatusName(p.GetStatus()),
+						orDash(p.GetDefaultBranch()),
+						fmt.Sprintf("%d/%d", p.GetSuccessRuns(), p.GetTotalRuns()),
+						formatUnix(p.GetUpdatedAt()),
+					})
+				}
+				return rows
+			})
+		},
+	}
+	cmd.Flags().StringVar(&projectID, "project", "", "filter by project ID")
+	cmd.Flags().StringVar(&name, "name", "", "filter by name")
+	cmd.Flags().StringVar(&status, "status", "", "filter by status (pending, running, success, failed, cancelled, paused)")
+	cmd.Flags().IntVar(&page, "page", 1, "page number")
+	cmd.Flags().IntVar(&pageSize, "page-size", 20, "page size")
+	return cmd
+}
+
+func newPipelineGetCommand(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <pipeline-id>",
+		Short: "Show a pipeline",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			var p pipelinev1.PipelineDetail
+			if err := c.Get(cmd.Context(), "/pipelines/"+url.PathEscape(args[0]), nil, &p); err != nil {
+				return err
+			}
+			return a.printer(cmd).PrintFields(&p, [][2]string{
+				{"ID", p.GetPipelineId()},
+				{"Name", p.GetName()},
+				{"Project", p.GetProjectId()},
+				{"Description", orDash(p.GetDescription())},
+				{"Repository", orDash(p.GetRepoUrl())},
+				{"Branch", orDash(p.GetDefaultBranch())},
+				{"Definition", orDash(p.GetPipelineFilePath())},
+				{"Status", pipelineStatusName(p.GetStatus())},
+				{"Enabled", strconv.FormatBool(p.GetIsEnabled() == 1)},
+				{"Runs", fmt.Sprintf("%d total, %d succeeded, %d failed", p.GetTotalRuns(), p.GetSuccessRuns(), p.GetFailedRuns())},
+				{"Last commit", orDash(p.GetLastCommitSha())},
+				{"Updated", formatUnix(p.GetUpdatedAt())},
+			})
+		},
+	}
+}
+
+func newPipelineValidateCommand(a *app) *cobra.Command {
+	var file string
+	cmd := &cobra.Command{
+		Use:   "validate <pipeline-id> -f <file>",
+		Short: "Validate a local pipeline definition against the control plane",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			body, err := readSpecFile(file)
+			if err != nil {
+				return err
+			}
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			var result struct {
+				JobsCount int32    `json:"jobsCount"`
+				Warnings  []string `json:"warnings"`
+			}
+			path := "/pipelines/" + url.PathEscape(args[0]) + "/spec/validate"
+			if err := c.Post(cmd.Context(), path, body, &result); err != nil {
+				return err
+			}
+			p := a.printer(cmd)
+			if p.JSON() {
+				return p.PrintJSON(result)
+			}
+			out := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(out, "%s is valid (%d jobs)\n", file, result.JobsCount)
+			for _, w := range result.Warnings {
+				_, _ = fmt.Fprintf(out, "warning: %s\n", w)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&file, "file", "f", "", "pipeline definition file (yaml or json)")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func newPipelineApplyCommand(a *app) *cobra.Command {
+	var (
+		file        string
+		message     string
+		expectedSha string
+		requestID   string
+	)
+	cmd := &cobra.Command{
+		Use:   "apply <pipeline-id> -f <file>",
+		Short: "Save a local pipeline definition to the pipeline repository",
+		Long: "Save a local pipeline definition to the pipeline repository, either as a direct commit\n" +
+			"or as a pull request depending on the pipeline save mode. Unless --expected-sha is set,\n" +
+			"the current head commit is fetched first so concurrent edits are rejected.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			body, err := readSpecFile(file)
+			if err != nil {
+				return err
+			}
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			base := "/pipelines/" + url.PathEscape(args[0]) + "/spec"
+			if expectedSha == "" {
+				var current struct {
+					HeadCommitSha string `json:"headCommitSha"`
+				}
+				// A pipeline without a definition yet has no head to compare with.
+				if err := c.Get(cmd.Context(), base, nil, &current); err == nil {
+					expectedSha = current.HeadCommitSha
+				}
+			}
+			body["expectedHeadCommitSha"] = expectedSha
+			body["commitMessage"] = message
+			body["requestId"] = requestID
+
+			var result struct {
+				CommitSha string `json:"commitSha"`
+				Branch    string `json:"branch"`
+				SaveMode  string `json:"saveMode"`
+				PrURL     string `json:"prUrl"`
+				PrBranch  string `json:"prBranch"`
+			}
+			if err := c.Post(cmd.Context(), base+"/save", body, &result); err != nil {
+				return err
+			}
+			return a.printer(cmd).PrintFields(result, [][2]string{
+				{"Commit", orDash(result.CommitSha)},
+				{"Branch", orDash(result.Branch)},
+				{"Save mode", orDash(result.SaveMode)},
+				{"Pull request", orDash(result.PrURL)},
+			})
+		},
+	}
+	cmd.Flags().StringVarP(&file, "file", "f", "", "pipeline definition file (yaml or json)")
+	cmd.Flags().StringVarP(&message, "message", "m", "", "commit message")
+	cmd.Flags().StringVar(&expectedSha, "expected-sha", "", "expected head commit of the definition")
+	cmd.Flags().StringVar(&requestID, "request-id", "", "idempotency key")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+// readSpecFile parses a local definition and returns the request body
+// fields spec and format. The spec is parsed locally so syntax errors are
+// reported before anything is sent.
+func readSpecFile(file string) (map[string]any, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", file, err)
+	}
+	format, formatName := pipelinev1.SpecFormat_SPEC_FORMAT_YAML, "yaml"
+	if strings.EqualFold(filepath.Ext(file), ".json") {
+		format, formatName = pipelinev1.SpecFormat_SPEC_FORMAT_JSON, "json"
+	}
+	parsed, err := spec.ParseContentToProto(string(content), format)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", file, err)
+	}
+	raw, err := protojson.Marshal(parsed)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", file, err)
+	}
+	return map[string]any{
+		"spec":   json.RawMessage(raw),
+		"format": formatName,
+	}, nil
+}
+
+// pipelineStatusName turns PIPELINE_STATUS_RUNNING into running.
+func pipelineStatusName(s pipelinev1.PipelineStatus) string {
+	if s == pipelinev1.PipelineStatus_PIPELINE_STATUS_UNSPECIFIED {
+		return "-"
+	}
+	return strings.ToLower(strings.TrimPrefix(s.String(), "PIPELINE_STATUS_"))
+}
+
+func setQuery(q url.Values, key, value string) {
+	if v := strings.TrimSpace(value); v != "" {
+		q.Set(key, v)
+	}
+}
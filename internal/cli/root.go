@@ -0,0 +1,98 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cli implements arcentra-cli, a command line client for the control
+// plane HTTP API.
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// app holds the global flags and the state shared by all commands.
+type app struct {
+	configPath string
+	server     string
+	output     string
+
+	config *Config
+}
+
+// NewCommand builds the arcentra-cli command tree.
+func NewCommand() *cobra.Command {
+	a := &app{}
+	root := &cobra.Command{
+		Use:           "arcentra-cli",
+		Short:         "arcentra cli is a command line tool",
+		Long:          "arcentra cli manages pipelines, runs, logs and agents of an Arcentra control plane.",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.PersistentFlags().StringVar(&a.configPath, "config", DefaultConfigPath(), "config file path")
+	root.PersistentFlags().StringVar(&a.server, "server", "", "control plane URL (overrides the config file)")
+	root.PersistentFlags().StringVarP(&a.output, "output", "o", "", "output format: table or json")
+
+	root.AddCommand(
+		newLoginCommand(a),
+		newLogoutCommand(a),
+		newPipelineCommand(a),
+		newRunCommand(a),
+		newLogsCommand(a),
+		newAgentCommand(a),
+	)
+	return root
+}
+
+// loadConfig reads the config file once and applies the --server flag.
+func (a *app) loadConfig() (*Config, error) {
+	if a.config != nil {
+		return a.config, nil
+	}
+	cfg, err := LoadConfig(a.configPath)
+	if err != nil {
+		return nil, err
+	}
+	if s := strings.TrimSpace(a.server); s != "" {
+		cfg.Server = s
+	}
+	a.config = cfg
+	return cfg, nil
+}
+
+// client returns an API client for the configured server and token.
+func (a *app) client() (*Client, error) {
+	cfg, err := a.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("not logged in, run `arcentra-cli login` first")
+	}
+	return NewClient(cfg.Server, cfg.Token), nil
+}
+
+// printer returns a printer for the --output flag, falling back to the
+// configured default.
+func (a *app) printer(cmd *cobra.Command) *Printer {
+	format := a.output
+	if format == "" {
+		if cfg, err := a.loadConfig(); err == nil {
+			format = cfg.Output
+		}
+	}
+	return NewPrinter(cmd.OutOrStdout(), format)
+}
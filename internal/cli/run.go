@@ -0,0 +1,423 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	pipelinev1 "github.com/arcentrix/arcentra/api/pipeline/v1"
+	steprunv1 "github.com/arcentrix/arcentra/api/steprun/v1"
+	"github.com/spf13/cobra"
+)
+
+// stepRun is the part of steprunv1.StepRunDetail the CLI shows. The full
+// message is not decoded because its args Struct is not plain-JSON friendly.
+type stepRun struct {
+	StepRunID    string                  `json:"step_run_id"`
+	PipelineID   string                  `json:"pipeline_id"`
+	JobID        string                  `json:"job_id"`
+	JobName      string                  `json:"job_name"`
+	StepName     string                  `json:"step_name"`
+	StepIndex    int32                   `json:"step_index"`
+	Status       steprunv1.StepRunStatus `json:"status"`
+	AgentID      string                  `json:"agent_id"`
+	ExitCode     int32                   `json:"exit_code"`
+	ErrorMessage string                  `json:"error_message"`
+	StartedAt    int64                   `json:"started_at"`
+	FinishedAt   int64                   `json:"finished_at"`
+	Duration     int64                   `json:"duration"`
+}
+
+// stepRunPageSize is the largest page the step run list endpoint serves.
+const stepRunPageSize = 200
+
+func newRunCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "run",
+		Aliases: []string{"runs"},
+		Short:   "Trigger and inspect pipeline runs",
+	}
+	cmd.AddCommand(
+		newRunTriggerCommand(a),
+		newRunListCommand(a),
+		newRunStatusCommand(a),
+		newRunCancelCommand(a),
+		newRunWatchCommand(a),
+		newRunRerunCommand(a),
+	)
+	return cmd
+}
+
+func newRunTriggerCommand(a *app) *cobra.Command {
+	var (
+		vars      []string
+		requestID string
+		watch     bool
+	)
+	cmd := &cobra.Command{
+		Use:   "trigger <pipeline-id>",
+		Short: "Trigger a pipeline run",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			variables, err := parseKeyValues(vars)
+			if err != nil {
+				return err
+			}
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			var result struct {
+				RunID   string `json:"runID"`
+				Message string `json:"message"`
+			}
+			body := map[string]any{"variables": variables, "requestId": requestID}
+			if err := c.Post(cmd.Context(), "/pipelines/"+url.PathEscape(args[0])+"/trigger", body, &result); err != nil {
+				return err
+			}
+			p := a.printer(cmd)
+			if p.JSON() && !watch {
+				return p.PrintJSON(result)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Triggered run %s\n", result.RunID)
+			if watch {
+				return watchRun(cmd, c, result.RunID, 2*time.Second)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "run variable as key=value (repeatable)")
+	cmd.Flags().StringVar(&requestID, "request-id", "", "idempotency key")
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "watch the run until it finishes")
+	return cmd
+}
+
+func newRunListCommand(a *app) *cobra.Command {
+	var (
+		status   string
+		page     int
+		pageSize int
+	)
+	cmd := &cobra.Command{
+		Use:   "list <pipeline-id>",
+		Short: "List the runs of a pipeline",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			query := url.Values{}
+			setQuery(query, "status", status)
+			query.Set("page", strconv.Itoa(page))
+			query.Set("pageSize", strconv.Itoa(pageSize))
+
+			var result pageResult[*pipelinev1.PipelineRunDetail]
+			if err := c.Get(cmd.Context(), "/pipelines/"+url.PathEscape(args[0])+"/runs", query, &result); err != nil {
+				return err
+			}
+			return a.printer(cmd).Print(result, func() [][]string {
+				rows := [][]string{{"RUN ID", "STATUS", "BRANCH", "COMMIT", "TRIGGERED BY", "STARTED", "DURATION"}}
+				for _, r := range result.List {
+					rows = append(rows, []string{
+						r.GetRunId(),
+						pipelineStatusName(r.GetStatus()),
+						orDash(r.GetBranch()),
+						shortSha(r.GetCommitSha()),
+						orDash(r.GetTriggeredBy()),
+						formatUnix(r.GetStartTime()),
+						formatMillis(r.GetDuration()),
+					})
+				}
+				return rows
+			})
+		},
+	}
+	cmd.Flags().StringVar(&status, "status", "", "filter by status")
+	cmd.Flags().IntVar(&page, "page", 1, "page number")
+	cmd.Flags().IntVar(&pageSize, "page-size", 20, "page size")
+	return cmd
+}
+
+func newRunStatusCommand(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status <run-id>",
+		Short: "Show a run and its steps",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			run, err := getRun(cmd.Context(), c, args[0])
+			if err != nil {
+				return err
+			}
+			steps, err := listStepRuns(cmd.Context(), c, args[0])
+			if err != nil {
+				return err
+			}
+
+			p := a.printer(cmd)
+			if p.JSON() {
+				return p.PrintJSON(map[string]any{"run": run, "steps": steps})
+			}
+			fields := [][2]string{
+				{"Run", run.GetRunId()},
+				{"Pipeline", fmt.Sprintf("%s (%s)", run.GetPipelineName(), run.GetPipelineId())},
+				{"Status", pipelineStatusName(run.GetStatus())},
+				{"Branch", orDash(run.GetBranch())},
+				{"Commit", shortSha(run.GetCommitSha())},
+				{"Triggered by", orDash(run.GetTriggeredBy())},
+				{"Jobs", fmt.Sprintf("%d total, %d completed, %d failed, %d running",
+					run.GetTotalJobs(), run.GetCompletedJobs(), run.GetFailedJobs(), run.GetRunningJobs())},
+				{"Started", formatUnix(run.GetStartTime())},
+				{"Duration", formatMillis(run.GetDuration())},
+			}
+			if run.GetRerunOfRunId() != "" {
+				fields = append(fields, [2]string{"Re-run of", fmt.Sprintf("%s (%s)", run.GetRerunOfRunId(), run.GetRerunMode())})
+			}
+			if err := p.PrintFields(run, fields); err != nil {
+				return err
+			}
+			if len(steps) == 0 {
+				return nil
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout())
+			return p.PrintTable(stepRows(steps))
+		},
+	}
+}
+
+func newRunCancelCommand(a *app) *cobra.Command {
+	var reason string
+	cmd := &cobra.Command{
+		Use:   "cancel <run-id>",
+		Short: "Cancel a running pipeline run",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			run, err := getRun(cmd.Context(), c, args[0])
+			if err != nil {
+				return err
+			}
+			path := "/pipelines/" + url.PathEscape(run.GetPipelineId()) + "/runs/" + url.PathEscape(run.GetRunId()) + "/stop"
+			if err := c.Post(cmd.Context(), path, map[string]string{"reason": reason}, nil); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Cancelled run %s\n", run.GetRunId())
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&reason, "reason", "cancelled from arcentra-cli", "cancel reason")
+	return cmd
+}
+
+func newRunWatchCommand(a *app) *cobra.Command {
+	var interval time.Duration
+	cmd := &cobra.Command{
+		Use:   "watch <run-id>",
+		Short: "Follow a run until it finishes",
+		Long:  "Follow a run until it finishes, printing run and step status changes. Exits non-zero when the run does not succeed.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			return watchRun(cmd, c, args[0], interval)
+		},
+	}
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "poll interval")
+	return cmd
+}
+
+func newRunRerunCommand(a *app) *cobra.Command {
+	var (
+		mode      string
+		jobName   string
+		requestID string
+		watch     bool
+	)
+	cmd := &cobra.Command{
+		Use:   "rerun <run-id>",
+		Short: "Re-run a finished run",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			run, err := getRun(cmd.Context(), c, args[0])
+			if err != nil {
+				return err
+			}
+			var result struct {
+				RunID         string   `json:"runID"`
+				Message       string   `json:"message"`
+				ReusedJobs    []string `json:"reusedJobs"`
+				ScheduledJobs []string `json:"scheduledJobs"`
+			}
+			path := "/pipelines/" + url.PathEscape(run.GetPipelineId()) + "/runs/" + url.PathEscape(run.GetRunId()) + "/rerun"
+			body := map[string]string{"mode": mode, "jobName": jobName, "requestId": requestID}
+			if err := c.Post(cmd.Context(), path, body, &result); err != nil {
+				return err
+			}
+			p := a.printer(cmd)
+			if p.JSON() && !watch {
+				return p.PrintJSON(result)
+			}
+			out := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(out, "Started re-run %s\n", result.RunID)
+			_, _ = fmt.Fprintf(out, "  scheduled: %s\n", orDash(strings.Join(result.ScheduledJobs, ", ")))
+			_, _ = fmt.Fprintf(out, "  reused:    %s\n", orDash(strings.Join(result.ReusedJobs, ", ")))
+			if watch {
+				return watchRun(cmd, c, result.RunID, 2*time.Second)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&mode, "mode", "failed-only", "re-run mode: all, failed-only or from-job")
+	cmd.Flags().StringVar(&jobName, "job", "", "job to restart from (mode from-job)")
+	cmd.Flags().StringVar(&requestID, "request-id", "", "idempotency key")
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "watch the new run until it finishes")
+	return cmd
+}
+
+// watchRun polls a run and prints run and step status transitions until the
+// run reaches a terminal status.
+func watchRun(cmd *cobra.Command, c *Client, runID string, interval time.Duration) error {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	out := cmd.OutOrStdout()
+	ctx := cmd.Context()
+	lastRun := pipelinev1.PipelineStatus_PIPELINE_STATUS_UNSPECIFIED
+	lastSteps := make(map[string]steprunv1.StepRunStatus)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		run, err := getRun(ctx, c, runID)
+		if err != nil {
+			return err
+		}
+		if steps, err := listStepRuns(ctx, c, runID); err == nil {
+			for _, s := range steps {
+				if lastSteps[s.StepRunID] == s.Status {
+					continue
+				}
+				lastSteps[s.StepRunID] = s.Status
+				_, _ = fmt.Fprintf(out, "%s  %s/%s  %s\n",
+					time.Now().Format(time.TimeOnly), s.JobName, s.StepName, stepStatusName(s.Status))
+			}
+		}
+		if run.GetStatus() != lastRun {
+			lastRun = run.GetStatus()
+			_, _ = fmt.Fprintf(out, "%s  run %s  %s\n",
+				time.Now().Format(time.TimeOnly), runID, pipelineStatusName(lastRun))
+		}
+
+		switch run.GetStatus() {
+		case pipelinev1.PipelineStatus_PIPELINE_STATUS_SUCCESS:
+			return nil
+		case pipelinev1.PipelineStatus_PIPELINE_STATUS_FAILED, pipelinev1.PipelineStatus_PIPELINE_STATUS_CANCELLED:
+			return fmt.Errorf("run %s %s", runID, pipelineStatusName(run.GetStatus()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func getRun(ctx context.Context, c *Client, runID string) (*pipelinev1.PipelineRunDetail, error) {
+	var run pipelinev1.PipelineRunDetail
+	if err := c.Get(ctx, "/pipelines/runs/"+url.PathEscape(runID), nil, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// listStepRuns returns all step runs of a run, ordered by creation.
+func listStepRuns(ctx context.Context, c *Client, runID string) ([]stepRun, error) {
+	var all []stepRun
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("pageSize", strconv.Itoa(stepRunPageSize))
+		var result pageResult[stepRun]
+		if err := c.Get(ctx, "/pipelines/runs/"+url.PathEscape(runID)+"/steps", query, &result); err != nil {
+			return nil, err
+		}
+		all = append(all, result.List...)
+		if len(result.List) < stepRunPageSize || int64(len(all)) >= result.Total {
+			return all, nil
+		}
+	}
+}
+
+func stepRows(steps []stepRun) [][]string {
+	rows := [][]string{{"JOB", "STEP", "STATUS", "AGENT", "DURATION", "STEP RUN ID"}}
+	for _, s := range steps {
+		rows = append(rows, []string{
+			orDash(s.JobName),
+			orDash(s.StepName),
+			stepStatusName(s.Status),
+			orDash(s.AgentID),
+			formatMillis(s.Duration),
+			s.StepRunID,
+		})
+	}
+	return rows
+}
+
+// stepStatusName turns STEP_RUN_STATUS_RUNNING into running.
+func stepStatusName(s steprunv1.StepRunStatus) string {
+	if s == steprunv1.StepRunStatus_STEP_RUN_STATUS_UNSPECIFIED {
+		return "-"
+	}
+	return strings.ToLower(strings.TrimPrefix(s.String(), "STEP_RUN_STATUS_"))
+}
+
+func shortSha(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return orDash(sha)
+}
+
+// parseKeyValues parses key=value pairs.
+func parseKeyValues(pairs []string) (map[string]string, error) {
+	out := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, fmt.Errorf("invalid variable %q, expected key=value", pair)
+		}
+		out[strings.TrimSpace(k)] = v
+	}
+	return out, nil
+}
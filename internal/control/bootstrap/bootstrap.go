@@ -33,6 +33,7 @@ import (
 	"github.com/arcentrix/arcentra/pkg/cron"
 	"github.com/arcentrix/arcentra/pkg/database"
 	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/arcentrix/arcentra/pkg/logindex"
 	"github.com/arcentrix/arcentra/pkg/metrics"
 	"github.com/arcentrix/arcentra/pkg/plugin"
 	"github.com/arcentrix/arcentra/pkg/safe"
@@ -54,6 +55,7 @@ type App struct {
 	Services      *service.Services
 	ShutdownMgr   *shutdown.Manager
 	Engine        *process.Process
+	CacheCleaner  *process.CacheCleaner
 }
 
 // InitAppFunc init app function type
@@ -67,7 +69,7 @@ func NewApp(
 	metricsServer *metrics.Server,
 	st storage.IStorage,
 	appConf *config.AppConfig,
-	_ database.IDatabase,
+	db database.IDatabase,
 	repos *repo.Repositories,
 	shutdownMgr *shutdown.Manager,
 	pipelineEngine *process.Process,
@@ -79,6 +81,56 @@ func NewApp(
 		rt.Services.PipelineEngine = pipelineEngine
 	}
 
+	// Finished step logs are compacted into object storage and read back from there.
+	if appConf != nil && appConf.LogArchive.Enable && st != nil && db != nil {
+		archiveConf := appConf.LogArchive
+		archiver := service.NewLogArchiver(
+			db.Database(),
+			st,
+			archiveConf.ChunkLines,
+			time.Duration(archiveConf.ArchiveDelay)*time.Second,
+			time.Duration(archiveConf.RetentionHours)*time.Hour,
+		)
+		rt.Services.LogArchiver = archiver
+		rt.Services.LogAggregator.SetArchiver(archiver)
+	}
+
+	// Step logs are indexed for full-text search as they are persisted.
+	var logIndex *logindex.Index
+	if appConf != nil && appConf.LogSearch.Enable {
+		dir := appConf.LogSearch.Dir
+		if dir == "" {
+			dir = config.DefaultLogSearchDir
+		}
+		idx, err := logindex.Open(logindex.Options{Dir: dir})
+		if err != nil {
+			return nil, nil, fmt.Errorf("open log index: %w", err)
+		}
+		logIndex = idx
+		rt.Services.LogSearch.SetIndex(idx)
+	}
+
+	// Run and job results are reported back to the triggering commit.
+	if appConf != nil && appConf.CommitStatus.Enable {
+		statusContext := appConf.CommitStatus.Context
+		if statusContext == "" {
+			statusContext = config.DefaultCommitStatusContext
+		}
+		rt.Services.CommitStatus.Enable(statusContext, appConf.CommitStatus.ExternalURL)
+	}
+
+	// Pipeline caches are evicted by TTL and per-project quota.
+	var cacheCleaner *process.CacheCleaner
+	if appConf != nil && db != nil {
+		cacheConf := appConf.Cache
+		cacheCleaner = process.NewCacheCleaner(
+			db.Database(),
+			st,
+			time.Duration(cacheConf.TTLHours)*time.Hour,
+			cacheConf.ProjectQuotaMB<<20,
+		)
+	}
+
 	app := &App{
 		HTTPApp:       httpApp,
 		PluginMgr:     pluginMgr,
@@ -91,6 +143,7 @@ func NewApp(
 		Services:      rt.Services,
 		ShutdownMgr:   shutdownMgr,
 		Engine:        pipelineEngine,
+		CacheCleaner:  cacheCleaner,
 	}
 
 	cleanup := func() {
@@ -99,6 +152,11 @@ func NewApp(
 			pipelineEngine.Stop()
 		}
 
+		// stop dispatching queued agent jobs; they stay queued for the next start
+		if rt.Services != nil && rt.Services.JobQueue != nil {
+			rt.Services.JobQueue.Stop()
+		}
+
 		// stop metrics server
 		if metricsServer != nil {
 			log.Info("Shutting down metrics server...")
@@ -126,6 +184,13 @@ func NewApp(
 		// stop global cron scheduler
 		cron.Stop()
 
+		// persist the lines indexed since the last flush
+		if logIndex != nil {
+			if err := logIndex.Close(); err != nil {
+				log.Errorw("Failed to close log index", zap.Error(err))
+			}
+		}
+
 		// shutdown OpenTelemetry tracing
 		log.Info("Shutting down OpenTelemetry tracing...")
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -194,6 +259,63 @@ func Run(app *App, cleanup func()) {
 		}, "agent-heartbeat-timeout")
 	}
 
+	// Agent job scheduler: dispatch queued jobs as agent capacity frees up.
+	if app.Services != nil && app.Services.JobQueue != nil {
+		app.Services.JobQueue.Start()
+	}
+
+	// Outgoing project webhooks: resend deliveries left pending by a restart,
+	// then sweep periodically for deliveries whose sender died.
+	if app.Services != nil && app.Services.Webhook != nil {
+		resumeCtx, resumeCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		app.Services.Webhook.ResumePending(resumeCtx)
+		resumeCancel()
+
+		_ = cron.AddFunc("*/10 * * * *", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			app.Services.Webhook.ResumePending(ctx)
+		}, "webhook-delivery-resume")
+	}
+
+	// Step log archival: archive finished steps every minute, purge archived
+	// database rows past retention hourly.
+	if app.Services != nil && app.Services.LogArchiver != nil {
+		archiver := app.Services.LogArchiver
+		_ = cron.AddFunc("*/1 * * * *", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 55*time.Second)
+			defer cancel()
+			archiver.ArchiveFinished(ctx)
+		}, "step-log-archive")
+		_ = cron.AddFunc("@every 1h", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+			defer cancel()
+			archiver.PurgeExpired(ctx)
+		}, "step-log-purge")
+	}
+
+	// Step log search: persist the index every 5 minutes, drop segments past
+	// retention daily.
+	if app.Services != nil && app.Services.LogSearch != nil && appConf.LogSearch.Enable {
+		logSearch := app.Services.LogSearch
+		_ = cron.AddFunc("*/5 * * * *", logSearch.Flush, "step-log-index-flush")
+		if days := appConf.LogSearch.RetentionDays; days > 0 {
+			_ = cron.AddFunc("@daily", func() {
+				logSearch.PurgeBefore(time.Now().AddDate(0, 0, -days))
+			}, "step-log-index-purge")
+		}
+	}
+
+	// Pipeline cache eviction: drop caches past the TTL or over project quota.
+	if app.CacheCleaner != nil {
+		cacheCleaner := app.CacheCleaner
+		_ = cron.AddFunc("@every 1h", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+			defer cancel()
+			cacheCleaner.CleanExpired(ctx)
+		}, "pipeline-cache-evict")
+	}
+
 	// Pipeline cron triggers: dynamically register each pipeline's custom cron
 	// expression with the scheduler. SyncAll on startup, then every 5 minutes.
 	if app.Engine != nil && app.Repos != nil {
@@ -213,6 +335,17 @@ func Run(app *App, cleanup func()) {
 			defer cancel()
 			cronMgr.SyncAll(ctx)
 		}, "pipeline-cron-sync")
+
+		// Pipeline triggers: start downstream pipelines when an upstream run
+		// finishes. Triggers are recorded in the database when definitions are
+		// saved or pushed, so no periodic sync is needed.
+		app.Engine.SetPipelineTriggerManager(trigger.NewPipelineTriggerManager(
+			app.Engine,
+			app.Repos.Pipeline,
+			app.Repos.Project,
+			app.Repos.JobRun,
+			service.LoadPipelineDefinition,
+		))
 	}
 
 	// Wire the pipeline process into ScmService for webhook-triggered runs.
@@ -66,6 +66,10 @@ type AppConfig struct {
 	Trace        trace.Config         `mapstructure:"trace" json:"Trace"`
 	TaskQueue    nova.TaskQueueConfig `mapstructure:"taskQueue" json:"TaskQueue"`
 	Pipeline     PipelineConfig       `mapstructure:"pipeline" json:"Pipeline"`
+	LogArchive   LogArchiveConfig     `mapstructure:"logArchive" json:"LogArchive"`
+	LogSearch    LogSearchConfig      `mapstructure:"logSearch" json:"LogSearch"`
+	Cache        CacheConfig          `mapstructure:"cache" json:"Cache"`
+	CommitStatus CommitStatusConfig   `mapstructure:"commitStatus" json:"CommitStatus"`
 }
 
 // PipelineConfig holds pipeline process settings.
@@ -73,6 +77,47 @@ type PipelineConfig struct {
 	KeepWorkspace bool `mapstructure:"keepWorkspace" json:"keepWorkspace"`
 }
 
+// LogArchiveConfig holds step log archival settings. Finished step logs are
+// compacted into object storage and purged from the database after retention.
+type LogArchiveConfig struct {
+	Enable         bool `mapstructure:"enable" json:"enable"`
+	ChunkLines     int  `mapstructure:"chunkLines" json:"chunkLines"`         // lines per archive chunk
+	ArchiveDelay   int  `mapstructure:"archiveDelay" json:"archiveDelay"`     // seconds after a step ends before archiving
+	RetentionHours int  `mapstructure:"retentionHours" json:"retentionHours"` // hours database rows are kept once archived
+}
+
+// DefaultLogSearchDir is where the log index is kept when no directory is set.
+const DefaultLogSearchDir = "./data/log-index"
+
+// LogSearchConfig holds step log full-text search settings. Lines are indexed
+// on local disk as they are persisted, so each replica only indexes the lines
+// it persisted itself; with several replicas, responses missing other
+// replicas' lines are marked partial.
+type LogSearchConfig struct {
+	Enable        bool   `mapstructure:"enable" json:"enable"`
+	Dir           string `mapstructure:"dir" json:"dir"`                     // index directory, DefaultLogSearchDir when empty
+	RetentionDays int    `mapstructure:"retentionDays" json:"retentionDays"` // days lines stay searchable, 0 keeps them forever
+}
+
+// CacheConfig holds pipeline cache eviction settings. Caches unused for the
+// TTL, and the least recently used caches over a project's quota, are evicted
+// hourly.
+type CacheConfig struct {
+	TTLHours       int   `mapstructure:"ttlHours" json:"ttlHours"`             // hours an unused cache is kept, 7 days when 0
+	ProjectQuotaMB int64 `mapstructure:"projectQuotaMB" json:"projectQuotaMB"` // total cache size per project, 10GB when 0
+}
+
+// DefaultCommitStatusContext prefixes every status context when none is set.
+const DefaultCommitStatusContext = "arcentra"
+
+// CommitStatusConfig holds SCM commit status reporting settings. Run and job
+// results are posted back to the commit that triggered them.
+type CommitStatusConfig struct {
+	Enable      bool   `mapstructure:"enable" json:"enable"`
+	Context     string `mapstructure:"context" json:"context"`         // status context prefix, DefaultCommitStatusContext when empty
+	ExternalURL string `mapstructure:"externalURL" json:"externalURL"` // public base URL used for status links
+}
+
 var (
 	cfg  AppConfig
 	mu   sync.RWMutex // 保护配置的读写
@@ -35,6 +35,9 @@ type Agent struct {
 	LastHeartbeat *time.Time     `gorm:"column:last_heartbeat" json:"lastHeartbeat,omitempty"` // 最后一次心跳时间
 	IsEnabled     int            `gorm:"column:is_enabled" json:"isEnabled"`                   // 0: disable, 1: enable
 	RegisteredBy  string         `gorm:"column:registered_by" json:"registeredBy"`             // admin, dynamic
+
+	// MaxConcurrentJobs 可同时执行的作业数，注册时上报，调度器据此计算容量
+	MaxConcurrentJobs int `gorm:"column:max_concurrent_jobs" json:"maxConcurrentJobs"`
 }
 
 func (a *Agent) TableName() string {
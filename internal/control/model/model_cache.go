@@ -0,0 +1,33 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import "time"
+
+// PipelineCache 流水线依赖/构建缓存索引表，缓存内容以 tar.gz 存放在对象存储中
+type PipelineCache struct {
+	BaseModel
+	CacheID        string     `gorm:"column:cache_id;type:varchar(64);uniqueIndex" json:"cacheId"`
+	ProjectID      string     `gorm:"column:project_id;type:varchar(64);index:idx_cache_scope" json:"projectId"`
+	Branch         string     `gorm:"column:branch;type:varchar(255);index:idx_cache_scope" json:"branch"` // 空表示项目级缓存
+	CacheKey       string     `gorm:"column:cache_key;type:varchar(512)" json:"cacheKey"`
+	ObjectName     string     `gorm:"column:object_name;type:varchar(1024)" json:"objectName"`
+	Size           int64      `gorm:"column:size" json:"size"` // 字节
+	LastAccessedAt *time.Time `gorm:"column:last_accessed_at;index" json:"lastAccessedAt"`
+}
+
+func (PipelineCache) TableName() string {
+	return "pipeline_cache"
+}
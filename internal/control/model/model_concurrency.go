@@ -0,0 +1,41 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import "time"
+
+// ConcurrencyLease 并发组租约。同一项目内组名相同的流水线执行或作业按 ID 顺序依次持有，
+// 持有者定期续约，控制面副本崩溃后租约过期即被清理，从而在多副本间互斥
+type ConcurrencyLease struct {
+	BaseModel
+	ProjectID  string    `gorm:"column:project_id" json:"projectId"`
+	GroupKey   string    `gorm:"column:group_key" json:"groupKey"` // 表达式求值后的组名
+	RunID      string    `gorm:"column:run_id" json:"runId"`
+	JobName    string    `gorm:"column:job_name" json:"jobName"`      // 为空表示流水线级并发组
+	Policy     string    `gorm:"column:policy" json:"policy"`         // queue / cancel-in-progress
+	Held       int       `gorm:"column:held" json:"held"`             // 0:等待 1:持有
+	Superseded int       `gorm:"column:superseded" json:"superseded"` // 1:已被更新的执行取代，持有者需自行取消
+	ExpiresAt  time.Time `gorm:"column:expires_at" json:"expiresAt"`
+}
+
+func (ConcurrencyLease) TableName() string {
+	return "concurrency_lease"
+}
+
+// Concurrency group policies.
+const (
+	ConcurrencyPolicyQueue            = "queue"
+	ConcurrencyPolicyCancelInProgress = "cancel-in-progress"
+)
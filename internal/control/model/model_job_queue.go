@@ -0,0 +1,157 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"slices"
+	"strconv"
+	"time"
+)
+
+// JobQueueEntry 作业排队记录。Agent 作业先持久化到队列，由调度器按优先级、项目并发上限
+// 与项目间公平份额在 Agent 有空闲容量时投递到任务队列；作业结束后记录被清理
+type JobQueueEntry struct {
+	BaseModel
+	JobRunID      string     `gorm:"column:job_run_id;type:varchar(64);uniqueIndex" json:"jobRunId"`
+	ProjectID     string     `gorm:"column:project_id" json:"projectId"` // 公平份额与并发上限按项目计算
+	PipelineID    string     `gorm:"column:pipeline_id" json:"pipelineId"`
+	PipelineRunID string     `gorm:"column:pipeline_run_id" json:"pipelineRunId"`
+	JobName       string     `gorm:"column:job_name" json:"jobName"`
+	Priority      int        `gorm:"column:priority" json:"priority"` // 1:最高 5:普通 10:最低
+	Payload       []byte     `gorm:"column:payload;type:mediumblob" json:"-"`
+	AgentSelector string     `gorm:"column:agent_selector;type:json" json:"agentSelector"` // JSON格式，作业各步骤的 Agent 选择器合并结果，为空表示任意 Agent
+	EnqueuedAt    time.Time  `gorm:"column:enqueued_at" json:"enqueuedAt"`
+	DispatchedAt  *time.Time `gorm:"column:dispatched_at" json:"dispatchedAt"` // 为空表示仍在排队
+}
+
+func (JobQueueEntry) TableName() string {
+	return "job_run_queue"
+}
+
+// JobAgentSelector 作业的 Agent 选择器，调度器据此按 Agent 标签分池计算容量
+type JobAgentSelector struct {
+	MatchLabels      map[string]string     `json:"matchLabels,omitempty"`
+	MatchExpressions []*JobLabelExpression `json:"matchExpressions,omitempty"`
+}
+
+// JobLabelExpression 标签表达式
+type JobLabelExpression struct {
+	Key      string   `json:"key"`
+	Operator string   `json:"operator"` // In, NotIn, Exists, NotExists, Gt, Lt
+	Values   []string `json:"values,omitempty"`
+}
+
+// IsEmpty 选择器为空时任意 Agent 均可执行
+func (s *JobAgentSelector) IsEmpty() bool {
+	return s == nil || (len(s.MatchLabels) == 0 && len(s.MatchExpressions) == 0)
+}
+
+// Matches 判断 Agent 标签是否满足选择器，所有条件均需满足
+func (s *JobAgentSelector) Matches(labels map[string]string) bool {
+	if s.IsEmpty() {
+		return true
+	}
+	for k, v := range s.MatchLabels {
+		if labels[k] != v {
+			return false
+		}
+	}
+	for _, expr := range s.MatchExpressions {
+		if !expr.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *JobLabelExpression) matches(labels map[string]string) bool {
+	if e == nil {
+		return true
+	}
+	value, exists := labels[e.Key]
+	switch e.Operator {
+	case "In":
+		return exists && slices.Contains(e.Values, value)
+	case "NotIn":
+		return !exists || !slices.Contains(e.Values, value)
+	case "Exists":
+		return exists
+	case "NotExists":
+		return !exists
+	case "Gt", "Lt":
+		if !exists || len(e.Values) == 0 {
+			return false
+		}
+		have, err1 := strconv.ParseFloat(value, 64)
+		want, err2 := strconv.ParseFloat(e.Values[0], 64)
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		if e.Operator == "Gt" {
+			return have > want
+		}
+		return have < want
+	default:
+		return false
+	}
+}
+
+// SchedulerLease 调度租约。多副本部署时各副本的作业调度器在每轮调度前抢占租约，
+// 同一时刻只有一个副本投递作业，避免重复计算空闲容量导致超发
+type SchedulerLease struct {
+	Name      string    `gorm:"column:name;primaryKey" json:"name"`
+	Holder    string    `gorm:"column:holder" json:"holder"`
+	ExpiresAt time.Time `gorm:"column:expires_at" json:"expiresAt"`
+}
+
+func (SchedulerLease) TableName() string {
+	return "scheduler_lease"
+}
+
+// Job queue priorities.
+const (
+	JobPriorityHighest = 1
+	JobPriorityDefault = 5
+	JobPriorityLowest  = 10
+)
+
+// QueuedJob 排队中的作业及其位置
+type QueuedJob struct {
+	JobRunID      string     `json:"jobRunId"`
+	ProjectID     string     `json:"projectId"`
+	PipelineID    string     `json:"pipelineId"`
+	PipelineRunID string     `json:"pipelineRunId"`
+	JobName       string     `json:"jobName"`
+	Priority      int        `json:"priority"`
+	EnqueuedAt    time.Time  `json:"enqueuedAt"`
+	Position      int        `json:"position"`     // 全局投递顺序，从 1 开始
+	ETASeconds    int64      `json:"etaSeconds"`   // 预计等待秒数，-1 表示无法估算
+	LimitReached  bool       `json:"limitReached"` // 所属项目已达并发上限
+	DispatchedAt  *time.Time `json:"dispatchedAt"` // 已投递、等待 Agent 领取时非空
+}
+
+// JobQueueStats 调度队列概况
+type JobQueueStats struct {
+	Capacity int `json:"capacity"` // 在线 Agent 并发容量之和，各选择器另按匹配的 Agent 计算
+	InFlight int `json:"inFlight"` // 已投递未结束的作业数
+	Waiting  int `json:"waiting"`  // 排队中的作业数
+}
+
+// JobQueueResp 调度队列查询响应
+type JobQueueResp struct {
+	JobQueueStats
+	List  []*QueuedJob `json:"list"`
+	Total int          `json:"total"`
+}
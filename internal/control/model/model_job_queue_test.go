@@ -0,0 +1,43 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import "testing"
+
+func TestJobAgentSelectorMatches(t *testing.T) {
+	labels := map[string]string{"os": "linux", "gpu": "true", "cores": "16"}
+	cases := []struct {
+		name string
+		sel  *JobAgentSelector
+		want bool
+	}{
+		{"nil", nil, true},
+		{"labels", &JobAgentSelector{MatchLabels: map[string]string{"os": "linux", "gpu": "true"}}, true},
+		{"label mismatch", &JobAgentSelector{MatchLabels: map[string]string{"os": "darwin"}}, false},
+		{"in", &JobAgentSelector{MatchExpressions: []*JobLabelExpression{{Key: "os", Operator: "In", Values: []string{"linux", "darwin"}}}}, true},
+		{"not in", &JobAgentSelector{MatchExpressions: []*JobLabelExpression{{Key: "os", Operator: "NotIn", Values: []string{"linux"}}}}, false},
+		{"exists", &JobAgentSelector{MatchExpressions: []*JobLabelExpression{{Key: "gpu", Operator: "Exists"}}}, true},
+		{"not exists", &JobAgentSelector{MatchExpressions: []*JobLabelExpression{{Key: "arch", Operator: "NotExists"}}}, true},
+		{"gt", &JobAgentSelector{MatchExpressions: []*JobLabelExpression{{Key: "cores", Operator: "Gt", Values: []string{"8"}}}}, true},
+		{"lt", &JobAgentSelector{MatchExpressions: []*JobLabelExpression{{Key: "cores", Operator: "Lt", Values: []string{"8"}}}}, false},
+		{"gt not numeric", &JobAgentSelector{MatchExpressions: []*JobLabelExpression{{Key: "os", Operator: "Gt", Values: []string{"8"}}}}, false},
+		{"unknown operator", &JobAgentSelector{MatchExpressions: []*JobLabelExpression{{Key: "os", Operator: "Like"}}}, false},
+	}
+	for _, tc := range cases {
+		if got := tc.sel.Matches(labels); got != tc.want {
+			t.Errorf("%s: Matches = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
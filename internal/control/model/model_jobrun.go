@@ -39,9 +39,12 @@ type JobRun struct {
 	FailedSteps    int        `gorm:"column:failed_steps" json:"failedSteps"`
 	ErrorMessage   string     `gorm:"column:error_message;type:text" json:"errorMessage"`
 	ArtifactURIs   string     `gorm:"column:artifact_uris;type:json" json:"artifactUris"`
+	Outputs        string     `gorm:"column:outputs;type:json" json:"outputs"`
+	ServiceLogs    string     `gorm:"column:service_logs;type:json" json:"serviceLogs"` // JSON格式，服务容器日志（按服务名）
 	StartTime      *time.Time `gorm:"column:start_time" json:"startTime"`
 	EndTime        *time.Time `gorm:"column:end_time" json:"endTime"`
 	Duration       int64      `gorm:"column:duration" json:"duration"`
+	ReusedFrom     string     `gorm:"column:reused_from" json:"reusedFrom"` // 重跑时复用的来源 JobRun ID，为空表示实际执行
 }
 
 // TableName returns the database table name.
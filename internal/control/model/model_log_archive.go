@@ -0,0 +1,43 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import "time"
+
+// StepRunLogArchive 步骤日志归档索引。步骤结束后其日志行被压缩为若干分块存入对象存储，
+// 本表记录每个分块覆盖的行号范围，按范围读取时只下载命中的分块
+type StepRunLogArchive struct {
+	BaseModel
+	StepRunID  string            `gorm:"column:step_run_id;type:varchar(64);uniqueIndex" json:"stepRunId"`
+	TotalLines int               `gorm:"column:total_lines" json:"totalLines"`
+	Size       int64             `gorm:"column:size" json:"size"`                                  // 压缩后总字节数
+	Chunks     []LogArchiveChunk `gorm:"column:chunks;type:json;serializer:json" json:"chunks"`    // 行号索引
+	Purged     int               `gorm:"column:purged;index:idx_purged_archived_at" json:"purged"` // 0:数据库仍保留日志行 1:已清理
+	ArchivedAt time.Time         `gorm:"column:archived_at;index:idx_purged_archived_at" json:"archivedAt"`
+	PurgedAt   *time.Time        `gorm:"column:purged_at" json:"purgedAt"`
+}
+
+// LogArchiveChunk 日志归档分块，内容为 gzip 压缩的 JSON Lines
+type LogArchiveChunk struct {
+	Object    string `json:"object"`    // 对象存储路径
+	FirstLine int32  `json:"firstLine"` // 分块内首行行号
+	LastLine  int32  `json:"lastLine"`  // 分块内末行行号
+	Lines     int    `json:"lines"`
+	Size      int64  `json:"size"` // 压缩后字节数
+}
+
+func (StepRunLogArchive) TableName() string {
+	return "step_run_log_archive"
+}
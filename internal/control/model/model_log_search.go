@@ -0,0 +1,61 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+// LogSearchReq 日志全文检索请求，Query 与 Regex 至少指定一个，同时指定时需同时匹配
+type LogSearchReq struct {
+	Query      string `json:"query"`      // 短语，不区分大小写，按词边界匹配
+	Regex      string `json:"regex"`      // RE2 正则表达式
+	ProjectID  string `json:"projectId"`  // 项目过滤，与 PipelineID 至少指定一个（需有项目读权限）
+	PipelineID string `json:"pipelineId"` // 流水线过滤
+	Branch     string `json:"branch"`     // 运行分支过滤
+	Status     int    `json:"status"`     // 步骤状态过滤，0 表示不限
+	StartTime  int64  `json:"startTime"`  // 日志时间下界（unix 秒），0 表示不限
+	EndTime    int64  `json:"endTime"`    // 日志时间上界（unix 秒），0 表示不限
+	Order      string `json:"order"`      // desc（默认，最新在前）/ asc
+	PageNum    int    `json:"pageNum"`
+	PageSize   int    `json:"pageSize"`
+}
+
+// LogSearchHit 日志检索命中行
+type LogSearchHit struct {
+	StepRunID     string   `json:"stepRunId"`
+	StepName      string   `json:"stepName"`
+	StepStatus    int      `json:"stepStatus"`
+	PipelineID    string   `json:"pipelineId"`
+	PipelineRunID string   `json:"pipelineRunId"`
+	JobID         string   `json:"jobId"`
+	Branch        string   `json:"branch"`
+	LineNumber    int32    `json:"lineNumber"`
+	Timestamp     int64    `json:"timestamp"`
+	Content       string   `json:"content"`
+	Highlights    [][2]int `json:"highlights"`  // 命中区间（字节偏移，左闭右开）
+	Highlighted   string   `json:"highlighted"` // HTML 转义后以 <mark> 标记命中区间的内容
+	Link          string   `json:"link"`        // 跳转到运行日志对应行的前端地址
+}
+
+// LogSearchResp 日志检索响应
+type LogSearchResp struct {
+	List      []*LogSearchHit `json:"list"`
+	Total     int             `json:"total"`
+	Truncated bool            `json:"truncated"` // 结果不完整：命中超过 MaxMatches 条，或仅检索了最新的 50000 个步骤
+	PageNum   int             `json:"pageNum"`
+	PageSize  int             `json:"pageSize"`
+
+	// Partial 结果不完整：索引只保存在各副本本地，部分已运行步骤的日志不在处理本次请求的副本的索引中
+	// （由其他副本写入，或已超出保留期），多副本部署时可能出现
+	Partial           bool `json:"partial"`
+	UnindexedStepRuns int  `json:"unindexedStepRuns"` // 已运行但日志不在本副本索引中的步骤数
+}
@@ -15,6 +15,7 @@
 package model
 
 import (
+	"github.com/bytedance/sonic"
 	"gorm.io/datatypes"
 )
 
@@ -74,3 +75,152 @@ const (
 	PlanPro        = "pro"        // 专业版
 	PlanEnterprise = "enterprise" // 企业版
 )
+
+// OrgQuota 组织配额，0 表示不限制
+type OrgQuota struct {
+	MaxMembers  int `json:"maxMembers"`  // 最大成员数
+	MaxProjects int `json:"maxProjects"` // 最大项目数
+	MaxTeams    int `json:"maxTeams"`    // 最大团队数
+}
+
+// PlanQuotas 各订阅计划的默认配额
+var PlanQuotas = map[string]OrgQuota{
+	PlanFree:       {MaxMembers: 10, MaxProjects: 5, MaxTeams: 3},
+	PlanPro:        {MaxMembers: 100, MaxProjects: 100, MaxTeams: 50},
+	PlanEnterprise: {},
+}
+
+// IsValidPlan 判断订阅计划是否合法
+func IsValidPlan(plan string) bool {
+	_, ok := PlanQuotas[plan]
+	return ok
+}
+
+// Quota 返回组织的有效配额：以订阅计划的默认配额为基础，Settings 中大于 0 的上限覆盖默认值
+func (o *Organization) Quota() OrgQuota {
+	quota, ok := PlanQuotas[o.Plan]
+	if !ok {
+		quota = PlanQuotas[PlanFree]
+	}
+	if len(o.Settings) == 0 {
+		return quota
+	}
+	var settings OrganizationSettings
+	if err := sonic.Unmarshal(o.Settings, &settings); err != nil {
+		return quota
+	}
+	if settings.MaxMembers > 0 {
+		quota.MaxMembers = settings.MaxMembers
+	}
+	if settings.MaxProjects > 0 {
+		quota.MaxProjects = settings.MaxProjects
+	}
+	if settings.MaxTeams > 0 {
+		quota.MaxTeams = settings.MaxTeams
+	}
+	return quota
+}
+
+type CreateOrganizationReq struct {
+	Name        string                 `json:"name" validate:"required,min=2,max=64"`
+	DisplayName string                 `json:"displayName"`
+	Description string                 `json:"description"`
+	Logo        string                 `json:"logo"`
+	Website     string                 `json:"website"`
+	Email       string                 `json:"email"`
+	Phone       string                 `json:"phone"`
+	Address     string                 `json:"address"`
+	Plan        string                 `json:"plan"` // 默认 free
+	Settings    map[string]interface{} `json:"settings"`
+}
+
+type UpdateOrganizationReq struct {
+	DisplayName *string                `json:"displayName,omitempty"`
+	Description *string                `json:"description,omitempty"`
+	Logo        *string                `json:"logo,omitempty"`
+	Website     *string                `json:"website,omitempty"`
+	Email       *string                `json:"email,omitempty"`
+	Phone       *string                `json:"phone,omitempty"`
+	Address     *string                `json:"address,omitempty"`
+	Plan        *string                `json:"plan,omitempty"`
+	Settings    map[string]interface{} `json:"settings,omitempty"`
+	Status      *int                   `json:"status,omitempty"`
+	IsEnabled   *int                   `json:"isEnabled,omitempty"`
+}
+
+type OrganizationQueryReq struct {
+	Name     string `json:"name" form:"name"`
+	Plan     string `json:"plan" form:"plan"`
+	Status   *int   `json:"status" form:"status"`
+	Page     int    `json:"page" form:"page"`
+	PageSize int    `json:"pageSize" form:"pageSize"`
+}
+
+type OrganizationResp struct {
+	OrgID         string                 `json:"orgId"`
+	Name          string                 `json:"name"`
+	DisplayName   string                 `json:"displayName"`
+	Description   string                 `json:"description"`
+	Logo          string                 `json:"logo"`
+	Website       string                 `json:"website"`
+	Email         string                 `json:"email"`
+	Phone         string                 `json:"phone"`
+	Address       string                 `json:"address"`
+	Settings      map[string]interface{} `json:"settings"`
+	Plan          string                 `json:"plan"`
+	Quota         OrgQuota               `json:"quota"`
+	Status        int                    `json:"status"`
+	OwnerUserID   string                 `json:"ownerUserId"`
+	IsEnabled     int                    `json:"isEnabled"`
+	TotalMembers  int                    `json:"totalMembers"`
+	TotalTeams    int                    `json:"totalTeams"`
+	TotalProjects int                    `json:"totalProjects"`
+	CreatedAt     string                 `json:"createdAt"`
+	UpdatedAt     string                 `json:"updatedAt"`
+}
+
+type OrganizationListResp struct {
+	Organizations []*OrganizationResp `json:"organizations"`
+	Total         int64               `json:"total"`
+	Page          int                 `json:"page"`
+	PageSize      int                 `json:"pageSize"`
+	TotalPages    int                 `json:"totalPages"`
+}
+
+func ToOrganizationResp(org *Organization) *OrganizationResp {
+	if org == nil {
+		return nil
+	}
+
+	resp := &OrganizationResp{
+		OrgID:         org.OrgID,
+		Name:          org.Name,
+		DisplayName:   org.DisplayName,
+		Description:   org.Description,
+		Logo:          org.Logo,
+		Website:       org.Website,
+		Email:         org.Email,
+		Phone:         org.Phone,
+		Address:       org.Address,
+		Plan:          org.Plan,
+		Quota:         org.Quota(),
+		Status:        org.Status,
+		OwnerUserID:   org.OwnerUserID,
+		IsEnabled:     org.IsEnabled,
+		TotalMembers:  org.TotalMembers,
+		TotalTeams:    org.TotalTeams,
+		TotalProjects: org.TotalProjects,
+		CreatedAt:     org.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:     org.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+
+	// 解析 Settings JSON
+	if len(org.Settings) > 0 {
+		settings := make(map[string]interface{})
+		if err := sonic.Unmarshal(org.Settings, &settings); err == nil {
+			resp.Settings = settings
+		}
+	}
+
+	return resp
+}
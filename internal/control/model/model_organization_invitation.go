@@ -14,6 +14,8 @@
 
 package model
 
+import "time"
+
 // OrganizationInvitation 组织邀请表
 type OrganizationInvitation struct {
 	BaseModel
@@ -38,3 +40,45 @@ const (
 	InvitationStatusRejected = 2 // 已拒绝
 	InvitationStatusExpired  = 3 // 已过期
 )
+
+// InvitationTTL 邀请默认有效期
+const InvitationTTL = 7 * 24 * time.Hour
+
+type CreateOrganizationInvitationReq struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role"` // 默认 member
+}
+
+type AcceptOrganizationInvitationReq struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// OrganizationInvitationResp 邀请响应，Token 仅在创建时返回一次
+type OrganizationInvitationResp struct {
+	InvitationID string `json:"invitationId"`
+	OrgID        string `json:"orgId"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	InvitedBy    string `json:"invitedBy"`
+	Status       int    `json:"status"`
+	ExpiresAt    string `json:"expiresAt"`
+	Token        string `json:"token,omitempty"`
+	EmailSent    bool   `json:"emailSent"`
+	CreatedAt    string `json:"createdAt"`
+}
+
+func ToOrganizationInvitationResp(inv *OrganizationInvitation) *OrganizationInvitationResp {
+	if inv == nil {
+		return nil
+	}
+	return &OrganizationInvitationResp{
+		InvitationID: inv.InvitationID,
+		OrgID:        inv.OrgID,
+		Email:        inv.Email,
+		Role:         inv.Role,
+		InvitedBy:    inv.InvitedBy,
+		Status:       inv.Status,
+		ExpiresAt:    inv.ExpiresAt,
+		CreatedAt:    inv.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -43,3 +43,17 @@ const (
 	OrgMemberStatusActive   = 1 // 正常
 	OrgMemberStatusDisabled = 2 // 禁用
 )
+
+// IsValidOrgRole 判断组织角色是否合法
+func IsValidOrgRole(role string) bool {
+	switch role {
+	case OrgRoleOwner, OrgRoleAdmin, OrgRoleMember:
+		return true
+	}
+	return false
+}
+
+type UpdateOrganizationMemberReq struct {
+	Role   *string `json:"role,omitempty"`   // owner/admin/member
+	Status *int    `json:"status,omitempty"` // 1-正常, 2-禁用
+}
@@ -43,6 +43,10 @@ type Pipeline struct {
 	FailedRuns        int        `gorm:"column:failed_runs" json:"failedRuns"`
 	CreatedBy         string     `gorm:"column:created_by" json:"createdBy"`
 	IsEnabled         int        `gorm:"column:is_enabled" json:"isEnabled"` // 0: disabled, 1: enabled
+
+	// DiscoveryPath 自动发现的定义文件路径，仅自动发现创建的流水线非空；(project_id, discovery_path)
+	// 唯一，并发推送不会为同一文件重复创建流水线
+	DiscoveryPath *string `gorm:"column:discovery_path" json:"discoveryPath,omitempty"`
 }
 
 func (Pipeline) TableName() string {
@@ -72,13 +76,31 @@ type PipelineRun struct {
 	TotalStages         int        `gorm:"column:total_stages" json:"totalStages"`
 	StartTime           *time.Time `gorm:"column:start_time" json:"startTime"`
 	EndTime             *time.Time `gorm:"column:end_time" json:"endTime"`
-	Duration            int64      `gorm:"column:duration" json:"duration"` // 毫秒
+	Duration            int64      `gorm:"column:duration" json:"duration"`             // 毫秒
+	RerunOfRunID        string     `gorm:"column:rerun_of_run_id" json:"rerunOfRunId"`  // 重跑来源 Run
+	RerunMode           string     `gorm:"column:rerun_mode" json:"rerunMode"`          // all / failed-only / from-job
+	UpstreamRunID       string     `gorm:"column:upstream_run_id" json:"upstreamRunId"` // 触发本次运行的上游 Run（pipeline 触发器）
+	ChangeRequest       int        `gorm:"column:change_request" json:"changeRequest"`  // 是否由 PR/MR 触发 0:否 1:是，此类运行不视为受保护分支
 }
 
 func (PipelineRun) TableName() string {
 	return "pipeline_run"
 }
 
+// PipelineUpstream 流水线 pipeline 触发器的登记表：定义保存或推送到默认分支时按定义中的
+// triggers[].type=pipeline 重建，上游运行结束时按上游项目查询，无需加载全部定义
+type PipelineUpstream struct {
+	BaseModel
+	PipelineID        string `gorm:"column:pipeline_id" json:"pipelineId"`                // 下游 Pipeline
+	ProjectID         string `gorm:"column:project_id" json:"projectId"`                  // 下游 Pipeline 所在项目
+	UpstreamProjectID string `gorm:"column:upstream_project_id" json:"upstreamProjectId"` // 上游项目 ID（触发器 project 选项，缺省为下游项目）
+	Options           string `gorm:"column:options;type:json" json:"options"`             // 触发器 options
+}
+
+func (PipelineUpstream) TableName() string {
+	return "pipeline_upstream"
+}
+
 // PipelineStage 流水线阶段表
 type PipelineStage struct {
 	BaseModel
@@ -104,6 +126,13 @@ const (
 	PipelineStatusPaused    = 6
 )
 
+// RerunModeAll and related constants enumerate pipeline re-run modes.
+const (
+	RerunModeAll        = "all"         // 全部 Job 重新执行
+	RerunModeFailedOnly = "failed-only" // 仅重跑未成功的 Job
+	RerunModeFromJob    = "from-job"    // 从指定 Job 及其下游重跑
+)
+
 // PipelineSaveModeDirect and PipelineSaveModePR define how pipeline definitions are saved.
 const (
 	PipelineSaveModeDirect = 1
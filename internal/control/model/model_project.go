@@ -15,6 +15,12 @@
 package model
 
 import (
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bytedance/sonic"
 	"gorm.io/datatypes"
 )
 
@@ -60,11 +66,61 @@ func (Project) TableName() string {
 	return "project"
 }
 
+// IsProtectedBranch 判断分支是否受保护：默认分支或匹配 Settings.protected_branches 的分支
+func (p *Project) IsProtectedBranch(branch string) bool {
+	if branch == "" {
+		return false
+	}
+	if branch == p.DefaultBranch {
+		return true
+	}
+	if len(p.Settings) == 0 {
+		return false
+	}
+	var settings ProjectSettings
+	if err := sonic.Unmarshal(p.Settings, &settings); err != nil {
+		return false
+	}
+	for _, pattern := range settings.ProtectedBranches {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ConcurrencyLimit 返回项目同时执行的 Agent 作业上限（Settings.max_concurrent），0 表示不限
+func (p *Project) ConcurrencyLimit() int {
+	if len(p.Settings) == 0 {
+		return 0
+	}
+	var settings ProjectSettings
+	if err := sonic.Unmarshal(p.Settings, &settings); err != nil {
+		return 0
+	}
+	return max(settings.MaxConcurrent, 0)
+}
+
+// AllowsDownstream 判断 projectID 下的流水线能否由本项目的运行触发：同项目或在 Settings.downstream_projects 中
+func (p *Project) AllowsDownstream(projectID string) bool {
+	if projectID == p.ProjectID {
+		return true
+	}
+	if len(p.Settings) == 0 {
+		return false
+	}
+	var settings ProjectSettings
+	if err := sonic.Unmarshal(p.Settings, &settings); err != nil {
+		return false
+	}
+	return slices.Contains(settings.DownstreamProjects, projectID)
+}
+
 // ProjectSettings 项目设置结构
 type ProjectSettings struct {
 	AutoCancel      bool     `json:"auto_cancel"`       // 自动取消之前的构建
 	Timeout         int      `json:"timeout"`           // 全局超时时间(秒)
-	MaxConcurrent   int      `json:"max_concurrent"`    // 最大并发构建数
+	MaxConcurrent   int      `json:"max_concurrent"`    // 最大并发构建数（同时执行的 Agent 作业数）
 	RetryCount      int      `json:"retry_count"`       // 默认重试次数
 	NotifyOnSuccess bool     `json:"notify_on_success"` // 成功时通知
 	NotifyOnFailure bool     `json:"notify_on_failure"` // 失败时通知
@@ -74,6 +130,48 @@ type ProjectSettings struct {
 	AllowedPaths    []string `json:"allowed_paths"`     // 触发构建的文件路径
 	IgnoredPaths    []string `json:"ignored_paths"`     // 忽略的文件路径
 	BadgeEnabled    bool     `json:"badge_enabled"`     // 启用构建状态徽章
+	// 受保护分支（支持 path.Match 通配符，如 release/*），默认分支始终受保护
+	ProtectedBranches []string `json:"protected_branches"`
+	// 允许通过 pipeline 触发器订阅本项目运行结果的其他项目 ID，本项目内的流水线始终允许
+	DownstreamProjects []string `json:"downstream_projects"`
+	// 流水线即代码自动发现
+	PipelineDiscovery PipelineDiscoverySettings `json:"pipeline_discovery"`
+}
+
+// PipelineDiscoverySettings 流水线自动发现配置：默认分支收到推送时，按 Paths 扫描推送提交中的定义文件，
+// 并据此创建、更新或禁用项目下的 Pipeline
+type PipelineDiscoverySettings struct {
+	Enabled bool     `json:"enabled"` // 启用自动发现
+	Paths   []string `json:"paths"`   // 定义文件匹配模式（相对仓库根目录，path.Match 语法），为空时使用默认模式
+}
+
+// DefaultPipelineDiscoveryPaths 自动发现默认扫描的定义文件
+var DefaultPipelineDiscoveryPaths = []string{".arcentra/*.yaml", ".arcentra/*.yml"}
+
+// PipelineDiscovery 返回项目的流水线自动发现配置，未启用时第二个返回值为 false
+func (p *Project) PipelineDiscovery() (PipelineDiscoverySettings, bool) {
+	if len(p.Settings) == 0 {
+		return PipelineDiscoverySettings{}, false
+	}
+	var settings ProjectSettings
+	if err := sonic.Unmarshal(p.Settings, &settings); err != nil {
+		return PipelineDiscoverySettings{}, false
+	}
+	discovery := settings.PipelineDiscovery
+	if !discovery.Enabled {
+		return discovery, false
+	}
+	paths := make([]string, 0, len(discovery.Paths))
+	for _, one := range discovery.Paths {
+		if one = strings.TrimLeft(strings.TrimSpace(one), "/"); one != "" {
+			paths = append(paths, one)
+		}
+	}
+	if len(paths) == 0 {
+		paths = append(paths, DefaultPipelineDiscoveryPaths...)
+	}
+	discovery.Paths = paths
+	return discovery, true
 }
 
 // BuildConfig 构建配置结构
@@ -109,13 +207,14 @@ const (
 
 // ProjectRepoType 仓库类型枚举
 const (
-	RepoTypeGit       = "git"       // 通用Git
-	RepoTypeGitHub    = "github"    // GitHub
-	RepoTypeGitLab    = "gitlab"    // GitLab
-	RepoTypeGitee     = "gitee"     // Gitee
-	RepoTypeBitbucket = "bitbucket" // Bitbucket
-	RepoTypeGitea     = "gitea"     // Gitea
-	RepoTypeSVN       = "svn"       // SVN
+	RepoTypeGit       = "git"         // 通用Git
+	RepoTypeGitHub    = "github"      // GitHub
+	RepoTypeGitLab    = "gitlab"      // GitLab
+	RepoTypeGitee     = "gitee"       // Gitee
+	RepoTypeBitbucket = "bitbucket"   // Bitbucket
+	RepoTypeGitea     = "gitea"       // Gitea
+	RepoTypeAzure     = "azuredevops" // Azure DevOps Repos
+	RepoTypeSVN       = "svn"         // SVN
 )
 
 // ProjectStatus 项目状态枚举
@@ -174,6 +273,116 @@ const (
 	VariableTypeFile   = "file"   // 文件
 )
 
+// variableKeyPattern 变量键必须是合法的环境变量名
+var variableKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// IsValidVariableKey 判断变量键是否合法
+func IsValidVariableKey(key string) bool {
+	return variableKeyPattern.MatchString(key)
+}
+
+// IsValidVariableType 判断变量类型是否合法
+func IsValidVariableType(t string) bool {
+	switch t {
+	case VariableTypeEnv, VariableTypeSecret, VariableTypeFile:
+		return true
+	}
+	return false
+}
+
+// CreateVariableReq 创建项目/团队变量请求
+type CreateVariableReq struct {
+	Key         string `json:"key" validate:"required"`
+	Value       string `json:"value"`
+	Type        string `json:"type"` // env/secret/file，默认 env
+	Protected   bool   `json:"protected"`
+	Masked      bool   `json:"masked"`
+	Description string `json:"description"`
+}
+
+// UpdateVariableReq 更新项目/团队变量请求
+type UpdateVariableReq struct {
+	Key         *string `json:"key,omitempty"`
+	Value       *string `json:"value,omitempty"`
+	Type        *string `json:"type,omitempty"`
+	Protected   *bool   `json:"protected,omitempty"`
+	Masked      *bool   `json:"masked,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// VariableResp 变量响应，掩码变量与 secret 类型变量不返回值
+type VariableResp struct {
+	VariableID  string `json:"variableId"`
+	Scope       string `json:"scope"`   // project/team
+	ScopeID     string `json:"scopeId"` // 项目ID或团队ID
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Type        string `json:"type"`
+	Protected   bool   `json:"protected"`
+	Masked      bool   `json:"masked"`
+	Description string `json:"description"`
+	CreatedAt   string `json:"createdAt"`
+	UpdatedAt   string `json:"updatedAt"`
+}
+
+// 变量作用域
+const (
+	VariableScopeProject = "project"
+	VariableScopeTeam    = "team"
+)
+
+// redactVariableValue 掩码变量与 secret 类型变量的值不通过接口返回;
+// 受保护变量的值仅返回给有写权限的调用者
+func redactVariableValue(value, varType string, masked, protected int, canWrite bool) string {
+	if masked == 1 || varType == VariableTypeSecret {
+		return ""
+	}
+	if protected == 1 && !canWrite {
+		return ""
+	}
+	return value
+}
+
+// ToProjectVariableResp canWrite 表示调用者对项目有写权限
+func ToProjectVariableResp(v *ProjectVariable, canWrite bool) *VariableResp {
+	if v == nil {
+		return nil
+	}
+	return &VariableResp{
+		VariableID:  v.VariableID,
+		Scope:       VariableScopeProject,
+		ScopeID:     v.ProjectID,
+		Key:         v.Key,
+		Value:       redactVariableValue(v.Value, v.Type, v.Masked, v.Protected, canWrite),
+		Type:        v.Type,
+		Protected:   v.Protected == 1,
+		Masked:      v.Masked == 1,
+		Description: v.Description,
+		CreatedAt:   v.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:   v.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// ToTeamVariableResp canWrite 表示调用者对团队有写权限
+func ToTeamVariableResp(v *TeamVariable, canWrite bool) *VariableResp {
+	if v == nil {
+		return nil
+	}
+	return &VariableResp{
+		VariableID:  v.VariableID,
+		Scope:       VariableScopeTeam,
+		ScopeID:     v.TeamID,
+		Key:         v.Key,
+		Value:       redactVariableValue(v.Value, v.Type, v.Masked, v.Protected, canWrite),
+		Type:        v.Type,
+		Protected:   v.Protected == 1,
+		Masked:      v.Masked == 1,
+		Description: v.Description,
+		CreatedAt:   v.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:   v.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
 // ProjectAccessLevel 项目访问级别
 const (
 	AccessLevelOwner = "owner" // 仅所有者
@@ -0,0 +1,72 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"gorm.io/datatypes"
+)
+
+func TestProjectIsProtectedBranch(t *testing.T) {
+	p := &Project{
+		DefaultBranch: "main",
+		Settings:      datatypes.JSON(`{"protected_branches":["release/*","prod"]}`),
+	}
+	cases := map[string]bool{
+		"main":         true,
+		"release/1.0":  true,
+		"prod":         true,
+		"release/1/x":  false,
+		"feature/main": false,
+		"production":   false,
+		"":             false,
+	}
+	for branch, want := range cases {
+		if got := p.IsProtectedBranch(branch); got != want {
+			t.Errorf("IsProtectedBranch(%q) = %v, want %v", branch, got, want)
+		}
+	}
+
+	// Only the default branch is protected without settings, or with
+	// settings that do not parse.
+	for _, settings := range []datatypes.JSON{nil, datatypes.JSON(`{`)} {
+		p := &Project{DefaultBranch: "main", Settings: settings}
+		if !p.IsProtectedBranch("main") || p.IsProtectedBranch("release/1.0") {
+			t.Errorf("IsProtectedBranch with settings %q: want only main protected", settings)
+		}
+	}
+}
+
+func TestProjectAllowsDownstream(t *testing.T) {
+	p := &Project{
+		ProjectID: "p-platform",
+		Settings:  datatypes.JSON(`{"downstream_projects":["p-deploy"]}`),
+	}
+	cases := map[string]bool{
+		"p-platform": true,
+		"p-deploy":   true,
+		"p-audit":    false,
+		"":           false,
+	}
+	for projectID, want := range cases {
+		if got := p.AllowsDownstream(projectID); got != want {
+			t.Errorf("AllowsDownstream(%q) = %v, want %v", projectID, got, want)
+		}
+	}
+	if (&Project{ProjectID: "p-platform"}).AllowsDownstream("p-deploy") {
+		t.Error("AllowsDownstream without settings: want only the project itself")
+	}
+}
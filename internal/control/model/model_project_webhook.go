@@ -0,0 +1,137 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"path"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// Webhook 投递状态
+const (
+	WebhookDeliveryPending   = 0 // 等待投递/重试中
+	WebhookDeliverySucceeded = 1 // 投递成功（2xx）
+	WebhookDeliveryFailed    = 2 // 重试耗尽后失败
+)
+
+// WebhookEventAll 订阅全部事件
+const WebhookEventAll = "*"
+
+// ProjectWebhookDelivery 项目Webhook投递记录表
+type ProjectWebhookDelivery struct {
+	BaseModel
+	DeliveryID   string     `gorm:"column:delivery_id" json:"deliveryId"`               // 投递唯一标识，同时作为 X-Arcentra-Delivery 头
+	WebhookID    string     `gorm:"column:webhook_id" json:"webhookId"`                 // Webhook ID
+	ProjectID    string     `gorm:"column:project_id" json:"projectId"`                 // 项目ID
+	Event        string     `gorm:"column:event" json:"event"`                          // 事件名，如 pipeline.completed
+	Payload      string     `gorm:"column:payload;type:json" json:"payload"`            // 请求体（JSON）
+	Status       int        `gorm:"column:status" json:"status"`                        // 0:等待 1:成功 2:失败
+	Attempts     int        `gorm:"column:attempts" json:"attempts"`                    // 已尝试次数
+	ResponseCode int        `gorm:"column:response_code" json:"responseCode"`           // 最后一次响应状态码
+	ResponseBody string     `gorm:"column:response_body;type:text" json:"responseBody"` // 最后一次响应体（截断）
+	ErrorMessage string     `gorm:"column:error_message;type:text" json:"errorMessage"` // 最后一次错误信息
+	Duration     int64      `gorm:"column:duration" json:"duration"`                    // 最后一次请求耗时（毫秒）
+	RedeliveryOf string     `gorm:"column:redelivery_of" json:"redeliveryOf"`           // 重新投递的来源投递ID
+	DeliveredAt  *time.Time `gorm:"column:delivered_at" json:"deliveredAt"`             // 最后一次尝试时间
+	ClaimedAt    *time.Time `gorm:"column:claimed_at" json:"-"`                         // 发送方认领时间，多副本下防止重复投递
+}
+
+func (ProjectWebhookDelivery) TableName() string {
+	return "project_webhook_delivery"
+}
+
+// WebhookEvents 解析 Webhook 订阅的事件列表
+func (w *ProjectWebhook) WebhookEvents() []string {
+	if w == nil || len(w.Events) == 0 {
+		return nil
+	}
+	var events []string
+	if err := sonic.Unmarshal(w.Events, &events); err != nil {
+		return nil
+	}
+	return events
+}
+
+// Subscribes 判断 Webhook 是否订阅了指定事件。
+// 支持 "*" 订阅全部事件，以及 "job.*" 这类通配符。
+func (w *ProjectWebhook) Subscribes(event string) bool {
+	for _, pattern := range w.WebhookEvents() {
+		if pattern == WebhookEventAll || pattern == event {
+			return true
+		}
+		if ok, err := path.Match(pattern, event); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateProjectWebhookReq 创建项目Webhook请求
+type CreateProjectWebhookReq struct {
+	Name        string   `json:"name" validate:"required"`
+	URL         string   `json:"url" validate:"required"`
+	Secret      string   `json:"secret"`
+	Events      []string `json:"events"` // 为空时订阅全部事件
+	IsEnabled   *bool    `json:"isEnabled,omitempty"`
+	Description string   `json:"description"`
+}
+
+// UpdateProjectWebhookReq 更新项目Webhook请求
+type UpdateProjectWebhookReq struct {
+	Name        *string   `json:"name,omitempty"`
+	URL         *string   `json:"url,omitempty"`
+	Secret      *string   `json:"secret,omitempty"`
+	Events      *[]string `json:"events,omitempty"`
+	IsEnabled   *bool     `json:"isEnabled,omitempty"`
+	Description *string   `json:"description,omitempty"`
+}
+
+// ProjectWebhookResp 项目Webhook响应，不返回密钥
+type ProjectWebhookResp struct {
+	WebhookID   string   `json:"webhookId"`
+	ProjectID   string   `json:"projectId"`
+	Name        string   `json:"name"`
+	URL         string   `json:"url"`
+	HasSecret   bool     `json:"hasSecret"`
+	Events      []string `json:"events"`
+	IsEnabled   bool     `json:"isEnabled"`
+	Description string   `json:"description"`
+	CreatedAt   string   `json:"createdAt"`
+	UpdatedAt   string   `json:"updatedAt"`
+}
+
+func ToProjectWebhookResp(w *ProjectWebhook) *ProjectWebhookResp {
+	if w == nil {
+		return nil
+	}
+	events := w.WebhookEvents()
+	if events == nil {
+		events = []string{}
+	}
+	return &ProjectWebhookResp{
+		WebhookID:   w.WebhookID,
+		ProjectID:   w.ProjectID,
+		Name:        w.Name,
+		URL:         w.URL,
+		HasSecret:   w.Secret != "",
+		Events:      events,
+		IsEnabled:   w.IsEnabled == 1,
+		Description: w.Description,
+		CreatedAt:   w.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:   w.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
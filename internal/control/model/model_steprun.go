@@ -39,6 +39,7 @@ type StepRun struct {
 	Workspace       string     `gorm:"column:workspace" json:"workspace"`
 	Env             string     `gorm:"column:env;type:json" json:"env"`         // JSON格式
 	Secrets         string     `gorm:"column:secrets;type:json" json:"secrets"` // JSON格式
+	Outputs         string     `gorm:"column:outputs;type:json" json:"outputs"` // JSON格式，步骤输出
 	Timeout         string     `gorm:"column:timeout" json:"timeout"`           // 超时时间（如 "30m", "1h"）
 	RetryCount      int        `gorm:"column:retry_count" json:"retryCount"`
 	CurrentRetry    int        `gorm:"column:current_retry" json:"currentRetry"`
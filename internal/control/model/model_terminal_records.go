@@ -29,9 +29,9 @@ type TerminalOutputRecord struct {
 	WorkingDirectory string                 `gorm:"column:working_directory;type:VARCHAR(255)" json:"workingDirectory"`
 	Command          string                 `gorm:"column:command;type:TEXT" json:"command"`
 	ExitCode         *int                   `gorm:"column:exit_code;type:INT" json:"exitCode,omitempty"`
-	Logs             []TerminalOutputLine   `gorm:"column:logs;type:JSON" json:"logs"`                  // JSON 字符串
-	Metadata         TerminalOutputMetadata `gorm:"column:metadata;type:JSON" json:"metadata"`          // JSON 字符串
-	Status           string                 `gorm:"column:status;type:VARCHAR(32);index" json:"status"` // running/completed/failed/timeout
+	Logs             []TerminalOutputLine   `gorm:"column:logs;type:JSON;serializer:json" json:"logs"`         // 会话记录，读取时由分块拼接
+	Metadata         TerminalOutputMetadata `gorm:"column:metadata;type:JSON;serializer:json" json:"metadata"` // JSON 字符串
+	Status           string                 `gorm:"column:status;type:VARCHAR(32);index" json:"status"`        // running/completed/failed/timeout
 	StartTime        time.Time              `gorm:"column:start_time;type:DATETIME" json:"startTime"`
 	EndTime          *time.Time             `gorm:"column:end_time;type:DATETIME" json:"endTime,omitempty"`
 	CreatedAt        time.Time              `gorm:"column:created_at;type:DATETIME;index" json:"createdAt"`
@@ -43,7 +43,16 @@ type TerminalOutputLine struct {
 	Line      int       `json:"line"`
 	Timestamp time.Time `json:"timestamp"`
 	Content   string    `json:"content"`
-	Stream    string    `json:"stream"` // stdout/stderr
+	Stream    string    `json:"stream"` // stdout/stderr/stdin
+}
+
+// TerminalTranscriptChunk 终端会话记录分块，会话进行中按块持久化
+type TerminalTranscriptChunk struct {
+	ID        uint64               `gorm:"column:id;primaryKey;autoIncrement" json:"-"`
+	SessionID string               `gorm:"column:session_id;type:VARCHAR(64);uniqueIndex:uk_session_seq,priority:1" json:"sessionId"`
+	Seq       int                  `gorm:"column:seq;type:INT;uniqueIndex:uk_session_seq,priority:2" json:"seq"` // 块序号，从 1 开始
+	Lines     []TerminalOutputLine `gorm:"column:lines;type:JSON;serializer:json" json:"lines"`
+	CreatedAt time.Time            `gorm:"column:created_at;type:DATETIME" json:"createdAt"`
 }
 
 // TerminalOutputMetadata 终端输出元数据
@@ -53,7 +62,21 @@ type TerminalOutputMetadata struct {
 	OutputSizeBytes int64 `json:"outputSizeBytes"`
 }
 
+// 终端会话类型与状态
+const (
+	TerminalSessionTypeDebug = "debug"
+
+	TerminalStatusRunning   = "running"
+	TerminalStatusCompleted = "completed"
+	TerminalStatusFailed    = "failed"
+)
+
 // TableName 返回表名称
 func (TerminalOutputRecord) TableName() string {
 	return "terminal_output_records"
 }
+
+// TableName 返回表名称
+func (TerminalTranscriptChunk) TableName() string {
+	return "terminal_transcript_chunks"
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package process
+
+import (
+	"context"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/shared/storage"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultCacheTTL evicts caches not restored or saved for a week.
+	DefaultCacheTTL = 7 * 24 * time.Hour
+	// DefaultCacheProjectQuota bounds the total cache size of a project.
+	DefaultCacheProjectQuota int64 = 10 << 30 // 10GB
+)
+
+// CacheCleaner evicts pipeline caches that have not been used within the TTL
+// and, per project, the least recently used caches beyond the size quota.
+// Entries are removed from both the database and object storage.
+type CacheCleaner struct {
+	db      *gorm.DB
+	storage storage.IStorage
+	ttl     time.Duration
+	quota   int64
+}
+
+// NewCacheCleaner creates a new cleaner. Non-positive ttl or quota fall back
+// to DefaultCacheTTL and DefaultCacheProjectQuota.
+func NewCacheCleaner(db *gorm.DB, st storage.IStorage, ttl time.Duration, quota int64) *CacheCleaner {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	if quota <= 0 {
+		quota = DefaultCacheProjectQuota
+	}
+	return &CacheCleaner{db: db, storage: st, ttl: ttl, quota: quota}
+}
+
+// CleanExpired evicts stale caches, then caches over the project quota.
+// Returns the number of caches evicted.
+func (cc *CacheCleaner) CleanExpired(ctx context.Context) int {
+	if cc.db == nil {
+		return 0
+	}
+
+	cleaned := cc.evictStale(ctx) + cc.evictOverQuota(ctx)
+	if cleaned > 0 {
+		log.Infow("pipeline caches evicted", "count", cleaned)
+	}
+	return cleaned
+}
+
+// evictStale removes caches whose last access is older than the TTL.
+func (cc *CacheCleaner) evictStale(ctx context.Context) int {
+	var caches []model.PipelineCache
+	if err := cc.db.WithContext(ctx).
+		Where("last_accessed_at IS NULL OR last_accessed_at < ?", time.Now().Add(-cc.ttl)).
+		Limit(500).
+		Find(&caches).Error; err != nil {
+		log.Warnw("failed to query stale caches", "error", err)
+		return 0
+	}
+	return cc.evict(ctx, caches)
+}
+
+// evictOverQuota removes the least recently used caches of every project
+// whose total cache size exceeds the quota.
+func (cc *CacheCleaner) evictOverQuota(ctx context.Context) int {
+	var projects []struct {
+		ProjectID string
+		Total     int64
+	}
+	if err := cc.db.WithContext(ctx).
+		Model(&model.PipelineCache{}).
+		Select("project_id, SUM(size) AS total").
+		Group("project_id").
+		Having("SUM(size) > ?", cc.quota).
+		Scan(&projects).Error; err != nil {
+		log.Warnw("failed to query cache usage", "error", err)
+		return 0
+	}
+
+	cleaned := 0
+	for _, p := range projects {
+		var caches []model.PipelineCache
+		if err := cc.db.WithContext(ctx).
+			Where("project_id = ?", p.ProjectID).
+			Order("last_accessed_at ASC").
+			Limit(500).
+			Find(&caches).Error; err != nil {
+			log.Warnw("failed to query project caches", "projectId", p.ProjectID, "error", err)
+			continue
+		}
+
+		excess := p.Total - cc.quota
+		n := 0
+		for n < len(caches) && excess > 0 {
+			excess -= caches[n].Size
+			n++
+		}
+		cleaned += cc.evict(ctx, caches[:n])
+	}
+	return cleaned
+}
+
+// evict deletes the given caches from object storage and the database.
+func (cc *CacheCleaner) evict(ctx context.Context, caches []model.PipelineCache) int {
+	cleaned := 0
+	for i := range caches {
+		c := &caches[i]
+
+		// Remove from object storage if available.
+		if cc.storage != nil && c.ObjectName != "" {
+			if err := cc.storage.Delete(ctx, c.ObjectName); err != nil {
+				log.Warnw("failed to delete cache from storage",
+					"cacheId", c.CacheID, "objectName", c.ObjectName, "error", err)
+			}
+		}
+
+		// Remove the DB record.
+		if err := cc.db.WithContext(ctx).
+			Where("cache_id = ?", c.CacheID).
+			Delete(&model.PipelineCache{}).Error; err != nil {
+			log.Warnw("failed to delete cache record", "cacheId", c.CacheID, "error", err)
+			continue
+		}
+		cleaned++
+	}
+	return cleaned
+}
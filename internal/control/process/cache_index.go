@@ -0,0 +1,78 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package process
+
+import (
+	"context"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/builtin"
+	"github.com/arcentrix/arcentra/pkg/id"
+)
+
+// CacheIndex adapts the pipeline cache repository into builtin.CacheIndex.
+type CacheIndex struct {
+	cacheRepo repo.IPipelineCacheRepository
+}
+
+// NewCacheIndex creates a cache index backed by the given repository.
+func NewCacheIndex(r repo.IPipelineCacheRepository) *CacheIndex {
+	return &CacheIndex{cacheRepo: r}
+}
+
+// Get returns the entry with the exact key, or nil when absent.
+func (c *CacheIndex) Get(ctx context.Context, projectID, branch, key string) (*builtin.CacheEntry, error) {
+	entry, err := c.cacheRepo.Get(ctx, projectID, branch, key)
+	return toCacheEntry(entry), err
+}
+
+// FindLatestByPrefix returns the newest entry whose key starts with prefix.
+func (c *CacheIndex) FindLatestByPrefix(ctx context.Context, projectID, branch, prefix string) (*builtin.CacheEntry, error) {
+	entry, err := c.cacheRepo.FindLatestByPrefix(ctx, projectID, branch, prefix)
+	return toCacheEntry(entry), err
+}
+
+// Save records a newly uploaded cache archive.
+func (c *CacheIndex) Save(ctx context.Context, projectID, branch string, entry *builtin.CacheEntry) error {
+	now := time.Now()
+	return c.cacheRepo.Upsert(ctx, &model.PipelineCache{
+		CacheID:        id.GetUild(),
+		ProjectID:      projectID,
+		Branch:         branch,
+		CacheKey:       entry.Key,
+		ObjectName:     entry.ObjectName,
+		Size:           entry.Size,
+		LastAccessedAt: &now,
+	})
+}
+
+// Touch records a cache hit.
+func (c *CacheIndex) Touch(ctx context.Context, cacheID string) error {
+	return c.cacheRepo.Touch(ctx, cacheID)
+}
+
+func toCacheEntry(entry *model.PipelineCache) *builtin.CacheEntry {
+	if entry == nil {
+		return nil
+	}
+	return &builtin.CacheEntry{
+		ID:         entry.CacheID,
+		Key:        entry.CacheKey,
+		ObjectName: entry.ObjectName,
+		Size:       entry.Size,
+	}
+}
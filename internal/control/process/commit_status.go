@@ -0,0 +1,102 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package process
+
+import (
+	"context"
+	"strings"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/service"
+	"github.com/arcentrix/arcentra/pkg/plugin"
+	"github.com/arcentrix/arcentra/pkg/scm"
+)
+
+// commitStatusPublisher turns the job CloudEvents of one run into commit
+// statuses. It is combined with the transport publisher through
+// executor.MultiPublisher.
+type commitStatusPublisher struct {
+	statuses *service.CommitStatusService
+	run      *model.PipelineRun
+}
+
+// newCommitStatusPublisher returns nil when no commit status service is
+// configured or the run has no commit to report to.
+func newCommitStatusPublisher(statuses *service.CommitStatusService, run *model.PipelineRun) *commitStatusPublisher {
+	if statuses == nil || run == nil || run.CommitSha == "" {
+		return nil
+	}
+	return &commitStatusPublisher{statuses: statuses, run: run}
+}
+
+// Publish reports job lifecycle events; other events are ignored.
+func (p *commitStatusPublisher) Publish(_ context.Context, event map[string]any) error {
+	eventType, _ := event["type"].(string)
+	subject, _ := event["subject"].(string)
+	jobName := jobNameFromSubject(subject)
+	if jobName == "" {
+		return nil
+	}
+	switch eventType {
+	case plugin.EventTypeJobStarted:
+		p.statuses.ReportJob(p.run, jobName, scm.CommitStatePending, "Job running")
+	case plugin.EventTypeJobCompleted:
+		if data, _ := event["data"].(map[string]any); data["status"] == "skipped" {
+			return nil
+		}
+		p.statuses.ReportJob(p.run, jobName, scm.CommitStateSuccess, "Job succeeded")
+	case plugin.EventTypeJobFailed:
+		p.statuses.ReportJob(p.run, jobName, scm.CommitStateFailure, "Job failed")
+	case plugin.EventTypeJobCancelled:
+		p.statuses.ReportJob(p.run, jobName, scm.CommitStateError, "Job cancelled")
+	}
+	return nil
+}
+
+// Close is a no-op; reports outlive the run.
+func (p *commitStatusPublisher) Close() error { return nil }
+
+// jobNameFromSubject extracts the job name from a job event subject such as
+// "pipeline:<namespace>:job:<name>". Step subjects yield "".
+func jobNameFromSubject(subject string) string {
+	name, ok := strings.CutPrefix(subject, "job:")
+	if !ok {
+		if _, after, found := strings.Cut(subject, ":job:"); found {
+			name, ok = after, true
+		}
+	}
+	if !ok || strings.Contains(name, ":step:") {
+		return ""
+	}
+	return name
+}
+
+// reportRunStatus reports the run-level commit status for a pipeline status.
+func (rc *Coordinator) reportRunStatus(status int) {
+	statuses := rc.engine.statuses
+	if statuses == nil {
+		return
+	}
+	switch status {
+	case model.PipelineStatusRunning:
+		statuses.ReportRun(rc.run, scm.CommitStatePending, "Pipeline running")
+	case model.PipelineStatusSuccess:
+		statuses.ReportRun(rc.run, scm.CommitStateSuccess, "Pipeline succeeded")
+	case model.PipelineStatusFailed:
+		statuses.ReportRun(rc.run, scm.CommitStateFailure, "Pipeline failed")
+	case model.PipelineStatusCancelled:
+		statuses.ReportRun(rc.run, scm.CommitStateError, "Pipeline cancelled")
+	}
+}
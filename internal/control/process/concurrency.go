@@ -0,0 +1,150 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/service"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/interceptor"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/arcentrix/arcentra/pkg/serde"
+)
+
+// acquireConcurrency waits until the run holds its pipeline-level
+// concurrency group. The returned release is a no-op when the pipeline has
+// no group.
+func (rc *Coordinator) acquireConcurrency(ctx context.Context) (func(), error) {
+	svc := rc.engine.concurrency
+	if svc == nil || strings.TrimSpace(rc.spec.GetConcurrency()) == "" {
+		return func() {}, nil
+	}
+	projectID := rc.projectID(ctx)
+	env := make(map[string]string, len(rc.spec.Variables)+4)
+	maps.Copy(env, rc.spec.Variables)
+	maps.Copy(env, serde.UnmarshalStringMap(rc.run.Env))
+	env["branch"] = rc.run.Branch
+	env["commit"] = rc.run.CommitSha
+	env["pipeline"] = rc.run.PipelineID
+	env["project"] = projectID
+
+	group, err := interceptor.NewVariableInterpreter(env).Resolve(rc.spec.Concurrency)
+	if err != nil {
+		return nil, fmt.Errorf("evaluate concurrency group: %w", err)
+	}
+	group = strings.TrimSpace(group)
+	if group == "" {
+		return func() {}, nil
+	}
+	return svc.Acquire(ctx, &model.ConcurrencyLease{
+		ProjectID: projectID,
+		GroupKey:  group,
+		RunID:     rc.run.RunID,
+		Policy:    rc.spec.ConcurrencyPolicy,
+	}, rc.supersede)
+}
+
+// supersede cancels a run that a newer run of its concurrency group took
+// over. The status is written here because the run context is gone by the
+// time Execute records it.
+func (rc *Coordinator) supersede() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := rc.engine.repos.Pipeline.UpdateRun(ctx, rc.run.RunID, map[string]any{
+		"status":   model.PipelineStatusCancelled,
+		"end_time": time.Now(),
+	}); err != nil {
+		log.Warnw("failed to mark superseded run cancelled", "runId", rc.run.RunID, "error", err)
+	}
+	rc.Cancel()
+	rc.engine.cancelRemoteJobRuns(rc.run.RunID, "")
+}
+
+// finishUnstarted records the terminal status of a run that ended before it
+// started executing: cancelled or superseded while waiting, or unable to
+// join its concurrency group. Runs interrupted by shutdown stay pending.
+func (rc *Coordinator) finishUnstarted(runErr error) {
+	if rc.engine.ctx.Err() != nil {
+		return
+	}
+	status := model.PipelineStatusFailed
+	action := "pipeline_run.failed"
+	if errors.Is(runErr, context.Canceled) || errors.Is(runErr, service.ErrSuperseded) {
+		status = model.PipelineStatusCancelled
+		action = "pipeline_run.cancelled"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := rc.engine.repos.Pipeline.UpdateRun(ctx, rc.run.RunID, map[string]any{
+		"status":   status,
+		"end_time": time.Now(),
+	}); err != nil {
+		log.Errorw("failed to update run terminal status", "runId", rc.run.RunID, "error", err)
+	}
+	if rc.engine.auditWriter != nil {
+		rc.engine.auditWriter.Write(ctx, PipelineAudit(action, "", rc.run.RunID, rc.run.PipelineID))
+	}
+	rc.updatePipelineStats(ctx, status)
+	rc.reportRunStatus(status)
+	rc.triggerDownstream(status)
+	if rc.notifier != nil {
+		rc.notifier.NotifyFinished(ctx, status, 0, runErr)
+	}
+}
+
+// projectID returns the project of the run's pipeline ("" if unknown).
+func (rc *Coordinator) projectID(ctx context.Context) string {
+	p, err := rc.engine.repos.Pipeline.Get(ctx, rc.run.PipelineID)
+	if err != nil || p == nil {
+		return ""
+	}
+	return p.ProjectID
+}
+
+// jobConcurrency enforces the job-level concurrency groups of one run. It
+// implements pipeline.IConcurrencyLocker.
+type jobConcurrency struct {
+	rc        *Coordinator
+	projectID string
+}
+
+// AcquireJob waits until job holds group. A superseded job is cancelled on
+// its agent in addition to onSuperseded cancelling it locally.
+func (j *jobConcurrency) AcquireJob(
+	ctx context.Context,
+	job, group, policy string,
+	onSuperseded func(),
+) (func(), error) {
+	release, err := j.rc.engine.concurrency.Acquire(ctx, &model.ConcurrencyLease{
+		ProjectID: j.projectID,
+		GroupKey:  group,
+		RunID:     j.rc.run.RunID,
+		JobName:   job,
+		Policy:    policy,
+	}, func() {
+		onSuperseded()
+		j.rc.engine.cancelRemoteJobRuns(j.rc.run.RunID, job)
+	})
+	if errors.Is(err, service.ErrSuperseded) {
+		return nil, fmt.Errorf("%w: %w", context.Canceled, err)
+	}
+	return release, err
+}
@@ -17,6 +17,7 @@ package process
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"sync"
@@ -27,6 +28,9 @@ import (
 	"github.com/arcentrix/arcentra/internal/shared/pipeline"
 	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
 	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/arcentrix/arcentra/pkg/logstream"
+	"github.com/arcentrix/arcentra/pkg/safe"
+	"github.com/arcentrix/arcentra/pkg/serde"
 )
 
 // Coordinator drives a single PipelineRun through its full lifecycle:
@@ -36,6 +40,19 @@ type Coordinator struct {
 	spec   *spec.Pipeline
 	engine *Process
 
+	// matrixGroups is the matrix expansion result of spec (nil if none).
+	matrixGroups map[string]*pipeline.MatrixGroup
+
+	// notifier delivers spec-level notification rules (nil if none).
+	notifier *RunNotifier
+
+	// rerun is the re-run plan when this run re-runs a previous one.
+	rerun *pipeline.RerunPlan
+
+	// masker holds the secrets of this run. It is registered under the run ID
+	// while the run executes so the log aggregator masks agent output with it.
+	masker *logstream.Masker
+
 	cancelFn context.CancelFunc
 	mu       sync.Mutex
 	paused   bool
@@ -45,10 +62,12 @@ type Coordinator struct {
 // NewCoordinator creates a coordinator for one pipeline run.
 func NewCoordinator(run *model.PipelineRun, s *spec.Pipeline, engine *Process) *Coordinator {
 	return &Coordinator{
-		run:     run,
-		spec:    s,
-		engine:  engine,
-		pauseCh: make(chan struct{}),
+		run:      run,
+		spec:     s,
+		engine:   engine,
+		notifier: NewRunNotifier(s, run, engine.notifySvc, engine.repos.Pipeline),
+		pauseCh:  make(chan struct{}),
+		masker:   logstream.NewMasker(),
 	}
 }
 
@@ -120,6 +139,10 @@ func (rc *Coordinator) Execute(ctx context.Context) error {
 	auditWriter := rc.engine.auditWriter
 	now := time.Now()
 
+	// The run's secrets leave the process with the run.
+	logstream.RegisterMasker(rc.run.RunID, rc.masker)
+	defer logstream.UnregisterMasker(rc.run.RunID)
+
 	// Audit: pipeline run started.
 	if auditWriter != nil {
 		auditWriter.Write(ctx, PipelineAudit("pipeline_run.started", "", rc.run.RunID, rc.run.PipelineID))
@@ -133,6 +156,10 @@ func (rc *Coordinator) Execute(ctx context.Context) error {
 	}); err != nil {
 		return fmt.Errorf("update run to running: %w", err)
 	}
+	rc.reportRunStatus(model.PipelineStatusRunning)
+	if rc.notifier != nil {
+		rc.notifier.NotifyStarted(ctx)
+	}
 
 	execErr := rc.executeDAG(ctx)
 
@@ -168,6 +195,12 @@ func (rc *Coordinator) Execute(ctx context.Context) error {
 	}
 
 	rc.updatePipelineStats(ctx, updates["status"].(int))
+	rc.reportRunStatus(updates["status"].(int))
+	rc.triggerDownstream(updates["status"].(int))
+
+	if rc.notifier != nil {
+		rc.notifier.NotifyFinished(ctx, updates["status"].(int), endTime.Sub(now), execErr)
+	}
 
 	// Workspace cleanup: remove the run working directory unless kept for debugging.
 	if !rc.engine.appConf.Pipeline.KeepWorkspace {
@@ -194,10 +227,19 @@ func (rc *Coordinator) executeDAG(ctx context.Context) error {
 	if rc.engine.taskQueue != nil {
 		execCtx.SetTaskQueue(rc.engine.taskQueue)
 	}
+	if rc.engine.jobQueue != nil {
+		execCtx.JobScheduler = rc.engine.jobQueue
+	}
 
+	project := rc.resolveProject(ctx, execCtx)
+	if rc.engine.concurrency != nil {
+		execCtx.ConcurrencyLocker = &jobConcurrency{rc: rc, projectID: execCtx.ProjectID}
+	}
 	rc.loadSecrets(ctx, execCtx)
+	rc.loadVariables(ctx, execCtx, project)
 	rc.setupEventEmitter(execCtx)
 	rc.setupLogPublisher(execCtx)
+	rc.setupCache(execCtx)
 
 	jobRunStore := NewJobRunStore(
 		rc.engine.repos.JobRun,
@@ -205,9 +247,18 @@ func (rc *Coordinator) executeDAG(ctx context.Context) error {
 	)
 	execCtx.JobRunStore = jobRunStore
 	execCtx.RunCoordinator = rc
+	if rc.notifier != nil {
+		execCtx.ApprovalNotifier = rc.notifier
+	}
 	execCtx.PipelineRunID = rc.run.RunID
 	execCtx.PipelineIDRef = rc.run.PipelineID
 	execCtx.ArtifactURIs = make(map[string]string)
+	execCtx.MatrixGroups = rc.matrixGroups
+	if rc.rerun != nil {
+		if err := rc.applyRerunPlan(ctx, execCtx); err != nil {
+			return fmt.Errorf("apply re-run plan: %w", err)
+		}
+	}
 
 	pipelineExec := pipeline.NewPipelineExecutorFromContext(execCtx, *rc.engine.logger)
 	return pipelineExec.Execute(ctx)
@@ -215,30 +266,36 @@ func (rc *Coordinator) executeDAG(ctx context.Context) error {
 
 // setupEventEmitter initialises the CloudEvents emitter so that
 // TaskFramework job/step events are actually published (to Kafka when
-// configured, otherwise silently dropped).
+// configured, otherwise silently dropped), forwarded to the project's
+// outgoing webhooks and reported as commit statuses.
 func (rc *Coordinator) setupEventEmitter(execCtx *pipeline.ExecutionContext) {
 	cfg := executor.EventEmitterConfig{
 		SourcePrefix:   "urn:arcentra:control",
 		PublishTimeout: 5 * time.Second,
 	}
 
+	// Fallback: log events instead of silently dropping them.
+	var publisher executor.EventPublisher = &executor.LogEventPublisher{}
 	kafkaCfg := rc.engine.appConf.MessageQueue.Kafka
-	if kafkaCfg.BootstrapServers == "" {
-		// Fallback: log events instead of silently dropping them.
-		execCtx.SetEventEmitter(executor.NewEventEmitter(&executor.LogEventPublisher{}, cfg))
-		return
-	}
-	publisher, err := executor.NewKafkaPublisher(
-		kafkaCfg.BootstrapServers,
-		"arcentra-control-events",
-		"EVENT_PIPELINE",
-	)
-	if err != nil {
-		log.Warnw("failed to create event publisher, falling back to log", "error", err)
-		execCtx.SetEventEmitter(executor.NewEventEmitter(&executor.LogEventPublisher{}, cfg))
-		return
+	if kafkaCfg.BootstrapServers != "" {
+		kafkaPublisher, err := executor.NewKafkaPublisher(
+			kafkaCfg.BootstrapServers,
+			"arcentra-control-events",
+			"EVENT_PIPELINE",
+		)
+		if err != nil {
+			log.Warnw("failed to create event publisher, falling back to log", "error", err)
+		} else {
+			publisher = kafkaPublisher
+		}
 	}
 
+	if hooks := newWebhookEventPublisher(rc.engine.webhookSvc, execCtx.ProjectID, rc.run); hooks != nil {
+		publisher = executor.NewMultiPublisher(publisher, hooks)
+	}
+	if statuses := newCommitStatusPublisher(rc.engine.statuses, rc.run); statuses != nil {
+		publisher = executor.NewMultiPublisher(publisher, statuses)
+	}
 	execCtx.SetEventEmitter(executor.NewEventEmitter(publisher, cfg))
 }
 
@@ -255,7 +312,36 @@ func (rc *Coordinator) setupLogPublisher(execCtx *pipeline.ExecutionContext) {
 		log.Warnw("failed to create log publisher for control-plane steps", "error", err)
 		return
 	}
-	execCtx.LogPublisher = pub
+	execCtx.LogPublisher = executor.NewMaskingLogPublisher(pub, rc.masker)
+}
+
+// resolveProject records the run's branch and project on the execution
+// context and returns the project (nil if it cannot be loaded).
+func (rc *Coordinator) resolveProject(ctx context.Context, execCtx *pipeline.ExecutionContext) *model.Project {
+	execCtx.Branch = rc.run.Branch
+	p, err := rc.engine.repos.Pipeline.Get(ctx, rc.run.PipelineID)
+	if err != nil || p == nil {
+		return nil
+	}
+	execCtx.ProjectID = p.ProjectID
+	if rc.engine.repos.Project == nil || p.ProjectID == "" {
+		return nil
+	}
+	project, err := rc.engine.repos.Project.Get(ctx, p.ProjectID)
+	if err != nil {
+		log.Warnw("failed to load project for pipeline run", "runId", rc.run.RunID, "projectId", p.ProjectID, "error", err)
+		return nil
+	}
+	return project
+}
+
+// setupCache scopes the cache builtin to the run's project and branch and
+// backs it with the configured object storage.
+func (rc *Coordinator) setupCache(execCtx *pipeline.ExecutionContext) {
+	if rc.engine.storage == nil || rc.engine.repos.PipelineCache == nil {
+		return
+	}
+	execCtx.BuiltinManager.SetCache(rc.engine.storage, NewCacheIndex(rc.engine.repos.PipelineCache))
 }
 
 // loadSecrets fetches project-scoped secrets and injects them into the
@@ -278,9 +364,47 @@ func (rc *Coordinator) loadSecrets(ctx context.Context, execCtx *pipeline.Execut
 			continue
 		}
 		execCtx.Env["secrets."+s.Name] = val
+		rc.addSecrets(execCtx, val)
 	}
 }
 
+// loadVariables merges team and project variables, the pipeline variables
+// and the trigger-time overrides into the run environment, see
+// mergeRunVariables for the precedence. Masked values are registered for
+// log masking.
+func (rc *Coordinator) loadVariables(ctx context.Context, execCtx *pipeline.ExecutionContext, project *model.Project) {
+	var (
+		teamVars    []*model.TeamVariable
+		projectVars []*model.ProjectVariable
+		err         error
+	)
+	if rc.engine.repos.Variable != nil && project != nil {
+		if teamVars, err = rc.engine.repos.Variable.ListInheritedTeamVariables(ctx, project.ProjectID); err != nil {
+			log.Warnw("failed to load team variables for pipeline run", "runId", rc.run.RunID, "error", err)
+		}
+		if projectVars, err = rc.engine.repos.Variable.ListProjectVariables(ctx, project.ProjectID); err != nil {
+			log.Warnw("failed to load project variables for pipeline run", "runId", rc.run.RunID, "error", err)
+		}
+	}
+
+	protected := protectedRun(project, rc.run)
+	var pipelineVars map[string]string
+	if rc.spec != nil {
+		pipelineVars = rc.spec.Variables
+	}
+	env, masked := mergeRunVariables(teamVars, projectVars, pipelineVars, serde.UnmarshalStringMap(rc.run.Env), protected)
+	maps.Copy(execCtx.Env, env)
+	rc.addSecrets(execCtx, masked...)
+}
+
+// addSecrets registers values with the mask set of the run, which the log
+// aggregator applies to the run's lines, and records them on the execution
+// context so they are sent to agents with each job.
+func (rc *Coordinator) addSecrets(execCtx *pipeline.ExecutionContext, values ...string) {
+	rc.masker.Add(values...)
+	execCtx.Secrets = append(execCtx.Secrets, values...)
+}
+
 // resolveWorkspace returns the workspace root for this pipeline run.
 func (rc *Coordinator) resolveWorkspace() string {
 	base := os.TempDir()
@@ -311,3 +435,19 @@ func (rc *Coordinator) updatePipelineStats(ctx context.Context, status int) {
 	}
 	_ = pipelineRepo.Update(ctx, rc.run.PipelineID, updates)
 }
+
+// triggerDownstream starts the pipelines triggered by the run finishing with
+// status. It runs in the background so downstream definitions are not loaded
+// on the run's critical path.
+func (rc *Coordinator) triggerDownstream(status int) {
+	downstream := rc.engine.downstream
+	if downstream == nil {
+		return
+	}
+	run := *rc.run
+	safe.Go(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+		downstream.OnRunFinished(ctx, &run, status)
+	})
+}
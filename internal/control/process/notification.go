@@ -0,0 +1,186 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package process
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"github.com/arcentrix/arcentra/internal/control/service"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
+	"github.com/arcentrix/arcentra/pkg/log"
+)
+
+const notifySendTimeout = 30 * time.Second
+
+// RunNotifier evaluates the spec-level notification rules of one pipeline
+// run and delivers matching lifecycle events through the notification
+// service. Channel names are resolved by the notify manager and message
+// bodies are rendered from notify templates by name.
+type RunNotifier struct {
+	rules        []*spec.NotificationRule
+	notification *service.NotificationService
+	pipelineRepo repo.IPipelineRepository
+	run          *model.PipelineRun
+	namespace    string
+}
+
+// NewRunNotifier creates a notifier for one run. Returns nil when the spec
+// has no notification rules or no notification service is configured.
+func NewRunNotifier(
+	s *spec.Pipeline,
+	run *model.PipelineRun,
+	notification *service.NotificationService,
+	pipelineRepo repo.IPipelineRepository,
+) *RunNotifier {
+	if s == nil || len(s.Notifications) == 0 || notification == nil {
+		return nil
+	}
+	return &RunNotifier{
+		rules:        s.Notifications,
+		notification: notification,
+		pipelineRepo: pipelineRepo,
+		run:          run,
+		namespace:    s.Namespace,
+	}
+}
+
+// NotifyStarted fires the started event.
+func (n *RunNotifier) NotifyStarted(ctx context.Context) {
+	n.dispatch(ctx, []string{spec.NotifyEventStarted}, nil)
+}
+
+// NotifyFinished fires the events for a run that reached a terminal status.
+// Succeeded/failed always fire; fixed and still_failing are derived from the
+// previous finished run of the same pipeline and branch. Cancelled runs do
+// not notify.
+func (n *RunNotifier) NotifyFinished(ctx context.Context, status int, duration time.Duration, runErr error) {
+	if status != model.PipelineStatusSuccess && status != model.PipelineStatusFailed {
+		return
+	}
+	events := finishedNotifyEvents(status, n.previousStatus(ctx))
+	extra := map[string]any{
+		"duration": duration.Round(time.Second).String(),
+	}
+	if runErr != nil {
+		extra["error"] = runErr.Error()
+	}
+	n.dispatch(ctx, events, extra)
+}
+
+// NotifyApprovalPending fires the approval_pending event. It implements
+// pipeline.IApprovalNotifier.
+func (n *RunNotifier) NotifyApprovalPending(ctx context.Context, jobName, approvalID string) {
+	n.dispatch(ctx, []string{spec.NotifyEventApprovalPending}, map[string]any{
+		"jobName":    jobName,
+		"approvalId": approvalID,
+	})
+}
+
+// finishedNotifyEvents returns the events fired for a finished run given the
+// status of the previous finished run (PipelineStatusUnknown when there is none).
+func finishedNotifyEvents(status, previousStatus int) []string {
+	switch status {
+	case model.PipelineStatusSuccess:
+		if previousStatus == model.PipelineStatusFailed {
+			return []string{spec.NotifyEventSucceeded, spec.NotifyEventFixed}
+		}
+		return []string{spec.NotifyEventSucceeded}
+	case model.PipelineStatusFailed:
+		if previousStatus == model.PipelineStatusFailed {
+			return []string{spec.NotifyEventFailed, spec.NotifyEventStillFailing}
+		}
+		return []string{spec.NotifyEventFailed}
+	}
+	return nil
+}
+
+// previousStatus returns the status of the previous finished run of the same
+// pipeline and branch, or PipelineStatusUnknown when there is none.
+func (n *RunNotifier) previousStatus(ctx context.Context) int {
+	if n.pipelineRepo == nil {
+		return model.PipelineStatusUnknown
+	}
+	prev, err := n.pipelineRepo.GetPreviousFinishedRun(ctx, n.run)
+	if err != nil {
+		log.Warnw("failed to load previous run for notifications", "runId", n.run.RunID, "error", err)
+		return model.PipelineStatusUnknown
+	}
+	if prev == nil {
+		return model.PipelineStatusUnknown
+	}
+	return prev.Status
+}
+
+// dispatch sends every rule that subscribes to one of the events. A rule
+// matching several events (e.g. failed and still_failing) is sent once, for
+// the most specific event.
+func (n *RunNotifier) dispatch(ctx context.Context, events []string, extra map[string]any) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notifySendTimeout)
+	defer cancel()
+
+	for _, rule := range n.rules {
+		event := matchRuleEvent(rule, events)
+		if event == "" {
+			continue
+		}
+		data := n.templateData(event, extra)
+
+		// approval_pending renders approval templates, other events build templates.
+		var err error
+		if event == spec.NotifyEventApprovalPending {
+			err = n.notification.BroadcastApprovalNotification(ctx, rule.Channels, rule.Template, data)
+		} else {
+			err = n.notification.BroadcastBuildNotification(ctx, rule.Channels, rule.Template, data)
+		}
+		if err != nil {
+			log.Warnw("failed to send pipeline notification",
+				"runId", n.run.RunID, "event", event, "template", rule.Template, "channels", rule.Channels, "error", err)
+		}
+	}
+}
+
+// matchRuleEvent returns the last event in events the rule subscribes to.
+// Events are ordered from generic to specific.
+func matchRuleEvent(rule *spec.NotificationRule, events []string) string {
+	matched := ""
+	for _, event := range events {
+		if slices.Contains(rule.Events, event) {
+			matched = event
+		}
+	}
+	return matched
+}
+
+// templateData builds the variables available to notify templates.
+func (n *RunNotifier) templateData(event string, extra map[string]any) map[string]any {
+	data := map[string]any{
+		"event":        event,
+		"namespace":    n.namespace,
+		"pipelineId":   n.run.PipelineID,
+		"pipelineName": n.run.PipelineName,
+		"runId":        n.run.RunID,
+		"branch":       n.run.Branch,
+		"commitSha":    n.run.CommitSha,
+		"triggeredBy":  n.run.TriggeredBy,
+	}
+	for k, v := range extra {
+		data[k] = v
+	}
+	return data
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package process
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
+)
+
+func TestFinishedNotifyEvents(t *testing.T) {
+	cases := []struct {
+		name             string
+		status, previous int
+		want             []string
+	}{
+		{"first success", model.PipelineStatusSuccess, model.PipelineStatusUnknown, []string{spec.NotifyEventSucceeded}},
+		{"success after success", model.PipelineStatusSuccess, model.PipelineStatusSuccess, []string{spec.NotifyEventSucceeded}},
+		{"fixed", model.PipelineStatusSuccess, model.PipelineStatusFailed, []string{spec.NotifyEventSucceeded, spec.NotifyEventFixed}},
+		{"first failure", model.PipelineStatusFailed, model.PipelineStatusUnknown, []string{spec.NotifyEventFailed}},
+		{"failure after success", model.PipelineStatusFailed, model.PipelineStatusSuccess, []string{spec.NotifyEventFailed}},
+		{"still failing", model.PipelineStatusFailed, model.PipelineStatusFailed, []string{spec.NotifyEventFailed, spec.NotifyEventStillFailing}},
+		{"cancelled", model.PipelineStatusCancelled, model.PipelineStatusFailed, nil},
+	}
+	for _, tc := range cases {
+		if got := finishedNotifyEvents(tc.status, tc.previous); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: events = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestMatchRuleEvent(t *testing.T) {
+	stillFailing := []string{spec.NotifyEventFailed, spec.NotifyEventStillFailing}
+	cases := []struct {
+		name   string
+		events []string
+		rule   []string
+		want   string
+	}{
+		{"generic only", stillFailing, []string{spec.NotifyEventFailed}, spec.NotifyEventFailed},
+		{"specific only", stillFailing, []string{spec.NotifyEventStillFailing}, spec.NotifyEventStillFailing},
+		{"both picks the specific", stillFailing, []string{spec.NotifyEventStillFailing, spec.NotifyEventFailed}, spec.NotifyEventStillFailing},
+		{"no match", stillFailing, []string{spec.NotifyEventSucceeded, spec.NotifyEventFixed}, ""},
+		{"empty rule", stillFailing, nil, ""},
+		{"approval", []string{spec.NotifyEventApprovalPending}, []string{spec.NotifyEventApprovalPending}, spec.NotifyEventApprovalPending},
+	}
+	for _, tc := range cases {
+		rule := &spec.NotificationRule{Events: tc.rule}
+		if got := matchRuleEvent(rule, tc.events); got != tc.want {
+			t.Errorf("%s: matchRuleEvent = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -17,18 +17,23 @@ package process
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/arcentrix/arcentra/internal/control/config"
 	"github.com/arcentrix/arcentra/internal/control/model"
 	"github.com/arcentrix/arcentra/internal/control/repo"
 	"github.com/arcentrix/arcentra/internal/control/service"
+	"github.com/arcentrix/arcentra/internal/shared/dsl"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline"
 	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/trigger"
 	"github.com/arcentrix/arcentra/internal/shared/storage"
 	"github.com/arcentrix/arcentra/pkg/log"
 	"github.com/arcentrix/arcentra/pkg/nova"
 	"github.com/arcentrix/arcentra/pkg/plugin"
 	"github.com/arcentrix/arcentra/pkg/safe"
+	"google.golang.org/protobuf/proto"
 )
 
 const defaultMaxConcurrentRuns = 10
@@ -44,6 +49,12 @@ type Process struct {
 	appConf     *config.AppConfig
 	secretSvc   *service.SecretService
 	auditWriter *AuditWriter
+	notifySvc   *service.NotificationService
+	webhookSvc  *service.WebhookService
+	jobQueue    *service.JobQueueService
+	concurrency *service.ConcurrencyService
+	statuses    *service.CommitStatusService
+	downstream  *trigger.PipelineTriggerManager
 
 	runs   sync.Map      // runID -> *Coordinator
 	sem    chan struct{} // concurrency limiter
@@ -84,9 +95,86 @@ func (e *Process) SetAuditWriter(aw *AuditWriter) {
 	e.auditWriter = aw
 }
 
+// SetNotificationService injects the service used to deliver spec-level
+// notification rules.
+func (e *Process) SetNotificationService(svc *service.NotificationService) {
+	e.notifySvc = svc
+}
+
+// SetWebhookService injects the service used to deliver run lifecycle
+// events to outgoing project webhooks.
+func (e *Process) SetWebhookService(svc *service.WebhookService) {
+	e.webhookSvc = svc
+}
+
+// SetCommitStatusService injects the service used to report run and job
+// state to the triggering commit.
+func (e *Process) SetCommitStatusService(svc *service.CommitStatusService) {
+	e.statuses = svc
+}
+
+// SetJobQueue injects the scheduler agent jobs are queued in. Without it
+// agent jobs go to the task queue directly.
+func (e *Process) SetJobQueue(q *service.JobQueueService) {
+	e.jobQueue = q
+}
+
+// SetConcurrencyService injects the service enforcing pipeline- and
+// job-level concurrency groups. Without it concurrency groups are ignored.
+func (e *Process) SetConcurrencyService(svc *service.ConcurrencyService) {
+	e.concurrency = svc
+}
+
+// SetPipelineTriggerManager injects the manager starting downstream pipelines
+// when a run finishes. Without it pipeline triggers are ignored.
+func (e *Process) SetPipelineTriggerManager(m *trigger.PipelineTriggerManager) {
+	e.downstream = m
+}
+
 // Submit asynchronously starts a pipeline run. It returns immediately;
 // the actual execution happens in a background goroutine.
 func (e *Process) Submit(run *model.PipelineRun, parsedSpec *spec.Pipeline) error {
+	return e.submit(run, parsedSpec, nil)
+}
+
+// PlanRerun computes which jobs a re-run reuses and which it schedules
+// again, without starting anything. succeeded maps the jobs that succeeded
+// in the source run to their JobRun IDs.
+func (e *Process) PlanRerun(
+	parsedSpec *spec.Pipeline,
+	succeeded map[string]string,
+	mode, fromJob string,
+) (reused, scheduled []string, err error) {
+	plan, err := planRerun(parsedSpec, succeeded, mode, fromJob)
+	if err != nil {
+		return nil, nil, err
+	}
+	for name := range plan.Reuse {
+		reused = append(reused, name)
+	}
+	sort.Strings(reused)
+	return reused, plan.Scheduled, nil
+}
+
+// SubmitRerun asynchronously starts a re-run in run.RerunMode. Reused jobs
+// take over the source JobRun results; only the remaining jobs are scheduled.
+func (e *Process) SubmitRerun(
+	run *model.PipelineRun,
+	parsedSpec *spec.Pipeline,
+	succeeded map[string]string,
+	fromJob string,
+) error {
+	if run == nil || parsedSpec == nil {
+		return fmt.Errorf("run and spec must not be nil")
+	}
+	plan, err := planRerun(parsedSpec, succeeded, run.RerunMode, fromJob)
+	if err != nil {
+		return fmt.Errorf("plan re-run: %w", err)
+	}
+	return e.submit(run, parsedSpec, plan)
+}
+
+func (e *Process) submit(run *model.PipelineRun, parsedSpec *spec.Pipeline, plan *pipeline.RerunPlan) error {
 	if run == nil || parsedSpec == nil {
 		return fmt.Errorf("run and spec must not be nil")
 	}
@@ -97,7 +185,18 @@ func (e *Process) Submit(run *model.PipelineRun, parsedSpec *spec.Pipeline) erro
 	default:
 	}
 
+	// Fan matrix jobs out into concrete jobs before the run is scheduled so
+	// that every cell gets its own JobRun and DAG node. Expansion rewrites
+	// the job list in place, so it works on a copy of the caller's spec.
+	parsedSpec = proto.Clone(parsedSpec).(*spec.Pipeline)
+	matrixGroups, err := dsl.ExpandMatrix(parsedSpec)
+	if err != nil {
+		return fmt.Errorf("expand matrix: %w", err)
+	}
+
 	rc := NewCoordinator(run, parsedSpec, e)
+	rc.matrixGroups = matrixGroups
+	rc.rerun = plan
 	e.runs.Store(run.RunID, rc)
 
 	e.wg.Add(1)
@@ -105,18 +204,28 @@ func (e *Process) Submit(run *model.PipelineRun, parsedSpec *spec.Pipeline) erro
 		defer e.wg.Done()
 		defer e.runs.Delete(run.RunID)
 
+		runCtx, runCancel := context.WithCancel(e.ctx)
+		rc.SetCancel(runCancel)
+		defer runCancel()
+
+		// Wait for the run's concurrency group before taking a run slot.
+		release, err := rc.acquireConcurrency(runCtx)
+		if err != nil {
+			log.Warnw("pipeline run did not start", "runId", run.RunID, "error", err)
+			rc.finishUnstarted(err)
+			return
+		}
+		defer release()
+
 		// Acquire semaphore
 		select {
 		case e.sem <- struct{}{}:
 			defer func() { <-e.sem }()
-		case <-e.ctx.Done():
+		case <-runCtx.Done():
+			rc.finishUnstarted(runCtx.Err())
 			return
 		}
 
-		runCtx, runCancel := context.WithCancel(e.ctx)
-		rc.SetCancel(runCancel)
-		defer runCancel()
-
 		if err := rc.Execute(runCtx); err != nil {
 			log.Errorw("pipeline run failed", "runId", run.RunID, "error", err)
 		}
@@ -136,14 +245,15 @@ func (e *Process) CancelRun(runID string) error {
 	rc.Cancel()
 
 	// Propagate cancellation to remote Agents for any running JobRuns.
-	e.cancelRemoteJobRuns(runID)
+	e.cancelRemoteJobRuns(runID, "")
 
 	return nil
 }
 
 // cancelRemoteJobRuns queries running JobRuns for a pipeline run and sends
-// CancelJobRun gRPC calls to the corresponding Agents.
-func (e *Process) cancelRemoteJobRuns(runID string) {
+// CancelJobRun gRPC calls to the corresponding Agents. A non-empty jobName
+// limits the cancellation to that job.
+func (e *Process) cancelRemoteJobRuns(runID, jobName string) {
 	if e.repos.JobRun == nil {
 		return
 	}
@@ -154,7 +264,7 @@ func (e *Process) cancelRemoteJobRuns(runID string) {
 		return
 	}
 	for _, jr := range jobRuns {
-		if !model.IsJobRunRunning(jr.Status) || jr.AgentID == "" {
+		if !model.IsJobRunRunning(jr.Status) || jr.AgentID == "" || (jobName != "" && jr.JobName != jobName) {
 			continue
 		}
 		agent, err := e.repos.Agent.Get(ctx, jr.AgentID)
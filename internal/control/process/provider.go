@@ -41,7 +41,16 @@ func ProvideEngine(
 	appConf *config.AppConfig,
 	services *service.Services,
 ) *Process {
-	return NewProcess(repos, pluginMgr, taskQueue, st, logger, appConf, services.Secret)
+	engine := NewProcess(repos, pluginMgr, taskQueue, st, logger, appConf, services.Secret)
+	engine.SetNotificationService(services.Notification)
+	engine.SetWebhookService(services.Webhook)
+	engine.SetConcurrencyService(services.Concurrency)
+	engine.SetCommitStatusService(services.CommitStatus)
+	if taskQueue != nil {
+		services.JobQueue.SetTaskQueue(taskQueue)
+		engine.SetJobQueue(services.JobQueue)
+	}
+	return engine
 }
 
 // ProvideTaskQueueProducer creates a Kafka-backed task queue for the control
@@ -0,0 +1,95 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package process
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/shared/dsl"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
+	"github.com/arcentrix/arcentra/pkg/id"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/bytedance/sonic"
+	"google.golang.org/protobuf/proto"
+)
+
+// planRerun plans a re-run on a matrix-expanded copy of the spec; Submit
+// expands the spec the same way, so job names match.
+func planRerun(
+	parsedSpec *spec.Pipeline,
+	succeeded map[string]string,
+	mode, fromJob string,
+) (*pipeline.RerunPlan, error) {
+	expanded := proto.Clone(parsedSpec).(*spec.Pipeline)
+	groups, err := dsl.ExpandMatrix(expanded)
+	if err != nil {
+		return nil, fmt.Errorf("expand matrix: %w", err)
+	}
+	return pipeline.PlanRerun(expanded.Jobs, groups, succeeded, mode, fromJob)
+}
+
+// applyRerunPlan copies the reused JobRuns of the source run into this run,
+// restores their outputs and artifact URIs for downstream jobs, and marks
+// reused and skipped jobs as settled so the DAG only schedules the rest.
+func (rc *Coordinator) applyRerunPlan(ctx context.Context, execCtx *pipeline.ExecutionContext) error {
+	plan := rc.rerun
+	settled := make(map[string]pipeline.TaskState, len(plan.Reuse)+len(plan.Skip))
+
+	for jobName, sourceID := range plan.Reuse {
+		source, err := rc.engine.repos.JobRun.GetByJobRunID(ctx, sourceID)
+		if err != nil {
+			return fmt.Errorf("load job run %s: %w", sourceID, err)
+		}
+
+		reused := *source
+		reused.BaseModel = model.BaseModel{}
+		reused.JobRunID = id.GetUild()
+		reused.PipelineRunID = rc.run.RunID
+		reused.ReusedFrom = source.JobRunID
+		if err := rc.engine.repos.JobRun.Create(ctx, &reused); err != nil {
+			return fmt.Errorf("copy job run %s: %w", sourceID, err)
+		}
+
+		if source.ArtifactURIs != "" {
+			var uris map[string]string
+			if err := sonic.UnmarshalString(source.ArtifactURIs, &uris); err != nil {
+				log.Warnw("failed to unmarshal reused artifact_uris", "jobRunId", sourceID, "error", err)
+			}
+			for key, uri := range uris {
+				execCtx.ArtifactURIs[jobName+"/"+key] = uri
+			}
+		}
+		if source.Outputs != "" {
+			var outputs map[string]string
+			if err := sonic.UnmarshalString(source.Outputs, &outputs); err != nil {
+				log.Warnw("failed to unmarshal reused outputs", "jobRunId", sourceID, "error", err)
+			}
+			execCtx.SetJobOutputs(jobName, outputs)
+		}
+		settled[jobName] = pipeline.TaskStateSucceeded
+	}
+	for _, jobName := range plan.Skip {
+		settled[jobName] = pipeline.TaskStateSkipped
+	}
+
+	execCtx.SettledTasks = settled
+	log.Infow("re-run plan applied",
+		"runId", rc.run.RunID, "sourceRunId", rc.run.RerunOfRunID,
+		"reused", len(plan.Reuse), "skipped", len(plan.Skip), "scheduled", len(plan.Scheduled))
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package process
+
+import (
+	"maps"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+)
+
+// protectedRun reports whether run is on a protected branch of project. Runs
+// of PR/MR changes never are, whatever their source branch is called.
+func protectedRun(project *model.Project, run *model.PipelineRun) bool {
+	if project == nil || run == nil || run.ChangeRequest == 1 {
+		return false
+	}
+	return project.IsProtectedBranch(run.Branch)
+}
+
+// mergeRunVariables builds the run environment from, lowest precedence first:
+//
+//  1. team variables of the teams with access to the project
+//  2. project variables
+//  3. pipeline `variables`
+//  4. trigger-time overrides
+//
+// Protected team and project variables are only included when the run is on
+// a protected branch. It returns the merged environment and the values of
+// the included variables that must be masked in logs.
+func mergeRunVariables(
+	teamVars []*model.TeamVariable,
+	projectVars []*model.ProjectVariable,
+	pipelineVars, overrides map[string]string,
+	protectedBranch bool,
+) (map[string]string, []string) {
+	env := make(map[string]string, len(teamVars)+len(projectVars)+len(pipelineVars)+len(overrides))
+	var masked []string
+
+	for _, v := range teamVars {
+		if v.Protected == 1 && !protectedBranch {
+			continue
+		}
+		env[v.Key] = v.Value
+		if v.Masked == 1 || v.Type == model.TeamVariableTypeSecret {
+			masked = append(masked, v.Value)
+		}
+	}
+	for _, v := range projectVars {
+		if v.Protected == 1 && !protectedBranch {
+			continue
+		}
+		env[v.Key] = v.Value
+		if v.Masked == 1 || v.Type == model.VariableTypeSecret {
+			masked = append(masked, v.Value)
+		}
+	}
+	maps.Copy(env, pipelineVars)
+	maps.Copy(env, overrides)
+	return env, masked
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package process
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"gorm.io/datatypes"
+)
+
+func TestMergeRunVariables(t *testing.T) {
+	teamVars := []*model.TeamVariable{
+		{Key: "REGION", Value: "team"},
+		{Key: "TEAM_TOKEN", Value: "team-secret", Type: model.TeamVariableTypeSecret},
+		{Key: "DEPLOY_KEY", Value: "team-deploy", Protected: 1, Masked: 1},
+	}
+	projectVars := []*model.ProjectVariable{
+		{Key: "REGION", Value: "project"},
+		{Key: "PROD_TOKEN", Value: "prod-secret", Protected: 1, Type: model.VariableTypeSecret},
+	}
+	pipelineVars := map[string]string{"REGION": "pipeline", "GOFLAGS": "-mod=mod"}
+	overrides := map[string]string{"GOFLAGS": "-race"}
+
+	env, masked := mergeRunVariables(teamVars, projectVars, pipelineVars, overrides, true)
+	want := map[string]string{
+		"REGION":     "pipeline",
+		"TEAM_TOKEN": "team-secret",
+		"DEPLOY_KEY": "team-deploy",
+		"PROD_TOKEN": "prod-secret",
+		"GOFLAGS":    "-race",
+	}
+	for k, v := range want {
+		if env[k] != v {
+			t.Errorf("env[%s] = %q, want %q", k, env[k], v)
+		}
+	}
+	slices.Sort(masked)
+	if !slices.Equal(masked, []string{"prod-secret", "team-deploy", "team-secret"}) {
+		t.Errorf("masked = %v", masked)
+	}
+
+	// Unprotected runs get neither the protected values nor their masks.
+	env, masked = mergeRunVariables(teamVars, projectVars, pipelineVars, overrides, false)
+	if _, ok := env["DEPLOY_KEY"]; ok {
+		t.Errorf("protected team variable leaked into an unprotected run")
+	}
+	if _, ok := env["PROD_TOKEN"]; ok {
+		t.Errorf("protected project variable leaked into an unprotected run")
+	}
+	if !slices.Equal(masked, []string{"team-secret"}) {
+		t.Errorf("masked = %v, want only the unprotected secret", masked)
+	}
+}
+
+func TestProtectedRun(t *testing.T) {
+	project := &model.Project{
+		DefaultBranch: "main",
+		Settings:      datatypes.JSON(`{"protected_branches":["release/*"]}`),
+	}
+	cases := []struct {
+		name string
+		run  *model.PipelineRun
+		want bool
+	}{
+		{"default branch push", &model.PipelineRun{Branch: "main"}, true},
+		{"protected pattern", &model.PipelineRun{Branch: "release/1.0"}, true},
+		{"feature branch", &model.PipelineRun{Branch: "feature/x"}, false},
+		{"PR from a branch named main", &model.PipelineRun{Branch: "main", ChangeRequest: 1}, false},
+		{"PR from a release branch", &model.PipelineRun{Branch: "release/1.0", ChangeRequest: 1}, false},
+	}
+	for _, tc := range cases {
+		if got := protectedRun(project, tc.run); got != tc.want {
+			t.Errorf("%s: protectedRun() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+	if protectedRun(nil, &model.PipelineRun{Branch: "main"}) {
+		t.Errorf("protectedRun() without project = true, want false")
+	}
+}
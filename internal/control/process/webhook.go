@@ -0,0 +1,93 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package process
+
+import (
+	"context"
+	"strings"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/service"
+)
+
+const cloudEventTypePrefix = "arcentra."
+
+// webhookEventPublisher forwards the pipeline, job, step and approval
+// CloudEvents of one run to the outgoing webhooks of its project. It is
+// combined with the transport publisher through executor.MultiPublisher.
+type webhookEventPublisher struct {
+	webhooks  *service.WebhookService
+	projectID string
+	run       *model.PipelineRun
+}
+
+// newWebhookEventPublisher returns nil when no webhook service is configured
+// or the run does not belong to a project.
+func newWebhookEventPublisher(
+	webhooks *service.WebhookService,
+	projectID string,
+	run *model.PipelineRun,
+) *webhookEventPublisher {
+	if webhooks == nil || projectID == "" {
+		return nil
+	}
+	return &webhookEventPublisher{webhooks: webhooks, projectID: projectID, run: run}
+}
+
+// Publish dispatches lifecycle events; other events (task logs, progress, ...)
+// are ignored.
+func (p *webhookEventPublisher) Publish(ctx context.Context, event map[string]any) error {
+	eventType, _ := event["type"].(string)
+	name := webhookEventName(eventType)
+	if name == "" {
+		return nil
+	}
+	data := map[string]any{
+		"pipelineId":   p.run.PipelineID,
+		"pipelineName": p.run.PipelineName,
+		"runId":        p.run.RunID,
+		"branch":       p.run.Branch,
+		"commitSha":    p.run.CommitSha,
+		"triggeredBy":  p.run.TriggeredBy,
+		"eventId":      event["id"],
+		"subject":      event["subject"],
+		"details":      event["data"],
+	}
+	p.webhooks.Dispatch(ctx, p.projectID, name, data)
+	return nil
+}
+
+// Close is a no-op; deliveries outlive the run.
+func (p *webhookEventPublisher) Close() error { return nil }
+
+// webhookEventName maps a CloudEvent type to the event name webhooks subscribe
+// to, e.g. "arcentra.job.failed" → "job.failed" and
+// "arcentra.pipeline.approval.requested" → "approval.requested". Returns ""
+// for events that are not delivered to webhooks.
+func webhookEventName(eventType string) string {
+	name, ok := strings.CutPrefix(eventType, cloudEventTypePrefix)
+	if !ok {
+		return ""
+	}
+	if approval, ok := strings.CutPrefix(name, "pipeline.approval."); ok {
+		return "approval." + approval
+	}
+	for _, resource := range []string{"pipeline.", "job.", "step."} {
+		if strings.HasPrefix(name, resource) {
+			return name
+		}
+	}
+	return ""
+}
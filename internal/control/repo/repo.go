@@ -46,6 +46,15 @@ type Repositories struct {
 	Approval             IApprovalRepository
 	PipelineTemplate     IPipelineTemplateRepository
 	RegistrationToken    IRegistrationTokenRepository
+	PipelineCache        IPipelineCacheRepository
+	Variable             IVariableRepository
+	Organization         IOrganizationRepository
+	OrganizationMember   IOrganizationMemberRepository
+	OrgInvitation        IOrganizationInvitationRepository
+	Webhook              IWebhookRepository
+	Terminal             ITerminalRepository
+	JobQueue             IJobQueueRepository
+	Concurrency          IConcurrencyRepository
 }
 
 // NewRepositories 初始化所有 repository
@@ -75,6 +84,15 @@ func NewRepositories(db database.IDatabase, ch cache.ICache) *Repositories {
 		Approval:             NewApprovalRepo(db),
 		PipelineTemplate:     NewPipelineTemplateRepo(db),
 		RegistrationToken:    NewRegistrationTokenRepo(db),
+		PipelineCache:        NewPipelineCacheRepo(db),
+		Variable:             NewVariableRepo(db),
+		Organization:         NewOrganizationRepo(db),
+		OrganizationMember:   NewOrganizationMemberRepo(db),
+		OrgInvitation:        NewOrganizationInvitationRepo(db),
+		Webhook:              NewWebhookRepo(db),
+		Terminal:             NewTerminalRepo(db),
+		JobQueue:             NewJobQueueRepo(db),
+		Concurrency:          NewConcurrencyRepo(db),
 	}
 }
 
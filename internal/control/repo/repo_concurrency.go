@@ -0,0 +1,117 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/pkg/database"
+	"gorm.io/gorm"
+)
+
+// IConcurrencyRepository defines concurrency group lease persistence with context support.
+type IConcurrencyRepository interface {
+	Create(ctx context.Context, lease *model.ConcurrencyLease) error
+	// Renew extends a lease and returns it as stored. Returns
+	// gorm.ErrRecordNotFound when the lease expired and was removed.
+	Renew(ctx context.Context, id uint64, until time.Time) (*model.ConcurrencyLease, error)
+	// Supersede flags the leases of a group older than beforeID so their
+	// holders cancel themselves. Returns the number of leases flagged.
+	Supersede(ctx context.Context, projectID, groupKey string, beforeID uint64) (int64, error)
+	// IsHead reports whether no unexpired lease of the group is older.
+	IsHead(ctx context.Context, lease *model.ConcurrencyLease, now time.Time) (bool, error)
+	MarkHeld(ctx context.Context, id uint64) error
+	Delete(ctx context.Context, id uint64) error
+	// DeleteExpired removes the leases whose holders stopped renewing them.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type ConcurrencyRepo struct {
+	database.IDatabase
+}
+
+// NewConcurrencyRepo creates a concurrency group lease repository.
+func NewConcurrencyRepo(db database.IDatabase) IConcurrencyRepository {
+	return &ConcurrencyRepo{IDatabase: db}
+}
+
+// Create adds a lease to the end of its group.
+func (r *ConcurrencyRepo) Create(ctx context.Context, lease *model.ConcurrencyLease) error {
+	if lease == nil {
+		return gorm.ErrInvalidData
+	}
+	return r.Database().WithContext(ctx).Create(lease).Error
+}
+
+// Renew extends a lease and returns it as stored.
+func (r *ConcurrencyRepo) Renew(ctx context.Context, id uint64, until time.Time) (*model.ConcurrencyLease, error) {
+	db := r.Database().WithContext(ctx)
+	res := db.Model(&model.ConcurrencyLease{}).
+		Where("id = ?", id).
+		Update("expires_at", until)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	var lease model.ConcurrencyLease
+	if err := db.Where("id = ?", id).First(&lease).Error; err != nil {
+		return nil, err
+	}
+	return &lease, nil
+}
+
+// Supersede flags the leases of a group older than beforeID.
+func (r *ConcurrencyRepo) Supersede(ctx context.Context, projectID, groupKey string, beforeID uint64) (int64, error) {
+	res := r.Database().WithContext(ctx).
+		Model(&model.ConcurrencyLease{}).
+		Where("project_id = ? AND group_key = ? AND id < ? AND superseded = ?", projectID, groupKey, beforeID, 0).
+		Update("superseded", 1)
+	return res.RowsAffected, res.Error
+}
+
+// IsHead reports whether no unexpired lease of the group is older.
+func (r *ConcurrencyRepo) IsHead(ctx context.Context, lease *model.ConcurrencyLease, now time.Time) (bool, error) {
+	var older int64
+	err := r.Database().WithContext(ctx).
+		Model(&model.ConcurrencyLease{}).
+		Where("project_id = ? AND group_key = ? AND id < ?", lease.ProjectID, lease.GroupKey, lease.ID).
+		Where("expires_at > ?", now).
+		Count(&older).Error
+	return older == 0, err
+}
+
+// MarkHeld records that a lease holds its group.
+func (r *ConcurrencyRepo) MarkHeld(ctx context.Context, id uint64) error {
+	return r.Database().WithContext(ctx).
+		Model(&model.ConcurrencyLease{}).
+		Where("id = ?", id).
+		Update("held", 1).Error
+}
+
+// Delete removes a lease, releasing its group.
+func (r *ConcurrencyRepo) Delete(ctx context.Context, id uint64) error {
+	return r.Database().WithContext(ctx).
+		Where("id = ?", id).
+		Delete(&model.ConcurrencyLease{}).Error
+}
+
+// DeleteExpired removes the leases whose holders stopped renewing them.
+func (r *ConcurrencyRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	res := r.Database().WithContext(ctx).
+		Where("expires_at <= ?", now).
+		Delete(&model.ConcurrencyLease{})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,279 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// jobRunTerminalStatuses are the job run statuses that release a queue slot.
+var jobRunTerminalStatuses = []int{
+	model.JobRunStatusSuccess,
+	model.JobRunStatusFailed,
+	model.JobRunStatusCancelled,
+	model.JobRunStatusTimeout,
+}
+
+// IJobQueueRepository defines job scheduling queue persistence with context support.
+type IJobQueueRepository interface {
+	Create(ctx context.Context, entry *model.JobQueueEntry) error
+	Get(ctx context.Context, jobRunID string) (*model.JobQueueEntry, error)
+	// ListWaiting lists the entries not yet dispatched. Payloads are only
+	// loaded when withPayload is set.
+	ListWaiting(ctx context.Context, withPayload bool) ([]*model.JobQueueEntry, error)
+	// ListDispatched lists the dispatched entries. Entries of finished jobs
+	// are included until Reap removes them.
+	ListDispatched(ctx context.Context) ([]*model.JobQueueEntry, error)
+	// MarkDispatched claims a waiting entry. It reports false when the entry
+	// was dispatched or removed meanwhile.
+	MarkDispatched(ctx context.Context, jobRunID string, at time.Time) (bool, error)
+	// ResetDispatched puts a dispatched entry back in the queue.
+	ResetDispatched(ctx context.Context, jobRunID string) error
+	// DeleteWaiting removes an entry that was not dispatched yet and reports
+	// whether it did.
+	DeleteWaiting(ctx context.Context, jobRunID string) (bool, error)
+	// Reap cancels the waiting jobs of finished pipeline runs and removes the
+	// entries of finished jobs. Returns the number of entries removed.
+	Reap(ctx context.Context) (int64, error)
+	// ListAgents lists the enabled online agents with their labels and
+	// concurrent job slots.
+	ListAgents(ctx context.Context) ([]*model.Agent, error)
+	// ClaimScheduler takes the scheduling lease for holder until the given
+	// time. It reports false while another holder's lease has not expired.
+	ClaimScheduler(ctx context.Context, holder string, until time.Time) (bool, error)
+	// ReleaseScheduler gives up the scheduling lease if holder has it.
+	ReleaseScheduler(ctx context.Context, holder string) error
+	// ProjectLimits returns the concurrency caps of the projects that have one.
+	ProjectLimits(ctx context.Context, projectIDs []string) (map[string]int, error)
+	// AverageJobDuration averages the run time of the jobs finished since.
+	AverageJobDuration(ctx context.Context, since time.Time) (time.Duration, error)
+}
+
+type JobQueueRepo struct {
+	database.IDatabase
+}
+
+// NewJobQueueRepo creates a job scheduling queue repository.
+func NewJobQueueRepo(db database.IDatabase) IJobQueueRepository {
+	return &JobQueueRepo{IDatabase: db}
+}
+
+// Create adds an entry to the queue.
+func (r *JobQueueRepo) Create(ctx context.Context, entry *model.JobQueueEntry) error {
+	if entry == nil {
+		return gorm.ErrInvalidData
+	}
+	return r.Database().WithContext(ctx).Create(entry).Error
+}
+
+// Get returns the entry of a job run without its payload.
+func (r *JobQueueRepo) Get(ctx context.Context, jobRunID string) (*model.JobQueueEntry, error) {
+	var entry model.JobQueueEntry
+	err := r.Database().WithContext(ctx).
+		Omit("payload").
+		Where("job_run_id = ?", jobRunID).
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ListWaiting lists the entries not yet dispatched.
+func (r *JobQueueRepo) ListWaiting(ctx context.Context, withPayload bool) ([]*model.JobQueueEntry, error) {
+	var entries []*model.JobQueueEntry
+	tx := r.Database().WithContext(ctx)
+	if !withPayload {
+		tx = tx.Omit("payload")
+	}
+	err := tx.Where("dispatched_at IS NULL").
+		Order("priority ASC, enqueued_at ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// ListDispatched lists the dispatched entries.
+func (r *JobQueueRepo) ListDispatched(ctx context.Context) ([]*model.JobQueueEntry, error) {
+	var entries []*model.JobQueueEntry
+	err := r.Database().WithContext(ctx).
+		Omit("payload").
+		Where("dispatched_at IS NOT NULL").
+		Find(&entries).Error
+	return entries, err
+}
+
+// MarkDispatched claims a waiting entry.
+func (r *JobQueueRepo) MarkDispatched(ctx context.Context, jobRunID string, at time.Time) (bool, error) {
+	res := r.Database().WithContext(ctx).
+		Model(&model.JobQueueEntry{}).
+		Where("job_run_id = ? AND dispatched_at IS NULL", jobRunID).
+		Update("dispatched_at", at)
+	return res.RowsAffected > 0, res.Error
+}
+
+// ResetDispatched puts a dispatched entry back in the queue.
+func (r *JobQueueRepo) ResetDispatched(ctx context.Context, jobRunID string) error {
+	return r.Database().WithContext(ctx).
+		Model(&model.JobQueueEntry{}).
+		Where("job_run_id = ?", jobRunID).
+		Update("dispatched_at", nil).Error
+}
+
+// DeleteWaiting removes an entry that was not dispatched yet.
+func (r *JobQueueRepo) DeleteWaiting(ctx context.Context, jobRunID string) (bool, error) {
+	res := r.Database().WithContext(ctx).
+		Where("job_run_id = ? AND dispatched_at IS NULL", jobRunID).
+		Delete(&model.JobQueueEntry{})
+	return res.RowsAffected > 0, res.Error
+}
+
+// Reap cancels the waiting jobs of finished pipeline runs and removes the
+// entries of finished jobs.
+func (r *JobQueueRepo) Reap(ctx context.Context) (int64, error) {
+	db := r.Database().WithContext(ctx)
+
+	var orphaned []string
+	err := db.Table("job_run_queue AS q").
+		Joins("JOIN pipeline_run AS pr ON pr.run_id = q.pipeline_run_id").
+		Where("q.dispatched_at IS NULL").
+		Where("pr.status IN ?", []int{
+			model.PipelineStatusSuccess,
+			model.PipelineStatusFailed,
+			model.PipelineStatusCancelled,
+		}).
+		Pluck("q.job_run_id", &orphaned).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(orphaned) > 0 {
+		err = db.Model(&model.JobRun{}).
+			Where("job_run_id IN ?", orphaned).
+			Where("status NOT IN ?", jobRunTerminalStatuses).
+			Updates(map[string]any{
+				"status":        model.JobRunStatusCancelled,
+				"error_message": "pipeline run finished before the job was scheduled",
+				"end_time":      time.Now(),
+			}).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	finished := db.Model(&model.JobRun{}).
+		Select("job_run_id").
+		Where("status IN ?", jobRunTerminalStatuses)
+	res := db.Where("job_run_id IN (?)", finished).Delete(&model.JobQueueEntry{})
+	return res.RowsAffected, res.Error
+}
+
+// ListAgents lists the enabled online agents with their labels and
+// concurrent job slots.
+func (r *JobQueueRepo) ListAgents(ctx context.Context) ([]*model.Agent, error) {
+	var agents []*model.Agent
+	err := r.Database().WithContext(ctx).
+		Select("agent_id", "labels", "max_concurrent_jobs").
+		Where("is_enabled = ?", 1).
+		Where("status IN ?", []int{1, 3, 4}). // online, busy, idle
+		Find(&agents).Error
+	return agents, err
+}
+
+// jobQueueLease is the name of the job scheduler's lease.
+const jobQueueLease = "job-queue"
+
+// ClaimScheduler takes the scheduling lease. The lease row is created on
+// first use; the conditional update then lets a single holder win.
+func (r *JobQueueRepo) ClaimScheduler(ctx context.Context, holder string, until time.Time) (bool, error) {
+	db := r.Database().WithContext(ctx)
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.SchedulerLease{Name: jobQueueLease, ExpiresAt: time.Unix(1, 0)}).Error
+	if err != nil {
+		return false, err
+	}
+	res := db.Model(&model.SchedulerLease{}).
+		Where("name = ?", jobQueueLease).
+		Where("holder = ? OR expires_at < ?", holder, time.Now()).
+		Updates(map[string]any{"holder": holder, "expires_at": until})
+	return res.RowsAffected > 0, res.Error
+}
+
+// ReleaseScheduler gives up the scheduling lease if holder has it.
+func (r *JobQueueRepo) ReleaseScheduler(ctx context.Context, holder string) error {
+	return r.Database().WithContext(ctx).
+		Model(&model.SchedulerLease{}).
+		Where("name = ? AND holder = ?", jobQueueLease, holder).
+		Update("expires_at", time.Unix(1, 0)).Error
+}
+
+// ProjectLimits returns the concurrency caps of the projects that have one.
+func (r *JobQueueRepo) ProjectLimits(ctx context.Context, projectIDs []string) (map[string]int, error) {
+	limits := make(map[string]int)
+	if len(projectIDs) == 0 {
+		return limits, nil
+	}
+	var projects []*model.Project
+	err := r.Database().WithContext(ctx).
+		Select("project_id", "settings").
+		Where("project_id IN ?", projectIDs).
+		Find(&projects).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		if limit := p.ConcurrencyLimit(); limit > 0 {
+			limits[p.ProjectID] = limit
+		}
+	}
+	return limits, nil
+}
+
+// AverageJobDuration averages the run time of the jobs finished since, over
+// the most recent ones.
+func (r *JobQueueRepo) AverageJobDuration(ctx context.Context, since time.Time) (time.Duration, error) {
+	var rows []struct {
+		StartTime *time.Time
+		EndTime   *time.Time
+	}
+	err := r.Database().WithContext(ctx).
+		Model(&model.JobRun{}).
+		Select("start_time", "end_time").
+		Where("status IN ?", []int{model.JobRunStatusSuccess, model.JobRunStatusFailed}).
+		Where("start_time IS NOT NULL AND end_time >= ?", since).
+		Order("end_time DESC").
+		Limit(200).
+		Scan(&rows).Error
+	if err != nil {
+		return 0, err
+	}
+	var total time.Duration
+	n := 0
+	for _, row := range rows {
+		if row.StartTime == nil || row.EndTime == nil || row.EndTime.Before(*row.StartTime) {
+			continue
+		}
+		total += row.EndTime.Sub(*row.StartTime)
+		n++
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	return total / time.Duration(n), nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repo
+
+import (
+	"context"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/pkg/database"
+)
+
+// IOrganizationRepository defines organization persistence with context support.
+type IOrganizationRepository interface {
+	Create(ctx context.Context, org *model.Organization) error
+	Get(ctx context.Context, orgID string) (*model.Organization, error)
+	Update(ctx context.Context, orgID string, updates map[string]interface{}) error
+	Delete(ctx context.Context, orgID string) error
+	List(ctx context.Context, query *model.OrganizationQueryReq) ([]*model.Organization, int64, error)
+	ListByUser(ctx context.Context, userID string) ([]*model.Organization, error)
+	NameExists(ctx context.Context, name string) (bool, error)
+	CountProjects(ctx context.Context, orgID string) (int64, error)
+	CountTeams(ctx context.Context, orgID string) (int64, error)
+	UpdateStatistics(ctx context.Context, orgID string) error
+}
+
+type OrganizationRepo struct {
+	database.IDatabase
+}
+
+func NewOrganizationRepo(db database.IDatabase) IOrganizationRepository {
+	return &OrganizationRepo{IDatabase: db}
+}
+
+// Create creates a new organization.
+func (r *OrganizationRepo) Create(ctx context.Context, org *model.Organization) error {
+	return r.Database().WithContext(ctx).Create(org).Error
+}
+
+// Get returns a non-deleted organization by orgID.
+func (r *OrganizationRepo) Get(ctx context.Context, orgID string) (*model.Organization, error) {
+	var org model.Organization
+	err := r.Database().WithContext(ctx).
+		Where("org_id = ? AND status != ?", orgID, model.OrgStatusDeleted).
+		First(&org).Error
+	if err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// Update updates organization by orgID.
+func (r *OrganizationRepo) Update(ctx context.Context, orgID string, updates map[string]interface{}) error {
+	return r.Database().WithContext(ctx).Model(&model.Organization{}).
+		Where("org_id = ?", orgID).
+		Updates(updates).Error
+}
+
+// Delete soft-deletes organization by orgID (sets status to deleted).
+func (r *OrganizationRepo) Delete(ctx context.Context, orgID string) error {
+	return r.Database().WithContext(ctx).Model(&model.Organization{}).
+		Where("org_id = ?", orgID).
+		Updates(map[string]interface{}{
+			"status":     model.OrgStatusDeleted,
+			"is_enabled": 0,
+		}).Error
+}
+
+// List lists non-deleted organizations with query filters.
+func (r *OrganizationRepo) List(ctx context.Context, query *model.OrganizationQueryReq) ([]*model.Organization, int64, error) {
+	var orgs []*model.Organization
+	var total int64
+
+	db := r.Database().WithContext(ctx).Model(&model.Organization{}).
+		Where("status != ?", model.OrgStatusDeleted)
+	if query.Name != "" {
+		db = db.Where("name LIKE ?", "%"+query.Name+"%")
+	}
+	if query.Plan != "" {
+		db = db.Where("plan = ?", query.Plan)
+	}
+	if query.Status != nil {
+		db = db.Where("status = ?", *query.Status)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if query.Page > 0 && query.PageSize > 0 {
+		db = db.Offset((query.Page - 1) * query.PageSize).Limit(query.PageSize)
+	} else {
+		db = db.Limit(100)
+	}
+
+	err := db.Order("id DESC").Find(&orgs).Error
+	return orgs, total, err
+}
+
+// ListByUser lists organizations the user is an active member of.
+func (r *OrganizationRepo) ListByUser(ctx context.Context, userID string) ([]*model.Organization, error) {
+	var orgs []*model.Organization
+	err := r.Database().WithContext(ctx).Table("organization o").
+		Select("o.*").
+		Joins("JOIN organization_member om ON o.org_id = om.org_id").
+		Where("om.user_id = ? AND om.status = ? AND o.status != ?", userID, model.OrgMemberStatusActive, model.OrgStatusDeleted).
+		Order("o.id DESC").
+		Find(&orgs).Error
+	return orgs, err
+}
+
+// NameExists checks if a non-deleted organization uses the name.
+func (r *OrganizationRepo) NameExists(ctx context.Context, name string) (bool, error) {
+	var count int64
+	err := r.Database().WithContext(ctx).Model(&model.Organization{}).
+		Where("name = ? AND status != ?", name, model.OrgStatusDeleted).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CountProjects counts the organization's projects that are not disabled.
+func (r *OrganizationRepo) CountProjects(ctx context.Context, orgID string) (int64, error) {
+	var count int64
+	err := r.Database().WithContext(ctx).Model(&model.Project{}).
+		Where("org_id = ? AND status != ?", orgID, model.ProjectStatusDisabled).
+		Count(&count).Error
+	return count, err
+}
+
+// CountTeams counts the organization's teams.
+func (r *OrganizationRepo) CountTeams(ctx context.Context, orgID string) (int64, error) {
+	var count int64
+	err := r.Database().WithContext(ctx).Model(&model.Team{}).
+		Where("org_id = ?", orgID).
+		Count(&count).Error
+	return count, err
+}
+
+// UpdateStatistics recounts organization members, teams and projects.
+func (r *OrganizationRepo) UpdateStatistics(ctx context.Context, orgID string) error {
+	var memberCount int64
+	if err := r.Database().WithContext(ctx).Model(&model.OrganizationMember{}).
+		Where("org_id = ? AND status = ?", orgID, model.OrgMemberStatusActive).
+		Count(&memberCount).Error; err != nil {
+		return err
+	}
+	teamCount, err := r.CountTeams(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	projectCount, err := r.CountProjects(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	return r.Database().WithContext(ctx).Model(&model.Organization{}).
+		Where("org_id = ?", orgID).
+		Updates(map[string]interface{}{
+			"total_members":  memberCount,
+			"total_teams":    teamCount,
+			"total_projects": projectCount,
+		}).Error
+}
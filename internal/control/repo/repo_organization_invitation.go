@@ -0,0 +1,127 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repo
+
+import (
+	"context"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/pkg/database"
+)
+
+// IOrganizationInvitationRepository defines organization invitation persistence.
+// Token holds the SHA-256 hash of the invitation token, never the token itself.
+type IOrganizationInvitationRepository interface {
+	Create(ctx context.Context, inv *model.OrganizationInvitation) error
+	Get(ctx context.Context, invitationID string) (*model.OrganizationInvitation, error)
+	GetByToken(ctx context.Context, tokenHash string) (*model.OrganizationInvitation, error)
+	ListByOrg(ctx context.Context, orgID string, status *int) ([]*model.OrganizationInvitation, error)
+	GetPendingByEmail(ctx context.Context, orgID, email string) (*model.OrganizationInvitation, error)
+	CountPending(ctx context.Context, orgID string) (int64, error)
+	UpdateStatus(ctx context.Context, invitationID string, status int) error
+	ExpirePending(ctx context.Context, now string) (int64, error)
+	Delete(ctx context.Context, invitationID string) error
+}
+
+type OrganizationInvitationRepo struct {
+	database.IDatabase
+}
+
+func NewOrganizationInvitationRepo(db database.IDatabase) IOrganizationInvitationRepository {
+	return &OrganizationInvitationRepo{IDatabase: db}
+}
+
+// Create creates an invitation.
+func (r *OrganizationInvitationRepo) Create(ctx context.Context, inv *model.OrganizationInvitation) error {
+	return r.Database().WithContext(ctx).Create(inv).Error
+}
+
+// Get returns an invitation by invitationID.
+func (r *OrganizationInvitationRepo) Get(ctx context.Context, invitationID string) (*model.OrganizationInvitation, error) {
+	var inv model.OrganizationInvitation
+	err := r.Database().WithContext(ctx).
+		Where("invitation_id = ?", invitationID).
+		First(&inv).Error
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// GetByToken returns an invitation by token hash.
+func (r *OrganizationInvitationRepo) GetByToken(ctx context.Context, tokenHash string) (*model.OrganizationInvitation, error) {
+	var inv model.OrganizationInvitation
+	err := r.Database().WithContext(ctx).
+		Where("token = ?", tokenHash).
+		First(&inv).Error
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// ListByOrg lists invitations of an organization, optionally filtered by status.
+func (r *OrganizationInvitationRepo) ListByOrg(ctx context.Context, orgID string, status *int) ([]*model.OrganizationInvitation, error) {
+	var invs []*model.OrganizationInvitation
+	db := r.Database().WithContext(ctx).Where("org_id = ?", orgID)
+	if status != nil {
+		db = db.Where("status = ?", *status)
+	}
+	err := db.Order("id DESC").Find(&invs).Error
+	return invs, err
+}
+
+// GetPendingByEmail returns the pending invitation of email in orgID.
+func (r *OrganizationInvitationRepo) GetPendingByEmail(ctx context.Context, orgID, email string) (*model.OrganizationInvitation, error) {
+	var inv model.OrganizationInvitation
+	err := r.Database().WithContext(ctx).
+		Where("org_id = ? AND email = ? AND status = ?", orgID, email, model.InvitationStatusPending).
+		First(&inv).Error
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// CountPending counts pending invitations of an organization.
+func (r *OrganizationInvitationRepo) CountPending(ctx context.Context, orgID string) (int64, error) {
+	var count int64
+	err := r.Database().WithContext(ctx).Model(&model.OrganizationInvitation{}).
+		Where("org_id = ? AND status = ?", orgID, model.InvitationStatusPending).
+		Count(&count).Error
+	return count, err
+}
+
+// UpdateStatus updates invitation status.
+func (r *OrganizationInvitationRepo) UpdateStatus(ctx context.Context, invitationID string, status int) error {
+	return r.Database().WithContext(ctx).Model(&model.OrganizationInvitation{}).
+		Where("invitation_id = ?", invitationID).
+		Update("status", status).Error
+}
+
+// ExpirePending marks pending invitations whose expires_at is before now as expired.
+func (r *OrganizationInvitationRepo) ExpirePending(ctx context.Context, now string) (int64, error) {
+	result := r.Database().WithContext(ctx).Model(&model.OrganizationInvitation{}).
+		Where("status = ? AND expires_at < ?", model.InvitationStatusPending, now).
+		Update("status", model.InvitationStatusExpired)
+	return result.RowsAffected, result.Error
+}
+
+// Delete deletes an invitation.
+func (r *OrganizationInvitationRepo) Delete(ctx context.Context, invitationID string) error {
+	return r.Database().WithContext(ctx).
+		Where("invitation_id = ?", invitationID).
+		Delete(&model.OrganizationInvitation{}).Error
+}
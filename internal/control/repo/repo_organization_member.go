@@ -0,0 +1,100 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repo
+
+import (
+	"context"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/pkg/database"
+)
+
+// IOrganizationMemberRepository defines organization member persistence.
+type IOrganizationMemberRepository interface {
+	Add(ctx context.Context, member *model.OrganizationMember) error
+	Get(ctx context.Context, orgID, userID string) (*model.OrganizationMember, error)
+	List(ctx context.Context, orgID string) ([]*model.OrganizationMember, error)
+	Update(ctx context.Context, orgID, userID string, updates map[string]interface{}) error
+	Remove(ctx context.Context, orgID, userID string) error
+	CountActive(ctx context.Context, orgID string) (int64, error)
+	CountByRole(ctx context.Context, orgID, role string) (int64, error)
+}
+
+type OrganizationMemberRepo struct {
+	database.IDatabase
+}
+
+func NewOrganizationMemberRepo(db database.IDatabase) IOrganizationMemberRepository {
+	return &OrganizationMemberRepo{IDatabase: db}
+}
+
+// Add adds a member to an organization.
+func (r *OrganizationMemberRepo) Add(ctx context.Context, member *model.OrganizationMember) error {
+	return r.Database().WithContext(ctx).Create(member).Error
+}
+
+// Get returns the membership of userID in orgID.
+func (r *OrganizationMemberRepo) Get(ctx context.Context, orgID, userID string) (*model.OrganizationMember, error) {
+	var member model.OrganizationMember
+	err := r.Database().WithContext(ctx).
+		Where("org_id = ? AND user_id = ?", orgID, userID).
+		First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// List lists the members of an organization.
+func (r *OrganizationMemberRepo) List(ctx context.Context, orgID string) ([]*model.OrganizationMember, error) {
+	var members []*model.OrganizationMember
+	err := r.Database().WithContext(ctx).
+		Where("org_id = ?", orgID).
+		Order("id ASC").
+		Find(&members).Error
+	return members, err
+}
+
+// Update updates the membership of userID in orgID.
+func (r *OrganizationMemberRepo) Update(ctx context.Context, orgID, userID string, updates map[string]interface{}) error {
+	return r.Database().WithContext(ctx).Model(&model.OrganizationMember{}).
+		Where("org_id = ? AND user_id = ?", orgID, userID).
+		Updates(updates).Error
+}
+
+// Remove removes userID from orgID.
+func (r *OrganizationMemberRepo) Remove(ctx context.Context, orgID, userID string) error {
+	return r.Database().WithContext(ctx).
+		Where("org_id = ? AND user_id = ?", orgID, userID).
+		Delete(&model.OrganizationMember{}).Error
+}
+
+// CountActive counts active members of an organization.
+func (r *OrganizationMemberRepo) CountActive(ctx context.Context, orgID string) (int64, error) {
+	var count int64
+	err := r.Database().WithContext(ctx).Model(&model.OrganizationMember{}).
+		Where("org_id = ? AND status = ?", orgID, model.OrgMemberStatusActive).
+		Count(&count).Error
+	return count, err
+}
+
+// CountByRole counts active members of an organization with the role.
+func (r *OrganizationMemberRepo) CountByRole(ctx context.Context, orgID, role string) (int64, error) {
+	var count int64
+	err := r.Database().WithContext(ctx).Model(&model.OrganizationMember{}).
+		Where("org_id = ? AND role_id = ? AND status = ?", orgID, role, model.OrgMemberStatusActive).
+		Count(&count).Error
+	return count, err
+}
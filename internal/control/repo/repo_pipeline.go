@@ -22,6 +22,7 @@ import (
 	"github.com/arcentrix/arcentra/internal/control/model"
 	"github.com/arcentrix/arcentra/pkg/database"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // PipelineQuery defines query parameters for listing pipelines.
@@ -35,15 +36,20 @@ type PipelineQuery struct {
 
 // PipelineRunQuery defines query parameters for listing pipeline runs.
 type PipelineRunQuery struct {
-	PipelineID string
-	Status     int
-	Page       int
-	PageSize   int
+	PipelineID    string
+	UpstreamRunID string
+	Status        int
+	Page          int
+	PageSize      int
 }
 
 // IPipelineRepository defines persistence methods for pipeline and pipeline run.
 type IPipelineRepository interface {
 	Create(ctx context.Context, pipeline *model.Pipeline) error
+	// CreateDiscovered creates a pipeline found by discovery unless the
+	// project already has one for its discovery path. It reports false and
+	// loads the existing pipeline into pipeline in that case.
+	CreateDiscovered(ctx context.Context, pipeline *model.Pipeline) (bool, error)
 	Update(ctx context.Context, pipelineID string, updates map[string]any) error
 	Get(ctx context.Context, pipelineID string) (*model.Pipeline, error)
 	List(ctx context.Context, query *PipelineQuery) ([]*model.Pipeline, int64, error)
@@ -51,7 +57,14 @@ type IPipelineRepository interface {
 	GetRun(ctx context.Context, runID string) (*model.PipelineRun, error)
 	UpdateRun(ctx context.Context, runID string, updates map[string]any) error
 	GetRunByRequestID(ctx context.Context, pipelineID, requestID string) (*model.PipelineRun, error)
+	GetPreviousFinishedRun(ctx context.Context, run *model.PipelineRun) (*model.PipelineRun, error)
 	ListRuns(ctx context.Context, query *PipelineRunQuery) ([]*model.PipelineRun, int64, error)
+	// ReplaceUpstreams replaces the pipeline triggers recorded for a
+	// downstream pipeline; an empty list removes them.
+	ReplaceUpstreams(ctx context.Context, pipelineID string, upstreams []*model.PipelineUpstream) error
+	// ListUpstreamsByProject lists the pipeline triggers listening to runs of
+	// the upstream project.
+	ListUpstreamsByProject(ctx context.Context, upstreamProjectID string) ([]*model.PipelineUpstream, error)
 }
 
 type PipelineRepo struct {
@@ -68,6 +81,30 @@ func (r *PipelineRepo) Create(ctx context.Context, pipeline *model.Pipeline) err
 	return r.Database().WithContext(ctx).Create(pipeline).Error
 }
 
+// CreateDiscovered creates a discovered pipeline, relying on the unique
+// (project_id, discovery_path) index to detect one created concurrently.
+func (r *PipelineRepo) CreateDiscovered(ctx context.Context, pipeline *model.Pipeline) (bool, error) {
+	if pipeline.DiscoveryPath == nil {
+		return false, gorm.ErrInvalidData
+	}
+	db := r.Database().WithContext(ctx)
+	res := db.Clauses(clause.OnConflict{DoNothing: true}).Create(pipeline)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected > 0 {
+		return true, nil
+	}
+	var existing model.Pipeline
+	err := db.Where("project_id = ? AND discovery_path = ?", pipeline.ProjectID, *pipeline.DiscoveryPath).
+		First(&existing).Error
+	if err != nil {
+		return false, err
+	}
+	*pipeline = existing
+	return false, nil
+}
+
 // Update updates a pipeline by pipelineID.
 func (r *PipelineRepo) Update(ctx context.Context, pipelineID string, updates map[string]any) error {
 	return r.Database().WithContext(ctx).
@@ -172,6 +209,29 @@ func (r *PipelineRepo) GetRunByRequestID(ctx context.Context, pipelineID, reques
 	return &one, nil
 }
 
+// GetPreviousFinishedRun gets the latest successful or failed run of the same
+// pipeline and branch that was created before the given run.
+// Returns (nil, nil) when not found.
+func (r *PipelineRepo) GetPreviousFinishedRun(ctx context.Context, run *model.PipelineRun) (*model.PipelineRun, error) {
+	if run == nil || strings.TrimSpace(run.PipelineID) == "" {
+		return nil, nil
+	}
+	tx := r.Database().WithContext(ctx).
+		Where("pipeline_id = ? AND branch = ? AND run_id <> ?", run.PipelineID, run.Branch, run.RunID).
+		Where("status IN ?", []int{model.PipelineStatusSuccess, model.PipelineStatusFailed})
+	if run.ID > 0 {
+		tx = tx.Where("id < ?", run.ID)
+	}
+	var one model.PipelineRun
+	if err := tx.Order("id DESC").First(&one).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &one, nil
+}
+
 // ListRuns lists pipeline runs.
 func (r *PipelineRepo) ListRuns(ctx context.Context, query *PipelineRunQuery) ([]*model.PipelineRun, int64, error) {
 	if query == nil {
@@ -191,6 +251,9 @@ func (r *PipelineRepo) ListRuns(ctx context.Context, query *PipelineRunQuery) ([
 	if query.PipelineID != "" {
 		tx = tx.Where("pipeline_id = ?", query.PipelineID)
 	}
+	if query.UpstreamRunID != "" {
+		tx = tx.Where("upstream_run_id = ?", query.UpstreamRunID)
+	}
 	if query.Status > 0 {
 		tx = tx.Where("status = ?", query.Status)
 	}
@@ -209,3 +272,28 @@ func (r *PipelineRepo) ListRuns(ctx context.Context, query *PipelineRunQuery) ([
 	}
 	return list, total, nil
 }
+
+// ReplaceUpstreams replaces the pipeline triggers recorded for a downstream
+// pipeline in one transaction.
+func (r *PipelineRepo) ReplaceUpstreams(ctx context.Context, pipelineID string, upstreams []*model.PipelineUpstream) error {
+	return r.Database().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("pipeline_id = ?", pipelineID).Delete(&model.PipelineUpstream{}).Error; err != nil {
+			return err
+		}
+		if len(upstreams) == 0 {
+			return nil
+		}
+		return tx.Create(upstreams).Error
+	})
+}
+
+// ListUpstreamsByProject lists the pipeline triggers listening to runs of
+// the upstream project.
+func (r *PipelineRepo) ListUpstreamsByProject(ctx context.Context, upstreamProjectID string) ([]*model.PipelineUpstream, error) {
+	var list []*model.PipelineUpstream
+	err := r.Database().WithContext(ctx).
+		Where("upstream_project_id = ?", upstreamProjectID).
+		Order("id ASC").
+		Find(&list).Error
+	return list, err
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/pkg/database"
+	"gorm.io/gorm"
+)
+
+// IPipelineCacheRepository defines persistence methods for pipeline cache entries.
+type IPipelineCacheRepository interface {
+	Get(ctx context.Context, projectID, branch, key string) (*model.PipelineCache, error)
+	FindLatestByPrefix(ctx context.Context, projectID, branch, prefix string) (*model.PipelineCache, error)
+	Upsert(ctx context.Context, entry *model.PipelineCache) error
+	Touch(ctx context.Context, cacheID string) error
+}
+
+// PipelineCacheRepo implements IPipelineCacheRepository using GORM.
+type PipelineCacheRepo struct {
+	database.IDatabase
+}
+
+// NewPipelineCacheRepo creates a new pipeline cache repository.
+func NewPipelineCacheRepo(db database.IDatabase) IPipelineCacheRepository {
+	return &PipelineCacheRepo{IDatabase: db}
+}
+
+// Get returns the entry with the exact key in the given scope, or nil when absent.
+func (r *PipelineCacheRepo) Get(ctx context.Context, projectID, branch, key string) (*model.PipelineCache, error) {
+	var entry model.PipelineCache
+	err := r.Database().WithContext(ctx).
+		Where("project_id = ? AND branch = ? AND cache_key = ?", projectID, branch, key).
+		First(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindLatestByPrefix returns the most recently saved entry whose key starts
+// with prefix in the given scope, or nil when absent.
+func (r *PipelineCacheRepo) FindLatestByPrefix(ctx context.Context, projectID, branch, prefix string) (*model.PipelineCache, error) {
+	var entry model.PipelineCache
+	err := r.Database().WithContext(ctx).
+		Where("project_id = ? AND branch = ? AND cache_key LIKE ? ESCAPE '!'", projectID, branch, escapeLike(prefix)+"%").
+		Order("updated_at DESC").
+		First(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Upsert creates the entry or, when the scope already holds the key, replaces
+// its object and size.
+func (r *PipelineCacheRepo) Upsert(ctx context.Context, entry *model.PipelineCache) error {
+	if entry == nil {
+		return gorm.ErrInvalidData
+	}
+	existing, err := r.Get(ctx, entry.ProjectID, entry.Branch, entry.CacheKey)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return r.Database().WithContext(ctx).Create(entry).Error
+	}
+	return r.Database().WithContext(ctx).
+		Model(&model.PipelineCache{}).
+		Where("cache_id = ?", existing.CacheID).
+		Updates(map[string]any{
+			"object_name":      entry.ObjectName,
+			"size":             entry.Size,
+			"last_accessed_at": entry.LastAccessedAt,
+			"updated_at":       time.Now(),
+		}).Error
+}
+
+// Touch records a cache hit so the entry survives least-recently-used eviction.
+func (r *PipelineCacheRepo) Touch(ctx context.Context, cacheID string) error {
+	return r.Database().WithContext(ctx).
+		Model(&model.PipelineCache{}).
+		Where("cache_id = ?", cacheID).
+		UpdateColumn("last_accessed_at", time.Now()).Error
+}
+
+// escapeLike escapes LIKE wildcards so that s matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`!`, `!!`, `%`, `!%`, `_`, `!_`).Replace(s)
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repo
+
+import (
+	"context"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/pkg/database"
+)
+
+// ITerminalRepository defines terminal session record persistence with context support.
+type ITerminalRepository interface {
+	Create(ctx context.Context, record *model.TerminalOutputRecord) error
+	Get(ctx context.Context, sessionID string) (*model.TerminalOutputRecord, error)
+	// Save overwrites the record. The transcript is stored in chunks.
+	Save(ctx context.Context, record *model.TerminalOutputRecord) error
+	// AppendTranscript stores the next chunk of a session transcript.
+	AppendTranscript(ctx context.Context, chunk *model.TerminalTranscriptChunk) error
+	// ListTranscript returns the transcript of a session in chunk order.
+	ListTranscript(ctx context.Context, sessionID string) ([]model.TerminalOutputLine, error)
+	// ListByStepRun lists the sessions of a step run without their transcripts.
+	ListByStepRun(ctx context.Context, stepRunID string) ([]*model.TerminalOutputRecord, error)
+}
+
+type TerminalRepo struct {
+	database.IDatabase
+}
+
+// NewTerminalRepo creates a terminal session record repository.
+func NewTerminalRepo(db database.IDatabase) ITerminalRepository {
+	return &TerminalRepo{IDatabase: db}
+}
+
+// Create creates a terminal session record.
+func (r *TerminalRepo) Create(ctx context.Context, record *model.TerminalOutputRecord) error {
+	return r.Database().WithContext(ctx).Create(record).Error
+}
+
+// Get returns a terminal session record by sessionID.
+func (r *TerminalRepo) Get(ctx context.Context, sessionID string) (*model.TerminalOutputRecord, error) {
+	var record model.TerminalOutputRecord
+	err := r.Database().WithContext(ctx).
+		Where("session_id = ?", sessionID).
+		First(&record).Error
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Save overwrites the record. The transcript is stored in chunks.
+func (r *TerminalRepo) Save(ctx context.Context, record *model.TerminalOutputRecord) error {
+	return r.Database().WithContext(ctx).Omit("logs").Save(record).Error
+}
+
+// AppendTranscript stores the next chunk of a session transcript.
+func (r *TerminalRepo) AppendTranscript(ctx context.Context, chunk *model.TerminalTranscriptChunk) error {
+	return r.Database().WithContext(ctx).Create(chunk).Error
+}
+
+// ListTranscript returns the transcript of a session in chunk order.
+func (r *TerminalRepo) ListTranscript(ctx context.Context, sessionID string) ([]model.TerminalOutputLine, error) {
+	var chunks []*model.TerminalTranscriptChunk
+	err := r.Database().WithContext(ctx).
+		Where("session_id = ?", sessionID).
+		Order("seq ASC").
+		Find(&chunks).Error
+	if err != nil {
+		return nil, err
+	}
+	var lines []model.TerminalOutputLine
+	for _, chunk := range chunks {
+		lines = append(lines, chunk.Lines...)
+	}
+	return lines, nil
+}
+
+// ListByStepRun lists the sessions of a step run without their transcripts.
+func (r *TerminalRepo) ListByStepRun(ctx context.Context, stepRunID string) ([]*model.TerminalOutputRecord, error) {
+	var records []*model.TerminalOutputRecord
+	err := r.Database().WithContext(ctx).
+		Omit("logs").
+		Where("step_run_id = ?", stepRunID).
+		Order("created_at DESC").
+		Find(&records).Error
+	return records, err
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repo
+
+import (
+	"context"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/pkg/database"
+)
+
+// IVariableRepository defines project and team variable persistence with context support.
+type IVariableRepository interface {
+	CreateProjectVariable(ctx context.Context, v *model.ProjectVariable) error
+	GetProjectVariable(ctx context.Context, projectID, variableID string) (*model.ProjectVariable, error)
+	ListProjectVariables(ctx context.Context, projectID string) ([]*model.ProjectVariable, error)
+	UpdateProjectVariable(ctx context.Context, projectID, variableID string, updates map[string]interface{}) error
+	DeleteProjectVariable(ctx context.Context, projectID, variableID string) error
+	ProjectVariableKeyExists(ctx context.Context, projectID, key string, excludeVariableID ...string) (bool, error)
+
+	CreateTeamVariable(ctx context.Context, v *model.TeamVariable) error
+	GetTeamVariable(ctx context.Context, teamID, variableID string) (*model.TeamVariable, error)
+	ListTeamVariables(ctx context.Context, teamID string) ([]*model.TeamVariable, error)
+	UpdateTeamVariable(ctx context.Context, teamID, variableID string, updates map[string]interface{}) error
+	DeleteTeamVariable(ctx context.Context, teamID, variableID string) error
+	TeamVariableKeyExists(ctx context.Context, teamID, key string, excludeVariableID ...string) (bool, error)
+
+	// ListInheritedTeamVariables lists the variables of every team with access
+	// to the project.
+	ListInheritedTeamVariables(ctx context.Context, projectID string) ([]*model.TeamVariable, error)
+}
+
+type VariableRepo struct {
+	database.IDatabase
+}
+
+// NewVariableRepo creates a variable repository.
+func NewVariableRepo(db database.IDatabase) IVariableRepository {
+	return &VariableRepo{IDatabase: db}
+}
+
+// CreateProjectVariable creates a project variable.
+func (r *VariableRepo) CreateProjectVariable(ctx context.Context, v *model.ProjectVariable) error {
+	return r.Database().WithContext(ctx).Create(v).Error
+}
+
+// GetProjectVariable returns a project variable by variableID.
+func (r *VariableRepo) GetProjectVariable(ctx context.Context, projectID, variableID string) (*model.ProjectVariable, error) {
+	var v model.ProjectVariable
+	err := r.Database().WithContext(ctx).
+		Where("project_id = ? AND variable_id = ?", projectID, variableID).
+		First(&v).Error
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// ListProjectVariables lists the variables of a project.
+func (r *VariableRepo) ListProjectVariables(ctx context.Context, projectID string) ([]*model.ProjectVariable, error) {
+	var vars []*model.ProjectVariable
+	err := r.Database().WithContext(ctx).
+		Where("project_id = ?", projectID).
+		Order("`key` ASC").
+		Find(&vars).Error
+	return vars, err
+}
+
+// UpdateProjectVariable updates a project variable.
+func (r *VariableRepo) UpdateProjectVariable(ctx context.Context, projectID, variableID string, updates map[string]interface{}) error {
+	return r.Database().WithContext(ctx).Model(&model.ProjectVariable{}).
+		Where("project_id = ? AND variable_id = ?", projectID, variableID).
+		Updates(updates).Error
+}
+
+// DeleteProjectVariable deletes a project variable.
+func (r *VariableRepo) DeleteProjectVariable(ctx context.Context, projectID, variableID string) error {
+	return r.Database().WithContext(ctx).
+		Where("project_id = ? AND variable_id = ?", projectID, variableID).
+		Delete(&model.ProjectVariable{}).Error
+}
+
+// ProjectVariableKeyExists checks if the key is used by another variable of the project.
+func (r *VariableRepo) ProjectVariableKeyExists(ctx context.Context, projectID, key string, excludeVariableID ...string) (bool, error) {
+	query := r.Database().WithContext(ctx).Model(&model.ProjectVariable{}).
+		Where("project_id = ? AND `key` = ?", projectID, key)
+	if len(excludeVariableID) > 0 && excludeVariableID[0] != "" {
+		query = query.Where("variable_id != ?", excludeVariableID[0])
+	}
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// CreateTeamVariable creates a team variable.
+func (r *VariableRepo) CreateTeamVariable(ctx context.Context, v *model.TeamVariable) error {
+	return r.Database().WithContext(ctx).Create(v).Error
+}
+
+// GetTeamVariable returns a team variable by variableID.
+func (r *VariableRepo) GetTeamVariable(ctx context.Context, teamID, variableID string) (*model.TeamVariable, error) {
+	var v model.TeamVariable
+	err := r.Database().WithContext(ctx).
+		Where("team_id = ? AND variable_id = ?", teamID, variableID).
+		First(&v).Error
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// ListTeamVariables lists the variables of a team.
+func (r *VariableRepo) ListTeamVariables(ctx context.Context, teamID string) ([]*model.TeamVariable, error) {
+	var vars []*model.TeamVariable
+	err := r.Database().WithContext(ctx).
+		Where("team_id = ?", teamID).
+		Order("`key` ASC").
+		Find(&vars).Error
+	return vars, err
+}
+
+// UpdateTeamVariable updates a team variable.
+func (r *VariableRepo) UpdateTeamVariable(ctx context.Context, teamID, variableID string, updates map[string]interface{}) error {
+	return r.Database().WithContext(ctx).Model(&model.TeamVariable{}).
+		Where("team_id = ? AND variable_id = ?", teamID, variableID).
+		Updates(updates).Error
+}
+
+// DeleteTeamVariable deletes a team variable.
+func (r *VariableRepo) DeleteTeamVariable(ctx context.Context, teamID, variableID string) error {
+	return r.Database().WithContext(ctx).
+		Where("team_id = ? AND variable_id = ?", teamID, variableID).
+		Delete(&model.TeamVariable{}).Error
+}
+
+// TeamVariableKeyExists checks if the key is used by another variable of the team.
+func (r *VariableRepo) TeamVariableKeyExists(ctx context.Context, teamID, key string, excludeVariableID ...string) (bool, error) {
+	query := r.Database().WithContext(ctx).Model(&model.TeamVariable{}).
+		Where("team_id = ? AND `key` = ?", teamID, key)
+	if len(excludeVariableID) > 0 && excludeVariableID[0] != "" {
+		query = query.Where("variable_id != ?", excludeVariableID[0])
+	}
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// ListInheritedTeamVariables lists the variables of the teams with access to
+// the project, oldest first so that the result is stable.
+func (r *VariableRepo) ListInheritedTeamVariables(ctx context.Context, projectID string) ([]*model.TeamVariable, error) {
+	var vars []*model.TeamVariable
+	teamIDs := r.Database().WithContext(ctx).Table(model.ProjectTeamAccess{}.TableName()).
+		Select("team_id").
+		Where("project_id = ?", projectID)
+	err := r.Database().WithContext(ctx).
+		Where("team_id IN (?)", teamIDs).
+		Order("id ASC").
+		Find(&vars).Error
+	return vars, err
+}
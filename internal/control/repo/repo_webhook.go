@@ -0,0 +1,184 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/pkg/database"
+)
+
+// IWebhookRepository defines project webhook and delivery persistence with context support.
+type IWebhookRepository interface {
+	Create(ctx context.Context, w *model.ProjectWebhook) error
+	Get(ctx context.Context, projectID, webhookID string) (*model.ProjectWebhook, error)
+	List(ctx context.Context, projectID string) ([]*model.ProjectWebhook, error)
+	// ListEnabled lists the enabled webhooks of a project.
+	ListEnabled(ctx context.Context, projectID string) ([]*model.ProjectWebhook, error)
+	Update(ctx context.Context, projectID, webhookID string, updates map[string]interface{}) error
+	Delete(ctx context.Context, projectID, webhookID string) error
+
+	CreateDelivery(ctx context.Context, d *model.ProjectWebhookDelivery) error
+	GetDelivery(ctx context.Context, webhookID, deliveryID string) (*model.ProjectWebhookDelivery, error)
+	ListDeliveries(ctx context.Context, webhookID string, pageNum, pageSize int) ([]*model.ProjectWebhookDelivery, int64, error)
+	UpdateDelivery(ctx context.Context, deliveryID string, updates map[string]interface{}) error
+	// ListPendingDeliveries lists deliveries that have not reached a final
+	// status and were not claimed after claimedBefore.
+	ListPendingDeliveries(ctx context.Context, claimedBefore time.Time, limit int) ([]*model.ProjectWebhookDelivery, error)
+	// ClaimDelivery atomically claims a pending delivery for sending. It fails
+	// (false) when the delivery is final or was claimed after claimedBefore.
+	ClaimDelivery(ctx context.Context, deliveryID string, claimedBefore time.Time) (bool, error)
+}
+
+type WebhookRepo struct {
+	database.IDatabase
+}
+
+// NewWebhookRepo creates a project webhook repository.
+func NewWebhookRepo(db database.IDatabase) IWebhookRepository {
+	return &WebhookRepo{IDatabase: db}
+}
+
+// Create creates a project webhook.
+func (r *WebhookRepo) Create(ctx context.Context, w *model.ProjectWebhook) error {
+	return r.Database().WithContext(ctx).Create(w).Error
+}
+
+// Get returns a project webhook by webhookID.
+func (r *WebhookRepo) Get(ctx context.Context, projectID, webhookID string) (*model.ProjectWebhook, error) {
+	var w model.ProjectWebhook
+	err := r.Database().WithContext(ctx).
+		Where("project_id = ? AND webhook_id = ?", projectID, webhookID).
+		First(&w).Error
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// List lists the webhooks of a project.
+func (r *WebhookRepo) List(ctx context.Context, projectID string) ([]*model.ProjectWebhook, error) {
+	var hooks []*model.ProjectWebhook
+	err := r.Database().WithContext(ctx).
+		Where("project_id = ?", projectID).
+		Order("id ASC").
+		Find(&hooks).Error
+	return hooks, err
+}
+
+// ListEnabled lists the enabled webhooks of a project.
+func (r *WebhookRepo) ListEnabled(ctx context.Context, projectID string) ([]*model.ProjectWebhook, error) {
+	var hooks []*model.ProjectWebhook
+	err := r.Database().WithContext(ctx).
+		Where("project_id = ? AND is_enabled = ?", projectID, 1).
+		Order("id ASC").
+		Find(&hooks).Error
+	return hooks, err
+}
+
+// Update updates a project webhook.
+func (r *WebhookRepo) Update(ctx context.Context, projectID, webhookID string, updates map[string]interface{}) error {
+	return r.Database().WithContext(ctx).
+		Model(&model.ProjectWebhook{}).
+		Where("project_id = ? AND webhook_id = ?", projectID, webhookID).
+		Updates(updates).Error
+}
+
+// Delete deletes a project webhook together with its delivery history.
+func (r *WebhookRepo) Delete(ctx context.Context, projectID, webhookID string) error {
+	db := r.Database().WithContext(ctx)
+	if err := db.Where("project_id = ? AND webhook_id = ?", projectID, webhookID).
+		Delete(&model.ProjectWebhook{}).Error; err != nil {
+		return err
+	}
+	return db.Where("webhook_id = ?", webhookID).Delete(&model.ProjectWebhookDelivery{}).Error
+}
+
+// CreateDelivery records a webhook delivery.
+func (r *WebhookRepo) CreateDelivery(ctx context.Context, d *model.ProjectWebhookDelivery) error {
+	return r.Database().WithContext(ctx).Create(d).Error
+}
+
+// GetDelivery returns a delivery of a webhook by deliveryID.
+func (r *WebhookRepo) GetDelivery(ctx context.Context, webhookID, deliveryID string) (*model.ProjectWebhookDelivery, error) {
+	var d model.ProjectWebhookDelivery
+	err := r.Database().WithContext(ctx).
+		Where("webhook_id = ? AND delivery_id = ?", webhookID, deliveryID).
+		First(&d).Error
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// ListDeliveries lists the deliveries of a webhook, newest first.
+func (r *WebhookRepo) ListDeliveries(
+	ctx context.Context,
+	webhookID string,
+	pageNum, pageSize int,
+) ([]*model.ProjectWebhookDelivery, int64, error) {
+	var deliveries []*model.ProjectWebhookDelivery
+	var total int64
+	query := r.Database().WithContext(ctx).
+		Model(&model.ProjectWebhookDelivery{}).
+		Where("webhook_id = ?", webhookID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (pageNum - 1) * pageSize
+	err := query.Order("id DESC").Offset(offset).Limit(pageSize).Find(&deliveries).Error
+	return deliveries, total, err
+}
+
+// UpdateDelivery updates a delivery by deliveryID.
+func (r *WebhookRepo) UpdateDelivery(ctx context.Context, deliveryID string, updates map[string]interface{}) error {
+	return r.Database().WithContext(ctx).
+		Model(&model.ProjectWebhookDelivery{}).
+		Where("delivery_id = ?", deliveryID).
+		Updates(updates).Error
+}
+
+// ListPendingDeliveries lists deliveries that have not reached a final status
+// and were not claimed after claimedBefore, oldest first.
+func (r *WebhookRepo) ListPendingDeliveries(
+	ctx context.Context,
+	claimedBefore time.Time,
+	limit int,
+) ([]*model.ProjectWebhookDelivery, error) {
+	var deliveries []*model.ProjectWebhookDelivery
+	err := r.Database().WithContext(ctx).
+		Where("status = ?", model.WebhookDeliveryPending).
+		Where("claimed_at IS NULL OR claimed_at < ?", claimedBefore).
+		Order("id ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// ClaimDelivery atomically claims a pending delivery for sending. It fails
+// (false) when the delivery is final or was claimed after claimedBefore.
+func (r *WebhookRepo) ClaimDelivery(ctx context.Context, deliveryID string, claimedBefore time.Time) (bool, error) {
+	res := r.Database().WithContext(ctx).
+		Model(&model.ProjectWebhookDelivery{}).
+		Where("delivery_id = ? AND status = ?", deliveryID, model.WebhookDeliveryPending).
+		Where("claimed_at IS NULL OR claimed_at < ?", claimedBefore).
+		Update("claimed_at", time.Now())
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
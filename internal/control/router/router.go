@@ -183,6 +183,9 @@ func (rt *Router) routerGroup(r fiber.Router) {
 	// registration tokens (dynamic agent registration)
 	rt.registrationTokenRouter(r, auth)
 
+	// organization
+	rt.organizationRouter(r, auth)
+
 	// team
 	rt.teamRouter(r, auth)
 
@@ -195,9 +198,22 @@ func (rt *Router) routerGroup(r fiber.Router) {
 	// project
 	rt.projectRouter(r, auth)
 
+	// project and team variables
+	rt.variableRouter(r, auth)
+
+	// outgoing project webhooks
+	rt.webhookRouter(r, auth)
+
 	// pipeline
 	rt.pipelineRouter(r, auth)
 
+	// debug terminal sessions
+	rt.terminalRouter(r, auth)
+
+	// step log search
+	rt.logSearchRouter(r, auth)
+	rt.jobQueueRouter(r, auth)
+
 	// secrets
 	rt.secretRouter(r, auth)
 
@@ -209,4 +225,7 @@ func (rt *Router) routerGroup(r fiber.Router) {
 
 	// pipeline templates
 	rt.pipelineTemplateRouter(r, auth)
+
+	// notification channels and templates
+	rt.notificationRouter(r, auth)
 }
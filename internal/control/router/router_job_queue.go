@@ -0,0 +1,64 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/arcentrix/arcentra/internal/control/service"
+	"github.com/arcentrix/arcentra/pkg/http"
+	"github.com/gofiber/fiber/v2"
+)
+
+// jobQueueRouter registers the agent job queue endpoints.
+func (rt *Router) jobQueueRouter(r fiber.Router, authMiddleware fiber.Handler) {
+	queue := r.Group("/queue")
+	{
+		queue.Get("/", authMiddleware, rt.listJobQueue)
+		queue.Get("/:jobRunId", authMiddleware, rt.getQueuedJob)
+	}
+}
+
+// jobQueueErr maps job queue errors to response codes
+func jobQueueErr(c *fiber.Ctx, err error) error {
+	if errors.Is(err, service.ErrJobNotQueued) {
+		return http.Err(c, http.NotFound.Code, err.Error())
+	}
+	return http.Err(c, http.Failed.Code, err.Error())
+}
+
+// listJobQueue returns the jobs waiting for an agent in dispatch order,
+// optionally limited to one project.
+func (rt *Router) listJobQueue(c *fiber.Ctx) error {
+	resp, err := rt.Services.JobQueue.List(c.Context(), strings.TrimSpace(c.Query("projectId")))
+	if err != nil {
+		return jobQueueErr(c, err)
+	}
+	return http.Detail(c, resp)
+}
+
+// getQueuedJob returns the queue position and estimated wait of a job run.
+func (rt *Router) getQueuedJob(c *fiber.Ctx) error {
+	jobRunID := strings.TrimSpace(c.Params("jobRunId"))
+	if jobRunID == "" {
+		return http.Err(c, http.BadRequest.Code, "jobRunId is required")
+	}
+	job, err := rt.Services.JobQueue.Position(c.Context(), jobRunID)
+	if err != nil {
+		return jobQueueErr(c, err)
+	}
+	return http.Detail(c, job)
+}
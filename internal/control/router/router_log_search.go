@@ -0,0 +1,76 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/service"
+	"github.com/arcentrix/arcentra/pkg/http"
+	"github.com/arcentrix/arcentra/pkg/logindex"
+	"github.com/gofiber/fiber/v2"
+)
+
+// logSearchRouter registers full-text search over step run logs.
+func (rt *Router) logSearchRouter(r fiber.Router, authMiddleware fiber.Handler) {
+	logs := r.Group("/logs")
+	{
+		logs.Get("/search", authMiddleware, rt.searchLogs)
+	}
+}
+
+// logSearchErr maps log search errors to response codes
+func logSearchErr(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, logindex.ErrEmptyQuery), errors.Is(err, logindex.ErrInvalidRegex),
+		errors.Is(err, service.ErrLogSearchScopeRequired):
+		return http.Err(c, http.BadRequest.Code, err.Error())
+	case errors.Is(err, service.ErrProjectPermissionDenied):
+		return http.Err(c, http.PermissionDenied.Code, err.Error())
+	case errors.Is(err, service.ErrLogSearchDisabled):
+		return http.Err(c, http.NotFound.Code, err.Error())
+	}
+	return http.Err(c, http.Failed.Code, err.Error())
+}
+
+func (rt *Router) searchLogs(c *fiber.Ctx) error {
+	userID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	req := &model.LogSearchReq{
+		Query:      c.Query("query"),
+		Regex:      c.Query("regex"),
+		ProjectID:  strings.TrimSpace(c.Query("projectId")),
+		PipelineID: strings.TrimSpace(c.Query("pipelineId")),
+		Branch:     strings.TrimSpace(c.Query("branch")),
+		Status:     c.QueryInt("status"),
+		StartTime:  int64(c.QueryInt("startTime")),
+		EndTime:    int64(c.QueryInt("endTime")),
+		Order:      strings.TrimSpace(c.Query("order")),
+		PageNum:    c.QueryInt("pageNum", 1),
+		PageSize:   c.QueryInt("pageSize"),
+	}
+	if req.StartTime > 0 && req.EndTime > 0 && req.StartTime > req.EndTime {
+		return http.Err(c, http.BadRequest.Code, "startTime must not be after endTime")
+	}
+	resp, err := rt.Services.LogSearch.Search(c.Context(), userID, req)
+	if err != nil {
+		return logSearchErr(c, err)
+	}
+	return http.Detail(c, resp)
+}
@@ -0,0 +1,316 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"strings"
+
+	"github.com/arcentrix/arcentra/internal/control/service"
+	"github.com/arcentrix/arcentra/internal/shared/notify/template"
+	"github.com/arcentrix/arcentra/pkg/http"
+	"github.com/gofiber/fiber/v2"
+)
+
+// notificationRouter registers notification channel and template routes.
+// Channels and templates are global, so changing or testing them is limited
+// to platform admins.
+func (rt *Router) notificationRouter(r fiber.Router, authMiddleware fiber.Handler) {
+	channels := r.Group("/notification-channels")
+	{
+		channels.Get("/types", authMiddleware, rt.listNotificationChannelTypes)
+		channels.Post("/reload", authMiddleware, rt.requireAdmin, rt.reloadNotificationChannels)
+		channels.Post("/test", authMiddleware, rt.requireAdmin, rt.testNotificationChannelConfig) // test an unsaved config
+		channels.Post("/", authMiddleware, rt.requireAdmin, rt.createNotificationChannel)
+		channels.Get("/", authMiddleware, rt.listNotificationChannels)
+		channels.Get("/:channelID", authMiddleware, rt.getNotificationChannel)
+		channels.Put("/:channelID", authMiddleware, rt.requireAdmin, rt.updateNotificationChannel)
+		channels.Delete("/:channelID", authMiddleware, rt.requireAdmin, rt.deleteNotificationChannel)
+		channels.Post("/:channelID/test", authMiddleware, rt.requireAdmin, rt.testNotificationChannel)
+	}
+
+	templates := r.Group("/notification-templates")
+	{
+		templates.Post("/", authMiddleware, rt.requireAdmin, rt.createNotificationTemplate)
+		templates.Get("/", authMiddleware, rt.listNotificationTemplates)
+		templates.Get("/:templateID", authMiddleware, rt.getNotificationTemplate)
+		templates.Put("/:templateID", authMiddleware, rt.requireAdmin, rt.updateNotificationTemplate)
+		templates.Delete("/:templateID", authMiddleware, rt.requireAdmin, rt.deleteNotificationTemplate)
+		templates.Post("/:templateID/render", authMiddleware, rt.renderNotificationTemplate)
+		templates.Post("/:templateID/test", authMiddleware, rt.requireAdmin, rt.testNotificationTemplate)
+	}
+}
+
+// requireAdmin only lets platform admins through
+func (rt *Router) requireAdmin(c *fiber.Ctx) error {
+	userID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	isAdmin, err := rt.Services.User.IsAdmin(c.Context(), userID)
+	if err != nil {
+		return http.Err(c, http.Failed.Code, err.Error())
+	}
+	if !isAdmin {
+		return http.Err(c, http.PermissionDenied.Code, http.PermissionDenied.Msg)
+	}
+	return c.Next()
+}
+
+// ---------------------------------------------------------------------------
+// Channel endpoints
+// ---------------------------------------------------------------------------
+
+func (rt *Router) listNotificationChannelTypes(c *fiber.Ctx) error {
+	return http.Detail(c, map[string]any{"types": rt.Services.NotificationChannel.ListChannelTypes()})
+}
+
+func (rt *Router) createNotificationChannel(c *fiber.Ctx) error {
+	var req service.NotificationChannelRequest
+	if err := c.BodyParser(&req); err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
+	}
+	ch, err := rt.Services.NotificationChannel.CreateChannel(c.Context(), &req)
+	if err != nil {
+		return http.Err(c, http.Failed.Code, err.Error())
+	}
+	return http.Detail(c, ch)
+}
+
+func (rt *Router) listNotificationChannels(c *fiber.Ctx) error {
+	includeInactive := c.QueryBool("includeInactive", false)
+	channels, err := rt.Services.NotificationChannel.ListChannels(c.Context(), includeInactive)
+	if err != nil {
+		return http.Err(c, http.Failed.Code, err.Error())
+	}
+	return http.Detail(c, map[string]any{
+		"list":  channels,
+		"total": len(channels),
+	})
+}
+
+func (rt *Router) getNotificationChannel(c *fiber.Ctx) error {
+	channelID := strings.TrimSpace(c.Params("channelID"))
+	if channelID == "" {
+		return http.Err(c, http.BadRequest.Code, "channel id is required")
+	}
+	ch, err := rt.Services.NotificationChannel.GetChannel(c.Context(), channelID)
+	if err != nil {
+		return http.Err(c, http.Failed.Code, err.Error())
+	}
+	return http.Detail(c, ch)
+}
+
+func (rt *Router) updateNotificationChannel(c *fiber.Ctx) error {
+	channelID := strings.TrimSpace(c.Params("channelID"))
+	if channelID == "" {
+		return http.Err(c, http.BadRequest.Code, "channel id is required")
+	}
+	var req service.NotificationChannelRequest
+	if err := c.BodyParser(&req); err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
+	}
+	ch, err := rt.Services.NotificationChannel.UpdateChannel(c.Context(), channelID, &req)
+	if err != nil {
+		return http.Err(c, http.Failed.Code, err.Error())
+	}
+	return http.Detail(c, ch)
+}
+
+func (rt *Router) deleteNotificationChannel(c *fiber.Ctx) error {
+	channelID := strings.TrimSpace(c.Params("channelID"))
+	if channelID == "" {
+		return http.Err(c, http.BadRequest.Code, "channel id is required")
+	}
+	if err := rt.Services.NotificationChannel.DeleteChannel(c.Context(), channelID); err != nil {
+		return http.Err(c, http.Failed.Code, err.Error())
+	}
+	return http.Operation(c)
+}
+
+func (rt *Router) testNotificationChannel(c *fiber.Ctx) error {
+	channelID := strings.TrimSpace(c.Params("channelID"))
+	if channelID == "" {
+		return http.Err(c, http.BadRequest.Code, "channel id is required")
+	}
+	var req struct {
+		Message string `json:"message"`
+	}
+	_ = c.BodyParser(&req)
+
+	if err := rt.Services.NotificationChannel.TestChannel(c.Context(), channelID, req.Message); err != nil {
+		return http.Err(c, http.Failed.Code, err.Error())
+	}
+	return http.Operation(c)
+}
+
+func (rt *Router) testNotificationChannelConfig(c *fiber.Ctx) error {
+	var req struct {
+		service.NotificationChannelRequest
+		Message string `json:"message"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
+	}
+	if err := rt.Services.NotificationChannel.TestChannelConfig(c.Context(), &req.NotificationChannelRequest, req.Message); err != nil {
+		return http.Err(c, http.Failed.Code, err.Error())
+	}
+	return http.Operation(c)
+}
+
+func (rt *Router) reloadNotificationChannels(c *fiber.Ctx) error {
+	channels, err := rt.Services.NotificationChannel.ReloadChannels(c.Context())
+	if err != nil {
+		return http.Err(c, http.Failed.Code, err.Error())
+	}
+	return http.Detail(c, map[string]any{"channels": channels})
+}
+
+// ---------------------------------------------------------------------------
+// Template endpoints
+// ---------------------------------------------------------------------------
+
+// notificationTemplateRequest is the request body for creating/updating a template
+type notificationTemplateRequest struct {
+	Name        string         `json:"name"`
+	Type        string         `json:"type"`
+	Channel     string         `json:"channel"`
+	Title       string         `json:"title"`
+	Content     string         `json:"content"`
+	Format      string         `json:"format"`
+	Metadata    map[string]any `json:"metadata"`
+	Description string         `json:"description"`
+}
+
+func (req *notificationTemplateRequest) toTemplate() *template.Template {
+	return &template.Template{
+		Name:        strings.TrimSpace(req.Name),
+		Type:        template.Type(strings.TrimSpace(req.Type)),
+		Channel:     strings.TrimSpace(req.Channel),
+		Title:       req.Title,
+		Content:     req.Content,
+		Format:      strings.TrimSpace(req.Format),
+		Metadata:    req.Metadata,
+		Description: req.Description,
+	}
+}
+
+func (rt *Router) createNotificationTemplate(c *fiber.Ctx) error {
+	var req notificationTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
+	}
+	t, err := rt.Services.NotificationTemplate.CreateTemplate(c.Context(), req.toTemplate())
+	if err != nil {
+		return http.Err(c, http.Failed.Code, err.Error())
+	}
+	return http.Detail(c, t)
+}
+
+func (rt *Router) listNotificationTemplates(c *fiber.Ctx) error {
+	page := maxIntWithOne(c.QueryInt("page", 1))
+	pageSize := maxIntWithOne(c.QueryInt("pageSize", 20))
+	filter := &template.Filter{
+		Type:    template.Type(strings.TrimSpace(c.Query("type"))),
+		Channel: strings.TrimSpace(c.Query("channel")),
+		Name:    strings.TrimSpace(c.Query("name")),
+		Limit:   pageSize,
+		Offset:  (page - 1) * pageSize,
+	}
+	templates, err := rt.Services.NotificationTemplate.ListTemplates(c.Context(), filter)
+	if err != nil {
+		return http.Err(c, http.Failed.Code, err.Error())
+	}
+	return http.Detail(c, map[string]any{
+		"list":     templates,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+func (rt *Router) getNotificationTemplate(c *fiber.Ctx) error {
+	templateID := strings.TrimSpace(c.Params("templateID"))
+	if templateID == "" {
+		return http.Err(c, http.BadRequest.Code, "template id is required")
+	}
+	t, err := rt.Services.NotificationTemplate.GetTemplate(c.Context(), templateID)
+	if err != nil {
+		return http.Err(c, http.Failed.Code, err.Error())
+	}
+	return http.Detail(c, t)
+}
+
+func (rt *Router) updateNotificationTemplate(c *fiber.Ctx) error {
+	templateID := strings.TrimSpace(c.Params("templateID"))
+	if templateID == "" {
+		return http.Err(c, http.BadRequest.Code, "template id is required")
+	}
+	var req notificationTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
+	}
+	t, err := rt.Services.NotificationTemplate.UpdateTemplate(c.Context(), templateID, req.toTemplate())
+	if err != nil {
+		return http.Err(c, http.Failed.Code, err.Error())
+	}
+	return http.Detail(c, t)
+}
+
+func (rt *Router) deleteNotificationTemplate(c *fiber.Ctx) error {
+	templateID := strings.TrimSpace(c.Params("templateID"))
+	if templateID == "" {
+		return http.Err(c, http.BadRequest.Code, "template id is required")
+	}
+	if err := rt.Services.NotificationTemplate.DeleteTemplate(c.Context(), templateID); err != nil {
+		return http.Err(c, http.Failed.Code, err.Error())
+	}
+	return http.Operation(c)
+}
+
+func (rt *Router) renderNotificationTemplate(c *fiber.Ctx) error {
+	templateID := strings.TrimSpace(c.Params("templateID"))
+	if templateID == "" {
+		return http.Err(c, http.BadRequest.Code, "template id is required")
+	}
+	var req struct {
+		Data map[string]any `json:"data"`
+	}
+	_ = c.BodyParser(&req)
+
+	content, err := rt.Services.NotificationTemplate.RenderTemplate(c.Context(), templateID, req.Data)
+	if err != nil {
+		return http.Err(c, http.Failed.Code, err.Error())
+	}
+	return http.Detail(c, map[string]any{"content": content})
+}
+
+func (rt *Router) testNotificationTemplate(c *fiber.Ctx) error {
+	templateID := strings.TrimSpace(c.Params("templateID"))
+	if templateID == "" {
+		return http.Err(c, http.BadRequest.Code, "template id is required")
+	}
+	var req struct {
+		Channel string         `json:"channel"`
+		Data    map[string]any `json:"data"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
+	}
+
+	content, err := rt.Services.NotificationTemplate.TestTemplate(c.Context(), templateID, strings.TrimSpace(req.Channel), req.Data)
+	if err != nil {
+		return http.Err(c, http.Failed.Code, err.Error())
+	}
+	return http.Detail(c, map[string]any{"content": content})
+}
@@ -0,0 +1,356 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/service"
+	"github.com/arcentrix/arcentra/pkg/auth"
+	"github.com/arcentrix/arcentra/pkg/http"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/gofiber/fiber/v2"
+)
+
+// organizationRouter registers organization, member and invitation routes
+func (rt *Router) organizationRouter(r fiber.Router, authMiddleware fiber.Handler) {
+	orgs := r.Group("/organizations")
+	{
+		orgs.Post("/", authMiddleware, rt.createOrganization)
+		orgs.Get("/", authMiddleware, rt.listOrganizations)
+		orgs.Get("/mine", authMiddleware, rt.listMyOrganizations)
+
+		// invitations addressed to the current user
+		orgs.Post("/invitations/accept", authMiddleware, rt.acceptOrganizationInvitation)
+		orgs.Post("/invitations/decline", authMiddleware, rt.declineOrganizationInvitation)
+
+		orgs.Get("/:orgID", authMiddleware, rt.getOrganization)
+		orgs.Put("/:orgID", authMiddleware, rt.updateOrganization)
+		orgs.Delete("/:orgID", authMiddleware, rt.deleteOrganization)
+
+		orgs.Get("/:orgID/members", authMiddleware, rt.listOrganizationMembers)
+		orgs.Post("/:orgID/members", authMiddleware, rt.addOrganizationMember)
+		orgs.Put("/:orgID/members/:userID", authMiddleware, rt.updateOrganizationMember)
+		orgs.Delete("/:orgID/members/:userID", authMiddleware, rt.removeOrganizationMember)
+
+		orgs.Get("/:orgID/invitations", authMiddleware, rt.listOrganizationInvitations)
+		orgs.Post("/:orgID/invitations", authMiddleware, rt.createOrganizationInvitation)
+		orgs.Delete("/:orgID/invitations/:invitationID", authMiddleware, rt.revokeOrganizationInvitation)
+	}
+}
+
+// organizationErr maps organization service errors to response codes
+func organizationErr(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrOrgPermissionDenied):
+		return http.Err(c, http.PermissionDenied.Code, err.Error())
+	case errors.Is(err, service.ErrOrganizationNotFound), errors.Is(err, service.ErrInvitationNotFound):
+		return http.Err(c, http.NotFound.Code, err.Error())
+	default:
+		return http.Err(c, http.Failed.Code, err.Error())
+	}
+}
+
+// currentUserID returns the user id from the authorization token
+func (rt *Router) currentUserID(c *fiber.Ctx) (string, error) {
+	claims, err := auth.ParseAuthorizationToken(c, rt.HTTP.Auth.SecretKey)
+	if err != nil {
+		log.Errorw("authentication failed", "error", err)
+		return "", err
+	}
+	return claims.UserID, nil
+}
+
+// orgIDParam returns the trimmed :orgID path parameter
+func orgIDParam(c *fiber.Ctx) string {
+	return strings.TrimSpace(c.Params("orgID"))
+}
+
+// ---------------------------------------------------------------------------
+// Organization endpoints
+// ---------------------------------------------------------------------------
+
+func (rt *Router) createOrganization(c *fiber.Ctx) error {
+	var req model.CreateOrganizationReq
+	if err := c.BodyParser(&req); err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
+	}
+	userID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	org, err := rt.Services.Organization.CreateOrganization(c.Context(), &req, userID)
+	if err != nil {
+		return organizationErr(c, err)
+	}
+	return http.Detail(c, org)
+}
+
+func (rt *Router) listOrganizations(c *fiber.Ctx) error {
+	var query model.OrganizationQueryReq
+	if err := c.QueryParser(&query); err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
+	}
+	result, err := rt.Services.Organization.ListOrganizations(c.Context(), &query)
+	if err != nil {
+		return organizationErr(c, err)
+	}
+	return http.Detail(c, result)
+}
+
+func (rt *Router) listMyOrganizations(c *fiber.Ctx) error {
+	userID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	orgs, err := rt.Services.Organization.ListUserOrganizations(c.Context(), userID)
+	if err != nil {
+		return organizationErr(c, err)
+	}
+	return http.Detail(c, map[string]any{
+		"list":  orgs,
+		"total": len(orgs),
+	})
+}
+
+func (rt *Router) getOrganization(c *fiber.Ctx) error {
+	orgID := orgIDParam(c)
+	if orgID == "" {
+		return http.Err(c, http.OrgIDIsEmpty.Code, http.OrgIDIsEmpty.Msg)
+	}
+	org, err := rt.Services.Organization.GetOrganization(c.Context(), orgID)
+	if err != nil {
+		return organizationErr(c, err)
+	}
+	return http.Detail(c, org)
+}
+
+func (rt *Router) updateOrganization(c *fiber.Ctx) error {
+	orgID := orgIDParam(c)
+	if orgID == "" {
+		return http.Err(c, http.OrgIDIsEmpty.Code, http.OrgIDIsEmpty.Msg)
+	}
+	var req model.UpdateOrganizationReq
+	if err := c.BodyParser(&req); err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
+	}
+	userID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	org, err := rt.Services.Organization.UpdateOrganization(c.Context(), orgID, userID, &req)
+	if err != nil {
+		return organizationErr(c, err)
+	}
+	return http.Detail(c, org)
+}
+
+func (rt *Router) deleteOrganization(c *fiber.Ctx) error {
+	orgID := orgIDParam(c)
+	if orgID == "" {
+		return http.Err(c, http.OrgIDIsEmpty.Code, http.OrgIDIsEmpty.Msg)
+	}
+	userID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	if err := rt.Services.Organization.DeleteOrganization(c.Context(), orgID, userID); err != nil {
+		return organizationErr(c, err)
+	}
+	return http.Operation(c)
+}
+
+// ---------------------------------------------------------------------------
+// Member endpoints
+// ---------------------------------------------------------------------------
+
+func (rt *Router) listOrganizationMembers(c *fiber.Ctx) error {
+	orgID := orgIDParam(c)
+	if orgID == "" {
+		return http.Err(c, http.OrgIDIsEmpty.Code, http.OrgIDIsEmpty.Msg)
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	members, err := rt.Services.Organization.ListMembers(c.Context(), orgID, operatorID)
+	if err != nil {
+		return organizationErr(c, err)
+	}
+	return http.Detail(c, map[string]any{
+		"list":  members,
+		"total": len(members),
+	})
+}
+
+func (rt *Router) addOrganizationMember(c *fiber.Ctx) error {
+	orgID := orgIDParam(c)
+	if orgID == "" {
+		return http.Err(c, http.OrgIDIsEmpty.Code, http.OrgIDIsEmpty.Msg)
+	}
+	var req struct {
+		UserID string `json:"userId" validate:"required"`
+		Role   string `json:"role"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.UserID == "" {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	member, err := rt.Services.Organization.AddMember(c.Context(), orgID, operatorID, req.UserID, req.Role)
+	if err != nil {
+		return organizationErr(c, err)
+	}
+	return http.Detail(c, member)
+}
+
+func (rt *Router) updateOrganizationMember(c *fiber.Ctx) error {
+	orgID := orgIDParam(c)
+	userID := strings.TrimSpace(c.Params("userID"))
+	if orgID == "" || userID == "" {
+		return http.Err(c, http.BadRequest.Code, "organization id and user id are required")
+	}
+	var req model.UpdateOrganizationMemberReq
+	if err := c.BodyParser(&req); err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	member, err := rt.Services.Organization.UpdateMember(c.Context(), orgID, operatorID, userID, &req)
+	if err != nil {
+		return organizationErr(c, err)
+	}
+	return http.Detail(c, member)
+}
+
+func (rt *Router) removeOrganizationMember(c *fiber.Ctx) error {
+	orgID := orgIDParam(c)
+	userID := strings.TrimSpace(c.Params("userID"))
+	if orgID == "" || userID == "" {
+		return http.Err(c, http.BadRequest.Code, "organization id and user id are required")
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	if err := rt.Services.Organization.RemoveMember(c.Context(), orgID, operatorID, userID); err != nil {
+		return organizationErr(c, err)
+	}
+	return http.Operation(c)
+}
+
+// ---------------------------------------------------------------------------
+// Invitation endpoints
+// ---------------------------------------------------------------------------
+
+func (rt *Router) listOrganizationInvitations(c *fiber.Ctx) error {
+	orgID := orgIDParam(c)
+	if orgID == "" {
+		return http.Err(c, http.OrgIDIsEmpty.Code, http.OrgIDIsEmpty.Msg)
+	}
+	var status *int
+	if raw := c.Query("status"); raw != "" {
+		v := c.QueryInt("status", -1)
+		if v < model.InvitationStatusPending || v > model.InvitationStatusExpired {
+			return http.Err(c, http.InvalidStatusParameter.Code, http.InvalidStatusParameter.Msg)
+		}
+		status = &v
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	invitations, err := rt.Services.Organization.ListInvitations(c.Context(), orgID, operatorID, status)
+	if err != nil {
+		return organizationErr(c, err)
+	}
+	return http.Detail(c, map[string]any{
+		"list":  invitations,
+		"total": len(invitations),
+	})
+}
+
+func (rt *Router) createOrganizationInvitation(c *fiber.Ctx) error {
+	orgID := orgIDParam(c)
+	if orgID == "" {
+		return http.Err(c, http.OrgIDIsEmpty.Code, http.OrgIDIsEmpty.Msg)
+	}
+	var req model.CreateOrganizationInvitationReq
+	if err := c.BodyParser(&req); err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	invitation, err := rt.Services.Organization.CreateInvitation(c.Context(), orgID, operatorID, &req)
+	if err != nil {
+		return organizationErr(c, err)
+	}
+	return http.Detail(c, invitation)
+}
+
+func (rt *Router) revokeOrganizationInvitation(c *fiber.Ctx) error {
+	orgID := orgIDParam(c)
+	invitationID := strings.TrimSpace(c.Params("invitationID"))
+	if orgID == "" || invitationID == "" {
+		return http.Err(c, http.BadRequest.Code, "organization id and invitation id are required")
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	if err := rt.Services.Organization.RevokeInvitation(c.Context(), orgID, operatorID, invitationID); err != nil {
+		return organizationErr(c, err)
+	}
+	return http.Operation(c)
+}
+
+func (rt *Router) acceptOrganizationInvitation(c *fiber.Ctx) error {
+	var req model.AcceptOrganizationInvitationReq
+	if err := c.BodyParser(&req); err != nil || req.Token == "" {
+		return http.Err(c, http.TokenBeEmpty.Code, http.TokenBeEmpty.Msg)
+	}
+	userID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	org, err := rt.Services.Organization.AcceptInvitation(c.Context(), req.Token, userID)
+	if err != nil {
+		return organizationErr(c, err)
+	}
+	return http.Detail(c, org)
+}
+
+func (rt *Router) declineOrganizationInvitation(c *fiber.Ctx) error {
+	var req model.AcceptOrganizationInvitationReq
+	if err := c.BodyParser(&req); err != nil || req.Token == "" {
+		return http.Err(c, http.TokenBeEmpty.Code, http.TokenBeEmpty.Msg)
+	}
+	userID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	if err := rt.Services.Organization.DeclineInvitation(c.Context(), req.Token, userID); err != nil {
+		return organizationErr(c, err)
+	}
+	return http.Operation(c)
+}
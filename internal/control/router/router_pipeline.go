@@ -15,14 +15,18 @@
 package router
 
 import (
+	"encoding/json"
+	"fmt"
 	"strings"
 
 	pipelinev1 "github.com/arcentrix/arcentra/api/pipeline/v1"
+	steprunv1 "github.com/arcentrix/arcentra/api/steprun/v1"
 	"github.com/arcentrix/arcentra/internal/control/service"
 	"github.com/arcentrix/arcentra/pkg/auth"
 	"github.com/arcentrix/arcentra/pkg/http"
 	"github.com/arcentrix/arcentra/pkg/log"
 	"github.com/gofiber/fiber/v2"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 func (rt *Router) pipelineRouter(r fiber.Router, authMiddleware fiber.Handler) {
@@ -41,7 +45,9 @@ func (rt *Router) pipelineRouter(r fiber.Router, authMiddleware fiber.Handler) {
 		pipeline.Post("/:pipelineID/trigger", authMiddleware, rt.triggerPipeline)
 		pipeline.Get("/:pipelineID/runs", authMiddleware, rt.listPipelineRuns)
 		pipeline.Get("/runs/:runID", authMiddleware, rt.getPipelineRun)
+		pipeline.Get("/runs/:runID/steps", authMiddleware, rt.listPipelineRunSteps)
 
+		pipeline.Post("/:pipelineID/runs/:runID/rerun", authMiddleware, rt.rerunPipeline)
 		pipeline.Post("/:pipelineID/runs/:runID/stop", authMiddleware, rt.stopPipeline)
 		pipeline.Post("/:pipelineID/runs/:runID/pause", authMiddleware, rt.pausePipeline)
 		pipeline.Post("/:pipelineID/runs/:runID/resume", authMiddleware, rt.resumePipeline)
@@ -218,15 +224,19 @@ func (rt *Router) validatePipelineSpec(c *fiber.Ctx) error {
 		return http.Err(c, http.BadRequest.Code, "pipeline id is required")
 	}
 	var req struct {
-		Spec   *pipelinev1.Spec `json:"spec"`
-		Format string           `json:"format"`
+		Spec   json.RawMessage `json:"spec"`
+		Format string          `json:"format"`
 	}
 	if err := c.BodyParser(&req); err != nil {
 		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
 	}
+	specBody, err := decodeSpecBody(req.Spec)
+	if err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, err.Error())
+	}
 	resp, err := rt.pipelineService().ValidatePipelineSpec(c.Context(), &pipelinev1.ValidatePipelineSpecRequest{
 		PipelineId: pipelineID,
-		Spec:       req.Spec,
+		Spec:       specBody,
 		Format:     parseSpecFormat(req.Format),
 	})
 	if err != nil {
@@ -247,23 +257,27 @@ func (rt *Router) savePipelineSpec(c *fiber.Ctx) error {
 		return http.Err(c, http.BadRequest.Code, "pipeline id is required")
 	}
 	var req struct {
-		Spec                  *pipelinev1.Spec `json:"spec"`
-		Format                string           `json:"format"`
-		ExpectedHeadCommitSha string           `json:"expectedHeadCommitSha"`
-		CommitMessage         string           `json:"commitMessage"`
-		RequestID             string           `json:"requestId"`
-		Editor                string           `json:"editor"`
+		Spec                  json.RawMessage `json:"spec"`
+		Format                string          `json:"format"`
+		ExpectedHeadCommitSha string          `json:"expectedHeadCommitSha"`
+		CommitMessage         string          `json:"commitMessage"`
+		RequestID             string          `json:"requestId"`
+		Editor                string          `json:"editor"`
 	}
 	if err := c.BodyParser(&req); err != nil {
 		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
 	}
+	specBody, err := decodeSpecBody(req.Spec)
+	if err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, err.Error())
+	}
 	editor := strings.TrimSpace(req.Editor)
 	if editor == "" {
 		editor = auth.CurrentUserID(c, rt.HTTP.Auth.SecretKey)
 	}
 	resp, err := rt.pipelineService().SavePipelineSpec(c.Context(), &pipelinev1.SavePipelineSpecRequest{
 		PipelineId:            pipelineID,
-		Spec:                  req.Spec,
+		Spec:                  specBody,
 		Format:                parseSpecFormat(req.Format),
 		ExpectedHeadCommitSha: req.ExpectedHeadCommitSha,
 		CommitMessage:         req.CommitMessage,
@@ -360,6 +374,73 @@ func (rt *Router) getPipelineRun(c *fiber.Ctx) error {
 	return http.Detail(c, resp.GetRun())
 }
 
+// listPipelineRunSteps lists the step runs of a pipeline run, e.g. to pick
+// the stepRunId for a log subscription.
+func (rt *Router) listPipelineRunSteps(c *fiber.Ctx) error {
+	runID := strings.TrimSpace(c.Params("runID"))
+	if runID == "" {
+		return http.Err(c, http.BadRequest.Code, "run id is required")
+	}
+	resp, err := service.NewStepRunServiceImpl(rt.Services.StepRunRepo).ListStepRuns(c.Context(), &steprunv1.ListStepRunsRequest{
+		PipelineRunId: runID,
+		Page:          int32(maxIntWithOne(rt.HTTP.QueryInt(c, "page"))),
+		PageSize:      int32(rt.HTTP.QueryInt(c, "pageSize")),
+	})
+	if err != nil {
+		return http.Err(c, http.Failed.Code, err.Error())
+	}
+	if !resp.GetSuccess() {
+		return http.Err(c, http.Failed.Code, resp.GetMessage())
+	}
+	return http.Detail(c, map[string]any{
+		"list":     resp.GetStepRuns(),
+		"total":    resp.GetTotal(),
+		"page":     resp.GetPage(),
+		"pageSize": resp.GetPageSize(),
+	})
+}
+
+func (rt *Router) rerunPipeline(c *fiber.Ctx) error {
+	pipelineID := strings.TrimSpace(c.Params("pipelineID"))
+	runID := strings.TrimSpace(c.Params("runID"))
+	if pipelineID == "" || runID == "" {
+		return http.Err(c, http.BadRequest.Code, "pipeline id and run id are required")
+	}
+	var req struct {
+		Mode        string `json:"mode"`
+		JobName     string `json:"jobName"`
+		TriggeredBy string `json:"triggeredBy"`
+		RequestID   string `json:"requestId"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
+	}
+	triggeredBy := strings.TrimSpace(req.TriggeredBy)
+	if triggeredBy == "" {
+		triggeredBy = auth.CurrentUserID(c, rt.HTTP.Auth.SecretKey)
+	}
+	resp, err := rt.pipelineService().RerunPipeline(c.Context(), &pipelinev1.RerunPipelineRequest{
+		PipelineId:  pipelineID,
+		RunId:       runID,
+		Mode:        strings.ToLower(strings.TrimSpace(req.Mode)),
+		JobName:     strings.TrimSpace(req.JobName),
+		TriggeredBy: triggeredBy,
+		RequestId:   req.RequestID,
+	})
+	if err != nil {
+		return http.Err(c, http.Failed.Code, err.Error())
+	}
+	if e := pipelineAPIError(resp.GetSuccess(), resp.GetMessage(), resp.GetError()); e != nil {
+		return http.Err(c, e.code, e.msg)
+	}
+	return http.Detail(c, map[string]any{
+		"runID":         resp.GetRunId(),
+		"message":       resp.GetMessage(),
+		"reusedJobs":    resp.GetReusedJobs(),
+		"scheduledJobs": resp.GetScheduledJobs(),
+	})
+}
+
 func (rt *Router) stopPipeline(c *fiber.Ctx) error {
 	pipelineID := strings.TrimSpace(c.Params("pipelineID"))
 	runID := strings.TrimSpace(c.Params("runID"))
@@ -483,6 +564,20 @@ func pipelineAPIError(success bool, message string, err *pipelinev1.Error) *pipe
 	return &pipelineRespErr{code: code, msg: msg}
 }
 
+// decodeSpecBody decodes the spec of a request body with protojson so that
+// google.protobuf.Struct fields (step args, matrix axes, ...) keep their
+// plain JSON object form.
+func decodeSpecBody(raw json.RawMessage) (*pipelinev1.Spec, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var sp pipelinev1.Spec
+	if err := protojson.Unmarshal(raw, &sp); err != nil {
+		return nil, fmt.Errorf("invalid spec: %w", err)
+	}
+	return &sp, nil
+}
+
 func parseSaveMode(mode string) pipelinev1.PipelineSaveMode {
 	switch strings.ToLower(strings.TrimSpace(mode)) {
 	case "pr":
@@ -0,0 +1,80 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/arcentrix/arcentra/internal/control/service"
+	"github.com/arcentrix/arcentra/pkg/http"
+	"github.com/gofiber/fiber/v2"
+)
+
+// terminalRouter registers the audit routes of debug terminal sessions. The
+// terminals themselves are opened over websocket, see wsRouter.
+func (rt *Router) terminalRouter(r fiber.Router, authMiddleware fiber.Handler) {
+	terminals := r.Group("/terminals")
+	{
+		terminals.Get("/", authMiddleware, rt.listTerminalSessions)
+		terminals.Get("/:sessionID", authMiddleware, rt.getTerminalSession)
+	}
+}
+
+// terminalErr maps terminal service errors to response codes
+func terminalErr(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrTerminalSessionNotFound), errors.Is(err, service.ErrTerminalStepRunNotFound):
+		return http.Err(c, http.NotFound.Code, err.Error())
+	case errors.Is(err, service.ErrProjectPermissionDenied):
+		return http.Err(c, http.PermissionDenied.Code, err.Error())
+	}
+	return http.Err(c, http.Failed.Code, err.Error())
+}
+
+func (rt *Router) listTerminalSessions(c *fiber.Ctx) error {
+	userID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	stepRunID := strings.TrimSpace(c.Query("stepRunId"))
+	if stepRunID == "" {
+		return http.Err(c, http.BadRequest.Code, "step run id is required")
+	}
+	sessions, err := rt.Services.Terminal.ListByStepRun(c.Context(), userID, stepRunID)
+	if err != nil {
+		return terminalErr(c, err)
+	}
+	return http.Detail(c, map[string]any{
+		"list":  sessions,
+		"total": len(sessions),
+	})
+}
+
+func (rt *Router) getTerminalSession(c *fiber.Ctx) error {
+	userID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	sessionID := strings.TrimSpace(c.Params("sessionID"))
+	if sessionID == "" {
+		return http.Err(c, http.BadRequest.Code, "session id is required")
+	}
+	session, err := rt.Services.Terminal.Get(c.Context(), userID, sessionID)
+	if err != nil {
+		return terminalErr(c, err)
+	}
+	return http.Detail(c, session)
+}
@@ -0,0 +1,252 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/service"
+	"github.com/arcentrix/arcentra/pkg/http"
+	"github.com/gofiber/fiber/v2"
+)
+
+// variableRouter registers project and team variable routes. Runs inherit
+// variables with the precedence team < project < pipeline < trigger overrides.
+func (rt *Router) variableRouter(r fiber.Router, authMiddleware fiber.Handler) {
+	projectVars := r.Group("/project/:projectID/variables")
+	{
+		projectVars.Get("/", authMiddleware, rt.listProjectVariables)
+		projectVars.Post("/", authMiddleware, rt.createProjectVariable)
+		projectVars.Get("/:variableID", authMiddleware, rt.getProjectVariable)
+		projectVars.Put("/:variableID", authMiddleware, rt.updateProjectVariable)
+		projectVars.Delete("/:variableID", authMiddleware, rt.deleteProjectVariable)
+	}
+
+	teamVars := r.Group("/team/:teamID/variables")
+	{
+		teamVars.Get("/", authMiddleware, rt.listTeamVariables)
+		teamVars.Post("/", authMiddleware, rt.createTeamVariable)
+		teamVars.Get("/:variableID", authMiddleware, rt.getTeamVariable)
+		teamVars.Put("/:variableID", authMiddleware, rt.updateTeamVariable)
+		teamVars.Delete("/:variableID", authMiddleware, rt.deleteTeamVariable)
+	}
+}
+
+// variableErr maps variable service errors to response codes
+func variableErr(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrProjectPermissionDenied), errors.Is(err, service.ErrTeamPermissionDenied):
+		return http.Err(c, http.PermissionDenied.Code, err.Error())
+	case errors.Is(err, service.ErrVariableNotFound):
+		return http.Err(c, http.NotFound.Code, err.Error())
+	}
+	return http.Err(c, http.Failed.Code, err.Error())
+}
+
+// ---------------------------------------------------------------------------
+// Project variable endpoints
+// ---------------------------------------------------------------------------
+
+func (rt *Router) listProjectVariables(c *fiber.Ctx) error {
+	projectID := strings.TrimSpace(c.Params("projectID"))
+	if projectID == "" {
+		return http.Err(c, http.BadRequest.Code, "project id is required")
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	vars, err := rt.Services.Variable.ListProjectVariables(c.Context(), projectID, operatorID)
+	if err != nil {
+		return variableErr(c, err)
+	}
+	return http.Detail(c, map[string]any{
+		"list":  vars,
+		"total": len(vars),
+	})
+}
+
+func (rt *Router) createProjectVariable(c *fiber.Ctx) error {
+	projectID := strings.TrimSpace(c.Params("projectID"))
+	if projectID == "" {
+		return http.Err(c, http.BadRequest.Code, "project id is required")
+	}
+	var req model.CreateVariableReq
+	if err := c.BodyParser(&req); err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	v, err := rt.Services.Variable.CreateProjectVariable(c.Context(), projectID, operatorID, &req)
+	if err != nil {
+		return variableErr(c, err)
+	}
+	return http.Detail(c, v)
+}
+
+func (rt *Router) getProjectVariable(c *fiber.Ctx) error {
+	projectID := strings.TrimSpace(c.Params("projectID"))
+	variableID := strings.TrimSpace(c.Params("variableID"))
+	if projectID == "" || variableID == "" {
+		return http.Err(c, http.BadRequest.Code, "project id and variable id are required")
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	v, err := rt.Services.Variable.GetProjectVariable(c.Context(), projectID, operatorID, variableID)
+	if err != nil {
+		return variableErr(c, err)
+	}
+	return http.Detail(c, v)
+}
+
+func (rt *Router) updateProjectVariable(c *fiber.Ctx) error {
+	projectID := strings.TrimSpace(c.Params("projectID"))
+	variableID := strings.TrimSpace(c.Params("variableID"))
+	if projectID == "" || variableID == "" {
+		return http.Err(c, http.BadRequest.Code, "project id and variable id are required")
+	}
+	var req model.UpdateVariableReq
+	if err := c.BodyParser(&req); err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	v, err := rt.Services.Variable.UpdateProjectVariable(c.Context(), projectID, operatorID, variableID, &req)
+	if err != nil {
+		return variableErr(c, err)
+	}
+	return http.Detail(c, v)
+}
+
+func (rt *Router) deleteProjectVariable(c *fiber.Ctx) error {
+	projectID := strings.TrimSpace(c.Params("projectID"))
+	variableID := strings.TrimSpace(c.Params("variableID"))
+	if projectID == "" || variableID == "" {
+		return http.Err(c, http.BadRequest.Code, "project id and variable id are required")
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	if err := rt.Services.Variable.DeleteProjectVariable(c.Context(), projectID, operatorID, variableID); err != nil {
+		return variableErr(c, err)
+	}
+	return http.Operation(c)
+}
+
+// ---------------------------------------------------------------------------
+// Team variable endpoints
+// ---------------------------------------------------------------------------
+
+func (rt *Router) listTeamVariables(c *fiber.Ctx) error {
+	teamID := strings.TrimSpace(c.Params("teamID"))
+	if teamID == "" {
+		return http.Err(c, http.TeamIDIsEmpty.Code, http.TeamIDIsEmpty.Msg)
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	vars, err := rt.Services.Variable.ListTeamVariables(c.Context(), teamID, operatorID)
+	if err != nil {
+		return variableErr(c, err)
+	}
+	return http.Detail(c, map[string]any{
+		"list":  vars,
+		"total": len(vars),
+	})
+}
+
+func (rt *Router) createTeamVariable(c *fiber.Ctx) error {
+	teamID := strings.TrimSpace(c.Params("teamID"))
+	if teamID == "" {
+		return http.Err(c, http.TeamIDIsEmpty.Code, http.TeamIDIsEmpty.Msg)
+	}
+	var req model.CreateVariableReq
+	if err := c.BodyParser(&req); err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	v, err := rt.Services.Variable.CreateTeamVariable(c.Context(), teamID, operatorID, &req)
+	if err != nil {
+		return variableErr(c, err)
+	}
+	return http.Detail(c, v)
+}
+
+func (rt *Router) getTeamVariable(c *fiber.Ctx) error {
+	teamID := strings.TrimSpace(c.Params("teamID"))
+	variableID := strings.TrimSpace(c.Params("variableID"))
+	if teamID == "" || variableID == "" {
+		return http.Err(c, http.BadRequest.Code, "team id and variable id are required")
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	v, err := rt.Services.Variable.GetTeamVariable(c.Context(), teamID, operatorID, variableID)
+	if err != nil {
+		return variableErr(c, err)
+	}
+	return http.Detail(c, v)
+}
+
+func (rt *Router) updateTeamVariable(c *fiber.Ctx) error {
+	teamID := strings.TrimSpace(c.Params("teamID"))
+	variableID := strings.TrimSpace(c.Params("variableID"))
+	if teamID == "" || variableID == "" {
+		return http.Err(c, http.BadRequest.Code, "team id and variable id are required")
+	}
+	var req model.UpdateVariableReq
+	if err := c.BodyParser(&req); err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	v, err := rt.Services.Variable.UpdateTeamVariable(c.Context(), teamID, operatorID, variableID, &req)
+	if err != nil {
+		return variableErr(c, err)
+	}
+	return http.Detail(c, v)
+}
+
+func (rt *Router) deleteTeamVariable(c *fiber.Ctx) error {
+	teamID := strings.TrimSpace(c.Params("teamID"))
+	variableID := strings.TrimSpace(c.Params("variableID"))
+	if teamID == "" || variableID == "" {
+		return http.Err(c, http.BadRequest.Code, "team id and variable id are required")
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	if err := rt.Services.Variable.DeleteTeamVariable(c.Context(), teamID, operatorID, variableID); err != nil {
+		return variableErr(c, err)
+	}
+	return http.Operation(c)
+}
@@ -0,0 +1,211 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/service"
+	"github.com/arcentrix/arcentra/pkg/http"
+	"github.com/gofiber/fiber/v2"
+)
+
+// webhookRouter registers outgoing project webhook routes: webhook CRUD,
+// delivery history and redelivery.
+func (rt *Router) webhookRouter(r fiber.Router, authMiddleware fiber.Handler) {
+	hooks := r.Group("/project/:projectID/webhooks")
+	{
+		hooks.Get("/", authMiddleware, rt.listProjectWebhooks)
+		hooks.Post("/", authMiddleware, rt.createProjectWebhook)
+		hooks.Get("/:webhookID", authMiddleware, rt.getProjectWebhook)
+		hooks.Put("/:webhookID", authMiddleware, rt.updateProjectWebhook)
+		hooks.Delete("/:webhookID", authMiddleware, rt.deleteProjectWebhook)
+		hooks.Get("/:webhookID/deliveries", authMiddleware, rt.listWebhookDeliveries)
+		hooks.Get("/:webhookID/deliveries/:deliveryID", authMiddleware, rt.getWebhookDelivery)
+		hooks.Post("/:webhookID/deliveries/:deliveryID/redeliver", authMiddleware, rt.redeliverWebhook)
+	}
+}
+
+// webhookErr maps webhook service errors to response codes
+func webhookErr(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrProjectPermissionDenied):
+		return http.Err(c, http.PermissionDenied.Code, err.Error())
+	case errors.Is(err, service.ErrWebhookNotFound) || errors.Is(err, service.ErrWebhookDeliveryNotFound):
+		return http.Err(c, http.NotFound.Code, err.Error())
+	}
+	return http.Err(c, http.Failed.Code, err.Error())
+}
+
+func (rt *Router) listProjectWebhooks(c *fiber.Ctx) error {
+	projectID := strings.TrimSpace(c.Params("projectID"))
+	if projectID == "" {
+		return http.Err(c, http.BadRequest.Code, "project id is required")
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	hooks, err := rt.Services.Webhook.ListWebhooks(c.Context(), projectID, operatorID)
+	if err != nil {
+		return webhookErr(c, err)
+	}
+	return http.Detail(c, map[string]any{
+		"list":  hooks,
+		"total": len(hooks),
+	})
+}
+
+func (rt *Router) createProjectWebhook(c *fiber.Ctx) error {
+	projectID := strings.TrimSpace(c.Params("projectID"))
+	if projectID == "" {
+		return http.Err(c, http.BadRequest.Code, "project id is required")
+	}
+	var req model.CreateProjectWebhookReq
+	if err := c.BodyParser(&req); err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	hook, err := rt.Services.Webhook.CreateWebhook(c.Context(), projectID, operatorID, &req)
+	if err != nil {
+		return webhookErr(c, err)
+	}
+	return http.Detail(c, hook)
+}
+
+func (rt *Router) getProjectWebhook(c *fiber.Ctx) error {
+	projectID := strings.TrimSpace(c.Params("projectID"))
+	webhookID := strings.TrimSpace(c.Params("webhookID"))
+	if projectID == "" || webhookID == "" {
+		return http.Err(c, http.BadRequest.Code, "project id and webhook id are required")
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	hook, err := rt.Services.Webhook.GetWebhook(c.Context(), projectID, operatorID, webhookID)
+	if err != nil {
+		return webhookErr(c, err)
+	}
+	return http.Detail(c, hook)
+}
+
+func (rt *Router) updateProjectWebhook(c *fiber.Ctx) error {
+	projectID := strings.TrimSpace(c.Params("projectID"))
+	webhookID := strings.TrimSpace(c.Params("webhookID"))
+	if projectID == "" || webhookID == "" {
+		return http.Err(c, http.BadRequest.Code, "project id and webhook id are required")
+	}
+	var req model.UpdateProjectWebhookReq
+	if err := c.BodyParser(&req); err != nil {
+		return http.Err(c, http.RequestParameterParsingFailed.Code, http.RequestParameterParsingFailed.Msg)
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	hook, err := rt.Services.Webhook.UpdateWebhook(c.Context(), projectID, operatorID, webhookID, &req)
+	if err != nil {
+		return webhookErr(c, err)
+	}
+	return http.Detail(c, hook)
+}
+
+func (rt *Router) deleteProjectWebhook(c *fiber.Ctx) error {
+	projectID := strings.TrimSpace(c.Params("projectID"))
+	webhookID := strings.TrimSpace(c.Params("webhookID"))
+	if projectID == "" || webhookID == "" {
+		return http.Err(c, http.BadRequest.Code, "project id and webhook id are required")
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	if err := rt.Services.Webhook.DeleteWebhook(c.Context(), projectID, operatorID, webhookID); err != nil {
+		return webhookErr(c, err)
+	}
+	return http.Operation(c)
+}
+
+func (rt *Router) listWebhookDeliveries(c *fiber.Ctx) error {
+	projectID := strings.TrimSpace(c.Params("projectID"))
+	webhookID := strings.TrimSpace(c.Params("webhookID"))
+	if projectID == "" || webhookID == "" {
+		return http.Err(c, http.BadRequest.Code, "project id and webhook id are required")
+	}
+	pageNum := rt.HTTP.QueryInt(c, "pageNum")
+	if pageNum <= 0 {
+		pageNum = 1
+	}
+	pageSize := rt.HTTP.QueryInt(c, "pageSize")
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	deliveries, total, err := rt.Services.Webhook.ListDeliveries(c.Context(), projectID, operatorID, webhookID, pageNum, pageSize)
+	if err != nil {
+		return webhookErr(c, err)
+	}
+	return http.Detail(c, map[string]any{
+		"list":     deliveries,
+		"total":    total,
+		"pageNum":  pageNum,
+		"pageSize": pageSize,
+	})
+}
+
+func (rt *Router) getWebhookDelivery(c *fiber.Ctx) error {
+	projectID := strings.TrimSpace(c.Params("projectID"))
+	webhookID := strings.TrimSpace(c.Params("webhookID"))
+	deliveryID := strings.TrimSpace(c.Params("deliveryID"))
+	if projectID == "" || webhookID == "" || deliveryID == "" {
+		return http.Err(c, http.BadRequest.Code, "project id, webhook id and delivery id are required")
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	delivery, err := rt.Services.Webhook.GetDelivery(c.Context(), projectID, operatorID, webhookID, deliveryID)
+	if err != nil {
+		return webhookErr(c, err)
+	}
+	return http.Detail(c, delivery)
+}
+
+func (rt *Router) redeliverWebhook(c *fiber.Ctx) error {
+	projectID := strings.TrimSpace(c.Params("projectID"))
+	webhookID := strings.TrimSpace(c.Params("webhookID"))
+	deliveryID := strings.TrimSpace(c.Params("deliveryID"))
+	if projectID == "" || webhookID == "" || deliveryID == "" {
+		return http.Err(c, http.BadRequest.Code, "project id, webhook id and delivery id are required")
+	}
+	operatorID, err := rt.currentUserID(c)
+	if err != nil {
+		return http.Err(c, http.AuthenticationFailed.Code, http.AuthenticationFailed.Msg)
+	}
+	delivery, err := rt.Services.Webhook.Redeliver(c.Context(), projectID, operatorID, webhookID, deliveryID)
+	if err != nil {
+		return webhookErr(c, err)
+	}
+	return http.Detail(c, delivery)
+}
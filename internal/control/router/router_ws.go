@@ -27,4 +27,8 @@ func (rt *Router) wsRouter(r fiber.Router, auth fiber.Handler) {
 	kafkaCfg := rt.AppConf.MessageQueue.Kafka
 	wsHandle := service.NewWSHandle(wsHub, rt.Services.LogAggregator, rt.Services.StepRunRepo, kafkaCfg)
 	r.Get("/ws", auth, ws.Handle(wsHub, wsHandle))
+
+	// 调试终端：每个连接打开一个终端，经由 Agent 的 Connect 流转发
+	terminalHub := ws.NewHub()
+	r.Get("/ws/terminal", auth, ws.Handle(terminalHub, service.NewWSTerminalHandle(rt.Services.Terminal)))
 }
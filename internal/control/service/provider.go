@@ -16,6 +16,7 @@ package service
 
 import (
 	"github.com/arcentrix/arcentra/internal/control/repo"
+	"github.com/arcentrix/arcentra/internal/shared/notify"
 	"github.com/arcentrix/arcentra/pkg/cache"
 	"github.com/arcentrix/arcentra/pkg/database"
 	"github.com/google/wire"
@@ -31,6 +32,7 @@ func ProvideServices(
 	db database.IDatabase,
 	cacheStore cache.ICache,
 	repos *repo.Repositories,
+	notifyManager *notify.Manager,
 ) *Services {
-	return NewServices(db, cacheStore, repos)
+	return NewServices(db, cacheStore, repos, notifyManager)
 }
@@ -37,6 +37,7 @@ type AgentService struct {
 	jobRunRepo           repo.IJobRunRepository
 	settingService       *SettingService
 	registrationTokenSvc *RegistrationTokenService
+	streams              *AgentStreamHub
 }
 
 // NewAgentService creates a new AgentService.
@@ -51,9 +52,15 @@ func NewAgentService(
 		stepRunRepo:    stepRunRepo,
 		jobRunRepo:     jobRunRepo,
 		settingService: settingService,
+		streams:        NewAgentStreamHub(),
 	}
 }
 
+// Streams returns the hub of open agent Connect streams.
+func (s *AgentService) Streams() *AgentStreamHub {
+	return s.streams
+}
+
 // SetRegistrationTokenService sets the registration token service for dynamic registration.
 func (al *AgentService) SetRegistrationTokenService(svc *RegistrationTokenService) {
 	al.registrationTokenSvc = svc
@@ -121,6 +121,8 @@ func (a *AgentServiceImpl) dynamicRegister(ctx context.Context, req *agentv1.Reg
 		Status:    1, // online
 		Labels:    []byte(labelsJSON),
 		Metrics:   "/metrics",
+
+		MaxConcurrentJobs: max(int(req.MaxConcurrentStepRuns), 1),
 	}
 
 	if err := a.agentService.DynamicRegisterAgent(ctx, agentID, agentName, agent); err != nil {
@@ -219,6 +221,9 @@ func (a *AgentServiceImpl) legacyRegister(ctx context.Context, req *agentv1.Regi
 			updates["labels"] = string(encoded)
 		}
 	}
+	if req.MaxConcurrentStepRuns > 0 {
+		updates["max_concurrent_jobs"] = int(req.MaxConcurrentStepRuns)
+	}
 	updates["status"] = 1
 	updates["last_heartbeat"] = time.Now()
 
@@ -377,6 +382,11 @@ func (a *AgentServiceImpl) ReportStepRunStatus(
 			updates["secrets"] = string(encoded)
 		}
 	}
+	if len(req.Outputs) > 0 {
+		if encoded, err := sonic.Marshal(req.Outputs); err == nil {
+			updates["outputs"] = string(encoded)
+		}
+	}
 	if err := a.agentService.stepRunRepo.PatchByStepRunID(ctx, stepRunID, updates); err != nil {
 		log.Debugw("step run patch miss, trying job run", "id", stepRunID, "error", err)
 	}
@@ -416,6 +426,16 @@ func (a *AgentServiceImpl) ReportJobRunStatus(
 			updates["artifact_uris"] = string(encoded)
 		}
 	}
+	if len(req.Outputs) > 0 {
+		if encoded, err := sonic.Marshal(req.Outputs); err == nil {
+			updates["outputs"] = string(encoded)
+		}
+	}
+	if len(req.ServiceLogs) > 0 {
+		if encoded, err := sonic.Marshal(req.ServiceLogs); err == nil {
+			updates["service_logs"] = string(encoded)
+		}
+	}
 	if req.AgentId != "" {
 		updates["agent_id"] = strings.TrimSpace(req.AgentId)
 	}
@@ -467,6 +487,50 @@ func (a *AgentServiceImpl) CancelStepRun(ctx context.Context, req *agentv1.Cance
 	return &agentv1.CancelStepRunResponse{Success: true, Message: "cancelled"}, nil
 }
 
+// Connect serves the bidirectional stream an agent keeps open to receive
+// commands pushed by the control plane. The first message identifies the agent.
+func (a *AgentServiceImpl) Connect(stream agentv1.AgentService_ConnectServer) error {
+	if a.agentService == nil || a.agentService.streams == nil {
+		return status.Errorf(codes.Unavailable, "agent service unavailable")
+	}
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	agentID := strings.TrimSpace(first.AgentId)
+	if agentID == "" {
+		return status.Errorf(codes.InvalidArgument, "agent_id is required")
+	}
+	hub := a.agentService.streams
+	s := hub.register(agentID, stream)
+	defer hub.unregister(agentID, s)
+	log.Infow("agent connect stream opened", "agentID", agentID)
+
+	for req := first; ; {
+		switch p := req.GetPayload().(type) {
+		case *agentv1.ConnectRequest_Heartbeat:
+			if a.agentService.agentRepo != nil {
+				updates := map[string]any{
+					"status":         int(p.Heartbeat.Status),
+					"updated_at":     time.Now(),
+					"last_heartbeat": time.Now(),
+				}
+				if err := a.agentService.agentRepo.Patch(stream.Context(), agentID, updates); err != nil {
+					log.Warnw("failed to update agent from connect heartbeat", "agentID", agentID, "error", err)
+				}
+			}
+		case *agentv1.ConnectRequest_TerminalOutput, *agentv1.ConnectRequest_TerminalExit:
+			hub.route(req)
+		case *agentv1.ConnectRequest_TaskAck:
+			log.Debugw("agent command ack", "agentID", agentID, "commandID", p.TaskAck.CommandId, "status", p.TaskAck.Status.String())
+		}
+		if req, err = stream.Recv(); err != nil {
+			log.Infow("agent connect stream closed", "agentID", agentID, "error", err)
+			return nil
+		}
+	}
+}
+
 func (a *AgentServiceImpl) UpdateLabels(ctx context.Context, req *agentv1.UpdateLabelsRequest) (*agentv1.UpdateLabelsResponse, error) {
 	if a.agentService == nil || a.agentService.agentRepo == nil {
 		return &agentv1.UpdateLabelsResponse{Success: false, Message: "agent repository unavailable"}, nil
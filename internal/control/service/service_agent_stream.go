@@ -0,0 +1,150 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"sync"
+
+	agentv1 "github.com/arcentrix/arcentra/api/agent/v1"
+	"github.com/arcentrix/arcentra/pkg/log"
+)
+
+// ErrAgentNotConnected is returned when an agent has no open Connect stream.
+var ErrAgentNotConnected = errors.New("agent is not connected")
+
+// terminalSinkSize bounds the messages buffered for a slow terminal client.
+const terminalSinkSize = 1024
+
+// AgentStreamHub tracks the open Connect streams of agents and routes the
+// terminal messages agents send back to the session that opened them.
+type AgentStreamHub struct {
+	mu       sync.RWMutex
+	streams  map[string]*agentStream  // agentID -> stream
+	sessions map[string]*terminalSink // sessionID -> sink
+}
+
+type agentStream struct {
+	mu     sync.Mutex
+	stream agentv1.AgentService_ConnectServer
+}
+
+type terminalSink struct {
+	agentID string
+	ch      chan *agentv1.ConnectRequest
+}
+
+// NewAgentStreamHub creates an empty hub.
+func NewAgentStreamHub() *AgentStreamHub {
+	return &AgentStreamHub{
+		streams:  make(map[string]*agentStream),
+		sessions: make(map[string]*terminalSink),
+	}
+}
+
+// register records the stream of an agent, replacing an older one.
+func (h *AgentStreamHub) register(agentID string, stream agentv1.AgentService_ConnectServer) *agentStream {
+	s := &agentStream{stream: stream}
+	h.mu.Lock()
+	h.streams[agentID] = s
+	h.mu.Unlock()
+	return s
+}
+
+// unregister drops the stream of an agent and ends its terminal sessions,
+// unless the agent has already reconnected with a newer stream.
+func (h *AgentStreamHub) unregister(agentID string, s *agentStream) {
+	h.mu.Lock()
+	if h.streams[agentID] != s {
+		h.mu.Unlock()
+		return
+	}
+	delete(h.streams, agentID)
+	var orphaned []string
+	for id, sink := range h.sessions {
+		if sink.agentID == agentID {
+			orphaned = append(orphaned, id)
+		}
+	}
+	h.mu.Unlock()
+	for _, id := range orphaned {
+		h.route(&agentv1.ConnectRequest{Payload: &agentv1.ConnectRequest_TerminalExit{
+			TerminalExit: &agentv1.TerminalExit{SessionId: id, ExitCode: -1, Error: ErrAgentNotConnected.Error()},
+		}})
+	}
+}
+
+// Connected reports whether the agent has an open Connect stream.
+func (h *AgentStreamHub) Connected(agentID string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	_, ok := h.streams[agentID]
+	return ok
+}
+
+// Send pushes a command to an agent over its Connect stream.
+func (h *AgentStreamHub) Send(agentID string, resp *agentv1.ConnectResponse) error {
+	h.mu.RLock()
+	s := h.streams[agentID]
+	h.mu.RUnlock()
+	if s == nil {
+		return ErrAgentNotConnected
+	}
+	resp.AgentId = agentID
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stream.Send(resp)
+}
+
+// subscribe returns the channel receiving the terminal messages of a session.
+func (h *AgentStreamHub) subscribe(sessionID, agentID string) <-chan *agentv1.ConnectRequest {
+	sink := &terminalSink{agentID: agentID, ch: make(chan *agentv1.ConnectRequest, terminalSinkSize)}
+	h.mu.Lock()
+	h.sessions[sessionID] = sink
+	h.mu.Unlock()
+	return sink.ch
+}
+
+// unsubscribe stops routing messages to a session.
+func (h *AgentStreamHub) unsubscribe(sessionID string) {
+	h.mu.Lock()
+	delete(h.sessions, sessionID)
+	h.mu.Unlock()
+}
+
+// route hands a terminal message from an agent to its session. A session that
+// does not keep up loses output rather than stalling the agent stream.
+func (h *AgentStreamHub) route(req *agentv1.ConnectRequest) {
+	var sessionID string
+	switch p := req.GetPayload().(type) {
+	case *agentv1.ConnectRequest_TerminalOutput:
+		sessionID = p.TerminalOutput.GetSessionId()
+	case *agentv1.ConnectRequest_TerminalExit:
+		sessionID = p.TerminalExit.GetSessionId()
+	default:
+		return
+	}
+	h.mu.RLock()
+	sink := h.sessions[sessionID]
+	h.mu.RUnlock()
+	if sink == nil {
+		return
+	}
+	select {
+	case sink.ch <- req:
+	default:
+		log.Warnw("terminal session is not keeping up, dropping output", "sessionId", sessionID)
+	}
+}
@@ -74,7 +74,12 @@ func (b *ApprovalCallbackURLBuilder) VerifyToken(ctx context.Context, approvalID
 }
 
 func (b *ApprovalCallbackURLBuilder) getExternalURL(ctx context.Context) (string, error) {
-	setting, err := b.settingRepo.Get(ctx, consts.SettingNameExternalURL)
+	return getExternalURL(ctx, b.settingRepo)
+}
+
+// getExternalURL reads the EXTERNAL_URL setting used to build links sent to users.
+func getExternalURL(ctx context.Context, settingRepo repo.ISettingRepository) (string, error) {
+	setting, err := settingRepo.Get(ctx, consts.SettingNameExternalURL)
 	if err != nil {
 		return "", err
 	}
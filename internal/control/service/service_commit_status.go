@@ -0,0 +1,210 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/arcentrix/arcentra/pkg/safe"
+	"github.com/arcentrix/arcentra/pkg/scm"
+)
+
+// commitStatusTimeout bounds one report, including the project lookup.
+const commitStatusTimeout = 15 * time.Second
+
+// CommitStatusService posts PipelineRun and JobRun state to the commit that
+// triggered the run, using the SCM provider of the pipeline's project.
+// Reports are sent in the background; for each commit and context only the
+// newest state is sent, so a slow API never leaves a stale state behind.
+type CommitStatusService struct {
+	projectRepo  repo.IProjectRepository
+	pipelineRepo repo.IPipelineRepository
+
+	enabled     bool
+	context     string
+	externalURL string
+
+	mu    sync.Mutex
+	slots map[string]*commitStatusSlot // repo@sha#context -> latest state
+}
+
+// commitStatusSlot serializes the reports of one commit status context.
+type commitStatusSlot struct {
+	send    sync.Mutex
+	pending *commitStatusReport // guarded by CommitStatusService.mu
+}
+
+type commitStatusReport struct {
+	run    *model.PipelineRun
+	status scm.CommitStatus
+}
+
+// NewCommitStatusService creates a commit status service. Reporting stays
+// off until Enable is called.
+func NewCommitStatusService(
+	projectRepo repo.IProjectRepository,
+	pipelineRepo repo.IPipelineRepository,
+) *CommitStatusService {
+	return &CommitStatusService{
+		projectRepo:  projectRepo,
+		pipelineRepo: pipelineRepo,
+		slots:        make(map[string]*commitStatusSlot),
+	}
+}
+
+// Enable turns reporting on. statusContext prefixes every status context
+// and externalURL is the public base URL status links point to.
+func (s *CommitStatusService) Enable(statusContext, externalURL string) {
+	s.enabled = true
+	s.context = strings.Trim(strings.TrimSpace(statusContext), "/")
+	s.externalURL = strings.TrimRight(strings.TrimSpace(externalURL), "/")
+}
+
+// ReportRun reports the state of a whole run under "<prefix>/<pipeline>".
+func (s *CommitStatusService) ReportRun(run *model.PipelineRun, state scm.CommitState, description string) {
+	s.report(run, "", state, description)
+}
+
+// ReportJob reports the state of one job of a run under
+// "<prefix>/<pipeline>/<job>".
+func (s *CommitStatusService) ReportJob(
+	run *model.PipelineRun,
+	jobName string,
+	state scm.CommitState,
+	description string,
+) {
+	if strings.TrimSpace(jobName) == "" {
+		return
+	}
+	s.report(run, jobName, state, description)
+}
+
+func (s *CommitStatusService) report(run *model.PipelineRun, jobName string, state scm.CommitState, description string) {
+	if s == nil || !s.enabled || run == nil || strings.TrimSpace(run.CommitSha) == "" {
+		return
+	}
+	pipelineName := run.PipelineName
+	if pipelineName == "" {
+		pipelineName = run.PipelineID
+	}
+	statusContext := s.context + "/" + pipelineName
+	if jobName != "" {
+		statusContext += "/" + jobName
+	}
+	r := &commitStatusReport{
+		run: run,
+		status: scm.CommitStatus{
+			SHA:         strings.TrimSpace(run.CommitSha),
+			State:       state,
+			Context:     statusContext,
+			Description: description,
+			TargetURL:   s.runURL(run),
+		},
+	}
+
+	key := run.PipelineID + "@" + r.status.SHA + "#" + statusContext
+	s.mu.Lock()
+	slot := s.slots[key]
+	if slot == nil {
+		slot = &commitStatusSlot{}
+		s.slots[key] = slot
+	}
+	slot.pending = r
+	s.mu.Unlock()
+
+	safe.Go(func() { s.flush(key, slot) })
+}
+
+// flush sends the newest pending report of slot. Sends of one slot never
+// overlap, so the state sent last is always the newest one.
+func (s *CommitStatusService) flush(key string, slot *commitStatusSlot) {
+	slot.send.Lock()
+	defer slot.send.Unlock()
+
+	s.mu.Lock()
+	r := slot.pending
+	slot.pending = nil
+	s.mu.Unlock()
+	if r != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), commitStatusTimeout)
+		if err := s.send(ctx, r); err != nil {
+			log.Warnw("report commit status failed",
+				"runId", r.run.RunID, "context", r.status.Context, "state", r.status.State, "error", err)
+		}
+		cancel()
+	}
+
+	s.mu.Lock()
+	if slot.pending == nil && s.slots[key] == slot {
+		delete(s.slots, key)
+	}
+	s.mu.Unlock()
+}
+
+// send resolves the run's project and posts the status through its provider.
+// Projects without a supported provider or token are skipped silently.
+func (s *CommitStatusService) send(ctx context.Context, r *commitStatusReport) error {
+	p, err := s.pipelineRepo.Get(ctx, r.run.PipelineID)
+	if err != nil {
+		return fmt.Errorf("get pipeline: %w", err)
+	}
+	if p == nil || p.ProjectID == "" {
+		return nil
+	}
+	project, err := s.projectRepo.Get(ctx, p.ProjectID)
+	if err != nil {
+		return fmt.Errorf("get project: %w", err)
+	}
+	if project == nil {
+		return nil
+	}
+	kind := mapRepoTypeToProviderKind(project.RepoType)
+	if kind == "" {
+		return nil
+	}
+	cfg := providerConfigFromProject(project, kind)
+	if cfg.Token == "" {
+		return nil
+	}
+	repoInfo, ok := parseRepoFromURL(project.RepoURL)
+	if !ok {
+		return nil
+	}
+	repoInfo.URL = project.RepoURL
+	prov, err := scm.NewProvider(cfg)
+	if err != nil {
+		return err
+	}
+	if !prov.Capabilities().Has(scm.CapCommitStatus) {
+		return nil
+	}
+	r.status.Repo = repoInfo
+	return prov.ReportCommitStatus(ctx, r.status)
+}
+
+// runURL links a status to the run page, "" without an external URL.
+func (s *CommitStatusService) runURL(run *model.PipelineRun) string {
+	if s.externalURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/pipelines/%s/runs/%s", s.externalURL, run.PipelineID, run.RunID)
+}
@@ -0,0 +1,230 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/arcentrix/arcentra/pkg/safe"
+	"gorm.io/gorm"
+)
+
+// maxConcurrencyGroupLen is the longest group name that can be stored.
+const maxConcurrencyGroupLen = 255
+
+// Lease timings are variables so tests can shorten them.
+var (
+	// concurrencyLeaseTTL is how long a lease survives without renewal,
+	// i.e. how long a group stays blocked after its holder's replica died.
+	concurrencyLeaseTTL = 30 * time.Second
+	// concurrencyRenewInterval is how often a holder renews its lease and
+	// checks whether it was superseded.
+	concurrencyRenewInterval = 10 * time.Second
+	// concurrencyPollInterval is how often a waiting lease checks whether
+	// the group is free.
+	concurrencyPollInterval = 2 * time.Second
+)
+
+// ErrSuperseded is returned when a newer run of the concurrency group with
+// the cancel-in-progress policy took over while waiting.
+var ErrSuperseded = errors.New("superseded by a newer run of the concurrency group")
+
+// ConcurrencyService serialises pipeline runs and jobs that share a
+// concurrency group. Groups are held through leases in the database, so they
+// hold across control-plane replicas: the oldest unexpired lease of a group
+// holds it, the others wait their turn.
+type ConcurrencyService struct {
+	leaseRepo repo.IConcurrencyRepository
+}
+
+// NewConcurrencyService creates the concurrency group service.
+func NewConcurrencyService(leaseRepo repo.IConcurrencyRepository) *ConcurrencyService {
+	return &ConcurrencyService{leaseRepo: leaseRepo}
+}
+
+// NormalizeConcurrencyPolicy returns the policy to apply for a spec value,
+// defaulting to queue.
+func NormalizeConcurrencyPolicy(policy string) string {
+	if policy == model.ConcurrencyPolicyCancelInProgress {
+		return policy
+	}
+	return model.ConcurrencyPolicyQueue
+}
+
+// Acquire blocks until lease holds its group or ctx is done. With the
+// cancel-in-progress policy the older leases of the group are superseded
+// first. While held, onSuperseded is called once if a newer lease
+// supersedes this one or the lease is lost (it expired and was removed, so
+// another run may hold the group now); the holder is expected to cancel
+// itself and call release, which frees the group for the next lease.
+func (s *ConcurrencyService) Acquire(
+	ctx context.Context,
+	lease *model.ConcurrencyLease,
+	onSuperseded func(),
+) (release func(), err error) {
+	if len(lease.GroupKey) > maxConcurrencyGroupLen {
+		return nil, fmt.Errorf("concurrency group %.32q... longer than %d bytes", lease.GroupKey, maxConcurrencyGroupLen)
+	}
+	lease.Policy = NormalizeConcurrencyPolicy(lease.Policy)
+	lease.Held = 0
+	lease.Superseded = 0
+	lease.ExpiresAt = time.Now().Add(concurrencyLeaseTTL)
+	if err := s.leaseRepo.Create(ctx, lease); err != nil {
+		return nil, fmt.Errorf("create concurrency lease: %w", err)
+	}
+
+	if lease.Policy == model.ConcurrencyPolicyCancelInProgress {
+		n, err := s.leaseRepo.Supersede(ctx, lease.ProjectID, lease.GroupKey, lease.ID)
+		if err != nil {
+			s.drop(lease.ID)
+			return nil, fmt.Errorf("supersede concurrency group %s: %w", lease.GroupKey, err)
+		}
+		if n > 0 {
+			log.Infow("superseded older runs of concurrency group",
+				"group", lease.GroupKey, "projectId", lease.ProjectID, "runId", lease.RunID, "count", n)
+		}
+	}
+
+	ticker := time.NewTicker(concurrencyPollInterval)
+	defer ticker.Stop()
+	for waiting := false; ; waiting = true {
+		held, err := s.tryHold(ctx, lease)
+		if err != nil {
+			s.drop(lease.ID)
+			return nil, err
+		}
+		if held {
+			break
+		}
+		if !waiting {
+			log.Infow("waiting for concurrency group",
+				"group", lease.GroupKey, "projectId", lease.ProjectID, "runId", lease.RunID, "job", lease.JobName)
+		}
+		select {
+		case <-ctx.Done():
+			s.drop(lease.ID)
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	safe.Go(func() {
+		defer close(done)
+		s.keepAlive(lease, onSuperseded, stop)
+	})
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			<-done
+			s.drop(lease.ID)
+		})
+	}, nil
+}
+
+// tryHold renews a waiting lease and takes the group when no older lease
+// is left. Transient database errors are logged and retried by the caller.
+func (s *ConcurrencyService) tryHold(ctx context.Context, lease *model.ConcurrencyLease) (bool, error) {
+	now := time.Now()
+	cur, err := s.leaseRepo.Renew(ctx, lease.ID, now.Add(concurrencyLeaseTTL))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, fmt.Errorf("concurrency lease for group %s expired while waiting", lease.GroupKey)
+		}
+		log.Warnw("failed to renew concurrency lease", "group", lease.GroupKey, "error", err)
+		return false, nil
+	}
+	if cur.Superseded == 1 {
+		return false, ErrSuperseded
+	}
+	if _, err := s.leaseRepo.DeleteExpired(ctx, now); err != nil {
+		log.Warnw("failed to remove expired concurrency leases", "error", err)
+	}
+	head, err := s.leaseRepo.IsHead(ctx, lease, now)
+	if err != nil {
+		log.Warnw("failed to check concurrency group", "group", lease.GroupKey, "error", err)
+		return false, nil
+	}
+	if !head {
+		return false, nil
+	}
+	if err := s.leaseRepo.MarkHeld(ctx, lease.ID); err != nil {
+		log.Warnw("failed to mark concurrency lease held", "group", lease.GroupKey, "error", err)
+	}
+	return true, nil
+}
+
+// keepAlive renews a held lease until stop is closed and reports the first
+// time it is found superseded or lost. A lease is lost when it was removed
+// as expired or could not be renewed for longer than its TTL.
+func (s *ConcurrencyService) keepAlive(lease *model.ConcurrencyLease, onSuperseded func(), stop <-chan struct{}) {
+	ticker := time.NewTicker(concurrencyRenewInterval)
+	defer ticker.Stop()
+	renewedAt := time.Now()
+	notified := false
+	notify := func(msg string) {
+		if notified {
+			return
+		}
+		notified = true
+		log.Infow(msg,
+			"group", lease.GroupKey, "projectId", lease.ProjectID, "runId", lease.RunID, "job", lease.JobName)
+		if onSuperseded != nil {
+			onSuperseded()
+		}
+	}
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), concurrencyRenewInterval)
+		cur, err := s.leaseRepo.Renew(ctx, lease.ID, time.Now().Add(concurrencyLeaseTTL))
+		cancel()
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			notify("concurrency group holder lost its lease")
+		case err != nil:
+			log.Warnw("failed to renew concurrency lease", "group", lease.GroupKey, "runId", lease.RunID, "error", err)
+			if time.Since(renewedAt) >= concurrencyLeaseTTL {
+				notify("concurrency group holder lost its lease")
+			}
+		default:
+			renewedAt = time.Now()
+			if cur.Superseded == 1 {
+				notify("concurrency group holder superseded")
+			}
+		}
+	}
+}
+
+// drop deletes a lease, releasing or leaving its group.
+func (s *ConcurrencyService) drop(id uint64) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.leaseRepo.Delete(ctx, id); err != nil {
+		log.Warnw("failed to release concurrency lease", "leaseId", id, "error", err)
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"gorm.io/gorm"
+)
+
+// fakeLeaseRepo keeps concurrency leases in memory with the semantics of
+// repo.ConcurrencyRepo.
+type fakeLeaseRepo struct {
+	mu       sync.Mutex
+	nextID   uint64
+	leases   map[uint64]*model.ConcurrencyLease
+	renewErr error
+}
+
+func newFakeLeaseRepo() *fakeLeaseRepo {
+	return &fakeLeaseRepo{leases: map[uint64]*model.ConcurrencyLease{}}
+}
+
+func (r *fakeLeaseRepo) Create(_ context.Context, lease *model.ConcurrencyLease) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	lease.ID = r.nextID
+	stored := *lease
+	r.leases[lease.ID] = &stored
+	return nil
+}
+
+func (r *fakeLeaseRepo) Renew(_ context.Context, id uint64, until time.Time) (*model.ConcurrencyLease, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.renewErr != nil {
+		return nil, r.renewErr
+	}
+	l, ok := r.leases[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	l.ExpiresAt = until
+	cur := *l
+	return &cur, nil
+}
+
+func (r *fakeLeaseRepo) Supersede(_ context.Context, projectID, groupKey string, beforeID uint64) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for _, l := range r.leases {
+		if l.ProjectID == projectID && l.GroupKey == groupKey && l.ID < beforeID && l.Superseded == 0 {
+			l.Superseded = 1
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *fakeLeaseRepo) IsHead(_ context.Context, lease *model.ConcurrencyLease, now time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, l := range r.leases {
+		if l.ProjectID == lease.ProjectID && l.GroupKey == lease.GroupKey && l.ID < lease.ID && l.ExpiresAt.After(now) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (r *fakeLeaseRepo) MarkHeld(_ context.Context, id uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if l, ok := r.leases[id]; ok {
+		l.Held = 1
+	}
+	return nil
+}
+
+func (r *fakeLeaseRepo) Delete(_ context.Context, id uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.leases, id)
+	return nil
+}
+
+func (r *fakeLeaseRepo) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for id, l := range r.leases {
+		if !l.ExpiresAt.After(now) {
+			delete(r.leases, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *fakeLeaseRepo) setRenewErr(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.renewErr = err
+}
+
+// shortenLeaseTimings speeds up the lease loops for the duration of a test.
+func shortenLeaseTimings(t *testing.T) {
+	ttl, renew, poll := concurrencyLeaseTTL, concurrencyRenewInterval, concurrencyPollInterval
+	concurrencyLeaseTTL = 300 * time.Millisecond
+	concurrencyRenewInterval = 20 * time.Millisecond
+	concurrencyPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() {
+		concurrencyLeaseTTL, concurrencyRenewInterval, concurrencyPollInterval = ttl, renew, poll
+	})
+}
+
+func waitClosed(t *testing.T, ch <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+}
+
+func TestConcurrencyQueueWaitsForHead(t *testing.T) {
+	shortenLeaseTimings(t)
+	svc := NewConcurrencyService(newFakeLeaseRepo())
+	ctx := context.Background()
+
+	release1, err := svc.Acquire(ctx, &model.ConcurrencyLease{ProjectID: "p", GroupKey: "g", RunID: "r1"}, nil)
+	if err != nil {
+		t.Fatalf("first Acquire: %v", err)
+	}
+	// Another group is independent of g.
+	releaseOther, err := svc.Acquire(ctx, &model.ConcurrencyLease{ProjectID: "p", GroupKey: "other", RunID: "r0"}, nil)
+	if err != nil {
+		t.Fatalf("Acquire of another group: %v", err)
+	}
+	defer releaseOther()
+
+	acquired := make(chan struct{})
+	var release2 func()
+	go func() {
+		var err error
+		release2, err = svc.Acquire(ctx, &model.ConcurrencyLease{ProjectID: "p", GroupKey: "g", RunID: "r2"}, nil)
+		if err != nil {
+			t.Errorf("second Acquire: %v", err)
+		}
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second lease held the group while the first still held it")
+	case <-time.After(100 * time.Millisecond):
+	}
+	release1()
+	waitClosed(t, acquired, "the second lease to take the group")
+	if release2 != nil {
+		release2()
+	}
+}
+
+func TestConcurrencyQueueWaiterGivesUpOnCancel(t *testing.T) {
+	shortenLeaseTimings(t)
+	leases := newFakeLeaseRepo()
+	svc := NewConcurrencyService(leases)
+
+	release, err := svc.Acquire(context.Background(), &model.ConcurrencyLease{ProjectID: "p", GroupKey: "g"}, nil)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := svc.Acquire(ctx, &model.ConcurrencyLease{ProjectID: "p", GroupKey: "g"}, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire = %v, want context.DeadlineExceeded", err)
+	}
+	leases.mu.Lock()
+	defer leases.mu.Unlock()
+	if len(leases.leases) != 1 {
+		t.Fatalf("%d leases left, want only the holder's", len(leases.leases))
+	}
+}
+
+func TestConcurrencyCancelInProgressSupersedesHolder(t *testing.T) {
+	shortenLeaseTimings(t)
+	svc := NewConcurrencyService(newFakeLeaseRepo())
+	ctx := context.Background()
+
+	superseded := make(chan struct{})
+	var release1 func()
+	release1, err := svc.Acquire(ctx, &model.ConcurrencyLease{ProjectID: "p", GroupKey: "g", RunID: "r1"}, func() {
+		close(superseded)
+		// The holder cancels itself and releases the group.
+		go release1()
+	})
+	if err != nil {
+		t.Fatalf("first Acquire: %v", err)
+	}
+
+	release2, err := svc.Acquire(ctx, &model.ConcurrencyLease{
+		ProjectID: "p", GroupKey: "g", RunID: "r2", Policy: model.ConcurrencyPolicyCancelInProgress,
+	}, nil)
+	if err != nil {
+		t.Fatalf("second Acquire: %v", err)
+	}
+	defer release2()
+	waitClosed(t, superseded, "the first holder to be superseded")
+}
+
+func TestConcurrencyWaiterSupersededWhileQueued(t *testing.T) {
+	shortenLeaseTimings(t)
+	leases := newFakeLeaseRepo()
+	svc := NewConcurrencyService(leases)
+	ctx := context.Background()
+
+	release, err := svc.Acquire(ctx, &model.ConcurrencyLease{ProjectID: "p", GroupKey: "g"}, nil)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	defer release()
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := svc.Acquire(ctx, &model.ConcurrencyLease{ProjectID: "p", GroupKey: "g"}, nil)
+		errCh <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	if _, err := leases.Supersede(ctx, "p", "g", 1<<62); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, ErrSuperseded) {
+			t.Fatalf("queued Acquire = %v, want ErrSuperseded", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("queued lease did not notice it was superseded")
+	}
+}
+
+func TestConcurrencyHolderNotifiedWhenLeaseRemoved(t *testing.T) {
+	shortenLeaseTimings(t)
+	leases := newFakeLeaseRepo()
+	svc := NewConcurrencyService(leases)
+
+	lost := make(chan struct{})
+	lease := &model.ConcurrencyLease{ProjectID: "p", GroupKey: "g"}
+	release, err := svc.Acquire(context.Background(), lease, func() { close(lost) })
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	defer release()
+
+	// Another replica removed the lease as expired.
+	_ = leases.Delete(context.Background(), lease.ID)
+	waitClosed(t, lost, "the holder to be told its lease was lost")
+}
+
+func TestConcurrencyHolderNotifiedWhenRenewalsFailPastTTL(t *testing.T) {
+	shortenLeaseTimings(t)
+	leases := newFakeLeaseRepo()
+	svc := NewConcurrencyService(leases)
+
+	lost := make(chan struct{})
+	release, err := svc.Acquire(context.Background(), &model.ConcurrencyLease{ProjectID: "p", GroupKey: "g"}, func() { close(lost) })
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	defer release()
+
+	leases.setRenewErr(errors.New("connection refused"))
+	waitClosed(t, lost, "the holder to be told its lease was lost")
+}
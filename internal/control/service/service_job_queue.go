@@ -0,0 +1,561 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"github.com/arcentrix/arcentra/pkg/fairshare"
+	"github.com/arcentrix/arcentra/pkg/id"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/arcentrix/arcentra/pkg/nova"
+	"github.com/arcentrix/arcentra/pkg/safe"
+	"github.com/arcentrix/arcentra/pkg/taskqueue"
+	"github.com/bytedance/sonic"
+	"gorm.io/gorm"
+)
+
+const (
+	// jobQueueInterval is how often the queue is scheduled when nothing
+	// wakes it earlier.
+	jobQueueInterval = 5 * time.Second
+	// jobQueueETAWindow is the window of finished jobs the wait estimate
+	// averages over.
+	jobQueueETAWindow = 24 * time.Hour
+	// jobQueueLeaseTTL bounds how long a replica that died mid-pass keeps
+	// the other replicas from scheduling.
+	jobQueueLeaseTTL = 30 * time.Second
+)
+
+// ErrJobNotQueued is returned when a job run has no queue entry, either
+// because it is not an agent job or because it already finished.
+var ErrJobNotQueued = errors.New("job run is not queued")
+
+// JobQueueService schedules agent jobs. Jobs wait in a persistent queue and
+// are dispatched to the task queue while the enabled online agents matching
+// their agent selector have free slots: by priority first, then sharing the
+// slots fairly between projects, never exceeding a project's concurrency cap.
+// Replicas take a lease in the database before each pass so only one of
+// them dispatches at a time.
+type JobQueueService struct {
+	queueRepo  repo.IJobQueueRepository
+	jobRunRepo repo.IJobRunRepository
+	taskQueue  nova.TaskQueue
+	holder     string // identifies this replica's scheduling lease
+
+	mu     sync.Mutex // serialises scheduling passes within the replica
+	wake   chan struct{}
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewJobQueueService creates the scheduler. Nothing is dispatched until a
+// task queue is set and the scheduler is started.
+func NewJobQueueService(queueRepo repo.IJobQueueRepository, jobRunRepo repo.IJobRunRepository) *JobQueueService {
+	return &JobQueueService{
+		queueRepo:  queueRepo,
+		jobRunRepo: jobRunRepo,
+		holder:     id.GetUild(),
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// SetTaskQueue sets the task queue jobs are dispatched to.
+func (s *JobQueueService) SetTaskQueue(tq nova.TaskQueue) {
+	s.taskQueue = tq
+}
+
+// Enqueue queues a job. The entry carries the task payload that is
+// dispatched once the job is scheduled.
+func (s *JobQueueService) Enqueue(ctx context.Context, entry *model.JobQueueEntry) error {
+	if entry.Priority < model.JobPriorityHighest || entry.Priority > model.JobPriorityLowest {
+		entry.Priority = model.JobPriorityDefault
+	}
+	entry.EnqueuedAt = time.Now()
+	entry.DispatchedAt = nil
+	if err := s.queueRepo.Create(ctx, entry); err != nil {
+		return fmt.Errorf("enqueue job run %s: %w", entry.JobRunID, err)
+	}
+	s.Wake()
+	return nil
+}
+
+// Cancel removes a job that is still waiting and marks its job run
+// cancelled. Jobs already dispatched are left to the agent.
+func (s *JobQueueService) Cancel(ctx context.Context, jobRunID string) error {
+	removed, err := s.queueRepo.DeleteWaiting(ctx, jobRunID)
+	if err != nil {
+		return fmt.Errorf("remove job run %s from queue: %w", jobRunID, err)
+	}
+	if !removed {
+		return nil
+	}
+	return s.jobRunRepo.UpdateByJobRunID(ctx, jobRunID, map[string]any{
+		"status":        model.JobRunStatusCancelled,
+		"error_message": "cancelled while queued",
+		"end_time":      time.Now(),
+	})
+}
+
+// Wake requests a scheduling pass, e.g. after a job was queued.
+func (s *JobQueueService) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start runs the scheduling loop until Stop.
+func (s *JobQueueService) Start() {
+	if s.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	safe.Go(func() {
+		defer close(s.done)
+		ticker := time.NewTicker(jobQueueInterval)
+		defer ticker.Stop()
+		for {
+			s.Schedule(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-s.wake:
+			}
+		}
+	})
+}
+
+// Stop stops the scheduling loop. Queued jobs stay in the queue.
+func (s *JobQueueService) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+	s.cancel = nil
+}
+
+// Schedule runs one scheduling pass and returns the number of jobs
+// dispatched.
+func (s *JobQueueService) Schedule(ctx context.Context) int {
+	if s.taskQueue == nil {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	claimed, err := s.queueRepo.ClaimScheduler(ctx, s.holder, time.Now().Add(jobQueueLeaseTTL))
+	if err != nil {
+		log.Warnw("failed to claim job scheduler lease", "error", err)
+		return 0
+	}
+	if !claimed {
+		return 0
+	}
+	defer func() {
+		if err := s.queueRepo.ReleaseScheduler(context.Background(), s.holder); err != nil {
+			log.Warnw("failed to release job scheduler lease", "error", err)
+		}
+	}()
+
+	if _, err := s.queueRepo.Reap(ctx); err != nil {
+		log.Warnw("failed to reap job queue", "error", err)
+	}
+	agents, err := s.queueRepo.ListAgents(ctx)
+	if err != nil {
+		log.Warnw("failed to list agents", "error", err)
+		return 0
+	}
+	dispatched, err := s.queueRepo.ListDispatched(ctx)
+	if err != nil {
+		log.Warnw("failed to list dispatched jobs", "error", err)
+		return 0
+	}
+	if len(dispatched) >= totalSlots(agents) {
+		return 0
+	}
+	waiting, err := s.queueRepo.ListWaiting(ctx, true)
+	if err != nil {
+		log.Warnw("failed to list queued jobs", "error", err)
+		return 0
+	}
+	if len(waiting) == 0 {
+		return 0
+	}
+
+	state, err := s.loadState(ctx, waiting, dispatched)
+	if err != nil {
+		log.Warnw("failed to load project limits", "error", err)
+		return 0
+	}
+	pools := newAgentPools(agents, waiting, dispatched)
+	byID := make(map[string]*model.JobQueueEntry, len(waiting))
+	for _, e := range waiting {
+		byID[e.JobRunID] = e
+	}
+
+	sent := 0
+	for _, it := range fairshare.Order(queueItems(waiting), state) {
+		if ctx.Err() != nil || pools.full() {
+			break
+		}
+		if limit := state.Limits[it.Group]; limit > 0 && state.Running[it.Group] >= limit {
+			continue
+		}
+		entry := byID[it.ID]
+		pool := pools.pool(entry.AgentSelector)
+		if !pools.fits(pool) {
+			continue
+		}
+		if s.dispatch(ctx, entry) {
+			pools.take(pool)
+			state.Running[it.Group]++
+			sent++
+		}
+	}
+	if sent > 0 {
+		log.Infow("jobs dispatched", "count", sent, "capacity", pools.capacity(), "inFlight", len(dispatched)+sent)
+	}
+	return sent
+}
+
+// dispatch claims an entry and sends its payload to the task queue. The
+// claim is released when sending fails so the job is retried.
+func (s *JobQueueService) dispatch(ctx context.Context, entry *model.JobQueueEntry) bool {
+	claimed, err := s.queueRepo.MarkDispatched(ctx, entry.JobRunID, time.Now())
+	if err != nil || !claimed {
+		if err != nil {
+			log.Warnw("failed to claim queued job", "jobRunId", entry.JobRunID, "error", err)
+		}
+		return false
+	}
+	_, err = s.taskQueue.Enqueue(&nova.Task{
+		Type:    taskqueue.TaskTypeJobRun,
+		Payload: entry.Payload,
+	}, nova.Queue("DEFAULT"))
+	if err != nil {
+		log.Warnw("failed to dispatch job run", "jobRunId", entry.JobRunID, "error", err)
+		if err := s.queueRepo.ResetDispatched(context.Background(), entry.JobRunID); err != nil {
+			log.Errorw("failed to requeue job run", "jobRunId", entry.JobRunID, "error", err)
+		}
+		return false
+	}
+	log.Infow("job run dispatched",
+		"jobRunId", entry.JobRunID,
+		"projectId", entry.ProjectID,
+		"priority", entry.Priority,
+		"waited", time.Since(entry.EnqueuedAt).Round(time.Second),
+	)
+	return true
+}
+
+// List returns the waiting jobs in dispatch order with their estimated
+// wait, optionally only those of one project.
+func (s *JobQueueService) List(ctx context.Context, projectID string) (*model.JobQueueResp, error) {
+	snap, err := s.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &model.JobQueueResp{JobQueueStats: snap.stats, List: []*model.QueuedJob{}}
+	for _, job := range snap.ordered {
+		if projectID != "" && job.ProjectID != projectID {
+			continue
+		}
+		resp.List = append(resp.List, job)
+	}
+	resp.Total = len(resp.List)
+	return resp, nil
+}
+
+// Position returns the queue position and estimated wait of a job run.
+// Dispatched jobs waiting to be picked up by an agent have position 0.
+func (s *JobQueueService) Position(ctx context.Context, jobRunID string) (*model.QueuedJob, error) {
+	entry, err := s.queueRepo.Get(ctx, jobRunID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrJobNotQueued
+		}
+		return nil, err
+	}
+	if entry.DispatchedAt != nil {
+		return queuedJob(entry), nil
+	}
+	snap, err := s.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range snap.ordered {
+		if job.JobRunID == jobRunID {
+			return job, nil
+		}
+	}
+	// dispatched or removed between the two reads
+	return nil, ErrJobNotQueued
+}
+
+// jobQueueSnapshot is the queue in dispatch order.
+type jobQueueSnapshot struct {
+	stats   model.JobQueueStats
+	ordered []*model.QueuedJob
+}
+
+func (s *JobQueueService) snapshot(ctx context.Context) (*jobQueueSnapshot, error) {
+	agents, err := s.queueRepo.ListAgents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list agents: %w", err)
+	}
+	dispatched, err := s.queueRepo.ListDispatched(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list dispatched jobs: %w", err)
+	}
+	waiting, err := s.queueRepo.ListWaiting(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("list queued jobs: %w", err)
+	}
+	state, err := s.loadState(ctx, waiting, dispatched)
+	if err != nil {
+		return nil, fmt.Errorf("load project limits: %w", err)
+	}
+	avg, err := s.queueRepo.AverageJobDuration(ctx, time.Now().Add(-jobQueueETAWindow))
+	if err != nil {
+		log.Warnw("failed to average job duration", "error", err)
+	}
+
+	pools := newAgentPools(agents, waiting, dispatched)
+	capacity := pools.capacity()
+	byID := make(map[string]*model.JobQueueEntry, len(waiting))
+	for _, e := range waiting {
+		byID[e.JobRunID] = e
+	}
+	snap := &jobQueueSnapshot{
+		stats: model.JobQueueStats{
+			Capacity: capacity,
+			InFlight: len(dispatched),
+			Waiting:  len(waiting),
+		},
+		ordered: make([]*model.QueuedJob, 0, len(waiting)),
+	}
+	for i, it := range fairshare.Order(queueItems(waiting), state) {
+		entry := byID[it.ID]
+		job := queuedJob(entry)
+		job.Position = i + 1
+		job.ETASeconds = -1
+		// jobs no online agent can run get no estimate
+		if pools.pool(entry.AgentSelector).size > 0 && capacity > 0 && avg > 0 {
+			wait := fairshare.EstimateWait(job.Position, len(dispatched), capacity, avg)
+			job.ETASeconds = int64(wait / time.Second)
+		}
+		if limit := state.Limits[job.ProjectID]; limit > 0 && state.Running[job.ProjectID] >= limit {
+			job.LimitReached = true
+		}
+		snap.ordered = append(snap.ordered, job)
+	}
+	return snap, nil
+}
+
+// loadState counts the dispatched jobs per project and loads the caps of
+// the projects involved.
+func (s *JobQueueService) loadState(ctx context.Context, waiting, dispatched []*model.JobQueueEntry) (fairshare.State, error) {
+	running := make(map[string]int)
+	for _, e := range dispatched {
+		running[e.ProjectID]++
+	}
+	seen := make(map[string]struct{})
+	projectIDs := make([]string, 0)
+	for _, e := range waiting {
+		if _, ok := seen[e.ProjectID]; ok || e.ProjectID == "" {
+			continue
+		}
+		seen[e.ProjectID] = struct{}{}
+		projectIDs = append(projectIDs, e.ProjectID)
+	}
+	limits, err := s.queueRepo.ProjectLimits(ctx, projectIDs)
+	if err != nil {
+		return fairshare.State{}, err
+	}
+	return fairshare.State{Running: running, Limits: limits}, nil
+}
+
+// agentPool is the set of agents a selector matches and the slots used on
+// them.
+type agentPool struct {
+	members  []bool // indexed like agentPools.slots
+	size     int
+	capacity int
+	used     int
+}
+
+// agentPools tracks the free slots per agent selector. A job may run on any
+// agent its selector matches, so it is counted against every pool whose
+// agents include all of those; a job fits when each such pool has room,
+// the pool of all agents included.
+type agentPools struct {
+	slots []int
+	tags  []map[string]string
+	pools map[string]*agentPool
+	all   *agentPool
+}
+
+// newAgentPools builds the pools of the selectors of the given entries and
+// counts the dispatched ones against them.
+func newAgentPools(agents []*model.Agent, waiting, dispatched []*model.JobQueueEntry) *agentPools {
+	p := &agentPools{pools: make(map[string]*agentPool)}
+	for _, a := range agents {
+		slots := a.MaxConcurrentJobs
+		if slots <= 0 {
+			slots = 1
+		}
+		labels := make(map[string]string)
+		if len(a.Labels) > 0 {
+			if err := sonic.Unmarshal(a.Labels, &labels); err != nil {
+				log.Warnw("invalid agent labels", "agentId", a.AgentID, "error", err)
+			}
+		}
+		p.slots = append(p.slots, slots)
+		p.tags = append(p.tags, labels)
+	}
+	p.all = p.pool("")
+	for _, e := range waiting {
+		p.pool(e.AgentSelector)
+	}
+	for _, e := range dispatched {
+		p.pool(e.AgentSelector)
+	}
+	for _, e := range dispatched {
+		p.take(p.pool(e.AgentSelector))
+	}
+	return p
+}
+
+// pool returns the pool of a selector, creating it on first use. A
+// selector that cannot be parsed matches no agent.
+func (p *agentPools) pool(selector string) *agentPool {
+	if pool, ok := p.pools[selector]; ok {
+		return pool
+	}
+	var sel *model.JobAgentSelector
+	valid := true
+	if selector != "" {
+		if err := sonic.UnmarshalString(selector, &sel); err != nil {
+			log.Warnw("invalid job agent selector", "selector", selector, "error", err)
+			valid = false
+		}
+	}
+	pool := &agentPool{members: make([]bool, len(p.slots))}
+	for i, labels := range p.tags {
+		if valid && sel.Matches(labels) {
+			pool.members[i] = true
+			pool.size++
+			pool.capacity += p.slots[i]
+		}
+	}
+	p.pools[selector] = pool
+	return pool
+}
+
+// fits reports whether a job of the pool can be dispatched now.
+func (p *agentPools) fits(pool *agentPool) bool {
+	if pool.size == 0 {
+		return false
+	}
+	for _, other := range p.pools {
+		if other.contains(pool) && other.used >= other.capacity {
+			return false
+		}
+	}
+	return true
+}
+
+// take counts a dispatched job of the pool.
+func (p *agentPools) take(pool *agentPool) {
+	for _, other := range p.pools {
+		if other.contains(pool) {
+			other.used++
+		}
+	}
+}
+
+// full reports whether every slot is used.
+func (p *agentPools) full() bool {
+	return p.all.used >= p.all.capacity
+}
+
+// capacity is the number of slots of all agents.
+func (p *agentPools) capacity() int {
+	return p.all.capacity
+}
+
+// contains reports whether the pool's agents include all of other's. An
+// empty pool is in none, so jobs no agent can run are not counted.
+func (a *agentPool) contains(other *agentPool) bool {
+	if other.size == 0 {
+		return false
+	}
+	for i, in := range other.members {
+		if in && !a.members[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// totalSlots sums the concurrent job slots of the agents.
+func totalSlots(agents []*model.Agent) int {
+	total := 0
+	for _, a := range agents {
+		if a.MaxConcurrentJobs > 0 {
+			total += a.MaxConcurrentJobs
+		} else {
+			total++
+		}
+	}
+	return total
+}
+
+func queueItems(entries []*model.JobQueueEntry) []fairshare.Item {
+	items := make([]fairshare.Item, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, fairshare.Item{
+			ID:         e.JobRunID,
+			Group:      e.ProjectID,
+			Priority:   e.Priority,
+			EnqueuedAt: e.EnqueuedAt,
+		})
+	}
+	return items
+}
+
+func queuedJob(e *model.JobQueueEntry) *model.QueuedJob {
+	return &model.QueuedJob{
+		JobRunID:      e.JobRunID,
+		ProjectID:     e.ProjectID,
+		PipelineID:    e.PipelineID,
+		PipelineRunID: e.PipelineRunID,
+		JobName:       e.JobName,
+		Priority:      e.Priority,
+		EnqueuedAt:    e.EnqueuedAt,
+		DispatchedAt:  e.DispatchedAt,
+	}
+}
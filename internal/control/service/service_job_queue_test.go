@@ -0,0 +1,204 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"github.com/arcentrix/arcentra/pkg/nova"
+	"gorm.io/datatypes"
+)
+
+// fakeQueueRepo keeps the queue and the scheduling lease in memory. Replicas
+// in a test share one.
+type fakeQueueRepo struct {
+	repo.IJobQueueRepository
+	agents  []*model.Agent
+	entries []*model.JobQueueEntry
+	limits  map[string]int
+
+	holder    string
+	expiresAt time.Time
+}
+
+func (f *fakeQueueRepo) Reap(context.Context) (int64, error) { return 0, nil }
+
+func (f *fakeQueueRepo) ListAgents(context.Context) ([]*model.Agent, error) { return f.agents, nil }
+
+func (f *fakeQueueRepo) ListWaiting(context.Context, bool) ([]*model.JobQueueEntry, error) {
+	var out []*model.JobQueueEntry
+	for _, e := range f.entries {
+		if e.DispatchedAt == nil {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeQueueRepo) ListDispatched(context.Context) ([]*model.JobQueueEntry, error) {
+	var out []*model.JobQueueEntry
+	for _, e := range f.entries {
+		if e.DispatchedAt != nil {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeQueueRepo) MarkDispatched(_ context.Context, jobRunID string, at time.Time) (bool, error) {
+	for _, e := range f.entries {
+		if e.JobRunID == jobRunID && e.DispatchedAt == nil {
+			e.DispatchedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeQueueRepo) ProjectLimits(context.Context, []string) (map[string]int, error) {
+	limits := make(map[string]int)
+	for k, v := range f.limits {
+		limits[k] = v
+	}
+	return limits, nil
+}
+
+func (f *fakeQueueRepo) ClaimScheduler(_ context.Context, holder string, until time.Time) (bool, error) {
+	if f.holder != holder && time.Now().Before(f.expiresAt) {
+		return false, nil
+	}
+	f.holder, f.expiresAt = holder, until
+	return true, nil
+}
+
+func (f *fakeQueueRepo) ReleaseScheduler(_ context.Context, holder string) error {
+	if f.holder == holder {
+		f.expiresAt = time.Time{}
+	}
+	return nil
+}
+
+func (f *fakeQueueRepo) queue(jobRunID, projectID, selector string) {
+	f.entries = append(f.entries, &model.JobQueueEntry{
+		JobRunID:      jobRunID,
+		ProjectID:     projectID,
+		Priority:      model.JobPriorityDefault,
+		EnqueuedAt:    time.Now().Add(time.Duration(len(f.entries)) * time.Millisecond),
+		AgentSelector: selector,
+	})
+}
+
+func (f *fakeQueueRepo) dispatched() []string {
+	var out []string
+	for _, e := range f.entries {
+		if e.DispatchedAt != nil {
+			out = append(out, e.JobRunID)
+		}
+	}
+	return out
+}
+
+type fakeTaskQueue struct {
+	nova.TaskQueue
+	sent int
+}
+
+func (q *fakeTaskQueue) Enqueue(*nova.Task, ...nova.Option) (*nova.Result, error) {
+	q.sent++
+	return &nova.Result{}, nil
+}
+
+func testAgent(id string, slots int, labels string) *model.Agent {
+	return &model.Agent{AgentID: id, MaxConcurrentJobs: slots, Labels: datatypes.JSON(labels)}
+}
+
+const gpuSelector = `{"matchLabels":{"gpu":"true"}}`
+
+func newTestJobQueue(queue *fakeQueueRepo) *JobQueueService {
+	s := NewJobQueueService(queue, nil)
+	s.SetTaskQueue(&fakeTaskQueue{})
+	return s
+}
+
+func TestScheduleSizesSlotsPerSelector(t *testing.T) {
+	queue := &fakeQueueRepo{agents: []*model.Agent{
+		testAgent("gpu", 1, `{"gpu":"true"}`),
+		testAgent("cpu", 3, `{}`),
+	}}
+	queue.queue("gpu-1", "p1", gpuSelector)
+	queue.queue("gpu-2", "p1", gpuSelector)
+	queue.queue("any-1", "p1", "")
+	queue.queue("arm-1", "p1", `{"matchLabels":{"arch":"arm64"}}`)
+
+	if sent := newTestJobQueue(queue).Schedule(context.Background()); sent != 2 {
+		t.Fatalf("dispatched %d jobs (%v), want 2", sent, queue.dispatched())
+	}
+	got := queue.dispatched()
+	if len(got) != 2 || got[0] != "gpu-1" || got[1] != "any-1" {
+		t.Fatalf("dispatched %v, want [gpu-1 any-1]", got)
+	}
+}
+
+func TestScheduleCountsDispatchedAgainstSupersetPools(t *testing.T) {
+	queue := &fakeQueueRepo{agents: []*model.Agent{
+		testAgent("gpu", 2, `{"gpu":"true"}`),
+	}}
+	queue.queue("gpu-1", "p1", gpuSelector)
+	queue.queue("any-1", "p1", "")
+	queue.queue("any-2", "p1", "")
+	now := time.Now()
+	queue.entries[0].DispatchedAt = &now
+
+	if sent := newTestJobQueue(queue).Schedule(context.Background()); sent != 1 {
+		t.Fatalf("dispatched %d jobs, want 1: the gpu job holds one of the two slots", sent)
+	}
+}
+
+func TestScheduleRespectsProjectLimit(t *testing.T) {
+	queue := &fakeQueueRepo{
+		agents: []*model.Agent{testAgent("a", 4, `{}`)},
+		limits: map[string]int{"p1": 1},
+	}
+	queue.queue("p1-1", "p1", "")
+	queue.queue("p1-2", "p1", "")
+	queue.queue("p2-1", "p2", "")
+
+	if sent := newTestJobQueue(queue).Schedule(context.Background()); sent != 2 {
+		t.Fatalf("dispatched %v, want one job of each project", queue.dispatched())
+	}
+}
+
+func TestScheduleRequiresLease(t *testing.T) {
+	queue := &fakeQueueRepo{agents: []*model.Agent{testAgent("a", 4, `{}`)}}
+	queue.queue("j1", "p1", "")
+	queue.holder, queue.expiresAt = "other-replica", time.Now().Add(time.Minute)
+
+	s := newTestJobQueue(queue)
+	if sent := s.Schedule(context.Background()); sent != 0 {
+		t.Fatalf("dispatched %d jobs while another replica holds the lease", sent)
+	}
+
+	queue.expiresAt = time.Now().Add(-time.Second)
+	if sent := s.Schedule(context.Background()); sent != 1 {
+		t.Fatalf("dispatched %d jobs after the lease expired, want 1", sent)
+	}
+	if queue.holder != s.holder || time.Now().Before(queue.expiresAt) {
+		t.Fatal("lease not released after the pass")
+	}
+}
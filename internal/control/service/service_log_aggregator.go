@@ -20,7 +20,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/arcentrix/arcentra/internal/control/model"
 	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/arcentrix/arcentra/pkg/logstream"
 	"github.com/arcentrix/arcentra/pkg/safe"
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
@@ -35,11 +37,14 @@ type LogAggregator struct {
 	bufferSize    int
 	flushInterval time.Duration
 	tableName     string
+	archiver      *LogArchiver      // 已归档步骤的日志从对象存储按范围读取，未启用时为 nil
+	indexer       *LogSearchService // 落库后的日志增量写入全文索引，未启用时为 nil
 }
 
 // LogStream 日志流
 type LogStream struct {
 	stepRunID string
+	runID     string // 所属流水线 Run，日志写入与广播前按该 Run 的密钥掩码
 	buffer    []*LogEntry
 	mu        sync.Mutex
 	lastFlush time.Time
@@ -73,6 +78,16 @@ func NewLogAggregator(_ *redis.Client, mysql *gorm.DB) *LogAggregator {
 	return la
 }
 
+// SetArchiver 设置日志归档器，已归档步骤的日志改为从归档读取
+func (la *LogAggregator) SetArchiver(archiver *LogArchiver) {
+	la.archiver = archiver
+}
+
+// SetIndexer 设置日志检索服务，每批日志落库后写入全文索引
+func (la *LogAggregator) SetIndexer(indexer *LogSearchService) {
+	la.indexer = indexer
+}
+
 // PushLog 推送日志到聚合器
 func (la *LogAggregator) PushLog(entry *LogEntry) error {
 	la.mu.RLock()
@@ -83,6 +98,7 @@ func (la *LogAggregator) PushLog(entry *LogEntry) error {
 		// 创建新的流
 		stream = &LogStream{
 			stepRunID: entry.StepRunID,
+			runID:     la.pipelineRunID(entry.StepRunID),
 			buffer:    make([]*LogEntry, 0, la.bufferSize),
 			lastFlush: time.Now(),
 		}
@@ -94,6 +110,8 @@ func (la *LogAggregator) PushLog(entry *LogEntry) error {
 		go la.periodicFlush(entry.StepRunID)
 	}
 
+	entry.Content = logstream.LookupMasker(stream.runID).Mask(entry.Content)
+
 	stream.mu.Lock()
 	defer stream.mu.Unlock()
 
@@ -116,6 +134,26 @@ func (la *LogAggregator) PushLog(entry *LogEntry) error {
 	return nil
 }
 
+// pipelineRunID 返回步骤所属的流水线 Run ID，查询失败时返回空（不掩码）
+func (la *LogAggregator) pipelineRunID(stepRunID string) string {
+	if la.mysql == nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var runIDs []string
+	err := la.mysql.WithContext(ctx).
+		Model(&model.StepRun{}).
+		Where("step_run_id = ?", stepRunID).
+		Limit(1).
+		Pluck("pipeline_run_id", &runIDs).Error
+	if err != nil || len(runIDs) == 0 {
+		log.Warnw("lookup pipeline run of step run failed", "stepRunId", stepRunID, "error", err)
+		return ""
+	}
+	return runIDs[0]
+}
+
 // PushBatch 批量推送日志
 func (la *LogAggregator) PushBatch(entries []*LogEntry) error {
 	for _, entry := range entries {
@@ -143,6 +181,11 @@ func (la *LogAggregator) flushStream(stream *LogStream) {
 		// 写入 MySQL
 		if err := la.writeToMySQL(logs); err != nil {
 			log.Errorw("failed to write logs to mysql", "logCount", len(logs), "error", err)
+			return
+		}
+		// 仅索引已落库的日志，检索结果总能定位到对应行
+		if la.indexer != nil {
+			la.indexer.IndexLogs(logs)
 		}
 	})
 }
@@ -255,8 +298,20 @@ func (la *LogAggregator) CloseStream(stepRunID string) error {
 	return nil
 }
 
-// GetLogsByStepRunID 从 MySQL 获取步骤执行日志
+// GetLogsByStepRunID 获取步骤执行日志，已归档的步骤从对象存储读取，否则从 MySQL 读取
 func (la *LogAggregator) GetLogsByStepRunID(stepRunID string, fromLine int32, limit int) ([]*LogEntry, error) {
+	if la.archiver != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		logs, archived, err := la.archiver.ReadRange(ctx, stepRunID, fromLine, limit)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("read archived logs: %w", err)
+		}
+		if archived {
+			return logs, nil
+		}
+	}
+
 	if la.mysql == nil {
 		return nil, fmt.Errorf("mysql client is nil")
 	}
@@ -0,0 +1,317 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	steprunv1 "github.com/arcentrix/arcentra/api/steprun/v1"
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/shared/storage"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/bytedance/sonic"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultLogArchiveChunkLines is the number of log lines per archive chunk.
+	DefaultLogArchiveChunkLines = 5000
+	// DefaultLogArchiveDelay leaves late log lines time to land before a
+	// finished step is archived.
+	DefaultLogArchiveDelay = 5 * time.Minute
+	// DefaultLogArchiveRetention is how long the database rows of an archived
+	// step are kept.
+	DefaultLogArchiveRetention = 24 * time.Hour
+
+	logArchivePrefix    = "logs"
+	logArchiveBatchSize = 100
+)
+
+// LogArchiver compacts the logs of finished step runs into gzip chunks in
+// object storage, serves line ranges from them and purges the archived rows
+// from the database once the retention has passed.
+type LogArchiver struct {
+	db         *gorm.DB
+	storage    storage.IStorage
+	table      string
+	chunkLines int
+	delay      time.Duration
+	retention  time.Duration
+}
+
+// NewLogArchiver creates an archiver. Non-positive settings fall back to the
+// defaults.
+func NewLogArchiver(db *gorm.DB, st storage.IStorage, chunkLines int, delay, retention time.Duration) *LogArchiver {
+	if chunkLines <= 0 {
+		chunkLines = DefaultLogArchiveChunkLines
+	}
+	if delay <= 0 {
+		delay = DefaultLogArchiveDelay
+	}
+	if retention <= 0 {
+		retention = DefaultLogArchiveRetention
+	}
+	return &LogArchiver{
+		db:         db,
+		storage:    st,
+		table:      "step_run_logs",
+		chunkLines: chunkLines,
+		delay:      delay,
+		retention:  retention,
+	}
+}
+
+// ArchiveFinished archives the step runs that finished before the archive
+// delay and have no archive yet. Returns the number of step runs archived.
+func (a *LogArchiver) ArchiveFinished(ctx context.Context) int {
+	if a.db == nil || a.storage == nil {
+		return 0
+	}
+	var stepRunIDs []string
+	err := a.db.WithContext(ctx).
+		Table("step_run AS sr").
+		Select("sr.step_run_id").
+		Joins("LEFT JOIN step_run_log_archive AS a ON a.step_run_id = sr.step_run_id").
+		Where("sr.status IN ?", []steprunv1.StepRunStatus{
+			steprunv1.StepRunStatus_STEP_RUN_STATUS_SUCCESS,
+			steprunv1.StepRunStatus_STEP_RUN_STATUS_FAILED,
+			steprunv1.StepRunStatus_STEP_RUN_STATUS_CANCELLED,
+			steprunv1.StepRunStatus_STEP_RUN_STATUS_TIMEOUT,
+		}).
+		Where("sr.end_time < ?", time.Now().Add(-a.delay)).
+		Where("a.id IS NULL").
+		Order("sr.end_time ASC").
+		Limit(logArchiveBatchSize).
+		Pluck("sr.step_run_id", &stepRunIDs).Error
+	if err != nil {
+		log.Warnw("failed to query step runs to archive", "error", err)
+		return 0
+	}
+
+	archived := 0
+	for _, stepRunID := range stepRunIDs {
+		if err := a.Archive(ctx, stepRunID); err != nil {
+			log.Warnw("failed to archive step run logs", "stepRunId", stepRunID, "error", err)
+			continue
+		}
+		archived++
+	}
+	if archived > 0 {
+		log.Infow("step run logs archived", "count", archived)
+	}
+	return archived
+}
+
+// Archive compacts the logs of a step run into object storage and records
+// the line index. The database rows are left for PurgeExpired.
+func (a *LogArchiver) Archive(ctx context.Context, stepRunID string) error {
+	entries, err := a.readRows(ctx, stepRunID)
+	if err != nil {
+		return err
+	}
+
+	archive := &model.StepRunLogArchive{
+		StepRunID:  stepRunID,
+		TotalLines: len(entries),
+		Chunks:     []model.LogArchiveChunk{},
+		ArchivedAt: time.Now(),
+	}
+	for start := 0; start < len(entries); start += a.chunkLines {
+		chunk, err := a.writeChunk(ctx, stepRunID, entries[start:min(start+a.chunkLines, len(entries))])
+		if err != nil {
+			return err
+		}
+		archive.Chunks = append(archive.Chunks, chunk)
+		archive.Size += chunk.Size
+	}
+	return a.db.WithContext(ctx).Create(archive).Error
+}
+
+// writeChunk uploads one gzip JSON Lines chunk.
+func (a *LogArchiver) writeChunk(ctx context.Context, stepRunID string, entries []*LogEntry) (model.LogArchiveChunk, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	for _, entry := range entries {
+		line, err := sonic.Marshal(entry)
+		if err != nil {
+			return model.LogArchiveChunk{}, fmt.Errorf("encode log line: %w", err)
+		}
+		_, _ = zw.Write(line)
+		_, _ = zw.Write([]byte{'\n'})
+	}
+	if err := zw.Close(); err != nil {
+		return model.LogArchiveChunk{}, fmt.Errorf("compress log chunk: %w", err)
+	}
+
+	first, last := entries[0].LineNumber, entries[len(entries)-1].LineNumber
+	name := fmt.Sprintf("%010d.jsonl.gz", first)
+	objectName := path.Join(logArchivePrefix, stepRunID, name)
+	file, cleanup, err := storage.FileHeaderFromBytes(name, buf.Bytes())
+	if err != nil {
+		return model.LogArchiveChunk{}, err
+	}
+	defer cleanup()
+	if _, err := a.storage.PutObject(ctx, objectName, file, "application/gzip"); err != nil {
+		return model.LogArchiveChunk{}, fmt.Errorf("upload log chunk %s: %w", objectName, err)
+	}
+	return model.LogArchiveChunk{
+		Object:    objectName,
+		FirstLine: first,
+		LastLine:  last,
+		Lines:     len(entries),
+		Size:      int64(buf.Len()),
+	}, nil
+}
+
+// ReadRange returns up to limit archived lines of a step run starting at
+// fromLine, downloading only the chunks that cover the range. ok is false
+// when the step run has not been archived.
+func (a *LogArchiver) ReadRange(ctx context.Context, stepRunID string, fromLine int32, limit int) (entries []*LogEntry, ok bool, err error) {
+	var archive model.StepRunLogArchive
+	if err := a.db.WithContext(ctx).Where("step_run_id = ?", stepRunID).First(&archive).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	entries = []*LogEntry{}
+	for _, chunk := range archive.Chunks {
+		if chunk.LastLine < fromLine {
+			continue
+		}
+		lines, err := a.readChunk(ctx, chunk)
+		if err != nil {
+			return nil, true, err
+		}
+		for _, entry := range lines {
+			if entry.LineNumber < fromLine {
+				continue
+			}
+			entries = append(entries, entry)
+			if limit > 0 && len(entries) >= limit {
+				return entries, true, nil
+			}
+		}
+	}
+	return entries, true, nil
+}
+
+func (a *LogArchiver) readChunk(ctx context.Context, chunk model.LogArchiveChunk) ([]*LogEntry, error) {
+	data, err := a.storage.GetObject(ctx, chunk.Object)
+	if err != nil {
+		return nil, fmt.Errorf("download log chunk %s: %w", chunk.Object, err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("open log chunk %s: %w", chunk.Object, err)
+	}
+	defer func() { _ = zr.Close() }()
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("decompress log chunk %s: %w", chunk.Object, err)
+	}
+
+	entries := make([]*LogEntry, 0, chunk.Lines)
+	for _, line := range bytes.Split(raw, []byte{'\n'}) {
+		if len(line) == 0 {
+			continue
+		}
+		var entry LogEntry
+		if err := sonic.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("decode log chunk %s: %w", chunk.Object, err)
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
+
+// PurgeExpired deletes the database rows of step runs archived before the
+// retention. Returns the number of step runs purged.
+func (a *LogArchiver) PurgeExpired(ctx context.Context) int {
+	if a.db == nil {
+		return 0
+	}
+	var archives []model.StepRunLogArchive
+	if err := a.db.WithContext(ctx).
+		Select("id", "step_run_id").
+		Where("purged = 0 AND archived_at < ?", time.Now().Add(-a.retention)).
+		Limit(logArchiveBatchSize).
+		Find(&archives).Error; err != nil {
+		log.Warnw("failed to query archived step runs to purge", "error", err)
+		return 0
+	}
+
+	purged := 0
+	for _, archive := range archives {
+		err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE step_run_id = ?", a.table), archive.StepRunID).Error; err != nil {
+				return err
+			}
+			return tx.Model(&model.StepRunLogArchive{}).
+				Where("id = ?", archive.ID).
+				Updates(map[string]any{"purged": 1, "purged_at": time.Now()}).Error
+		})
+		if err != nil {
+			log.Warnw("failed to purge archived step run logs", "stepRunId", archive.StepRunID, "error", err)
+			continue
+		}
+		purged++
+	}
+	if purged > 0 {
+		log.Infow("archived step run logs purged from database", "count", purged)
+	}
+	return purged
+}
+
+// readRows reads all database log rows of a step run in line order.
+func (a *LogArchiver) readRows(ctx context.Context, stepRunID string) ([]*LogEntry, error) {
+	rows, err := a.db.WithContext(ctx).Raw(fmt.Sprintf(`
+		SELECT step_run_id, timestamp, line_number, level, content, stream, plugin_name, agent_id
+		FROM %s
+		WHERE step_run_id = ?
+		ORDER BY line_number ASC
+	`, a.table), stepRunID).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("query logs from mysql: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []*LogEntry
+	for rows.Next() {
+		var entry LogEntry
+		if err := rows.Scan(
+			&entry.StepRunID,
+			&entry.Timestamp,
+			&entry.LineNumber,
+			&entry.Level,
+			&entry.Content,
+			&entry.Stream,
+			&entry.PluginName,
+			&entry.AgentID,
+		); err != nil {
+			return nil, fmt.Errorf("scan log entry: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type memStorage struct {
+	objects map[string][]byte
+}
+
+func (s *memStorage) PutObject(ctx context.Context, name string, file *multipart.FileHeader, _ string) (string, error) {
+	return s.Upload(ctx, name, file, "")
+}
+
+func (s *memStorage) GetObject(ctx context.Context, name string) ([]byte, error) {
+	return s.Download(ctx, name)
+}
+
+func (s *memStorage) Upload(_ context.Context, name string, file *multipart.FileHeader, _ string) (string, error) {
+	f, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return "", err
+	}
+	s.objects[name] = data
+	return name, nil
+}
+
+func (s *memStorage) Download(_ context.Context, name string) ([]byte, error) {
+	data, ok := s.objects[name]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return data, nil
+}
+
+func (s *memStorage) Delete(_ context.Context, name string) error {
+	delete(s.objects, name)
+	return nil
+}
+
+func (s *memStorage) GetPresignedURL(context.Context, string, time.Duration) (string, error) {
+	return "", nil
+}
+
+func TestLogArchiverArchiveFinished(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&model.StepRunLogArchive{}); err != nil {
+		t.Fatal(err)
+	}
+	finishedAt := time.Now().Add(-time.Hour)
+	for _, stmt := range []struct {
+		sql  string
+		args []any
+	}{
+		{sql: `CREATE TABLE step_run (id INTEGER PRIMARY KEY, step_run_id TEXT, status INTEGER, end_time DATETIME)`},
+		{sql: `CREATE TABLE step_run_logs (step_run_id TEXT, timestamp INTEGER, line_number INTEGER, level TEXT,
+			content TEXT, stream TEXT, plugin_name TEXT, agent_id TEXT)`},
+		// unit-tests failed an hour ago, integration-tests is still running
+		{sql: `INSERT INTO step_run (step_run_id, status, end_time) VALUES ('sr-unit', 5, ?), ('sr-integration', 3, NULL)`, args: []any{finishedAt}},
+		{sql: `INSERT INTO step_run_logs VALUES
+			('sr-unit', 100, 1, 'info', 'go test ./...', 'stdout', 'shell', 'agent-1'),
+			('sr-unit', 101, 2, 'error', '--- FAIL: TestRefund (0.02s)', 'stdout', 'shell', 'agent-1'),
+			('sr-integration', 102, 1, 'info', 'docker compose up -d', 'stdout', 'shell', 'agent-2')`},
+	} {
+		if err := db.Exec(stmt.sql, stmt.args...).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	st := &memStorage{objects: map[string][]byte{}}
+	archiver := NewLogArchiver(db, st, 0, time.Minute, 0)
+	ctx := context.Background()
+
+	if got := archiver.ArchiveFinished(ctx); got != 1 {
+		t.Fatalf("ArchiveFinished() = %d, want 1", got)
+	}
+	entries, ok, err := archiver.ReadRange(ctx, "sr-unit", 2, 0)
+	if err != nil || !ok {
+		t.Fatalf("ReadRange(sr-unit) = ok %v, %v; want archived", ok, err)
+	}
+	if len(entries) != 1 || entries[0].Content != "--- FAIL: TestRefund (0.02s)" {
+		t.Errorf("ReadRange(sr-unit, 2) = %+v, want the failing test line", entries)
+	}
+	if _, ok, err := archiver.ReadRange(ctx, "sr-integration", 1, 0); err != nil || ok {
+		t.Errorf("ReadRange(sr-integration) = ok %v, %v; want the running step left unarchived", ok, err)
+	}
+
+	// Archived steps are not picked up again
+	if got := archiver.ArchiveFinished(ctx); got != 0 {
+		t.Errorf("second ArchiveFinished() = %d, want 0", got)
+	}
+}
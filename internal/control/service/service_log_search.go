@@ -0,0 +1,341 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	steprunv1 "github.com/arcentrix/arcentra/api/steprun/v1"
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/arcentrix/arcentra/pkg/logindex"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultLogSearchPageSize = 20
+	maxLogSearchPageSize     = 100
+	// maxLogSearchStepRuns bounds the step runs a search considers; the
+	// newest ones are kept and the result is marked truncated.
+	maxLogSearchStepRuns = 50000
+)
+
+var (
+	// ErrLogSearchDisabled is returned when log search is not enabled.
+	ErrLogSearchDisabled = errors.New("log search is not enabled")
+	// ErrLogSearchScopeRequired is returned when a search names neither a
+	// project nor a pipeline.
+	ErrLogSearchScopeRequired = errors.New("projectId or pipelineId is required")
+)
+
+// LogSearchService keeps a full-text index of step run logs, fed by the log
+// aggregator as batches are written to the database, and searches it with
+// project, pipeline, branch, status and time filters. Searches are scoped to
+// one project the caller can read.
+//
+// The index lives on the local disk of the replica and holds the lines that
+// replica's aggregator persisted, so with several control plane replicas each
+// one only finds its own share of lines. Searches then report the step runs
+// in scope that ran but are missing from the local index, and mark the
+// response partial.
+type LogSearchService struct {
+	db           *gorm.DB
+	pipelineRepo repo.IPipelineRepository
+	access       *ProjectAccessService
+
+	mu    sync.RWMutex
+	index *logindex.Index
+}
+
+// NewLogSearchService creates the service. Searching is disabled until an
+// index is set.
+func NewLogSearchService(
+	db *gorm.DB,
+	pipelineRepo repo.IPipelineRepository,
+	access *ProjectAccessService,
+) *LogSearchService {
+	return &LogSearchService{db: db, pipelineRepo: pipelineRepo, access: access}
+}
+
+// SetIndex sets the index lines are written to and searched in.
+func (s *LogSearchService) SetIndex(index *logindex.Index) {
+	s.mu.Lock()
+	s.index = index
+	s.mu.Unlock()
+}
+
+func (s *LogSearchService) getIndex() *logindex.Index {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.index
+}
+
+// IndexLogs adds persisted log lines to the index.
+func (s *LogSearchService) IndexLogs(logs []*LogEntry) {
+	index := s.getIndex()
+	if index == nil || len(logs) == 0 {
+		return
+	}
+	docs := make([]logindex.Doc, 0, len(logs))
+	for _, entry := range logs {
+		docs = append(docs, logindex.Doc{
+			StepRunID: entry.StepRunID,
+			Line:      entry.LineNumber,
+			Timestamp: entry.Timestamp,
+			Content:   entry.Content,
+		})
+	}
+	if err := index.Add(docs...); err != nil {
+		log.Warnw("failed to index step run logs", "lines", len(docs), "error", err)
+	}
+}
+
+// Flush persists the lines indexed since the last flush.
+func (s *LogSearchService) Flush() {
+	index := s.getIndex()
+	if index == nil {
+		return
+	}
+	if err := index.Flush(); err != nil {
+		log.Warnw("failed to flush log index", "error", err)
+	}
+}
+
+// PurgeBefore drops the index segments holding only lines older than t.
+func (s *LogSearchService) PurgeBefore(t time.Time) int {
+	index := s.getIndex()
+	if index == nil {
+		return 0
+	}
+	dropped, err := index.DeleteBefore(t.Unix())
+	if err != nil {
+		log.Warnw("failed to purge log index", "error", err)
+	}
+	if dropped > 0 {
+		log.Infow("log index segments purged", "count", dropped)
+	}
+	return dropped
+}
+
+// Search finds the log lines matching req in a project userID can read.
+func (s *LogSearchService) Search(ctx context.Context, userID string, req *model.LogSearchReq) (*model.LogSearchResp, error) {
+	index := s.getIndex()
+	if index == nil {
+		return nil, ErrLogSearchDisabled
+	}
+	projectID, err := s.authorize(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	pageNum := max(req.PageNum, 1)
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultLogSearchPageSize
+	}
+	pageSize = min(pageSize, maxLogSearchPageSize)
+	resp := &model.LogSearchResp{List: []*model.LogSearchHit{}, PageNum: pageNum, PageSize: pageSize}
+
+	q := logindex.Query{
+		Phrase: strings.TrimSpace(req.Query),
+		Regex:  req.Regex,
+		From:   req.StartTime,
+		To:     req.EndTime,
+		Desc:   !strings.EqualFold(req.Order, "asc"),
+		Offset: (pageNum - 1) * pageSize,
+		Limit:  pageSize,
+	}
+	stepRunIDs, ran, stepRunsTruncated, err := s.filterStepRuns(ctx, projectID, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Truncated = stepRunsTruncated
+	if len(stepRunIDs) == 0 {
+		return resp, nil
+	}
+	resp.UnindexedStepRuns = len(ran) - len(index.Indexed(ran))
+	resp.Partial = resp.UnindexedStepRuns > 0
+	q.Match = func(stepRunID string) bool {
+		_, ok := stepRunIDs[stepRunID]
+		return ok
+	}
+
+	result, err := index.Search(q)
+	if err != nil {
+		return nil, err
+	}
+	resp.Total = result.Total
+	resp.Truncated = resp.Truncated || result.Truncated
+	if len(result.Hits) == 0 {
+		return resp, nil
+	}
+
+	steps, err := s.loadSteps(ctx, result.Hits)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range result.Hits {
+		hit := &model.LogSearchHit{
+			StepRunID:   h.StepRunID,
+			LineNumber:  h.Line,
+			Timestamp:   h.Timestamp,
+			Content:     h.Content,
+			Highlights:  h.Highlights,
+			Highlighted: logindex.Highlight(h.Content, h.Highlights, "<mark>", "</mark>", html.EscapeString),
+		}
+		if step, ok := steps[h.StepRunID]; ok {
+			hit.StepName = step.Name
+			hit.StepStatus = step.Status
+			hit.PipelineID = step.PipelineID
+			hit.PipelineRunID = step.PipelineRunID
+			hit.JobID = step.JobID
+			hit.Branch = step.Branch
+			hit.Link = logLineLink(step.PipelineID, step.PipelineRunID, h.StepRunID, h.Line)
+		}
+		resp.List = append(resp.List, hit)
+	}
+	return resp, nil
+}
+
+// authorize returns the project a search is scoped to: the requested one or
+// that of the requested pipeline. userID must be able to read it.
+func (s *LogSearchService) authorize(ctx context.Context, userID string, req *model.LogSearchReq) (string, error) {
+	projectID := req.ProjectID
+	if projectID == "" {
+		if req.PipelineID == "" {
+			return "", ErrLogSearchScopeRequired
+		}
+		pipeline, err := s.pipelineRepo.Get(ctx, req.PipelineID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", ErrProjectPermissionDenied
+			}
+			return "", err
+		}
+		projectID = pipeline.ProjectID
+	}
+	if err := s.access.Require(ctx, projectID, userID, model.AccessLevelRead); err != nil {
+		return "", err
+	}
+	return projectID, nil
+}
+
+// logSearchStep is the step run context attached to a hit.
+type logSearchStep struct {
+	StepRunID     string `gorm:"column:step_run_id"`
+	Name          string `gorm:"column:name"`
+	Status        int    `gorm:"column:status"`
+	PipelineID    string `gorm:"column:pipeline_id"`
+	PipelineRunID string `gorm:"column:pipeline_run_id"`
+	JobID         string `gorm:"column:job_id"`
+	Branch        string `gorm:"column:branch"`
+}
+
+// filterStepRuns resolves the project and run filters of req into the step
+// runs whose lines may match, and lists those among them that started and so
+// are expected to have lines. Only the newest maxLogSearchStepRuns are kept,
+// in which case truncated is set.
+func (s *LogSearchService) filterStepRuns(
+	ctx context.Context, projectID string, req *model.LogSearchReq,
+) (ids map[string]struct{}, ran []string, truncated bool, err error) {
+	tx := s.db.WithContext(ctx).
+		Table("step_run AS sr").
+		Joins("JOIN pipeline_run AS pr ON pr.run_id = sr.pipeline_run_id").
+		Joins("JOIN pipeline AS p ON p.pipeline_id = sr.pipeline_id").
+		Where("p.project_id = ?", projectID)
+	if req.PipelineID != "" {
+		tx = tx.Where("sr.pipeline_id = ?", req.PipelineID)
+	}
+	if req.Branch != "" {
+		tx = tx.Where("pr.branch = ?", req.Branch)
+	}
+	if req.Status > 0 {
+		tx = tx.Where("sr.status = ?", req.Status)
+	}
+	// a step whose run window misses the time range has no line in it
+	if req.StartTime > 0 {
+		tx = tx.Where("(sr.end_time IS NULL OR sr.end_time >= ?)", time.Unix(req.StartTime, 0))
+	}
+	if req.EndTime > 0 {
+		tx = tx.Where("(sr.start_time IS NULL OR sr.start_time <= ?)", time.Unix(req.EndTime, 0))
+	}
+
+	var found []*logSearchCandidate
+	err = tx.Select("sr.step_run_id, sr.status, sr.start_time").
+		Order("sr.id DESC").Limit(maxLogSearchStepRuns + 1).Scan(&found).Error
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("query step runs: %w", err)
+	}
+	if len(found) > maxLogSearchStepRuns {
+		found, truncated = found[:maxLogSearchStepRuns], true
+	}
+	ids = make(map[string]struct{}, len(found))
+	for _, c := range found {
+		ids[c.StepRunID] = struct{}{}
+		if c.StartTime != nil && c.Status != int(steprunv1.StepRunStatus_STEP_RUN_STATUS_SKIPPED) {
+			ran = append(ran, c.StepRunID)
+		}
+	}
+	return ids, ran, truncated, nil
+}
+
+// logSearchCandidate is a step run in the scope of a search.
+type logSearchCandidate struct {
+	StepRunID string     `gorm:"column:step_run_id"`
+	Status    int        `gorm:"column:status"`
+	StartTime *time.Time `gorm:"column:start_time"`
+}
+
+// loadSteps loads the step run context of the hits.
+func (s *LogSearchService) loadSteps(ctx context.Context, hits []logindex.Hit) (map[string]*logSearchStep, error) {
+	ids := make([]string, 0, len(hits))
+	seen := make(map[string]struct{}, len(hits))
+	for _, h := range hits {
+		if _, ok := seen[h.StepRunID]; !ok {
+			seen[h.StepRunID] = struct{}{}
+			ids = append(ids, h.StepRunID)
+		}
+	}
+
+	var rows []*logSearchStep
+	err := s.db.WithContext(ctx).
+		Table("step_run AS sr").
+		Select("sr.step_run_id, sr.name, sr.status, sr.pipeline_id, sr.pipeline_run_id, sr.job_id, pr.branch").
+		Joins("LEFT JOIN pipeline_run AS pr ON pr.run_id = sr.pipeline_run_id").
+		Where("sr.step_run_id IN ?", ids).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("query step runs: %w", err)
+	}
+	steps := make(map[string]*logSearchStep, len(rows))
+	for _, row := range rows {
+		steps[row.StepRunID] = row
+	}
+	return steps, nil
+}
+
+// logLineLink returns the console path of a log line in its run.
+func logLineLink(pipelineID, pipelineRunID, stepRunID string, line int32) string {
+	return fmt.Sprintf("/pipelines/%s/runs/%s?stepRunId=%s#L%d",
+		url.PathEscape(pipelineID), url.PathEscape(pipelineRunID), url.QueryEscape(stepRunID), line)
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/pkg/logindex"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestLogSearchRequiresProjectAccess(t *testing.T) {
+	f := newAccessFixture()
+	f.projects["private"] = &model.Project{ProjectID: "private", Visibility: model.VisibilityPrivate}
+	f.pipelines["build"] = &model.Pipeline{PipelineID: "build", ProjectID: "private"}
+	f.members["private/dev"] = string(model.ProjectRoleDeveloper)
+	svc := NewLogSearchService(nil, fakePipelineRepo{f: f}, f.service())
+
+	index, err := logindex.Open(logindex.Options{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.SetIndex(index)
+	ctx := context.Background()
+
+	if _, err := svc.Search(ctx, "dev", &model.LogSearchReq{Query: "error"}); !errors.Is(err, ErrLogSearchScopeRequired) {
+		t.Errorf("search without project: got %v, want ErrLogSearchScopeRequired", err)
+	}
+	for _, req := range []*model.LogSearchReq{
+		{Query: "error", ProjectID: "private"},
+		{Query: "error", PipelineID: "build"},
+		{Query: "error", PipelineID: "missing"},
+	} {
+		if _, err := svc.Search(ctx, "stranger", req); !errors.Is(err, ErrProjectPermissionDenied) {
+			t.Errorf("stranger search %+v: got %v, want ErrProjectPermissionDenied", req, err)
+		}
+	}
+
+	// A pipeline-only search is scoped to the pipeline's project.
+	projectID, err := svc.authorize(ctx, "dev", &model.LogSearchReq{PipelineID: "build"})
+	if err != nil || projectID != "private" {
+		t.Errorf("authorize(dev, build) = %q, %v; want private", projectID, err)
+	}
+}
+
+func TestLogSearchReportsPartialResults(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE pipeline (pipeline_id TEXT, project_id TEXT)`,
+		`CREATE TABLE pipeline_run (run_id TEXT, branch TEXT)`,
+		`CREATE TABLE step_run (id INTEGER PRIMARY KEY, step_run_id TEXT, name TEXT, status INTEGER,
+			pipeline_id TEXT, pipeline_run_id TEXT, job_id TEXT, start_time DATETIME, end_time DATETIME)`,
+		`INSERT INTO pipeline VALUES ('build', 'payments-api')`,
+		`INSERT INTO pipeline_run VALUES ('run-41', 'main')`,
+		// unit-tests ran on this replica, lint on another one, deploy was skipped
+		`INSERT INTO step_run (step_run_id, name, status, pipeline_id, pipeline_run_id, start_time) VALUES
+			('sr-unit', 'unit-tests', 5, 'build', 'run-41', CURRENT_TIMESTAMP),
+			('sr-lint', 'lint', 4, 'build', 'run-41', CURRENT_TIMESTAMP),
+			('sr-deploy', 'deploy', 8, 'build', 'run-41', NULL)`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f := newAccessFixture()
+	f.projects["payments-api"] = &model.Project{ProjectID: "payments-api", Visibility: model.VisibilityPrivate}
+	f.members["payments-api/dev"] = string(model.ProjectRoleDeveloper)
+	svc := NewLogSearchService(db, fakePipelineRepo{f: f}, f.service())
+	index, err := logindex.Open(logindex.Options{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.SetIndex(index)
+	svc.IndexLogs([]*LogEntry{{StepRunID: "sr-unit", LineNumber: 12, Timestamp: 100, Content: "--- FAIL: TestRefund (0.02s)"}})
+
+	req := &model.LogSearchReq{Query: "fail", ProjectID: "payments-api"}
+	resp, err := svc.Search(context.Background(), "dev", req)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if resp.Total != 1 || resp.List[0].StepName != "unit-tests" {
+		t.Fatalf("Search() = %d hits, want the unit-tests line", resp.Total)
+	}
+	if !resp.Partial || resp.UnindexedStepRuns != 1 {
+		t.Fatalf("Partial = %v, UnindexedStepRuns = %d; want true, 1 (lint)", resp.Partial, resp.UnindexedStepRuns)
+	}
+
+	svc.IndexLogs([]*LogEntry{{StepRunID: "sr-lint", LineNumber: 1, Timestamp: 101, Content: "golangci-lint run ./..."}})
+	if resp, err = svc.Search(context.Background(), "dev", req); err != nil || resp.Partial {
+		t.Fatalf("Search() after indexing lint = partial %v, %v; want complete", resp.Partial, err)
+	}
+}
@@ -0,0 +1,470 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"github.com/arcentrix/arcentra/internal/shared/notify"
+	"github.com/arcentrix/arcentra/internal/shared/notify/channel"
+	"github.com/arcentrix/arcentra/pkg/id"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/bytedance/sonic"
+	"gorm.io/gorm"
+)
+
+const maskedValue = "***MASKED***"
+
+// ErrNotificationTargetBlocked 测试配置中的地址指向回环、私有或链路本地地址
+var ErrNotificationTargetBlocked = errors.New("notification target resolves to a loopback, private or link-local address")
+
+// notificationTargetKeys 控制面发送通知时会连接的地址配置项
+var notificationTargetKeys = []string{"webhook_url", "token_url", "smtp_host"}
+
+// sensitiveConfigKeys 响应中需要脱敏的配置项关键字。机器人 webhook 地址本身即凭据，
+// 持有地址即可向群组发送消息，因此 URL 类配置项同样脱敏
+var sensitiveConfigKeys = []string{"secret", "token", "password", "key", "url"}
+
+// NotificationChannelRequest 创建/更新通知渠道的请求
+type NotificationChannelRequest struct {
+	Name        string         `json:"name"`
+	Type        string         `json:"type"`
+	Config      map[string]any `json:"config"`
+	AuthConfig  map[string]any `json:"authConfig"`
+	Description *string        `json:"description"`
+	IsActive    *bool          `json:"isActive"`
+}
+
+// NotificationChannelService 管理通知渠道，变更后实时同步到 notify.Manager
+type NotificationChannelService struct {
+	channelRepo repo.INotificationChannelRepository
+	manager     *notify.Manager
+}
+
+// NewNotificationChannelService creates a notification channel service
+func NewNotificationChannelService(channelRepo repo.INotificationChannelRepository, manager *notify.Manager) *NotificationChannelService {
+	return &NotificationChannelService{
+		channelRepo: channelRepo,
+		manager:     manager,
+	}
+}
+
+// ListChannelTypes returns the supported channel types
+func (s *NotificationChannelService) ListChannelTypes() []notify.ChannelType {
+	return notify.SupportedChannelTypes()
+}
+
+// CreateChannel validates and persists a channel, then registers it
+func (s *NotificationChannelService) CreateChannel(ctx context.Context, req *NotificationChannelRequest) (*model.NotificationChannel, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	if err := s.ensureNameAvailable(ctx, name, ""); err != nil {
+		return nil, err
+	}
+
+	cfg := &notify.ChannelConfig{
+		Name:       name,
+		Type:       notify.ChannelType(strings.TrimSpace(req.Type)),
+		Config:     req.Config,
+		AuthConfig: req.AuthConfig,
+	}
+	if _, err := s.buildChannel(cfg); err != nil {
+		return nil, err
+	}
+
+	ch := &model.NotificationChannel{
+		ChannelID: id.GetUild(),
+		Name:      name,
+		Type:      string(cfg.Type),
+		IsActive:  req.IsActive == nil || *req.IsActive,
+	}
+	if req.Description != nil {
+		ch.Description = strings.TrimSpace(*req.Description)
+	}
+	if err := setChannelConfig(ch, cfg); err != nil {
+		return nil, err
+	}
+	if err := s.channelRepo.Create(ctx, ch); err != nil {
+		log.Errorw("failed to create notification channel", "name", name, "error", err)
+		return nil, errors.New("failed to create notification channel")
+	}
+
+	s.syncChannel(ctx, ch.ChannelID, "")
+	log.Infow("notification channel created", "channelID", ch.ChannelID, "name", name, "type", ch.Type)
+	return maskChannel(ch), nil
+}
+
+// UpdateChannel updates a channel; omitted fields keep their current value and
+// masked config values keep the stored secret
+func (s *NotificationChannelService) UpdateChannel(
+	ctx context.Context,
+	channelID string,
+	req *NotificationChannelRequest,
+) (*model.NotificationChannel, error) {
+	existing, err := s.getChannel(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	current, err := notify.ChannelConfigFromModel(existing)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stored channel config: %w", err)
+	}
+
+	cfg := &notify.ChannelConfig{
+		Name:       existing.Name,
+		Type:       current.Type,
+		Config:     current.Config,
+		AuthConfig: current.AuthConfig,
+	}
+	if name := strings.TrimSpace(req.Name); name != "" && name != existing.Name {
+		if err := s.ensureNameAvailable(ctx, name, channelID); err != nil {
+			return nil, err
+		}
+		cfg.Name = name
+	}
+	if t := strings.TrimSpace(req.Type); t != "" {
+		cfg.Type = notify.ChannelType(t)
+	}
+	if req.Config != nil {
+		cfg.Config = unmaskConfig(req.Config, current.Config)
+	}
+	if req.AuthConfig != nil {
+		cfg.AuthConfig = unmaskConfig(req.AuthConfig, current.AuthConfig)
+	}
+	if _, err := s.buildChannel(cfg); err != nil {
+		return nil, err
+	}
+
+	ch := &model.NotificationChannel{
+		ChannelID:   channelID,
+		Name:        cfg.Name,
+		Type:        string(cfg.Type),
+		Description: existing.Description,
+		IsActive:    existing.IsActive,
+	}
+	if req.Description != nil {
+		ch.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.IsActive != nil {
+		ch.IsActive = *req.IsActive
+	}
+	if err := setChannelConfig(ch, cfg); err != nil {
+		return nil, err
+	}
+	if err := s.channelRepo.Update(ctx, ch); err != nil {
+		log.Errorw("failed to update notification channel", "channelID", channelID, "error", err)
+		return nil, errors.New("failed to update notification channel")
+	}
+	// Updates 会忽略零值，停用需要单独处理
+	if !ch.IsActive && existing.IsActive {
+		if err := s.channelRepo.Delete(ctx, channelID); err != nil {
+			log.Errorw("failed to deactivate notification channel", "channelID", channelID, "error", err)
+			return nil, errors.New("failed to deactivate notification channel")
+		}
+	}
+
+	s.syncChannel(ctx, channelID, existing.Name)
+	log.Infow("notification channel updated", "channelID", channelID, "name", ch.Name)
+	return s.GetChannel(ctx, channelID)
+}
+
+// GetChannel returns a channel with sensitive config masked
+func (s *NotificationChannelService) GetChannel(ctx context.Context, channelID string) (*model.NotificationChannel, error) {
+	ch, err := s.getChannel(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	return maskChannel(ch), nil
+}
+
+// ListChannels lists channels with sensitive config masked
+func (s *NotificationChannelService) ListChannels(ctx context.Context, includeInactive bool) ([]*model.NotificationChannel, error) {
+	var (
+		channels []*model.NotificationChannel
+		err      error
+	)
+	if includeInactive {
+		channels, err = s.channelRepo.List(ctx)
+	} else {
+		channels, err = s.channelRepo.ListActive(ctx)
+	}
+	if err != nil {
+		log.Errorw("failed to list notification channels", "error", err)
+		return nil, errors.New("failed to list notification channels")
+	}
+	for i, ch := range channels {
+		channels[i] = maskChannel(ch)
+	}
+	return channels, nil
+}
+
+// DeleteChannel deactivates a channel and unregisters it from the manager
+func (s *NotificationChannelService) DeleteChannel(ctx context.Context, channelID string) error {
+	existing, err := s.getChannel(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	if err := s.channelRepo.Delete(ctx, channelID); err != nil {
+		log.Errorw("failed to delete notification channel", "channelID", channelID, "error", err)
+		return errors.New("failed to delete notification channel")
+	}
+	s.syncChannel(ctx, channelID, existing.Name)
+	log.Infow("notification channel deleted", "channelID", channelID, "name", existing.Name)
+	return nil
+}
+
+// TestChannel sends a test message through a stored channel (active or not)
+func (s *NotificationChannelService) TestChannel(ctx context.Context, channelID, message string) error {
+	existing, err := s.getChannel(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	cfg, err := notify.ChannelConfigFromModel(existing)
+	if err != nil {
+		return fmt.Errorf("invalid stored channel config: %w", err)
+	}
+	return s.sendTest(ctx, cfg, message)
+}
+
+// TestChannelConfig sends a test message through an unsaved channel config.
+// Configs targeting internal addresses are rejected, so the endpoint cannot
+// be used to probe the control plane's network.
+func (s *NotificationChannelService) TestChannelConfig(ctx context.Context, req *NotificationChannelRequest, message string) error {
+	if err := validateNotificationTargets(ctx, req.Config, req.AuthConfig); err != nil {
+		return err
+	}
+	cfg := &notify.ChannelConfig{
+		Name:       strings.TrimSpace(req.Name),
+		Type:       notify.ChannelType(strings.TrimSpace(req.Type)),
+		Config:     req.Config,
+		AuthConfig: req.AuthConfig,
+	}
+	return s.sendTest(ctx, cfg, message)
+}
+
+// ReloadChannels reloads all active channels from the database into the manager
+func (s *NotificationChannelService) ReloadChannels(ctx context.Context) ([]string, error) {
+	err := s.manager.ReloadChannelsFromDatabase(ctx)
+	return s.manager.ListChannels(), err
+}
+
+func (s *NotificationChannelService) sendTest(ctx context.Context, cfg *notify.ChannelConfig, message string) error {
+	ch, err := s.buildChannel(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = ch.Close() }()
+
+	if strings.TrimSpace(message) == "" {
+		message = fmt.Sprintf("Arcentra test notification from channel %q", cfg.Name)
+	}
+	if err := ch.Send(ctx, message); err != nil {
+		return fmt.Errorf("send test message: %w", err)
+	}
+	return nil
+}
+
+func (s *NotificationChannelService) buildChannel(cfg *notify.ChannelConfig) (*channel.NotifyChannel, error) {
+	if cfg.Type == "" {
+		return nil, errors.New("type is required")
+	}
+	if !notify.IsSupportedChannelType(cfg.Type) {
+		return nil, fmt.Errorf("unsupported channel type %q, must be one of: %v", cfg.Type, notify.SupportedChannelTypes())
+	}
+	ch, err := s.manager.BuildChannel(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid channel config: %w", err)
+	}
+	return ch, nil
+}
+
+// syncChannel 将渠道的最新状态同步到 notify.Manager（注册、替换或注销）
+func (s *NotificationChannelService) syncChannel(ctx context.Context, channelID, previousName string) {
+	if previousName != "" {
+		_ = s.manager.UnregisterChannel(previousName)
+	}
+	ch, err := s.channelRepo.Get(ctx, channelID)
+	if err != nil || !ch.IsActive {
+		return
+	}
+	cfg, err := notify.ChannelConfigFromModel(ch)
+	if err != nil {
+		log.Warnw("failed to parse notification channel config", "channelID", channelID, "error", err)
+		return
+	}
+	notifyChannel, err := s.manager.BuildChannel(cfg)
+	if err == nil {
+		err = s.manager.ReplaceChannel(ch.Name, notifyChannel)
+	}
+	if err != nil {
+		log.Warnw("failed to register notification channel", "channelID", channelID, "error", err)
+	}
+}
+
+func (s *NotificationChannelService) getChannel(ctx context.Context, channelID string) (*model.NotificationChannel, error) {
+	ch, err := s.channelRepo.Get(ctx, channelID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("notification channel not found")
+		}
+		log.Errorw("failed to get notification channel", "channelID", channelID, "error", err)
+		return nil, errors.New("failed to get notification channel")
+	}
+	return ch, nil
+}
+
+// ensureNameAvailable 活跃渠道的名称必须唯一（通知规则按名称引用渠道）
+func (s *NotificationChannelService) ensureNameAvailable(ctx context.Context, name, exceptID string) error {
+	channels, err := s.channelRepo.ListActive(ctx)
+	if err != nil {
+		log.Errorw("failed to list notification channels", "error", err)
+		return errors.New("failed to check channel name")
+	}
+	for _, ch := range channels {
+		if ch.Name == name && ch.ChannelID != exceptID {
+			return fmt.Errorf("notification channel %q already exists", name)
+		}
+	}
+	return nil
+}
+
+func setChannelConfig(ch *model.NotificationChannel, cfg *notify.ChannelConfig) error {
+	config, err := sonic.MarshalString(cfg.Config)
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	ch.Config = config
+	ch.AuthConfig = ""
+	if len(cfg.AuthConfig) > 0 {
+		authConfig, err := sonic.MarshalString(cfg.AuthConfig)
+		if err != nil {
+			return fmt.Errorf("invalid authConfig: %w", err)
+		}
+		ch.AuthConfig = authConfig
+	}
+	return nil
+}
+
+// maskChannel 返回脱敏后的渠道副本
+func maskChannel(ch *model.NotificationChannel) *model.NotificationChannel {
+	masked := *ch
+	cfg, err := notify.ChannelConfigFromModel(ch)
+	if err != nil {
+		masked.Config = maskedValue
+		masked.AuthConfig = ""
+		return &masked
+	}
+	if raw, err := sonic.MarshalString(maskConfig(cfg.Config)); err == nil {
+		masked.Config = raw
+	}
+	if len(cfg.AuthConfig) > 0 {
+		if raw, err := sonic.MarshalString(maskConfig(cfg.AuthConfig)); err == nil {
+			masked.AuthConfig = raw
+		}
+	}
+	return &masked
+}
+
+func maskConfig(config map[string]any) map[string]any {
+	out := make(map[string]any, len(config))
+	for k, v := range config {
+		if isSensitiveKey(k) {
+			if str, ok := v.(string); ok && str != "" {
+				v = maskedValue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// unmaskConfig 将请求中仍为掩码的值还原为已存储的值
+func unmaskConfig(config, stored map[string]any) map[string]any {
+	out := make(map[string]any, len(config))
+	for k, v := range config {
+		if v == maskedValue {
+			v = stored[k]
+		}
+		out[k] = v
+	}
+	return out
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	if key == "header_name" {
+		return false
+	}
+	for _, s := range sensitiveConfigKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateNotificationTargets rejects endpoints in configs that are not
+// http(s) URLs or that resolve to a loopback, private or link-local address.
+func validateNotificationTargets(ctx context.Context, configs ...map[string]any) error {
+	for _, config := range configs {
+		for _, key := range notificationTargetKeys {
+			raw, _ := config[key].(string)
+			raw = strings.TrimSpace(raw)
+			if raw == "" {
+				continue
+			}
+			host := raw
+			if key != "smtp_host" {
+				u, err := url.Parse(raw)
+				if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+					return fmt.Errorf("%s must be an absolute http(s) url", key)
+				}
+				host = u.Hostname()
+			}
+			if err := checkPublicHost(ctx, host); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkPublicHost resolves host and fails when any of its addresses is not
+// publicly routable.
+func checkPublicHost(ctx context.Context, host string) error {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrNotificationTargetBlocked
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if blockedWebhookAddr(addr) {
+			return ErrNotificationTargetBlocked
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMaskConfig(t *testing.T) {
+	config := map[string]any{
+		"webhook_url": "https://oapi.dingtalk.com/robot/send?access_token=abc",
+		"url":         "https://hooks.example.com/T000/B000/XXX",
+		"secret":      "SEC123",
+		"app_secret":  "app-secret",
+		"bot_token":   "xoxb-1",
+		"api_key":     "k",
+		"header_name": "X-Token",
+		"method":      "POST",
+		"smtp_host":   "smtp.example.com",
+		"smtp_port":   465,
+		"token":       "",
+	}
+	masked := maskConfig(config)
+	for _, k := range []string{"webhook_url", "url", "secret", "app_secret", "bot_token", "api_key"} {
+		if masked[k] != maskedValue {
+			t.Errorf("%s = %v, want masked", k, masked[k])
+		}
+	}
+	for _, k := range []string{"header_name", "method", "smtp_host", "smtp_port", "token"} {
+		if masked[k] != config[k] {
+			t.Errorf("%s = %v, want %v", k, masked[k], config[k])
+		}
+	}
+
+	// a masked value sent back on update keeps the stored one
+	masked["method"] = "PUT"
+	restored := unmaskConfig(masked, config)
+	if restored["webhook_url"] != config["webhook_url"] || restored["secret"] != config["secret"] {
+		t.Fatalf("masked values not restored: %v", restored)
+	}
+	if restored["method"] != "PUT" {
+		t.Fatalf("method = %v, want the updated value", restored["method"])
+	}
+}
+
+func TestValidateNotificationTargets(t *testing.T) {
+	ctx := context.Background()
+	ok := []map[string]any{
+		{"webhook_url": "https://203.0.113.10/robot/send?access_token=abc"},
+		{"smtp_host": "198.51.100.25", "smtp_port": 465},
+		{"webhook_url": ""},
+	}
+	for _, config := range ok {
+		if err := validateNotificationTargets(ctx, config); err != nil {
+			t.Errorf("validateNotificationTargets(%v) = %v, want nil", config, err)
+		}
+	}
+	blocked := []map[string]any{
+		{"webhook_url": "http://169.254.169.254/latest/meta-data"},
+		{"webhook_url": "http://localhost:8080/hook"},
+		{"webhook_url": "http://[::1]/hook"},
+		{"smtp_host": "10.0.0.25"},
+		{"smtp_host": "127.0.0.1"},
+	}
+	for _, config := range blocked {
+		if err := validateNotificationTargets(ctx, config); !errors.Is(err, ErrNotificationTargetBlocked) {
+			t.Errorf("validateNotificationTargets(%v) = %v, want ErrNotificationTargetBlocked", config, err)
+		}
+	}
+	if err := validateNotificationTargets(ctx, nil, map[string]any{"token_url": "http://192.168.1.10/token"}); !errors.Is(err, ErrNotificationTargetBlocked) {
+		t.Errorf("auth config token_url = %v, want ErrNotificationTargetBlocked", err)
+	}
+	if err := validateNotificationTargets(ctx, map[string]any{"webhook_url": "file:///etc/passwd"}); err == nil {
+		t.Errorf("non-http webhook_url accepted")
+	}
+}
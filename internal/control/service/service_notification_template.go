@@ -0,0 +1,209 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/arcentrix/arcentra/internal/shared/notify"
+	"github.com/arcentrix/arcentra/internal/shared/notify/template"
+	"github.com/arcentrix/arcentra/pkg/id"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"gorm.io/gorm"
+)
+
+// NotificationTemplateService 管理通知模板，并支持渲染预览与测试发送
+type NotificationTemplateService struct {
+	templateService *template.Service
+	manager         *notify.Manager
+}
+
+// NewNotificationTemplateService creates a notification template service
+func NewNotificationTemplateService(templateService *template.Service, manager *notify.Manager) *NotificationTemplateService {
+	return &NotificationTemplateService{
+		templateService: templateService,
+		manager:         manager,
+	}
+}
+
+// CreateTemplate validates and persists a template
+func (s *NotificationTemplateService) CreateTemplate(ctx context.Context, tmpl *template.Template) (*template.Template, error) {
+	tmpl.Name = strings.TrimSpace(tmpl.Name)
+	if err := validateNotificationTemplate(tmpl); err != nil {
+		return nil, err
+	}
+	if existing, err := s.templateService.GetTemplateByNameAndType(ctx, tmpl.Name, tmpl.Type); err == nil && existing != nil {
+		return nil, fmt.Errorf("notification template %q of type %s already exists", tmpl.Name, tmpl.Type)
+	}
+	if tmpl.Channel == "" {
+		tmpl.Channel = "all"
+	}
+	if tmpl.Format == "" {
+		tmpl.Format = "markdown"
+	}
+
+	tmpl.ID = id.GetUild()
+	if err := s.templateService.CreateTemplate(ctx, tmpl); err != nil {
+		log.Errorw("failed to create notification template", "name", tmpl.Name, "error", err)
+		return nil, err
+	}
+	log.Infow("notification template created", "templateID", tmpl.ID, "name", tmpl.Name)
+	return tmpl, nil
+}
+
+// UpdateTemplate updates a template; empty fields keep their current value
+func (s *NotificationTemplateService) UpdateTemplate(
+	ctx context.Context,
+	templateID string,
+	patch *template.Template,
+) (*template.Template, error) {
+	tmpl, err := s.GetTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if name := strings.TrimSpace(patch.Name); name != "" {
+		tmpl.Name = name
+	}
+	if patch.Type != "" {
+		tmpl.Type = patch.Type
+	}
+	if patch.Channel != "" {
+		tmpl.Channel = patch.Channel
+	}
+	if patch.Title != "" {
+		tmpl.Title = patch.Title
+	}
+	if patch.Content != "" {
+		tmpl.Content = patch.Content
+	}
+	if patch.Format != "" {
+		tmpl.Format = patch.Format
+	}
+	if patch.Metadata != nil {
+		tmpl.Metadata = patch.Metadata
+	}
+	if patch.Description != "" {
+		tmpl.Description = patch.Description
+	}
+	if err := validateNotificationTemplate(tmpl); err != nil {
+		return nil, err
+	}
+	if existing, err := s.templateService.GetTemplateByNameAndType(ctx, tmpl.Name, tmpl.Type); err == nil && existing.ID != templateID {
+		return nil, fmt.Errorf("notification template %q of type %s already exists", tmpl.Name, tmpl.Type)
+	}
+
+	if err := s.templateService.UpdateTemplate(ctx, tmpl); err != nil {
+		log.Errorw("failed to update notification template", "templateID", templateID, "error", err)
+		return nil, err
+	}
+	log.Infow("notification template updated", "templateID", templateID)
+	return tmpl, nil
+}
+
+// GetTemplate returns an active template by ID
+func (s *NotificationTemplateService) GetTemplate(ctx context.Context, templateID string) (*template.Template, error) {
+	tmpl, err := s.templateService.GetTemplate(ctx, templateID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("notification template not found")
+		}
+		log.Errorw("failed to get notification template", "templateID", templateID, "error", err)
+		return nil, errors.New("failed to get notification template")
+	}
+	return tmpl, nil
+}
+
+// ListTemplates lists active templates
+func (s *NotificationTemplateService) ListTemplates(ctx context.Context, filter *template.Filter) ([]*template.Template, error) {
+	templates, err := s.templateService.ListTemplates(ctx, filter)
+	if err != nil {
+		log.Errorw("failed to list notification templates", "error", err)
+		return nil, errors.New("failed to list notification templates")
+	}
+	return templates, nil
+}
+
+// DeleteTemplate deactivates a template
+func (s *NotificationTemplateService) DeleteTemplate(ctx context.Context, templateID string) error {
+	if _, err := s.GetTemplate(ctx, templateID); err != nil {
+		return err
+	}
+	if err := s.templateService.DeleteTemplate(ctx, templateID); err != nil {
+		log.Errorw("failed to delete notification template", "templateID", templateID, "error", err)
+		return errors.New("failed to delete notification template")
+	}
+	log.Infow("notification template deleted", "templateID", templateID)
+	return nil
+}
+
+// RenderTemplate renders a template; variables missing from data are filled
+// with "<variable>" placeholders so that a preview is always produced
+func (s *NotificationTemplateService) RenderTemplate(
+	ctx context.Context,
+	templateID string,
+	data map[string]any,
+) (string, error) {
+	tmpl, err := s.GetTemplate(ctx, templateID)
+	if err != nil {
+		return "", err
+	}
+	return s.templateService.RenderTemplate(ctx, tmpl.ID, sampleTemplateData(tmpl.Variables, data))
+}
+
+// TestTemplate renders a template and sends it through a registered channel
+func (s *NotificationTemplateService) TestTemplate(
+	ctx context.Context,
+	templateID, channelName string,
+	data map[string]any,
+) (string, error) {
+	if strings.TrimSpace(channelName) == "" {
+		return "", errors.New("channel is required")
+	}
+	content, err := s.RenderTemplate(ctx, templateID, data)
+	if err != nil {
+		return "", err
+	}
+	if err := s.manager.Send(ctx, channelName, content); err != nil {
+		return content, fmt.Errorf("send test message: %w", err)
+	}
+	return content, nil
+}
+
+func validateNotificationTemplate(tmpl *template.Template) error {
+	if tmpl.Name == "" {
+		return errors.New("name is required")
+	}
+	if !template.IsValidType(tmpl.Type) {
+		return fmt.Errorf("invalid type %q, must be one of: %s, %s", tmpl.Type, template.Build, template.Approval)
+	}
+	if strings.TrimSpace(tmpl.Content) == "" {
+		return errors.New("content is required")
+	}
+	return nil
+}
+
+func sampleTemplateData(variables []string, data map[string]any) map[string]any {
+	out := make(map[string]any, len(variables)+len(data))
+	for _, v := range variables {
+		out[v] = "<" + v + ">"
+	}
+	for k, v := range data {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,876 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"github.com/arcentrix/arcentra/internal/shared/notify"
+	"github.com/arcentrix/arcentra/internal/shared/notify/channel"
+	"github.com/arcentrix/arcentra/pkg/id"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"gorm.io/gorm"
+)
+
+const invitationTokenPrefix = "aoi_"
+
+var (
+	ErrOrganizationNotFound     = errors.New("organization not found")
+	ErrOrgPermissionDenied      = errors.New("organization permission denied")
+	ErrOrgQuotaExceeded         = errors.New("organization quota exceeded")
+	ErrInvitationNotFound       = errors.New("invitation not found")
+	ErrInvitationNotPending     = errors.New("invitation is no longer pending")
+	ErrInvitationExpired        = errors.New("invitation has expired")
+	ErrInvitationEmailMismatch  = errors.New("invitation was sent to a different email")
+	ErrOrgLastOwner             = errors.New("organization must keep at least one owner")
+	errOrganizationNotAvailable = errors.New("organization is not active")
+)
+
+// OrganizationService manages organizations, their members and invitations,
+// and enforces plan quotas for projects and teams created in an organization.
+type OrganizationService struct {
+	orgRepo        repo.IOrganizationRepository
+	memberRepo     repo.IOrganizationMemberRepository
+	invitationRepo repo.IOrganizationInvitationRepository
+	userRepo       repo.IUserRepository
+	settingRepo    repo.ISettingRepository
+	notifyManager  *notify.Manager
+}
+
+func NewOrganizationService(
+	orgRepo repo.IOrganizationRepository,
+	memberRepo repo.IOrganizationMemberRepository,
+	invitationRepo repo.IOrganizationInvitationRepository,
+	userRepo repo.IUserRepository,
+	settingRepo repo.ISettingRepository,
+	notifyManager *notify.Manager,
+) *OrganizationService {
+	return &OrganizationService{
+		orgRepo:        orgRepo,
+		memberRepo:     memberRepo,
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		settingRepo:    settingRepo,
+		notifyManager:  notifyManager,
+	}
+}
+
+// CreateOrganization creates an organization owned by userID.
+func (s *OrganizationService) CreateOrganization(ctx context.Context, req *model.CreateOrganizationReq, userID string) (*model.OrganizationResp, error) {
+	if req.Name == "" {
+		return nil, errors.New("organization name cannot be empty")
+	}
+	exists, err := s.orgRepo.NameExists(ctx, req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("check organization name failed: %w", err)
+	}
+	if exists {
+		return nil, errors.New("organization name already exists")
+	}
+
+	plan := req.Plan
+	if plan == "" {
+		plan = model.PlanFree
+	}
+	if !model.IsValidPlan(plan) {
+		return nil, fmt.Errorf("invalid plan: %s", plan)
+	}
+
+	settingsJSON, err := repo.ConvertSettingsToJSON(req.Settings)
+	if err != nil {
+		return nil, fmt.Errorf("convert settings failed: %w", err)
+	}
+
+	displayName := req.DisplayName
+	if displayName == "" {
+		displayName = req.Name
+	}
+
+	org := &model.Organization{
+		OrgID:       id.GetUUID(),
+		Name:        req.Name,
+		DisplayName: displayName,
+		Description: req.Description,
+		Logo:        req.Logo,
+		Website:     req.Website,
+		Email:       req.Email,
+		Phone:       req.Phone,
+		Address:     req.Address,
+		Settings:    settingsJSON,
+		Plan:        plan,
+		Status:      model.OrgStatusActive,
+		OwnerUserID: userID,
+		IsEnabled:   1,
+	}
+	if err := s.orgRepo.Create(ctx, org); err != nil {
+		log.Errorw("create organization failed", "name", org.Name, "error", err)
+		return nil, fmt.Errorf("create organization failed: %w", err)
+	}
+
+	owner := &model.OrganizationMember{
+		OrgID:  org.OrgID,
+		UserID: userID,
+		RoleID: model.OrgRoleOwner,
+		Status: model.OrgMemberStatusActive,
+	}
+	if user, err := s.userRepo.GetUserByUserID(userID); err == nil {
+		owner.Username = user.Username
+		owner.Email = user.Email
+	}
+	if err := s.memberRepo.Add(ctx, owner); err != nil {
+		log.Errorw("add organization owner failed", "orgID", org.OrgID, "userID", userID, "error", err)
+		return nil, fmt.Errorf("add organization owner failed: %w", err)
+	}
+	org.TotalMembers = 1
+
+	log.Infow("success create organization", "name", org.Name, "orgID", org.OrgID)
+	return model.ToOrganizationResp(org), nil
+}
+
+// UpdateOrganization updates an organization. Only owners and admins may update it,
+// and only owners may change the plan or status.
+func (s *OrganizationService) UpdateOrganization(
+	ctx context.Context,
+	orgID, operatorID string,
+	req *model.UpdateOrganizationReq,
+) (*model.OrganizationResp, error) {
+	if _, err := s.getOrganization(ctx, orgID); err != nil {
+		return nil, err
+	}
+	role, err := s.requireRole(ctx, orgID, operatorID, model.OrgRoleOwner, model.OrgRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if req.DisplayName != nil {
+		updates["display_name"] = *req.DisplayName
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.Logo != nil {
+		updates["logo"] = *req.Logo
+	}
+	if req.Website != nil {
+		updates["website"] = *req.Website
+	}
+	if req.Email != nil {
+		updates["email"] = *req.Email
+	}
+	if req.Phone != nil {
+		updates["phone"] = *req.Phone
+	}
+	if req.Address != nil {
+		updates["address"] = *req.Address
+	}
+	if req.IsEnabled != nil {
+		updates["is_enabled"] = *req.IsEnabled
+	}
+	if req.Settings != nil {
+		settingsJSON, convertErr := repo.ConvertSettingsToJSON(req.Settings)
+		if convertErr != nil {
+			return nil, fmt.Errorf("convert settings failed: %w", convertErr)
+		}
+		updates["settings"] = settingsJSON
+	}
+	if req.Plan != nil || req.Status != nil {
+		if role != model.OrgRoleOwner {
+			return nil, ErrOrgPermissionDenied
+		}
+		if req.Plan != nil {
+			if !model.IsValidPlan(*req.Plan) {
+				return nil, fmt.Errorf("invalid plan: %s", *req.Plan)
+			}
+			updates["plan"] = *req.Plan
+		}
+		if req.Status != nil {
+			if *req.Status == model.OrgStatusDeleted {
+				return nil, errors.New("use delete to remove an organization")
+			}
+			updates["status"] = *req.Status
+		}
+	}
+
+	if len(updates) > 0 {
+		updates["updated_at"] = time.Now()
+		if err := s.orgRepo.Update(ctx, orgID, updates); err != nil {
+			log.Errorw("update organization failed", "orgID", orgID, "error", err)
+			return nil, fmt.Errorf("update organization failed: %w", err)
+		}
+	}
+
+	return s.GetOrganization(ctx, orgID)
+}
+
+// DeleteOrganization soft-deletes an organization. Only owners may delete it and
+// the organization must not have projects or teams left.
+func (s *OrganizationService) DeleteOrganization(ctx context.Context, orgID, operatorID string) error {
+	if _, err := s.getOrganization(ctx, orgID); err != nil {
+		return err
+	}
+	if _, err := s.requireRole(ctx, orgID, operatorID, model.OrgRoleOwner); err != nil {
+		return err
+	}
+
+	projects, err := s.orgRepo.CountProjects(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("count organization projects failed: %w", err)
+	}
+	if projects > 0 {
+		return fmt.Errorf("organization has %d projects, cannot delete", projects)
+	}
+	teams, err := s.orgRepo.CountTeams(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("count organization teams failed: %w", err)
+	}
+	if teams > 0 {
+		return fmt.Errorf("organization has %d teams, cannot delete", teams)
+	}
+
+	if err := s.orgRepo.Delete(ctx, orgID); err != nil {
+		log.Errorw("delete organization failed", "orgID", orgID, "error", err)
+		return fmt.Errorf("delete organization failed: %w", err)
+	}
+	log.Infow("success delete organization", "orgID", orgID)
+	return nil
+}
+
+// GetOrganization returns an organization by orgID.
+func (s *OrganizationService) GetOrganization(ctx context.Context, orgID string) (*model.OrganizationResp, error) {
+	org, err := s.getOrganization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return model.ToOrganizationResp(org), nil
+}
+
+// ListOrganizations lists organizations with query filters.
+func (s *OrganizationService) ListOrganizations(ctx context.Context, query *model.OrganizationQueryReq) (*model.OrganizationListResp, error) {
+	orgs, total, err := s.orgRepo.List(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list organizations failed: %w", err)
+	}
+
+	resp := &model.OrganizationListResp{
+		Organizations: make([]*model.OrganizationResp, 0, len(orgs)),
+		Total:         total,
+		Page:          query.Page,
+		PageSize:      query.PageSize,
+	}
+	for _, org := range orgs {
+		resp.Organizations = append(resp.Organizations, model.ToOrganizationResp(org))
+	}
+	if query.PageSize > 0 {
+		resp.TotalPages = int((total + int64(query.PageSize) - 1) / int64(query.PageSize))
+	}
+	return resp, nil
+}
+
+// ListUserOrganizations lists organizations the user is an active member of.
+func (s *OrganizationService) ListUserOrganizations(ctx context.Context, userID string) ([]*model.OrganizationResp, error) {
+	orgs, err := s.orgRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user organizations failed: %w", err)
+	}
+	resp := make([]*model.OrganizationResp, 0, len(orgs))
+	for _, org := range orgs {
+		resp = append(resp, model.ToOrganizationResp(org))
+	}
+	return resp, nil
+}
+
+// ListMembers lists members of an organization. The operator must be a member.
+func (s *OrganizationService) ListMembers(ctx context.Context, orgID, operatorID string) ([]*model.OrganizationMember, error) {
+	if _, err := s.getOrganization(ctx, orgID); err != nil {
+		return nil, err
+	}
+	if _, err := s.requireRole(ctx, orgID, operatorID, model.OrgRoleOwner, model.OrgRoleAdmin, model.OrgRoleMember); err != nil {
+		return nil, err
+	}
+	return s.memberRepo.List(ctx, orgID)
+}
+
+// AddMember adds an existing user to an organization directly.
+func (s *OrganizationService) AddMember(ctx context.Context, orgID, operatorID, userID, role string) (*model.OrganizationMember, error) {
+	org, err := s.getActiveOrganization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	operatorRole, err := s.requireRole(ctx, orgID, operatorID, model.OrgRoleOwner, model.OrgRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		role = model.OrgRoleMember
+	}
+	if !model.IsValidOrgRole(role) {
+		return nil, fmt.Errorf("invalid organization role: %s", role)
+	}
+	if role == model.OrgRoleOwner && operatorRole != model.OrgRoleOwner {
+		return nil, ErrOrgPermissionDenied
+	}
+	if _, err := s.memberRepo.Get(ctx, orgID, userID); err == nil {
+		return nil, errors.New("user is already a member of the organization")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("get organization member failed: %w", err)
+	}
+	user, err := s.userRepo.GetUserByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user failed: %w", err)
+	}
+	if err := s.checkMemberQuota(ctx, org, 0); err != nil {
+		return nil, err
+	}
+
+	member := &model.OrganizationMember{
+		OrgID:     orgID,
+		UserID:    userID,
+		RoleID:    role,
+		Username:  user.Username,
+		Email:     user.Email,
+		InvitedBy: operatorID,
+		Status:    model.OrgMemberStatusActive,
+	}
+	if err := s.memberRepo.Add(ctx, member); err != nil {
+		return nil, fmt.Errorf("add organization member failed: %w", err)
+	}
+	s.refreshStatistics(ctx, orgID)
+	return member, nil
+}
+
+// UpdateMember changes the role or status of a member. Only owners may grant or
+// revoke the owner role, and the last active owner cannot be demoted or disabled.
+func (s *OrganizationService) UpdateMember(
+	ctx context.Context,
+	orgID, operatorID, userID string,
+	req *model.UpdateOrganizationMemberReq,
+) (*model.OrganizationMember, error) {
+	if _, err := s.getOrganization(ctx, orgID); err != nil {
+		return nil, err
+	}
+	operatorRole, err := s.requireRole(ctx, orgID, operatorID, model.OrgRoleOwner, model.OrgRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.memberRepo.Get(ctx, orgID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("organization member not found")
+		}
+		return nil, fmt.Errorf("get organization member failed: %w", err)
+	}
+
+	updates := make(map[string]interface{})
+	losesOwner := false
+	if req.Role != nil && *req.Role != member.RoleID {
+		if !model.IsValidOrgRole(*req.Role) {
+			return nil, fmt.Errorf("invalid organization role: %s", *req.Role)
+		}
+		if (*req.Role == model.OrgRoleOwner || member.RoleID == model.OrgRoleOwner) && operatorRole != model.OrgRoleOwner {
+			return nil, ErrOrgPermissionDenied
+		}
+		losesOwner = member.RoleID == model.OrgRoleOwner
+		updates["role_id"] = *req.Role
+	}
+	if req.Status != nil && *req.Status != member.Status {
+		if *req.Status != model.OrgMemberStatusActive && *req.Status != model.OrgMemberStatusDisabled {
+			return nil, fmt.Errorf("invalid member status: %d", *req.Status)
+		}
+		if member.RoleID == model.OrgRoleOwner && operatorRole != model.OrgRoleOwner {
+			return nil, ErrOrgPermissionDenied
+		}
+		losesOwner = losesOwner || (member.RoleID == model.OrgRoleOwner && *req.Status == model.OrgMemberStatusDisabled)
+		updates["status"] = *req.Status
+	}
+	if len(updates) == 0 {
+		return member, nil
+	}
+	if losesOwner && member.Status == model.OrgMemberStatusActive {
+		if err := s.ensureAnotherOwner(ctx, orgID); err != nil {
+			return nil, err
+		}
+	}
+
+	updates["updated_at"] = time.Now()
+	if err := s.memberRepo.Update(ctx, orgID, userID, updates); err != nil {
+		return nil, fmt.Errorf("update organization member failed: %w", err)
+	}
+	s.refreshStatistics(ctx, orgID)
+	return s.memberRepo.Get(ctx, orgID, userID)
+}
+
+// RemoveMember removes a member from an organization. Members may remove
+// themselves; removing others requires the admin role, and owners can only be
+// removed by owners.
+func (s *OrganizationService) RemoveMember(ctx context.Context, orgID, operatorID, userID string) error {
+	if _, err := s.getOrganization(ctx, orgID); err != nil {
+		return err
+	}
+	member, err := s.memberRepo.Get(ctx, orgID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("organization member not found")
+		}
+		return fmt.Errorf("get organization member failed: %w", err)
+	}
+	if operatorID != userID {
+		operatorRole, err := s.requireRole(ctx, orgID, operatorID, model.OrgRoleOwner, model.OrgRoleAdmin)
+		if err != nil {
+			return err
+		}
+		if member.RoleID == model.OrgRoleOwner && operatorRole != model.OrgRoleOwner {
+			return ErrOrgPermissionDenied
+		}
+	}
+	if member.RoleID == model.OrgRoleOwner && member.Status == model.OrgMemberStatusActive {
+		if err := s.ensureAnotherOwner(ctx, orgID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.memberRepo.Remove(ctx, orgID, userID); err != nil {
+		return fmt.Errorf("remove organization member failed: %w", err)
+	}
+	s.refreshStatistics(ctx, orgID)
+	return nil
+}
+
+// CreateInvitation invites email to join an organization and sends the invitation
+// through the configured email notification channel. The plain token is only
+// returned here; the database keeps its hash.
+func (s *OrganizationService) CreateInvitation(
+	ctx context.Context,
+	orgID, operatorID string,
+	req *model.CreateOrganizationInvitationReq,
+) (*model.OrganizationInvitationResp, error) {
+	org, err := s.getActiveOrganization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	operatorRole, err := s.requireRole(ctx, orgID, operatorID, model.OrgRoleOwner, model.OrgRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if email == "" || !strings.Contains(email, "@") {
+		return nil, errors.New("invalid email")
+	}
+	role := req.Role
+	if role == "" {
+		role = model.OrgRoleMember
+	}
+	if !model.IsValidOrgRole(role) {
+		return nil, fmt.Errorf("invalid organization role: %s", role)
+	}
+	if role == model.OrgRoleOwner && operatorRole != model.OrgRoleOwner {
+		return nil, ErrOrgPermissionDenied
+	}
+
+	s.expireInvitations(ctx)
+	if _, err := s.invitationRepo.GetPendingByEmail(ctx, orgID, email); err == nil {
+		return nil, errors.New("a pending invitation already exists for this email")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("get pending invitation failed: %w", err)
+	}
+	pending, err := s.invitationRepo.CountPending(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("count pending invitations failed: %w", err)
+	}
+	if err := s.checkMemberQuota(ctx, org, pending); err != nil {
+		return nil, err
+	}
+
+	token, tokenHash, err := newInvitationToken()
+	if err != nil {
+		return nil, err
+	}
+	inv := &model.OrganizationInvitation{
+		InvitationID: id.GetUUID(),
+		OrgID:        orgID,
+		Email:        email,
+		Role:         role,
+		Token:        tokenHash,
+		InvitedBy:    operatorID,
+		Status:       model.InvitationStatusPending,
+		ExpiresAt:    time.Now().Add(model.InvitationTTL).Format(time.DateTime),
+	}
+	if err := s.invitationRepo.Create(ctx, inv); err != nil {
+		log.Errorw("create organization invitation failed", "orgID", orgID, "email", email, "error", err)
+		return nil, fmt.Errorf("create invitation failed: %w", err)
+	}
+
+	resp := model.ToOrganizationInvitationResp(inv)
+	resp.Token = token
+	if err := s.sendInvitationEmail(ctx, org, inv, operatorID, token); err != nil {
+		log.Warnw("send organization invitation email failed", "orgID", orgID, "invitationID", inv.InvitationID, "error", err)
+	} else {
+		resp.EmailSent = true
+	}
+
+	log.Infow("success create organization invitation", "orgID", orgID, "invitationID", inv.InvitationID)
+	return resp, nil
+}
+
+// ListInvitations lists invitations of an organization, optionally filtered by
+// status. The operator must be an owner or admin.
+func (s *OrganizationService) ListInvitations(
+	ctx context.Context,
+	orgID, operatorID string,
+	status *int,
+) ([]*model.OrganizationInvitationResp, error) {
+	if _, err := s.getOrganization(ctx, orgID); err != nil {
+		return nil, err
+	}
+	if _, err := s.requireRole(ctx, orgID, operatorID, model.OrgRoleOwner, model.OrgRoleAdmin); err != nil {
+		return nil, err
+	}
+	s.expireInvitations(ctx)
+	invs, err := s.invitationRepo.ListByOrg(ctx, orgID, status)
+	if err != nil {
+		return nil, fmt.Errorf("list invitations failed: %w", err)
+	}
+	resp := make([]*model.OrganizationInvitationResp, 0, len(invs))
+	for _, inv := range invs {
+		resp = append(resp, model.ToOrganizationInvitationResp(inv))
+	}
+	return resp, nil
+}
+
+// RevokeInvitation deletes an invitation so its token can no longer be used.
+func (s *OrganizationService) RevokeInvitation(ctx context.Context, orgID, operatorID, invitationID string) error {
+	if _, err := s.requireRole(ctx, orgID, operatorID, model.OrgRoleOwner, model.OrgRoleAdmin); err != nil {
+		return err
+	}
+	inv, err := s.invitationRepo.Get(ctx, invitationID)
+	if err != nil || inv.OrgID != orgID {
+		return ErrInvitationNotFound
+	}
+	if err := s.invitationRepo.Delete(ctx, invitationID); err != nil {
+		return fmt.Errorf("revoke invitation failed: %w", err)
+	}
+	return nil
+}
+
+// AcceptInvitation adds userID to the inviting organization. The user's email must
+// match the invited email.
+func (s *OrganizationService) AcceptInvitation(ctx context.Context, token, userID string) (*model.OrganizationResp, error) {
+	inv, user, err := s.pendingInvitationForUser(ctx, token, userID)
+	if err != nil {
+		return nil, err
+	}
+	org, err := s.getActiveOrganization(ctx, inv.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.memberRepo.Get(ctx, inv.OrgID, userID)
+	switch {
+	case err == nil && existing.Status == model.OrgMemberStatusActive:
+		// already a member, only consume the invitation
+	case err == nil:
+		if err := s.checkMemberQuota(ctx, org, 0); err != nil {
+			return nil, err
+		}
+		if err := s.memberRepo.Update(ctx, inv.OrgID, userID, map[string]interface{}{
+			"role_id":    inv.Role,
+			"status":     model.OrgMemberStatusActive,
+			"updated_at": time.Now(),
+		}); err != nil {
+			return nil, fmt.Errorf("activate organization member failed: %w", err)
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if err := s.checkMemberQuota(ctx, org, 0); err != nil {
+			return nil, err
+		}
+		if err := s.memberRepo.Add(ctx, &model.OrganizationMember{
+			OrgID:     inv.OrgID,
+			UserID:    userID,
+			RoleID:    inv.Role,
+			Username:  user.Username,
+			Email:     user.Email,
+			InvitedBy: inv.InvitedBy,
+			Status:    model.OrgMemberStatusActive,
+		}); err != nil {
+			return nil, fmt.Errorf("add organization member failed: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("get organization member failed: %w", err)
+	}
+
+	if err := s.invitationRepo.UpdateStatus(ctx, inv.InvitationID, model.InvitationStatusAccepted); err != nil {
+		return nil, fmt.Errorf("update invitation status failed: %w", err)
+	}
+	s.refreshStatistics(ctx, inv.OrgID)
+
+	log.Infow("organization invitation accepted", "orgID", inv.OrgID, "invitationID", inv.InvitationID, "userID", userID)
+	return s.GetOrganization(ctx, org.OrgID)
+}
+
+// DeclineInvitation rejects an invitation addressed to userID.
+func (s *OrganizationService) DeclineInvitation(ctx context.Context, token, userID string) error {
+	inv, _, err := s.pendingInvitationForUser(ctx, token, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.invitationRepo.UpdateStatus(ctx, inv.InvitationID, model.InvitationStatusRejected); err != nil {
+		return fmt.Errorf("update invitation status failed: %w", err)
+	}
+	return nil
+}
+
+// CheckProjectQuota returns an error when a project cannot be created in orgID,
+// either because the organization is not active or its project quota is used up.
+func (s *OrganizationService) CheckProjectQuota(ctx context.Context, orgID string) error {
+	org, err := s.getActiveOrganization(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	limit := org.Quota().MaxProjects
+	if limit <= 0 {
+		return nil
+	}
+	count, err := s.orgRepo.CountProjects(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("count organization projects failed: %w", err)
+	}
+	if count >= int64(limit) {
+		return fmt.Errorf("%w: plan %s allows %d projects", ErrOrgQuotaExceeded, org.Plan, limit)
+	}
+	return nil
+}
+
+// CheckTeamQuota returns an error when a team cannot be created in orgID.
+func (s *OrganizationService) CheckTeamQuota(ctx context.Context, orgID string) error {
+	org, err := s.getActiveOrganization(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	limit := org.Quota().MaxTeams
+	if limit <= 0 {
+		return nil
+	}
+	count, err := s.orgRepo.CountTeams(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("count organization teams failed: %w", err)
+	}
+	if count >= int64(limit) {
+		return fmt.Errorf("%w: plan %s allows %d teams", ErrOrgQuotaExceeded, org.Plan, limit)
+	}
+	return nil
+}
+
+// RefreshStatistics recounts the organization's members, teams and projects.
+func (s *OrganizationService) RefreshStatistics(ctx context.Context, orgID string) {
+	s.refreshStatistics(ctx, orgID)
+}
+
+func (s *OrganizationService) refreshStatistics(ctx context.Context, orgID string) {
+	if err := s.orgRepo.UpdateStatistics(ctx, orgID); err != nil {
+		log.Warnw("update organization statistics failed", "orgID", orgID, "error", err)
+	}
+}
+
+func (s *OrganizationService) getOrganization(ctx context.Context, orgID string) (*model.Organization, error) {
+	if orgID == "" {
+		return nil, errors.New("organization id cannot be empty")
+	}
+	org, err := s.orgRepo.Get(ctx, orgID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("get organization failed: %w", err)
+	}
+	return org, nil
+}
+
+func (s *OrganizationService) getActiveOrganization(ctx context.Context, orgID string) (*model.Organization, error) {
+	org, err := s.getOrganization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if org.Status != model.OrgStatusActive || org.IsEnabled != 1 {
+		return nil, errOrganizationNotAvailable
+	}
+	return org, nil
+}
+
+// requireRole returns the operator's role when it is one of roles.
+func (s *OrganizationService) requireRole(ctx context.Context, orgID, userID string, roles ...string) (string, error) {
+	member, err := s.memberRepo.Get(ctx, orgID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrOrgPermissionDenied
+		}
+		return "", fmt.Errorf("get organization member failed: %w", err)
+	}
+	if member.Status != model.OrgMemberStatusActive {
+		return "", ErrOrgPermissionDenied
+	}
+	for _, role := range roles {
+		if member.RoleID == role {
+			return member.RoleID, nil
+		}
+	}
+	return "", ErrOrgPermissionDenied
+}
+
+func (s *OrganizationService) ensureAnotherOwner(ctx context.Context, orgID string) error {
+	owners, err := s.memberRepo.CountByRole(ctx, orgID, model.OrgRoleOwner)
+	if err != nil {
+		return fmt.Errorf("count organization owners failed: %w", err)
+	}
+	if owners <= 1 {
+		return ErrOrgLastOwner
+	}
+	return nil
+}
+
+// checkMemberQuota checks that active members plus reserved seats (pending
+// invitations) leave room for one more member.
+func (s *OrganizationService) checkMemberQuota(ctx context.Context, org *model.Organization, reserved int64) error {
+	limit := org.Quota().MaxMembers
+	if limit <= 0 {
+		return nil
+	}
+	count, err := s.memberRepo.CountActive(ctx, org.OrgID)
+	if err != nil {
+		return fmt.Errorf("count organization members failed: %w", err)
+	}
+	if count+reserved >= int64(limit) {
+		return fmt.Errorf("%w: plan %s allows %d members", ErrOrgQuotaExceeded, org.Plan, limit)
+	}
+	return nil
+}
+
+// pendingInvitationForUser resolves token to a pending, unexpired invitation
+// addressed to userID's email.
+func (s *OrganizationService) pendingInvitationForUser(
+	ctx context.Context,
+	token, userID string,
+) (*model.OrganizationInvitation, *model.User, error) {
+	if token == "" {
+		return nil, nil, ErrInvitationNotFound
+	}
+	inv, err := s.invitationRepo.GetByToken(ctx, hashInvitationToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvitationNotFound
+		}
+		return nil, nil, fmt.Errorf("get invitation failed: %w", err)
+	}
+	if inv.Status != model.InvitationStatusPending {
+		if inv.Status == model.InvitationStatusExpired {
+			return nil, nil, ErrInvitationExpired
+		}
+		return nil, nil, ErrInvitationNotPending
+	}
+	if invitationExpired(inv, time.Now()) {
+		if err := s.invitationRepo.UpdateStatus(ctx, inv.InvitationID, model.InvitationStatusExpired); err != nil {
+			log.Warnw("mark invitation expired failed", "invitationID", inv.InvitationID, "error", err)
+		}
+		return nil, nil, ErrInvitationExpired
+	}
+
+	user, err := s.userRepo.GetUserByUserID(userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get user failed: %w", err)
+	}
+	if !strings.EqualFold(strings.TrimSpace(user.Email), inv.Email) {
+		return nil, nil, ErrInvitationEmailMismatch
+	}
+	return inv, user, nil
+}
+
+// expireInvitations marks pending invitations past their expiry as expired.
+func (s *OrganizationService) expireInvitations(ctx context.Context) {
+	n, err := s.invitationRepo.ExpirePending(ctx, time.Now().Format(time.DateTime))
+	if err != nil {
+		log.Warnw("expire organization invitations failed", "error", err)
+		return
+	}
+	if n > 0 {
+		log.Infow("organization invitations expired", "count", n)
+	}
+}
+
+func (s *OrganizationService) sendInvitationEmail(
+	ctx context.Context,
+	org *model.Organization,
+	inv *model.OrganizationInvitation,
+	inviterID, token string,
+) error {
+	if s.notifyManager == nil {
+		return errors.New("notify manager is not configured")
+	}
+	ch, err := s.notifyManager.BuildEmailChannel(ctx, inv.Email)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = ch.Close() }()
+
+	inviter := inviterID
+	if user, err := s.userRepo.GetUserByUserID(inviterID); err == nil && user.Username != "" {
+		inviter = user.Username
+	}
+	title := fmt.Sprintf("Invitation to join %s on Arcentra", org.DisplayName)
+	content := fmt.Sprintf("%s invited you to join the organization %s as %s. The invitation expires at %s.",
+		inviter, org.DisplayName, inv.Role, inv.ExpiresAt)
+
+	baseURL, err := getExternalURL(ctx, s.settingRepo)
+	if err != nil {
+		// without an external URL the token is sent for manual acceptance
+		content += " Invitation token: " + token
+		return ch.SendInteractive(ctx, title, content, nil)
+	}
+	acceptURL := fmt.Sprintf("%sinvitations/accept?token=%s", ensureTrailingSlash(baseURL), token)
+	return ch.SendInteractive(ctx, title, content, []channel.InteractiveAction{
+		{Label: "Accept invitation", ActionID: "accept", CallbackURL: acceptURL, Style: "primary"},
+	})
+}
+
+func invitationExpired(inv *model.OrganizationInvitation, now time.Time) bool {
+	expiresAt, err := time.ParseInLocation(time.DateTime, inv.ExpiresAt, time.Local)
+	if err != nil {
+		return false
+	}
+	return now.After(expiresAt)
+}
+
+// newInvitationToken returns a random invitation token and its hash.
+func newInvitationToken() (string, string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+	token := invitationTokenPrefix + hex.EncodeToString(b)
+	return token, hashInvitationToken(token), nil
+}
+
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"gorm.io/gorm"
+)
+
+type fakeOrgRepo struct {
+	repo.IOrganizationRepository
+	orgs map[string]*model.Organization
+}
+
+func (r fakeOrgRepo) Get(_ context.Context, orgID string) (*model.Organization, error) {
+	if org, ok := r.orgs[orgID]; ok {
+		return org, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type fakeOrgMembers struct {
+	repo.IOrganizationMemberRepository
+	members []*model.OrganizationMember
+}
+
+func (r fakeOrgMembers) Get(_ context.Context, orgID, userID string) (*model.OrganizationMember, error) {
+	for _, m := range r.members {
+		if m.OrgID == orgID && m.UserID == userID {
+			return m, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r fakeOrgMembers) List(_ context.Context, orgID string) ([]*model.OrganizationMember, error) {
+	var out []*model.OrganizationMember
+	for _, m := range r.members {
+		if m.OrgID == orgID {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+type fakeOrgInvitations struct {
+	repo.IOrganizationInvitationRepository
+	invitations []*model.OrganizationInvitation
+}
+
+func (r fakeOrgInvitations) ListByOrg(_ context.Context, orgID string, _ *int) ([]*model.OrganizationInvitation, error) {
+	var out []*model.OrganizationInvitation
+	for _, inv := range r.invitations {
+		if inv.OrgID == orgID {
+			out = append(out, inv)
+		}
+	}
+	return out, nil
+}
+
+func (r fakeOrgInvitations) ExpirePending(context.Context, string) (int64, error) { return 0, nil }
+
+func newOrgListFixture() *OrganizationService {
+	member := func(userID, role string, status int) *model.OrganizationMember {
+		return &model.OrganizationMember{OrgID: "org1", UserID: userID, RoleID: role, Status: status}
+	}
+	return NewOrganizationService(
+		fakeOrgRepo{orgs: map[string]*model.Organization{"org1": {OrgID: "org1"}}},
+		fakeOrgMembers{members: []*model.OrganizationMember{
+			member("owner", model.OrgRoleOwner, model.OrgMemberStatusActive),
+			member("admin", model.OrgRoleAdmin, model.OrgMemberStatusActive),
+			member("dev", model.OrgRoleMember, model.OrgMemberStatusActive),
+			member("left", model.OrgRoleAdmin, model.OrgMemberStatusDisabled),
+		}},
+		fakeOrgInvitations{invitations: []*model.OrganizationInvitation{
+			{OrgID: "org1", InvitationID: "inv1", Email: "new@example.com"},
+		}},
+		nil, nil, nil,
+	)
+}
+
+func TestListMembersRequiresMembership(t *testing.T) {
+	s := newOrgListFixture()
+	ctx := context.Background()
+
+	for _, userID := range []string{"owner", "admin", "dev"} {
+		members, err := s.ListMembers(ctx, "org1", userID)
+		if err != nil {
+			t.Fatalf("%s: ListMembers: %v", userID, err)
+		}
+		if len(members) != 4 {
+			t.Fatalf("%s: got %d members, want 4", userID, len(members))
+		}
+	}
+	for _, userID := range []string{"stranger", "left", ""} {
+		if _, err := s.ListMembers(ctx, "org1", userID); !errors.Is(err, ErrOrgPermissionDenied) {
+			t.Fatalf("%q: ListMembers err = %v, want ErrOrgPermissionDenied", userID, err)
+		}
+	}
+	if _, err := s.ListMembers(ctx, "missing", "owner"); !errors.Is(err, ErrOrganizationNotFound) {
+		t.Fatalf("missing org: err = %v, want ErrOrganizationNotFound", err)
+	}
+}
+
+func TestListInvitationsRequiresAdmin(t *testing.T) {
+	s := newOrgListFixture()
+	ctx := context.Background()
+
+	for _, userID := range []string{"owner", "admin"} {
+		invs, err := s.ListInvitations(ctx, "org1", userID, nil)
+		if err != nil {
+			t.Fatalf("%s: ListInvitations: %v", userID, err)
+		}
+		if len(invs) != 1 {
+			t.Fatalf("%s: got %d invitations, want 1", userID, len(invs))
+		}
+	}
+	for _, userID := range []string{"dev", "stranger", "left"} {
+		if _, err := s.ListInvitations(ctx, "org1", userID, nil); !errors.Is(err, ErrOrgPermissionDenied) {
+			t.Fatalf("%s: ListInvitations err = %v, want ErrOrgPermissionDenied", userID, err)
+		}
+	}
+}
@@ -0,0 +1,399 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	pipelinev1 "github.com/arcentrix/arcentra/api/pipeline/v1"
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
+	tmpl "github.com/arcentrix/arcentra/internal/shared/pipeline/template"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/trigger"
+	"github.com/arcentrix/arcentra/pkg/id"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/arcentrix/arcentra/pkg/scm"
+	"github.com/arcentrix/arcentra/pkg/serde"
+)
+
+const (
+	// pipelineDiscoveryActor is recorded as creator and editor of the
+	// pipelines managed by discovery.
+	pipelineDiscoveryActor = "pipeline-discovery"
+	// pipelineDiscoveryRemoved is the sync message of pipelines disabled
+	// because their definition file was deleted. Only those are re-enabled
+	// when the file comes back; pipelines disabled by hand stay disabled.
+	pipelineDiscoveryRemoved = "definition file removed from repository"
+)
+
+// eventSource holds what the pipelines matched against one SCM event share:
+// the changed files, fetched at most once, and repository snapshots at the
+// event's commit, cloned at most once per repository.
+type eventSource struct {
+	ev           *scm.Event
+	project      *model.Project
+	prov         scm.Provider
+	filesLoaded  bool
+	snapshots    map[string]*repoSnapshot
+	snapshotErrs map[string]error
+}
+
+func newEventSource(project *model.Project, prov scm.Provider, ev *scm.Event) *eventSource {
+	return &eventSource{
+		ev:           ev,
+		project:      project,
+		prov:         prov,
+		snapshots:    map[string]*repoSnapshot{},
+		snapshotErrs: map[string]error{},
+	}
+}
+
+// ref returns the branch or tag name the event happened on.
+func (e *eventSource) ref() string {
+	ref := strings.TrimPrefix(trigger.NormalizeBranch(e.ev.Ref), "refs/tags/")
+	if ref == "" && e.ev.Change != nil {
+		ref = e.ev.Change.SourceBranch
+	}
+	return ref
+}
+
+// commit returns the commit the event points at; PR/MR events use the head
+// of the source branch.
+func (e *eventSource) commit() string {
+	if e.ev.CommitID == "" && e.ev.Change != nil {
+		return e.ev.Change.HeadCommitID
+	}
+	return e.ev.CommitID
+}
+
+// loadChangedFiles populates the changed files of the event when the payload
+// did not carry them.
+func (e *eventSource) loadChangedFiles(ctx context.Context) {
+	if e.filesLoaded {
+		return
+	}
+	e.filesLoaded = true
+	if err := scm.FillChangedFiles(ctx, e.prov, e.ev); err != nil {
+		log.Warnw("webhook trigger: list changed files failed",
+			"projectId", e.project.ProjectID, "eventType", e.ev.EventType, "error", err)
+	}
+}
+
+// snapshot returns repoURL checked out at the event's commit.
+func (e *eventSource) snapshot(ctx context.Context, repoURL string) (*repoSnapshot, error) {
+	key := normalizeRepoURL(repoURL)
+	if snap, ok := e.snapshots[key]; ok {
+		return snap, nil
+	}
+	if err, ok := e.snapshotErrs[key]; ok {
+		return nil, err
+	}
+	auth := scm.NewGitAuthFromMap(scmAuthFromProject(e.project))
+	snap, err := openRepoSnapshot(ctx, repoURL, e.ref(), e.commit(), auth)
+	if err != nil {
+		e.snapshotErrs[key] = err
+		return nil, err
+	}
+	e.snapshots[key] = snap
+	return snap, nil
+}
+
+// definition reads the definition of p at the event's commit. Pipelines
+// backed by another repository than the project's fall back to the HEAD of
+// their default branch, since the event's commit does not exist there.
+func (e *eventSource) definition(ctx context.Context, p *model.Pipeline) (string, string, error) {
+	if normalizeRepoURL(p.RepoURL) != normalizeRepoURL(e.project.RepoURL) {
+		return LoadPipelineDefinition(ctx, p, e.project)
+	}
+	snap, err := e.snapshot(ctx, p.RepoURL)
+	if err != nil {
+		return "", "", err
+	}
+	content, err := snap.ReadFile(p.PipelineFilePath)
+	if err != nil {
+		return "", "", err
+	}
+	return content, snap.sha, nil
+}
+
+// close removes the snapshots cloned for the event.
+func (e *eventSource) close() {
+	for _, snap := range e.snapshots {
+		snap.Close()
+	}
+}
+
+// normalizeRepoURL returns a comparable form of a repository URL.
+func normalizeRepoURL(repoURL string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimRight(strings.TrimSpace(repoURL), "/"), ".git"))
+}
+
+// listProjectPipelines returns all pipelines of a project.
+func (s *ScmService) listProjectPipelines(ctx context.Context, projectID string) ([]*model.Pipeline, error) {
+	var out []*model.Pipeline
+	query := &repo.PipelineQuery{ProjectID: projectID, Page: 1, PageSize: 100}
+	for {
+		list, total, err := s.pipelineRepo.List(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, list...)
+		if len(list) == 0 || int64(len(out)) >= total {
+			return out, nil
+		}
+		query.Page++
+	}
+}
+
+// parseDefinition expands the includes of a definition and parses it.
+func (s *ScmService) parseDefinition(ctx context.Context, projectID, content string) (*pipelinev1.Spec, error) {
+	if s.templateResolver != nil {
+		expanded, err := tmpl.ResolveIncludes(ctx, content, s.templateResolver, model.TemplateScopeProject, projectID)
+		if err != nil {
+			return nil, err
+		}
+		content = expanded
+	}
+	return spec.ParseContentToProto(content, pipelinev1.SpecFormat_SPEC_FORMAT_UNSPECIFIED)
+}
+
+// discoveryEnabled reports whether ev should sync the project's pipelines
+// from the repository: discovery is on and ev is a push to the default branch.
+func discoveryEnabled(project *model.Project, ev *scm.Event) (model.PipelineDiscoverySettings, bool) {
+	if ev.EventType != scm.EventTypePush || scm.IsZeroCommitID(ev.CommitID) {
+		return model.PipelineDiscoverySettings{}, false
+	}
+	branch := project.DefaultBranch
+	if branch == "" {
+		branch = defaultBranch
+	}
+	if trigger.NormalizeBranch(ev.Ref) != branch {
+		return model.PipelineDiscoverySettings{}, false
+	}
+	return project.PipelineDiscovery()
+}
+
+// discoverPipelines makes the project's discovered pipelines match the
+// definition files found at the pushed commit: new files create pipelines,
+// existing ones are refreshed and pipelines whose file is gone are disabled.
+// Pipelines outside the discovery paths are left alone. A file discovered by
+// a concurrent push is not created twice; the unique discovery path makes the
+// later push refresh the pipeline instead. It returns pipelines with the
+// changes applied.
+func (s *ScmService) discoverPipelines(
+	ctx context.Context,
+	src *eventSource,
+	settings model.PipelineDiscoverySettings,
+	pipelines []*model.Pipeline,
+) []*model.Pipeline {
+	project := src.project
+	snap, err := src.snapshot(ctx, project.RepoURL)
+	if err != nil {
+		log.Warnw("pipeline discovery: checkout failed",
+			"projectId", project.ProjectID, "commit", src.commit(), "error", err)
+		return pipelines
+	}
+	files, err := snap.Glob(settings.Paths)
+	if err != nil {
+		log.Warnw("pipeline discovery: scan failed", "projectId", project.ProjectID, "error", err)
+		return pipelines
+	}
+
+	managed := map[string]*model.Pipeline{}
+	for _, p := range pipelines {
+		filePath := normalizePipelinePath(p.PipelineFilePath)
+		if normalizeRepoURL(p.RepoURL) != normalizeRepoURL(project.RepoURL) || !matchDiscoveryPath(settings.Paths, filePath) {
+			continue
+		}
+		if _, dup := managed[filePath]; !dup {
+			managed[filePath] = p
+		}
+	}
+
+	now := time.Now()
+	found := map[string]struct{}{}
+	for _, filePath := range files {
+		found[filePath] = struct{}{}
+		content, readErr := snap.ReadFile(filePath)
+		var parsed *pipelinev1.Spec
+		if readErr == nil {
+			parsed, readErr = s.parseDefinition(ctx, project.ProjectID, content)
+		}
+		if p, ok := managed[filePath]; ok {
+			s.refreshDiscoveredPipeline(ctx, p, filePath, parsed, readErr, snap.sha, now)
+			continue
+		}
+		p := newDiscoveredPipeline(project, filePath, parsed, readErr, snap.sha, now)
+		created, err := s.pipelineRepo.CreateDiscovered(ctx, p)
+		if err != nil {
+			log.Warnw("pipeline discovery: create pipeline failed",
+				"projectId", project.ProjectID, "path", filePath, "error", err)
+			continue
+		}
+		if created {
+			log.Infow("pipeline discovered",
+				"projectId", project.ProjectID, "pipelineId", p.PipelineID, "path", filePath, "commit", snap.sha)
+		} else {
+			// another push discovered the file meanwhile; p is that pipeline
+			s.refreshDiscoveredPipeline(ctx, p, filePath, parsed, readErr, snap.sha, now)
+		}
+		pipelines = append(pipelines, p)
+	}
+
+	for filePath, p := range managed {
+		if _, ok := found[filePath]; ok || p.IsEnabled != 1 {
+			continue
+		}
+		updates := map[string]any{
+			"is_enabled":        0,
+			"last_commit_sha":   snap.sha,
+			"last_sync_status":  model.PipelineSyncStatusSuccess,
+			"last_sync_message": pipelineDiscoveryRemoved,
+			"last_synced_at":    now,
+		}
+		if err := s.pipelineRepo.Update(ctx, p.PipelineID, updates); err != nil {
+			log.Warnw("pipeline discovery: disable pipeline failed",
+				"pipelineId", p.PipelineID, "path", filePath, "error", err)
+			continue
+		}
+		p.IsEnabled = 0
+		p.LastCommitSha = snap.sha
+		p.LastSyncStatus = model.PipelineSyncStatusSuccess
+		p.LastSyncMessage = pipelineDiscoveryRemoved
+		p.LastSyncedAt = &now
+		log.Infow("pipeline disabled, definition removed",
+			"projectId", project.ProjectID, "pipelineId", p.PipelineID, "path", filePath, "commit", snap.sha)
+	}
+	return pipelines
+}
+
+// refreshDiscoveredPipeline records the sync result of an existing pipeline
+// whose definition file was found at commit.
+func (s *ScmService) refreshDiscoveredPipeline(
+	ctx context.Context,
+	p *model.Pipeline,
+	filePath string,
+	parsed *pipelinev1.Spec,
+	parseErr error,
+	commit string,
+	now time.Time,
+) {
+	if p.LastCommitSha == commit && p.LastSyncMessage != pipelineDiscoveryRemoved {
+		return
+	}
+	updates := map[string]any{
+		"last_commit_sha": commit,
+		"last_synced_at":  now,
+	}
+	if parseErr != nil {
+		updates["last_sync_status"] = model.PipelineSyncStatusFailed
+		updates["last_sync_message"] = parseErr.Error()
+	} else {
+		updates["last_sync_status"] = model.PipelineSyncStatusSuccess
+		updates["last_sync_message"] = ""
+		name, description := discoveredPipelineMeta(filePath, parsed)
+		updates["name"] = name
+		updates["description"] = description
+	}
+	if p.IsEnabled != 1 && p.LastSyncMessage == pipelineDiscoveryRemoved {
+		updates["is_enabled"] = 1
+	}
+	if err := s.pipelineRepo.Update(ctx, p.PipelineID, updates); err != nil {
+		log.Warnw("pipeline discovery: update pipeline failed",
+			"pipelineId", p.PipelineID, "path", filePath, "error", err)
+		return
+	}
+	p.LastCommitSha = commit
+	p.LastSyncedAt = &now
+	p.LastSyncStatus, _ = updates["last_sync_status"].(int)
+	p.LastSyncMessage, _ = updates["last_sync_message"].(string)
+	if v, ok := updates["name"].(string); ok {
+		p.Name = v
+	}
+	if v, ok := updates["description"].(string); ok {
+		p.Description = v
+	}
+	if _, ok := updates["is_enabled"]; ok {
+		p.IsEnabled = 1
+	}
+}
+
+// newDiscoveredPipeline builds the pipeline of a newly found definition file.
+func newDiscoveredPipeline(
+	project *model.Project,
+	filePath string,
+	parsed *pipelinev1.Spec,
+	parseErr error,
+	commit string,
+	now time.Time,
+) *model.Pipeline {
+	branch := project.DefaultBranch
+	if branch == "" {
+		branch = defaultBranch
+	}
+	name, description := discoveredPipelineMeta(filePath, parsed)
+	p := &model.Pipeline{
+		PipelineID:       id.GetUild(),
+		ProjectID:        project.ProjectID,
+		Name:             name,
+		Description:      description,
+		RepoURL:          project.RepoURL,
+		DefaultBranch:    branch,
+		PipelineFilePath: filePath,
+		DiscoveryPath:    &filePath,
+		SaveMode:         model.PipelineSaveModeDirect,
+		Metadata:         serde.MarshalStringMap(map[string]string{"source": "discovery"}),
+		Status:           model.PipelineStatusPending,
+		LastSyncStatus:   model.PipelineSyncStatusSuccess,
+		LastSyncedAt:     &now,
+		LastEditor:       pipelineDiscoveryActor,
+		LastCommitSha:    commit,
+		CreatedBy:        pipelineDiscoveryActor,
+		IsEnabled:        1,
+	}
+	if parseErr != nil {
+		p.LastSyncStatus = model.PipelineSyncStatusFailed
+		p.LastSyncMessage = parseErr.Error()
+	}
+	return p
+}
+
+// discoveredPipelineMeta returns the name and description of a discovered
+// pipeline: meta.name and meta.description of the spec, or the file name.
+func discoveredPipelineMeta(filePath string, parsed *pipelinev1.Spec) (string, string) {
+	name := strings.TrimSuffix(path.Base(filePath), path.Ext(filePath))
+	description := ""
+	if fields := parsed.GetMeta().GetFields(); fields != nil {
+		if v := strings.TrimSpace(fields["name"].GetStringValue()); v != "" {
+			name = v
+		}
+		description = strings.TrimSpace(fields["description"].GetStringValue())
+	}
+	return name, description
+}
+
+// matchDiscoveryPath reports whether filePath matches any discovery pattern.
+func matchDiscoveryPath(patterns []string, filePath string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, filePath); ok {
+			return true
+		}
+	}
+	return false
+}
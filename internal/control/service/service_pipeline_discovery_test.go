@@ -0,0 +1,163 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"github.com/arcentrix/arcentra/pkg/scm"
+)
+
+func TestMatchDiscoveryPath(t *testing.T) {
+	patterns := []string{".arcentra/*.yml", "ci/pipelines/*.yaml"}
+	cases := map[string]bool{
+		".arcentra/build.yml":        true,
+		"ci/pipelines/deploy.yaml":   true,
+		".arcentra/build.yaml":       false,
+		".arcentra/nested/build.yml": false,
+		"ci/pipelines/deploy.yml":    false,
+		"build.yml":                  false,
+	}
+	for filePath, want := range cases {
+		if got := matchDiscoveryPath(patterns, filePath); got != want {
+			t.Errorf("matchDiscoveryPath(%q) = %v, want %v", filePath, got, want)
+		}
+	}
+	if matchDiscoveryPath(nil, ".arcentra/build.yml") {
+		t.Error("no pattern must match nothing")
+	}
+	if matchDiscoveryPath([]string{"[invalid"}, "[invalid") {
+		t.Error("a malformed pattern must match nothing")
+	}
+}
+
+// discoveryRepo stores pipelines in memory and enforces the unique
+// discovery path like the database does.
+type discoveryRepo struct {
+	repo.IPipelineRepository
+	pipelines []*model.Pipeline
+}
+
+func (r *discoveryRepo) CreateDiscovered(_ context.Context, p *model.Pipeline) (bool, error) {
+	for _, existing := range r.pipelines {
+		if existing.ProjectID == p.ProjectID && existing.DiscoveryPath != nil && *existing.DiscoveryPath == *p.DiscoveryPath {
+			*p = *existing
+			return false, nil
+		}
+	}
+	stored := *p
+	r.pipelines = append(r.pipelines, &stored)
+	return true, nil
+}
+
+func (r *discoveryRepo) Update(_ context.Context, pipelineID string, updates map[string]any) error {
+	for _, p := range r.pipelines {
+		if p.PipelineID != pipelineID {
+			continue
+		}
+		if v, ok := updates["is_enabled"].(int); ok {
+			p.IsEnabled = v
+		}
+		if v, ok := updates["last_commit_sha"].(string); ok {
+			p.LastCommitSha = v
+		}
+	}
+	return nil
+}
+
+func (r *discoveryRepo) find(pipelineID string) *model.Pipeline {
+	for _, p := range r.pipelines {
+		if p.PipelineID == pipelineID {
+			return p
+		}
+	}
+	return nil
+}
+
+func writeRepoFiles(t *testing.T, files ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range files {
+		full := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("meta:\n  name: "+filepath.Base(name)+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestDiscoverPipelinesReconciles(t *testing.T) {
+	project := &model.Project{ProjectID: "p1", RepoURL: "https://git.example.com/acme/app.git", DefaultBranch: "main"}
+	pathOf := func(s string) *string { return &s }
+	pipeline := func(id, repoURL, filePath string, enabled int) *model.Pipeline {
+		return &model.Pipeline{
+			PipelineID: id, ProjectID: "p1", RepoURL: repoURL, PipelineFilePath: filePath,
+			DiscoveryPath: pathOf(filePath), IsEnabled: enabled, LastCommitSha: "old",
+		}
+	}
+	kept := pipeline("kept", project.RepoURL, ".arcentra/build.yml", 1)
+	removed := pipeline("removed", project.RepoURL, ".arcentra/old.yml", 1)
+	elsewhere := pipeline("elsewhere", "https://git.example.com/acme/other.git", ".arcentra/old.yml", 1)
+	manual := &model.Pipeline{PipelineID: "manual", ProjectID: "p1", RepoURL: project.RepoURL, PipelineFilePath: "deploy/pipeline.yml", IsEnabled: 1}
+	// discovered by a concurrent push after the caller listed the pipelines
+	raced := pipeline("raced", project.RepoURL, ".arcentra/race.yml", 1)
+
+	store := &discoveryRepo{pipelines: []*model.Pipeline{kept, removed, elsewhere, manual, raced}}
+	s := NewScmService(nil, store)
+	src := newEventSource(project, nil, &scm.Event{EventType: scm.EventTypePush, Ref: "refs/heads/main", CommitID: "c1"})
+	src.snapshots[normalizeRepoURL(project.RepoURL)] = &repoSnapshot{
+		workdir: writeRepoFiles(t, ".arcentra/build.yml", ".arcentra/new.yml", ".arcentra/race.yml", "deploy/pipeline.yml"),
+		sha:     "c1",
+	}
+	settings := model.PipelineDiscoverySettings{Enabled: true, Paths: []string{".arcentra/*.yml"}}
+	listed := []*model.Pipeline{kept, removed, elsewhere, manual}
+
+	got := s.discoverPipelines(context.Background(), src, settings, listed)
+
+	if len(store.pipelines) != 6 {
+		t.Fatalf("stored %d pipelines, want 6 (one new)", len(store.pipelines))
+	}
+	created := store.pipelines[5]
+	if created.PipelineFilePath != ".arcentra/new.yml" || created.DiscoveryPath == nil || created.IsEnabled != 1 {
+		t.Fatalf("created pipeline = %+v", created)
+	}
+	if kept.LastCommitSha != "c1" || kept.IsEnabled != 1 {
+		t.Fatalf("kept pipeline not refreshed: sha %q enabled %d", kept.LastCommitSha, kept.IsEnabled)
+	}
+	if removed.IsEnabled != 0 || store.find("removed").IsEnabled != 0 {
+		t.Fatal("pipeline whose file is gone not disabled")
+	}
+	if elsewhere.IsEnabled != 1 || manual.IsEnabled != 1 || manual.LastCommitSha != "" {
+		t.Fatal("pipelines outside discovery were changed")
+	}
+	if store.find("raced").LastCommitSha != "c1" {
+		t.Fatal("pipeline discovered concurrently not refreshed")
+	}
+	ids := map[string]int{}
+	for _, p := range got {
+		ids[p.PipelineID]++
+	}
+	if len(got) != 6 || ids["raced"] != 1 || ids[created.PipelineID] != 1 {
+		t.Fatalf("returned pipelines %v, want the listed ones plus new and raced", ids)
+	}
+}
@@ -16,8 +16,10 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/arcentrix/arcentra/internal/control/model"
@@ -33,32 +35,129 @@ func LoadPipelineDefinition(
 	pipeline *model.Pipeline,
 	project *model.Project,
 ) (string, string, error) {
-	auth := scmAuthFromProject(project)
-	workdir, err := os.MkdirTemp("", "arcentra-pipeline-read-*")
+	return LoadPipelineDefinitionAt(ctx, pipeline, project, pipeline.DefaultBranch, "")
+}
+
+// LoadPipelineDefinitionAt reads the pipeline's definition file at commit,
+// fetched through ref (a branch or tag name). An empty commit reads the HEAD
+// of ref. It returns the file content and the commit SHA it was read at.
+func LoadPipelineDefinitionAt(
+	ctx context.Context,
+	pipeline *model.Pipeline,
+	project *model.Project,
+	ref, commit string,
+) (string, string, error) {
+	snap, err := openRepoSnapshot(ctx, pipeline.RepoURL, ref, commit, scm.NewGitAuthFromMap(scmAuthFromProject(project)))
 	if err != nil {
 		return "", "", err
 	}
-	defer func() { _ = os.RemoveAll(workdir) }()
+	defer snap.Close()
 
-	if cloneErr := scm.Clone(scm.GitCloneRequest{
-		Workdir: workdir,
-		RepoURL: pipeline.RepoURL,
-		Branch:  pipeline.DefaultBranch,
-		Auth:    scm.NewGitAuthFromMap(auth),
-	}); cloneErr != nil {
-		return "", "", cloneErr
-	}
-	headSha, err := scm.HeadSHA(scm.GitHeadSHARequest{Workdir: workdir})
+	content, err := snap.ReadFile(pipeline.PipelineFilePath)
 	if err != nil {
 		return "", "", err
 	}
-	filePath := strings.TrimLeft(strings.TrimSpace(pipeline.PipelineFilePath), "/")
-	content, err := os.ReadFile(filepath.Join(workdir, filePath))
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return "", "", ctxErr
+	}
+	return content, snap.sha, nil
+}
+
+// repoSnapshot is a shallow working copy of a repository at one commit.
+type repoSnapshot struct {
+	workdir string
+	sha     string
+}
+
+// openRepoSnapshot clones repoURL at ref and moves to commit when it is not
+// the tip of ref. When ref cannot be cloned (e.g. the source branch of a
+// fork), the default branch is cloned and commit fetched by SHA instead.
+func openRepoSnapshot(ctx context.Context, repoURL, ref, commit string, auth scm.GitAuth) (*repoSnapshot, error) {
+	commit = strings.TrimSpace(commit)
+	if scm.IsZeroCommitID(commit) {
+		return nil, fmt.Errorf("ref %s was deleted", ref)
+	}
+	workdir, err := os.MkdirTemp("", "arcentra-pipeline-read-*")
 	if err != nil {
-		return "", "", err
+		return nil, err
+	}
+	snap := &repoSnapshot{workdir: workdir}
+
+	cloneErr := scm.Clone(scm.GitCloneRequest{Workdir: workdir, RepoURL: repoURL, Branch: ref, Auth: auth})
+	if cloneErr != nil && ref != "" && commit != "" {
+		_ = os.RemoveAll(workdir)
+		if err := os.MkdirAll(workdir, 0o700); err != nil {
+			return nil, err
+		}
+		cloneErr = scm.Clone(scm.GitCloneRequest{Workdir: workdir, RepoURL: repoURL, Auth: auth})
+	}
+	if cloneErr != nil {
+		snap.Close()
+		return nil, cloneErr
+	}
+	if snap.sha, err = scm.HeadSHA(scm.GitHeadSHARequest{Workdir: workdir}); err != nil {
+		snap.Close()
+		return nil, err
+	}
+	if commit != "" && !strings.EqualFold(commit, snap.sha) {
+		if err := scm.Fetch(scm.GitFetchRequest{Workdir: workdir, Ref: commit, Auth: auth}); err != nil {
+			snap.Close()
+			return nil, err
+		}
+		if err := scm.CheckoutDetached(scm.GitCheckoutRequest{Workdir: workdir, Ref: "FETCH_HEAD"}); err != nil {
+			snap.Close()
+			return nil, err
+		}
+		if snap.sha, err = scm.HeadSHA(scm.GitHeadSHARequest{Workdir: workdir}); err != nil {
+			snap.Close()
+			return nil, err
+		}
 	}
 	if ctxErr := ctx.Err(); ctxErr != nil {
-		return "", "", ctxErr
+		snap.Close()
+		return nil, ctxErr
 	}
-	return string(content), headSha, nil
+	return snap, nil
+}
+
+// ReadFile returns the content of a repository-relative file.
+func (r *repoSnapshot) ReadFile(name string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(r.workdir, filepath.FromSlash(normalizePipelinePath(name))))
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// Glob returns the sorted, repository-relative paths of the regular files
+// matching any of patterns.
+func (r *repoSnapshot) Glob(patterns []string) ([]string, error) {
+	seen := map[string]struct{}{}
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(r.workdir, filepath.FromSlash(normalizePipelinePath(pattern))))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if info, statErr := os.Lstat(match); statErr != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			rel, relErr := filepath.Rel(r.workdir, match)
+			if relErr != nil {
+				continue
+			}
+			seen[filepath.ToSlash(rel)] = struct{}{}
+		}
+	}
+	files := make([]string, 0, len(seen))
+	for one := range seen {
+		files = append(files, one)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Close removes the working copy.
+func (r *repoSnapshot) Close() {
+	_ = os.RemoveAll(r.workdir)
 }
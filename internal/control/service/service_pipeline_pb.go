@@ -27,6 +27,7 @@ import (
 	"github.com/arcentrix/arcentra/internal/control/repo"
 	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
 	tmpl "github.com/arcentrix/arcentra/internal/shared/pipeline/template"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/trigger"
 	"github.com/arcentrix/arcentra/internal/shared/pipeline/validation"
 	"github.com/arcentrix/arcentra/pkg/dispatch"
 	"github.com/arcentrix/arcentra/pkg/id"
@@ -43,6 +44,8 @@ const defaultBranch = "main"
 // used to avoid circular dependency between service and process packages.
 type IPipelineEngine interface {
 	Submit(run *model.PipelineRun, parsedSpec *spec.Pipeline) error
+	PlanRerun(parsedSpec *spec.Pipeline, succeeded map[string]string, mode, fromJob string) (reused, scheduled []string, err error)
+	SubmitRerun(run *model.PipelineRun, parsedSpec *spec.Pipeline, succeeded map[string]string, fromJob string) error
 	CancelRun(runID string) error
 	PauseRun(runID string) error
 	ResumeRun(runID string) error
@@ -52,6 +55,7 @@ type PipelineServiceImpl struct {
 	pipelinev1.UnimplementedPipelineServiceServer
 	pipelineRepo     repo.IPipelineRepository
 	projectRepo      repo.IProjectRepository
+	jobRunRepo       repo.IJobRunRepository
 	engine           IPipelineEngine
 	templateResolver *PipelineTemplateService
 }
@@ -61,6 +65,7 @@ func NewPipelineServiceImpl(services *Services) *PipelineServiceImpl {
 	return &PipelineServiceImpl{
 		pipelineRepo:     services.PipelineRepo,
 		projectRepo:      services.ProjectRepo,
+		jobRunRepo:       services.JobRunRepo,
 		engine:           services.PipelineEngine,
 		templateResolver: services.PipelineTemplate,
 	}
@@ -280,6 +285,9 @@ func (s *PipelineServiceImpl) DeletePipeline(
 			Error:   s.error(500, err.Error(), "internal", nil),
 		}, nil
 	}
+	if err := s.pipelineRepo.ReplaceUpstreams(ctx, req.GetPipelineId(), nil); err != nil {
+		log.Warnw("remove pipeline triggers failed", "pipelineId", req.GetPipelineId(), "error", err)
+	}
 	return &pipelinev1.DeletePipelineResponse{Success: true, Message: "pipeline deleted"}, nil
 }
 
@@ -321,44 +329,12 @@ func (s *PipelineServiceImpl) TriggerPipeline(
 			}, nil
 		}
 	}
-	project, err := s.projectRepo.Get(ctx, pipeline.ProjectID)
-	if err != nil {
+	parsedSpec, headSha, message, apiErr := s.loadRunSpec(ctx, pipeline)
+	if apiErr != nil {
 		return &pipelinev1.TriggerPipelineResponse{
 			Success: false,
-			Message: "project not found",
-			Error:   s.error(404, err.Error(), "not_found", nil),
-		}, nil
-	}
-
-	content, headSha, err := s.loadDefinitionFromRepo(ctx, pipeline, project)
-	if err != nil {
-		return &pipelinev1.TriggerPipelineResponse{
-			Success: false,
-			Message: "load definition failed",
-			Error:   s.error(500, err.Error(), "internal", nil),
-		}, nil
-	}
-	content, err = s.resolveIncludes(ctx, content, pipeline.ProjectID)
-	if err != nil {
-		return &pipelinev1.TriggerPipelineResponse{
-			Success: false,
-			Message: "resolve includes failed",
-			Error:   s.error(400, err.Error(), "validation", nil),
-		}, nil
-	}
-	parsedSpec, err := spec.ParseContentToProto(content, pipelinev1.SpecFormat_SPEC_FORMAT_UNSPECIFIED)
-	if err != nil {
-		return &pipelinev1.TriggerPipelineResponse{
-			Success: false,
-			Message: "spec parse failed",
-			Error:   s.error(400, err.Error(), "validation", nil),
-		}, nil
-	}
-	if err := validation.NewSchemaValidator().Validate(parsedSpec); err != nil {
-		return &pipelinev1.TriggerPipelineResponse{
-			Success: false,
-			Message: "spec validation failed",
-			Error:   s.error(400, err.Error(), "validation", nil),
+			Message: message,
+			Error:   apiErr,
 		}, nil
 	}
 
@@ -579,13 +555,63 @@ func (s *PipelineServiceImpl) GetPipelineRun(
 			Error:   s.error(404, err.Error(), "not_found", nil),
 		}, nil
 	}
+	detail := toPipelineRunDetail(run)
+	s.fillRunGraph(ctx, detail, run)
 	return &pipelinev1.GetPipelineRunResponse{
 		Success: true,
 		Message: "ok",
-		Run:     toPipelineRunDetail(run),
+		Run:     detail,
 	}, nil
 }
 
+// maxDownstreamRuns caps the downstream links returned for a single run.
+const maxDownstreamRuns = 100
+
+// fillRunGraph resolves the upstream link of a run and lists the runs it
+// triggered through pipeline triggers.
+func (s *PipelineServiceImpl) fillRunGraph(ctx context.Context, detail *pipelinev1.PipelineRunDetail, run *model.PipelineRun) {
+	projects := make(map[string]string) // pipelineID -> projectID
+	if run.UpstreamRunID != "" {
+		if upstream, err := s.pipelineRepo.GetRun(ctx, run.UpstreamRunID); err == nil && upstream != nil {
+			detail.Upstream = s.toPipelineRunLink(ctx, upstream, projects)
+		}
+	}
+	downstream, _, err := s.pipelineRepo.ListRuns(ctx, &repo.PipelineRunQuery{
+		UpstreamRunID: run.RunID,
+		Page:          1,
+		PageSize:      maxDownstreamRuns,
+	})
+	if err != nil {
+		log.Warnw("list downstream runs failed", "runId", run.RunID, "error", err)
+		return
+	}
+	for _, item := range downstream {
+		detail.Downstream = append(detail.Downstream, s.toPipelineRunLink(ctx, item, projects))
+	}
+}
+
+// toPipelineRunLink converts a linked run, resolving its project through the
+// projects cache.
+func (s *PipelineServiceImpl) toPipelineRunLink(
+	ctx context.Context, run *model.PipelineRun, projects map[string]string,
+) *pipelinev1.PipelineRunLink {
+	projectID, ok := projects[run.PipelineID]
+	if !ok {
+		if p, err := s.pipelineRepo.Get(ctx, run.PipelineID); err == nil && p != nil {
+			projectID = p.ProjectID
+		}
+		projects[run.PipelineID] = projectID
+	}
+	return &pipelinev1.PipelineRunLink{
+		RunId:        run.RunID,
+		PipelineId:   run.PipelineID,
+		PipelineName: run.PipelineName,
+		ProjectId:    projectID,
+		Branch:       run.Branch,
+		Status:       pipelinev1.PipelineStatus(run.Status),
+	}
+}
+
 func (s *PipelineServiceImpl) ListPipelineRuns(
 	ctx context.Context,
 	req *pipelinev1.ListPipelineRunsRequest,
@@ -883,6 +909,13 @@ func (s *PipelineServiceImpl) SavePipelineSpec(
 		"last_commit_sha":      commitSha,
 		"last_save_request_id": strings.TrimSpace(req.GetRequestId()),
 	})
+	// Saved to a change request, the definition takes effect on merge, when
+	// the push records its pipeline triggers.
+	if mode != model.PipelineSaveModePR {
+		if err := trigger.RecordPipelineTriggers(ctx, s.pipelineRepo, pipeline, req.GetSpec()); err != nil {
+			log.Warnw("record pipeline triggers failed", "pipelineId", pipeline.PipelineID, "error", err)
+		}
+	}
 
 	return &pipelinev1.SavePipelineSpecResponse{
 		Success:   true,
@@ -917,6 +950,45 @@ func (s *PipelineServiceImpl) readDefinition(
 	return p, project, content, headSha, nil
 }
 
+// loadRunSpec reads the pipeline definition from its repository, resolves
+// includes and validates it for a new run. On failure it returns the response
+// message and error to report.
+func (s *PipelineServiceImpl) loadRunSpec(
+	ctx context.Context,
+	pipeline *model.Pipeline,
+) (*spec.Pipeline, string, string, *pipelinev1.Error) {
+	return s.loadRunSpecAt(ctx, pipeline, pipeline.DefaultBranch, "")
+}
+
+// loadRunSpecAt is loadRunSpec reading the definition at commit, fetched
+// through ref. An empty commit reads the HEAD of ref.
+func (s *PipelineServiceImpl) loadRunSpecAt(
+	ctx context.Context,
+	pipeline *model.Pipeline,
+	ref, commit string,
+) (*spec.Pipeline, string, string, *pipelinev1.Error) {
+	project, err := s.projectRepo.Get(ctx, pipeline.ProjectID)
+	if err != nil {
+		return nil, "", "project not found", s.error(404, err.Error(), "not_found", nil)
+	}
+	content, headSha, err := LoadPipelineDefinitionAt(ctx, pipeline, project, ref, commit)
+	if err != nil {
+		return nil, "", "load definition failed", s.error(500, err.Error(), "internal", nil)
+	}
+	content, err = s.resolveIncludes(ctx, content, pipeline.ProjectID)
+	if err != nil {
+		return nil, "", "resolve includes failed", s.error(400, err.Error(), "validation", nil)
+	}
+	parsedSpec, err := spec.ParseContentToProto(content, pipelinev1.SpecFormat_SPEC_FORMAT_UNSPECIFIED)
+	if err != nil {
+		return nil, "", "spec parse failed", s.error(400, err.Error(), "validation", nil)
+	}
+	if err := validation.NewSchemaValidator().Validate(parsedSpec); err != nil {
+		return nil, "", "spec validation failed", s.error(400, err.Error(), "validation", nil)
+	}
+	return parsedSpec, headSha, "", nil
+}
+
 func (s *PipelineServiceImpl) loadDefinitionFromRepo(
 	ctx context.Context,
 	pipeline *model.Pipeline,
@@ -987,7 +1059,7 @@ func toPipelineRunDetail(run *model.PipelineRun) *pipelinev1.PipelineRunDetail {
 	if run == nil {
 		return nil
 	}
-	return &pipelinev1.PipelineRunDetail{
+	detail := &pipelinev1.PipelineRunDetail{
 		RunId:               run.RunID,
 		PipelineId:          run.PipelineID,
 		PipelineName:        run.PipelineName,
@@ -1006,7 +1078,13 @@ func toPipelineRunDetail(run *model.PipelineRun) *pipelinev1.PipelineRunDetail {
 		StartTime:           timepkg.ToUnix(run.StartTime),
 		EndTime:             timepkg.ToUnix(run.EndTime),
 		Duration:            run.Duration,
+		RerunOfRunId:        run.RerunOfRunID,
+		RerunMode:           run.RerunMode,
+	}
+	if run.UpstreamRunID != "" {
+		detail.Upstream = &pipelinev1.PipelineRunLink{RunId: run.UpstreamRunID}
 	}
+	return detail
 }
 
 func mapSaveModeToModel(mode pipelinev1.PipelineSaveMode) int {
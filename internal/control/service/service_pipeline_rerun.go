@@ -0,0 +1,212 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"strings"
+
+	pipelinev1 "github.com/arcentrix/arcentra/api/pipeline/v1"
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
+	"github.com/arcentrix/arcentra/pkg/id"
+	"github.com/arcentrix/arcentra/pkg/log"
+)
+
+// RerunPipeline creates a new run from a finished one. Depending on the mode
+// it runs every job again ("all"), only the jobs that did not succeed
+// ("failed-only"), or a job and everything downstream of it ("from-job").
+// Jobs that are not run again reuse the source JobRun results and artifacts.
+// The new run uses the definition the source run ran with, not the current
+// HEAD, so reused results and re-run jobs come from the same spec.
+func (s *PipelineServiceImpl) RerunPipeline(
+	ctx context.Context,
+	req *pipelinev1.RerunPipelineRequest,
+) (*pipelinev1.RerunPipelineResponse, error) {
+	pipelineID := strings.TrimSpace(req.GetPipelineId())
+	runID := strings.TrimSpace(req.GetRunId())
+	if pipelineID == "" || runID == "" {
+		return s.rerunFailed("pipelineID and runID are required", s.error(400, "pipelineID and runID are required", "validation", nil)), nil
+	}
+	mode := strings.TrimSpace(req.GetMode())
+	if mode == "" {
+		mode = model.RerunModeFailedOnly
+	}
+	if !isValidRerunMode(mode) {
+		return s.rerunFailed("invalid re-run mode", s.error(400, "invalid re-run mode: "+mode, "validation", nil)), nil
+	}
+
+	source, err := s.pipelineRepo.GetRun(ctx, runID)
+	if err != nil || source.PipelineID != pipelineID {
+		msg := "run not found"
+		if err != nil {
+			msg = err.Error()
+		}
+		return s.rerunFailed("run not found", s.error(404, msg, "not_found", nil)), nil
+	}
+	if !isPipelineRunFinished(source.Status) {
+		return s.rerunFailed("run is not finished", s.error(409, "only finished runs can be re-run", "conflict", nil)), nil
+	}
+
+	p, err := s.pipelineRepo.Get(ctx, pipelineID)
+	if err != nil {
+		return s.rerunFailed("pipeline not found", s.error(404, err.Error(), "not_found", nil)), nil
+	}
+	requestID := strings.TrimSpace(req.GetRequestId())
+	if requestID != "" {
+		existing, getErr := s.pipelineRepo.GetRunByRequestID(ctx, pipelineID, requestID)
+		if getErr != nil {
+			return s.rerunFailed("check request id failed", s.error(500, getErr.Error(), "internal", nil)), nil
+		}
+		if existing != nil {
+			return &pipelinev1.RerunPipelineResponse{
+				Success: true,
+				Message: "idempotent request",
+				RunId:   existing.RunID,
+			}, nil
+		}
+	}
+
+	parsedSpec, definitionSha, message, apiErr := s.loadSourceRunSpec(ctx, p, source)
+	if apiErr != nil {
+		return s.rerunFailed(message, apiErr), nil
+	}
+
+	if s.engine == nil {
+		return s.rerunFailed("pipeline engine unavailable", s.error(503, "pipeline engine is not running", "unavailable", nil)), nil
+	}
+	succeeded, err := s.succeededJobRuns(ctx, source.RunID)
+	if err != nil {
+		return s.rerunFailed("list job runs failed", s.error(500, err.Error(), "internal", nil)), nil
+	}
+	reused, scheduled, err := s.engine.PlanRerun(parsedSpec, succeeded, mode, req.GetJobName())
+	if err != nil {
+		return s.rerunFailed("plan re-run failed", s.error(400, err.Error(), "validation", nil)), nil
+	}
+
+	run := &model.PipelineRun{
+		RunID:               id.GetUild(),
+		PipelineID:          p.PipelineID,
+		RequestID:           requestID,
+		PipelineName:        p.Name,
+		Branch:              source.Branch,
+		CommitSha:           source.CommitSha,
+		DefinitionCommitSha: definitionSha,
+		DefinitionPath:      definitionPath(p, source),
+		Status:              model.PipelineStatusPending,
+		TriggerType:         int(pipelinev1.TriggerType_TRIGGER_TYPE_MANUAL),
+		TriggeredBy:         strings.TrimSpace(req.GetTriggeredBy()),
+		Env:                 source.Env,
+		RerunOfRunID:        source.RunID,
+		RerunMode:           mode,
+		ChangeRequest:       source.ChangeRequest,
+	}
+	if err := s.pipelineRepo.CreateRun(ctx, run); err != nil {
+		if requestID != "" && isDuplicateEntryError(err) {
+			existing, getErr := s.pipelineRepo.GetRunByRequestID(ctx, pipelineID, requestID)
+			if getErr == nil && existing != nil {
+				return &pipelinev1.RerunPipelineResponse{
+					Success: true,
+					Message: "idempotent request",
+					RunId:   existing.RunID,
+				}, nil
+			}
+		}
+		return s.rerunFailed("create run failed", s.error(500, err.Error(), "internal", nil)), nil
+	}
+	_ = s.pipelineRepo.Update(ctx, p.PipelineID, map[string]any{
+		"status":     model.PipelineStatusPending,
+		"total_runs": p.TotalRuns + 1,
+	})
+
+	if err := s.engine.SubmitRerun(run, parsedSpec, succeeded, req.GetJobName()); err != nil {
+		log.Warnw("process submit failed, re-run created but not executing",
+			"runId", run.RunID, "sourceRunId", source.RunID, "error", err)
+	}
+
+	return &pipelinev1.RerunPipelineResponse{
+		Success:       true,
+		Message:       "pipeline re-run triggered",
+		RunId:         run.RunID,
+		ReusedJobs:    reused,
+		ScheduledJobs: scheduled,
+	}, nil
+}
+
+// succeededJobRuns maps the jobs of a run whose latest JobRun succeeded to
+// that JobRun ID.
+func (s *PipelineServiceImpl) succeededJobRuns(ctx context.Context, runID string) (map[string]string, error) {
+	jobRuns, err := s.jobRunRepo.ListByPipelineRunID(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[string]*model.JobRun, len(jobRuns))
+	for _, jr := range jobRuns {
+		latest[jr.JobName] = jr // ordered by created_at ASC
+	}
+	succeeded := make(map[string]string, len(latest))
+	for name, jr := range latest {
+		if jr.Status == model.JobRunStatusSuccess {
+			succeeded[name] = jr.JobRunID
+		}
+	}
+	return succeeded, nil
+}
+
+func (s *PipelineServiceImpl) rerunFailed(message string, err *pipelinev1.Error) *pipelinev1.RerunPipelineResponse {
+	return &pipelinev1.RerunPipelineResponse{
+		Success: false,
+		Message: message,
+		Error:   err,
+	}
+}
+
+func isValidRerunMode(mode string) bool {
+	return mode == model.RerunModeAll || mode == model.RerunModeFailedOnly || mode == model.RerunModeFromJob
+}
+
+func isPipelineRunFinished(status int) bool {
+	return status == model.PipelineStatusSuccess ||
+		status == model.PipelineStatusFailed ||
+		status == model.PipelineStatusCancelled
+}
+
+// loadSourceRunSpec loads the definition source ran with: its definition file
+// at its DefinitionCommitSha. Runs recorded without a definition commit fall
+// back to the HEAD of the default branch.
+func (s *PipelineServiceImpl) loadSourceRunSpec(
+	ctx context.Context,
+	p *model.Pipeline,
+	source *model.PipelineRun,
+) (*spec.Pipeline, string, string, *pipelinev1.Error) {
+	if source.DefinitionCommitSha == "" {
+		return s.loadRunSpec(ctx, p)
+	}
+	def := *p
+	def.PipelineFilePath = definitionPath(p, source)
+	ref := source.Branch
+	if ref == "" {
+		ref = p.DefaultBranch
+	}
+	return s.loadRunSpecAt(ctx, &def, ref, source.DefinitionCommitSha)
+}
+
+// definitionPath returns the definition file source ran with.
+func definitionPath(p *model.Pipeline, source *model.PipelineRun) string {
+	if source.DefinitionPath != "" {
+		return source.DefinitionPath
+	}
+	return p.PipelineFilePath
+}
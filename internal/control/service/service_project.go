@@ -28,6 +28,7 @@ import (
 
 type ProjectService struct {
 	projectRepo repo.IProjectRepository
+	orgService  *OrganizationService
 }
 
 func NewProjectService(projectRepo repo.IProjectRepository) *ProjectService {
@@ -36,11 +37,21 @@ func NewProjectService(projectRepo repo.IProjectRepository) *ProjectService {
 	}
 }
 
+// SetOrganizationService enables organization checks and plan quotas on create.
+func (s *ProjectService) SetOrganizationService(orgService *OrganizationService) {
+	s.orgService = orgService
+}
+
 // CreateProject creates a project.
 func (s *ProjectService) CreateProject(ctx context.Context, req *model.CreateProjectReq, createdBy string) (*model.Project, error) {
 	if req.OrgID == "" {
 		return nil, errors.New("organization id cannot be empty")
 	}
+	if s.orgService != nil {
+		if err := s.orgService.CheckProjectQuota(ctx, req.OrgID); err != nil {
+			return nil, err
+		}
+	}
 
 	exists, err := s.projectRepo.NameExists(ctx, req.OrgID, req.Name)
 	if err != nil {
@@ -132,6 +143,9 @@ func (s *ProjectService) CreateProject(ctx context.Context, req *model.CreatePro
 	}
 
 	log.Infow("success create project", "name", project.Name, "projectID", project.ProjectID)
+	if s.orgService != nil {
+		s.orgService.RefreshStatistics(ctx, project.OrgID)
+	}
 
 	return project, nil
 }
@@ -246,14 +260,14 @@ func (s *ProjectService) UpdateProject(ctx context.Context, projectID string, re
 
 // DeleteProject deletes a project.
 func (s *ProjectService) DeleteProject(ctx context.Context, projectID string) error {
-	exists, err := s.projectRepo.Exists(ctx, projectID)
+	project, err := s.projectRepo.Get(ctx, projectID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("project not found")
+		}
 		log.Errorw("check project exists failed", "projectID", projectID, "error", err)
 		return fmt.Errorf("check project exists failed: %w", err)
 	}
-	if !exists {
-		return errors.New("project not found")
-	}
 
 	if err := s.projectRepo.Delete(ctx, projectID); err != nil {
 		log.Errorw("delete project failed", "projectID", projectID, "error", err)
@@ -261,6 +275,9 @@ func (s *ProjectService) DeleteProject(ctx context.Context, projectID string) er
 	}
 
 	log.Infow("success delete project", "projectID", projectID)
+	if s.orgService != nil {
+		s.orgService.RefreshStatistics(ctx, project.OrgID)
+	}
 
 	return nil
 }
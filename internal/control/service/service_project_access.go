@@ -0,0 +1,161 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrProjectPermissionDenied = errors.New("project permission denied")
+)
+
+// accessRank orders project access levels; 0 means no access.
+var accessRank = map[string]int{
+	model.AccessLevelRead:  1,
+	model.AccessLevelWrite: 2,
+	model.AccessLevelAdmin: 3,
+}
+
+// ProjectAccessService resolves the access level a user has in a project.
+// The level is the highest one granted by project membership, by the access
+// of the user's teams, and by the user's role in the project's organization;
+// internal and public projects grant read access to organization members and
+// to everyone respectively.
+type ProjectAccessService struct {
+	projectRepo    repo.IProjectRepository
+	memberRepo     repo.IProjectMemberRepository
+	teamAccessRepo repo.IProjectTeamAccessRepository
+	teamMemberRepo repo.ITeamMemberRepository
+	orgMemberRepo  repo.IOrganizationMemberRepository
+}
+
+// NewProjectAccessService creates a project access service.
+func NewProjectAccessService(
+	projectRepo repo.IProjectRepository,
+	memberRepo repo.IProjectMemberRepository,
+	teamAccessRepo repo.IProjectTeamAccessRepository,
+	teamMemberRepo repo.ITeamMemberRepository,
+	orgMemberRepo repo.IOrganizationMemberRepository,
+) *ProjectAccessService {
+	return &ProjectAccessService{
+		projectRepo:    projectRepo,
+		memberRepo:     memberRepo,
+		teamAccessRepo: teamAccessRepo,
+		teamMemberRepo: teamMemberRepo,
+		orgMemberRepo:  orgMemberRepo,
+	}
+}
+
+// Require returns ErrProjectPermissionDenied unless userID has at least
+// level (read, write or admin) in projectID.
+func (s *ProjectAccessService) Require(ctx context.Context, projectID, userID, level string) error {
+	if userID == "" || projectID == "" {
+		return ErrProjectPermissionDenied
+	}
+	granted, err := s.Level(ctx, projectID, userID)
+	if err != nil {
+		return err
+	}
+	if accessRank[granted] < accessRank[level] || accessRank[granted] == 0 {
+		return ErrProjectPermissionDenied
+	}
+	return nil
+}
+
+// Level returns the access level of userID in projectID ("" for none).
+func (s *ProjectAccessService) Level(ctx context.Context, projectID, userID string) (string, error) {
+	project, err := s.projectRepo.Get(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrProjectPermissionDenied
+		}
+		return "", fmt.Errorf("get project failed: %w", err)
+	}
+
+	level := ""
+	grant := func(l string) {
+		if accessRank[l] > accessRank[level] {
+			level = l
+		}
+	}
+
+	if project.Visibility == model.VisibilityPublic {
+		grant(model.AccessLevelRead)
+	}
+	if project.OrgID != "" && s.orgMemberRepo != nil {
+		member, err := s.orgMemberRepo.Get(ctx, project.OrgID, userID)
+		switch {
+		case err == nil && member.Status == model.OrgMemberStatusActive:
+			if member.RoleID == model.OrgRoleOwner || member.RoleID == model.OrgRoleAdmin {
+				return model.AccessLevelAdmin, nil
+			}
+			if project.Visibility == model.VisibilityInternal {
+				grant(model.AccessLevelRead)
+			}
+		case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+			return "", fmt.Errorf("get organization member failed: %w", err)
+		}
+	}
+
+	member, err := s.memberRepo.Get(ctx, projectID, userID)
+	switch {
+	case err == nil:
+		grant(projectRoleAccess(member.RoleID))
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return "", fmt.Errorf("get project member failed: %w", err)
+	}
+
+	if s.teamAccessRepo != nil && s.teamMemberRepo != nil && level != model.AccessLevelAdmin {
+		teams, err := s.teamAccessRepo.ListProjectTeams(ctx, projectID)
+		if err != nil {
+			return "", fmt.Errorf("list project teams failed: %w", err)
+		}
+		if len(teams) > 0 {
+			memberships, err := s.teamMemberRepo.ListUserTeams(ctx, userID)
+			if err != nil {
+				return "", fmt.Errorf("list user teams failed: %w", err)
+			}
+			joined := make(map[string]bool, len(memberships))
+			for _, m := range memberships {
+				joined[m.TeamID] = true
+			}
+			for _, t := range teams {
+				if joined[t.TeamID] {
+					grant(t.AccessLevel)
+				}
+			}
+		}
+	}
+	return level, nil
+}
+
+// projectRoleAccess maps a project member role to an access level.
+func projectRoleAccess(role string) string {
+	switch model.ProjectMemberRole(role) {
+	case model.ProjectRoleOwner, model.ProjectRoleMaintainer:
+		return model.AccessLevelAdmin
+	case model.ProjectRoleDeveloper:
+		return model.AccessLevelWrite
+	default:
+		return model.AccessLevelRead
+	}
+}
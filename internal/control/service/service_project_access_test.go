@@ -0,0 +1,220 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"gorm.io/gorm"
+)
+
+// accessFixture backs the repositories ProjectAccessService reads with maps.
+type accessFixture struct {
+	projects   map[string]*model.Project
+	members    map[string]string // projectID/userID -> role
+	orgMembers map[string]*model.OrganizationMember
+	teamAccess map[string][]model.ProjectTeamAccess // projectID -> grants
+	userTeams  map[string][]model.TeamMember        // userID -> memberships
+	pipelines  map[string]*model.Pipeline
+	stepRuns   map[string]*model.StepRun
+}
+
+func newAccessFixture() *accessFixture {
+	return &accessFixture{
+		projects:   map[string]*model.Project{},
+		members:    map[string]string{},
+		orgMembers: map[string]*model.OrganizationMember{},
+		teamAccess: map[string][]model.ProjectTeamAccess{},
+		userTeams:  map[string][]model.TeamMember{},
+		pipelines:  map[string]*model.Pipeline{},
+		stepRuns:   map[string]*model.StepRun{},
+	}
+}
+
+func (f *accessFixture) service() *ProjectAccessService {
+	return NewProjectAccessService(
+		fakeProjectRepo{f: f},
+		fakeProjectMemberRepo{f: f},
+		fakeTeamAccessRepo{f: f},
+		fakeTeamMemberRepo{f: f},
+		fakeOrgMemberRepo{f: f},
+	)
+}
+
+type fakeProjectRepo struct {
+	repo.IProjectRepository
+	f *accessFixture
+}
+
+func (r fakeProjectRepo) Get(_ context.Context, projectID string) (*model.Project, error) {
+	if p, ok := r.f.projects[projectID]; ok {
+		return p, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r fakeProjectRepo) Exists(_ context.Context, projectID string) (bool, error) {
+	_, ok := r.f.projects[projectID]
+	return ok, nil
+}
+
+type fakeProjectMemberRepo struct {
+	repo.IProjectMemberRepository
+	f *accessFixture
+}
+
+func (r fakeProjectMemberRepo) Get(_ context.Context, projectID, userID string) (*model.ProjectMember, error) {
+	if role, ok := r.f.members[projectID+"/"+userID]; ok {
+		return &model.ProjectMember{ProjectID: projectID, UserID: userID, RoleID: role}, nil
+	}
+	return &model.ProjectMember{}, gorm.ErrRecordNotFound
+}
+
+type fakeOrgMemberRepo struct {
+	repo.IOrganizationMemberRepository
+	f *accessFixture
+}
+
+func (r fakeOrgMemberRepo) Get(_ context.Context, orgID, userID string) (*model.OrganizationMember, error) {
+	if m, ok := r.f.orgMembers[orgID+"/"+userID]; ok {
+		return m, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type fakeTeamAccessRepo struct {
+	repo.IProjectTeamAccessRepository
+	f *accessFixture
+}
+
+func (r fakeTeamAccessRepo) ListProjectTeams(_ context.Context, projectID string) ([]model.ProjectTeamAccess, error) {
+	return r.f.teamAccess[projectID], nil
+}
+
+type fakeTeamMemberRepo struct {
+	repo.ITeamMemberRepository
+	f *accessFixture
+}
+
+func (r fakeTeamMemberRepo) ListUserTeams(_ context.Context, userID string) ([]model.TeamMember, error) {
+	return r.f.userTeams[userID], nil
+}
+
+func (r fakeTeamMemberRepo) Get(_ context.Context, teamID, userID string) (*model.TeamMember, error) {
+	for _, m := range r.f.userTeams[userID] {
+		if m.TeamID == teamID {
+			return &m, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type fakePipelineRepo struct {
+	repo.IPipelineRepository
+	f *accessFixture
+}
+
+func (r fakePipelineRepo) Get(_ context.Context, pipelineID string) (*model.Pipeline, error) {
+	if p, ok := r.f.pipelines[pipelineID]; ok {
+		return p, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type fakeStepRunRepo struct {
+	repo.IStepRunRepository
+	f *accessFixture
+}
+
+func (r fakeStepRunRepo) GetByStepRunID(_ context.Context, stepRunID string) (*model.StepRun, error) {
+	if sr, ok := r.f.stepRuns[stepRunID]; ok {
+		return sr, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestProjectAccessLevel(t *testing.T) {
+	f := newAccessFixture()
+	f.projects["private"] = &model.Project{ProjectID: "private", OrgID: "org", Visibility: model.VisibilityPrivate}
+	f.projects["internal"] = &model.Project{ProjectID: "internal", OrgID: "org", Visibility: model.VisibilityInternal}
+	f.projects["public"] = &model.Project{ProjectID: "public", OrgID: "org", Visibility: model.VisibilityPublic}
+	f.members["private/dev"] = string(model.ProjectRoleDeveloper)
+	f.members["private/guest"] = string(model.ProjectRoleGuest)
+	f.members["private/maintainer"] = string(model.ProjectRoleMaintainer)
+	f.orgMembers["org/admin"] = &model.OrganizationMember{RoleID: model.OrgRoleAdmin, Status: model.OrgMemberStatusActive}
+	f.orgMembers["org/member"] = &model.OrganizationMember{RoleID: model.OrgRoleMember, Status: model.OrgMemberStatusActive}
+	f.orgMembers["org/disabled"] = &model.OrganizationMember{RoleID: model.OrgRoleOwner, Status: model.OrgMemberStatusDisabled}
+	f.teamAccess["private"] = []model.ProjectTeamAccess{{TeamID: "ops", AccessLevel: model.AccessLevelWrite}}
+	f.userTeams["teammate"] = []model.TeamMember{{TeamID: "ops"}}
+	f.userTeams["outsider"] = []model.TeamMember{{TeamID: "other"}}
+	svc := f.service()
+
+	tests := []struct {
+		project, user, want string
+	}{
+		{"private", "dev", model.AccessLevelWrite},
+		{"private", "guest", model.AccessLevelRead},
+		{"private", "maintainer", model.AccessLevelAdmin},
+		{"private", "admin", model.AccessLevelAdmin},
+		{"private", "member", ""},
+		{"private", "disabled", ""},
+		{"private", "teammate", model.AccessLevelWrite},
+		{"private", "outsider", ""},
+		{"private", "stranger", ""},
+		{"internal", "member", model.AccessLevelRead},
+		{"internal", "stranger", ""},
+		{"public", "stranger", model.AccessLevelRead},
+	}
+	for _, tt := range tests {
+		got, err := svc.Level(context.Background(), tt.project, tt.user)
+		if err != nil {
+			t.Fatalf("Level(%s, %s): %v", tt.project, tt.user, err)
+		}
+		if got != tt.want {
+			t.Errorf("Level(%s, %s) = %q, want %q", tt.project, tt.user, got, tt.want)
+		}
+	}
+}
+
+func TestProjectAccessRequire(t *testing.T) {
+	f := newAccessFixture()
+	f.projects["p"] = &model.Project{ProjectID: "p", Visibility: model.VisibilityPublic}
+	f.members["p/dev"] = string(model.ProjectRoleDeveloper)
+	svc := f.service()
+	ctx := context.Background()
+
+	if err := svc.Require(ctx, "p", "dev", model.AccessLevelWrite); err != nil {
+		t.Fatalf("developer should have write access: %v", err)
+	}
+	if err := svc.Require(ctx, "p", "dev", model.AccessLevelAdmin); !errors.Is(err, ErrProjectPermissionDenied) {
+		t.Fatalf("developer admin access: got %v, want ErrProjectPermissionDenied", err)
+	}
+	if err := svc.Require(ctx, "p", "stranger", model.AccessLevelRead); err != nil {
+		t.Fatalf("public project should be readable: %v", err)
+	}
+	if err := svc.Require(ctx, "p", "stranger", model.AccessLevelWrite); !errors.Is(err, ErrProjectPermissionDenied) {
+		t.Fatalf("stranger write access: got %v, want ErrProjectPermissionDenied", err)
+	}
+	if err := svc.Require(ctx, "missing", "dev", model.AccessLevelRead); !errors.Is(err, ErrProjectPermissionDenied) {
+		t.Fatalf("unknown project: got %v, want ErrProjectPermissionDenied", err)
+	}
+	if err := svc.Require(ctx, "p", "", model.AccessLevelRead); !errors.Is(err, ErrProjectPermissionDenied) {
+		t.Fatalf("anonymous user: got %v, want ErrProjectPermissionDenied", err)
+	}
+}
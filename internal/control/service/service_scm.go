@@ -24,12 +24,11 @@ import (
 	pipelinev1 "github.com/arcentrix/arcentra/api/pipeline/v1"
 	"github.com/arcentrix/arcentra/internal/control/model"
 	"github.com/arcentrix/arcentra/internal/control/repo"
-	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
-	tmpl "github.com/arcentrix/arcentra/internal/shared/pipeline/template"
 	"github.com/arcentrix/arcentra/internal/shared/pipeline/trigger"
 	"github.com/arcentrix/arcentra/pkg/id"
 	"github.com/arcentrix/arcentra/pkg/log"
 	"github.com/arcentrix/arcentra/pkg/scm"
+	"github.com/arcentrix/arcentra/pkg/scm/azuredevops"
 	_ "github.com/arcentrix/arcentra/pkg/scm/builtin" // register builtin SCM providers
 	"github.com/bytedance/sonic"
 	"gorm.io/datatypes"
@@ -75,7 +74,7 @@ func (s *ScmService) HandleWebhook(ctx context.Context, projectID string, header
 		return nil, fmt.Errorf("unsupported repo type: %s", project.RepoType)
 	}
 
-	prov, err := scm.NewProvider(s.providerConfigFromProject(project, kind))
+	prov, err := scm.NewProvider(providerConfigFromProject(project, kind))
 	if err != nil {
 		return nil, err
 	}
@@ -98,9 +97,9 @@ func (s *ScmService) HandleWebhook(ctx context.Context, projectID string, header
 		}
 	}
 
-	// Trigger matching pipelines asynchronously.
+	// Sync discovered pipelines and trigger the matching ones.
 	if s.pipelineRepo != nil && len(events) > 0 {
-		s.matchAndTriggerPipelines(ctx, projectID, events)
+		s.matchAndTriggerPipelines(ctx, project, prov, events)
 	}
 
 	return events, nil
@@ -108,68 +107,95 @@ func (s *ScmService) HandleWebhook(ctx context.Context, projectID string, header
 
 // matchAndTriggerPipelines evaluates pipeline-level event triggers against
 // the incoming SCM events. For each match it creates a PipelineRun and
-// submits it to the engine.
-func (s *ScmService) matchAndTriggerPipelines(ctx context.Context, projectID string, events []scm.Event) {
-	pipelines, _, err := s.pipelineRepo.List(ctx, &repo.PipelineQuery{
-		ProjectID: projectID,
-		Page:      1,
-		PageSize:  100,
-	})
-	if err != nil || len(pipelines) == 0 {
+// submits it to the engine. Pushes to the default branch first sync the
+// discovered pipelines of the project. Definitions are read at the event's
+// commit, and changed files missing from the payload are fetched from prov
+// once per event, only for pipelines with path filters.
+func (s *ScmService) matchAndTriggerPipelines(ctx context.Context, project *model.Project, prov scm.Provider, events []scm.Event) {
+	pipelines, err := s.listProjectPipelines(ctx, project.ProjectID)
+	if err != nil {
+		log.Warnw("webhook trigger: list pipelines failed", "projectId", project.ProjectID, "error", err)
 		return
 	}
 
-	for _, ev := range events {
+	for i := range events {
+		src := newEventSource(project, prov, &events[i])
+		if settings, ok := discoveryEnabled(project, src.ev); ok {
+			pipelines = s.discoverPipelines(ctx, src, settings, pipelines)
+		}
 		for _, p := range pipelines {
 			if p.IsEnabled != 1 {
 				continue
 			}
-			s.tryTriggerPipeline(ctx, p, ev)
+			s.tryTriggerPipeline(ctx, p, src)
 		}
+		src.close()
 	}
 }
 
-// tryTriggerPipeline loads a pipeline's spec and checks if any event trigger
-// matches the given SCM event. On match it creates a run and submits.
-func (s *ScmService) tryTriggerPipeline(ctx context.Context, p *model.Pipeline, ev scm.Event) {
+// triggerEvent converts an SCM event into the form event triggers match on.
+func triggerEvent(ev *scm.Event) trigger.Event {
+	te := trigger.Event{
+		Type:         string(ev.EventType),
+		Ref:          ev.Ref,
+		Message:      ev.Message,
+		ChangedFiles: ev.ChangedFiles,
+	}
+	if c := ev.Change; c != nil {
+		te.SourceBranch = c.SourceBranch
+		te.TargetBranch = c.TargetBranch
+		te.Action = c.Action
+		te.Labels = c.Labels
+	}
+	return te
+}
+
+// tryTriggerPipeline loads a pipeline's spec at the event's commit and checks
+// if any event trigger matches the event of src. On match it creates a run,
+// recording that commit as DefinitionCommitSha, and submits it.
+func (s *ScmService) tryTriggerPipeline(ctx context.Context, p *model.Pipeline, src *eventSource) {
+	ev := src.ev
 	if s.engine == nil {
 		log.Infow("webhook event skipped (process not available)",
 			"pipelineId", p.PipelineID, "eventType", ev.EventType, "ref", ev.Ref)
 		return
 	}
 
-	project, err := s.projectRepo.Get(ctx, p.ProjectID)
-	if err != nil || project == nil {
+	content, definitionSha, err := src.definition(ctx, p)
+	if err != nil {
+		log.Debugw("webhook trigger: load definition failed", "pipelineId", p.PipelineID, "error", err)
 		return
 	}
 
-	content, headSha, err := LoadPipelineDefinition(ctx, p, project)
+	parsedSpec, err := s.parseDefinition(ctx, p.ProjectID, content)
 	if err != nil {
-		log.Debugw("webhook trigger: load definition failed", "pipelineId", p.PipelineID, "error", err)
+		log.Debugw("webhook trigger: parse spec failed", "pipelineId", p.PipelineID, "error", err)
 		return
 	}
-
-	if s.templateResolver != nil {
-		expanded, inclErr := tmpl.ResolveIncludes(ctx, content, s.templateResolver, model.TemplateScopeProject, p.ProjectID)
-		if inclErr != nil {
-			log.Debugw("webhook trigger: resolve includes failed", "pipelineId", p.PipelineID, "error", inclErr)
-			return
+	// A push to the default branch changes the definition other pipelines'
+	// runs are matched against.
+	if ev.Change == nil && trigger.NormalizeBranch(ev.Ref) == p.DefaultBranch {
+		if err := trigger.RecordPipelineTriggers(ctx, s.pipelineRepo, p, parsedSpec); err != nil {
+			log.Warnw("webhook trigger: record pipeline triggers failed", "pipelineId", p.PipelineID, "error", err)
 		}
-		content = expanded
 	}
 
-	parsedSpec, err := spec.ParseContentToProto(content, pipelinev1.SpecFormat_SPEC_FORMAT_UNSPECIFIED)
-	if err != nil {
-		log.Debugw("webhook trigger: parse spec failed", "pipelineId", p.PipelineID, "error", err)
+	if trigger.UsesPathFilters(parsedSpec) {
+		src.loadChangedFiles(ctx)
+	}
+	if !trigger.MatchAnyTriggerEvent(parsedSpec, triggerEvent(ev)) {
 		return
 	}
 
-	if !trigger.MatchAnyEventTrigger(parsedSpec, string(ev.EventType), ev.Ref) {
-		return
+	// PR/MR events build the source branch at its head commit.
+	branch := trigger.NormalizeBranch(ev.Ref)
+	if branch == "" && ev.Change != nil {
+		branch = ev.Change.SourceBranch
 	}
+	commitID := src.commit()
 
 	requestID := fmt.Sprintf("event:%s:%s:%s:%d",
-		p.PipelineID, ev.EventType, ev.CommitID, ev.OccurredAt.Unix())
+		p.PipelineID, ev.EventType, commitID, ev.OccurredAt.Unix())
 
 	existing, _ := s.pipelineRepo.GetRunByRequestID(ctx, p.PipelineID, requestID)
 	if existing != nil {
@@ -181,14 +207,19 @@ func (s *ScmService) tryTriggerPipeline(ctx context.Context, p *model.Pipeline,
 		PipelineID:          p.PipelineID,
 		RequestID:           requestID,
 		PipelineName:        p.Name,
-		Branch:              trigger.NormalizeBranch(ev.Ref),
-		CommitSha:           ev.CommitID,
-		DefinitionCommitSha: headSha,
+		Branch:              branch,
+		CommitSha:           commitID,
+		DefinitionCommitSha: definitionSha,
 		DefinitionPath:      p.PipelineFilePath,
 		Status:              model.PipelineStatusPending,
 		TriggerType:         int(pipelinev1.TriggerType_TRIGGER_TYPE_EVENT),
 		TriggeredBy:         "webhook",
 	}
+	if ev.Change != nil {
+		// The source branch of a PR/MR may carry a protected name, e.g. in a
+		// fork; its runs never get protected variables.
+		run.ChangeRequest = 1
+	}
 	if err := s.pipelineRepo.CreateRun(ctx, run); err != nil {
 		log.Warnw("webhook trigger: create run failed", "pipelineId", p.PipelineID, "error", err)
 		return
@@ -238,16 +269,15 @@ func (s *ScmService) pollProject(ctx context.Context, p *model.Project) error {
 	if kind == "" {
 		return nil
 	}
-	fromURL, ok := parseRepoFromURL(p.RepoURL)
-	if ok {
-		fromURL.URL = p.RepoURL
-	}
+	// Generic git servers are polled by URL alone.
+	fromURL, _ := parseRepoFromURL(p.RepoURL)
+	fromURL.URL = p.RepoURL
 	cursor, err := s.loadCursor(p, kind)
 	if err != nil {
 		return err
 	}
 
-	prov, err := scm.NewProvider(s.providerConfigFromProject(p, kind))
+	prov, err := scm.NewProvider(providerConfigFromProject(p, kind))
 	if err != nil {
 		return err
 	}
@@ -255,18 +285,37 @@ func (s *ScmService) pollProject(ctx context.Context, p *model.Project) error {
 	if err != nil {
 		return err
 	}
-	if len(events) == 0 {
+	// The generic git provider advances its ref snapshot without events.
+	if len(events) == 0 && next.Since.Equal(cursor.Since) && next.Opaque == cursor.Opaque {
 		return nil
 	}
 	if err := s.saveCursor(ctx, p.ProjectID, kind, next); err != nil {
 		return err
 	}
+	if len(events) == 0 {
+		return nil
+	}
 	log.Infow("polled scm events", "projectID", p.ProjectID, "kind", kind, "count", len(events))
+
+	// Providers with webhooks trigger pipelines from them; polled events
+	// trigger only for those that cannot deliver webhooks.
+	if s.pipelineRepo != nil && !prov.Capabilities().Has(scm.CapWebhookParse) {
+		now := time.Now()
+		for i := range events {
+			if events[i].OccurredAt.IsZero() {
+				events[i].OccurredAt = now
+			}
+			if events[i].ProviderKind == "" {
+				events[i].ProviderKind = kind
+			}
+		}
+		s.matchAndTriggerPipelines(ctx, p, prov, events)
+	}
 	return nil
 }
 
 // providerConfigFromProject creates the provider config from the project
-func (s *ScmService) providerConfigFromProject(p *model.Project, kind scm.ProviderKind) scm.ProviderConfig {
+func providerConfigFromProject(p *model.Project, kind scm.ProviderKind) scm.ProviderConfig {
 	cfg := scm.ProviderConfig{
 		Kind: kind,
 	}
@@ -277,6 +326,9 @@ func (s *ScmService) providerConfigFromProject(p *model.Project, kind scm.Provid
 	if p.AuthType == model.AuthTypeToken && strings.TrimSpace(p.Credential) != "" {
 		cfg.Token = strings.TrimSpace(p.Credential)
 	}
+	if kind == scm.ProviderKindGit {
+		cfg.Git = scm.NewGitAuthFromMap(scmAuthFromProject(p))
+	}
 	return cfg
 }
 
@@ -293,6 +345,10 @@ func mapRepoTypeToProviderKind(repoType string) scm.ProviderKind {
 		return scm.ProviderKindBitbucket
 	case "gitea":
 		return scm.ProviderKindGitea
+	case model.RepoTypeAzure:
+		return scm.ProviderKindAzure
+	case model.RepoTypeGit:
+		return scm.ProviderKindGit
 	default:
 		return ""
 	}
@@ -403,6 +459,10 @@ func parseRepoFromURL(repoURL string) (scm.Repo, bool) {
 	if repoURL == "" {
 		return scm.Repo{}, false
 	}
+	// Azure DevOps nests repositories under organization and project.
+	if repo, ok := azuredevops.ParseRepoURL(repoURL); ok {
+		return repo, true
+	}
 	if strings.HasPrefix(repoURL, "http://") || strings.HasPrefix(repoURL, "https://") {
 		u, err := url.Parse(repoURL)
 		if err != nil {
@@ -28,7 +28,8 @@ import (
 )
 
 type TeamService struct {
-	teamRepo repo.ITeamRepository
+	teamRepo   repo.ITeamRepository
+	orgService *OrganizationService
 }
 
 func NewTeamService(teamRepo repo.ITeamRepository) *TeamService {
@@ -37,12 +38,22 @@ func NewTeamService(teamRepo repo.ITeamRepository) *TeamService {
 	}
 }
 
+// SetOrganizationService enables organization checks and plan quotas on create.
+func (s *TeamService) SetOrganizationService(orgService *OrganizationService) {
+	s.orgService = orgService
+}
+
 // CreateTeam creates a team.
 func (s *TeamService) CreateTeam(ctx context.Context, req *model.CreateTeamReq, _ string) (*model.TeamResp, error) {
 	// 1. 验证组织是否存在
 	if req.OrgID == "" {
 		return nil, errors.New("organization id cannot be empty")
 	}
+	if s.orgService != nil {
+		if err := s.orgService.CheckTeamQuota(ctx, req.OrgID); err != nil {
+			return nil, err
+		}
+	}
 
 	// 2. 检查团队名称是否已存在
 	exists, err := s.teamRepo.NameExists(ctx, req.OrgID, req.Name)
@@ -96,6 +107,9 @@ func (s *TeamService) CreateTeam(ctx context.Context, req *model.CreateTeamReq,
 	}
 
 	log.Infow("success create team", "name", teamEntity.Name, "teamID", teamEntity.TeamID)
+	if s.orgService != nil {
+		s.orgService.RefreshStatistics(ctx, teamEntity.OrgID)
+	}
 
 	// 7. 返回响应
 	return model.ToTeamResp(teamEntity), nil
@@ -210,6 +224,9 @@ func (s *TeamService) DeleteTeam(ctx context.Context, teamID string) error {
 	}
 
 	log.Infow("success delete team", "name", teamEntity.Name, "teamID", teamID)
+	if s.orgService != nil {
+		s.orgService.RefreshStatistics(ctx, teamEntity.OrgID)
+	}
 
 	return nil
 }
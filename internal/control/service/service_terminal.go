@@ -0,0 +1,448 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	agentv1 "github.com/arcentrix/arcentra/api/agent/v1"
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"github.com/arcentrix/arcentra/pkg/id"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"gorm.io/gorm"
+)
+
+const (
+	// terminalTranscriptLimit caps the transcript bytes kept per session.
+	terminalTranscriptLimit = 4 << 20
+	// terminalChunkLines is the number of transcript lines stored per chunk.
+	terminalChunkLines = 100
+	// terminalFlushInterval bounds how long transcript lines stay unsaved.
+	terminalFlushInterval = 5 * time.Second
+	// terminalCloseTimeout bounds the wait for the agent to confirm a close.
+	terminalCloseTimeout = 5 * time.Second
+	// terminalCommand is what the agent runs in the job container.
+	terminalCommand = "bash || sh"
+)
+
+var (
+	ErrTerminalStepRunNotFound  = errors.New("step run not found")
+	ErrTerminalStepRunNotActive = errors.New("step run is neither running nor failed")
+	ErrTerminalAgentUnavailable = errors.New("the agent of the step run is not connected")
+	ErrTerminalSessionNotFound  = errors.New("terminal session not found")
+)
+
+// TerminalService opens debug terminals into the workspace of running or
+// failed steps on the owning agent and records their transcripts.
+type TerminalService struct {
+	terminalRepo repo.ITerminalRepository
+	stepRunRepo  repo.IStepRunRepository
+	jobRunRepo   repo.IJobRunRepository
+	pipelineRepo repo.IPipelineRepository
+	access       *ProjectAccessService
+	streams      *AgentStreamHub
+}
+
+// NewTerminalService creates a terminal service.
+func NewTerminalService(
+	terminalRepo repo.ITerminalRepository,
+	stepRunRepo repo.IStepRunRepository,
+	jobRunRepo repo.IJobRunRepository,
+	pipelineRepo repo.IPipelineRepository,
+	access *ProjectAccessService,
+	streams *AgentStreamHub,
+) *TerminalService {
+	return &TerminalService{
+		terminalRepo: terminalRepo,
+		stepRunRepo:  stepRunRepo,
+		jobRunRepo:   jobRunRepo,
+		pipelineRepo: pipelineRepo,
+		access:       access,
+		streams:      streams,
+	}
+}
+
+// Open opens a terminal in the workspace of a step run. The step must be
+// running, or failed while its job is kept alive on the agent; the agent
+// rejects the latter once the keep-alive window is over. The user needs write
+// access to the project of the step run.
+func (s *TerminalService) Open(ctx context.Context, userID, stepRunID string, cols, rows uint32) (*TerminalSession, error) {
+	stepRun, err := s.stepRunRepo.GetByStepRunID(ctx, stepRunID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTerminalStepRunNotFound
+		}
+		return nil, err
+	}
+	if err := s.authorize(ctx, userID, stepRun.PipelineID); err != nil {
+		return nil, err
+	}
+	if stepRun.Status != model.JobRunStatusRunning && stepRun.Status != model.JobRunStatusFailed {
+		return nil, ErrTerminalStepRunNotActive
+	}
+	agentID, workspace := stepRun.AgentID, stepRun.Workspace
+	if stepRun.JobRunID != "" && s.jobRunRepo != nil {
+		if jobRun, err := s.jobRunRepo.GetByJobRunID(ctx, stepRun.JobRunID); err == nil {
+			if agentID == "" {
+				agentID = jobRun.AgentID
+			}
+			if workspace == "" {
+				workspace = jobRun.Workspace
+			}
+		}
+	}
+	if agentID == "" || !s.streams.Connected(agentID) {
+		return nil, ErrTerminalAgentUnavailable
+	}
+
+	now := time.Now()
+	record := &model.TerminalOutputRecord{
+		SessionID:        id.GetUUID(),
+		SessionType:      model.TerminalSessionTypeDebug,
+		StepRunID:        stepRun.StepRunID,
+		PipelineID:       stepRun.PipelineID,
+		PipelineRunID:    stepRun.PipelineRunID,
+		UserID:           userID,
+		Hostname:         agentID,
+		WorkingDirectory: workspace,
+		Command:          terminalCommand,
+		Status:           model.TerminalStatusRunning,
+		StartTime:        now,
+	}
+	if err := s.terminalRepo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+
+	sess := &TerminalSession{
+		svc:     s,
+		agentID: agentID,
+		record:  record,
+		output:  make(chan []byte, terminalSinkSize),
+		done:    make(chan struct{}),
+	}
+	events := s.streams.subscribe(record.SessionID, agentID)
+	err = s.streams.Send(agentID, &agentv1.ConnectResponse{
+		CommandId: id.GetUUID(),
+		Payload: &agentv1.ConnectResponse_TerminalOpen{TerminalOpen: &agentv1.TerminalOpen{
+			SessionId: record.SessionID,
+			StepRunId: stepRun.StepRunID,
+			JobRunId:  stepRun.JobRunID,
+			Cols:      cols,
+			Rows:      rows,
+		}},
+	})
+	if err != nil {
+		s.streams.unsubscribe(record.SessionID)
+		sess.finish(-1, err.Error())
+		return nil, fmt.Errorf("%w: %v", ErrTerminalAgentUnavailable, err)
+	}
+	go sess.pump(events)
+	log.Infow("debug terminal opened", "sessionId", record.SessionID, "stepRunId", stepRunID, "agentId", agentID, "userId", userID)
+	return sess, nil
+}
+
+// Get returns a terminal session record with its transcript, assembled from
+// the stored chunks. Like opening a terminal, reading a transcript needs write
+// access to the project.
+func (s *TerminalService) Get(ctx context.Context, userID, sessionID string) (*model.TerminalOutputRecord, error) {
+	record, err := s.terminalRepo.Get(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTerminalSessionNotFound
+		}
+		return nil, err
+	}
+	if err := s.authorize(ctx, userID, record.PipelineID); err != nil {
+		return nil, err
+	}
+	lines, err := s.terminalRepo.ListTranscript(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) > 0 {
+		record.Logs = lines
+	}
+	return record, nil
+}
+
+// ListByStepRun lists the terminal sessions opened into a step run.
+func (s *TerminalService) ListByStepRun(ctx context.Context, userID, stepRunID string) ([]*model.TerminalOutputRecord, error) {
+	stepRun, err := s.stepRunRepo.GetByStepRunID(ctx, stepRunID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTerminalStepRunNotFound
+		}
+		return nil, err
+	}
+	if err := s.authorize(ctx, userID, stepRun.PipelineID); err != nil {
+		return nil, err
+	}
+	return s.terminalRepo.ListByStepRun(ctx, stepRunID)
+}
+
+// authorize requires write access to the project owning pipelineID.
+func (s *TerminalService) authorize(ctx context.Context, userID, pipelineID string) error {
+	if s.access == nil {
+		return ErrProjectPermissionDenied
+	}
+	pipeline, err := s.pipelineRepo.Get(ctx, pipelineID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrProjectPermissionDenied
+		}
+		return err
+	}
+	return s.access.Require(ctx, pipeline.ProjectID, userID, model.AccessLevelWrite)
+}
+
+// TerminalSession is an open debug terminal. Output is delivered on Output
+// until the shell exits, after which Done is closed.
+type TerminalSession struct {
+	svc     *TerminalService
+	agentID string
+	output  chan []byte
+	done    chan struct{}
+
+	mu       sync.Mutex
+	record   *model.TerminalOutputRecord
+	partial  []byte // output after the last newline
+	input    []byte // input after the last line break
+	pending  []model.TerminalOutputLine
+	lines    int
+	seq      int
+	size     int64
+	exitCode int
+	errMsg   string
+	once     sync.Once
+}
+
+// ID returns the session ID.
+func (t *TerminalSession) ID() string {
+	return t.record.SessionID
+}
+
+// Output returns the channel carrying terminal output. It is closed when the
+// session ends.
+func (t *TerminalSession) Output() <-chan []byte {
+	return t.output
+}
+
+// Done is closed when the session has ended.
+func (t *TerminalSession) Done() <-chan struct{} {
+	return t.done
+}
+
+// Exit returns the shell exit code and error once the session has ended.
+func (t *TerminalSession) Exit() (int, string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.exitCode, t.errMsg
+}
+
+// Input sends keystrokes to the shell and records them in the transcript.
+func (t *TerminalSession) Input(data []byte) error {
+	err := t.send(&agentv1.ConnectResponse{Payload: &agentv1.ConnectResponse_TerminalInput{
+		TerminalInput: &agentv1.TerminalInput{SessionId: t.ID(), Data: data},
+	}})
+	if err == nil {
+		t.recordInput(data)
+	}
+	return err
+}
+
+// Resize changes the terminal window size.
+func (t *TerminalSession) Resize(cols, rows uint32) error {
+	return t.send(&agentv1.ConnectResponse{Payload: &agentv1.ConnectResponse_TerminalResize{
+		TerminalResize: &agentv1.TerminalResize{SessionId: t.ID(), Cols: cols, Rows: rows},
+	}})
+}
+
+// Close hangs up the shell and waits briefly for the agent to confirm, so the
+// transcript ends with the exit code whenever possible.
+func (t *TerminalSession) Close() {
+	select {
+	case <-t.done:
+		return
+	default:
+	}
+	if err := t.send(&agentv1.ConnectResponse{Payload: &agentv1.ConnectResponse_TerminalClose{
+		TerminalClose: &agentv1.TerminalClose{SessionId: t.ID()},
+	}}); err == nil {
+		select {
+		case <-t.done:
+			return
+		case <-time.After(terminalCloseTimeout):
+		}
+	}
+	t.svc.streams.unsubscribe(t.ID())
+	t.finish(-1, "closed without confirmation from the agent")
+}
+
+func (t *TerminalSession) send(resp *agentv1.ConnectResponse) error {
+	resp.CommandId = id.GetUUID()
+	return t.svc.streams.Send(t.agentID, resp)
+}
+
+// pump records and forwards the agent messages of the session until the
+// shell exits, saving the transcript as it goes.
+func (t *TerminalSession) pump(events <-chan *agentv1.ConnectRequest) {
+	defer close(t.output)
+	ticker := time.NewTicker(terminalFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-ticker.C:
+			t.flush(0)
+		case req := <-events:
+			switch p := req.GetPayload().(type) {
+			case *agentv1.ConnectRequest_TerminalOutput:
+				data := p.TerminalOutput.GetData()
+				t.transcribe(data)
+				select {
+				case t.output <- data:
+				default:
+					// the client is not reading; the transcript still has it
+				}
+			case *agentv1.ConnectRequest_TerminalExit:
+				t.svc.streams.unsubscribe(t.ID())
+				t.finish(int(p.TerminalExit.GetExitCode()), p.TerminalExit.GetError())
+				return
+			}
+		}
+	}
+}
+
+// transcribe appends output to the transcript line by line.
+func (t *TerminalSession) transcribe(data []byte) {
+	t.mu.Lock()
+	if t.reserve(len(data)) {
+		t.partial = append(t.partial, data...)
+		for {
+			i := bytes.IndexByte(t.partial, '\n')
+			if i < 0 {
+				break
+			}
+			t.appendLine(t.partial[:i], "stdout")
+			t.partial = t.partial[i+1:]
+		}
+	}
+	t.mu.Unlock()
+	t.flush(terminalChunkLines)
+}
+
+// recordInput appends input to the transcript, one line per line break typed.
+func (t *TerminalSession) recordInput(data []byte) {
+	t.mu.Lock()
+	if t.reserve(len(data)) {
+		t.input = append(t.input, data...)
+		for {
+			i := bytes.IndexAny(t.input, "\r\n")
+			if i < 0 {
+				break
+			}
+			t.appendLine(t.input[:i], "stdin")
+			t.input = t.input[i+1:]
+		}
+	}
+	t.mu.Unlock()
+	t.flush(terminalChunkLines)
+}
+
+// reserve counts n transcript bytes and reports whether they fit the limit.
+func (t *TerminalSession) reserve(n int) bool {
+	t.size += int64(n)
+	return t.size <= terminalTranscriptLimit
+}
+
+func (t *TerminalSession) appendLine(line []byte, stream string) {
+	t.lines++
+	t.pending = append(t.pending, model.TerminalOutputLine{
+		Line:      t.lines,
+		Timestamp: time.Now(),
+		Content:   strings.TrimRight(string(line), "\r"),
+		Stream:    stream,
+	})
+}
+
+// flush saves the pending transcript lines as the next chunk once there are
+// at least minLines of them.
+func (t *TerminalSession) flush(minLines int) {
+	t.mu.Lock()
+	if len(t.pending) == 0 || len(t.pending) < minLines {
+		t.mu.Unlock()
+		return
+	}
+	t.seq++
+	chunk := &model.TerminalTranscriptChunk{
+		SessionID: t.record.SessionID,
+		Seq:       t.seq,
+		Lines:     t.pending,
+	}
+	t.pending = nil
+	t.mu.Unlock()
+
+	if err := t.svc.terminalRepo.AppendTranscript(context.Background(), chunk); err != nil {
+		log.Errorw("save terminal transcript chunk failed", "sessionId", chunk.SessionID, "seq", chunk.Seq, "error", err)
+	}
+}
+
+// finish ends the session once and persists the rest of the transcript.
+func (t *TerminalSession) finish(exitCode int, errMsg string) {
+	t.once.Do(func() {
+		t.mu.Lock()
+		if len(t.partial) > 0 {
+			t.appendLine(t.partial, "stdout")
+			t.partial = nil
+		}
+		if len(t.input) > 0 {
+			t.appendLine(t.input, "stdin")
+			t.input = nil
+		}
+		t.mu.Unlock()
+		t.flush(0)
+
+		t.mu.Lock()
+		t.exitCode, t.errMsg = exitCode, errMsg
+		end := time.Now()
+		record := t.record
+		record.ExitCode = &exitCode
+		record.EndTime = &end
+		record.Status = model.TerminalStatusCompleted
+		if exitCode < 0 {
+			record.Status = model.TerminalStatusFailed
+		}
+		record.Metadata = model.TerminalOutputMetadata{
+			TotalLines:      t.lines,
+			DurationMs:      end.Sub(record.StartTime).Milliseconds(),
+			OutputSizeBytes: t.size,
+		}
+		t.mu.Unlock()
+
+		if err := t.svc.terminalRepo.Save(context.Background(), record); err != nil {
+			log.Errorw("save terminal session failed", "sessionId", record.SessionID, "error", err)
+		}
+		close(t.done)
+		log.Infow("debug terminal closed", "sessionId", record.SessionID, "exitCode", exitCode, "error", errMsg)
+	})
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+)
+
+type fakeTerminalRepo struct {
+	mu     sync.Mutex
+	saved  *model.TerminalOutputRecord
+	chunks []*model.TerminalTranscriptChunk
+}
+
+func (r *fakeTerminalRepo) Create(context.Context, *model.TerminalOutputRecord) error { return nil }
+
+func (r *fakeTerminalRepo) Get(context.Context, string) (*model.TerminalOutputRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.saved, nil
+}
+
+func (r *fakeTerminalRepo) Save(_ context.Context, record *model.TerminalOutputRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.saved = record
+	return nil
+}
+
+func (r *fakeTerminalRepo) AppendTranscript(_ context.Context, chunk *model.TerminalTranscriptChunk) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.chunks = append(r.chunks, chunk)
+	return nil
+}
+
+func (r *fakeTerminalRepo) ListTranscript(context.Context, string) ([]model.TerminalOutputLine, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var lines []model.TerminalOutputLine
+	for _, chunk := range r.chunks {
+		lines = append(lines, chunk.Lines...)
+	}
+	return lines, nil
+}
+
+func (r *fakeTerminalRepo) ListByStepRun(context.Context, string) ([]*model.TerminalOutputRecord, error) {
+	return nil, nil
+}
+
+func TestTerminalOpenRequiresProjectWriteAccess(t *testing.T) {
+	f := newAccessFixture()
+	f.projects["p"] = &model.Project{ProjectID: "p", Visibility: model.VisibilityPublic}
+	f.pipelines["pl"] = &model.Pipeline{PipelineID: "pl", ProjectID: "p"}
+	f.stepRuns["sr"] = &model.StepRun{
+		StepRunID:  "sr",
+		PipelineID: "pl",
+		AgentID:    "agent-1",
+		Status:     model.JobRunStatusRunning,
+	}
+	f.members["p/dev"] = string(model.ProjectRoleDeveloper)
+	f.members["p/guest"] = string(model.ProjectRoleGuest)
+
+	svc := NewTerminalService(nil, fakeStepRunRepo{f: f}, nil, fakePipelineRepo{f: f}, f.service(), NewAgentStreamHub())
+	ctx := context.Background()
+
+	for _, user := range []string{"stranger", "guest", ""} {
+		if _, err := svc.Open(ctx, user, "sr", 80, 24); !errors.Is(err, ErrProjectPermissionDenied) {
+			t.Errorf("Open by %q: got %v, want ErrProjectPermissionDenied", user, err)
+		}
+		if _, err := svc.ListByStepRun(ctx, user, "sr"); !errors.Is(err, ErrProjectPermissionDenied) {
+			t.Errorf("ListByStepRun by %q: got %v, want ErrProjectPermissionDenied", user, err)
+		}
+	}
+
+	// An authorized user gets past the permission check; the agent is not
+	// connected in this test.
+	if _, err := svc.Open(ctx, "dev", "sr", 80, 24); !errors.Is(err, ErrTerminalAgentUnavailable) {
+		t.Fatalf("Open by developer: got %v, want ErrTerminalAgentUnavailable", err)
+	}
+	if _, err := svc.Open(ctx, "dev", "missing", 80, 24); !errors.Is(err, ErrTerminalStepRunNotFound) {
+		t.Fatalf("Open unknown step run: got %v, want ErrTerminalStepRunNotFound", err)
+	}
+}
+
+func TestTerminalTranscriptIsSavedInChunks(t *testing.T) {
+	terminals := &fakeTerminalRepo{}
+	sess := &TerminalSession{
+		svc: &TerminalService{terminalRepo: terminals},
+		record: &model.TerminalOutputRecord{
+			SessionID: "s1",
+			Status:    model.TerminalStatusRunning,
+			StartTime: time.Now(),
+		},
+		output: make(chan []byte, 1),
+		done:   make(chan struct{}),
+	}
+
+	sess.recordInput([]byte("ls -l"))
+	sess.recordInput([]byte("a\r"))
+	var out strings.Builder
+	for i := 0; i < terminalChunkLines+20; i++ {
+		fmt.Fprintf(&out, "line %d\r\n", i)
+	}
+	sess.transcribe([]byte(out.String()))
+
+	// A full chunk is saved while the session is still running.
+	terminals.mu.Lock()
+	saved := len(terminals.chunks)
+	terminals.mu.Unlock()
+	if saved != 1 {
+		t.Fatalf("chunks while running = %d, want 1", saved)
+	}
+
+	sess.transcribe([]byte("$ "))
+	sess.finish(0, "")
+
+	if len(terminals.chunks) != 2 || terminals.chunks[0].Seq != 1 || terminals.chunks[1].Seq != 2 {
+		t.Fatalf("chunks = %+v, want seq 1 and 2", terminals.chunks)
+	}
+	lines, _ := terminals.ListTranscript(context.Background(), "s1")
+	if want := terminalChunkLines + 22; len(lines) != want {
+		t.Fatalf("transcript lines = %d, want %d", len(lines), want)
+	}
+	first := lines[0]
+	if first.Stream != "stdin" || first.Content != "ls -la" || first.Line != 1 {
+		t.Errorf("first line = %+v, want the typed command", first)
+	}
+	if last := lines[len(lines)-1]; last.Stream != "stdout" || last.Content != "$ " {
+		t.Errorf("last line = %+v, want the trailing prompt", last)
+	}
+	if terminals.saved == nil || terminals.saved.Metadata.TotalLines != len(lines) || terminals.saved.Status != model.TerminalStatusCompleted {
+		t.Errorf("saved record = %+v, want the completed session", terminals.saved)
+	}
+}
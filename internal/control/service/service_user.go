@@ -32,6 +32,7 @@ import (
 	"github.com/arcentrix/arcentra/pkg/safe"
 	"github.com/arcentrix/arcentra/pkg/util"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type LoginService interface {
@@ -435,6 +436,20 @@ func (ul *UserService) UpdateAvatar(userID, avatarURL string) error {
 	return nil
 }
 
+// IsAdmin 判断用户是否绑定了平台管理员角色
+func (ul *UserService) IsAdmin(ctx context.Context, userID string) (bool, error) {
+	if userID == "" {
+		return false, nil
+	}
+	if _, err := ul.userRoleBindingRepo.GetByRole(ctx, userID, model.Admin); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 // GetUserRoles 获取用户的角色信息
 func (ul *UserService) GetUserRoles(ctx context.Context, userID string) ([]model.RoleDTO, error) {
 	roleBindings, err := ul.userRoleBindingRepo.List(ctx, userID)
@@ -0,0 +1,49 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"gorm.io/gorm"
+)
+
+type fakeRoleBindingRepo struct {
+	repo.IUserRoleBindingRepository
+	roles map[string]string // userID -> roleID
+}
+
+func (r fakeRoleBindingRepo) GetByRole(_ context.Context, userID, roleID string) (*model.UserRoleBinding, error) {
+	if r.roles[userID] == roleID {
+		return &model.UserRoleBinding{UserID: userID, RoleID: roleID}, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestUserServiceIsAdmin(t *testing.T) {
+	svc := &UserService{userRoleBindingRepo: fakeRoleBindingRepo{roles: map[string]string{
+		"alice": model.Admin,
+		"bob":   model.Member,
+	}}}
+	for user, want := range map[string]bool{"alice": true, "bob": false, "mallory": false, "": false} {
+		got, err := svc.IsAdmin(context.Background(), user)
+		if err != nil || got != want {
+			t.Errorf("IsAdmin(%q) = %v, %v; want %v", user, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,450 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"github.com/arcentrix/arcentra/pkg/id"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrVariableNotFound     = errors.New("variable not found")
+	ErrTeamPermissionDenied = errors.New("team permission denied")
+)
+
+// VariableService manages project and team variables. Runs inherit them with
+// the precedence team < project < pipeline variables < trigger-time overrides.
+//
+// Changing variables requires write access to the project (or team); listing
+// them requires read access. Values of protected variables are only returned
+// to callers with write access.
+type VariableService struct {
+	variableRepo   repo.IVariableRepository
+	projectRepo    repo.IProjectRepository
+	teamRepo       repo.ITeamRepository
+	teamMemberRepo repo.ITeamMemberRepository
+	orgMemberRepo  repo.IOrganizationMemberRepository
+	access         *ProjectAccessService
+}
+
+func NewVariableService(
+	variableRepo repo.IVariableRepository,
+	projectRepo repo.IProjectRepository,
+	teamRepo repo.ITeamRepository,
+	teamMemberRepo repo.ITeamMemberRepository,
+	orgMemberRepo repo.IOrganizationMemberRepository,
+	access *ProjectAccessService,
+) *VariableService {
+	return &VariableService{
+		variableRepo:   variableRepo,
+		projectRepo:    projectRepo,
+		teamRepo:       teamRepo,
+		teamMemberRepo: teamMemberRepo,
+		orgMemberRepo:  orgMemberRepo,
+		access:         access,
+	}
+}
+
+// CreateProjectVariable creates a variable in a project.
+func (s *VariableService) CreateProjectVariable(
+	ctx context.Context,
+	projectID, operatorID string,
+	req *model.CreateVariableReq,
+) (*model.VariableResp, error) {
+	if err := s.access.Require(ctx, projectID, operatorID, model.AccessLevelWrite); err != nil {
+		return nil, err
+	}
+	if exists, err := s.projectRepo.Exists(ctx, projectID); err != nil {
+		return nil, fmt.Errorf("check project exists failed: %w", err)
+	} else if !exists {
+		return nil, errors.New("project not found")
+	}
+	varType, err := validateCreateVariableReq(req)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := s.variableRepo.ProjectVariableKeyExists(ctx, projectID, req.Key)
+	if err != nil {
+		return nil, fmt.Errorf("check variable key failed: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("variable %s already exists", req.Key)
+	}
+
+	v := &model.ProjectVariable{
+		VariableID:  id.GetUUID(),
+		ProjectID:   projectID,
+		Key:         req.Key,
+		Value:       req.Value,
+		Type:        varType,
+		Protected:   boolToInt(req.Protected),
+		Masked:      boolToInt(req.Masked),
+		Description: req.Description,
+	}
+	if err := s.variableRepo.CreateProjectVariable(ctx, v); err != nil {
+		log.Errorw("create project variable failed", "projectID", projectID, "key", req.Key, "error", err)
+		return nil, fmt.Errorf("create variable failed: %w", err)
+	}
+	return model.ToProjectVariableResp(v, true), nil
+}
+
+// ListProjectVariables lists the variables of a project.
+func (s *VariableService) ListProjectVariables(ctx context.Context, projectID, operatorID string) ([]*model.VariableResp, error) {
+	canWrite, err := s.projectAccess(ctx, projectID, operatorID)
+	if err != nil {
+		return nil, err
+	}
+	vars, err := s.variableRepo.ListProjectVariables(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list variables failed: %w", err)
+	}
+	resp := make([]*model.VariableResp, 0, len(vars))
+	for _, v := range vars {
+		resp = append(resp, model.ToProjectVariableResp(v, canWrite))
+	}
+	return resp, nil
+}
+
+// GetProjectVariable returns a variable of a project.
+func (s *VariableService) GetProjectVariable(ctx context.Context, projectID, operatorID, variableID string) (*model.VariableResp, error) {
+	canWrite, err := s.projectAccess(ctx, projectID, operatorID)
+	if err != nil {
+		return nil, err
+	}
+	v, err := s.variableRepo.GetProjectVariable(ctx, projectID, variableID)
+	if err != nil {
+		return nil, variableErr(err)
+	}
+	return model.ToProjectVariableResp(v, canWrite), nil
+}
+
+// UpdateProjectVariable updates a variable of a project.
+func (s *VariableService) UpdateProjectVariable(
+	ctx context.Context,
+	projectID, operatorID, variableID string,
+	req *model.UpdateVariableReq,
+) (*model.VariableResp, error) {
+	if err := s.access.Require(ctx, projectID, operatorID, model.AccessLevelWrite); err != nil {
+		return nil, err
+	}
+	if _, err := s.variableRepo.GetProjectVariable(ctx, projectID, variableID); err != nil {
+		return nil, variableErr(err)
+	}
+	updates, err := variableUpdates(req)
+	if err != nil {
+		return nil, err
+	}
+	if req.Key != nil {
+		exists, err := s.variableRepo.ProjectVariableKeyExists(ctx, projectID, *req.Key, variableID)
+		if err != nil {
+			return nil, fmt.Errorf("check variable key failed: %w", err)
+		}
+		if exists {
+			return nil, fmt.Errorf("variable %s already exists", *req.Key)
+		}
+	}
+	if len(updates) > 0 {
+		updates["updated_at"] = time.Now()
+		if err := s.variableRepo.UpdateProjectVariable(ctx, projectID, variableID, updates); err != nil {
+			log.Errorw("update project variable failed", "projectID", projectID, "variableID", variableID, "error", err)
+			return nil, fmt.Errorf("update variable failed: %w", err)
+		}
+	}
+	v, err := s.variableRepo.GetProjectVariable(ctx, projectID, variableID)
+	if err != nil {
+		return nil, variableErr(err)
+	}
+	return model.ToProjectVariableResp(v, true), nil
+}
+
+// DeleteProjectVariable deletes a variable of a project.
+func (s *VariableService) DeleteProjectVariable(ctx context.Context, projectID, operatorID, variableID string) error {
+	if err := s.access.Require(ctx, projectID, operatorID, model.AccessLevelWrite); err != nil {
+		return err
+	}
+	if _, err := s.variableRepo.GetProjectVariable(ctx, projectID, variableID); err != nil {
+		return variableErr(err)
+	}
+	if err := s.variableRepo.DeleteProjectVariable(ctx, projectID, variableID); err != nil {
+		return fmt.Errorf("delete variable failed: %w", err)
+	}
+	return nil
+}
+
+// CreateTeamVariable creates a variable in a team.
+func (s *VariableService) CreateTeamVariable(
+	ctx context.Context,
+	teamID, operatorID string,
+	req *model.CreateVariableReq,
+) (*model.VariableResp, error) {
+	if err := s.requireTeam(ctx, teamID, operatorID, model.AccessLevelWrite); err != nil {
+		return nil, err
+	}
+	varType, err := validateCreateVariableReq(req)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := s.variableRepo.TeamVariableKeyExists(ctx, teamID, req.Key)
+	if err != nil {
+		return nil, fmt.Errorf("check variable key failed: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("variable %s already exists", req.Key)
+	}
+
+	v := &model.TeamVariable{
+		VariableID:  id.GetUUID(),
+		TeamID:      teamID,
+		Key:         req.Key,
+		Value:       req.Value,
+		Type:        varType,
+		Protected:   boolToInt(req.Protected),
+		Masked:      boolToInt(req.Masked),
+		Description: req.Description,
+	}
+	if err := s.variableRepo.CreateTeamVariable(ctx, v); err != nil {
+		log.Errorw("create team variable failed", "teamID", teamID, "key", req.Key, "error", err)
+		return nil, fmt.Errorf("create variable failed: %w", err)
+	}
+	return model.ToTeamVariableResp(v, true), nil
+}
+
+// ListTeamVariables lists the variables of a team.
+func (s *VariableService) ListTeamVariables(ctx context.Context, teamID, operatorID string) ([]*model.VariableResp, error) {
+	canWrite, err := s.teamAccess(ctx, teamID, operatorID)
+	if err != nil {
+		return nil, err
+	}
+	vars, err := s.variableRepo.ListTeamVariables(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("list variables failed: %w", err)
+	}
+	resp := make([]*model.VariableResp, 0, len(vars))
+	for _, v := range vars {
+		resp = append(resp, model.ToTeamVariableResp(v, canWrite))
+	}
+	return resp, nil
+}
+
+// GetTeamVariable returns a variable of a team.
+func (s *VariableService) GetTeamVariable(ctx context.Context, teamID, operatorID, variableID string) (*model.VariableResp, error) {
+	canWrite, err := s.teamAccess(ctx, teamID, operatorID)
+	if err != nil {
+		return nil, err
+	}
+	v, err := s.variableRepo.GetTeamVariable(ctx, teamID, variableID)
+	if err != nil {
+		return nil, variableErr(err)
+	}
+	return model.ToTeamVariableResp(v, canWrite), nil
+}
+
+// UpdateTeamVariable updates a variable of a team.
+func (s *VariableService) UpdateTeamVariable(
+	ctx context.Context,
+	teamID, operatorID, variableID string,
+	req *model.UpdateVariableReq,
+) (*model.VariableResp, error) {
+	if err := s.requireTeam(ctx, teamID, operatorID, model.AccessLevelWrite); err != nil {
+		return nil, err
+	}
+	if _, err := s.variableRepo.GetTeamVariable(ctx, teamID, variableID); err != nil {
+		return nil, variableErr(err)
+	}
+	updates, err := variableUpdates(req)
+	if err != nil {
+		return nil, err
+	}
+	if req.Key != nil {
+		exists, err := s.variableRepo.TeamVariableKeyExists(ctx, teamID, *req.Key, variableID)
+		if err != nil {
+			return nil, fmt.Errorf("check variable key failed: %w", err)
+		}
+		if exists {
+			return nil, fmt.Errorf("variable %s already exists", *req.Key)
+		}
+	}
+	if len(updates) > 0 {
+		updates["updated_at"] = time.Now()
+		if err := s.variableRepo.UpdateTeamVariable(ctx, teamID, variableID, updates); err != nil {
+			log.Errorw("update team variable failed", "teamID", teamID, "variableID", variableID, "error", err)
+			return nil, fmt.Errorf("update variable failed: %w", err)
+		}
+	}
+	v, err := s.variableRepo.GetTeamVariable(ctx, teamID, variableID)
+	if err != nil {
+		return nil, variableErr(err)
+	}
+	return model.ToTeamVariableResp(v, true), nil
+}
+
+// DeleteTeamVariable deletes a variable of a team.
+func (s *VariableService) DeleteTeamVariable(ctx context.Context, teamID, operatorID, variableID string) error {
+	if err := s.requireTeam(ctx, teamID, operatorID, model.AccessLevelWrite); err != nil {
+		return err
+	}
+	if _, err := s.variableRepo.GetTeamVariable(ctx, teamID, variableID); err != nil {
+		return variableErr(err)
+	}
+	if err := s.variableRepo.DeleteTeamVariable(ctx, teamID, variableID); err != nil {
+		return fmt.Errorf("delete variable failed: %w", err)
+	}
+	return nil
+}
+
+// projectAccess requires read access to the project and reports whether the
+// caller may also write, which reveals protected values.
+func (s *VariableService) projectAccess(ctx context.Context, projectID, userID string) (bool, error) {
+	if err := s.access.Require(ctx, projectID, userID, model.AccessLevelRead); err != nil {
+		return false, err
+	}
+	err := s.access.Require(ctx, projectID, userID, model.AccessLevelWrite)
+	if errors.Is(err, ErrProjectPermissionDenied) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// teamAccess requires read access to the team and reports whether the caller
+// may also write.
+func (s *VariableService) teamAccess(ctx context.Context, teamID, userID string) (bool, error) {
+	level, err := s.teamLevel(ctx, teamID, userID)
+	if err != nil {
+		return false, err
+	}
+	if accessRank[level] == 0 {
+		return false, ErrTeamPermissionDenied
+	}
+	return accessRank[level] >= accessRank[model.AccessLevelWrite], nil
+}
+
+// requireTeam returns ErrTeamPermissionDenied unless userID has at least
+// level in teamID.
+func (s *VariableService) requireTeam(ctx context.Context, teamID, userID, level string) error {
+	granted, err := s.teamLevel(ctx, teamID, userID)
+	if err != nil {
+		return err
+	}
+	if accessRank[granted] < accessRank[level] || accessRank[granted] == 0 {
+		return ErrTeamPermissionDenied
+	}
+	return nil
+}
+
+// teamLevel returns the access level of userID in teamID ("" for none). Team
+// variables are visible to team members only; owners and admins of the
+// team's organization have admin access.
+func (s *VariableService) teamLevel(ctx context.Context, teamID, userID string) (string, error) {
+	if userID == "" || teamID == "" {
+		return "", nil
+	}
+	team, err := s.teamRepo.Get(ctx, teamID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrTeamPermissionDenied
+		}
+		return "", fmt.Errorf("get team failed: %w", err)
+	}
+	if team.OrgID != "" {
+		member, err := s.orgMemberRepo.Get(ctx, team.OrgID, userID)
+		switch {
+		case err == nil && member.Status == model.OrgMemberStatusActive &&
+			(member.RoleID == model.OrgRoleOwner || member.RoleID == model.OrgRoleAdmin):
+			return model.AccessLevelAdmin, nil
+		case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+			return "", fmt.Errorf("get organization member failed: %w", err)
+		}
+	}
+	member, err := s.teamMemberRepo.Get(ctx, teamID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("get team member failed: %w", err)
+	}
+	return teamRoleAccess(member.RoleID), nil
+}
+
+// teamRoleAccess maps a team member role to an access level.
+func teamRoleAccess(role string) string {
+	switch role {
+	case model.TeamRoleOwner, model.TeamRoleMaintainer:
+		return model.AccessLevelAdmin
+	case model.TeamRoleDeveloper:
+		return model.AccessLevelWrite
+	default:
+		return model.AccessLevelRead
+	}
+}
+
+// validateCreateVariableReq validates the request and returns the variable type.
+func validateCreateVariableReq(req *model.CreateVariableReq) (string, error) {
+	if !model.IsValidVariableKey(req.Key) {
+		return "", fmt.Errorf("invalid variable key: %q", req.Key)
+	}
+	varType := req.Type
+	if varType == "" {
+		varType = model.VariableTypeEnv
+	}
+	if !model.IsValidVariableType(varType) {
+		return "", fmt.Errorf("invalid variable type: %s", varType)
+	}
+	return varType, nil
+}
+
+// variableUpdates converts an update request to column updates.
+func variableUpdates(req *model.UpdateVariableReq) (map[string]interface{}, error) {
+	updates := make(map[string]interface{})
+	if req.Key != nil {
+		if !model.IsValidVariableKey(*req.Key) {
+			return nil, fmt.Errorf("invalid variable key: %q", *req.Key)
+		}
+		updates["key"] = *req.Key
+	}
+	if req.Value != nil {
+		updates["value"] = *req.Value
+	}
+	if req.Type != nil {
+		if !model.IsValidVariableType(*req.Type) {
+			return nil, fmt.Errorf("invalid variable type: %s", *req.Type)
+		}
+		updates["type"] = *req.Type
+	}
+	if req.Protected != nil {
+		updates["protected"] = boolToInt(*req.Protected)
+	}
+	if req.Masked != nil {
+		updates["masked"] = boolToInt(*req.Masked)
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	return updates, nil
+}
+
+func variableErr(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrVariableNotFound
+	}
+	return fmt.Errorf("get variable failed: %w", err)
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"gorm.io/gorm"
+)
+
+type fakeVariableRepo struct {
+	repo.IVariableRepository
+	project map[string][]*model.ProjectVariable
+	team    map[string][]*model.TeamVariable
+}
+
+func (r *fakeVariableRepo) ListProjectVariables(_ context.Context, projectID string) ([]*model.ProjectVariable, error) {
+	return r.project[projectID], nil
+}
+
+func (r *fakeVariableRepo) ListTeamVariables(_ context.Context, teamID string) ([]*model.TeamVariable, error) {
+	return r.team[teamID], nil
+}
+
+func (r *fakeVariableRepo) ProjectVariableKeyExists(context.Context, string, string, ...string) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeVariableRepo) CreateProjectVariable(_ context.Context, v *model.ProjectVariable) error {
+	r.project[v.ProjectID] = append(r.project[v.ProjectID], v)
+	return nil
+}
+
+type fakeTeamRepo struct {
+	repo.ITeamRepository
+	teams map[string]*model.Team
+}
+
+func (r fakeTeamRepo) Get(_ context.Context, teamID string) (*model.Team, error) {
+	if t, ok := r.teams[teamID]; ok {
+		return t, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestVariableServiceProjectAccess(t *testing.T) {
+	f := newAccessFixture()
+	f.projects["payments-api"] = &model.Project{ProjectID: "payments-api", Visibility: model.VisibilityPublic}
+	f.members["payments-api/alice"] = string(model.ProjectRoleDeveloper)
+	f.members["payments-api/carol"] = string(model.ProjectRoleReporter)
+	vars := &fakeVariableRepo{project: map[string][]*model.ProjectVariable{
+		"payments-api": {
+			{VariableID: "v1", ProjectID: "payments-api", Key: "LOG_LEVEL", Value: "debug"},
+			{VariableID: "v2", ProjectID: "payments-api", Key: "DEPLOY_TOKEN", Value: "tok-live", Protected: 1},
+		},
+	}}
+	svc := NewVariableService(vars, fakeProjectRepo{f: f}, fakeTeamRepo{}, fakeTeamMemberRepo{f: f},
+		fakeOrgMemberRepo{f: f}, f.service())
+	ctx := context.Background()
+
+	// Writers see protected values; readers only see unprotected ones.
+	list, err := svc.ListProjectVariables(ctx, "payments-api", "alice")
+	if err != nil {
+		t.Fatalf("developer ListProjectVariables: %v", err)
+	}
+	if list[1].Value != "tok-live" {
+		t.Fatalf("developer protected value = %q, want tok-live", list[1].Value)
+	}
+	list, err = svc.ListProjectVariables(ctx, "payments-api", "carol")
+	if err != nil {
+		t.Fatalf("reporter ListProjectVariables: %v", err)
+	}
+	if list[0].Value != "debug" || list[1].Value != "" {
+		t.Fatalf("reporter values = %q, %q; want debug and redacted", list[0].Value, list[1].Value)
+	}
+
+	if _, err := svc.CreateProjectVariable(ctx, "payments-api", "carol", &model.CreateVariableReq{
+		Key: "FEATURE_FLAG", Value: "on",
+	}); !errors.Is(err, ErrProjectPermissionDenied) {
+		t.Fatalf("reporter CreateProjectVariable: got %v, want ErrProjectPermissionDenied", err)
+	}
+	if _, err := svc.CreateProjectVariable(ctx, "payments-api", "alice", &model.CreateVariableReq{
+		Key: "FEATURE_FLAG", Value: "on",
+	}); err != nil {
+		t.Fatalf("developer CreateProjectVariable: %v", err)
+	}
+	if err := svc.DeleteProjectVariable(ctx, "payments-api", "", "v1"); !errors.Is(err, ErrProjectPermissionDenied) {
+		t.Fatalf("anonymous DeleteProjectVariable: got %v, want ErrProjectPermissionDenied", err)
+	}
+}
+
+func TestVariableServiceTeamAccess(t *testing.T) {
+	f := newAccessFixture()
+	f.userTeams["alice"] = []model.TeamMember{{TeamID: "platform", UserID: "alice", RoleID: model.TeamRoleMaintainer}}
+	f.userTeams["carol"] = []model.TeamMember{{TeamID: "platform", UserID: "carol", RoleID: model.TeamRoleGuest}}
+	f.orgMembers["acme/olivia"] = &model.OrganizationMember{
+		OrgID: "acme", UserID: "olivia", RoleID: model.OrgRoleAdmin, Status: model.OrgMemberStatusActive,
+	}
+	teams := fakeTeamRepo{teams: map[string]*model.Team{"platform": {TeamID: "platform", OrgID: "acme"}}}
+	vars := &fakeVariableRepo{team: map[string][]*model.TeamVariable{
+		"platform": {{VariableID: "v1", TeamID: "platform", Key: "REGISTRY_PASSWORD", Value: "hunter2", Protected: 1}},
+	}}
+	svc := NewVariableService(vars, fakeProjectRepo{f: f}, teams, fakeTeamMemberRepo{f: f},
+		fakeOrgMemberRepo{f: f}, f.service())
+	ctx := context.Background()
+
+	if _, err := svc.ListTeamVariables(ctx, "platform", "mallory"); !errors.Is(err, ErrTeamPermissionDenied) {
+		t.Fatalf("non-member ListTeamVariables: got %v, want ErrTeamPermissionDenied", err)
+	}
+	list, err := svc.ListTeamVariables(ctx, "platform", "carol")
+	if err != nil || list[0].Value != "" {
+		t.Fatalf("guest ListTeamVariables = %v, %v; want redacted protected value", list, err)
+	}
+	for _, user := range []string{"alice", "olivia"} {
+		list, err := svc.ListTeamVariables(ctx, "platform", user)
+		if err != nil || list[0].Value != "hunter2" {
+			t.Fatalf("%s ListTeamVariables = %v, %v; want protected value", user, list, err)
+		}
+	}
+	if err := svc.DeleteTeamVariable(ctx, "platform", "carol", "v1"); !errors.Is(err, ErrTeamPermissionDenied) {
+		t.Fatalf("guest DeleteTeamVariable: got %v, want ErrTeamPermissionDenied", err)
+	}
+	if _, err := svc.ListTeamVariables(ctx, "unknown", "alice"); !errors.Is(err, ErrTeamPermissionDenied) {
+		t.Fatalf("unknown team: got %v, want ErrTeamPermissionDenied", err)
+	}
+}
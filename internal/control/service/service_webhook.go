@@ -0,0 +1,631 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"github.com/arcentrix/arcentra/pkg/id"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/arcentrix/arcentra/pkg/retry"
+	"github.com/arcentrix/arcentra/pkg/safe"
+	"github.com/arcentrix/arcentra/pkg/scm"
+	"github.com/bytedance/sonic"
+	"github.com/go-resty/resty/v2"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Headers sent with every outgoing project webhook request. The signature
+// follows the GitHub convention and verifies with
+// scm.VerifyHmacSha256Hex(body, secret, header, "sha256=").
+const (
+	WebhookEventHeader     = "X-Arcentra-Event"
+	WebhookDeliveryHeader  = "X-Arcentra-Delivery"
+	WebhookSignatureHeader = "X-Arcentra-Signature-256"
+	webhookSignaturePrefix = "sha256="
+)
+
+const (
+	webhookMaxAttempts      = 5
+	webhookBackoffBase      = 2 * time.Second
+	webhookBackoffMax       = time.Minute
+	webhookRequestTimeout   = 10 * time.Second
+	webhookResponseMaxBytes = 4096
+	webhookResumeBatchSize  = 200
+	// webhookClaimTimeout is how long a replica owns a delivery it is sending.
+	// It outlasts a full retry sequence, so a claim only goes stale when its
+	// sender died.
+	webhookClaimTimeout = 5 * time.Minute
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrWebhookAddressBlocked   = errors.New("webhook url resolves to a loopback, private or link-local address")
+)
+
+// webhookPermanentError marks a response that retrying will not fix
+// (a 4xx other than 408/429).
+type webhookPermanentError struct {
+	code int
+}
+
+func (e *webhookPermanentError) Error() string {
+	return fmt.Sprintf("webhook endpoint responded with status %d", e.code)
+}
+
+// WebhookService manages project webhooks and delivers run lifecycle events
+// to them. Every delivery is persisted before it is sent so that it survives
+// restarts, is retried with exponential backoff and can be redelivered.
+type WebhookService struct {
+	webhookRepo repo.IWebhookRepository
+	projectRepo repo.IProjectRepository
+	access      *ProjectAccessService
+	client      *resty.Client
+
+	// inflight holds the IDs of deliveries this replica is sending; other
+	// replicas are kept off them by the claim on the delivery row.
+	inflight sync.Map
+}
+
+// NewWebhookService creates the webhook service. Managing webhooks and
+// redelivering requires admin access to the project; reading them and their
+// deliveries requires read access.
+func NewWebhookService(
+	webhookRepo repo.IWebhookRepository,
+	projectRepo repo.IProjectRepository,
+	access *ProjectAccessService,
+) *WebhookService {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		projectRepo: projectRepo,
+		access:      access,
+		client:      resty.NewWithClient(&http.Client{Transport: webhookTransport()}).SetTimeout(webhookRequestTimeout),
+	}
+}
+
+// webhookTransport dials webhook endpoints only on public addresses. The check
+// runs on the resolved address of every connection, redirects included, so
+// DNS answers cannot point a webhook at the control plane's network. Proxies
+// are not used since they would dial on our behalf.
+func webhookTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout: webhookRequestTimeout,
+		Control: webhookDialControl,
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = nil
+	t.DialContext = dialer.DialContext
+	return t
+}
+
+// webhookDialControl rejects connections to blocked addresses.
+func webhookDialControl(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("webhook dial %s: %w", address, err)
+	}
+	if blockedWebhookAddr(addrPort.Addr()) {
+		return fmt.Errorf("webhook dial %s: %w", address, ErrWebhookAddressBlocked)
+	}
+	return nil
+}
+
+// blockedWebhookAddr reports whether webhooks may not be sent to addr:
+// loopback, RFC 1918 and unique local, link-local (including the cloud
+// metadata endpoint 169.254.169.254), carrier-grade NAT, unspecified and
+// multicast addresses.
+func blockedWebhookAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return !addr.IsValid() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() ||
+		sharedAddressSpace.Contains(addr)
+}
+
+// sharedAddressSpace is the carrier-grade NAT range of RFC 6598.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// CreateWebhook creates a webhook in a project.
+func (s *WebhookService) CreateWebhook(
+	ctx context.Context,
+	projectID, operatorID string,
+	req *model.CreateProjectWebhookReq,
+) (*model.ProjectWebhookResp, error) {
+	if err := s.access.Require(ctx, projectID, operatorID, model.AccessLevelAdmin); err != nil {
+		return nil, err
+	}
+	if exists, err := s.projectRepo.Exists(ctx, projectID); err != nil {
+		return nil, fmt.Errorf("check project exists failed: %w", err)
+	} else if !exists {
+		return nil, errors.New("project not found")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("webhook name is required")
+	}
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	events, err := encodeWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+	enabled := true
+	if req.IsEnabled != nil {
+		enabled = *req.IsEnabled
+	}
+
+	w := &model.ProjectWebhook{
+		WebhookID:   id.GetUUID(),
+		ProjectID:   projectID,
+		Name:        name,
+		URL:         strings.TrimSpace(req.URL),
+		Secret:      strings.TrimSpace(req.Secret),
+		Events:      events,
+		IsEnabled:   boolToInt(enabled),
+		Description: req.Description,
+	}
+	if err := s.webhookRepo.Create(ctx, w); err != nil {
+		log.Errorw("create project webhook failed", "projectID", projectID, "name", name, "error", err)
+		return nil, fmt.Errorf("create webhook failed: %w", err)
+	}
+	return model.ToProjectWebhookResp(w), nil
+}
+
+// ListWebhooks lists the webhooks of a project.
+func (s *WebhookService) ListWebhooks(ctx context.Context, projectID, operatorID string) ([]*model.ProjectWebhookResp, error) {
+	if err := s.access.Require(ctx, projectID, operatorID, model.AccessLevelRead); err != nil {
+		return nil, err
+	}
+	hooks, err := s.webhookRepo.List(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks failed: %w", err)
+	}
+	resp := make([]*model.ProjectWebhookResp, 0, len(hooks))
+	for _, w := range hooks {
+		resp = append(resp, model.ToProjectWebhookResp(w))
+	}
+	return resp, nil
+}
+
+// GetWebhook returns a webhook of a project.
+func (s *WebhookService) GetWebhook(ctx context.Context, projectID, operatorID, webhookID string) (*model.ProjectWebhookResp, error) {
+	if err := s.access.Require(ctx, projectID, operatorID, model.AccessLevelRead); err != nil {
+		return nil, err
+	}
+	return s.getWebhook(ctx, projectID, webhookID)
+}
+
+func (s *WebhookService) getWebhook(ctx context.Context, projectID, webhookID string) (*model.ProjectWebhookResp, error) {
+	w, err := s.webhookRepo.Get(ctx, projectID, webhookID)
+	if err != nil {
+		return nil, webhookErr(err)
+	}
+	return model.ToProjectWebhookResp(w), nil
+}
+
+// UpdateWebhook updates a webhook of a project.
+func (s *WebhookService) UpdateWebhook(
+	ctx context.Context,
+	projectID, operatorID, webhookID string,
+	req *model.UpdateProjectWebhookReq,
+) (*model.ProjectWebhookResp, error) {
+	if err := s.access.Require(ctx, projectID, operatorID, model.AccessLevelAdmin); err != nil {
+		return nil, err
+	}
+	if _, err := s.webhookRepo.Get(ctx, projectID, webhookID); err != nil {
+		return nil, webhookErr(err)
+	}
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("webhook name is required")
+		}
+		updates["name"] = name
+	}
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		updates["url"] = strings.TrimSpace(*req.URL)
+	}
+	if req.Secret != nil {
+		updates["secret"] = strings.TrimSpace(*req.Secret)
+	}
+	if req.Events != nil {
+		events, err := encodeWebhookEvents(*req.Events)
+		if err != nil {
+			return nil, err
+		}
+		updates["events"] = events
+	}
+	if req.IsEnabled != nil {
+		updates["is_enabled"] = boolToInt(*req.IsEnabled)
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if len(updates) > 0 {
+		if err := s.webhookRepo.Update(ctx, projectID, webhookID, updates); err != nil {
+			log.Errorw("update project webhook failed", "projectID", projectID, "webhookID", webhookID, "error", err)
+			return nil, fmt.Errorf("update webhook failed: %w", err)
+		}
+	}
+	return s.getWebhook(ctx, projectID, webhookID)
+}
+
+// DeleteWebhook deletes a webhook of a project and its delivery history.
+func (s *WebhookService) DeleteWebhook(ctx context.Context, projectID, operatorID, webhookID string) error {
+	if err := s.access.Require(ctx, projectID, operatorID, model.AccessLevelAdmin); err != nil {
+		return err
+	}
+	if _, err := s.webhookRepo.Get(ctx, projectID, webhookID); err != nil {
+		return webhookErr(err)
+	}
+	if err := s.webhookRepo.Delete(ctx, projectID, webhookID); err != nil {
+		log.Errorw("delete project webhook failed", "projectID", projectID, "webhookID", webhookID, "error", err)
+		return fmt.Errorf("delete webhook failed: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries lists the delivery history of a webhook, newest first.
+func (s *WebhookService) ListDeliveries(
+	ctx context.Context,
+	projectID, operatorID, webhookID string,
+	pageNum, pageSize int,
+) ([]*model.ProjectWebhookDelivery, int64, error) {
+	if err := s.access.Require(ctx, projectID, operatorID, model.AccessLevelRead); err != nil {
+		return nil, 0, err
+	}
+	if _, err := s.webhookRepo.Get(ctx, projectID, webhookID); err != nil {
+		return nil, 0, webhookErr(err)
+	}
+	if pageNum <= 0 {
+		pageNum = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	deliveries, total, err := s.webhookRepo.ListDeliveries(ctx, webhookID, pageNum, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list webhook deliveries failed: %w", err)
+	}
+	return deliveries, total, nil
+}
+
+// GetDelivery returns a delivery of a webhook.
+func (s *WebhookService) GetDelivery(
+	ctx context.Context,
+	projectID, operatorID, webhookID, deliveryID string,
+) (*model.ProjectWebhookDelivery, error) {
+	if err := s.access.Require(ctx, projectID, operatorID, model.AccessLevelRead); err != nil {
+		return nil, err
+	}
+	return s.getDelivery(ctx, projectID, webhookID, deliveryID)
+}
+
+func (s *WebhookService) getDelivery(
+	ctx context.Context,
+	projectID, webhookID, deliveryID string,
+) (*model.ProjectWebhookDelivery, error) {
+	if _, err := s.webhookRepo.Get(ctx, projectID, webhookID); err != nil {
+		return nil, webhookErr(err)
+	}
+	d, err := s.webhookRepo.GetDelivery(ctx, webhookID, deliveryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, fmt.Errorf("get webhook delivery failed: %w", err)
+	}
+	return d, nil
+}
+
+// Redeliver sends the payload of a previous delivery again as a new delivery.
+func (s *WebhookService) Redeliver(
+	ctx context.Context,
+	projectID, operatorID, webhookID, deliveryID string,
+) (*model.ProjectWebhookDelivery, error) {
+	if err := s.access.Require(ctx, projectID, operatorID, model.AccessLevelAdmin); err != nil {
+		return nil, err
+	}
+	original, err := s.getDelivery(ctx, projectID, webhookID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	d := &model.ProjectWebhookDelivery{
+		DeliveryID:   id.GetUUID(),
+		WebhookID:    original.WebhookID,
+		ProjectID:    original.ProjectID,
+		Event:        original.Event,
+		Payload:      original.Payload,
+		Status:       model.WebhookDeliveryPending,
+		RedeliveryOf: original.DeliveryID,
+	}
+	if err := s.webhookRepo.CreateDelivery(ctx, d); err != nil {
+		log.Errorw("create webhook redelivery failed", "webhookID", webhookID, "deliveryID", deliveryID, "error", err)
+		return nil, fmt.Errorf("create webhook delivery failed: %w", err)
+	}
+	s.deliverAsync(d)
+	return d, nil
+}
+
+// Dispatch records a delivery of event to every enabled webhook of the
+// project subscribed to it and sends them in the background. Events are
+// named "<resource>.<action>", e.g. "pipeline.completed" or "job.failed".
+func (s *WebhookService) Dispatch(ctx context.Context, projectID, event string, data map[string]any) {
+	if projectID == "" || event == "" {
+		return
+	}
+	hooks, err := s.webhookRepo.ListEnabled(ctx, projectID)
+	if err != nil {
+		log.Warnw("list project webhooks failed", "projectID", projectID, "event", event, "error", err)
+		return
+	}
+	for _, w := range hooks {
+		if !w.Subscribes(event) {
+			continue
+		}
+		deliveryID := id.GetUUID()
+		payload, err := sonic.Marshal(map[string]any{
+			"event":      event,
+			"deliveryId": deliveryID,
+			"projectId":  projectID,
+			"webhookId":  w.WebhookID,
+			"timestamp":  time.Now().UTC().Format(time.RFC3339),
+			"data":       data,
+		})
+		if err != nil {
+			log.Warnw("marshal webhook payload failed", "webhookID", w.WebhookID, "event", event, "error", err)
+			continue
+		}
+		d := &model.ProjectWebhookDelivery{
+			DeliveryID: deliveryID,
+			WebhookID:  w.WebhookID,
+			ProjectID:  projectID,
+			Event:      event,
+			Payload:    string(payload),
+			Status:     model.WebhookDeliveryPending,
+		}
+		if err := s.webhookRepo.CreateDelivery(ctx, d); err != nil {
+			log.Warnw("create webhook delivery failed", "webhookID", w.WebhookID, "event", event, "error", err)
+			continue
+		}
+		s.deliverAsync(d)
+	}
+}
+
+// ResumePending resends deliveries that never reached a final status, e.g.
+// because the control plane restarted while they were being retried. Only
+// deliveries nobody has claimed within webhookClaimTimeout are picked up, and
+// each is claimed before it is sent, so replicas never send one twice.
+func (s *WebhookService) ResumePending(ctx context.Context) {
+	deliveries, err := s.webhookRepo.ListPendingDeliveries(ctx, time.Now().Add(-webhookClaimTimeout), webhookResumeBatchSize)
+	if err != nil {
+		log.Warnw("list pending webhook deliveries failed", "error", err)
+		return
+	}
+	for _, d := range deliveries {
+		s.deliverAsync(d)
+	}
+}
+
+// deliverAsync sends a delivery in the background unless it is already being sent.
+func (s *WebhookService) deliverAsync(d *model.ProjectWebhookDelivery) {
+	if _, loaded := s.inflight.LoadOrStore(d.DeliveryID, struct{}{}); loaded {
+		return
+	}
+	safe.Go(func() {
+		defer s.inflight.Delete(d.DeliveryID)
+		s.deliver(context.Background(), d)
+	})
+}
+
+// deliver claims a delivery and sends it, retrying transient failures, and
+// records the outcome of every attempt.
+func (s *WebhookService) deliver(ctx context.Context, d *model.ProjectWebhookDelivery) {
+	claimed, err := s.webhookRepo.ClaimDelivery(ctx, d.DeliveryID, time.Now().Add(-webhookClaimTimeout))
+	if err != nil {
+		log.Warnw("claim webhook delivery failed", "deliveryID", d.DeliveryID, "error", err)
+		return
+	}
+	if !claimed {
+		// Finished, or being sent by another replica.
+		return
+	}
+
+	w, err := s.webhookRepo.Get(ctx, d.ProjectID, d.WebhookID)
+	if err != nil {
+		s.finishDelivery(ctx, d, model.WebhookDeliveryFailed, map[string]interface{}{
+			"error_message": "webhook no longer exists",
+		})
+		return
+	}
+	if w.IsEnabled != 1 {
+		s.finishDelivery(ctx, d, model.WebhookDeliveryFailed, map[string]interface{}{
+			"error_message": "webhook is disabled",
+		})
+		return
+	}
+
+	attempts := d.Attempts
+	err = retry.Do(ctx, func(ctx context.Context) error {
+		attempts++
+		updates, sendErr := s.send(ctx, w, d)
+		updates["attempts"] = attempts
+		updates["claimed_at"] = time.Now()
+		if sendErr != nil {
+			updates["error_message"] = sendErr.Error()
+		} else {
+			updates["error_message"] = ""
+		}
+		if err := s.webhookRepo.UpdateDelivery(ctx, d.DeliveryID, updates); err != nil {
+			log.Warnw("record webhook delivery attempt failed", "deliveryID", d.DeliveryID, "error", err)
+		}
+		return sendErr
+	},
+		retry.WithMaxAttempts(max(webhookMaxAttempts-d.Attempts, 1)),
+		retry.WithBackoff(retry.Exponential(webhookBackoffBase, webhookBackoffMax)),
+		retry.WithJitter(retry.FullJitter),
+		retry.WithRetryIf(func(err error) bool {
+			var permanent *webhookPermanentError
+			return retry.IsRetryableError(err) && !errors.As(err, &permanent)
+		}),
+	)
+
+	status := model.WebhookDeliverySucceeded
+	if err != nil {
+		status = model.WebhookDeliveryFailed
+		log.Warnw("webhook delivery failed",
+			"deliveryID", d.DeliveryID, "webhookID", d.WebhookID, "event", d.Event, "attempts", attempts, "error", err)
+	}
+	s.finishDelivery(ctx, d, status, nil)
+}
+
+// send performs a single delivery attempt and returns the columns to record.
+func (s *WebhookService) send(
+	ctx context.Context,
+	w *model.ProjectWebhook,
+	d *model.ProjectWebhookDelivery,
+) (map[string]interface{}, error) {
+	body := []byte(d.Payload)
+	req := s.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("User-Agent", "Arcentra-Webhook").
+		SetHeader(WebhookEventHeader, d.Event).
+		SetHeader(WebhookDeliveryHeader, d.DeliveryID).
+		SetBody(body)
+	if w.Secret != "" {
+		req.SetHeader(WebhookSignatureHeader, webhookSignaturePrefix+scm.SignHmacSha256Hex(body, w.Secret))
+	}
+
+	start := time.Now()
+	resp, err := req.Post(w.URL)
+	now := time.Now()
+	updates := map[string]interface{}{
+		"duration":     now.Sub(start).Milliseconds(),
+		"delivered_at": now,
+	}
+	if err != nil {
+		updates["response_code"] = 0
+		updates["response_body"] = ""
+		// %v rather than %w: a per-request timeout must stay retryable; retry.Do
+		// still stops on its own when ctx ends.
+		return updates, fmt.Errorf("webhook request failed: %v", err)
+	}
+	updates["response_code"] = resp.StatusCode()
+	updates["response_body"] = truncateWebhookResponse(resp.String())
+	if resp.IsSuccess() {
+		return updates, nil
+	}
+	code := resp.StatusCode()
+	if code >= 400 && code < 500 && code != http.StatusRequestTimeout && code != http.StatusTooManyRequests {
+		return updates, &webhookPermanentError{code: code}
+	}
+	return updates, fmt.Errorf("webhook endpoint responded with status %d", code)
+}
+
+func (s *WebhookService) finishDelivery(
+	ctx context.Context,
+	d *model.ProjectWebhookDelivery,
+	status int,
+	updates map[string]interface{},
+) {
+	if updates == nil {
+		updates = make(map[string]interface{})
+	}
+	updates["status"] = status
+	if err := s.webhookRepo.UpdateDelivery(ctx, d.DeliveryID, updates); err != nil {
+		log.Warnw("update webhook delivery status failed", "deliveryID", d.DeliveryID, "error", err)
+	}
+}
+
+// validateWebhookURL checks the form of a webhook URL and rejects hosts that
+// are blocked addresses or localhost. Host names are checked again on every
+// dial, see webhookTransport.
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.New("webhook url must be an absolute http(s) url")
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrWebhookAddressBlocked
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && blockedWebhookAddr(addr) {
+		return ErrWebhookAddressBlocked
+	}
+	return nil
+}
+
+// encodeWebhookEvents validates the subscribed events. An empty list
+// subscribes to every event.
+func encodeWebhookEvents(events []string) (datatypes.JSON, error) {
+	cleaned := make([]string, 0, len(events))
+	for _, e := range events {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		if e != model.WebhookEventAll && !strings.Contains(e, ".") {
+			return nil, fmt.Errorf("invalid webhook event %q, expected <resource>.<action>", e)
+		}
+		cleaned = append(cleaned, e)
+	}
+	if len(cleaned) == 0 {
+		cleaned = append(cleaned, model.WebhookEventAll)
+	}
+	encoded, err := sonic.Marshal(cleaned)
+	if err != nil {
+		return nil, fmt.Errorf("encode webhook events failed: %w", err)
+	}
+	return datatypes.JSON(encoded), nil
+}
+
+func truncateWebhookResponse(body string) string {
+	if len(body) <= webhookResponseMaxBytes {
+		return body
+	}
+	return body[:webhookResponseMaxBytes]
+}
+
+func webhookErr(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrWebhookNotFound
+	}
+	return fmt.Errorf("get webhook failed: %w", err)
+}
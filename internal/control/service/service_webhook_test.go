@@ -0,0 +1,169 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+)
+
+func TestValidateWebhookURL(t *testing.T) {
+	for _, raw := range []string{
+		"https://hooks.example.com/ci",
+		"http://203.0.113.10:8080/hook",
+	} {
+		if err := validateWebhookURL(raw); err != nil {
+			t.Errorf("validateWebhookURL(%q) = %v, want nil", raw, err)
+		}
+	}
+	for _, raw := range []string{
+		"ftp://example.com",
+		"/relative",
+		"http://localhost:8080",
+		"http://api.localhost",
+		"http://127.0.0.1",
+		"http://10.0.0.5",
+		"http://192.168.1.1",
+		"http://172.16.0.1",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]:80",
+		"http://[::ffff:127.0.0.1]",
+		"http://0.0.0.0",
+	} {
+		if err := validateWebhookURL(raw); err == nil {
+			t.Errorf("validateWebhookURL(%q) = nil, want error", raw)
+		}
+	}
+}
+
+func TestWebhookDialControl(t *testing.T) {
+	blocked := []string{
+		"127.0.0.1:80", "10.1.2.3:443", "172.31.255.255:80", "192.168.0.10:80",
+		"169.254.169.254:80", "100.64.0.1:80", "[fd00::1]:443", "[fe80::1]:80", "[::ffff:10.0.0.1]:80",
+	}
+	for _, addr := range blocked {
+		if err := webhookDialControl("tcp", addr, nil); !errors.Is(err, ErrWebhookAddressBlocked) {
+			t.Errorf("webhookDialControl(%s) = %v, want ErrWebhookAddressBlocked", addr, err)
+		}
+	}
+	for _, addr := range []string{"203.0.113.10:443", "[2001:db8::1]:443", "8.8.8.8:80"} {
+		if err := webhookDialControl("tcp", addr, nil); err != nil {
+			t.Errorf("webhookDialControl(%s) = %v, want nil", addr, err)
+		}
+	}
+	if !blockedWebhookAddr(netip.Addr{}) {
+		t.Errorf("blockedWebhookAddr(invalid) = false, want true")
+	}
+}
+
+type fakeWebhookRepo struct {
+	repo.IWebhookRepository
+	hooks      map[string]*model.ProjectWebhook
+	deliveries map[string]*model.ProjectWebhookDelivery
+	deleted    []string
+}
+
+func (r *fakeWebhookRepo) Get(_ context.Context, projectID, webhookID string) (*model.ProjectWebhook, error) {
+	if w, ok := r.hooks[webhookID]; ok && w.ProjectID == projectID {
+		return w, nil
+	}
+	return nil, ErrWebhookNotFound
+}
+
+func (r *fakeWebhookRepo) List(_ context.Context, projectID string) ([]*model.ProjectWebhook, error) {
+	var out []*model.ProjectWebhook
+	for _, w := range r.hooks {
+		if w.ProjectID == projectID {
+			out = append(out, w)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeWebhookRepo) Delete(_ context.Context, _, webhookID string) error {
+	r.deleted = append(r.deleted, webhookID)
+	return nil
+}
+
+func (r *fakeWebhookRepo) GetDelivery(_ context.Context, webhookID, deliveryID string) (*model.ProjectWebhookDelivery, error) {
+	if d, ok := r.deliveries[deliveryID]; ok && d.WebhookID == webhookID {
+		return d, nil
+	}
+	return nil, ErrWebhookDeliveryNotFound
+}
+
+func TestWebhookServiceRequiresProjectAccess(t *testing.T) {
+	f := newAccessFixture()
+	f.projects["payments-api"] = &model.Project{ProjectID: "payments-api", Visibility: model.VisibilityPrivate}
+	f.members["payments-api/alice"] = string(model.ProjectRoleMaintainer)
+	f.members["payments-api/bob"] = string(model.ProjectRoleDeveloper)
+	webhooks := &fakeWebhookRepo{
+		hooks: map[string]*model.ProjectWebhook{
+			"wh-deploy": {WebhookID: "wh-deploy", ProjectID: "payments-api", Name: "deploy-notifier"},
+		},
+		deliveries: map[string]*model.ProjectWebhookDelivery{
+			"dl-1": {DeliveryID: "dl-1", WebhookID: "wh-deploy"},
+		},
+	}
+	svc := NewWebhookService(webhooks, fakeProjectRepo{f: f}, f.service())
+	ctx := context.Background()
+
+	// A user who is not a member of the private project can neither read nor
+	// manage its webhooks.
+	if _, err := svc.ListWebhooks(ctx, "payments-api", "mallory"); !errors.Is(err, ErrProjectPermissionDenied) {
+		t.Fatalf("non-member ListWebhooks: got %v, want ErrProjectPermissionDenied", err)
+	}
+	if _, err := svc.GetWebhook(ctx, "payments-api", "mallory", "wh-deploy"); !errors.Is(err, ErrProjectPermissionDenied) {
+		t.Fatalf("non-member GetWebhook: got %v, want ErrProjectPermissionDenied", err)
+	}
+	if _, err := svc.GetDelivery(ctx, "payments-api", "mallory", "wh-deploy", "dl-1"); !errors.Is(err, ErrProjectPermissionDenied) {
+		t.Fatalf("non-member GetDelivery: got %v, want ErrProjectPermissionDenied", err)
+	}
+	if _, err := svc.CreateWebhook(ctx, "payments-api", "mallory", &model.CreateProjectWebhookReq{
+		Name: "exfil", URL: "https://attacker.example.com/hook",
+	}); !errors.Is(err, ErrProjectPermissionDenied) {
+		t.Fatalf("non-member CreateWebhook: got %v, want ErrProjectPermissionDenied", err)
+	}
+	if err := svc.DeleteWebhook(ctx, "payments-api", "mallory", "wh-deploy"); !errors.Is(err, ErrProjectPermissionDenied) {
+		t.Fatalf("non-member DeleteWebhook: got %v, want ErrProjectPermissionDenied", err)
+	}
+
+	// Developers may read webhooks but not manage or redeliver them.
+	hooks, err := svc.ListWebhooks(ctx, "payments-api", "bob")
+	if err != nil || len(hooks) != 1 {
+		t.Fatalf("developer ListWebhooks = %d, %v; want 1 webhook", len(hooks), err)
+	}
+	if _, err := svc.GetDelivery(ctx, "payments-api", "bob", "wh-deploy", "dl-1"); err != nil {
+		t.Fatalf("developer GetDelivery: %v", err)
+	}
+	if err := svc.DeleteWebhook(ctx, "payments-api", "bob", "wh-deploy"); !errors.Is(err, ErrProjectPermissionDenied) {
+		t.Fatalf("developer DeleteWebhook: got %v, want ErrProjectPermissionDenied", err)
+	}
+	if _, err := svc.Redeliver(ctx, "payments-api", "bob", "wh-deploy", "dl-1"); !errors.Is(err, ErrProjectPermissionDenied) {
+		t.Fatalf("developer Redeliver: got %v, want ErrProjectPermissionDenied", err)
+	}
+
+	if err := svc.DeleteWebhook(ctx, "payments-api", "alice", "wh-deploy"); err != nil {
+		t.Fatalf("maintainer DeleteWebhook: %v", err)
+	}
+	if len(webhooks.deleted) != 1 || webhooks.deleted[0] != "wh-deploy" {
+		t.Fatalf("deleted = %v, want only the maintainer's delete", webhooks.deleted)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/arcentrix/arcentra/pkg/http/jwt"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/arcentrix/arcentra/pkg/ws"
+	"github.com/bytedance/sonic"
+)
+
+const (
+	terminalMsgInput  = "input"
+	terminalMsgResize = "resize"
+	terminalMsgOpened = "opened"
+	terminalMsgExit   = "exit"
+	terminalMsgError  = "error"
+)
+
+// WSTerminalMessage is a control message of the terminal websocket. Terminal
+// output is sent as binary frames; binary frames from the client are input.
+type WSTerminalMessage struct {
+	Type      string `json:"type"`
+	SessionID string `json:"sessionId,omitempty"`
+	Data      string `json:"data,omitempty"`
+	Cols      uint32 `json:"cols,omitempty"`
+	Rows      uint32 `json:"rows,omitempty"`
+	ExitCode  *int   `json:"exitCode,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// WSTerminalHandle serves debug terminals over websocket. A connection opens
+// one terminal into the step run given by the stepRunId query parameter.
+type WSTerminalHandle struct {
+	terminals *TerminalService
+
+	mu       sync.Mutex
+	sessions map[string]*TerminalSession // connID -> session
+}
+
+func NewWSTerminalHandle(terminals *TerminalService) *WSTerminalHandle {
+	return &WSTerminalHandle{
+		terminals: terminals,
+		sessions:  make(map[string]*TerminalSession),
+	}
+}
+
+func (h *WSTerminalHandle) OnConnect(conn ws.Conn) error {
+	claims, _ := conn.Locals("claims").(*jwt.AuthClaims)
+	if claims == nil || claims.UserID == "" {
+		return h.reject(conn, errors.New("unauthorized"))
+	}
+	stepRunID := strings.TrimSpace(conn.Query("stepRunId"))
+	if stepRunID == "" {
+		return h.reject(conn, errors.New("stepRunId is required"))
+	}
+	cols, _ := strconv.ParseUint(conn.Query("cols", "80"), 10, 32)
+	rows, _ := strconv.ParseUint(conn.Query("rows", "24"), 10, 32)
+
+	sess, err := h.terminals.Open(context.Background(), claims.UserID, stepRunID, uint32(cols), uint32(rows))
+	if err != nil {
+		return h.reject(conn, err)
+	}
+	h.mu.Lock()
+	h.sessions[conn.ID()] = sess
+	h.mu.Unlock()
+
+	if err := conn.WriteJSON(WSTerminalMessage{Type: terminalMsgOpened, SessionID: sess.ID()}); err != nil {
+		return err
+	}
+	go h.forward(conn, sess)
+	return nil
+}
+
+// forward writes terminal output to the client until the shell exits, then
+// reports the exit code and closes the connection.
+func (h *WSTerminalHandle) forward(conn ws.Conn, sess *TerminalSession) {
+	for data := range sess.Output() {
+		if err := conn.WriteMessage(ws.BinaryMessage, data); err != nil {
+			log.Debugw("write terminal output failed", "conn", conn.ID(), "error", err)
+		}
+	}
+	exitCode, errMsg := sess.Exit()
+	_ = conn.WriteJSON(WSTerminalMessage{Type: terminalMsgExit, SessionID: sess.ID(), ExitCode: &exitCode, Error: errMsg})
+	_ = conn.Close()
+}
+
+func (h *WSTerminalHandle) OnMessage(conn ws.Conn, messageType int, data []byte) error {
+	h.mu.Lock()
+	sess := h.sessions[conn.ID()]
+	h.mu.Unlock()
+	if sess == nil {
+		return nil
+	}
+	if messageType == ws.BinaryMessage {
+		return sess.Input(data)
+	}
+	if messageType != ws.TextMessage {
+		return nil
+	}
+
+	var msg WSTerminalMessage
+	if err := sonic.Unmarshal(data, &msg); err != nil {
+		return conn.WriteJSON(WSTerminalMessage{Type: terminalMsgError, Error: "invalid request: message must be a JSON object"})
+	}
+	switch msg.Type {
+	case terminalMsgInput:
+		return sess.Input([]byte(msg.Data))
+	case terminalMsgResize:
+		return sess.Resize(msg.Cols, msg.Rows)
+	default:
+		return conn.WriteJSON(WSTerminalMessage{Type: terminalMsgError, Error: "unknown message type: " + msg.Type})
+	}
+}
+
+func (h *WSTerminalHandle) OnDisconnect(conn ws.Conn, _ error) {
+	h.mu.Lock()
+	sess := h.sessions[conn.ID()]
+	delete(h.sessions, conn.ID())
+	h.mu.Unlock()
+	if sess != nil {
+		sess.Close()
+	}
+}
+
+func (h *WSTerminalHandle) OnError(conn ws.Conn, err error) {
+	if err != nil {
+		log.Warnw("ws terminal error", "conn", conn.ID(), "error", err)
+	}
+}
+
+// reject tells the client why the terminal could not be opened.
+func (h *WSTerminalHandle) reject(conn ws.Conn, err error) error {
+	_ = conn.WriteJSON(WSTerminalMessage{Type: terminalMsgError, Error: err.Error()})
+	return err
+}
@@ -16,6 +16,8 @@ package service
 
 import (
 	"github.com/arcentrix/arcentra/internal/control/repo"
+	"github.com/arcentrix/arcentra/internal/shared/notify"
+	"github.com/arcentrix/arcentra/internal/shared/notify/template"
 	"github.com/arcentrix/arcentra/pkg/cache"
 	"github.com/arcentrix/arcentra/pkg/database"
 	"github.com/arcentrix/arcentra/pkg/sso/util"
@@ -28,25 +30,40 @@ type Services struct {
 	Agent             *AgentService
 	Identity          *IdentityService
 	Team              *TeamService
+	Organization      *OrganizationService
 	Storage           *StorageService
 	Upload            *UploadService
 	Secret            *SecretService
 	Setting           *SettingService
 	Project           *ProjectService
+	ProjectAccess     *ProjectAccessService
+	Variable          *VariableService
+	Webhook           *WebhookService
+	Terminal          *TerminalService
+	JobQueue          *JobQueueService // agent job scheduler; its task queue is set with the pipeline process
+	Concurrency       *ConcurrencyService
 	Scm               *ScmService
+	CommitStatus      *CommitStatusService // reports run state to the SCM once enabled from config
 	UserExt           *UserExt
 	Menu              *MenuService
 	Role              *RoleService
 	ProjectMemberRepo repo.IProjectMemberRepository
 	StepRunRepo       repo.IStepRunRepository
+	JobRunRepo        repo.IJobRunRepository
 	ProjectRepo       repo.IProjectRepository
 	PipelineRepo      repo.IPipelineRepository
 	StorageRepo       repo.IStorageRepository
 	LogAggregator     *LogAggregator
+	LogArchiver       *LogArchiver // set when log archival is enabled and storage is configured
+	LogSearch         *LogSearchService
 	Approval          *ApprovalService
 	PipelineTemplate  *PipelineTemplateService
 	RegistrationToken *RegistrationTokenService
-	PipelineEngine    IPipelineEngine // set after process initialization
+	// NotificationChannel / NotificationTemplate 通知渠道与模板管理
+	NotificationChannel  *NotificationChannelService
+	NotificationTemplate *NotificationTemplateService
+	Notification         *NotificationService // 流水线通知规则按模板渲染并发送
+	PipelineEngine       IPipelineEngine      // set after process initialization
 }
 
 // NewServices 初始化所有 service
@@ -54,6 +71,7 @@ func NewServices(
 	db database.IDatabase,
 	cacheStore cache.ICache,
 	repos *repo.Repositories,
+	notifyManager *notify.Manager,
 ) *Services {
 	// 基础服务
 	menuService := NewMenuService(repos.Menu)
@@ -71,43 +89,100 @@ func NewServices(
 	agentService := NewAgentService(repos.Agent, repos.StepRun, settingService, repos.JobRun)
 	stateStore := util.NewRedisStateStore(cacheStore)
 	identityService := NewIdentityService(repos.Identity, repos.User, repos.UserExt, stateStore)
+	organizationService := NewOrganizationService(
+		repos.Organization,
+		repos.OrganizationMember,
+		repos.OrgInvitation,
+		repos.User,
+		repos.Setting,
+		notifyManager,
+	)
 	teamService := NewTeamService(repos.Team)
+	teamService.SetOrganizationService(organizationService)
 	storageService := NewStorageService(repos.Storage)
 	uploadService := NewUploadService(repos.Storage)
 	secretService := NewSecretService(repos.Secret)
 	projectService := NewProjectService(repos.Project)
+	projectService.SetOrganizationService(organizationService)
+	projectAccessService := NewProjectAccessService(
+		repos.Project,
+		repos.ProjectMember,
+		repos.ProjectTeamAccess,
+		repos.TeamMember,
+		repos.OrganizationMember,
+	)
+	variableService := NewVariableService(
+		repos.Variable,
+		repos.Project,
+		repos.Team,
+		repos.TeamMember,
+		repos.OrganizationMember,
+		projectAccessService,
+	)
+	webhookService := NewWebhookService(repos.Webhook, repos.Project, projectAccessService)
+	terminalService := NewTerminalService(
+		repos.Terminal,
+		repos.StepRun,
+		repos.JobRun,
+		repos.Pipeline,
+		projectAccessService,
+		agentService.Streams(),
+	)
+	jobQueueService := NewJobQueueService(repos.JobQueue, repos.JobRun)
+	concurrencyService := NewConcurrencyService(repos.Concurrency)
 	scmService := NewScmService(repos.Project, repos.Pipeline)
+	commitStatusService := NewCommitStatusService(repos.Project, repos.Pipeline)
 	userExt := NewUserExt(repos.UserExt)
 	roleService := NewRoleService(repos.Role)
 	logAggregator := NewLogAggregator(nil, db.Database())
+	logSearchService := NewLogSearchService(db.Database(), repos.Pipeline, projectAccessService)
+	logAggregator.SetIndexer(logSearchService)
 	approvalService := NewApprovalService(repos.Approval)
 	pipelineTemplateService := NewPipelineTemplateService(repos.PipelineTemplate, repos.Secret)
 	registrationTokenService := NewRegistrationTokenService(repos.RegistrationToken)
 	agentService.SetRegistrationTokenService(registrationTokenService)
+	notificationChannelService := NewNotificationChannelService(repos.NotificationChannel, notifyManager)
+	notifyTemplateService := template.NewTemplateService(template.NewDatabaseTemplateRepository(repos.NotificationTemplate))
+	notificationTemplateService := NewNotificationTemplateService(notifyTemplateService, notifyManager)
+	notificationService := NewNotificationService(notifyManager, notifyTemplateService)
 
 	return &Services{
 		User:              userService,
 		Agent:             agentService,
 		Identity:          identityService,
 		Team:              teamService,
+		Organization:      organizationService,
 		Storage:           storageService,
 		Upload:            uploadService,
 		Secret:            secretService,
 		Setting:           settingService,
 		Project:           projectService,
+		ProjectAccess:     projectAccessService,
+		Variable:          variableService,
+		Webhook:           webhookService,
+		Terminal:          terminalService,
+		JobQueue:          jobQueueService,
+		Concurrency:       concurrencyService,
 		Scm:               scmService,
+		CommitStatus:      commitStatusService,
 		UserExt:           userExt,
 		Menu:              menuService,
 		Role:              roleService,
 		ProjectMemberRepo: repos.ProjectMember,
 		StepRunRepo:       repos.StepRun,
+		JobRunRepo:        repos.JobRun,
 		ProjectRepo:       repos.Project,
 		PipelineRepo:      repos.Pipeline,
 		StorageRepo:       repos.Storage,
 		LogAggregator:     logAggregator,
+		LogSearch:         logSearchService,
 		Approval:          approvalService,
 		PipelineTemplate:  pipelineTemplateService,
 		RegistrationToken: registrationTokenService,
+
+		NotificationChannel:  notificationChannelService,
+		NotificationTemplate: notificationTemplateService,
+		Notification:         notificationService,
 	}
 }
 
@@ -0,0 +1,310 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsl
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/arcentrix/arcentra/internal/shared/pipeline"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxMatrixCells bounds the number of jobs a single matrix may expand into.
+const maxMatrixCells = 256
+
+var (
+	// matrixExprRegex matches ${{ matrix.<axis> }} references.
+	matrixExprRegex = regexp.MustCompile(`\${{\s*matrix\.([A-Za-z0-9_-]+)\s*}}`)
+	// jobNameUnsafe matches characters not allowed in derived job names.
+	jobNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+// matrixCell is one combination of axis values.
+type matrixCell map[string]string
+
+// ExpandMatrix replaces every job that declares a matrix with one concrete job
+// per matrix cell. Cell values are substituted into ${{ matrix.<axis> }}
+// expressions and exported as MATRIX_<AXIS> env vars. DependsOn references to
+// an expanded job are rewritten to depend on all of its cells.
+//
+// The returned groups are keyed by original job name and drive max-parallel
+// and fail-fast in the reconciler. It returns nil when no job has a matrix.
+func ExpandMatrix(pl *spec.Pipeline) (map[string]*pipeline.MatrixGroup, error) {
+	if pl == nil {
+		return nil, nil
+	}
+
+	var groups map[string]*pipeline.MatrixGroup
+	expanded := make([]*spec.Job, 0, len(pl.Jobs))
+
+	// Cell names must not collide with any job of the pipeline, including
+	// hand-written jobs that look like a cell, e.g. "build-2".
+	taken := make(map[string]bool, len(pl.Jobs))
+	for _, job := range pl.Jobs {
+		if job != nil {
+			taken[job.Name] = true
+		}
+	}
+
+	for _, job := range pl.Jobs {
+		if job == nil || job.Matrix == nil {
+			expanded = append(expanded, job)
+			continue
+		}
+
+		cells, err := buildMatrixCells(job.Matrix)
+		if err != nil {
+			return nil, fmt.Errorf("job '%s' matrix: %w", job.Name, err)
+		}
+
+		group := &pipeline.MatrixGroup{
+			Name:        job.Name,
+			Jobs:        make([]string, 0, len(cells)),
+			MaxParallel: int(job.Matrix.MaxParallel),
+			FailFast:    job.Matrix.FailFast,
+		}
+		for _, cell := range cells {
+			name := uniqueJobName(matrixJobName(job.Name, cell), taken)
+			taken[name] = true
+			expanded = append(expanded, expandMatrixJob(job, name, cell))
+			group.Jobs = append(group.Jobs, name)
+		}
+
+		if groups == nil {
+			groups = make(map[string]*pipeline.MatrixGroup)
+		}
+		groups[job.Name] = group
+	}
+
+	if groups == nil {
+		return nil, nil
+	}
+
+	// Point dependents of a matrix job at all of its cells.
+	for _, job := range expanded {
+		if job == nil || len(job.DependsOn) == 0 {
+			continue
+		}
+		deps := make([]string, 0, len(job.DependsOn))
+		for _, dep := range job.DependsOn {
+			if group, ok := groups[dep]; ok {
+				deps = append(deps, group.Jobs...)
+				continue
+			}
+			deps = append(deps, dep)
+		}
+		job.DependsOn = deps
+	}
+
+	pl.Jobs = expanded
+	return groups, nil
+}
+
+// buildMatrixCells computes the cartesian product of the axes, then applies
+// exclude and include entries.
+func buildMatrixCells(m *spec.Matrix) ([]matrixCell, error) {
+	axes := spec.StructAsMap(m.Axes)
+	axisNames := make([]string, 0, len(axes))
+	for name := range axes {
+		axisNames = append(axisNames, name)
+	}
+	sort.Strings(axisNames)
+
+	cells := []matrixCell{{}}
+	for _, name := range axisNames {
+		values, ok := axes[name].([]any)
+		if !ok {
+			return nil, fmt.Errorf("axis '%s' must be a list", name)
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("axis '%s' has no values", name)
+		}
+		next := make([]matrixCell, 0, len(cells)*len(values))
+		for _, cell := range cells {
+			for _, v := range values {
+				c := cloneCell(cell)
+				c[name] = matrixValueString(v)
+				next = append(next, c)
+			}
+		}
+		if len(next) > maxMatrixCells {
+			return nil, fmt.Errorf("expands to more than %d jobs", maxMatrixCells)
+		}
+		cells = next
+	}
+	if len(axisNames) == 0 {
+		cells = nil
+	}
+
+	for _, ex := range m.Exclude {
+		entry := toCell(spec.StructAsMap(ex))
+		filtered := cells[:0]
+		for _, cell := range cells {
+			if !cellMatches(cell, entry) {
+				filtered = append(filtered, cell)
+			}
+		}
+		cells = filtered
+	}
+
+	for _, in := range m.Include {
+		entry := toCell(spec.StructAsMap(in))
+		// Keys that are axes select existing cells; the rest are added to them.
+		selector := matrixCell{}
+		for k, v := range entry {
+			if _, ok := axes[k]; ok {
+				selector[k] = v
+			}
+		}
+		matched := false
+		if len(selector) > 0 {
+			for _, cell := range cells {
+				if cellMatches(cell, selector) {
+					matched = true
+					for k, v := range entry {
+						cell[k] = v
+					}
+				}
+			}
+		}
+		if !matched {
+			cells = append(cells, entry)
+		}
+	}
+
+	if len(cells) == 0 {
+		return nil, fmt.Errorf("expands to no jobs")
+	}
+	if len(cells) > maxMatrixCells {
+		return nil, fmt.Errorf("expands to more than %d jobs", maxMatrixCells)
+	}
+	return cells, nil
+}
+
+// expandMatrixJob clones the job for one cell.
+func expandMatrixJob(job *spec.Job, name string, cell matrixCell) *spec.Job {
+	clone := proto.Clone(job).(*spec.Job)
+	clone.Matrix = nil
+
+	substituteMatrixRefs(clone, cell)
+	clone.Name = name
+
+	if clone.Env == nil {
+		clone.Env = make(map[string]string, len(cell))
+	}
+	for k, v := range cell {
+		clone.Env[matrixEnvKey(k)] = v
+	}
+	return clone
+}
+
+// substituteMatrixRefs replaces ${{ matrix.<axis> }} references in every
+// string field of job. Unknown axes are left untouched.
+func substituteMatrixRefs(job *spec.Job, cell matrixCell) {
+	spec.RewriteStrings(job, func(s string) string {
+		return matrixExprRegex.ReplaceAllStringFunc(s, func(m string) string {
+			key := matrixExprRegex.FindStringSubmatch(m)[1]
+			if v, ok := cell[key]; ok {
+				return v
+			}
+			return m
+		})
+	})
+}
+
+// matrixJobName derives "<job>-<v1>-<v2>..." with values ordered by key.
+func matrixJobName(base string, cell matrixCell) string {
+	keys := make([]string, 0, len(cell))
+	for k := range cell {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys)+1)
+	parts = append(parts, base)
+	for _, k := range keys {
+		v := strings.Trim(jobNameUnsafe.ReplaceAllString(cell[k], "-"), "-")
+		if v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// uniqueJobName returns name, or name suffixed with "-2", "-3"... when it is
+// already taken.
+func uniqueJobName(name string, taken map[string]bool) string {
+	if !taken[name] {
+		return name
+	}
+	for n := 2; ; n++ {
+		if candidate := fmt.Sprintf("%s-%d", name, n); !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
+// matrixEnvKey converts an axis name into its MATRIX_<AXIS> env var name.
+func matrixEnvKey(axis string) string {
+	return "MATRIX_" + strings.ToUpper(strings.ReplaceAll(axis, "-", "_"))
+}
+
+// matrixValueString renders a Struct value (string, number, bool) as a string.
+func matrixValueString(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+func toCell(m map[string]any) matrixCell {
+	cell := make(matrixCell, len(m))
+	for k, v := range m {
+		cell[k] = matrixValueString(v)
+	}
+	return cell
+}
+
+func cloneCell(c matrixCell) matrixCell {
+	out := make(matrixCell, len(c)+1)
+	for k, v := range c {
+		out[k] = v
+	}
+	return out
+}
+
+// cellMatches reports whether cell has every key/value of entry.
+func cellMatches(cell, entry matrixCell) bool {
+	if len(entry) == 0 {
+		return false
+	}
+	for k, v := range entry {
+		if cell[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,200 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsl
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	pipelinev1 "github.com/arcentrix/arcentra/api/pipeline/v1"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestExpandMatrix(t *testing.T) {
+	content := `
+namespace: test
+jobs:
+  - name: build
+    env:
+      GOVERSION: "${{ matrix.go }}"
+    matrix:
+      axes:
+        go: ["1.22", "1.23"]
+        os: [linux, darwin]
+      exclude:
+        - go: "1.22"
+          os: darwin
+      include:
+        - go: "1.23"
+          os: linux
+          race: "true"
+        - go: "1.24"
+          os: windows
+      max_parallel: 2
+      fail_fast: true
+    steps:
+      - name: test
+        uses: shell
+        args:
+          script: "go test ./... # ${{ matrix.os }} ${{ secrets.token }}"
+  - name: release
+    dependsOn: [build]
+    steps:
+      - name: publish
+        uses: shell
+`
+	pl, err := spec.ParseContentToProto(content, pipelinev1.SpecFormat_SPEC_FORMAT_YAML)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	groups, err := ExpandMatrix(pl)
+	if err != nil {
+		t.Fatalf("ExpandMatrix() error = %v", err)
+	}
+
+	wantJobs := []string{"build-1.22-linux", "build-1.23-linux-true", "build-1.23-darwin", "build-1.24-windows", "release"}
+	var gotJobs []string
+	for _, job := range pl.Jobs {
+		gotJobs = append(gotJobs, job.Name)
+	}
+	if !reflect.DeepEqual(gotJobs, wantJobs) {
+		t.Fatalf("jobs = %v, want %v", gotJobs, wantJobs)
+	}
+
+	group := groups["build"]
+	if group == nil || group.MaxParallel != 2 || !group.FailFast || len(group.Jobs) != 4 {
+		t.Fatalf("unexpected group: %+v", group)
+	}
+
+	cell := pl.Jobs[2]
+	if cell.Matrix != nil {
+		t.Errorf("expanded job still has matrix")
+	}
+	if cell.Env["GOVERSION"] != "1.23" || cell.Env["MATRIX_GO"] != "1.23" || cell.Env["MATRIX_OS"] != "darwin" {
+		t.Errorf("unexpected env: %v", cell.Env)
+	}
+	script := spec.StructAsMap(cell.Steps[0].Args)["script"]
+	if script != "go test ./... # darwin ${{ secrets.token }}" {
+		t.Errorf("unexpected script: %v", script)
+	}
+	if pl.Jobs[1].Env["MATRIX_RACE"] != "true" {
+		t.Errorf("include did not extend matching cell: %v", pl.Jobs[1].Env)
+	}
+
+	if got := pl.Jobs[4].DependsOn; !reflect.DeepEqual(got, group.Jobs) {
+		t.Errorf("release dependsOn = %v, want %v", got, group.Jobs)
+	}
+}
+
+func TestExpandMatrix_NameCollisions(t *testing.T) {
+	content := `
+namespace: test
+jobs:
+  - name: build
+    matrix:
+      axes:
+        platform: ["linux/amd64", "linux amd64", "darwin/arm64"]
+    steps:
+      - name: compile
+        uses: shell
+  - name: build-linux-amd64-2
+    steps:
+      - name: compile
+        uses: shell
+  - name: build-darwin-arm64
+    dependsOn: [build]
+    steps:
+      - name: notarize
+        uses: shell
+`
+	pl, err := spec.ParseContentToProto(content, pipelinev1.SpecFormat_SPEC_FORMAT_YAML)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	groups, err := ExpandMatrix(pl)
+	if err != nil {
+		t.Fatalf("ExpandMatrix() error = %v", err)
+	}
+
+	// Cells skip the names of each other and of the hand-written jobs.
+	wantCells := []string{"build-linux-amd64", "build-linux-amd64-3", "build-darwin-arm64-2"}
+	if got := groups["build"].Jobs; !reflect.DeepEqual(got, wantCells) {
+		t.Fatalf("cells = %v, want %v", got, wantCells)
+	}
+	wantJobs := append(slices.Clone(wantCells), "build-linux-amd64-2", "build-darwin-arm64")
+	var gotJobs []string
+	for _, job := range pl.Jobs {
+		gotJobs = append(gotJobs, job.Name)
+	}
+	if !reflect.DeepEqual(gotJobs, wantJobs) {
+		t.Fatalf("jobs = %v, want %v", gotJobs, wantJobs)
+	}
+	if got := pl.Jobs[4].DependsOn; !reflect.DeepEqual(got, wantCells) {
+		t.Errorf("build-darwin-arm64 dependsOn = %v, want %v", got, wantCells)
+	}
+}
+
+func TestExpandMatrix_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix *spec.Matrix
+	}{
+		{
+			name:   "axis not a list",
+			matrix: &spec.Matrix{Axes: spec.MapAsStruct(map[string]any{"go": "1.22"})},
+		},
+		{
+			name:   "empty axis",
+			matrix: &spec.Matrix{Axes: spec.MapAsStruct(map[string]any{"go": []any{}})},
+		},
+		{
+			name: "everything excluded",
+			matrix: &spec.Matrix{
+				Axes:    spec.MapAsStruct(map[string]any{"go": []any{"1.22"}}),
+				Exclude: []*structpb.Struct{spec.MapAsStruct(map[string]any{"go": "1.22"})},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl := &spec.Pipeline{
+				Namespace: "test",
+				Jobs:      []*spec.Job{{Name: "build", Matrix: tt.matrix}},
+			}
+			if _, err := ExpandMatrix(pl); err == nil {
+				t.Errorf("ExpandMatrix() expected error")
+			}
+		})
+	}
+}
+
+func TestExpandMatrix_NoMatrix(t *testing.T) {
+	pl := &spec.Pipeline{
+		Namespace: "test",
+		Jobs:      []*spec.Job{{Name: "build"}, {Name: "deploy", DependsOn: []string{"build"}}},
+	}
+	groups, err := ExpandMatrix(pl)
+	if err != nil || groups != nil {
+		t.Fatalf("ExpandMatrix() = %v, %v; want nil, nil", groups, err)
+	}
+	if len(pl.Jobs) != 2 || pl.Jobs[1].DependsOn[0] != "build" {
+		t.Errorf("pipeline modified without matrix")
+	}
+}
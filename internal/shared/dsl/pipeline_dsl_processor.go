@@ -60,7 +60,13 @@ func (p *Processor) ProcessConfig(
 		return nil, nil, fmt.Errorf("parse DSL config: %w", err)
 	}
 
+	matrixGroups, err := ExpandMatrix(pl)
+	if err != nil {
+		return nil, nil, fmt.Errorf("expand matrix: %w", err)
+	}
+
 	execCtx := pipeline.NewExecutionContext(pl, pluginMgr, workspace, p.logger)
+	execCtx.MatrixGroups = matrixGroups
 
 	for k, v := range additionalEnv {
 		execCtx.Env[k] = v
@@ -0,0 +1,322 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/arcentrix/arcentra/pkg/logstream"
+	"github.com/arcentrix/arcentra/pkg/sandbox"
+)
+
+const (
+	nameContainer = "container"
+
+	// ContainerWorkspace 容器内 job workspace 的挂载路径，同时作为工作目录
+	ContainerWorkspace = "/workspace"
+
+	// MetadataLogsStreamed 标记执行器已实时推送构建日志，Manager 不再重复推送
+	MetadataLogsStreamed = "logsStreamed"
+)
+
+// ContainerExecutor 在 sandbox 容器中执行 run/script/command 类步骤。
+// 步骤需指定运行时镜像，job workspace 以 bind mount 挂载到 /workspace，
+// stdout/stderr 按行实时推送到 LogPublisher，推送前对密钥做掩码。
+type ContainerExecutor struct {
+	sandbox sandbox.Sandbox
+	mu      sync.RWMutex
+	logPub  LogPublisher
+}
+
+// NewContainerExecutor 创建 ContainerExecutor。
+func NewContainerExecutor(sb sandbox.Sandbox) *ContainerExecutor {
+	return &ContainerExecutor{sandbox: sb}
+}
+
+// Name 返回执行器名称。
+func (e *ContainerExecutor) Name() string {
+	return nameContainer
+}
+
+// SetLogPublisher 设置实时推送构建日志的 LogPublisher。
+func (e *ContainerExecutor) SetLogPublisher(publisher LogPublisher) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.logPub = publisher
+}
+
+func (e *ContainerExecutor) getLogPublisher() LogPublisher {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.logPub
+}
+
+// CanExecute 检查步骤是否指定了容器镜像且包含可执行命令。
+func (e *ContainerExecutor) CanExecute(req *ExecutionRequest) bool {
+	if e.sandbox == nil || req == nil || req.Step == nil || req.Step.Args == nil {
+		return false
+	}
+	if req.ContainerImage() == "" {
+		return false
+	}
+	return strings.TrimSpace(BuildCommandFromStepArgs(req.Step)) != ""
+}
+
+// Execute 创建容器并使用 sh -lc 执行步骤命令，执行结束后删除容器。
+func (e *ContainerExecutor) Execute(ctx context.Context, req *ExecutionRequest) (*ExecutionResult, error) {
+	result := NewExecutionResult(e.Name())
+	if req == nil || req.Step == nil {
+		result.Complete(false, 1, nil)
+		result.Error = "nil request or step"
+		return result, nil
+	}
+	cmdText := BuildCommandFromStepArgs(req.Step)
+	if strings.TrimSpace(cmdText) == "" {
+		result.Complete(false, 1, nil)
+		result.Error = "empty command"
+		return result, nil
+	}
+
+	opts, err := containerCreateOptions(req, cmdText)
+	if err != nil {
+		result.Complete(false, 1, err)
+		return result, err
+	}
+	containerID, err := e.sandbox.Create(ctx, opts)
+	if err != nil {
+		err = fmt.Errorf("create step container: %w", err)
+		result.Complete(false, 1, err)
+		return result, err
+	}
+	result.WithMetadata("containerId", containerID)
+	defer func() { _ = e.sandbox.Remove(context.WithoutCancel(ctx), containerID) }()
+
+	streamer := newLogStreamer(ctx, e.getLogPublisher(), req.Masker, req)
+	stdout := streamer.writer("stdout")
+	stderr := streamer.writer("stderr")
+	execOpts := &sandbox.ExecuteOptions{
+		Stdout: stdout,
+		Stderr: stderr,
+	}
+	if req.Options != nil {
+		execOpts.Timeout = req.Options.Timeout
+	}
+	execResult, err := e.sandbox.Execute(ctx, containerID, opts.Command, execOpts)
+	stdout.flush()
+	stderr.flush()
+	result.WithOutput(stdout.String(), stderr.String())
+	result.WithMetadata(MetadataLogsStreamed, streamer.enabled())
+
+	if err != nil {
+		err = fmt.Errorf("run step container: %w", err)
+		result.Complete(false, 1, err)
+		if execResult != nil && execResult.ExitCode > 0 {
+			result.ExitCode = execResult.ExitCode
+		}
+		return result, err
+	}
+	if execResult.ExitCode != 0 {
+		err = fmt.Errorf("step container exited with code %d", execResult.ExitCode)
+		result.Complete(false, execResult.ExitCode, err)
+		if errOut := strings.TrimSpace(result.ErrorOutput); errOut != "" {
+			result.Error = errOut
+		}
+		return result, err
+	}
+	result.Complete(true, 0, nil)
+	return result, nil
+}
+
+// containerCreateOptions 根据执行请求构造容器创建参数。
+func containerCreateOptions(req *ExecutionRequest, cmdText string) (*sandbox.CreateOptions, error) {
+	opts := &sandbox.CreateOptions{
+		Image:       req.ContainerImage(),
+		Command:     []string{"sh", "-lc", cmdText},
+		Env:         make(map[string]string, len(req.Runtime.Env)+len(req.Env)),
+		NetworkMode: req.Runtime.NetworkMode,
+		Hosts:       req.Runtime.Hosts,
+		Labels: map[string]string{
+			"arcentra.step": req.Step.Name,
+		},
+	}
+
+	workspace := ""
+	if strings.TrimSpace(req.Workspace) != "" {
+		abs, err := filepath.Abs(req.Workspace)
+		if err != nil {
+			return nil, fmt.Errorf("resolve workspace: %w", err)
+		}
+		workspace = abs
+		opts.WorkingDir = ContainerWorkspace
+		opts.Mounts = []sandbox.Mount{{
+			Source: workspace,
+			Target: ContainerWorkspace,
+			Type:   "bind",
+		}}
+	}
+
+	// job/step env 覆盖运行时 env；指向宿主机 workspace 的路径改写为容器内路径
+	for k, v := range req.Runtime.Env {
+		opts.Env[k] = v
+	}
+	for k, v := range req.Env {
+		opts.Env[k] = toContainerPath(workspace, v)
+	}
+
+	if res := req.Runtime.Resources; res != nil {
+		opts.Resources = &sandbox.Resources{
+			CPU:               res.CPULimit,
+			CPUShares:         cpuShares(res.CPURequest),
+			Memory:            res.MemoryLimit,
+			MemoryReservation: res.MemoryRequest,
+		}
+	}
+	return opts, nil
+}
+
+// toContainerPath 将位于宿主机 workspace 下的路径改写为容器内 /workspace 下的路径。
+func toContainerPath(workspace, value string) string {
+	if workspace == "" || !filepath.IsAbs(value) {
+		return value
+	}
+	rel, err := filepath.Rel(workspace, value)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return value
+	}
+	return filepath.ToSlash(filepath.Join(ContainerWorkspace, rel))
+}
+
+// cpuShares 将 CPU 请求（如 "500m"、"2"）换算为 cgroup cpu shares（1 核 = 1024）。
+func cpuShares(request string) int64 {
+	request = strings.TrimSpace(request)
+	if request == "" {
+		return 0
+	}
+	if milli, ok := strings.CutSuffix(request, "m"); ok {
+		var m int64
+		if _, err := fmt.Sscanf(milli, "%d", &m); err != nil {
+			return 0
+		}
+		return m * 1024 / 1000
+	}
+	var cores float64
+	if _, err := fmt.Sscanf(request, "%g", &cores); err != nil {
+		return 0
+	}
+	return int64(cores * 1024)
+}
+
+// logStreamer 将容器输出按行推送到 LogPublisher，stdout 与 stderr 共用行号。
+type logStreamer struct {
+	ctx       context.Context
+	publisher LogPublisher
+	masker    *logstream.Masker
+	eventCtx  EventContext
+	stepRunID string
+	mu        sync.Mutex
+	line      int32
+}
+
+func newLogStreamer(ctx context.Context, publisher LogPublisher, masker *logstream.Masker, req *ExecutionRequest) *logStreamer {
+	return &logStreamer{
+		ctx:       ctx,
+		publisher: publisher,
+		masker:    masker,
+		eventCtx:  buildEventContext(req),
+		stepRunID: req.Step.RunID,
+	}
+}
+
+func (s *logStreamer) enabled() bool {
+	return s.publisher != nil
+}
+
+func (s *logStreamer) writer(stream string) *logLineWriter {
+	return &logLineWriter{streamer: s, stream: stream, mask: logstream.NewStreamMasker(s.masker)}
+}
+
+func (s *logStreamer) publish(content, stream string) {
+	if s.publisher == nil {
+		return
+	}
+	s.mu.Lock()
+	s.line++
+	msg := BuildLogMessageFromEvent(s.eventCtx, content, stream)
+	msg.LineNumber = s.line
+	s.mu.Unlock()
+	if s.stepRunID != "" {
+		msg.StepRunID = s.stepRunID
+	}
+	if stream == "stderr" {
+		msg.Level = "error"
+	}
+	_ = s.publisher.Publish(s.ctx, msg)
+}
+
+// logLineWriter 对写入内容做密钥掩码后按行切分推送，同时保留完整输出。
+// 掩码在切分前进行，跨越多次写入的密钥同样会被替换。
+type logLineWriter struct {
+	streamer *logStreamer
+	stream   string
+	mask     *logstream.StreamMasker
+	mu       sync.Mutex
+	pending  []byte
+	output   bytes.Buffer
+}
+
+// Write 实现 io.Writer。
+func (w *logLineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.append(w.mask.Write(p))
+	return len(p), nil
+}
+
+// append 记录已掩码的内容并推送其中完整的行。
+func (w *logLineWriter) append(p []byte) {
+	w.output.Write(p)
+	w.pending = append(w.pending, p...)
+	for {
+		idx := bytes.IndexByte(w.pending, '\n')
+		if idx < 0 {
+			break
+		}
+		w.streamer.publish(strings.TrimSuffix(string(w.pending[:idx]), "\r"), w.stream)
+		w.pending = w.pending[idx+1:]
+	}
+}
+
+// flush 推送最后一个未以换行结束的行。
+func (w *logLineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.append(w.mask.Flush())
+	if len(w.pending) > 0 {
+		w.streamer.publish(string(w.pending), w.stream)
+		w.pending = nil
+	}
+}
+
+// String 返回完整输出。
+func (w *logLineWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.output.String()
+}
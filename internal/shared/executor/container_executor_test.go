@@ -0,0 +1,197 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arcentrix/arcentra/pkg/logstream"
+	"github.com/arcentrix/arcentra/pkg/sandbox"
+)
+
+type fakeSandbox struct {
+	created  *sandbox.CreateOptions
+	removed  []string
+	stdout   string
+	stderr   string
+	exitCode int32
+}
+
+func (f *fakeSandbox) Create(_ context.Context, opts *sandbox.CreateOptions) (string, error) {
+	f.created = opts
+	return "c1", nil
+}
+
+func (f *fakeSandbox) Start(context.Context, string) error { return nil }
+
+func (f *fakeSandbox) Execute(_ context.Context, _ string, _ []string, opts *sandbox.ExecuteOptions) (*sandbox.ExecuteResult, error) {
+	_, _ = io.WriteString(opts.Stdout, f.stdout)
+	_, _ = io.WriteString(opts.Stderr, f.stderr)
+	return &sandbox.ExecuteResult{ExitCode: f.exitCode}, nil
+}
+
+func (f *fakeSandbox) Stop(context.Context, string, time.Duration) error { return nil }
+
+func (f *fakeSandbox) Remove(_ context.Context, id string) error {
+	f.removed = append(f.removed, id)
+	return nil
+}
+
+func (f *fakeSandbox) GetLogs(context.Context, string, *sandbox.LogOptions) (io.ReadCloser, error) {
+	return nil, nil
+}
+
+func (f *fakeSandbox) Cleanup(context.Context) error { return nil }
+func (f *fakeSandbox) Close() error                  { return nil }
+
+type recordingLogPublisher struct {
+	mu   sync.Mutex
+	msgs []*logstream.BuildLogMessage
+}
+
+func (p *recordingLogPublisher) Publish(_ context.Context, msg *logstream.BuildLogMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.msgs = append(p.msgs, msg)
+	return nil
+}
+
+func containerRequest(workspace string) *ExecutionRequest {
+	req := NewExecutionRequest(
+		&StepInfo{Name: "build", RunID: "sr-1", Args: map[string]any{"run": "make"}},
+		&JobInfo{Name: "job"},
+		&PipelineInfo{Namespace: "p1"},
+	)
+	req.Workspace = workspace
+	req.Env = map[string]string{
+		"GOFLAGS":         "-mod=mod",
+		"ARCENTRA_OUTPUT": filepath.Join(workspace, ".arcentra", "outputs", "sr-1.env"),
+	}
+	req.Runtime = &RuntimeInfo{
+		Type:      "docker",
+		Image:     "golang:1.24",
+		Env:       map[string]string{"GOFLAGS": "", "CGO_ENABLED": "0"},
+		Resources: &ResourceInfo{CPURequest: "500m", CPULimit: "2", MemoryLimit: "1Gi"},
+	}
+	return req
+}
+
+func TestContainerExecutor_CanExecute(t *testing.T) {
+	exec := NewContainerExecutor(&fakeSandbox{})
+	req := containerRequest("/tmp/ws")
+	if !exec.CanExecute(req) {
+		t.Error("expected container executor to handle steps with an image")
+	}
+	if NewShellExecutor().CanExecute(req) {
+		t.Error("expected shell executor to skip steps with an image")
+	}
+
+	req.Runtime.Type = RuntimeTypeHost
+	if exec.CanExecute(req) {
+		t.Error("expected host runtime to run on the host")
+	}
+	if NewContainerExecutor(nil).CanExecute(containerRequest("/tmp/ws")) {
+		t.Error("expected executor without sandbox to be unavailable")
+	}
+}
+
+func TestContainerExecutor_Execute(t *testing.T) {
+	workspace := t.TempDir()
+	sb := &fakeSandbox{stdout: "compiling\ndone", stderr: "warning: x\n"}
+	pub := &recordingLogPublisher{}
+
+	manager := NewExecutorManager()
+	manager.Register(NewContainerExecutor(sb))
+	manager.SetLogPublisher(pub)
+
+	result, err := manager.Execute(context.Background(), containerRequest(workspace))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !result.Success || result.ExecutorName != nameContainer {
+		t.Fatalf("result = %+v", result)
+	}
+
+	opts := sb.created
+	if opts.Image != "golang:1.24" || opts.WorkingDir != ContainerWorkspace {
+		t.Errorf("image/workdir = %s/%s", opts.Image, opts.WorkingDir)
+	}
+	if len(opts.Mounts) != 1 || opts.Mounts[0].Source != workspace || opts.Mounts[0].Target != ContainerWorkspace {
+		t.Errorf("mounts = %+v", opts.Mounts)
+	}
+	if opts.Env["GOFLAGS"] != "-mod=mod" || opts.Env["CGO_ENABLED"] != "0" {
+		t.Errorf("env = %v", opts.Env)
+	}
+	if got := opts.Env["ARCENTRA_OUTPUT"]; got != "/workspace/.arcentra/outputs/sr-1.env" {
+		t.Errorf("ARCENTRA_OUTPUT = %s", got)
+	}
+	if r := opts.Resources; r == nil || r.CPU != "2" || r.CPUShares != 512 || r.Memory != "1Gi" {
+		t.Errorf("resources = %+v", r)
+	}
+	if len(sb.removed) != 1 {
+		t.Errorf("container not removed")
+	}
+
+	if len(pub.msgs) != 3 {
+		t.Fatalf("published %d lines, want 3", len(pub.msgs))
+	}
+	for i, want := range []string{"compiling", "warning: x", "done"} {
+		msg := pub.msgs[i]
+		if msg.Content != want || msg.LineNumber != int32(i+1) || msg.StepRunID != "sr-1" {
+			t.Errorf("line %d = %+v", i, msg)
+		}
+	}
+	if result.Output != "compiling\ndone" || result.ErrorOutput != "warning: x\n" {
+		t.Errorf("output = %q / %q", result.Output, result.ErrorOutput)
+	}
+}
+
+func TestContainerExecutor_ExecuteFailure(t *testing.T) {
+	sb := &fakeSandbox{stderr: "make: *** no rule\n", exitCode: 2}
+	result, err := NewContainerExecutor(sb).Execute(context.Background(), containerRequest(t.TempDir()))
+	if err == nil {
+		t.Fatal("expected error for non-zero exit code")
+	}
+	if result.Success || result.ExitCode != 2 || result.Error != "make: *** no rule" {
+		t.Errorf("result = %+v", result)
+	}
+	if len(sb.removed) != 1 {
+		t.Errorf("container not removed after failure")
+	}
+}
+
+func TestLogLineWriter_MasksAcrossWrites(t *testing.T) {
+	masker := logstream.NewMasker()
+	masker.Add("hunter2-token")
+	pub := &recordingLogPublisher{}
+	w := newLogStreamer(context.Background(), pub, masker, containerRequest(t.TempDir())).writer("stdout")
+
+	for _, chunk := range []string{"login hun", "ter2-to", "ken ok\nbye"} {
+		_, _ = io.WriteString(w, chunk)
+	}
+	w.flush()
+
+	if len(pub.msgs) != 2 || pub.msgs[0].Content != "login *** ok" || pub.msgs[1].Content != "bye" {
+		t.Fatalf("published %+v", pub.msgs)
+	}
+	if got := w.String(); got != "login *** ok\nbye" {
+		t.Errorf("output = %q", got)
+	}
+}
@@ -18,6 +18,8 @@ import (
 	"context"
 	"strings"
 	"time"
+
+	"github.com/arcentrix/arcentra/pkg/logstream"
 )
 
 // Executor 定义了执行器的统一接口
@@ -50,13 +52,20 @@ type ExecutionRequest struct {
 	Workspace string
 	Env       map[string]string
 
+	// 运行时（容器镜像、资源限制），为空表示直接在宿主机执行
+	Runtime *RuntimeInfo
+
 	// 执行选项
 	Options *ExecutionOptions
+
+	// 本次执行所属 Job/Step 的密钥掩码集合，输出、事件与构建日志在离开执行器前掩码；为空时不掩码
+	Masker *logstream.Masker
 }
 
 // StepInfo step 信息
 type StepInfo struct {
 	Name           string
+	RunID          string // StepRun ID
 	Uses           string // Plugin name
 	Action         string // Plugin action
 	Args           map[string]any
@@ -80,6 +89,27 @@ type RetryInfo struct {
 	Delay       string
 }
 
+// RuntimeInfo 运行时信息
+type RuntimeInfo struct {
+	Type        string // 运行时类型，host 表示在宿主机执行
+	Image       string // 容器镜像
+	Env         map[string]string
+	Resources   *ResourceInfo
+	NetworkMode string            // 容器网络模式，"container:<id>" 表示加入 job 的网络
+	Hosts       map[string]string // 额外的 hosts 解析（主机名 -> IP），用于按名称访问 services
+}
+
+// ResourceInfo 资源请求与限制
+type ResourceInfo struct {
+	CPURequest    string
+	CPULimit      string
+	MemoryRequest string
+	MemoryLimit   string
+}
+
+// RuntimeTypeHost 在宿主机上执行的运行时类型
+const RuntimeTypeHost = "host"
+
 // PipelineInfo pipeline 信息
 type PipelineInfo struct {
 	Namespace string
@@ -200,6 +230,14 @@ func (r *ExecutionResult) WithMetadata(key string, value any) *ExecutionResult {
 	return r
 }
 
+// ContainerImage 返回 step 需要运行的容器镜像，未设置镜像或运行时为 host 时返回空
+func (r *ExecutionRequest) ContainerImage() string {
+	if r == nil || r.Runtime == nil || strings.EqualFold(r.Runtime.Type, RuntimeTypeHost) {
+		return ""
+	}
+	return strings.TrimSpace(r.Runtime.Image)
+}
+
 // ParseTimeout 解析超时字符串，无效或空时使用 defaultSec 作为默认秒数。
 func ParseTimeout(raw string, defaultSec int) time.Duration {
 	if strings.TrimSpace(raw) != "" {
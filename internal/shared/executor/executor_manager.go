@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/arcentrix/arcentra/pkg/logstream"
 	"github.com/arcentrix/arcentra/pkg/plugin"
 )
 
@@ -37,11 +38,19 @@ func NewExecutorManager() *Manager {
 	}
 }
 
+// LogStreamer 由实时推送构建日志的执行器实现，Manager 会向其传递 LogPublisher。
+type LogStreamer interface {
+	SetLogPublisher(publisher LogPublisher)
+}
+
 // Register 注册执行器。
 func (m *Manager) Register(executor Executor) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.executors = append(m.executors, executor)
+	if streamer, ok := executor.(LogStreamer); ok && m.logPub != nil {
+		streamer.SetLogPublisher(m.logPub)
+	}
 }
 
 // SelectExecutor 按注册顺序选择第一个可执行该请求的执行器。
@@ -69,6 +78,7 @@ func (m *Manager) Execute(ctx context.Context, req *ExecutionRequest) (*Executio
 	m.emitStarted(ctx, req, executor.Name())
 
 	result, execErr := executor.Execute(ctx, req)
+	maskResult(req.Masker, result)
 	if execErr != nil {
 		m.emitFailure(ctx, req, result, execErr, executor.Name())
 		return result, execErr
@@ -117,11 +127,27 @@ func (m *Manager) SetEventPublisher(publisher EventPublisher, config EventEmitte
 	m.SetEventEmitter(NewEventEmitter(publisher, config))
 }
 
-// SetLogPublisher sets the log publisher for build logs.
+// SetLogPublisher sets the log publisher for build logs and hands it to
+// executors that stream their logs live.
 func (m *Manager) SetLogPublisher(publisher LogPublisher) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.logPub = publisher
+	for _, executor := range m.executors {
+		if streamer, ok := executor.(LogStreamer); ok {
+			streamer.SetLogPublisher(publisher)
+		}
+	}
+}
+
+// maskResult 使用请求的密钥掩码集合对执行结果中的输出与错误信息做掩码。
+func maskResult(masker *logstream.Masker, result *ExecutionResult) {
+	if result == nil || masker == nil {
+		return
+	}
+	result.Output = masker.Mask(result.Output)
+	result.ErrorOutput = masker.Mask(result.ErrorOutput)
+	result.Error = masker.Mask(result.Error)
 }
 
 func (m *Manager) getEmitter() *EventEmitter {
@@ -228,6 +254,10 @@ func (m *Manager) emitLogs(
 		return
 	}
 	logPublisher := m.getLogPublisher()
+	if streamed, _ := result.Metadata[MetadataLogsStreamed].(bool); streamed {
+		// 日志已由执行器实时推送
+		logPublisher = nil
+	}
 	if result.Output != "" {
 		data := map[string]any{
 			"stream":  "stdout",
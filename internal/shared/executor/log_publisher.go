@@ -105,6 +105,28 @@ func (p *KafkaLogPublisher) Close() {
 	p.producer.Close()
 }
 
+// MaskingLogPublisher masks registered secret values in build logs before
+// handing them to the wrapped publisher.
+type MaskingLogPublisher struct {
+	publisher LogPublisher
+	masker    *logstream.Masker
+}
+
+// NewMaskingLogPublisher wraps publisher so that content goes through masker.
+func NewMaskingLogPublisher(publisher LogPublisher, masker *logstream.Masker) *MaskingLogPublisher {
+	return &MaskingLogPublisher{publisher: publisher, masker: masker}
+}
+
+// Publish masks the message content and publishes a copy of the message.
+func (p *MaskingLogPublisher) Publish(ctx context.Context, msg *logstream.BuildLogMessage) error {
+	if p == nil || p.publisher == nil || msg == nil {
+		return nil
+	}
+	masked := *msg
+	masked.Content = p.masker.Mask(msg.Content)
+	return p.publisher.Publish(ctx, &masked)
+}
+
 // BuildLogMessageFromEvent builds a build log message from EventContext.
 func BuildLogMessageFromEvent(ctx EventContext, content, stream string) *logstream.BuildLogMessage {
 	return &logstream.BuildLogMessage{
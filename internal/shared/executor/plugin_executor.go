@@ -98,7 +98,7 @@ func (e *PluginExecutor) Execute(ctx context.Context, req *ExecutionRequest) (*E
 
 // executePlugin 通过 plugin 调用执行（Shell 类型）
 // 支持本地执行和远程执行，由 UnifiedExecutor 根据 RunRemotely 字段决定
-func (e *PluginExecutor) executePlugin(_ context.Context, req *ExecutionRequest, pluginInstance plugin.Plugin) (*ExecutionResult, error) {
+func (e *PluginExecutor) executePlugin(ctx context.Context, req *ExecutionRequest, pluginInstance plugin.Plugin) (*ExecutionResult, error) {
 	result := NewExecutionResult(e.Name())
 
 	// 确定 action（默认为 "Execute"）
@@ -139,7 +139,7 @@ func (e *PluginExecutor) executePlugin(_ context.Context, req *ExecutionRequest,
 	}
 
 	// 调用 plugin 方法
-	pluginResult, err := pluginInstance.Execute(action, paramsJSON, optsJSON)
+	pluginResult, err := plugin.ExecuteContext(ctx, pluginInstance, action, paramsJSON, optsJSON)
 	if err != nil {
 		err = fmt.Errorf("plugin execution failed: %w", err)
 		result.Complete(false, -1, err)
@@ -36,10 +36,14 @@ func (e *ShellExecutor) Name() string {
 }
 
 // CanExecute 检查步骤是否包含可执行命令（Args 中含 run/script/command/commands）。
+// 指定了容器镜像的步骤交由 ContainerExecutor 执行，不会在宿主机上运行。
 func (e *ShellExecutor) CanExecute(req *ExecutionRequest) bool {
 	if req == nil || req.Step == nil || req.Step.Args == nil {
 		return false
 	}
+	if req.ContainerImage() != "" {
+		return false
+	}
 	return strings.TrimSpace(BuildCommandFromStepArgs(req.Step)) != ""
 }
 
@@ -206,7 +206,7 @@ func (e *UnifiedExecutor) executeRemotely(ctx context.Context, req *ExecutionReq
 }
 
 // executeLocally 在本地执行
-func (e *UnifiedExecutor) executeLocally(_ context.Context, req *ExecutionRequest) (*ExecutionResult, error) {
+func (e *UnifiedExecutor) executeLocally(ctx context.Context, req *ExecutionRequest) (*ExecutionResult, error) {
 	result := NewExecutionResult(e.Name())
 
 	if e.pluginManager == nil {
@@ -263,7 +263,7 @@ func (e *UnifiedExecutor) executeLocally(_ context.Context, req *ExecutionReques
 
 	// 调用 plugin 方法
 	// 注意：超时已通过 opts 传递给 plugin，plugin 内部会处理超时
-	pluginResult, err := pluginInstance.Execute(action, paramsJSON, optsJSON)
+	pluginResult, err := plugin.ExecuteContext(ctx, pluginInstance, action, paramsJSON, optsJSON)
 	if err != nil {
 		err = fmt.Errorf("plugin execution failed: %w", err)
 		result.Complete(false, -1, err)
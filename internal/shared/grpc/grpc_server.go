@@ -90,7 +90,10 @@ func (s *ServerWrapper) Register(services *service.Services,
 	agentv1.RegisterAgentServiceServer(s.svr, service.NewAgentServiceImpl(services.Agent, services.StorageRepo))
 	gatewayv1.RegisterGatewayServiceServer(s.svr, service.NewGatewayServiceImpl())
 	steprunv1.RegisterStepRunServiceServer(s.svr, service.NewStepRunServiceImpl(services.StepRunRepo))
-	streamv1.RegisterStreamServiceServer(s.svr, service.NewStreamService(redisClient, db, kafkaSettings))
+	streamSvc := service.NewStreamService(redisClient, db, kafkaSettings)
+	// 经 Kafka 汇入的构建日志落库后同样写入全文索引
+	streamSvc.GetLogAggregator().SetIndexer(services.LogSearch)
+	streamv1.RegisterStreamServiceServer(s.svr, streamSvc)
 	pipelinev1.RegisterPipelineServiceServer(s.svr, service.NewPipelineServiceImpl(services))
 	// reflection（调试）
 	reflection.Register(s.svr)
@@ -89,6 +89,19 @@ func (nc *NotifyChannel) SendWithTemplate(ctx context.Context, template string,
 	return nc.channel.SendWithTemplate(ctx, template, data)
 }
 
+// SendInteractive sends a message with action buttons when the channel supports
+// it; other channels receive the title, content and action links as plain text.
+func (nc *NotifyChannel) SendInteractive(ctx context.Context, title, content string, actions []InteractiveAction) error {
+	if ic, ok := nc.channel.(IInteractiveChannel); ok {
+		return ic.SendInteractive(ctx, title, content, actions)
+	}
+	message := title + "\n\n" + content
+	for _, a := range actions {
+		message += "\n" + a.Label + ": " + a.CallbackURL
+	}
+	return nc.channel.Send(ctx, message)
+}
+
 // Validate validates the channel configuration
 func (nc *NotifyChannel) Validate() error {
 	if nc.authProvider != nil {
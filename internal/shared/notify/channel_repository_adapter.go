@@ -45,7 +45,7 @@ func (a *ChannelRepositoryAdapter) ListActiveChannels(ctx context.Context) ([]*C
 
 	configs := make([]*ChannelConfig, 0, len(models))
 	for _, m := range models {
-		cfg, err := modelToChannelConfig(m)
+		cfg, err := ChannelConfigFromModel(m)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert channel %s: %w", m.Name, err)
 		}
@@ -55,8 +55,8 @@ func (a *ChannelRepositoryAdapter) ListActiveChannels(ctx context.Context) ([]*C
 	return configs, nil
 }
 
-// modelToChannelConfig 将 model.NotificationChannel 转换为 notify.ChannelConfig
-func modelToChannelConfig(m *model.NotificationChannel) (*ChannelConfig, error) {
+// ChannelConfigFromModel 将 model.NotificationChannel 转换为 notify.ChannelConfig
+func ChannelConfigFromModel(m *model.NotificationChannel) (*ChannelConfig, error) {
 	var config map[string]interface{}
 	if m.Config != "" {
 		if err := sonic.UnmarshalString(m.Config, &config); err != nil {
@@ -17,6 +17,8 @@ package notify
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strconv"
 	"sync"
 
 	"github.com/arcentrix/arcentra/internal/shared/notify/auth"
@@ -66,29 +68,14 @@ func (nm *Manager) LoadChannelsFromDatabase(ctx context.Context) error {
 
 	var errors []error
 	for _, cfg := range configs {
-		// 创建 channel 实例
-		ch, err := nm.factory.CreateChannel(cfg.Type, cfg.Config)
+		notifyChannel, err := nm.BuildChannel(cfg)
 		if err != nil {
 			log.Warnw("failed to create channel", "channel", cfg.Name, "error", err)
 			errors = append(errors, fmt.Errorf("channel %s: %w", cfg.Name, err))
 			continue
 		}
 
-		// 设置认证（如果有）
-		if len(cfg.AuthConfig) > 0 {
-			authType, _ := cfg.AuthConfig["type"].(string)
-			if authType != "" {
-				authProvider, err := nm.factory.CreateAuthProvider(auth.Type(authType), cfg.AuthConfig)
-				if err == nil {
-					_ = ch.SetAuth(authProvider)
-				} else {
-					log.Warnw("failed to create auth provider", "channel", cfg.Name, "error", err)
-				}
-			}
-		}
-
 		// 注册 channel
-		notifyChannel := channel.NewNotifyChannel(ch)
 		if err := nm.RegisterChannel(cfg.Name, notifyChannel); err != nil {
 			log.Warnw("failed to register channel", "channel", cfg.Name, "error", err)
 			errors = append(errors, fmt.Errorf("channel %s: %w", cfg.Name, err))
@@ -105,6 +92,125 @@ func (nm *Manager) LoadChannelsFromDatabase(ctx context.Context) error {
 	return nil
 }
 
+// ReloadChannelsFromDatabase 重新加载数据库中的活跃通知配置并替换当前全部 channel，
+// 已删除或停用的 channel 会被关闭并移除；单个 channel 失败不影响其它 channel
+func (nm *Manager) ReloadChannelsFromDatabase(ctx context.Context) error {
+	if nm.channelRepo == nil {
+		return fmt.Errorf("channel repository is not set")
+	}
+
+	configs, err := nm.channelRepo.ListActiveChannels(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load channels from database: %w", err)
+	}
+
+	channels := make(map[string]*channel.NotifyChannel, len(configs))
+	var errs []error
+	for _, cfg := range configs {
+		ch, err := nm.BuildChannel(cfg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: %w", cfg.Name, err))
+			continue
+		}
+		channels[cfg.Name] = ch
+	}
+
+	nm.mu.Lock()
+	old := nm.channels
+	nm.channels = channels
+	nm.mu.Unlock()
+	for _, ch := range old {
+		_ = ch.Close()
+	}
+
+	log.Infow("notification channels reloaded", "channel_count", len(channels))
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to load %d channel(s): %v", len(errs), errs)
+	}
+	return nil
+}
+
+// BuildChannel 根据配置创建 channel 实例（含认证）并校验配置，不注册到 Manager
+func (nm *Manager) BuildChannel(cfg *ChannelConfig) (*channel.NotifyChannel, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("channel config cannot be nil")
+	}
+	ch, err := nm.factory.CreateChannel(cfg.Type, cfg.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	notifyChannel := channel.NewNotifyChannel(ch)
+	// 设置认证（如果有）
+	if len(cfg.AuthConfig) > 0 {
+		authType, _ := cfg.AuthConfig["type"].(string)
+		if authType != "" {
+			authProvider, err := nm.factory.CreateAuthProvider(auth.Type(authType), cfg.AuthConfig)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create auth provider: %w", err)
+			}
+			_ = notifyChannel.SetAuth(authProvider)
+		}
+	}
+
+	if err := notifyChannel.Validate(); err != nil {
+		return nil, fmt.Errorf("channel validation failed: %w", err)
+	}
+	return notifyChannel, nil
+}
+
+// BuildEmailChannel 复用数据库中第一个活跃的 email channel 的 SMTP 与认证配置，
+// 创建一个发送给指定收件人的临时 channel（不注册到 Manager）
+func (nm *Manager) BuildEmailChannel(ctx context.Context, to ...string) (*channel.NotifyChannel, error) {
+	if len(to) == 0 {
+		return nil, fmt.Errorf("email recipients cannot be empty")
+	}
+	if nm.channelRepo == nil {
+		return nil, fmt.Errorf("channel repository is not set")
+	}
+	configs, err := nm.channelRepo.ListActiveChannels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load channels from database: %w", err)
+	}
+	for _, cfg := range configs {
+		if cfg.Type != ChannelTypeEmail {
+			continue
+		}
+		config := make(map[string]any, len(cfg.Config))
+		for k, v := range cfg.Config {
+			config[k] = v
+		}
+		recipients := make([]any, 0, len(to))
+		for _, addr := range to {
+			recipients = append(recipients, addr)
+		}
+		config["to_emails"] = recipients
+		return nm.BuildChannel(&ChannelConfig{
+			ChannelID:  cfg.ChannelID,
+			Name:       cfg.Name,
+			Type:       cfg.Type,
+			Config:     config,
+			AuthConfig: cfg.AuthConfig,
+		})
+	}
+	return nil, fmt.Errorf("no active email channel configured")
+}
+
+// ReplaceChannel 注册或替换 channel，被替换的旧 channel 会被关闭
+func (nm *Manager) ReplaceChannel(name string, ch *channel.NotifyChannel) error {
+	nm.mu.RLock()
+	old := nm.channels[name]
+	nm.mu.RUnlock()
+
+	if err := nm.RegisterChannel(name, ch); err != nil {
+		return err
+	}
+	if old != nil && old != ch {
+		_ = old.Close()
+	}
+	return nil
+}
+
 // RegisterChannel registers a notification channel
 func (nm *Manager) RegisterChannel(name string, ch *channel.NotifyChannel) error {
 	nm.mu.Lock()
@@ -232,6 +338,22 @@ var channelCreators = map[ChannelType]channelCreator{
 	ChannelTypeDiscord:    createDiscordChannel,
 }
 
+// SupportedChannelTypes returns the supported channel types sorted by name
+func SupportedChannelTypes() []ChannelType {
+	types := make([]ChannelType, 0, len(channelCreators))
+	for t := range channelCreators {
+		types = append(types, t)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// IsSupportedChannelType reports whether a channel type can be created
+func IsSupportedChannelType(channelType ChannelType) bool {
+	_, ok := channelCreators[channelType]
+	return ok
+}
+
 // CreateChannel creates a notification channel based on type and configuration
 func (cf *ChannelFactory) CreateChannel(channelType ChannelType, config map[string]any) (channel.INotifyChannel, error) {
 	if creator, ok := channelCreators[channelType]; ok {
@@ -310,7 +432,7 @@ func createWebhookChannel(config map[string]any) (channel.INotifyChannel, error)
 
 func createEmailChannel(config map[string]any) (channel.INotifyChannel, error) {
 	smtpHost, _ := config["smtp_host"].(string)
-	smtpPort, _ := config["smtp_port"].(int)
+	smtpPort := intValue(config["smtp_port"])
 	fromEmail, _ := config["from_email"].(string)
 	toEmailsRaw, _ := config["to_emails"].([]any)
 	var toEmails []string
@@ -325,6 +447,22 @@ func createEmailChannel(config map[string]any) (channel.INotifyChannel, error) {
 	return channel.NewEmailChannel(smtpHost, smtpPort, fromEmail, toEmails), nil
 }
 
+// intValue 读取整数配置，兼容 JSON 反序列化得到的 float64 与字符串
+func intValue(v any) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	case string:
+		i, _ := strconv.Atoi(n)
+		return i
+	}
+	return 0
+}
+
 func createSlackChannel(config map[string]any) (channel.INotifyChannel, error) {
 	webhookURL, _ := config["webhook_url"].(string)
 	if webhookURL == "" {
@@ -33,18 +33,23 @@ const (
 	Approval Type = "approval" // Approval-related notifications
 )
 
+// IsValidType reports whether t is a supported template type
+func IsValidType(t Type) bool {
+	return t == Build || t == Approval
+}
+
 // Template represents a notification template
 type Template struct {
-	ID          string                 // Template unique ID
-	Name        string                 // Template name
-	Type        Type                   // Template type (build/approval)
-	Channel     string                 // Target channel (dingtalk/feishu/slack/etc)
-	Title       string                 // Template title
-	Content     string                 // Template content with variables
-	Variables   []string               // Required variables
-	Format      string                 // Message format (text/markdown/html)
-	Metadata    map[string]interface{} // Additional metadata
-	Description string                 // Template description
+	ID          string                 `json:"id"`          // Template unique ID
+	Name        string                 `json:"name"`        // Template name
+	Type        Type                   `json:"type"`        // Template type (build/approval)
+	Channel     string                 `json:"channel"`     // Target channel (dingtalk/feishu/slack/etc)
+	Title       string                 `json:"title"`       // Template title
+	Content     string                 `json:"content"`     // Template content with variables
+	Variables   []string               `json:"variables"`   // Required variables
+	Format      string                 `json:"format"`      // Message format (text/markdown/html)
+	Metadata    map[string]interface{} `json:"metadata"`    // Additional metadata
+	Description string                 `json:"description"` // Template description
 }
 
 // Engine handles template rendering
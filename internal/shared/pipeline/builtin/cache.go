@@ -0,0 +1,590 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builtin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/arcentrix/arcentra/internal/shared/storage"
+	"github.com/bytedance/sonic"
+)
+
+// Cache scopes. Branch caches are restored before project caches; project
+// caches are shared by every branch of the project.
+const (
+	CacheScopeBranch  = "branch"
+	CacheScopeProject = "project"
+)
+
+// CacheArgs 缓存 restore / save 参数
+type CacheArgs struct {
+	Key         string   `json:"key"`                   // 缓存键前缀（如 "go-mod"）
+	Files       []string `json:"files,omitempty"`       // 参与哈希的锁文件（相对于workspace，支持 glob 与 **）
+	RestoreKeys []string `json:"restoreKeys,omitempty"` // 精确未命中时按前缀回退的键
+	Paths       []string `json:"paths"`                 // 要缓存的文件/目录路径（相对于workspace）
+	Scope       string   `json:"scope,omitempty"`       // 缓存作用域：branch（默认）, project
+}
+
+// CacheEntry describes a cache archive stored in object storage.
+type CacheEntry struct {
+	ID         string
+	Key        string
+	ObjectName string
+	Size       int64
+}
+
+// CacheIndex looks up and records cache entries. An empty branch denotes the
+// project-wide scope. Lookups return nil, nil on a miss.
+type CacheIndex interface {
+	Get(ctx context.Context, projectID, branch, key string) (*CacheEntry, error)
+	FindLatestByPrefix(ctx context.Context, projectID, branch, prefix string) (*CacheEntry, error)
+	Save(ctx context.Context, projectID, branch string, entry *CacheEntry) error
+	Touch(ctx context.Context, id string) error
+}
+
+// cacheScope identifies where a cache entry lives.
+type cacheScope struct {
+	projectID string
+	branch    string
+}
+
+// SetCache configures the object storage and index backing the cache builtin.
+func (m *Manager) SetCache(st storage.IStorage, index CacheIndex) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cacheStorage = st
+	m.cacheIndex = index
+}
+
+// cacheBackend returns the configured storage and index, failing when the
+// cache builtin has not been wired.
+func (m *Manager) cacheBackend() (storage.IStorage, CacheIndex, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.cacheStorage == nil || m.cacheIndex == nil {
+		return nil, nil, fmt.Errorf("cache storage is not configured")
+	}
+	return m.cacheStorage, m.cacheIndex, nil
+}
+
+// handleCacheRestore restores the first matching cache into the workspace.
+// The exact key is tried first, then every restore key as a prefix, in the
+// branch scope before the project scope.
+func (m *Manager) handleCacheRestore(ctx context.Context, params json.RawMessage, opts *Options) (json.RawMessage, error) {
+	args, scopes, err := parseCacheArgs(params, opts)
+	if err != nil {
+		return nil, err
+	}
+	st, index, err := m.cacheBackend()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := CacheKey(opts.Workspace, args.Key, args.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := lookupCache(ctx, index, scopes, key, args.RestoreKeys)
+	if err != nil {
+		return nil, fmt.Errorf("lookup cache %s: %w", key, err)
+	}
+	if entry == nil {
+		m.logger.Infow("cache miss", "key", key)
+		return sonic.Marshal(map[string]any{
+			"success": true,
+			"key":     key,
+			"outputs": map[string]string{"cache-hit": "false"},
+		})
+	}
+
+	data, err := st.Download(ctx, entry.ObjectName)
+	if err != nil {
+		return nil, fmt.Errorf("download cache %s: %w", entry.Key, err)
+	}
+	if err := extractCacheArchive(bytes.NewReader(data), opts.Workspace); err != nil {
+		return nil, fmt.Errorf("extract cache %s: %w", entry.Key, err)
+	}
+	if err := index.Touch(ctx, entry.ID); err != nil {
+		m.logger.Warnw("failed to touch cache entry", "key", entry.Key, "error", err)
+	}
+
+	hit := entry.Key == key
+	m.logger.Infow("cache restored", "key", key, "matchedKey", entry.Key, "size", entry.Size)
+	return sonic.Marshal(map[string]any{
+		"success":     true,
+		"key":         key,
+		"matched_key": entry.Key,
+		"outputs":     map[string]string{"cache-hit": fmt.Sprintf("%t", hit)},
+	})
+}
+
+// handleCacheSave archives the cache paths and uploads them under the
+// computed key. Entries are immutable: an existing key is left untouched.
+func (m *Manager) handleCacheSave(ctx context.Context, params json.RawMessage, opts *Options) (json.RawMessage, error) {
+	args, scopes, err := parseCacheArgs(params, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(args.Paths) == 0 {
+		return nil, fmt.Errorf("paths are required")
+	}
+	st, index, err := m.cacheBackend()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := CacheKey(opts.Workspace, args.Key, args.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	// 保存到最具体的作用域
+	scope := scopes[0]
+	existing, err := index.Get(ctx, scope.projectID, scope.branch, key)
+	if err != nil {
+		return nil, fmt.Errorf("lookup cache %s: %w", key, err)
+	}
+	if existing != nil {
+		m.logger.Infow("cache already exists, skip saving", "key", key)
+		return sonic.Marshal(map[string]any{"success": true, "key": key, "skipped": true})
+	}
+
+	archive, err := os.CreateTemp("", "arcentra-cache-*.tar.gz")
+	if err != nil {
+		return nil, fmt.Errorf("create cache archive: %w", err)
+	}
+	defer func() { _ = os.Remove(archive.Name()) }()
+
+	skipped, err := writeCacheArchive(archive, opts.Workspace, args.Paths)
+	if len(skipped) > 0 {
+		m.logger.Warnw("skip symlinks pointing outside the workspace", "key", key, "links", skipped)
+	}
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("archive cache %s: %w", key, err)
+	}
+
+	file, cleanup, err := storage.FileHeaderFromPath(archive.Name())
+	if err != nil {
+		return nil, fmt.Errorf("prepare cache upload: %w", err)
+	}
+	defer cleanup()
+
+	objectName := cacheObjectName(scope, key)
+	if _, err := st.Upload(ctx, objectName, file, "application/gzip"); err != nil {
+		return nil, fmt.Errorf("upload cache %s: %w", key, err)
+	}
+	entry := &CacheEntry{Key: key, ObjectName: objectName, Size: file.Size}
+	if err := index.Save(ctx, scope.projectID, scope.branch, entry); err != nil {
+		return nil, fmt.Errorf("record cache %s: %w", key, err)
+	}
+
+	m.logger.Infow("cache saved", "key", key, "size", file.Size)
+	return sonic.Marshal(map[string]any{
+		"success": true,
+		"key":     key,
+		"size":    file.Size,
+	})
+}
+
+// parseCacheArgs validates cache params and resolves the scopes to search,
+// most specific first.
+func parseCacheArgs(params json.RawMessage, opts *Options) (*CacheArgs, []cacheScope, error) {
+	var args CacheArgs
+	if err := sonic.Unmarshal(params, &args); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse cache params: %w", err)
+	}
+	if args.Key == "" {
+		return nil, nil, fmt.Errorf("key is required")
+	}
+	if opts == nil || opts.ExecutionContext == nil {
+		return nil, nil, fmt.Errorf("execution context is required")
+	}
+
+	projectID, branch := opts.ExecutionContext.GetCacheScope()
+	if projectID == "" {
+		return nil, nil, fmt.Errorf("cache requires a project scope")
+	}
+	switch args.Scope {
+	case "", CacheScopeBranch:
+		if branch != "" {
+			return &args, []cacheScope{{projectID, branch}, {projectID, ""}}, nil
+		}
+		return &args, []cacheScope{{projectID, ""}}, nil
+	case CacheScopeProject:
+		return &args, []cacheScope{{projectID, ""}}, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported cache scope %q", args.Scope)
+	}
+}
+
+// lookupCache returns the first entry matching key exactly or one of the
+// restore keys as a prefix, searching scopes in order.
+func lookupCache(ctx context.Context, index CacheIndex, scopes []cacheScope, key string, restoreKeys []string) (*CacheEntry, error) {
+	for _, scope := range scopes {
+		entry, err := index.Get(ctx, scope.projectID, scope.branch, key)
+		if err != nil || entry != nil {
+			return entry, err
+		}
+		for _, prefix := range restoreKeys {
+			if prefix == "" {
+				continue
+			}
+			entry, err := index.FindLatestByPrefix(ctx, scope.projectID, scope.branch, prefix)
+			if err != nil || entry != nil {
+				return entry, err
+			}
+		}
+	}
+	return nil, nil
+}
+
+// CacheKey appends the hash of the files matching patterns to prefix, e.g.
+// "go-mod-3f2a...". Without patterns the prefix is the key.
+func CacheKey(workspace, prefix string, patterns []string) (string, error) {
+	if len(patterns) == 0 {
+		return prefix, nil
+	}
+	hash, err := HashFiles(workspace, patterns)
+	if err != nil {
+		return "", err
+	}
+	return prefix + "-" + hash, nil
+}
+
+// HashFiles returns the sha256 over the relative path and content of every
+// file under workspace matching one of patterns. Patterns use path.Match
+// syntax plus "**" for any number of directories.
+func HashFiles(workspace string, patterns []string) (string, error) {
+	var matched []string
+	err := filepath.WalkDir(workspace, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(workspace, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		for _, pattern := range patterns {
+			if matchGlob(pattern, rel) {
+				matched = append(matched, rel)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("hash files: %w", err)
+	}
+	if len(matched) == 0 {
+		return "", fmt.Errorf("no files match %v", patterns)
+	}
+
+	sort.Strings(matched)
+	h := sha256.New()
+	for _, rel := range matched {
+		f, err := os.Open(filepath.Join(workspace, filepath.FromSlash(rel)))
+		if err != nil {
+			return "", fmt.Errorf("hash files: %w", err)
+		}
+		_, _ = io.WriteString(h, rel+"\x00")
+		_, err = io.Copy(h, f)
+		_ = f.Close()
+		if err != nil {
+			return "", fmt.Errorf("hash files: %w", err)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// matchGlob reports whether the slash-separated name matches pattern, where
+// a "**" segment matches zero or more path segments.
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// cacheObjectName returns the object key of a cache archive. The key is
+// hashed since it may contain characters unsuitable for object names.
+func cacheObjectName(scope cacheScope, key string) string {
+	branch := "_project"
+	if scope.branch != "" {
+		branch = url.PathEscape(scope.branch)
+	}
+	sum := sha256.Sum256([]byte(key))
+	return path.Join("caches", scope.projectID, branch, hex.EncodeToString(sum[:])+".tar.gz")
+}
+
+// writeCacheArchive writes paths (relative to workspace) as a tar.gz to w.
+// Absolute symlinks into the workspace are stored relative to their
+// directory; symlinks pointing outside the workspace would be rejected on
+// restore, so they are left out and returned.
+func writeCacheArchive(w io.Writer, workspace string, paths []string) ([]string, error) {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	var skipped []string
+	for _, p := range paths {
+		root, err := workspacePath(workspace, p)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Lstat(root); os.IsNotExist(err) {
+			// 缓存路径不存在时跳过（如首次构建尚未生成依赖目录）
+			continue
+		}
+		err = filepath.WalkDir(root, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			ok, err := addToArchive(tw, workspace, file, d)
+			if err == nil && !ok {
+				skipped = append(skipped, file)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return skipped, gw.Close()
+}
+
+// addToArchive writes a single file, directory or symlink to tw. It reports
+// false for a symlink left out because it points outside workspace.
+func addToArchive(tw *tar.Writer, workspace, file string, d fs.DirEntry) (bool, error) {
+	info, err := d.Info()
+	if err != nil {
+		return false, err
+	}
+	link := ""
+	if info.Mode()&os.ModeSymlink != 0 {
+		if link, err = os.Readlink(file); err != nil {
+			return false, err
+		}
+		var ok bool
+		if link, ok = archiveLinkTarget(workspace, file, link); !ok {
+			return false, nil
+		}
+	} else if !info.Mode().IsRegular() && !info.IsDir() {
+		return true, nil
+	}
+
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return false, err
+	}
+	rel, err := filepath.Rel(workspace, file)
+	if err != nil {
+		return false, err
+	}
+	header.Name = filepath.ToSlash(rel)
+	if err := tw.WriteHeader(header); err != nil {
+		return false, err
+	}
+	if !info.Mode().IsRegular() {
+		return true, nil
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = f.Close() }()
+	_, err = io.Copy(tw, f)
+	return err == nil, err
+}
+
+// archiveLinkTarget returns the link target to store for the symlink file,
+// rewriting absolute targets inside workspace relative to the link. It
+// reports false when the target is outside workspace.
+func archiveLinkTarget(workspace, file, link string) (string, bool) {
+	if filepath.IsAbs(link) {
+		rel, err := filepath.Rel(filepath.Dir(file), link)
+		if err != nil {
+			return "", false
+		}
+		link = rel
+	}
+	if checkLinkTarget(workspace, file, link) != nil {
+		return "", false
+	}
+	return filepath.ToSlash(link), true
+}
+
+// extractCacheArchive extracts a tar.gz produced by writeCacheArchive into
+// workspace. Entries escaping the workspace are rejected, as are symlinks
+// pointing outside it, and nothing is written through an existing symlink.
+func extractCacheArchive(r io.Reader, workspace string) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = gr.Close() }()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := workspacePath(workspace, header.Name)
+		if err != nil {
+			return err
+		}
+		if err := checkNoSymlinks(workspace, target); err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if isSymlink(target) {
+				return fmt.Errorf("path %s is a symlink", header.Name)
+			}
+			if err := os.MkdirAll(target, header.FileInfo().Mode().Perm()|0o700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if isSymlink(target) {
+				if err := os.Remove(target); err != nil {
+					return err
+				}
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, header.FileInfo().Mode().Perm())
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := checkLinkTarget(workspace, target, header.Linkname); err != nil {
+				return fmt.Errorf("symlink %s: %w", header.Name, err)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			_ = os.Remove(target)
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// workspacePath joins rel onto workspace, rejecting paths that escape it.
+func workspacePath(workspace, rel string) (string, error) {
+	target := filepath.Join(workspace, filepath.FromSlash(rel))
+	within, err := filepath.Rel(workspace, target)
+	if err != nil || within == ".." || strings.HasPrefix(within, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %s escapes the workspace", rel)
+	}
+	return target, nil
+}
+
+// checkLinkTarget rejects symlinks at target whose destination is absolute or
+// resolves outside workspace.
+func checkLinkTarget(workspace, target, linkname string) error {
+	if linkname == "" || filepath.IsAbs(filepath.FromSlash(linkname)) {
+		return fmt.Errorf("link target %q is not relative", linkname)
+	}
+	dest := filepath.Join(filepath.Dir(target), filepath.FromSlash(linkname))
+	within, err := filepath.Rel(workspace, dest)
+	if err != nil || within == ".." || strings.HasPrefix(within, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("link target %q escapes the workspace", linkname)
+	}
+	return nil
+}
+
+// checkNoSymlinks rejects target when a directory between workspace and
+// target is a symlink, so extraction never writes through one.
+func checkNoSymlinks(workspace, target string) error {
+	within, err := filepath.Rel(workspace, filepath.Dir(target))
+	if err != nil || within == "." {
+		return err
+	}
+	dir := workspace
+	for _, part := range strings.Split(within, string(filepath.Separator)) {
+		dir = filepath.Join(dir, part)
+		if isSymlink(dir) {
+			return fmt.Errorf("path %s passes through a symlink", target)
+		}
+	}
+	return nil
+}
+
+func isSymlink(path string) bool {
+	info, err := os.Lstat(path)
+	return err == nil && info.Mode()&os.ModeSymlink != 0
+}
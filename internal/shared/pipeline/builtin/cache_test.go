@@ -0,0 +1,352 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builtin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/bytedance/sonic"
+	"go.uber.org/zap"
+)
+
+type memStorage struct {
+	objects map[string][]byte
+}
+
+func (s *memStorage) PutObject(ctx context.Context, name string, file *multipart.FileHeader, _ string) (string, error) {
+	return s.Upload(ctx, name, file, "")
+}
+
+func (s *memStorage) GetObject(ctx context.Context, name string) ([]byte, error) {
+	return s.Download(ctx, name)
+}
+
+func (s *memStorage) Upload(_ context.Context, name string, file *multipart.FileHeader, _ string) (string, error) {
+	f, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return "", err
+	}
+	s.objects[name] = data
+	return name, nil
+}
+
+func (s *memStorage) Download(_ context.Context, name string) ([]byte, error) {
+	data, ok := s.objects[name]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return data, nil
+}
+
+func (s *memStorage) Delete(_ context.Context, name string) error {
+	delete(s.objects, name)
+	return nil
+}
+
+func (s *memStorage) GetPresignedURL(context.Context, string, time.Duration) (string, error) {
+	return "", nil
+}
+
+type memCacheIndex struct {
+	entries map[string]*CacheEntry // "<project>/<branch>/<key>" -> entry
+}
+
+func (idx *memCacheIndex) Get(_ context.Context, projectID, branch, key string) (*CacheEntry, error) {
+	return idx.entries[projectID+"/"+branch+"/"+key], nil
+}
+
+func (idx *memCacheIndex) FindLatestByPrefix(_ context.Context, projectID, branch, prefix string) (*CacheEntry, error) {
+	for k, e := range idx.entries {
+		if strings.HasPrefix(k, projectID+"/"+branch+"/"+prefix) {
+			return e, nil
+		}
+	}
+	return nil, nil
+}
+
+func (idx *memCacheIndex) Save(_ context.Context, projectID, branch string, entry *CacheEntry) error {
+	entry.ID = entry.Key
+	idx.entries[projectID+"/"+branch+"/"+entry.Key] = entry
+	return nil
+}
+
+func (idx *memCacheIndex) Touch(context.Context, string) error { return nil }
+
+type cacheExecCtx struct{ branch string }
+
+func (c cacheExecCtx) GetPipeline() *spec.Pipeline     { return &spec.Pipeline{} }
+func (c cacheExecCtx) GetWorkspaceRoot() string        { return "" }
+func (c cacheExecCtx) GetCacheScope() (string, string) { return "p1", c.branch }
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"go.sum", "go.sum", true},
+		{"go.sum", "sub/go.sum", false},
+		{"**/go.sum", "go.sum", true},
+		{"**/go.sum", "a/b/go.sum", true},
+		{"web/**/package-lock.json", "web/app/package-lock.json", true},
+		{"*.lock", "Cargo.lock", true},
+		{"*.lock", "sub/Cargo.lock", false},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	ws := t.TempDir()
+	writeFile(t, filepath.Join(ws, "go.sum"), "v1")
+
+	key, err := CacheKey(ws, "go-mod", []string{"go.sum"})
+	if err != nil {
+		t.Fatalf("CacheKey() error = %v", err)
+	}
+	if !strings.HasPrefix(key, "go-mod-") {
+		t.Errorf("CacheKey() = %q, want go-mod- prefix", key)
+	}
+
+	writeFile(t, filepath.Join(ws, "go.sum"), "v2")
+	changed, _ := CacheKey(ws, "go-mod", []string{"go.sum"})
+	if changed == key {
+		t.Errorf("CacheKey() did not change with lockfile content")
+	}
+
+	if _, err := CacheKey(ws, "npm", []string{"package-lock.json"}); err == nil {
+		t.Errorf("CacheKey() expected error when no file matches")
+	}
+}
+
+func TestCacheSaveRestore(t *testing.T) {
+	m := NewManager(log.Logger{SugaredLogger: zap.NewNop().Sugar()})
+	st := &memStorage{objects: map[string][]byte{}}
+	m.SetCache(st, &memCacheIndex{entries: map[string]*CacheEntry{}})
+
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "go.sum"), "v1")
+	writeFile(t, filepath.Join(src, ".gomodcache", "mod", "a.txt"), "cached")
+	params := []byte(`{"key":"go-mod","files":["go.sum"],"restoreKeys":["go-mod-"],"paths":[".gomodcache"]}`)
+
+	opts := &Options{Workspace: src, ExecutionContext: cacheExecCtx{branch: "main"}}
+	if _, err := m.Execute(context.Background(), "cache", "save", params, opts); err != nil {
+		t.Fatalf("save error = %v", err)
+	}
+	if len(st.objects) != 1 {
+		t.Fatalf("stored objects = %d, want 1", len(st.objects))
+	}
+
+	// Caches saved on main are not visible to other branches.
+	dst := t.TempDir()
+	writeFile(t, filepath.Join(dst, "go.sum"), "v2")
+	opts = &Options{Workspace: dst, ExecutionContext: cacheExecCtx{branch: "feature"}}
+	out, err := m.Execute(context.Background(), "cache", "restore", params, opts)
+	if err != nil {
+		t.Fatalf("restore error = %v", err)
+	}
+	if hit := cacheHit(t, out); hit != "false" {
+		t.Errorf("cache-hit on other branch = %q, want false", hit)
+	}
+
+	// Same branch, changed lockfile: partial restore through the restore key.
+	opts = &Options{Workspace: dst, ExecutionContext: cacheExecCtx{branch: "main"}}
+	out, err = m.Execute(context.Background(), "cache", "restore", params, opts)
+	if err != nil {
+		t.Fatalf("restore error = %v", err)
+	}
+	if hit := cacheHit(t, out); hit != "false" {
+		t.Errorf("cache-hit for restore key = %q, want false", hit)
+	}
+	data, err := os.ReadFile(filepath.Join(dst, ".gomodcache", "mod", "a.txt"))
+	if err != nil || string(data) != "cached" {
+		t.Fatalf("restored file = %q, %v; want cached", data, err)
+	}
+
+	// Exact key.
+	writeFile(t, filepath.Join(dst, "go.sum"), "v1")
+	out, err = m.Execute(context.Background(), "cache", "restore", params, opts)
+	if err != nil {
+		t.Fatalf("restore error = %v", err)
+	}
+	if hit := cacheHit(t, out); hit != "true" {
+		t.Errorf("cache-hit for exact key = %q, want true", hit)
+	}
+}
+
+func TestCacheSaveRestoreAbsoluteSymlinks(t *testing.T) {
+	m := NewManager(log.Logger{SugaredLogger: zap.NewNop().Sugar()})
+	m.SetCache(&memStorage{objects: map[string][]byte{}}, &memCacheIndex{entries: map[string]*CacheEntry{}})
+
+	src := t.TempDir()
+	outside := t.TempDir()
+	writeFile(t, filepath.Join(src, "package-lock.json"), "v1")
+	writeFile(t, filepath.Join(src, "node_modules", ".pnpm", "lodash@4.17.21", "index.js"), "lodash")
+	writeFile(t, filepath.Join(outside, "npmrc"), "token")
+	// pnpm-style absolute link into the cached tree, and one leaving the workspace.
+	if err := os.Symlink(filepath.Join(src, "node_modules", ".pnpm", "lodash@4.17.21"),
+		filepath.Join(src, "node_modules", "lodash")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "npmrc"), filepath.Join(src, "node_modules", ".npmrc")); err != nil {
+		t.Fatal(err)
+	}
+	params := []byte(`{"key":"node-modules","files":["package-lock.json"],"paths":["node_modules"]}`)
+
+	opts := &Options{Workspace: src, ExecutionContext: cacheExecCtx{branch: "main"}}
+	if _, err := m.Execute(context.Background(), "cache", "save", params, opts); err != nil {
+		t.Fatalf("save error = %v", err)
+	}
+
+	dst := t.TempDir()
+	writeFile(t, filepath.Join(dst, "package-lock.json"), "v1")
+	opts = &Options{Workspace: dst, ExecutionContext: cacheExecCtx{branch: "main"}}
+	out, err := m.Execute(context.Background(), "cache", "restore", params, opts)
+	if err != nil {
+		t.Fatalf("restore error = %v", err)
+	}
+	if hit := cacheHit(t, out); hit != "true" {
+		t.Fatalf("cache-hit = %q, want true", hit)
+	}
+	link, err := os.Readlink(filepath.Join(dst, "node_modules", "lodash"))
+	if err != nil || filepath.IsAbs(link) {
+		t.Fatalf("restored link = %q, %v; want a relative link", link, err)
+	}
+	data, err := os.ReadFile(filepath.Join(dst, "node_modules", "lodash", "index.js"))
+	if err != nil || string(data) != "lodash" {
+		t.Fatalf("read through restored link = %q, %v; want lodash", data, err)
+	}
+	if _, err := os.Lstat(filepath.Join(dst, "node_modules", ".npmrc")); !os.IsNotExist(err) {
+		t.Fatalf("link outside the workspace was restored: %v", err)
+	}
+}
+
+func TestWorkspacePathRejectsEscape(t *testing.T) {
+	if _, err := workspacePath("/ws", "../etc/passwd"); err == nil {
+		t.Errorf("workspacePath() expected error for escaping path")
+	}
+	if _, err := workspacePath("/ws", "a/../b"); err != nil {
+		t.Errorf("workspacePath() error = %v", err)
+	}
+}
+
+func TestExtractCacheArchiveRejectsUnsafeLinks(t *testing.T) {
+	outside := t.TempDir()
+	cases := map[string][]*tar.Header{
+		"absolute link": {
+			{Name: "abs", Typeflag: tar.TypeSymlink, Linkname: outside},
+		},
+		"escaping link": {
+			{Name: "a/up", Typeflag: tar.TypeSymlink, Linkname: "../../etc"},
+		},
+		"write through link": {
+			{Name: "dir", Typeflag: tar.TypeSymlink, Linkname: "real"},
+			{Name: "dir/file", Typeflag: tar.TypeReg, Mode: 0o644},
+		},
+	}
+	for name, headers := range cases {
+		t.Run(name, func(t *testing.T) {
+			ws := t.TempDir()
+			if err := extractCacheArchive(tarball(t, headers), ws); err == nil {
+				t.Fatalf("extractCacheArchive() expected error")
+			}
+		})
+	}
+
+	// A file replacing an existing symlink does not write to its target.
+	ws := t.TempDir()
+	victim := filepath.Join(outside, "victim")
+	writeFile(t, victim, "keep")
+	if err := os.Symlink(victim, filepath.Join(ws, "f")); err != nil {
+		t.Fatal(err)
+	}
+	if err := extractCacheArchive(tarball(t, []*tar.Header{{Name: "f", Typeflag: tar.TypeReg, Mode: 0o644}}), ws); err != nil {
+		t.Fatalf("extractCacheArchive() error = %v", err)
+	}
+	if data, _ := os.ReadFile(victim); string(data) != "keep" {
+		t.Errorf("symlink target was overwritten: %q", data)
+	}
+
+	// Relative links inside the workspace are kept.
+	ws = t.TempDir()
+	if err := extractCacheArchive(tarball(t, []*tar.Header{
+		{Name: "bin/tool", Typeflag: tar.TypeSymlink, Linkname: "../lib/tool"},
+	}), ws); err != nil {
+		t.Fatalf("extractCacheArchive() error = %v", err)
+	}
+	if link, err := os.Readlink(filepath.Join(ws, "bin", "tool")); err != nil || link != "../lib/tool" {
+		t.Errorf("link = %q, %v; want ../lib/tool", link, err)
+	}
+}
+
+// tarball builds a tar.gz of empty entries.
+func tarball(t *testing.T, headers []*tar.Header) io.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, h := range headers {
+		if err := tw.WriteHeader(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func cacheHit(t *testing.T, out []byte) string {
+	t.Helper()
+	var res struct {
+		Outputs map[string]string `json:"outputs"`
+	}
+	if err := sonic.Unmarshal(out, &res); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	return res.Outputs["cache-hit"]
+}
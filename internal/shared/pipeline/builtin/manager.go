@@ -21,6 +21,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/arcentrix/arcentra/internal/shared/storage"
 	"github.com/arcentrix/arcentra/pkg/log"
 )
 
@@ -29,11 +30,16 @@ import (
 // - shell: execute shell scripts and commands
 // - artifacts: upload and download artifacts
 // - reports: generate reports (dotenv, etc.)
+// - cache: restore and save dependency/build caches
 type Manager struct {
 	mu       sync.RWMutex
 	handlers map[string]map[string]ActionHandler // builtin:action -> handler
 	infos    map[string]*Info
 	logger   log.Logger
+
+	// cacheStorage and cacheIndex back the cache builtin; see SetCache.
+	cacheStorage storage.IStorage
+	cacheIndex   CacheIndex
 }
 
 // NewManager creates a new builtin function manager
@@ -69,6 +75,16 @@ func (m *Manager) registerBuiltins() {
 		"download": m.handleArtifactsDownload,
 	})
 
+	// Register cache builtin
+	m.registerBuiltin("cache", &Info{
+		Name:        "cache",
+		Description: "Restore and save dependency/build caches keyed by file hashes",
+		Actions:     []string{"restore", "save"},
+	}, map[string]ActionHandler{
+		"restore": m.handleCacheRestore,
+		"save":    m.handleCacheSave,
+	})
+
 	// Register reports builtin
 	m.registerBuiltin("reports", &Info{
 		Name:        "reports",
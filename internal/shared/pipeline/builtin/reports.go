@@ -15,15 +15,22 @@
 package builtin
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/bytedance/sonic"
 )
 
+// OutputFileEnv names the env var holding the dotenv file a step writes its
+// outputs to, e.g. `echo "version=1.2.3" >> "$ARCENTRA_OUTPUT"`.
+const OutputFileEnv = "ARCENTRA_OUTPUT"
+
 // ReportsDotenvArgs contains arguments for dotenv reports
 type ReportsDotenvArgs struct {
 	Dotenv []string `json:"dotenv"` // List of dotenv file paths
@@ -56,8 +63,9 @@ func (m *Manager) handleReportsDotenv(_ context.Context, params json.RawMessage,
 		return nil, fmt.Errorf("create reports directory: %w", err)
 	}
 
-	// 复制dotenv文件到reports目录
+	// 复制dotenv文件到reports目录，并解析为步骤输出
 	reportedFiles := make([]string, 0)
+	outputs := make(map[string]string)
 	for _, dotenvPath := range dotenvParams.Dotenv {
 		srcPath := filepath.Join(opts.Workspace, dotenvPath)
 		dstPath := filepath.Join(reportsDir, filepath.Base(dotenvPath))
@@ -66,6 +74,14 @@ func (m *Manager) handleReportsDotenv(_ context.Context, params json.RawMessage,
 			return nil, fmt.Errorf("copy dotenv file %s: %w", dotenvPath, err)
 		}
 
+		values, err := ParseDotenvFile(srcPath)
+		if err != nil {
+			return nil, fmt.Errorf("parse dotenv file %s: %w", dotenvPath, err)
+		}
+		for k, v := range values {
+			outputs[k] = v
+		}
+
 		reportedFiles = append(reportedFiles, dotenvPath)
 	}
 
@@ -73,7 +89,75 @@ func (m *Manager) handleReportsDotenv(_ context.Context, params json.RawMessage,
 		"success":        true,
 		"reported_files": reportedFiles,
 		"reports_dir":    reportsDir,
+		"outputs":        outputs,
 	}
 
 	return sonic.Marshal(result)
 }
+
+// ParseDotenv parses KEY=VALUE lines. Blank lines and # comments are ignored,
+// an optional "export " prefix is dropped and matching surrounding quotes are
+// stripped from the value. Later keys override earlier ones.
+func ParseDotenv(r io.Reader) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNo)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 {
+			if q := value[0]; (q == '"' || q == '\'') && value[len(value)-1] == q {
+				value = value[1 : len(value)-1]
+			}
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// ParseDotenvFile parses the dotenv file at path. A missing file yields an
+// empty map, since steps are not required to write outputs.
+func ParseDotenvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	return ParseDotenv(f)
+}
+
+// SelectOutputs applies a step's declared outputs to the values it wrote.
+// declared maps output name to source key (empty = same name); when declared
+// is empty every value is exported.
+func SelectOutputs(declared, values map[string]string) map[string]string {
+	if len(declared) == 0 {
+		return values
+	}
+	selected := make(map[string]string, len(declared))
+	for name, key := range declared {
+		if key == "" {
+			key = name
+		}
+		if v, ok := values[key]; ok {
+			selected[name] = v
+		}
+	}
+	return selected
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builtin
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	content := `
+# build metadata
+VERSION=1.2.3
+export IMAGE="registry/app:1.2.3"
+NOTE='hello world'
+EMPTY=
+VERSION=1.2.4
+`
+	got, err := ParseDotenv(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ParseDotenv() error = %v", err)
+	}
+	want := map[string]string{
+		"VERSION": "1.2.4",
+		"IMAGE":   "registry/app:1.2.3",
+		"NOTE":    "hello world",
+		"EMPTY":   "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseDotenv() = %v, want %v", got, want)
+	}
+
+	if _, err := ParseDotenv(strings.NewReader("not a pair")); err == nil {
+		t.Errorf("ParseDotenv() expected error for malformed line")
+	}
+}
+
+func TestParseDotenvFile_Missing(t *testing.T) {
+	got, err := ParseDotenvFile(filepath.Join(t.TempDir(), "outputs.env"))
+	if err != nil || len(got) != 0 {
+		t.Errorf("ParseDotenvFile() = %v, %v; want empty map", got, err)
+	}
+}
+
+func TestSelectOutputs(t *testing.T) {
+	values := map[string]string{"VERSION": "1.2.3", "DEBUG": "1"}
+
+	if got := SelectOutputs(nil, values); !reflect.DeepEqual(got, values) {
+		t.Errorf("SelectOutputs(nil) = %v, want all values", got)
+	}
+
+	got := SelectOutputs(map[string]string{"version": "VERSION", "DEBUG": "", "missing": ""}, values)
+	want := map[string]string{"version": "1.2.3", "DEBUG": "1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SelectOutputs() = %v, want %v", got, want)
+	}
+}
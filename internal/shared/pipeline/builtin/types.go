@@ -25,6 +25,8 @@ import (
 type ExecutionContext interface {
 	GetPipeline() *spec.Pipeline
 	GetWorkspaceRoot() string
+	// GetCacheScope returns the project and branch caches are scoped to.
+	GetCacheScope() (projectID, branch string)
 }
 
 // ActionHandler handles a specific action for builtin functions
@@ -27,6 +27,11 @@ import (
 // VariableInterpreter interprets and resolves ${{ ... }} variable expressions
 type VariableInterpreter struct {
 	env map[string]any
+
+	// jobOutputs and stepOutputs back ${{ jobs.<name>.outputs.<key> }} and
+	// ${{ steps.<name>.outputs.<key> }} references.
+	jobOutputs  map[string]map[string]string
+	stepOutputs map[string]map[string]string
 }
 
 // NewVariableInterpreter creates a new variable interpreter
@@ -46,12 +51,24 @@ func NewVariableInterpreter(env map[string]string) *VariableInterpreter {
 // Supports: ${{ variable }}, ${{ expression }}, ${{env.VAR}}, etc.
 var VariableRegex = regexp.MustCompile(`\${{([^}]+)}}`)
 
+// OutputRefRegex matches ${{ jobs.<name>.outputs.<key> }} and
+// ${{ steps.<name>.outputs.<key> }}. Names may contain '-' and '.', which
+// expr cannot address, so these references are resolved before evaluation.
+var OutputRefRegex = regexp.MustCompile(`\${{\s*(jobs|steps)\.([A-Za-z0-9_.-]+?)\.outputs\.([A-Za-z0-9_-]+)\s*}}`)
+
 // Resolve resolves all ${{ ... }} expressions in a string
 func (vi *VariableInterpreter) Resolve(text string) (string, error) {
 	if text == "" {
 		return text, nil
 	}
 
+	if vi.jobOutputs != nil || vi.stepOutputs != nil {
+		var err error
+		if text, err = vi.ResolveOutputs(text); err != nil {
+			return "", err
+		}
+	}
+
 	// Find all variable expressions
 	matches := VariableRegex.FindAllStringSubmatch(text, -1)
 	if len(matches) == 0 {
@@ -69,6 +86,12 @@ func (vi *VariableInterpreter) Resolve(text string) (string, error) {
 			continue
 		}
 
+		// Output references are only known at run time; keep them for the
+		// scheduler when their scope is not registered here.
+		if OutputRefRegex.MatchString(match[0]) {
+			continue
+		}
+
 		// Evaluate expression
 		value, err := vi.Evaluate(exprStr)
 		if err != nil {
@@ -170,6 +193,82 @@ func (vi *VariableInterpreter) SetVariables(vars map[string]any) {
 	maps.Copy(vi.env, vars)
 }
 
+// SetJobOutputs registers the outputs of upstream jobs keyed by job name.
+// Only registered jobs may be referenced; callers pass the jobs listed in
+// dependsOn.
+func (vi *VariableInterpreter) SetJobOutputs(outputs map[string]map[string]string) {
+	vi.jobOutputs = outputs
+	vi.SetVariable("jobs", outputsVariable(outputs))
+}
+
+// SetStepOutputs registers the outputs of the steps of the current job keyed
+// by step name.
+func (vi *VariableInterpreter) SetStepOutputs(outputs map[string]map[string]string) {
+	vi.stepOutputs = outputs
+	vi.SetVariable("steps", outputsVariable(outputs))
+}
+
+// ResolveOutputs substitutes jobs/steps output references in text and leaves
+// any other ${{ ... }} expression untouched, as well as references to a scope
+// with no registered outputs. Referencing an unknown job or step within a
+// registered scope is an error; a missing key resolves to "".
+func (vi *VariableInterpreter) ResolveOutputs(text string) (string, error) {
+	var resolveErr error
+	resolved := OutputRefRegex.ReplaceAllStringFunc(text, func(match string) string {
+		m := OutputRefRegex.FindStringSubmatch(match)
+		scope, name, key := m[1], m[2], m[3]
+
+		source := vi.jobOutputs
+		if scope == "steps" {
+			source = vi.stepOutputs
+		}
+		if source == nil {
+			return match
+		}
+		outputs, ok := source[name]
+		if !ok {
+			if resolveErr == nil {
+				resolveErr = fmt.Errorf("%s.%s is not in scope", scope, name)
+			}
+			return match
+		}
+		return outputs[key]
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	return resolved, nil
+}
+
+// EvaluateJobOutputs resolves a job's declared outputs against the outputs of
+// its steps.
+func EvaluateJobOutputs(declared map[string]string, stepOutputs map[string]map[string]string) (map[string]string, error) {
+	if len(declared) == 0 {
+		return nil, nil
+	}
+	vi := NewVariableInterpreter(nil)
+	vi.SetStepOutputs(stepOutputs)
+
+	outputs := make(map[string]string, len(declared))
+	for name, value := range declared {
+		resolved, err := vi.ResolveOutputs(value)
+		if err != nil {
+			return nil, fmt.Errorf("output '%s': %w", name, err)
+		}
+		outputs[name] = resolved
+	}
+	return outputs, nil
+}
+
+// outputsVariable shapes outputs as {name: {outputs: {key: value}}} for expr.
+func outputsVariable(outputs map[string]map[string]string) map[string]any {
+	vars := make(map[string]any, len(outputs))
+	for name, values := range outputs {
+		vars[name] = map[string]any{"outputs": values}
+	}
+	return vars
+}
+
 // GetVariable gets a variable value
 func (vi *VariableInterpreter) GetVariable(key string) (any, bool) {
 	if vi.env == nil {
@@ -0,0 +1,101 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVariableInterpreter_ResolveOutputs(t *testing.T) {
+	vi := NewVariableInterpreter(map[string]string{"branch": "main"})
+	vi.SetJobOutputs(map[string]map[string]string{
+		"build-1.23": {"version": "1.2.3"},
+		"lint":       {},
+	})
+
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{name: "dependency output", text: "v${{ jobs.build-1.23.outputs.version }}", want: "v1.2.3"},
+		{name: "missing key", text: "[${{ jobs.lint.outputs.report }}]", want: "[]"},
+		{name: "other expressions untouched", text: "${{ branch }}/${{ jobs.build-1.23.outputs.version }}", want: "${{ branch }}/1.2.3"},
+		{name: "unregistered scope untouched", text: "${{ steps.test.outputs.code }}", want: "${{ steps.test.outputs.code }}"},
+		{name: "job outside dependsOn", text: "${{ jobs.deploy.outputs.url }}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := vi.ResolveOutputs(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveOutputs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveOutputs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVariableInterpreter_ResolveWithOutputs(t *testing.T) {
+	vi := NewVariableInterpreter(map[string]string{"branch": "main"})
+	got, err := vi.Resolve("${{ branch }}-${{ jobs.build.outputs.version }}")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if want := "main-${{ jobs.build.outputs.version }}"; got != want {
+		t.Errorf("Resolve() without outputs = %q, want %q", got, want)
+	}
+
+	vi.SetJobOutputs(map[string]map[string]string{"build": {"version": "1.2.3"}})
+	got, err = vi.Resolve("${{ branch }}-${{ jobs.build.outputs.version }}")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if want := "main-1.2.3"; got != want {
+		t.Errorf("Resolve() = %q, want %q", got, want)
+	}
+
+	ok, err := vi.Evaluate(`jobs.build.outputs.version == "1.2.3"`)
+	if err != nil || ok != true {
+		t.Errorf("Evaluate() = %v, %v; want true", ok, err)
+	}
+}
+
+func TestEvaluateJobOutputs(t *testing.T) {
+	steps := map[string]map[string]string{
+		"build":   {"version": "1.2.3", "digest": "sha256:abc"},
+		"skipped": {},
+	}
+
+	got, err := EvaluateJobOutputs(map[string]string{
+		"version": "${{ steps.build.outputs.version }}",
+		"image":   "app@${{ steps.build.outputs.digest }}",
+		"empty":   "${{ steps.skipped.outputs.value }}",
+	}, steps)
+	if err != nil {
+		t.Fatalf("EvaluateJobOutputs() error = %v", err)
+	}
+	want := map[string]string{"version": "1.2.3", "image": "app@sha256:abc", "empty": ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EvaluateJobOutputs() = %v, want %v", got, want)
+	}
+
+	if _, err := EvaluateJobOutputs(map[string]string{"x": "${{ steps.nope.outputs.x }}"}, steps); err == nil {
+		t.Errorf("EvaluateJobOutputs() expected error for unknown step")
+	}
+}
@@ -23,7 +23,9 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 
+	"github.com/arcentrix/arcentra/internal/control/model"
 	"github.com/arcentrix/arcentra/internal/shared/executor"
 	"github.com/arcentrix/arcentra/internal/shared/pipeline/builtin"
 	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
@@ -40,6 +42,31 @@ type IPauseChecker interface {
 	WaitIfPaused(ctx context.Context) error
 }
 
+// IApprovalNotifier is told when a job starts waiting for approval so that
+// approval_pending notification rules can fire, without importing the
+// process package.
+type IApprovalNotifier interface {
+	NotifyApprovalPending(ctx context.Context, jobName, approvalID string)
+}
+
+// IJobScheduler queues agent jobs until an agent has capacity for them,
+// without importing the service package.
+type IJobScheduler interface {
+	Enqueue(ctx context.Context, entry *model.JobQueueEntry) error
+	// Cancel removes a job that was not dispatched yet.
+	Cancel(ctx context.Context, jobRunID string) error
+}
+
+// IConcurrencyLocker serialises jobs sharing a concurrency group across
+// control-plane replicas, without importing the service package.
+type IConcurrencyLocker interface {
+	// AcquireJob blocks until the job holds group. onSuperseded is called
+	// when a newer job of the group with the cancel-in-progress policy takes
+	// over; release frees the group. A job superseded while waiting gets an
+	// error wrapping context.Canceled.
+	AcquireJob(ctx context.Context, job, group, policy string, onSuperseded func()) (release func(), err error)
+}
+
 // ExecutionContext provides execution context for pipeline
 type ExecutionContext struct {
 	Pipeline       *spec.Pipeline
@@ -56,21 +83,56 @@ type ExecutionContext struct {
 	// persist JobRun / StepRun records and poll their status.
 	JobRunStore any // process.IJobRunStore (avoid circular import)
 
+	// JobScheduler queues agent jobs by priority and project fair share.
+	// When nil, agent jobs are sent to TaskQueue directly.
+	JobScheduler IJobScheduler
+
+	// ConcurrencyLocker enforces job-level concurrency groups. When nil,
+	// Job.concurrency is ignored.
+	ConcurrencyLocker IConcurrencyLocker
+
 	// RunCoordinator exposes pause/resume checking to TaskFramework.
 	RunCoordinator IPauseChecker
 
+	// ApprovalNotifier delivers approval_pending notifications. Set by
+	// RunCoordinator when the pipeline has notification rules.
+	ApprovalNotifier IApprovalNotifier
+
 	// PipelineRunID and PipelineIDRef are set by RunCoordinator so that
 	// TaskFramework can associate DB records with the current run.
 	PipelineRunID string
 	PipelineIDRef string
 
+	// ProjectID and Branch scope the cache builtin. Set by RunCoordinator.
+	ProjectID string
+	Branch    string
+
 	// ArtifactURIs stores upstream job artifact URIs keyed by "jobName/artifactName".
 	// Populated after each Job completes; downstream jobs read from here.
 	ArtifactURIs map[string]string
 
+	// jobOutputs stores the outputs of succeeded jobs keyed by job name.
+	// Jobs complete concurrently, so access goes through SetJobOutputs and
+	// JobOutputs.
+	jobOutputs   map[string]map[string]string
+	jobOutputsMu sync.RWMutex
+
+	// MatrixGroups holds the matrix expansion result keyed by original job
+	// name. Nil when the pipeline has no matrix jobs.
+	MatrixGroups map[string]*MatrixGroup
+
+	// SettledTasks holds jobs that are already in a terminal state before
+	// execution starts (re-runs reuse or skip jobs of the source run). They
+	// are not scheduled but still unlock their dependents.
+	SettledTasks map[string]TaskState
+
 	// LogPublisher publishes build log messages to the BUILD_LOGS topic.
 	// Set by the control-plane process for local step log visibility.
 	LogPublisher executor.LogPublisher
+
+	// Secrets holds the secret and masked variable values of the run. They
+	// are sent with every job so that agents mask them in build logs.
+	Secrets []string
 }
 
 // NewExecutionContext creates a new execution context
@@ -106,6 +168,32 @@ func (c *ExecutionContext) SetTaskQueue(queue nova.TaskQueue) {
 	c.TaskQueue = queue
 }
 
+// SetJobOutputs records the outputs of a succeeded job.
+func (c *ExecutionContext) SetJobOutputs(job string, outputs map[string]string) {
+	c.jobOutputsMu.Lock()
+	defer c.jobOutputsMu.Unlock()
+	if c.jobOutputs == nil {
+		c.jobOutputs = make(map[string]map[string]string)
+	}
+	c.jobOutputs[job] = outputs
+}
+
+// JobOutputs returns the outputs of the given jobs. Jobs that produced no
+// outputs (or were skipped) map to an empty set so references resolve to "".
+func (c *ExecutionContext) JobOutputs(jobs []string) map[string]map[string]string {
+	c.jobOutputsMu.RLock()
+	defer c.jobOutputsMu.RUnlock()
+	result := make(map[string]map[string]string, len(jobs))
+	for _, job := range jobs {
+		outputs := c.jobOutputs[job]
+		if outputs == nil {
+			outputs = map[string]string{}
+		}
+		result[job] = outputs
+	}
+	return result
+}
+
 // GetPipeline returns the pipeline (implements builtin.ExecutionContext interface)
 func (c *ExecutionContext) GetPipeline() *spec.Pipeline {
 	return c.Pipeline
@@ -116,6 +204,11 @@ func (c *ExecutionContext) GetWorkspaceRoot() string {
 	return c.WorkspaceRoot
 }
 
+// GetCacheScope returns the project and branch of the run (implements builtin.ExecutionContext interface)
+func (c *ExecutionContext) GetCacheScope() (string, string) {
+	return c.ProjectID, c.Branch
+}
+
 // JobWorkspace returns workspace path for job
 func (c *ExecutionContext) JobWorkspace(jobName string) string {
 	p := filepath.Join(c.WorkspaceRoot, c.Pipeline.Namespace, jobName)
@@ -301,7 +394,7 @@ func (c *ExecutionContext) MarshalParams(params map[string]any) (json.RawMessage
 }
 
 // SendNotification sends notification using notify plugin
-func (c *ExecutionContext) SendNotification(_ context.Context, item *spec.NotifyItem, success bool) error {
+func (c *ExecutionContext) SendNotification(ctx context.Context, item *spec.NotifyItem, success bool) error {
 	if item == nil || item.Plugin == "" {
 		return nil
 	}
@@ -328,6 +421,6 @@ func (c *ExecutionContext) SendNotification(_ context.Context, item *spec.Notify
 		action = "Send"
 	}
 
-	_, err = pluginClient.Execute(action, paramsJSON, nil)
+	_, err = plugin.ExecuteContext(ctx, pluginClient, action, paramsJSON, nil)
 	return err
 }
@@ -86,6 +86,7 @@ func (pe *Executor) Execute(ctx context.Context) error {
 
 	// Create reconciler
 	reconciler := NewReconciler(graph, tasks, taskFramework, pe.logger)
+	reconciler.Settle(pe.execCtx.SettledTasks)
 
 	// Channel to trigger reconcile
 	reconcileCh := make(chan struct{}, 1)
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
+	"github.com/arcentrix/arcentra/pkg/plugin"
 )
 
 // JobRunner runs a single job
@@ -111,7 +112,7 @@ func (r *JobRunner) Run(ctx context.Context) error {
 }
 
 // handleSource handles source checkout/clone
-func (r *JobRunner) handleSource(_ context.Context) error {
+func (r *JobRunner) handleSource(ctx context.Context) error {
 	if r.job.Source.Type == "" {
 		return fmt.Errorf("source type is required")
 	}
@@ -143,39 +144,44 @@ func (r *JobRunner) handleSource(_ context.Context) error {
 	}
 
 	// Call clone action
-	_, err = pluginClient.Execute("clone", paramsJSON, optsJSON)
+	_, err = plugin.ExecuteContext(ctx, pluginClient, "clone", paramsJSON, optsJSON)
 	return err
 }
 
-// handleApproval handles approval workflow
-func (r *JobRunner) handleApproval(_ context.Context) error {
+// handleApproval creates an approval request through the approval plugin and
+// blocks until it is approved, rejected or expired.
+func (r *JobRunner) handleApproval(ctx context.Context) error {
 	if r.job.Approval.Plugin == "" {
 		return fmt.Errorf("approval plugin is required")
 	}
 
-	pluginClient, err := r.ctx.PluginManager.GetPlugin(r.job.Approval.Plugin)
-	if err != nil {
-		return fmt.Errorf("approval plugin not found: %s: %w", r.job.Approval.Plugin, err)
+	am := NewApprovalManager(r.ctx.PluginManager, r.ctx.Logger)
+	if r.ctx.EventEmitter != nil {
+		am.SetEventEmitter(r.ctx.EventEmitter)
 	}
 
 	r.ctx.LogJob(r.job.Name, "waiting for approval...")
 
-	paramsJSON, err := r.ctx.MarshalParams(spec.StructAsMap(r.job.Approval.Params))
+	req, err := am.CreateApproval(ctx, r.job.Name, "", r.job.Approval.Plugin, spec.StructAsMap(r.job.Approval.Params))
 	if err != nil {
-		return fmt.Errorf("marshal approval params: %w", err)
+		return fmt.Errorf("create approval: %w", err)
 	}
-	_, err = pluginClient.Execute("approval.create", paramsJSON, nil)
-	if err != nil {
-		return err
+	if r.ctx.ApprovalNotifier != nil {
+		r.ctx.ApprovalNotifier.NotifyApprovalPending(ctx, r.job.Name, req.ID)
 	}
 
-	// Poll for approval status
-	// TODO: Implement polling logic
+	approved, err := am.WaitForApproval(ctx, req.ID)
+	if err != nil {
+		return fmt.Errorf("wait for approval: %w", err)
+	}
+	if !approved {
+		return fmt.Errorf("approval rejected for job %s", r.job.Name)
+	}
 	return nil
 }
 
 // handleTarget handles deployment target
-func (r *JobRunner) handleTarget(_ context.Context) error {
+func (r *JobRunner) handleTarget(ctx context.Context) error {
 	if r.job.Target.Type == "" {
 		return fmt.Errorf("target type is required")
 	}
@@ -196,6 +202,6 @@ func (r *JobRunner) handleTarget(_ context.Context) error {
 		return fmt.Errorf("marshal target opts: %w", err)
 	}
 
-	_, err = pluginClient.Execute("deploy", paramsJSON, optsJSON)
+	_, err = plugin.ExecuteContext(ctx, pluginClient, "deploy", paramsJSON, optsJSON)
 	return err
 }
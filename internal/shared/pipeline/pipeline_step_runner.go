@@ -18,6 +18,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/arcentrix/arcentra/internal/shared/pipeline/builtin"
@@ -28,9 +31,10 @@ import (
 
 // StepRunner runs a single step
 type StepRunner struct {
-	ctx  *ExecutionContext
-	job  *spec.Job
-	step *spec.Step
+	ctx     *ExecutionContext
+	job     *spec.Job
+	step    *spec.Step
+	outputs map[string]string
 }
 
 const defaultBuiltinActionExecute = "Execute"
@@ -40,6 +44,11 @@ func NewStepRunner(ctx *ExecutionContext, job *spec.Job, step *spec.Step) *StepR
 	return &StepRunner{ctx: ctx, job: job, step: step}
 }
 
+// Outputs returns the outputs exported by the last successful run.
+func (r *StepRunner) Outputs() map[string]string {
+	return r.outputs
+}
+
 // Run executes the step
 func (r *StepRunner) Run(ctx context.Context) error {
 	r.ctx.LogStep(r.job.Name, r.step.Name, "starting step")
@@ -200,6 +209,7 @@ func (r *StepRunner) executeBuiltin(ctx context.Context, builtinName string) err
 
 	// Resolve environment variables
 	env := r.ctx.ResolveStepEnv(r.job, r.step)
+	env[builtin.OutputFileEnv] = r.resetOutputFile()
 
 	// Resolve params with variable substitution
 	resolvedParams := r.ctx.ResolveVariables(spec.StructAsMap(r.step.Args))
@@ -278,12 +288,12 @@ func (r *StepRunner) executeBuiltin(ctx context.Context, builtinName string) err
 	}
 
 	// Execute builtin
-	_, err = r.ctx.BuiltinManager.Execute(ctx, builtinName, action, paramsJSON, opts)
+	result, err := r.ctx.BuiltinManager.Execute(ctx, builtinName, action, paramsJSON, opts)
 	if err != nil {
 		return fmt.Errorf("builtin execution failed: %w", err)
 	}
 
-	return nil
+	return r.collectOutputs(result)
 }
 
 // executePlugin executes a plugin
@@ -305,6 +315,7 @@ func (r *StepRunner) executePlugin(ctx context.Context) error {
 
 	// Resolve environment variables
 	env := r.ctx.ResolveStepEnv(r.job, r.step)
+	env[builtin.OutputFileEnv] = r.resetOutputFile()
 
 	// Resolve params with variable substitution
 	resolvedParams := r.ctx.ResolveVariables(spec.StructAsMap(r.step.Args))
@@ -328,10 +339,42 @@ func (r *StepRunner) executePlugin(ctx context.Context) error {
 	// Call plugin method
 	// Note: ctx is kept for future use (e.g., timeout control)
 	_ = ctx
-	_, err = pluginClient.Execute(action, paramsJSON, optsJSON)
+	result, err := plugin.ExecuteContext(ctx, pluginClient, action, paramsJSON, optsJSON)
 	if err != nil {
 		return fmt.Errorf("plugin execution failed: %w", err)
 	}
 
+	return r.collectOutputs(result)
+}
+
+// outputPath returns the dotenv file exposed to the step as $ARCENTRA_OUTPUT.
+func (r *StepRunner) outputPath() string {
+	return filepath.Join(r.ctx.StepWorkspace(r.job.Name, r.step.Name), ".arcentra", "outputs.env")
+}
+
+// resetOutputFile clears values left by a previous attempt and returns outputPath.
+func (r *StepRunner) resetOutputFile() string {
+	path := r.outputPath()
+	_ = os.MkdirAll(filepath.Dir(path), 0o755)
+	_ = os.Remove(path)
+	return path
+}
+
+// collectOutputs gathers the values the step wrote to $ARCENTRA_OUTPUT plus
+// any "outputs" object in its result, then applies the step's declared outputs.
+func (r *StepRunner) collectOutputs(result json.RawMessage) error {
+	values, err := builtin.ParseDotenvFile(r.outputPath())
+	if err != nil {
+		return fmt.Errorf("read step outputs: %w", err)
+	}
+	if len(result) > 0 {
+		var res struct {
+			Outputs map[string]string `json:"outputs"`
+		}
+		if sonic.Unmarshal(result, &res) == nil {
+			maps.Copy(values, res.Outputs)
+		}
+	}
+	r.outputs = builtin.SelectOutputs(r.step.Outputs, values)
 	return nil
 }
@@ -17,6 +17,7 @@ package pipeline
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/arcentrix/arcentra/pkg/dag"
@@ -34,7 +35,13 @@ type Reconciler struct {
 	logger        log.Logger
 	mu            sync.RWMutex
 	completed     map[string]TaskState
+	running       map[string]context.CancelFunc
 	onCompleted   func() // Callback when task completes to trigger next reconcile
+
+	// matrix bookkeeping: task name -> group, and groups cancelled by fail-fast
+	groups  map[string]*MatrixGroup
+	cells   map[string]int // task name -> position in its group's expansion order
+	aborted map[string]bool
 }
 
 // NewReconciler creates a new reconciler
@@ -44,13 +51,26 @@ func NewReconciler(
 	taskFramework *TaskFramework,
 	logger log.Logger,
 ) *Reconciler {
-	return &Reconciler{
+	r := &Reconciler{
 		dag:           graph,
 		tasks:         tasks,
 		taskFramework: taskFramework,
 		logger:        logger,
 		completed:     make(map[string]TaskState),
+		running:       make(map[string]context.CancelFunc),
+		groups:        make(map[string]*MatrixGroup),
+		cells:         make(map[string]int),
+		aborted:       make(map[string]bool),
+	}
+	if taskFramework != nil && taskFramework.execCtx != nil {
+		for _, group := range taskFramework.execCtx.MatrixGroups {
+			for i, name := range group.Jobs {
+				r.groups[name] = group
+				r.cells[name] = i
+			}
+		}
 	}
+	return r
 }
 
 // SetOnCompleted sets the callback function to be called when a task completes
@@ -60,6 +80,22 @@ func (r *Reconciler) SetOnCompleted(callback func()) {
 	r.onCompleted = callback
 }
 
+// Settle records tasks that reached a terminal state outside this execution
+// (e.g. results reused by a re-run). Settled tasks are never scheduled;
+// succeeded and skipped ones unlock their dependents as usual.
+func (r *Reconciler) Settle(states map[string]TaskState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name, state := range states {
+		task, ok := r.tasks[name]
+		if !ok {
+			continue
+		}
+		task.MarkCompleted(state, nil)
+		r.completed[name] = state
+	}
+}
+
 // Reconcile calculates which tasks can be scheduled and starts their execution
 // Returns true if there are more tasks to process, false if pipeline is complete
 func (r *Reconciler) Reconcile(ctx context.Context) (bool, error) {
@@ -90,11 +126,18 @@ func (r *Reconciler) Reconcile(ctx context.Context) (bool, error) {
 		return true, nil
 	}
 
-	// Start execution for schedulable tasks
-	for taskName, node := range schedulableNodes {
+	// Start execution for schedulable tasks in a stable order so that
+	// max-parallel picks matrix cells in expansion order.
+	names := make([]string, 0, len(schedulableNodes))
+	for taskName := range schedulableNodes {
+		names = append(names, taskName)
+	}
+	r.sortForSchedule(names)
+
+	for _, taskName := range names {
 		// Get task from tasks map using node name
 		// DAG returns defaultNode, not TaskNode, so we lookup by name
-		task, exists := r.tasks[node.NodeName()]
+		task, exists := r.tasks[schedulableNodes[taskName].NodeName()]
 		if !exists {
 			continue
 		}
@@ -103,14 +146,32 @@ func (r *Reconciler) Reconcile(ctx context.Context) (bool, error) {
 		if _, done := r.completed[taskName]; done {
 			continue
 		}
+		if _, busy := r.running[taskName]; busy {
+			continue
+		}
+
+		if group := r.groups[taskName]; group != nil {
+			// A failed cell of a fail-fast matrix cancels the cells that
+			// have not started yet.
+			if r.aborted[group.Name] {
+				task.MarkCompleted(TaskStateSkipped, fmt.Errorf("cancelled by fail-fast of matrix %s", group.Name))
+				r.completed[taskName] = TaskStateSkipped
+				continue
+			}
+			if group.MaxParallel > 0 && r.runningInGroup(group) >= group.MaxParallel {
+				continue
+			}
+		}
 
-		// Mark as processing (not completed yet)
-		// Start task execution asynchronously
-		// Capture loop variables for goroutine
+		// Start task execution asynchronously with its own cancel func so
+		// that fail-fast can stop sibling matrix cells.
+		taskCtx, cancel := context.WithCancel(ctx)
+		r.running[taskName] = cancel
 		currentTaskName := taskName
 		currentTask := task
 		safe.Go(func() {
-			if err := r.taskFramework.Execute(ctx, currentTask); err != nil {
+			defer cancel()
+			if err := r.taskFramework.Execute(taskCtx, currentTask); err != nil {
 				r.logger.Errorw("task execution failed", "task", currentTaskName, "error", err)
 			}
 			r.markCompleted(currentTaskName, currentTask.State)
@@ -120,10 +181,52 @@ func (r *Reconciler) Reconcile(ctx context.Context) (bool, error) {
 	return true, nil
 }
 
+// sortForSchedule sorts task names in scheduling order: matrix cells sort
+// under their group's name in expansion order, other tasks by their own name.
+func (r *Reconciler) sortForSchedule(names []string) {
+	sort.Slice(names, func(i, j int) bool {
+		ki, ci := r.scheduleKey(names[i])
+		kj, cj := r.scheduleKey(names[j])
+		if ki != kj {
+			return ki < kj
+		}
+		return ci < cj
+	})
+}
+
+func (r *Reconciler) scheduleKey(taskName string) (string, int) {
+	if group := r.groups[taskName]; group != nil {
+		return group.Name, r.cells[taskName]
+	}
+	return taskName, -1
+}
+
+// runningInGroup counts the cells of a matrix group that are currently running.
+// Caller must hold r.mu.
+func (r *Reconciler) runningInGroup(group *MatrixGroup) int {
+	n := 0
+	for _, name := range group.Jobs {
+		if _, ok := r.running[name]; ok {
+			n++
+		}
+	}
+	return n
+}
+
 // markCompleted records the terminal state of a task.
 func (r *Reconciler) markCompleted(taskName string, state TaskState) {
 	r.mu.Lock()
 	r.completed[taskName] = state
+	delete(r.running, taskName)
+	if group := r.groups[taskName]; group != nil && group.FailFast && state == TaskStateFailed && !r.aborted[group.Name] {
+		r.aborted[group.Name] = true
+		for _, name := range group.Jobs {
+			if cancel, ok := r.running[name]; ok {
+				cancel()
+			}
+		}
+		r.logger.Infow("matrix fail-fast triggered", "matrix", group.Name, "task", taskName)
+	}
 	callback := r.onCompleted
 	r.mu.Unlock()
 
@@ -0,0 +1,42 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/arcentrix/arcentra/pkg/log"
+)
+
+func TestReconcilerSchedulesMatrixCellsInExpansionOrder(t *testing.T) {
+	tf := &TaskFramework{execCtx: &ExecutionContext{
+		MatrixGroups: map[string]*MatrixGroup{
+			"test": {
+				Name:        "test",
+				Jobs:        []string{"test-1.22-linux", "test-1.23-linux", "test-1.23-darwin", "test-1.24-windows"},
+				MaxParallel: 1,
+			},
+		},
+	}}
+	r := NewReconciler(nil, nil, tf, log.Logger{})
+
+	names := []string{"test-integration", "lint", "test-1.23-darwin", "build", "test-1.24-windows", "test-1.22-linux", "test-1.23-linux"}
+	r.sortForSchedule(names)
+	want := []string{"build", "lint", "test-1.22-linux", "test-1.23-linux", "test-1.23-darwin", "test-1.24-windows", "test-integration"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("order = %v, want %v", names, want)
+	}
+}
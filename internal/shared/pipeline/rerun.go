@@ -0,0 +1,150 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
+)
+
+// RerunPlan describes how a re-run settles each job of the (matrix-expanded)
+// pipeline: jobs in Reuse take over the result of the source run's JobRun,
+// jobs in Skip are neither run nor reused, everything else is scheduled.
+type RerunPlan struct {
+	// Reuse maps job name to the source JobRun ID whose result is reused.
+	Reuse map[string]string
+	// Skip lists jobs outside the selected subgraph that did not succeed
+	// in the source run.
+	Skip []string
+	// Scheduled lists the jobs that run again, sorted by name.
+	Scheduled []string
+}
+
+// PlanRerun computes the re-run plan for jobs. succeeded maps the job names
+// that succeeded in the source run to their JobRun IDs. groups is the matrix
+// expansion result, so that fromJob may name a matrix job (all of its cells
+// are restarted).
+func PlanRerun(
+	jobs []*spec.Job,
+	groups map[string]*MatrixGroup,
+	succeeded map[string]string,
+	mode, fromJob string,
+) (*RerunPlan, error) {
+	names := make(map[string]*spec.Job, len(jobs))
+	for _, job := range jobs {
+		if job != nil {
+			names[job.Name] = job
+		}
+	}
+
+	selected := make(map[string]bool, len(names))
+	switch mode {
+	case model.RerunModeAll:
+		for name := range names {
+			selected[name] = true
+		}
+	case model.RerunModeFailedOnly:
+		for name := range names {
+			if _, ok := succeeded[name]; !ok {
+				selected[name] = true
+			}
+		}
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("source run has no failed jobs to re-run")
+		}
+	case model.RerunModeFromJob:
+		fromJob = strings.TrimSpace(fromJob)
+		if fromJob == "" {
+			return nil, fmt.Errorf("job name is required for mode %s", model.RerunModeFromJob)
+		}
+		var starts []string
+		if group := groups[fromJob]; group != nil {
+			starts = group.Jobs
+		} else if _, ok := names[fromJob]; ok {
+			starts = []string{fromJob}
+		} else {
+			return nil, fmt.Errorf("job %s not found in pipeline", fromJob)
+		}
+		for _, name := range downstreamJobs(jobs, starts) {
+			selected[name] = true
+		}
+	default:
+		return nil, fmt.Errorf("invalid re-run mode %q (valid: %s, %s, %s)",
+			mode, model.RerunModeAll, model.RerunModeFailedOnly, model.RerunModeFromJob)
+	}
+
+	plan := &RerunPlan{Reuse: make(map[string]string)}
+	for name := range names {
+		if selected[name] {
+			plan.Scheduled = append(plan.Scheduled, name)
+			continue
+		}
+		if jobRunID, ok := succeeded[name]; ok {
+			plan.Reuse[name] = jobRunID
+			continue
+		}
+		plan.Skip = append(plan.Skip, name)
+	}
+	sort.Strings(plan.Scheduled)
+	sort.Strings(plan.Skip)
+
+	// A scheduled job can only start once its dependencies succeed, so none
+	// of them may be skipped.
+	skipped := make(map[string]bool, len(plan.Skip))
+	for _, name := range plan.Skip {
+		skipped[name] = true
+	}
+	for _, name := range plan.Scheduled {
+		for _, dep := range names[name].DependsOn {
+			if skipped[dep] {
+				return nil, fmt.Errorf("job %s depends on %s which did not succeed in the source run", name, dep)
+			}
+		}
+	}
+	return plan, nil
+}
+
+// downstreamJobs returns starts and every job that transitively depends on
+// one of them.
+func downstreamJobs(jobs []*spec.Job, starts []string) []string {
+	dependents := make(map[string][]string)
+	for _, job := range jobs {
+		if job == nil {
+			continue
+		}
+		for _, dep := range job.DependsOn {
+			dependents[dep] = append(dependents[dep], job.Name)
+		}
+	}
+
+	seen := make(map[string]bool)
+	queue := append([]string(nil), starts...)
+	out := make([]string, 0, len(starts))
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		out = append(out, name)
+		queue = append(queue, dependents[name]...)
+	}
+	return out
+}
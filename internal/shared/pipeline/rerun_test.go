@@ -0,0 +1,107 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
+)
+
+// rerunJobs builds build -> test -> deploy plus an independent lint job.
+func rerunJobs() []*spec.Job {
+	return []*spec.Job{
+		{Name: "build"},
+		{Name: "lint"},
+		{Name: "test", DependsOn: []string{"build"}},
+		{Name: "deploy", DependsOn: []string{"test"}},
+	}
+}
+
+func TestPlanRerunFailedOnly(t *testing.T) {
+	succeeded := map[string]string{"build": "jr-build", "lint": "jr-lint"}
+	plan, err := PlanRerun(rerunJobs(), nil, succeeded, model.RerunModeFailedOnly, "")
+	if err != nil {
+		t.Fatalf("PlanRerun: %v", err)
+	}
+	if !slices.Equal(plan.Scheduled, []string{"deploy", "test"}) {
+		t.Errorf("scheduled = %v, want [deploy test]", plan.Scheduled)
+	}
+	if plan.Reuse["build"] != "jr-build" || plan.Reuse["lint"] != "jr-lint" || len(plan.Reuse) != 2 {
+		t.Errorf("reuse = %v", plan.Reuse)
+	}
+	if len(plan.Skip) != 0 {
+		t.Errorf("skip = %v, want none", plan.Skip)
+	}
+}
+
+func TestPlanRerunFailedOnlyNothingFailed(t *testing.T) {
+	succeeded := map[string]string{"build": "1", "lint": "2", "test": "3", "deploy": "4"}
+	if _, err := PlanRerun(rerunJobs(), nil, succeeded, model.RerunModeFailedOnly, ""); err == nil {
+		t.Fatal("expected error when no job failed")
+	}
+}
+
+func TestPlanRerunFromJob(t *testing.T) {
+	succeeded := map[string]string{"build": "jr-build", "test": "jr-test", "deploy": "jr-deploy"}
+	plan, err := PlanRerun(rerunJobs(), nil, succeeded, model.RerunModeFromJob, "test")
+	if err != nil {
+		t.Fatalf("PlanRerun: %v", err)
+	}
+	if !slices.Equal(plan.Scheduled, []string{"deploy", "test"}) {
+		t.Errorf("scheduled = %v, want [deploy test]", plan.Scheduled)
+	}
+	if len(plan.Reuse) != 1 || plan.Reuse["build"] != "jr-build" {
+		t.Errorf("reuse = %v, want only build", plan.Reuse)
+	}
+	if !slices.Equal(plan.Skip, []string{"lint"}) {
+		t.Errorf("skip = %v, want [lint]", plan.Skip)
+	}
+}
+
+func TestPlanRerunFromJobMatrixGroup(t *testing.T) {
+	jobs := []*spec.Job{
+		{Name: "test-linux"},
+		{Name: "test-darwin"},
+		{Name: "release", DependsOn: []string{"test-linux", "test-darwin"}},
+	}
+	groups := map[string]*MatrixGroup{"test": {Jobs: []string{"test-linux", "test-darwin"}}}
+	plan, err := PlanRerun(jobs, groups, nil, model.RerunModeFromJob, "test")
+	if err != nil {
+		t.Fatalf("PlanRerun: %v", err)
+	}
+	if !slices.Equal(plan.Scheduled, []string{"release", "test-darwin", "test-linux"}) {
+		t.Errorf("scheduled = %v", plan.Scheduled)
+	}
+}
+
+func TestPlanRerunFromJobSkippedDependency(t *testing.T) {
+	// build failed, so test cannot start without it.
+	succeeded := map[string]string{"lint": "jr-lint"}
+	if _, err := PlanRerun(rerunJobs(), nil, succeeded, model.RerunModeFromJob, "test"); err == nil {
+		t.Fatal("expected error when a dependency did not succeed")
+	}
+}
+
+func TestPlanRerunInvalid(t *testing.T) {
+	if _, err := PlanRerun(rerunJobs(), nil, nil, "bogus", ""); err == nil {
+		t.Error("expected error for invalid mode")
+	}
+	if _, err := PlanRerun(rerunJobs(), nil, nil, model.RerunModeFromJob, "missing"); err == nil {
+		t.Error("expected error for unknown job")
+	}
+}
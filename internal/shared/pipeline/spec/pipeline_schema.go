@@ -37,8 +37,33 @@ type (
 	Trigger         = pipelinev1.Trigger
 	AgentSelector   = pipelinev1.AgentSelector
 	LabelExpression = pipelinev1.LabelExpression
+	Matrix          = pipelinev1.Matrix
+	Service         = pipelinev1.Service
+	HealthCheck     = pipelinev1.HealthCheck
+
+	NotificationRule = pipelinev1.NotificationRule
+)
+
+// Run lifecycle events that a NotificationRule can subscribe to.
+const (
+	NotifyEventStarted         = "started"
+	NotifyEventSucceeded       = "succeeded"
+	NotifyEventFailed          = "failed"
+	NotifyEventFixed           = "fixed"
+	NotifyEventStillFailing    = "still_failing"
+	NotifyEventApprovalPending = "approval_pending"
 )
 
+// NotifyEvents lists all supported notification rule events.
+var NotifyEvents = []string{
+	NotifyEventStarted,
+	NotifyEventSucceeded,
+	NotifyEventFailed,
+	NotifyEventFixed,
+	NotifyEventStillFailing,
+	NotifyEventApprovalPending,
+}
+
 func StructAsMap(s *structpb.Struct) map[string]any {
 	if s == nil {
 		return map[string]any{}
@@ -0,0 +1,73 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// RewriteStrings applies fn to every string field of msg in place, including
+// repeated fields, map values and google.protobuf.Struct values.
+func RewriteStrings(msg proto.Message, fn func(string) string) {
+	if msg == nil {
+		return
+	}
+	rewriteStrings(msg.ProtoReflect(), fn)
+}
+
+func rewriteStrings(msg protoreflect.Message, fn func(string) string) {
+	// Collect scalar updates first: mutating a message or map while ranging
+	// over it is undefined behaviour in protoreflect.
+	var updates []func()
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			m := v.Map()
+			valueKind := fd.MapValue().Kind()
+			m.Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				switch valueKind {
+				case protoreflect.MessageKind:
+					rewriteStrings(mv.Message(), fn)
+				case protoreflect.StringKind:
+					updates = append(updates, func() {
+						m.Set(k, protoreflect.ValueOfString(fn(mv.String())))
+					})
+				}
+				return true
+			})
+		case fd.IsList():
+			l := v.List()
+			for i := 0; i < l.Len(); i++ {
+				switch fd.Kind() {
+				case protoreflect.MessageKind:
+					rewriteStrings(l.Get(i).Message(), fn)
+				case protoreflect.StringKind:
+					l.Set(i, protoreflect.ValueOfString(fn(l.Get(i).String())))
+				}
+			}
+		case fd.Kind() == protoreflect.MessageKind:
+			rewriteStrings(v.Message(), fn)
+		case fd.Kind() == protoreflect.StringKind:
+			updates = append(updates, func() {
+				msg.Set(fd, protoreflect.ValueOfString(fn(v.String())))
+			})
+		}
+		return true
+	})
+	for _, update := range updates {
+		update()
+	}
+}
@@ -80,6 +80,19 @@ func (t *Task) Get(key string) (any, bool) {
 	return v, ok
 }
 
+// MatrixGroup describes the jobs expanded from a single matrix job. The
+// reconciler uses it to enforce max-parallel and fail-fast across the cells.
+type MatrixGroup struct {
+	// Name is the name of the original (unexpanded) job.
+	Name string
+	// Jobs are the names of the expanded jobs, in expansion order.
+	Jobs []string
+	// MaxParallel caps the number of cells running at once (0 = unlimited).
+	MaxParallel int
+	// FailFast cancels the remaining cells once one of them fails.
+	FailFast bool
+}
+
 // TaskNode implements dag.NamedNode for DAG integration
 type TaskNode struct {
 	task *Task
@@ -17,10 +17,13 @@ package pipeline
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/interceptor"
 	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
 	"github.com/arcentrix/arcentra/pkg/id"
 	"github.com/arcentrix/arcentra/pkg/log"
@@ -29,6 +32,7 @@ import (
 	"github.com/arcentrix/arcentra/pkg/retry"
 	"github.com/arcentrix/arcentra/pkg/taskqueue"
 	"github.com/bytedance/sonic"
+	"google.golang.org/protobuf/proto"
 )
 
 // TaskFramework handles task execution lifecycle
@@ -69,6 +73,22 @@ func (tf *TaskFramework) Execute(ctx context.Context, task *Task) error {
 		return nil
 	}
 
+	// Concurrency: wait for the job's concurrency group. A newer job of the
+	// group with the cancel-in-progress policy cancels this one.
+	ctx, cancelJob := context.WithCancel(ctx)
+	defer cancelJob()
+	release, err := tf.acquireConcurrency(ctx, task, cancelJob)
+	if err != nil {
+		task.MarkCompleted(TaskStateFailed, err)
+		if errors.Is(err, context.Canceled) {
+			tf.emitJobEvent(plugin.EventTypeJobCancelled, task, map[string]any{
+				"status": "cancelled",
+			})
+		}
+		return fmt.Errorf("acquire concurrency group for task %s: %w", task.Name, err)
+	}
+	defer release()
+
 	// Create: create task execution context
 	if err := tf.create(ctx, task); err != nil {
 		task.MarkCompleted(TaskStateFailed, err)
@@ -91,7 +111,8 @@ func (tf *TaskFramework) Execute(ctx context.Context, task *Task) error {
 		return fmt.Errorf("queue task %s: %w", task.Name, err)
 	}
 
-	// Apply timeout if specified
+	// Apply timeout if specified. Agent jobs start the clock once they leave
+	// the queue, see waitForAgentJob.
 	waitCtx := ctx
 	var cancel context.CancelFunc
 	if task.Job.Timeout != "" {
@@ -100,8 +121,10 @@ func (tf *TaskFramework) Execute(ctx context.Context, task *Task) error {
 			task.MarkCompleted(TaskStateFailed, err)
 			return fmt.Errorf("invalid timeout format: %w", err)
 		}
-		waitCtx, cancel = context.WithTimeout(ctx, timeout)
-		defer cancel()
+		if !isAgentJob(task.Job) {
+			waitCtx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
 	}
 
 	// Wait: wait for task completion
@@ -121,8 +144,9 @@ func (tf *TaskFramework) Execute(ctx context.Context, task *Task) error {
 		return fmt.Errorf("wait task %s: %w", task.Name, err)
 	}
 
-	// Backflow artifact URIs so downstream jobs can reference them.
+	// Backflow artifact URIs and outputs so downstream jobs can reference them.
 	tf.backflowArtifactURIs(ctx, task)
+	tf.backflowJobOutputs(ctx, task)
 
 	task.MarkCompleted(TaskStateSucceeded, nil)
 	tf.emitJobEvent(plugin.EventTypeJobCompleted, task, map[string]any{
@@ -143,13 +167,25 @@ func (tf *TaskFramework) prepare(_ context.Context, task *Task) error {
 		}
 	}
 
+	// Substitute ${{ jobs.<name>.outputs.<key> }} for the jobs this one
+	// depends on.
+	depOutputs := tf.execCtx.JobOutputs(task.Job.DependsOn)
+	if err := tf.resolveJobOutputRefs(task, depOutputs); err != nil {
+		return fmt.Errorf("resolve job outputs: %w", err)
+	}
+
 	// Evaluate when condition
 	if task.Job.When != "" {
+		jobs := make(map[string]any, len(depOutputs))
+		for name, outputs := range depOutputs {
+			jobs[name] = map[string]any{"outputs": outputs}
+		}
 		jobContext := map[string]any{
 			"job": map[string]any{
 				"name":        task.Job.Name,
 				"description": task.Job.Description,
 			},
+			"jobs": jobs,
 		}
 		ok, err := tf.execCtx.EvalConditionWithContext(task.Job.When, jobContext)
 		if err != nil {
@@ -165,6 +201,31 @@ func (tf *TaskFramework) prepare(_ context.Context, task *Task) error {
 	return nil
 }
 
+// resolveJobOutputRefs replaces the task's job with a copy in which upstream
+// output references are substituted. Referencing a job outside dependsOn fails.
+func (tf *TaskFramework) resolveJobOutputRefs(task *Task, depOutputs map[string]map[string]string) error {
+	vi := interceptor.NewVariableInterpreter(nil)
+	vi.SetJobOutputs(depOutputs)
+
+	job := proto.Clone(task.Job).(*spec.Job)
+	var resolveErr error
+	spec.RewriteStrings(job, func(s string) string {
+		resolved, err := vi.ResolveOutputs(s)
+		if err != nil {
+			if resolveErr == nil {
+				resolveErr = err
+			}
+			return s
+		}
+		return resolved
+	})
+	if resolveErr != nil {
+		return resolveErr
+	}
+	task.Job = job
+	return nil
+}
+
 // create creates task execution context
 func (tf *TaskFramework) create(ctx context.Context, task *Task) error {
 	task.State = TaskStateCreated
@@ -214,9 +275,35 @@ func isAgentJob(job *spec.Job) bool {
 	return false
 }
 
+// acquireConcurrency evaluates the job's concurrency group and waits until
+// the job holds it. The returned release is a no-op when the job has no
+// group.
+func (tf *TaskFramework) acquireConcurrency(ctx context.Context, task *Task, onSuperseded func()) (func(), error) {
+	locker := tf.execCtx.ConcurrencyLocker
+	if locker == nil || strings.TrimSpace(task.Job.Concurrency) == "" {
+		return func() {}, nil
+	}
+	env := tf.execCtx.ResolveStepEnv(task.Job, nil)
+	env["branch"] = tf.execCtx.Branch
+	env["pipeline"] = tf.execCtx.PipelineIDRef
+	env["project"] = tf.execCtx.ProjectID
+	env["job"] = task.Job.Name
+	group, err := interceptor.NewVariableInterpreter(env).Resolve(task.Job.Concurrency)
+	if err != nil {
+		return nil, fmt.Errorf("evaluate concurrency group: %w", err)
+	}
+	group = strings.TrimSpace(group)
+	if group == "" {
+		return func() {}, nil
+	}
+	return locker.AcquireJob(ctx, task.Job.Name, group, task.Job.ConcurrencyPolicy, onSuperseded)
+}
+
 // queue queues a task (job) for execution. For Agent jobs the entire Job is
-// persisted as a JobRun/StepRun in the DB and enqueued to Kafka as a single
-// message. Local jobs skip this phase entirely.
+// persisted as a JobRun/StepRun in the DB and handed to the job scheduler,
+// which enqueues it to Kafka as a single message once an agent has capacity.
+// Without a scheduler it is enqueued right away. Local jobs skip this phase
+// entirely.
 func (tf *TaskFramework) queue(ctx context.Context, task *Task) error {
 	task.State = TaskStateQueued
 	tf.logger.Infow("task queued", "task", task.Name)
@@ -233,6 +320,11 @@ func (tf *TaskFramework) queue(ctx context.Context, task *Task) error {
 	jobRunID := id.GetUild()
 	task.Set("jobRunID", jobRunID)
 
+	priority := model.JobPriorityDefault
+	if p := int(task.Job.Priority); p >= model.JobPriorityHighest && p <= model.JobPriorityLowest {
+		priority = p
+	}
+
 	envJSON, _ := sonic.MarshalString(tf.execCtx.ResolveStepEnv(task.Job, nil))
 	jr := &model.JobRun{
 		JobRunID:      jobRunID,
@@ -240,7 +332,7 @@ func (tf *TaskFramework) queue(ctx context.Context, task *Task) error {
 		PipelineRunID: tf.execCtx.PipelineRunID,
 		JobName:       task.Job.Name,
 		Status:        model.JobRunStatusQueued,
-		Priority:      5,
+		Priority:      priority,
 		Env:           envJSON,
 		Workspace:     tf.execCtx.JobWorkspace(task.Job.Name),
 		Timeout:       task.Job.Timeout,
@@ -295,6 +387,7 @@ func (tf *TaskFramework) queue(ctx context.Context, task *Task) error {
 			ContinueOnError: step.ContinueOnError,
 			Timeout:         step.Timeout,
 			When:            step.When,
+			Outputs:         step.Outputs,
 		})
 	}
 
@@ -308,6 +401,34 @@ func (tf *TaskFramework) queue(ctx context.Context, task *Task) error {
 		Workspace:     tf.execCtx.JobWorkspace(task.Job.Name),
 		Timeout:       task.Job.Timeout,
 		ArtifactURIs:  tf.execCtx.ArtifactURIs,
+		Outputs:       task.Job.Outputs,
+		Secrets:       tf.execCtx.Secrets,
+
+		KeepAliveOnFailure: task.Job.KeepAliveOnFailure,
+	}
+	for _, svc := range task.Job.Services {
+		if svc == nil {
+			continue
+		}
+		sp := taskqueue.ServicePayload{
+			Name:    svc.Name,
+			Image:   svc.Image,
+			Env:     svc.Env,
+			Command: svc.Command,
+			Args:    svc.Args,
+		}
+		if hc := svc.HealthCheck; hc != nil && len(hc.Command) > 0 {
+			sp.HealthCheck = &taskqueue.HealthCheckPayload{
+				Command:  hc.Command,
+				Interval: hc.Interval,
+				Timeout:  hc.Timeout,
+				Retries:  hc.Retries,
+			}
+		}
+		payload.Services = append(payload.Services, sp)
+	}
+	if pipeline := tf.execCtx.Pipeline; pipeline != nil {
+		payload.Runtime = runtimePayload(pipeline.Runtime)
 	}
 	if task.Job.Source != nil {
 		payload.Source = &taskqueue.SourcePayload{
@@ -321,6 +442,23 @@ func (tf *TaskFramework) queue(ctx context.Context, task *Task) error {
 	if err != nil {
 		return fmt.Errorf("marshal job run payload: %w", err)
 	}
+	if scheduler := tf.execCtx.JobScheduler; scheduler != nil {
+		err = scheduler.Enqueue(ctx, &model.JobQueueEntry{
+			JobRunID:      jobRunID,
+			ProjectID:     tf.execCtx.ProjectID,
+			PipelineID:    tf.execCtx.PipelineIDRef,
+			PipelineRunID: tf.execCtx.PipelineRunID,
+			JobName:       task.Job.Name,
+			Priority:      priority,
+			Payload:       payloadBytes,
+			AgentSelector: jobAgentSelector(task.Job),
+		})
+		if err != nil {
+			return fmt.Errorf("schedule job run: %w", err)
+		}
+		tf.logger.Infow("job run queued for scheduling", "jobRunId", jobRunID, "job", task.Job.Name, "priority", priority)
+		return nil
+	}
 	if _, err = tf.execCtx.TaskQueue.Enqueue(&nova.Task{
 		Type:    taskqueue.TaskTypeJobRun,
 		Payload: payloadBytes,
@@ -332,6 +470,57 @@ func (tf *TaskFramework) queue(ctx context.Context, task *Task) error {
 	return nil
 }
 
+// jobAgentSelector merges the agent selectors of a job's steps into the
+// selector the scheduler sizes the job's slots with. Empty means any agent.
+func jobAgentSelector(job *spec.Job) string {
+	sel := &model.JobAgentSelector{}
+	for _, step := range job.GetSteps() {
+		as := step.GetAgentSelector()
+		for k, v := range as.GetMatchLabels() {
+			if sel.MatchLabels == nil {
+				sel.MatchLabels = make(map[string]string)
+			}
+			sel.MatchLabels[k] = v
+		}
+		for _, expr := range as.GetMatchExpressions() {
+			sel.MatchExpressions = append(sel.MatchExpressions, &model.JobLabelExpression{
+				Key:      expr.GetKey(),
+				Operator: expr.GetOperator(),
+				Values:   expr.GetValues(),
+			})
+		}
+	}
+	if sel.IsEmpty() {
+		return ""
+	}
+	data, err := sonic.MarshalString(sel)
+	if err != nil {
+		return ""
+	}
+	return data
+}
+
+// runtimePayload converts the pipeline runtime into its task queue payload.
+func runtimePayload(rt *spec.Runtime) *taskqueue.RuntimePayload {
+	if rt == nil || (rt.Type == "" && rt.Image == "") {
+		return nil
+	}
+	payload := &taskqueue.RuntimePayload{
+		Type:  rt.Type,
+		Image: rt.Image,
+		Env:   rt.Env,
+	}
+	if res := rt.Resources; res != nil {
+		payload.Resources = &taskqueue.ResourcesPayload{
+			CPURequest:    res.CpuRequest,
+			CPULimit:      res.CpuLimit,
+			MemoryRequest: res.MemoryRequest,
+			MemoryLimit:   res.MemoryLimit,
+		}
+	}
+	return payload
+}
+
 // wait waits for task completion. For Agent jobs it polls the DB for the
 // JobRun terminal status; for local jobs it executes steps sequentially.
 func (tf *TaskFramework) wait(ctx context.Context, task *Task) error {
@@ -344,7 +533,8 @@ func (tf *TaskFramework) wait(ctx context.Context, task *Task) error {
 }
 
 // waitForAgentJob polls the JobRun record in the DB until a terminal status
-// is reached or the context is cancelled.
+// is reached or the context is cancelled. The job timeout starts once an
+// agent picks the job up; a job cancelled while queued leaves the queue.
 func (tf *TaskFramework) waitForAgentJob(ctx context.Context, task *Task) error {
 	store := tf.getJobRunStore()
 	if store == nil {
@@ -357,23 +547,38 @@ func (tf *TaskFramework) waitForAgentJob(ctx context.Context, task *Task) error
 		return fmt.Errorf("jobRunID not set on task %s", task.Name)
 	}
 
+	var timeout time.Duration
+	if task.Job.Timeout != "" {
+		timeout, _ = time.ParseDuration(task.Job.Timeout)
+	}
+	var deadline <-chan time.Time
+
 	pollInterval := 2 * time.Second
 	ticker := time.NewTicker(pollInterval)
 	defer ticker.Stop()
 
 	for {
 		if err := tf.checkPause(ctx); err != nil {
+			tf.cancelQueuedJob(jrID)
 			return err
 		}
 		select {
 		case <-ctx.Done():
+			tf.cancelQueuedJob(jrID)
 			return ctx.Err()
+		case <-deadline:
+			return fmt.Errorf("agent job %s timed out after %s", task.Job.Name, timeout)
 		case <-ticker.C:
 			status, err := store.GetJobRunStatus(ctx, jrID)
 			if err != nil {
 				tf.logger.Warnw("poll job run status failed", "jobRunId", jrID, "error", err)
 				continue
 			}
+			if deadline == nil && timeout > 0 && model.IsJobRunRunning(status) {
+				timer := time.NewTimer(timeout)
+				defer timer.Stop()
+				deadline = timer.C
+			}
 			if model.IsJobRunTerminal(status) {
 				if status == model.JobRunStatusSuccess {
 					tf.sendSuccessNotification(ctx, task)
@@ -386,8 +591,26 @@ func (tf *TaskFramework) waitForAgentJob(ctx context.Context, task *Task) error
 	}
 }
 
+// cancelQueuedJob takes a job the run no longer waits for out of the
+// scheduler queue. Jobs already dispatched are cancelled on the agent.
+func (tf *TaskFramework) cancelQueuedJob(jobRunID string) {
+	if tf.execCtx.JobScheduler == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := tf.execCtx.JobScheduler.Cancel(ctx, jobRunID); err != nil {
+		tf.logger.Warnw("failed to remove job run from queue", "jobRunId", jobRunID, "error", err)
+	}
+}
+
 // waitForLocalJob executes steps sequentially in the control-plane process.
 func (tf *TaskFramework) waitForLocalJob(ctx context.Context, task *Task) error {
+	// Service containers need the Agent sandbox; the control plane has none.
+	if len(task.Job.Services) > 0 {
+		return fmt.Errorf("job %s declares services, which require steps with run_on_agent", task.Job.Name)
+	}
+
 	for i := range task.Job.Steps {
 		step := task.Job.Steps[i]
 		if step == nil {
@@ -408,10 +631,36 @@ func (tf *TaskFramework) waitForLocalJob(ctx context.Context, task *Task) error
 		}
 	}
 
+	outputs, err := interceptor.EvaluateJobOutputs(task.Job.Outputs, tf.stepOutputs(task))
+	if err != nil {
+		tf.sendFailureNotification(ctx, task)
+		return fmt.Errorf("resolve job outputs: %w", err)
+	}
+	task.Set("outputs", outputs)
+
 	tf.sendSuccessNotification(ctx, task)
 	return nil
 }
 
+// stepOutputs returns the outputs recorded by the task's local steps. Steps
+// that did not run map to an empty set.
+func (tf *TaskFramework) stepOutputs(task *Task) map[string]map[string]string {
+	recorded, _ := task.Get("stepOutputs")
+	byStep, _ := recorded.(map[string]map[string]string)
+	result := make(map[string]map[string]string, len(task.Job.Steps))
+	for _, step := range task.Job.Steps {
+		if step == nil {
+			continue
+		}
+		outputs := byStep[step.Name]
+		if outputs == nil {
+			outputs = map[string]string{}
+		}
+		result[step.Name] = outputs
+	}
+	return result
+}
+
 // checkPause blocks if the pipeline run is paused.
 func (tf *TaskFramework) checkPause(ctx context.Context) error {
 	if tf.execCtx.RunCoordinator != nil {
@@ -487,7 +736,17 @@ func (tf *TaskFramework) executeStepOnce(ctx context.Context, task *Task, step *
 
 	// Create step runner and execute
 	stepRunner := NewStepRunner(tf.execCtx, task.Job, step)
-	if err := stepRunner.Run(ctx); err != nil {
+	err := stepRunner.Run(ctx)
+	if outputs := stepRunner.Outputs(); outputs != nil {
+		recorded, _ := task.Get("stepOutputs")
+		byStep, _ := recorded.(map[string]map[string]string)
+		if byStep == nil {
+			byStep = make(map[string]map[string]string)
+			task.Set("stepOutputs", byStep)
+		}
+		byStep[step.Name] = outputs
+	}
+	if err != nil {
 		if step.ContinueOnError {
 			tf.logger.Warnw("step failed but continuing", "task", task.Name, "step", step.Name, "error", err)
 			tf.emitStepEvent(plugin.EventTypeStepFailed, task, step.Name, map[string]any{
@@ -512,7 +771,7 @@ func (tf *TaskFramework) executeStepOnce(ctx context.Context, task *Task, step *
 
 // handleSource handles source configuration by invoking the appropriate
 // source plugin (e.g. "git") to populate the job workspace.
-func (tf *TaskFramework) handleSource(_ context.Context, task *Task) error {
+func (tf *TaskFramework) handleSource(ctx context.Context, task *Task) error {
 	src := task.Job.Source
 	if src == nil {
 		return nil
@@ -545,7 +804,7 @@ func (tf *TaskFramework) handleSource(_ context.Context, task *Task) error {
 	}
 
 	action := "clone"
-	if _, execErr := p.Execute(action, paramsJSON, nil); execErr != nil {
+	if _, execErr := plugin.ExecuteContext(ctx, p, action, paramsJSON, nil); execErr != nil {
 		return fmt.Errorf("source %s clone: %w", sourceType, execErr)
 	}
 
@@ -609,6 +868,45 @@ func (tf *TaskFramework) backflowArtifactURIs(ctx context.Context, task *Task) {
 	}
 }
 
+// backflowJobOutputs records the completed job's outputs in execCtx so that
+// dependents can resolve ${{ jobs.<name>.outputs.<key> }}. Local jobs carry
+// them on the task; agent jobs report them into the JobRun record.
+func (tf *TaskFramework) backflowJobOutputs(ctx context.Context, task *Task) {
+	if v, ok := task.Get("outputs"); ok {
+		if outputs, ok := v.(map[string]string); ok {
+			tf.execCtx.SetJobOutputs(task.Job.Name, outputs)
+		}
+		return
+	}
+
+	jobRunIDVal, ok := task.Get("jobRunID")
+	if !ok {
+		return
+	}
+	jrID, ok := jobRunIDVal.(string)
+	if !ok || jrID == "" {
+		return
+	}
+	store := tf.getJobRunStore()
+	if store == nil {
+		return
+	}
+	jr, err := store.GetJobRun(ctx, jrID)
+	if err != nil || jr == nil {
+		tf.logger.Warnw("failed to read job run for outputs backflow", "jobRunId", jrID, "error", err)
+		return
+	}
+	if jr.Outputs == "" {
+		return
+	}
+	var outputs map[string]string
+	if err := sonic.UnmarshalString(jr.Outputs, &outputs); err != nil {
+		tf.logger.Warnw("failed to unmarshal job run outputs", "jobRunId", jrID, "error", err)
+		return
+	}
+	tf.execCtx.SetJobOutputs(task.Job.Name, outputs)
+}
+
 // handleApproval handles approval configuration by creating an approval request
 // via ApprovalManager, then blocking until the request is approved/rejected/expired.
 func (tf *TaskFramework) handleApproval(ctx context.Context, task *Task) error {
@@ -633,6 +931,9 @@ func (tf *TaskFramework) handleApproval(ctx context.Context, task *Task) error {
 	}
 
 	tf.logger.Infow("waiting for approval", "task", task.Name, "approvalId", req.ID)
+	if tf.execCtx.ApprovalNotifier != nil {
+		tf.execCtx.ApprovalNotifier.NotifyApprovalPending(ctx, task.Job.Name, req.ID)
+	}
 
 	approved, err := am.WaitForApproval(ctx, req.ID)
 	if err != nil {
@@ -16,47 +16,170 @@ package trigger
 
 import (
 	"path"
+	"slices"
 	"strings"
 
 	pipelinev1 "github.com/arcentrix/arcentra/api/pipeline/v1"
 	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
 )
 
+// skipCIMarkers are the commit message markers that suppress event triggers.
+var skipCIMarkers = []string{"[skip ci]", "[ci skip]", "[no ci]", "[skip arcentra]"}
+
+// Event is the SCM event an event trigger is evaluated against.
+type Event struct {
+	Type    string // push, tag, pull_request, merge_request, ...
+	Ref     string // e.g. "refs/heads/main" or "refs/tags/v1.0"
+	Message string // head commit message
+	// ChangedFiles lists the paths touched by the event; nil when unknown,
+	// in which case path filters do not reject the event.
+	ChangedFiles []string
+	SourceBranch string // PR/MR source branch
+	TargetBranch string // PR/MR target branch
+	Action       string // PR/MR action, e.g. "opened" or "synchronize"
+	Labels       []string
+}
+
 // MatchEvent checks whether a single event trigger definition matches the
 // incoming SCM event (eventType such as "push", ref such as "refs/heads/main").
 func MatchEvent(trigger *pipelinev1.Trigger, eventType string, ref string) bool {
+	return MatchTriggerEvent(trigger, Event{Type: eventType, Ref: ref})
+}
+
+// MatchTriggerEvent checks whether a single event trigger definition matches
+// ev. Every filter set in the trigger options must match.
+func MatchTriggerEvent(trigger *pipelinev1.Trigger, ev Event) bool {
 	if trigger == nil || trigger.GetType() != TriggerTypeEvent {
 		return false
 	}
 
 	opts := spec.StructAsMap(trigger.GetOptions())
 
-	if wantType, ok := opts[OptionEventType].(string); ok && wantType != "" {
-		if !strings.EqualFold(wantType, eventType) {
+	if wantTypes := optionStrings(opts, OptionEventType); len(wantTypes) > 0 {
+		if !slices.ContainsFunc(wantTypes, func(t string) bool { return strings.EqualFold(t, ev.Type) }) {
 			return false
 		}
 	}
 
 	if wantBranch, ok := opts[OptionBranch].(string); ok && wantBranch != "" {
-		if !matchBranch(wantBranch, ref) {
+		if !matchBranch(wantBranch, ev.Ref) {
 			return false
 		}
 	}
 
+	if tags := optionStrings(opts, OptionTags); len(tags) > 0 {
+		tag, ok := strings.CutPrefix(ev.Ref, "refs/tags/")
+		if !ok || !matchAny(tags, tag) {
+			return false
+		}
+	}
+
+	if !matchChange(opts, ev) {
+		return false
+	}
+
+	if !matchPaths(optionStrings(opts, OptionPaths), optionStrings(opts, OptionPathsIgnore), ev.ChangedFiles) {
+		return false
+	}
+
+	if ignore, _ := opts[OptionIgnoreSkipCI].(bool); !ignore && HasSkipCIMarker(ev.Message) {
+		return false
+	}
+
 	return true
 }
 
 // MatchAnyEventTrigger returns true when any event trigger in the spec matches
 // the given SCM event.
 func MatchAnyEventTrigger(s *pipelinev1.Spec, eventType string, ref string) bool {
+	return MatchAnyTriggerEvent(s, Event{Type: eventType, Ref: ref})
+}
+
+// MatchAnyTriggerEvent returns true when any event trigger in the spec
+// matches ev.
+func MatchAnyTriggerEvent(s *pipelinev1.Spec, ev Event) bool {
+	for _, t := range ExtractTriggersByType(s, TriggerTypeEvent) {
+		if MatchTriggerEvent(t, ev) {
+			return true
+		}
+	}
+	return false
+}
+
+// UsesPathFilters reports whether any event trigger in the spec filters on
+// changed files, i.e. whether the files of an event must be known to match.
+func UsesPathFilters(s *pipelinev1.Spec) bool {
 	for _, t := range ExtractTriggersByType(s, TriggerTypeEvent) {
-		if MatchEvent(t, eventType, ref) {
+		opts := spec.StructAsMap(t.GetOptions())
+		if len(optionStrings(opts, OptionPaths)) > 0 || len(optionStrings(opts, OptionPathsIgnore)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// HasSkipCIMarker reports whether a commit message asks CI to skip it.
+func HasSkipCIMarker(message string) bool {
+	message = strings.ToLower(message)
+	for _, m := range skipCIMarkers {
+		if strings.Contains(message, m) {
 			return true
 		}
 	}
 	return false
 }
 
+// matchChange applies the PR/MR filters. Events that are not PR/MR events
+// carry no branches, action or labels and fail any filter that is set.
+func matchChange(opts map[string]any, ev Event) bool {
+	if patterns := optionStrings(opts, OptionTargetBranches); len(patterns) > 0 {
+		if ev.TargetBranch == "" || !matchAny(patterns, ev.TargetBranch) {
+			return false
+		}
+	}
+	if patterns := optionStrings(opts, OptionSourceBranches); len(patterns) > 0 {
+		if ev.SourceBranch == "" || !matchAny(patterns, ev.SourceBranch) {
+			return false
+		}
+	}
+	if actions := optionStrings(opts, OptionActions); len(actions) > 0 {
+		if !slices.ContainsFunc(actions, func(a string) bool { return ev.Action != "" && strings.EqualFold(a, ev.Action) }) {
+			return false
+		}
+	}
+	if labels := optionStrings(opts, OptionLabels); len(labels) > 0 {
+		found := slices.ContainsFunc(ev.Labels, func(l string) bool {
+			return slices.ContainsFunc(labels, func(want string) bool { return strings.EqualFold(want, l) })
+		})
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchPaths reports whether at least one changed file matches paths (any
+// file when paths is empty) without matching pathsIgnore. Unknown files
+// always match, so a missing file list never suppresses a run.
+func matchPaths(paths, pathsIgnore, files []string) bool {
+	if len(paths) == 0 && len(pathsIgnore) == 0 {
+		return true
+	}
+	if files == nil {
+		return true
+	}
+	for _, f := range files {
+		if len(paths) > 0 && !matchAnyPath(paths, f) {
+			continue
+		}
+		if matchAnyPath(pathsIgnore, f) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
 // matchBranch checks if ref matches the branch pattern. The pattern can be:
 //   - a literal branch name: "main" matches "refs/heads/main" or "main"
 //   - a glob pattern: "release/*" matches "refs/heads/release/1.0"
@@ -71,6 +194,76 @@ func matchBranch(pattern string, ref string) bool {
 	return matched
 }
 
+// matchAny reports whether name equals or glob-matches any of patterns.
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if strings.EqualFold(p, name) {
+			return true
+		}
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAnyPath reports whether file matches any of patterns. Patterns use
+// path.Match syntax plus "**" for any number of directories; a pattern
+// ending in "/" matches everything below that directory.
+func matchAnyPath(patterns []string, file string) bool {
+	file = strings.TrimPrefix(file, "/")
+	for _, p := range patterns {
+		p = strings.TrimPrefix(strings.TrimSpace(p), "/")
+		if strings.HasSuffix(p, "/") {
+			p += "**"
+		}
+		if matchPathSegments(strings.Split(p, "/"), strings.Split(file, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPathSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchPathSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// optionStrings reads an option given either as a single string or as a
+// list of strings. Empty entries are dropped.
+func optionStrings(opts map[string]any, key string) []string {
+	var out []string
+	switch v := opts[key].(type) {
+	case string:
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				out = append(out, strings.TrimSpace(s))
+			}
+		}
+	}
+	return out
+}
+
 // NormalizeBranch strips the "refs/heads/" prefix from a Git ref.
 func NormalizeBranch(ref string) string {
 	return strings.TrimPrefix(ref, "refs/heads/")
@@ -0,0 +1,100 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trigger
+
+import (
+	"slices"
+	"strings"
+
+	pipelinev1 "github.com/arcentrix/arcentra/api/pipeline/v1"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
+)
+
+// Upstream run results a pipeline trigger filters on.
+const (
+	UpstreamStatusSuccess   = "success"
+	UpstreamStatusFailed    = "failed"
+	UpstreamStatusCancelled = "cancelled"
+)
+
+// Completion is the finished upstream run a pipeline trigger is evaluated
+// against.
+type Completion struct {
+	ProjectID    string
+	ProjectName  string
+	PipelineID   string
+	PipelineName string
+	Branch       string
+	Status       string // one of the UpstreamStatus constants
+}
+
+// MatchPipelineTrigger checks whether a single pipeline trigger of a pipeline
+// in projectID matches the upstream completion c. The trigger must name the
+// upstream pipeline by ID or name; the upstream project is given by ID and
+// defaults to projectID, and the status filter defaults to success. Whether
+// the upstream project lets projectID listen to its runs is checked by the
+// caller.
+func MatchPipelineTrigger(trigger *pipelinev1.Trigger, projectID string, c Completion) bool {
+	if trigger == nil || trigger.GetType() != TriggerTypePipeline {
+		return false
+	}
+
+	opts := spec.StructAsMap(trigger.GetOptions())
+
+	upstream, _ := opts[OptionPipeline].(string)
+	if upstream = strings.TrimSpace(upstream); upstream == "" {
+		return false
+	}
+	if upstream != c.PipelineID && !strings.EqualFold(upstream, c.PipelineName) {
+		return false
+	}
+
+	if UpstreamProjectID(trigger, projectID) != c.ProjectID {
+		return false
+	}
+
+	if branches := optionStrings(opts, OptionBranch); len(branches) > 0 {
+		if !matchAny(branches, NormalizeBranch(c.Branch)) {
+			return false
+		}
+	}
+
+	statuses := optionStrings(opts, OptionStatus)
+	if len(statuses) == 0 {
+		statuses = []string{UpstreamStatusSuccess}
+	}
+	return slices.ContainsFunc(statuses, func(s string) bool { return strings.EqualFold(s, c.Status) })
+}
+
+// UpstreamProjectID returns the ID of the project whose runs a pipeline
+// trigger of a pipeline in projectID listens to.
+func UpstreamProjectID(trigger *pipelinev1.Trigger, projectID string) string {
+	project, _ := spec.StructAsMap(trigger.GetOptions())[OptionProject].(string)
+	if project = strings.TrimSpace(project); project != "" {
+		return project
+	}
+	return projectID
+}
+
+// MatchAnyPipelineTrigger returns true when any pipeline trigger in the spec
+// of a pipeline in projectID matches c.
+func MatchAnyPipelineTrigger(s *pipelinev1.Spec, projectID string, c Completion) bool {
+	for _, t := range ExtractTriggersByType(s, TriggerTypePipeline) {
+		if MatchPipelineTrigger(t, projectID, c) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,316 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	pipelinev1 "github.com/arcentrix/arcentra/api/pipeline/v1"
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
+	"github.com/arcentrix/arcentra/internal/shared/pipeline/spec"
+	"github.com/arcentrix/arcentra/pkg/id"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"github.com/arcentrix/arcentra/pkg/serde"
+	"github.com/bytedance/sonic"
+)
+
+// MaxChainDepth bounds how many pipeline triggers a chain of runs may cross,
+// so pipelines that trigger each other cannot loop forever.
+const MaxChainDepth = 10
+
+// Variables injected into a run started by a pipeline trigger, next to the
+// outputs of the upstream jobs.
+const (
+	VarUpstreamRunID      = "ARCENTRA_UPSTREAM_RUN_ID"
+	VarUpstreamPipelineID = "ARCENTRA_UPSTREAM_PIPELINE_ID"
+	VarUpstreamPipeline   = "ARCENTRA_UPSTREAM_PIPELINE"
+	VarUpstreamProject    = "ARCENTRA_UPSTREAM_PROJECT"
+	VarUpstreamBranch     = "ARCENTRA_UPSTREAM_BRANCH"
+	VarUpstreamCommitSha  = "ARCENTRA_UPSTREAM_COMMIT_SHA"
+	VarUpstreamStatus     = "ARCENTRA_UPSTREAM_STATUS"
+)
+
+// PipelineTriggerManager starts downstream pipelines when an upstream run
+// finishes. The triggers[].type=pipeline of each pipeline are recorded in the
+// database by RecordPipelineTriggers when its definition is saved or pushed
+// to the default branch, so completions are matched without loading every
+// definition and all replicas see the same triggers; the definition of a
+// matched pipeline is reloaded before its run is created.
+type PipelineTriggerManager struct {
+	engine       IPipelineEngine
+	pipelineRepo repo.IPipelineRepository
+	projectRepo  repo.IProjectRepository
+	jobRunRepo   repo.IJobRunRepository
+	loader       DefinitionLoader
+}
+
+// NewPipelineTriggerManager creates a manager for pipeline-to-pipeline triggers.
+func NewPipelineTriggerManager(
+	engine IPipelineEngine,
+	pipelineRepo repo.IPipelineRepository,
+	projectRepo repo.IProjectRepository,
+	jobRunRepo repo.IJobRunRepository,
+	loader DefinitionLoader,
+) *PipelineTriggerManager {
+	return &PipelineTriggerManager{
+		engine:       engine,
+		pipelineRepo: pipelineRepo,
+		projectRepo:  projectRepo,
+		jobRunRepo:   jobRunRepo,
+		loader:       loader,
+	}
+}
+
+// RecordPipelineTriggers records the pipeline triggers declared by the spec
+// of pipeline p, replacing the ones recorded before. A nil spec or a spec
+// without pipeline triggers clears them.
+func RecordPipelineTriggers(
+	ctx context.Context, pipelineRepo repo.IPipelineRepository, p *model.Pipeline, s *pipelinev1.Spec,
+) error {
+	var upstreams []*model.PipelineUpstream
+	for _, t := range ExtractTriggersByType(s, TriggerTypePipeline) {
+		options, err := sonic.MarshalString(spec.StructAsMap(t.GetOptions()))
+		if err != nil {
+			return fmt.Errorf("encode pipeline trigger options: %w", err)
+		}
+		upstreams = append(upstreams, &model.PipelineUpstream{
+			PipelineID:        p.PipelineID,
+			ProjectID:         p.ProjectID,
+			UpstreamProjectID: UpstreamProjectID(t, p.ProjectID),
+			Options:           options,
+		})
+	}
+	return pipelineRepo.ReplaceUpstreams(ctx, p.PipelineID, upstreams)
+}
+
+// recordedTrigger rebuilds the trigger of a recorded pipeline trigger.
+func recordedTrigger(u *model.PipelineUpstream) (*pipelinev1.Trigger, error) {
+	var options map[string]any
+	if err := sonic.UnmarshalString(u.Options, &options); err != nil {
+		return nil, err
+	}
+	return &pipelinev1.Trigger{Type: TriggerTypePipeline, Options: spec.MapAsStruct(options)}, nil
+}
+
+// OnRunFinished starts every downstream pipeline whose pipeline trigger
+// matches the finished run. status is the run's terminal pipeline status;
+// other statuses are ignored.
+func (m *PipelineTriggerManager) OnRunFinished(ctx context.Context, run *model.PipelineRun, status int) {
+	if run == nil {
+		return
+	}
+	upstreamStatus := completionStatus(status)
+	if upstreamStatus == "" {
+		return
+	}
+
+	pipeline, err := m.pipelineRepo.Get(ctx, run.PipelineID)
+	if err != nil || pipeline == nil {
+		log.Warnw("pipeline trigger: upstream pipeline unavailable", "runId", run.RunID, "error", err)
+		return
+	}
+	upstreams, err := m.pipelineRepo.ListUpstreamsByProject(ctx, pipeline.ProjectID)
+	if err != nil {
+		log.Warnw("pipeline trigger: list pipeline triggers failed", "projectId", pipeline.ProjectID, "error", err)
+		return
+	}
+	if len(upstreams) == 0 {
+		return
+	}
+	project, err := m.projectRepo.Get(ctx, pipeline.ProjectID)
+	if err != nil || project == nil {
+		log.Warnw("pipeline trigger: upstream project unavailable", "runId", run.RunID, "error", err)
+		return
+	}
+	c := Completion{
+		PipelineID:   pipeline.PipelineID,
+		PipelineName: pipeline.Name,
+		ProjectID:    pipeline.ProjectID,
+		ProjectName:  project.Name,
+		Branch:       run.Branch,
+		Status:       upstreamStatus,
+	}
+
+	matched := matchUpstreams(project, upstreams, c)
+	if len(matched) == 0 {
+		return
+	}
+
+	if depth := m.chainDepth(ctx, run); depth >= MaxChainDepth {
+		log.Warnw("pipeline trigger: chain depth limit reached, not triggering downstream",
+			"runId", run.RunID, "depth", depth, "downstream", matched)
+		return
+	}
+
+	vars := m.upstreamVariables(ctx, run, c)
+	for _, pid := range matched {
+		m.fire(ctx, pid, run, c, vars)
+	}
+}
+
+// matchUpstreams returns the sorted IDs of the downstream pipelines whose
+// recorded pipeline trigger matches c. Pipelines of other projects only match
+// when the upstream project lists theirs in its downstream projects.
+func matchUpstreams(project *model.Project, upstreams []*model.PipelineUpstream, c Completion) []string {
+	var matched []string
+	for _, u := range upstreams {
+		if u.PipelineID == c.PipelineID || slices.Contains(matched, u.PipelineID) {
+			continue
+		}
+		if !project.AllowsDownstream(u.ProjectID) {
+			log.Debugw("pipeline trigger: downstream project not allowed by upstream project",
+				"upstreamProjectId", project.ProjectID, "projectId", u.ProjectID, "pipelineId", u.PipelineID)
+			continue
+		}
+		t, err := recordedTrigger(u)
+		if err != nil {
+			log.Warnw("pipeline trigger: decode recorded trigger failed", "pipelineId", u.PipelineID, "error", err)
+			continue
+		}
+		if MatchPipelineTrigger(t, u.ProjectID, c) {
+			matched = append(matched, u.PipelineID)
+		}
+	}
+	slices.Sort(matched)
+	return matched
+}
+
+// fire creates and submits a run of the downstream pipeline for the upstream
+// run. The run is keyed by the upstream run so a completion never starts the
+// same downstream pipeline twice.
+func (m *PipelineTriggerManager) fire(
+	ctx context.Context, pipelineID string, upstream *model.PipelineRun, c Completion, vars map[string]string,
+) {
+	pipeline, err := m.pipelineRepo.Get(ctx, pipelineID)
+	if err != nil || pipeline == nil || pipeline.IsEnabled != 1 {
+		log.Warnw("pipeline trigger: pipeline unavailable", "pipelineId", pipelineID, "error", err)
+		return
+	}
+
+	requestID := fmt.Sprintf("pipeline:%s", upstream.RunID)
+	if existing, _ := m.pipelineRepo.GetRunByRequestID(ctx, pipelineID, requestID); existing != nil {
+		return
+	}
+
+	project, err := m.projectRepo.Get(ctx, pipeline.ProjectID)
+	if err != nil || project == nil {
+		log.Warnw("pipeline trigger: project unavailable", "pipelineId", pipelineID, "error", err)
+		return
+	}
+
+	content, headSha, err := m.loader(ctx, pipeline, project)
+	if err != nil {
+		log.Warnw("pipeline trigger: load definition failed", "pipelineId", pipelineID, "error", err)
+		return
+	}
+
+	parsedSpec, err := spec.ParseContentToProto(content, pipelinev1.SpecFormat_SPEC_FORMAT_UNSPECIFIED)
+	if err != nil {
+		log.Warnw("pipeline trigger: parse spec failed", "pipelineId", pipelineID, "error", err)
+		return
+	}
+	if err := RecordPipelineTriggers(ctx, m.pipelineRepo, pipeline, parsedSpec); err != nil {
+		log.Warnw("pipeline trigger: record pipeline triggers failed", "pipelineId", pipelineID, "error", err)
+	}
+	if !MatchAnyPipelineTrigger(parsedSpec, pipeline.ProjectID, c) {
+		return
+	}
+
+	run := &model.PipelineRun{
+		RunID:               id.GetUild(),
+		PipelineID:          pipeline.PipelineID,
+		RequestID:           requestID,
+		PipelineName:        pipeline.Name,
+		Branch:              pipeline.DefaultBranch,
+		DefinitionCommitSha: headSha,
+		DefinitionPath:      pipeline.PipelineFilePath,
+		Status:              model.PipelineStatusPending,
+		TriggerType:         int(pipelinev1.TriggerType_TRIGGER_TYPE_PIPELINE),
+		TriggeredBy:         "pipeline",
+		Env:                 serde.MarshalStringMap(vars),
+		UpstreamRunID:       upstream.RunID,
+	}
+	if err := m.pipelineRepo.CreateRun(ctx, run); err != nil {
+		log.Warnw("pipeline trigger: create run failed", "pipelineId", pipelineID, "error", err)
+		return
+	}
+
+	if err := m.engine.Submit(run, parsedSpec); err != nil {
+		log.Warnw("pipeline trigger: process submit failed", "pipelineId", pipelineID, "runId", run.RunID, "error", err)
+		return
+	}
+
+	log.Infow("pipeline trigger fired",
+		"pipelineId", pipelineID, "runId", run.RunID, "upstreamRunId", upstream.RunID)
+}
+
+// upstreamVariables returns the variables of a downstream run: the outputs of
+// every upstream job, merged in job name order, plus the ARCENTRA_UPSTREAM_*
+// variables describing the upstream run, which win on conflicts.
+func (m *PipelineTriggerManager) upstreamVariables(
+	ctx context.Context, run *model.PipelineRun, c Completion,
+) map[string]string {
+	vars := make(map[string]string)
+	if m.jobRunRepo != nil {
+		jobs, err := m.jobRunRepo.ListByPipelineRunID(ctx, run.RunID)
+		if err != nil {
+			log.Warnw("pipeline trigger: list upstream job runs failed", "runId", run.RunID, "error", err)
+		}
+		slices.SortFunc(jobs, func(a, b *model.JobRun) int { return strings.Compare(a.JobName, b.JobName) })
+		for _, job := range jobs {
+			maps.Copy(vars, serde.UnmarshalStringMap(job.Outputs))
+		}
+	}
+	vars[VarUpstreamRunID] = run.RunID
+	vars[VarUpstreamPipelineID] = c.PipelineID
+	vars[VarUpstreamPipeline] = c.PipelineName
+	vars[VarUpstreamProject] = c.ProjectName
+	vars[VarUpstreamBranch] = run.Branch
+	vars[VarUpstreamCommitSha] = run.CommitSha
+	vars[VarUpstreamStatus] = c.Status
+	return vars
+}
+
+// chainDepth counts the pipeline-triggered runs leading to run.
+func (m *PipelineTriggerManager) chainDepth(ctx context.Context, run *model.PipelineRun) int {
+	depth := 0
+	for upstreamID := run.UpstreamRunID; upstreamID != "" && depth < MaxChainDepth; depth++ {
+		upstream, err := m.pipelineRepo.GetRun(ctx, upstreamID)
+		if err != nil || upstream == nil {
+			return depth + 1
+		}
+		upstreamID = upstream.UpstreamRunID
+	}
+	return depth
+}
+
+// completionStatus maps a terminal pipeline status to the upstream status a
+// pipeline trigger filters on ("" for non-terminal statuses).
+func completionStatus(status int) string {
+	switch status {
+	case model.PipelineStatusSuccess:
+		return UpstreamStatusSuccess
+	case model.PipelineStatusFailed:
+		return UpstreamStatusFailed
+	case model.PipelineStatusCancelled:
+		return UpstreamStatusCancelled
+	}
+	return ""
+}
@@ -13,7 +13,7 @@
 // limitations under the License.
 
 // Package trigger provides DSL trigger evaluation for pipeline-level triggers
-// defined in pipeline YAML (triggers[].type = cron | event | manual).
+// defined in pipeline YAML (triggers[].type = cron | event | manual | pipeline).
 package trigger
 
 import (
@@ -28,6 +28,8 @@ const (
 	TriggerTypeCron = "cron"
 	// TriggerTypeEvent represents an SCM webhook event trigger.
 	TriggerTypeEvent = "event"
+	// TriggerTypePipeline represents the completion of an upstream pipeline.
+	TriggerTypePipeline = "pipeline"
 
 	// OptionExpression is the key in Trigger.Options for cron expressions.
 	OptionExpression = "expression"
@@ -35,6 +37,36 @@ const (
 	OptionEventType = "event_type"
 	// OptionBranch is the key in Trigger.Options for branch filtering.
 	OptionBranch = "branch"
+	// OptionTags is the key in Trigger.Options for tag name patterns.
+	OptionTags = "tags"
+	// OptionPaths is the key in Trigger.Options for changed file patterns.
+	OptionPaths = "paths"
+	// OptionPathsIgnore is the key in Trigger.Options for changed file
+	// patterns that alone never fire the trigger.
+	OptionPathsIgnore = "paths_ignore"
+	// OptionTargetBranches is the key in Trigger.Options for PR/MR target
+	// branch patterns.
+	OptionTargetBranches = "target_branches"
+	// OptionSourceBranches is the key in Trigger.Options for PR/MR source
+	// branch patterns.
+	OptionSourceBranches = "source_branches"
+	// OptionActions is the key in Trigger.Options for PR/MR actions
+	// (opened, synchronize, labeled, ...).
+	OptionActions = "actions"
+	// OptionLabels is the key in Trigger.Options for PR/MR labels.
+	OptionLabels = "labels"
+	// OptionIgnoreSkipCI is the key in Trigger.Options that disables
+	// skip-ci commit markers.
+	OptionIgnoreSkipCI = "ignore_skip_ci"
+	// OptionPipeline is the key in Trigger.Options for the upstream pipeline
+	// name or ID.
+	OptionPipeline = "pipeline"
+	// OptionProject is the key in Trigger.Options for the upstream project
+	// ID; the triggered pipeline's own project when unset.
+	OptionProject = "project"
+	// OptionStatus is the key in Trigger.Options for the upstream run
+	// results that fire the trigger (success, failed, cancelled).
+	OptionStatus = "status"
 )
 
 // ExtractTriggersByType returns all pipeline-level triggers of the given type.
@@ -15,10 +15,16 @@
 package trigger
 
 import (
+	"context"
+	"maps"
+	"slices"
 	"testing"
 
 	pipelinev1 "github.com/arcentrix/arcentra/api/pipeline/v1"
+	"github.com/arcentrix/arcentra/internal/control/model"
+	"github.com/arcentrix/arcentra/internal/control/repo"
 	"google.golang.org/protobuf/types/known/structpb"
+	"gorm.io/datatypes"
 )
 
 func makeTrigger(typ string, opts map[string]any) *pipelinev1.Trigger {
@@ -201,3 +207,349 @@ func TestMatchAnyEventTrigger(t *testing.T) {
 		t.Error("expected no match for tag event")
 	}
 }
+
+func TestMatchTriggerEvent(t *testing.T) {
+	tests := []struct {
+		name string
+		opts map[string]any
+		ev   Event
+		want bool
+	}{
+		{
+			name: "paths match",
+			opts: map[string]any{"event_type": "push", "paths": []any{"services/api/**"}},
+			ev:   Event{Type: "push", Ref: "refs/heads/main", ChangedFiles: []string{"services/api/main.go"}},
+			want: true,
+		},
+		{
+			name: "paths no match",
+			opts: map[string]any{"paths": []any{"services/api/**"}},
+			ev:   Event{Type: "push", ChangedFiles: []string{"services/web/index.ts"}},
+			want: false,
+		},
+		{
+			name: "directory pattern",
+			opts: map[string]any{"paths": "services/api/"},
+			ev:   Event{Type: "push", ChangedFiles: []string{"services/api/v1/handler.go"}},
+			want: true,
+		},
+		{
+			name: "unknown files never suppress",
+			opts: map[string]any{"paths": []any{"services/api/**"}},
+			ev:   Event{Type: "push"},
+			want: true,
+		},
+		{
+			name: "known empty file list",
+			opts: map[string]any{"paths": []any{"services/api/**"}},
+			ev:   Event{Type: "push", ChangedFiles: []string{}},
+			want: false,
+		},
+		{
+			name: "paths_ignore all ignored",
+			opts: map[string]any{"paths_ignore": []any{"**/*.md", "docs/**"}},
+			ev:   Event{Type: "push", ChangedFiles: []string{"README.md", "docs/guide/setup.txt"}},
+			want: false,
+		},
+		{
+			name: "paths_ignore one relevant",
+			opts: map[string]any{"paths_ignore": []any{"**/*.md"}},
+			ev:   Event{Type: "push", ChangedFiles: []string{"README.md", "main.go"}},
+			want: true,
+		},
+		{
+			name: "paths with paths_ignore",
+			opts: map[string]any{"paths": []any{"services/**"}, "paths_ignore": []any{"**/*_test.go"}},
+			ev:   Event{Type: "push", ChangedFiles: []string{"services/api/a_test.go", "README.md"}},
+			want: false,
+		},
+		{
+			name: "tag pattern",
+			opts: map[string]any{"event_type": "tag", "tags": []any{"v*"}},
+			ev:   Event{Type: "tag", Ref: "refs/tags/v1.2.0"},
+			want: true,
+		},
+		{
+			name: "tag pattern mismatch",
+			opts: map[string]any{"tags": []any{"v*"}},
+			ev:   Event{Type: "tag", Ref: "refs/tags/nightly"},
+			want: false,
+		},
+		{
+			name: "tag pattern on branch push",
+			opts: map[string]any{"tags": []any{"*"}},
+			ev:   Event{Type: "push", Ref: "refs/heads/main"},
+			want: false,
+		},
+		{
+			name: "pull request filters",
+			opts: map[string]any{
+				"event_type":      []any{"pull_request", "merge_request"},
+				"target_branches": []any{"main", "release/*"},
+				"source_branches": "feature/*",
+				"actions":         []any{"opened", "synchronize"},
+			},
+			ev: Event{
+				Type:         "merge_request",
+				SourceBranch: "feature/login",
+				TargetBranch: "release/2.0",
+				Action:       "synchronize",
+			},
+			want: true,
+		},
+		{
+			name: "pull request target mismatch",
+			opts: map[string]any{"target_branches": []any{"main"}},
+			ev:   Event{Type: "pull_request", SourceBranch: "feature/x", TargetBranch: "develop"},
+			want: false,
+		},
+		{
+			name: "pull request action mismatch",
+			opts: map[string]any{"actions": []any{"opened"}},
+			ev:   Event{Type: "pull_request", Action: "closed"},
+			want: false,
+		},
+		{
+			name: "labeled with label filter",
+			opts: map[string]any{"actions": []any{"labeled"}, "labels": []any{"run-e2e"}},
+			ev:   Event{Type: "pull_request", Action: "labeled", Labels: []string{"bug", "Run-E2E"}},
+			want: true,
+		},
+		{
+			name: "label filter mismatch",
+			opts: map[string]any{"labels": []any{"run-e2e"}},
+			ev:   Event{Type: "pull_request", Labels: []string{"bug"}},
+			want: false,
+		},
+		{
+			name: "pr filter on push",
+			opts: map[string]any{"target_branches": []any{"main"}},
+			ev:   Event{Type: "push", Ref: "refs/heads/main"},
+			want: false,
+		},
+		{
+			name: "skip ci marker",
+			opts: map[string]any{"event_type": "push"},
+			ev:   Event{Type: "push", Message: "docs: typo [skip ci]"},
+			want: false,
+		},
+		{
+			name: "skip ci marker ignored",
+			opts: map[string]any{"event_type": "push", "ignore_skip_ci": true},
+			ev:   Event{Type: "push", Message: "docs: typo [CI SKIP]"},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MatchTriggerEvent(makeTrigger("event", tt.opts), tt.ev)
+			if got != tt.want {
+				t.Errorf("MatchTriggerEvent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasSkipCIMarker(t *testing.T) {
+	for _, msg := range []string{"fix [skip ci]", "[ci skip] wip", "chore [No CI]", "x [skip arcentra]"} {
+		if !HasSkipCIMarker(msg) {
+			t.Errorf("expected skip marker in %q", msg)
+		}
+	}
+	if HasSkipCIMarker("skip ci without brackets") {
+		t.Error("unexpected skip marker")
+	}
+}
+
+func TestMatchPipelineTrigger(t *testing.T) {
+	build := Completion{
+		ProjectID:    "p-platform",
+		ProjectName:  "platform",
+		PipelineID:   "pl-build",
+		PipelineName: "build",
+		Branch:       "main",
+		Status:       UpstreamStatusSuccess,
+	}
+	failed := build
+	failed.Status = UpstreamStatusFailed
+	release := build
+	release.Branch = "release/1.2"
+
+	tests := []struct {
+		name      string
+		opts      map[string]any
+		projectID string
+		c         Completion
+		want      bool
+	}{
+		{
+			name:      "same project by name",
+			opts:      map[string]any{"pipeline": "build"},
+			projectID: "p-platform",
+			c:         build,
+			want:      true,
+		},
+		{
+			name:      "by pipeline id",
+			opts:      map[string]any{"pipeline": "pl-build"},
+			projectID: "p-platform",
+			c:         build,
+			want:      true,
+		},
+		{
+			name:      "other project requires project option",
+			opts:      map[string]any{"pipeline": "build"},
+			projectID: "p-deploy",
+			c:         build,
+			want:      false,
+		},
+		{
+			name:      "other project by name does not match",
+			opts:      map[string]any{"pipeline": "build", "project": "platform"},
+			projectID: "p-deploy",
+			c:         build,
+			want:      false,
+		},
+		{
+			name:      "other project by id",
+			opts:      map[string]any{"pipeline": "build", "project": "p-platform"},
+			projectID: "p-deploy",
+			c:         build,
+			want:      true,
+		},
+		{
+			name:      "wrong pipeline",
+			opts:      map[string]any{"pipeline": "lint"},
+			projectID: "p-platform",
+			c:         build,
+			want:      false,
+		},
+		{
+			name:      "missing pipeline option",
+			opts:      map[string]any{"branch": "main"},
+			projectID: "p-platform",
+			c:         build,
+			want:      false,
+		},
+		{
+			name:      "failure ignored by default",
+			opts:      map[string]any{"pipeline": "build"},
+			projectID: "p-platform",
+			c:         failed,
+			want:      false,
+		},
+		{
+			name:      "status filter",
+			opts:      map[string]any{"pipeline": "build", "status": []any{"failed", "cancelled"}},
+			projectID: "p-platform",
+			c:         failed,
+			want:      true,
+		},
+		{
+			name:      "branch pattern",
+			opts:      map[string]any{"pipeline": "build", "branch": []any{"main", "release/*"}},
+			projectID: "p-platform",
+			c:         release,
+			want:      true,
+		},
+		{
+			name:      "branch mismatch",
+			opts:      map[string]any{"pipeline": "build", "branch": "main"},
+			projectID: "p-platform",
+			c:         release,
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MatchPipelineTrigger(makeTrigger("pipeline", tt.opts), tt.projectID, tt.c)
+			if got != tt.want {
+				t.Errorf("MatchPipelineTrigger() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchAnyPipelineTrigger(t *testing.T) {
+	s := &pipelinev1.Spec{Triggers: []*pipelinev1.Trigger{
+		makeTrigger("event", map[string]any{"event_type": "push"}),
+		makeTrigger("pipeline", map[string]any{"pipeline": "build"}),
+	}}
+	c := Completion{ProjectID: "p1", PipelineName: "build", Branch: "main", Status: UpstreamStatusSuccess}
+	if !MatchAnyPipelineTrigger(s, "p1", c) {
+		t.Error("expected pipeline trigger to match")
+	}
+	if MatchAnyPipelineTrigger(nil, "p1", c) {
+		t.Error("nil spec must not match")
+	}
+}
+
+// upstreamRepo records pipeline triggers in memory.
+type upstreamRepo struct {
+	repo.IPipelineRepository
+	upstreams map[string][]*model.PipelineUpstream
+}
+
+func (r *upstreamRepo) ReplaceUpstreams(_ context.Context, pipelineID string, upstreams []*model.PipelineUpstream) error {
+	r.upstreams[pipelineID] = upstreams
+	return nil
+}
+
+func (r *upstreamRepo) all() []*model.PipelineUpstream {
+	var all []*model.PipelineUpstream
+	for _, pid := range slices.Sorted(maps.Keys(r.upstreams)) {
+		all = append(all, r.upstreams[pid]...)
+	}
+	return all
+}
+
+func TestRecordPipelineTriggersAndMatch(t *testing.T) {
+	r := &upstreamRepo{upstreams: map[string][]*model.PipelineUpstream{}}
+	ctx := context.Background()
+	record := func(pipelineID, projectID string, opts map[string]any) {
+		t.Helper()
+		s := &pipelinev1.Spec{Triggers: []*pipelinev1.Trigger{
+			makeTrigger("event", map[string]any{"event_type": "push"}),
+			makeTrigger("pipeline", opts),
+		}}
+		p := &model.Pipeline{PipelineID: pipelineID, ProjectID: projectID}
+		if err := RecordPipelineTriggers(ctx, r, p, s); err != nil {
+			t.Fatalf("RecordPipelineTriggers(%s): %v", pipelineID, err)
+		}
+	}
+	record("pl-test", "p-platform", map[string]any{"pipeline": "build"})
+	record("pl-deploy", "p-deploy", map[string]any{"pipeline": "build", "project": "p-platform"})
+	record("pl-audit", "p-audit", map[string]any{"pipeline": "build", "project": "p-platform"})
+
+	if got := r.upstreams["pl-deploy"]; len(got) != 1 || got[0].UpstreamProjectID != "p-platform" || got[0].ProjectID != "p-deploy" {
+		t.Fatalf("recorded triggers of pl-deploy = %+v", got)
+	}
+	if got := r.upstreams["pl-test"]; len(got) != 1 || got[0].UpstreamProjectID != "p-platform" {
+		t.Fatalf("recorded triggers of pl-test = %+v", got)
+	}
+
+	c := Completion{ProjectID: "p-platform", PipelineID: "pl-build", PipelineName: "build", Branch: "main", Status: UpstreamStatusSuccess}
+	platform := &model.Project{
+		ProjectID: "p-platform",
+		Settings:  datatypes.JSON(`{"downstream_projects":["p-deploy"]}`),
+	}
+	if got, want := matchUpstreams(platform, r.all(), c), []string{"pl-deploy", "pl-test"}; !slices.Equal(got, want) {
+		t.Errorf("matchUpstreams() = %v, want %v", got, want)
+	}
+
+	// Without the allow-list only the project's own pipelines are triggered.
+	closed := &model.Project{ProjectID: "p-platform"}
+	if got, want := matchUpstreams(closed, r.all(), c), []string{"pl-test"}; !slices.Equal(got, want) {
+		t.Errorf("matchUpstreams() without downstream projects = %v, want %v", got, want)
+	}
+
+	// Dropping the trigger from the definition removes it.
+	if err := RecordPipelineTriggers(ctx, r, &model.Pipeline{PipelineID: "pl-deploy", ProjectID: "p-deploy"}, &pipelinev1.Spec{}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := matchUpstreams(platform, r.all(), c), []string{"pl-test"}; !slices.Equal(got, want) {
+		t.Errorf("matchUpstreams() after removal = %v, want %v", got, want)
+	}
+}
@@ -17,6 +17,7 @@ package validation
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -42,6 +43,9 @@ func (v *SchemaValidator) Validate(pipeline *spec.Pipeline) error {
 	if len(pipeline.Jobs) == 0 {
 		return fmt.Errorf("pipeline must have at least one job")
 	}
+	if err := v.validateConcurrencyPolicy(pipeline.ConcurrencyPolicy); err != nil {
+		return fmt.Errorf("concurrency_policy: %w", err)
+	}
 	if err := v.validateUniqueJobNames(pipeline.Jobs); err != nil {
 		return err
 	}
@@ -50,6 +54,9 @@ func (v *SchemaValidator) Validate(pipeline *spec.Pipeline) error {
 			return err
 		}
 	}
+	if err := v.validateNotifications(pipeline.Notifications); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -75,6 +82,15 @@ func (v *SchemaValidator) validateVersion(version string) error {
 	return nil
 }
 
+// validateConcurrencyPolicy accepts "" (queue), "queue" and "cancel-in-progress".
+func (v *SchemaValidator) validateConcurrencyPolicy(policy string) error {
+	switch policy {
+	case "", "queue", "cancel-in-progress":
+		return nil
+	}
+	return fmt.Errorf("must be queue or cancel-in-progress, got %q", policy)
+}
+
 func (v *SchemaValidator) validateUniqueJobNames(jobs []*spec.Job) error {
 	jobNames := make(map[string]int)
 	for i, job := range jobs {
@@ -105,6 +121,17 @@ func (v *SchemaValidator) validateJobAdvanced(job *spec.Job, index int) error {
 			return fmt.Errorf("job[%d] '%s' timeout: %w", index, job.Name, err)
 		}
 	}
+	if job.KeepAliveOnFailure != "" {
+		if err := v.validateTimeout(job.KeepAliveOnFailure); err != nil {
+			return fmt.Errorf("job[%d] '%s' keep_alive_on_failure: %w", index, job.Name, err)
+		}
+	}
+	if err := v.validateConcurrencyPolicy(job.ConcurrencyPolicy); err != nil {
+		return fmt.Errorf("job[%d] '%s' concurrency_policy: %w", index, job.Name, err)
+	}
+	if job.Priority != 0 && (job.Priority < 1 || job.Priority > 10) {
+		return fmt.Errorf("job[%d] '%s' priority: must be between 1 (highest) and 10 (lowest), got %d", index, job.Name, job.Priority)
+	}
 	if job.Retry != nil {
 		if err := v.validateRetry(job.Retry); err != nil {
 			return fmt.Errorf("job[%d] '%s' retry: %w", index, job.Name, err)
@@ -118,6 +145,73 @@ func (v *SchemaValidator) validateJobAdvanced(job *spec.Job, index int) error {
 			return fmt.Errorf("job[%d] '%s' step[%d] '%s': %w", index, job.Name, i, step.Name, err)
 		}
 	}
+	if err := v.validateServices(job.Services); err != nil {
+		return fmt.Errorf("job[%d] '%s' services: %w", index, job.Name, err)
+	}
+	return nil
+}
+
+func (v *SchemaValidator) validateServices(services []*spec.Service) error {
+	names := make(map[string]int)
+	for i, svc := range services {
+		if svc == nil {
+			return fmt.Errorf("service[%d] is nil", i)
+		}
+		if err := v.validateName(svc.Name, "service name"); err != nil {
+			return fmt.Errorf("service[%d] name: %w", i, err)
+		}
+		if existingIndex, exists := names[svc.Name]; exists {
+			return fmt.Errorf("duplicate service name '%s' at index %d and %d", svc.Name, existingIndex, i)
+		}
+		names[svc.Name] = i
+		if strings.TrimSpace(svc.Image) == "" {
+			return fmt.Errorf("service[%d] '%s': image is required", i, svc.Name)
+		}
+		if hc := svc.HealthCheck; hc != nil {
+			if len(hc.Command) == 0 {
+				return fmt.Errorf("service[%d] '%s' health_check: command is required", i, svc.Name)
+			}
+			for field, value := range map[string]string{"interval": hc.Interval, "timeout": hc.Timeout} {
+				if value == "" {
+					continue
+				}
+				if err := v.validateTimeout(value); err != nil {
+					return fmt.Errorf("service[%d] '%s' health_check %s: %w", i, svc.Name, field, err)
+				}
+			}
+			if hc.Retries < 0 {
+				return fmt.Errorf("service[%d] '%s' health_check: retries must not be negative", i, svc.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func (v *SchemaValidator) validateNotifications(rules []*spec.NotificationRule) error {
+	for i, rule := range rules {
+		if rule == nil {
+			return fmt.Errorf("notifications[%d] is nil", i)
+		}
+		if len(rule.Events) == 0 {
+			return fmt.Errorf("notifications[%d]: at least one event is required", i)
+		}
+		for _, event := range rule.Events {
+			if !slices.Contains(spec.NotifyEvents, event) {
+				return fmt.Errorf("notifications[%d]: invalid event '%s' (valid: %s)", i, event, strings.Join(spec.NotifyEvents, ", "))
+			}
+		}
+		if len(rule.Channels) == 0 {
+			return fmt.Errorf("notifications[%d]: at least one channel is required", i)
+		}
+		for _, ch := range rule.Channels {
+			if strings.TrimSpace(ch) == "" {
+				return fmt.Errorf("notifications[%d]: channel name must not be empty", i)
+			}
+		}
+		if strings.TrimSpace(rule.Template) == "" {
+			return fmt.Errorf("notifications[%d]: template is required", i)
+		}
+	}
 	return nil
 }
 
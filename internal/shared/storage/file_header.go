@@ -0,0 +1,74 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+)
+
+// fileHeaderMaxMemory 超过该大小的内容由 multipart 落盘到临时文件
+const fileHeaderMaxMemory = 1 << 20 // 1MB
+
+// FileHeaderFromPath 将本地文件包装为 multipart.FileHeader，便于非 HTTP 场景
+// （如流水线缓存）复用 IStorage.Upload / PutObject。
+// 返回的 cleanup 用于删除 multipart 生成的临时文件，调用方需在上传完成后执行。
+func FileHeaderFromPath(path string) (*multipart.FileHeader, func(), error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = src.Close() }()
+	return fileHeaderFromReader(filepath.Base(path), src)
+}
+
+// FileHeaderFromBytes 将内存中的内容包装为 multipart.FileHeader（如归档的日志分块），
+// cleanup 语义同 FileHeaderFromPath。
+func FileHeaderFromBytes(name string, data []byte) (*multipart.FileHeader, func(), error) {
+	return fileHeaderFromReader(name, bytes.NewReader(data))
+}
+
+func fileHeaderFromReader(name string, src io.Reader) (*multipart.FileHeader, func(), error) {
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		part, err := writer.CreateFormFile("file", name)
+		if err == nil {
+			_, err = io.Copy(part, src)
+		}
+		if err == nil {
+			err = writer.Close()
+		}
+		_ = pw.CloseWithError(err)
+	}()
+
+	form, err := multipart.NewReader(pr, writer.Boundary()).ReadForm(fileHeaderMaxMemory)
+	_ = pr.Close()
+	if err != nil {
+		return nil, nil, fmt.Errorf("read multipart form: %w", err)
+	}
+	cleanup := func() { _ = form.RemoveAll() }
+
+	files := form.File["file"]
+	if len(files) == 0 {
+		cleanup()
+		return nil, nil, fmt.Errorf("file %s not found in multipart form", name)
+	}
+	return files[0], cleanup, nil
+}
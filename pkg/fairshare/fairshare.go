@@ -0,0 +1,134 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fairshare orders queued work by priority and shares the available
+// slots fairly between groups, so that one busy group cannot starve the rest.
+package fairshare
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Item is a unit of queued work.
+type Item struct {
+	ID         string
+	Group      string // fair share group, e.g. a project
+	Priority   int    // lower runs first
+	EnqueuedAt time.Time
+}
+
+// State is the load the queue is scheduled against.
+type State struct {
+	Running map[string]int // work already running per group
+	Limits  map[string]int // concurrency cap per group; absent or <= 0 is unlimited
+}
+
+// Order returns items in the order they are dispatched. Among the next item
+// of every group the highest priority wins; ties go to the group with the
+// least work running, then to the oldest item. Items of groups at their cap
+// come last, in the order they run once their group frees a slot.
+func Order(items []Item, st State) []Item {
+	return schedule(items, st, len(items), true)
+}
+
+// Pick returns up to slots items to dispatch now in dispatch order. Groups at
+// their cap are skipped.
+func Pick(items []Item, st State, slots int) []Item {
+	return schedule(items, st, slots, false)
+}
+
+// EstimateWait estimates how long the item at position (1-based) waits for a
+// slot when inFlight items occupy some of capacity slots and an item takes
+// avg to run. Zero is returned when no estimate can be made or the item
+// starts right away.
+func EstimateWait(position, inFlight, capacity int, avg time.Duration) time.Duration {
+	if position <= 0 || capacity <= 0 || avg <= 0 {
+		return 0
+	}
+	ahead := inFlight + position - 1
+	return time.Duration(ahead/capacity) * avg
+}
+
+func schedule(items []Item, st State, slots int, all bool) []Item {
+	queues := make(map[string][]Item)
+	for _, it := range items {
+		queues[it.Group] = append(queues[it.Group], it)
+	}
+	groups := make([]string, 0, len(queues))
+	for g, q := range queues {
+		slices.SortStableFunc(q, compareItems)
+		groups = append(groups, g)
+	}
+	slices.Sort(groups)
+
+	running := make(map[string]int, len(groups))
+	for _, g := range groups {
+		running[g] = st.Running[g]
+	}
+	capped := func(g string) bool {
+		limit := st.Limits[g]
+		return limit > 0 && running[g] >= limit
+	}
+
+	out := make([]Item, 0, min(slots, len(items)))
+	for len(out) < slots {
+		best, bestCapped := "", false
+		for _, g := range groups {
+			if len(queues[g]) == 0 {
+				continue
+			}
+			c := capped(g)
+			if c && !all {
+				continue
+			}
+			if best == "" || better(g, c, best, bestCapped, queues, running) {
+				best, bestCapped = g, c
+			}
+		}
+		if best == "" {
+			break
+		}
+		out = append(out, queues[best][0])
+		queues[best] = queues[best][1:]
+		running[best]++
+	}
+	return out
+}
+
+// better reports whether the head of group a goes before the head of group b.
+func better(a string, aCapped bool, b string, bCapped bool, queues map[string][]Item, running map[string]int) bool {
+	if aCapped != bCapped {
+		return !aCapped
+	}
+	x, y := queues[a][0], queues[b][0]
+	if c := cmp.Compare(x.Priority, y.Priority); c != 0 {
+		return c < 0
+	}
+	if c := cmp.Compare(running[a], running[b]); c != 0 {
+		return c < 0
+	}
+	return compareItems(x, y) < 0
+}
+
+func compareItems(x, y Item) int {
+	if c := cmp.Compare(x.Priority, y.Priority); c != 0 {
+		return c
+	}
+	if c := x.EnqueuedAt.Compare(y.EnqueuedAt); c != 0 {
+		return c
+	}
+	return cmp.Compare(x.ID, y.ID)
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fairshare
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var t0 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func item(id, group string, priority, minute int) Item {
+	return Item{ID: id, Group: group, Priority: priority, EnqueuedAt: t0.Add(time.Duration(minute) * time.Minute)}
+}
+
+func ids(items []Item) []string {
+	out := make([]string, 0, len(items))
+	for _, it := range items {
+		out = append(out, it.ID)
+	}
+	return out
+}
+
+func TestOrder_FairShare(t *testing.T) {
+	// project a queued everything first; b still gets every other slot
+	items := []Item{
+		item("a1", "a", 5, 0),
+		item("a2", "a", 5, 1),
+		item("a3", "a", 5, 2),
+		item("b1", "b", 5, 3),
+		item("b2", "b", 5, 4),
+	}
+	assert.Equal(t, []string{"a1", "b1", "a2", "b2", "a3"}, ids(Order(items, State{})))
+
+	// work already running counts against its group
+	st := State{Running: map[string]int{"a": 2}}
+	assert.Equal(t, []string{"b1", "b2", "a1", "a2", "a3"}, ids(Order(items, st)))
+}
+
+func TestOrder_Priority(t *testing.T) {
+	items := []Item{
+		item("a1", "a", 5, 0),
+		item("a2", "a", 1, 1),
+		item("b1", "b", 5, 2),
+	}
+	assert.Equal(t, []string{"a2", "b1", "a1"}, ids(Order(items, State{})))
+}
+
+func TestPick_Limits(t *testing.T) {
+	items := []Item{
+		item("a1", "a", 5, 0),
+		item("a2", "a", 5, 1),
+		item("b1", "b", 5, 2),
+	}
+	st := State{Running: map[string]int{"a": 1}, Limits: map[string]int{"a": 2}}
+
+	assert.Equal(t, []string{"b1", "a1"}, ids(Pick(items, st, 10)))
+	assert.Equal(t, []string{"b1"}, ids(Pick(items, st, 1)))
+	// capped items keep a position behind everything runnable
+	assert.Equal(t, []string{"b1", "a1", "a2"}, ids(Order(items, st)))
+	assert.Empty(t, Pick(items, st, 0))
+}
+
+func TestEstimateWait(t *testing.T) {
+	avg := 10 * time.Minute
+	assert.Equal(t, time.Duration(0), EstimateWait(1, 0, 2, avg))
+	assert.Equal(t, time.Duration(0), EstimateWait(2, 0, 2, avg))
+	assert.Equal(t, avg, EstimateWait(1, 2, 2, avg))
+	assert.Equal(t, 2*avg, EstimateWait(3, 3, 2, avg))
+	assert.Equal(t, time.Duration(0), EstimateWait(3, 3, 0, avg))
+}
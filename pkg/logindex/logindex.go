@@ -0,0 +1,708 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logindex is an embedded inverted index over build log lines.
+//
+// Lines are added in batches to an in-memory segment. Once a segment holds
+// enough lines it is sealed: written to disk as a gzip'd gob file next to a
+// small JSON header (time range and step runs). Searches prune segments by
+// header, load the rest on demand through a small cache, narrow candidates
+// with the term postings and verify each candidate against the phrase or
+// regular expression, returning the matched spans for highlighting.
+package logindex
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultSegmentDocs is the number of lines buffered before a segment is sealed.
+	DefaultSegmentDocs = 50000
+	// DefaultCacheSegments is the number of sealed segments kept decoded in memory.
+	DefaultCacheSegments = 8
+	// MaxMatches bounds the matches collected by one search.
+	MaxMatches = 10000
+
+	maxHighlights = 16
+	minTokenLen   = 2
+	maxTokenLen   = 64
+)
+
+// ErrEmptyQuery is returned by Search when neither a phrase nor a regular
+// expression is given.
+var ErrEmptyQuery = errors.New("logindex: phrase or regex is required")
+
+// ErrInvalidRegex is returned by Search when the regular expression does not
+// compile.
+var ErrInvalidRegex = errors.New("logindex: invalid regex")
+
+// Doc is an indexed log line.
+type Doc struct {
+	StepRunID string
+	Line      int32
+	Timestamp int64 // unix seconds
+	Content   string
+}
+
+// Options configures an index.
+type Options struct {
+	Dir           string // directory holding the sealed segments
+	SegmentDocs   int    // lines per segment, DefaultSegmentDocs when zero
+	CacheSegments int    // decoded segments cached, DefaultCacheSegments when zero
+}
+
+// Query is a search over the index. Phrase matches case-insensitively; Regex
+// uses RE2 syntax. When both are set a line must match both.
+type Query struct {
+	Phrase string
+	Regex  string
+	From   int64 // inclusive lower timestamp bound, 0 for none
+	To     int64 // inclusive upper timestamp bound, 0 for none
+	// Match, when set, restricts the search to the step runs it accepts.
+	Match  func(stepRunID string) bool
+	Desc   bool // newest first; oldest first by default
+	Offset int
+	Limit  int
+}
+
+// Hit is a matching line with the byte spans that matched.
+type Hit struct {
+	Doc
+	Highlights [][2]int
+}
+
+// Result is a page of hits.
+type Result struct {
+	Hits      []Hit
+	Total     int  // matches found, capped at MaxMatches
+	Truncated bool // more than MaxMatches lines matched
+}
+
+// segmentMeta is the header of a sealed segment.
+type segmentMeta struct {
+	ID       uint64   `json:"id"`
+	Docs     int      `json:"docs"`
+	MinTS    int64    `json:"minTs"`
+	MaxTS    int64    `json:"maxTs"`
+	StepRuns []string `json:"stepRuns"`
+}
+
+// segment holds the lines and postings of a segment. Postings list the
+// positions of the lines containing a term, in ascending order.
+type segment struct {
+	Docs     []Doc
+	Postings map[string][]uint32
+}
+
+// Index is an embedded inverted index. It is safe for concurrent use.
+type Index struct {
+	opts Options
+
+	mu         sync.RWMutex
+	sealed     []*segmentMeta
+	active     *segment
+	activeMeta *segmentMeta
+	activeRuns map[string]struct{}
+	nextID     uint64
+
+	cacheMu sync.Mutex
+	cache   map[uint64]*segment
+	lru     []uint64
+}
+
+// Open opens the index in opts.Dir, creating the directory when needed.
+func Open(opts Options) (*Index, error) {
+	if opts.Dir == "" {
+		return nil, errors.New("logindex: dir is required")
+	}
+	if opts.SegmentDocs <= 0 {
+		opts.SegmentDocs = DefaultSegmentDocs
+	}
+	if opts.CacheSegments <= 0 {
+		opts.CacheSegments = DefaultCacheSegments
+	}
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("logindex: create dir: %w", err)
+	}
+
+	ix := &Index{opts: opts, cache: make(map[uint64]*segment), nextID: 1}
+	metas, err := filepath.Glob(filepath.Join(opts.Dir, "*.meta"))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range metas {
+		raw, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("logindex: read %s: %w", name, err)
+		}
+		var meta segmentMeta
+		if err := json.Unmarshal(raw, &meta); err != nil {
+			return nil, fmt.Errorf("logindex: decode %s: %w", name, err)
+		}
+		ix.sealed = append(ix.sealed, &meta)
+		ix.nextID = max(ix.nextID, meta.ID+1)
+	}
+	slices.SortFunc(ix.sealed, func(a, b *segmentMeta) int { return compareUint(a.ID, b.ID) })
+	ix.resetActive()
+	return ix, nil
+}
+
+// Add indexes lines. The segment is sealed to disk once it is full.
+func (ix *Index) Add(docs ...Doc) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	for _, doc := range docs {
+		pos := uint32(len(ix.active.Docs))
+		ix.active.Docs = append(ix.active.Docs, doc)
+		for _, term := range uniqueTerms(doc.Content) {
+			ix.active.Postings[term] = append(ix.active.Postings[term], pos)
+		}
+		meta := ix.activeMeta
+		if meta.Docs == 0 || doc.Timestamp < meta.MinTS {
+			meta.MinTS = doc.Timestamp
+		}
+		if doc.Timestamp > meta.MaxTS {
+			meta.MaxTS = doc.Timestamp
+		}
+		meta.Docs++
+		if _, ok := ix.activeRuns[doc.StepRunID]; !ok {
+			ix.activeRuns[doc.StepRunID] = struct{}{}
+			meta.StepRuns = append(meta.StepRuns, doc.StepRunID)
+		}
+		if meta.Docs >= ix.opts.SegmentDocs {
+			if err := ix.sealLocked(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Flush seals the lines added so far to disk.
+func (ix *Index) Flush() error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if ix.activeMeta.Docs == 0 {
+		return nil
+	}
+	return ix.sealLocked()
+}
+
+// Close flushes the index.
+func (ix *Index) Close() error {
+	return ix.Flush()
+}
+
+// DeleteBefore drops the sealed segments whose lines are all older than ts
+// and returns the number of segments dropped.
+func (ix *Index) DeleteBefore(ts int64) (int, error) {
+	ix.mu.Lock()
+	var keep, drop []*segmentMeta
+	for _, meta := range ix.sealed {
+		if meta.MaxTS < ts {
+			drop = append(drop, meta)
+		} else {
+			keep = append(keep, meta)
+		}
+	}
+	ix.sealed = keep
+	ix.mu.Unlock()
+
+	var errs []error
+	for _, meta := range drop {
+		ix.uncache(meta.ID)
+		// the header goes first so a crash never leaves a header without data
+		if err := os.Remove(ix.metaPath(meta.ID)); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+		if err := os.Remove(ix.segmentPath(meta.ID)); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	return len(drop), errors.Join(errs...)
+}
+
+// Indexed returns the step runs among stepRunIDs that have lines in the
+// index.
+func (ix *Index) Indexed(stepRunIDs []string) map[string]struct{} {
+	want := make(map[string]struct{}, len(stepRunIDs))
+	for _, id := range stepRunIDs {
+		want[id] = struct{}{}
+	}
+	found := make(map[string]struct{})
+	mark := func(id string) {
+		if _, ok := want[id]; ok {
+			found[id] = struct{}{}
+		}
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	for _, meta := range ix.sealed {
+		for _, id := range meta.StepRuns {
+			mark(id)
+		}
+	}
+	for id := range ix.activeRuns {
+		mark(id)
+	}
+	return found
+}
+
+// Search runs a query over the index.
+func (ix *Index) Search(q Query) (*Result, error) {
+	m, err := newMatcher(q)
+	if err != nil {
+		return nil, err
+	}
+
+	truncated := false
+	collect := func(seg *segment, hits []Hit) []Hit {
+		cands := m.candidates(seg)
+		for i := range cands {
+			if len(hits) >= MaxMatches {
+				truncated = true
+				return hits
+			}
+			pos := cands[i]
+			if q.Desc {
+				pos = cands[len(cands)-1-i]
+			}
+			doc := seg.Docs[pos]
+			if !m.accepts(doc) {
+				continue
+			}
+			if spans := m.match(doc.Content); spans != nil {
+				hits = append(hits, Hit{Doc: doc, Highlights: spans})
+			}
+		}
+		return hits
+	}
+
+	// the active segment changes under Add, search it under the lock
+	ix.mu.RLock()
+	sealed := slices.Clone(ix.sealed)
+	var active []Hit
+	if m.overlaps(ix.activeMeta) {
+		active = collect(ix.active, nil)
+	}
+	ix.mu.RUnlock()
+	activeFull := truncated
+	truncated = false
+
+	// Lines arrive in time order: sealed segments run from oldest to newest
+	// and the active segment holds the newest lines. Segments are visited in
+	// the requested order so a truncated search keeps the first matches of
+	// that order.
+	var hits []Hit
+	if q.Desc {
+		hits, truncated = active, activeFull
+		slices.Reverse(sealed)
+	}
+	for _, meta := range sealed {
+		if truncated {
+			break
+		}
+		if !m.overlaps(meta) {
+			continue
+		}
+		seg, err := ix.load(meta.ID)
+		if err != nil {
+			return nil, err
+		}
+		hits = collect(seg, hits)
+	}
+	if !q.Desc && !truncated {
+		if room := MaxMatches - len(hits); activeFull || len(active) > room {
+			active = active[:min(room, len(active))]
+			truncated = true
+		}
+		hits = append(hits, active...)
+	}
+
+	slices.SortFunc(hits, func(a, b Hit) int {
+		c := compareInt(a.Timestamp, b.Timestamp)
+		if c == 0 {
+			c = strings.Compare(a.StepRunID, b.StepRunID)
+		}
+		if c == 0 {
+			c = compareInt(int64(a.Line), int64(b.Line))
+		}
+		if q.Desc {
+			return -c
+		}
+		return c
+	})
+
+	res := &Result{Total: len(hits), Truncated: truncated}
+	start := min(max(q.Offset, 0), len(hits))
+	end := len(hits)
+	if q.Limit > 0 {
+		end = min(start+q.Limit, len(hits))
+	}
+	res.Hits = hits[start:end]
+	return res, nil
+}
+
+// sealLocked writes the active segment to disk and starts a new one.
+func (ix *Index) sealLocked() error {
+	meta := ix.activeMeta
+	meta.ID = ix.nextID
+	if err := ix.writeSegment(meta.ID, ix.active); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(ix.metaPath(meta.ID), raw); err != nil {
+		return fmt.Errorf("logindex: write segment header: %w", err)
+	}
+	ix.nextID++
+	ix.sealed = append(ix.sealed, meta)
+	ix.store(meta.ID, ix.active)
+	ix.resetActive()
+	return nil
+}
+
+func (ix *Index) resetActive() {
+	ix.active = &segment{Postings: make(map[string][]uint32)}
+	ix.activeMeta = &segmentMeta{}
+	ix.activeRuns = make(map[string]struct{})
+}
+
+func (ix *Index) writeSegment(id uint64, seg *segment) error {
+	tmp := ix.segmentPath(id) + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("logindex: create segment: %w", err)
+	}
+	zw := gzip.NewWriter(f)
+	err = gob.NewEncoder(zw).Encode(seg)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, ix.segmentPath(id))
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("logindex: write segment: %w", err)
+	}
+	return nil
+}
+
+// load returns a sealed segment, decoding it from disk when not cached.
+func (ix *Index) load(id uint64) (*segment, error) {
+	ix.cacheMu.Lock()
+	if seg, ok := ix.cache[id]; ok {
+		ix.touchLocked(id)
+		ix.cacheMu.Unlock()
+		return seg, nil
+	}
+	ix.cacheMu.Unlock()
+
+	f, err := os.Open(ix.segmentPath(id))
+	if err != nil {
+		return nil, fmt.Errorf("logindex: open segment: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("logindex: read segment %d: %w", id, err)
+	}
+	var seg segment
+	if err := gob.NewDecoder(zr).Decode(&seg); err != nil {
+		return nil, fmt.Errorf("logindex: decode segment %d: %w", id, err)
+	}
+	ix.store(id, &seg)
+	return &seg, nil
+}
+
+func (ix *Index) store(id uint64, seg *segment) {
+	ix.cacheMu.Lock()
+	defer ix.cacheMu.Unlock()
+	ix.cache[id] = seg
+	ix.touchLocked(id)
+	for len(ix.lru) > ix.opts.CacheSegments {
+		delete(ix.cache, ix.lru[0])
+		ix.lru = ix.lru[1:]
+	}
+}
+
+func (ix *Index) uncache(id uint64) {
+	ix.cacheMu.Lock()
+	defer ix.cacheMu.Unlock()
+	delete(ix.cache, id)
+	ix.lru = slices.DeleteFunc(ix.lru, func(v uint64) bool { return v == id })
+}
+
+// touchLocked moves id to the most recently used end.
+func (ix *Index) touchLocked(id uint64) {
+	ix.lru = slices.DeleteFunc(ix.lru, func(v uint64) bool { return v == id })
+	ix.lru = append(ix.lru, id)
+}
+
+func (ix *Index) segmentPath(id uint64) string {
+	return filepath.Join(ix.opts.Dir, segmentName(id)+".seg")
+}
+
+func (ix *Index) metaPath(id uint64) string {
+	return filepath.Join(ix.opts.Dir, segmentName(id)+".meta")
+}
+
+func segmentName(id uint64) string {
+	return fmt.Sprintf("%012d", id)
+}
+
+func writeFileAtomic(name string, data []byte) error {
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+// matcher evaluates a query against segments and lines.
+type matcher struct {
+	q      Query
+	phrase string
+	terms  []string
+	re     *regexp.Regexp
+}
+
+func newMatcher(q Query) (*matcher, error) {
+	m := &matcher{q: q, phrase: strings.ToLower(strings.TrimSpace(q.Phrase))}
+	if q.Regex != "" {
+		re, err := regexp.Compile(q.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRegex, err)
+		}
+		m.re = re
+	}
+	if m.phrase == "" && m.re == nil {
+		return nil, ErrEmptyQuery
+	}
+	m.terms = uniqueTerms(m.phrase)
+	return m, nil
+}
+
+// overlaps reports whether a segment may hold matching lines.
+func (m *matcher) overlaps(meta *segmentMeta) bool {
+	if meta.Docs == 0 {
+		return false
+	}
+	if m.q.From > 0 && meta.MaxTS < m.q.From {
+		return false
+	}
+	if m.q.To > 0 && meta.MinTS > m.q.To {
+		return false
+	}
+	if m.q.Match == nil {
+		return true
+	}
+	return slices.ContainsFunc(meta.StepRuns, m.q.Match)
+}
+
+// candidates returns the positions of the lines containing every phrase term.
+func (m *matcher) candidates(seg *segment) []uint32 {
+	if len(m.terms) == 0 {
+		all := make([]uint32, len(seg.Docs))
+		for i := range all {
+			all[i] = uint32(i)
+		}
+		return all
+	}
+	lists := make([][]uint32, 0, len(m.terms))
+	for _, term := range m.terms {
+		list := seg.Postings[term]
+		if len(list) == 0 {
+			return nil
+		}
+		lists = append(lists, list)
+	}
+	slices.SortFunc(lists, func(a, b []uint32) int { return len(a) - len(b) })
+	out := lists[0]
+	for _, list := range lists[1:] {
+		out = intersect(out, list)
+		if len(out) == 0 {
+			return nil
+		}
+	}
+	return out
+}
+
+// alignedAt reports whether the phrase found at lower[start:end] begins and
+// ends on word boundaries, as the terms it was looked up by are whole words.
+func (m *matcher) alignedAt(lower string, start, end int) bool {
+	if isWordByte(m.phrase[0]) && start > 0 && isWordByte(lower[start-1]) {
+		return false
+	}
+	if isWordByte(m.phrase[len(m.phrase)-1]) && end < len(lower) && isWordByte(lower[end]) {
+		return false
+	}
+	return true
+}
+
+func (m *matcher) accepts(doc Doc) bool {
+	if m.q.From > 0 && doc.Timestamp < m.q.From {
+		return false
+	}
+	if m.q.To > 0 && doc.Timestamp > m.q.To {
+		return false
+	}
+	return m.q.Match == nil || m.q.Match(doc.StepRunID)
+}
+
+// match returns the matched spans of content, nil when it does not match.
+func (m *matcher) match(content string) [][2]int {
+	var spans [][2]int
+	if m.phrase != "" {
+		lower := strings.ToLower(content)
+		for i := 0; len(spans) < maxHighlights; {
+			j := strings.Index(lower[i:], m.phrase)
+			if j < 0 {
+				break
+			}
+			start, end := i+j, i+j+len(m.phrase)
+			if m.alignedAt(lower, start, end) {
+				spans = append(spans, [2]int{start, end})
+				i = end
+			} else {
+				i = start + 1
+			}
+		}
+		if len(spans) == 0 {
+			return nil
+		}
+		// lower-casing can change byte lengths; offsets then do not map back
+		if len(lower) != len(content) {
+			spans = [][2]int{}
+		}
+	}
+	if m.re != nil {
+		locs := m.re.FindAllStringIndex(content, maxHighlights)
+		if locs == nil {
+			return nil
+		}
+		for _, loc := range locs {
+			spans = append(spans, [2]int{loc[0], loc[1]})
+		}
+	}
+	slices.SortFunc(spans, func(a, b [2]int) int { return a[0] - b[0] })
+	return spans
+}
+
+// Highlight wraps the spans of content in pre and post. escape, when set, is
+// applied to the text between and inside the spans.
+func Highlight(content string, spans [][2]int, pre, post string, escape func(string) string) string {
+	if escape == nil {
+		escape = func(s string) string { return s }
+	}
+	var b strings.Builder
+	last := 0
+	for _, span := range spans {
+		start, end := max(span[0], last), span[1]
+		if start >= end || end > len(content) {
+			continue
+		}
+		b.WriteString(escape(content[last:start]))
+		b.WriteString(pre)
+		b.WriteString(escape(content[start:end]))
+		b.WriteString(post)
+		last = end
+	}
+	b.WriteString(escape(content[last:]))
+	return b.String()
+}
+
+// uniqueTerms splits text into lower-cased terms of letters, digits and
+// underscores, each returned once.
+func uniqueTerms(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	seen := make(map[string]struct{}, len(fields))
+	terms := fields[:0]
+	for _, f := range fields {
+		if len(f) < minTokenLen || len(f) > maxTokenLen {
+			continue
+		}
+		if _, ok := seen[f]; ok {
+			continue
+		}
+		seen[f] = struct{}{}
+		terms = append(terms, f)
+	}
+	return terms
+}
+
+// isWordByte reports whether b belongs to a term. Bytes of multi-byte runes
+// count as word bytes, which keeps boundaries conservative.
+func isWordByte(b byte) bool {
+	return b == '_' || b >= utf8.RuneSelf ||
+		'0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+func intersect(a, b []uint32) []uint32 {
+	out := make([]uint32, 0, min(len(a), len(b)))
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			out = append(out, a[i])
+			i++
+			j++
+		}
+	}
+	return out
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logindex
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleDocs() []Doc {
+	return []Doc{
+		{StepRunID: "s1", Line: 1, Timestamp: 100, Content: "Compiling module arcentra"},
+		{StepRunID: "s1", Line: 2, Timestamp: 101, Content: "panic: runtime error: index out of range"},
+		{StepRunID: "s2", Line: 1, Timestamp: 200, Content: "FAIL TestRunner (0.03s)"},
+		{StepRunID: "s2", Line: 2, Timestamp: 201, Content: "runtime errors are reported above"},
+		{StepRunID: "s3", Line: 7, Timestamp: 300, Content: "exit code 137 <oom>"},
+	}
+}
+
+func openIndex(t *testing.T, segmentDocs int) *Index {
+	t.Helper()
+	ix, err := Open(Options{Dir: t.TempDir(), SegmentDocs: segmentDocs})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ix.Close() })
+	require.NoError(t, ix.Add(sampleDocs()...))
+	return ix
+}
+
+func TestSearch_Phrase(t *testing.T) {
+	for _, segmentDocs := range []int{2, 100} {
+		ix := openIndex(t, segmentDocs)
+
+		res, err := ix.Search(Query{Phrase: "Runtime Error"})
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Total, "phrase must stop on a word boundary")
+		assert.Equal(t, "s1", res.Hits[0].StepRunID)
+		assert.Equal(t, int32(2), res.Hits[0].Line)
+		assert.Equal(t, [][2]int{{7, 20}}, res.Hits[0].Highlights)
+
+		res, err = ix.Search(Query{Phrase: "runtime"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, res.Total)
+
+		res, err = ix.Search(Query{Phrase: "untime"})
+		require.NoError(t, err)
+		assert.Equal(t, 0, res.Total)
+	}
+}
+
+func TestSearch_Regex(t *testing.T) {
+	ix := openIndex(t, 2)
+
+	res, err := ix.Search(Query{Regex: `exit code \d+`})
+	require.NoError(t, err)
+	require.Equal(t, 1, res.Total)
+	assert.Equal(t, "s3", res.Hits[0].StepRunID)
+	assert.Equal(t, [][2]int{{0, 13}}, res.Hits[0].Highlights)
+
+	_, err = ix.Search(Query{Regex: "("})
+	assert.ErrorIs(t, err, ErrInvalidRegex)
+
+	_, err = ix.Search(Query{})
+	assert.ErrorIs(t, err, ErrEmptyQuery)
+}
+
+func TestSearch_Filters(t *testing.T) {
+	ix := openIndex(t, 2)
+
+	res, err := ix.Search(Query{Phrase: "runtime", From: 150})
+	require.NoError(t, err)
+	require.Equal(t, 1, res.Total)
+	assert.Equal(t, "s2", res.Hits[0].StepRunID)
+
+	res, err = ix.Search(Query{Regex: ".", Match: func(id string) bool { return id == "s1" }})
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Total)
+
+	res, err = ix.Search(Query{Regex: ".", Desc: true, Offset: 1, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 5, res.Total)
+	require.Len(t, res.Hits, 2)
+	assert.Equal(t, int64(201), res.Hits[0].Timestamp)
+	assert.Equal(t, int64(200), res.Hits[1].Timestamp)
+}
+
+func TestIndex_Reopen(t *testing.T) {
+	dir := t.TempDir()
+	ix, err := Open(Options{Dir: dir, SegmentDocs: 2})
+	require.NoError(t, err)
+	require.NoError(t, ix.Add(sampleDocs()...))
+	require.NoError(t, ix.Close())
+
+	ix, err = Open(Options{Dir: dir, SegmentDocs: 2})
+	require.NoError(t, err)
+	defer ix.Close()
+
+	res, err := ix.Search(Query{Regex: "."})
+	require.NoError(t, err)
+	assert.Equal(t, 5, res.Total)
+}
+
+func TestIndex_DeleteBefore(t *testing.T) {
+	ix := openIndex(t, 2)
+	require.NoError(t, ix.Flush())
+
+	n, err := ix.DeleteBefore(250)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	res, err := ix.Search(Query{Regex: "."})
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, "s3", res.Hits[0].StepRunID)
+}
+
+func TestIndex_Indexed(t *testing.T) {
+	// s1 and s2 end up in sealed segments, s3 in the active one.
+	ix := openIndex(t, 2)
+
+	got := ix.Indexed([]string{"s1", "s3", "s9"})
+	assert.Equal(t, map[string]struct{}{"s1": {}, "s3": {}}, got)
+}
+
+func TestHighlight(t *testing.T) {
+	escape := strings.NewReplacer("<", "&lt;", ">", "&gt;").Replace
+	got := Highlight("exit code 137 <oom>", [][2]int{{5, 9}, {14, 19}}, "[", "]", escape)
+	assert.Equal(t, "exit [code] 137 [&lt;oom&gt;]", got)
+	assert.Equal(t, "a<b", Highlight("a<b", nil, "[", "]", nil))
+}
+
+func TestSearch_TruncatedKeepsFirstMatchesOfOrder(t *testing.T) {
+	ix, err := Open(Options{Dir: t.TempDir(), SegmentDocs: 4000})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ix.Close() })
+
+	// three sealed segments and a partly filled active one
+	const lines = MaxMatches + 3000
+	docs := make([]Doc, 0, lines)
+	for i := range lines {
+		docs = append(docs, Doc{StepRunID: "s1", Line: int32(i + 1), Timestamp: int64(i + 1), Content: "error: retrying"})
+	}
+	require.NoError(t, ix.Add(docs...))
+
+	res, err := ix.Search(Query{Phrase: "error", Desc: true, Limit: 1})
+	require.NoError(t, err)
+	assert.True(t, res.Truncated)
+	assert.Equal(t, MaxMatches, res.Total)
+	assert.Equal(t, int64(lines), res.Hits[0].Timestamp, "newest first must start at the newest line")
+
+	res, err = ix.Search(Query{Phrase: "error", Limit: 1})
+	require.NoError(t, err)
+	assert.True(t, res.Truncated)
+	assert.Equal(t, int64(1), res.Hits[0].Timestamp, "oldest first must start at the oldest line")
+
+	// with everything sealed the newest segment comes first too
+	require.NoError(t, ix.Flush())
+	res, err = ix.Search(Query{Phrase: "error", Desc: true, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(lines), res.Hits[0].Timestamp)
+}
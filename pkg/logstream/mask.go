@@ -0,0 +1,215 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstream
+
+import (
+	"encoding/base64"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MaskPlaceholder replaces every masked value in log content.
+const MaskPlaceholder = "***"
+
+// minMaskLength is the shortest value that is masked. Shorter values such as
+// "1" or "on" would garble unrelated output.
+const minMaskLength = 4
+
+// maskers holds the mask sets registered for runs in progress, by scope.
+var maskers sync.Map
+
+// RegisterMasker makes masker the mask set of scope, e.g. a pipeline run ID,
+// so log paths that only know the scope can find it. Every RegisterMasker is
+// paired with UnregisterMasker once the scope has finished, which drops its
+// secrets from the process.
+func RegisterMasker(scope string, masker *Masker) {
+	if scope == "" || masker == nil {
+		return
+	}
+	maskers.Store(scope, masker)
+}
+
+// UnregisterMasker forgets the mask set of scope.
+func UnregisterMasker(scope string) {
+	maskers.Delete(scope)
+}
+
+// LookupMasker returns the mask set of scope, nil when none is registered. A
+// nil Masker masks nothing.
+func LookupMasker(scope string) *Masker {
+	if v, ok := maskers.Load(scope); ok {
+		return v.(*Masker)
+	}
+	return nil
+}
+
+// Masker replaces registered secret values in log content. It is safe for
+// concurrent use.
+type Masker struct {
+	mu     sync.RWMutex
+	values map[string]struct{}
+	// sorted holds the values longest first so that a value containing
+	// another one is replaced as a whole.
+	sorted []string
+}
+
+// NewMasker creates a Masker for values.
+func NewMasker(values ...string) *Masker {
+	m := &Masker{values: make(map[string]struct{})}
+	m.Add(values...)
+	return m
+}
+
+// Add registers secret values. Besides the value itself, its base64 and
+// URL-encoded forms and, for multi-line values, each of its lines are masked.
+func (m *Masker) Add(values ...string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	changed := false
+	for _, value := range values {
+		for _, form := range maskForms(value) {
+			if len(form) < minMaskLength {
+				continue
+			}
+			if _, ok := m.values[form]; ok {
+				continue
+			}
+			m.values[form] = struct{}{}
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+	m.sorted = m.sorted[:0]
+	for v := range m.values {
+		m.sorted = append(m.sorted, v)
+	}
+	sort.Slice(m.sorted, func(i, j int) bool {
+		if len(m.sorted[i]) != len(m.sorted[j]) {
+			return len(m.sorted[i]) > len(m.sorted[j])
+		}
+		return m.sorted[i] < m.sorted[j]
+	})
+}
+
+// Len returns the number of registered forms.
+func (m *Masker) Len() int {
+	if m == nil {
+		return 0
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.sorted)
+}
+
+// Mask returns s with every registered value replaced by MaskPlaceholder.
+func (m *Masker) Mask(s string) string {
+	if m == nil || s == "" {
+		return s
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, v := range m.sorted {
+		if strings.Contains(s, v) {
+			s = strings.ReplaceAll(s, v, MaskPlaceholder)
+		}
+	}
+	return s
+}
+
+// partialSuffix returns the length of the longest suffix of s that is a
+// proper prefix of a registered value, i.e. the part of s that may turn
+// into a secret once more content arrives.
+func (m *Masker) partialSuffix(s string) int {
+	if m == nil {
+		return 0
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	longest := 0
+	for _, v := range m.sorted {
+		n := len(v) - 1
+		if n > len(s) {
+			n = len(s)
+		}
+		for ; n > longest; n-- {
+			if strings.HasPrefix(v, s[len(s)-n:]) {
+				longest = n
+				break
+			}
+		}
+	}
+	return longest
+}
+
+// maskForms returns the representations of value that are masked.
+func maskForms(value string) []string {
+	if len(value) < minMaskLength {
+		return nil
+	}
+	forms := []string{
+		value,
+		base64.StdEncoding.EncodeToString([]byte(value)),
+		base64.RawStdEncoding.EncodeToString([]byte(value)),
+		base64.URLEncoding.EncodeToString([]byte(value)),
+		base64.RawURLEncoding.EncodeToString([]byte(value)),
+		url.QueryEscape(value),
+		url.PathEscape(value),
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		for _, line := range strings.Split(value, "\n") {
+			forms = append(forms, strings.TrimSuffix(line, "\r"))
+		}
+	}
+	return forms
+}
+
+// StreamMasker masks content that arrives in chunks. A value split across
+// two writes is still masked: the tail of a write that may start a secret is
+// held back until the next write or Flush. It is not safe for concurrent use.
+type StreamMasker struct {
+	masker  *Masker
+	pending string
+}
+
+// NewStreamMasker creates a StreamMasker backed by masker.
+func NewStreamMasker(masker *Masker) *StreamMasker {
+	return &StreamMasker{masker: masker}
+}
+
+// Write masks p together with the held back content and returns the part
+// that can be emitted.
+func (s *StreamMasker) Write(p []byte) []byte {
+	if s.masker.Len() == 0 && s.pending == "" {
+		return p
+	}
+	masked := s.masker.Mask(s.pending + string(p))
+	hold := s.masker.partialSuffix(masked)
+	s.pending = masked[len(masked)-hold:]
+	return []byte(masked[:len(masked)-hold])
+}
+
+// Flush returns the held back content.
+func (s *StreamMasker) Flush() []byte {
+	out := s.masker.Mask(s.pending)
+	s.pending = ""
+	return []byte(out)
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstream
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestMaskerMasksEncodedForms(t *testing.T) {
+	m := NewMasker()
+	secret := "p@ss word/42"
+	m.Add(secret)
+
+	inputs := []string{
+		"token=" + secret,
+		"auth: " + base64.StdEncoding.EncodeToString([]byte(secret)),
+		"https://example.com/?p=" + url.QueryEscape(secret),
+		"https://example.com/" + url.PathEscape(secret),
+	}
+	for _, in := range inputs {
+		got := m.Mask(in)
+		if !strings.Contains(got, MaskPlaceholder) || strings.Contains(got, secret) {
+			t.Errorf("Mask(%q) = %q", in, got)
+		}
+	}
+}
+
+func TestMaskerIgnoresShortValues(t *testing.T) {
+	m := NewMasker()
+	m.Add("on", "")
+	if m.Len() != 0 {
+		t.Fatalf("Len = %d, want 0", m.Len())
+	}
+	if got := m.Mask("turn it on"); got != "turn it on" {
+		t.Errorf("Mask = %q", got)
+	}
+}
+
+func TestMaskerMasksLinesOfMultiLineValues(t *testing.T) {
+	m := NewMasker()
+	m.Add("-----BEGIN KEY-----\nabcdefgh\n-----END KEY-----")
+	if got := m.Mask("abcdefgh"); got != MaskPlaceholder {
+		t.Errorf("Mask = %q, want %q", got, MaskPlaceholder)
+	}
+}
+
+func TestStreamMaskerAcrossChunks(t *testing.T) {
+	m := NewMasker()
+	m.Add("supersecret")
+	s := NewStreamMasker(m)
+
+	var out strings.Builder
+	for _, chunk := range []string{"value: sup", "ers", "ecret done\nnext super", " line"} {
+		out.Write(s.Write([]byte(chunk)))
+	}
+	out.Write(s.Flush())
+
+	want := "value: *** done\nnext super line"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestStreamMaskerPassesThroughWithoutValues(t *testing.T) {
+	s := NewStreamMasker(NewMasker())
+	if got := string(s.Write([]byte("plain"))); got != "plain" {
+		t.Errorf("Write = %q", got)
+	}
+	if got := string(s.Flush()); got != "" {
+		t.Errorf("Flush = %q", got)
+	}
+}
+
+func TestMaskerRegistry(t *testing.T) {
+	m := NewMasker("run-secret")
+	RegisterMasker("run-1", m)
+	if got := LookupMasker("run-1").Mask("token=run-secret"); got != "token=***" {
+		t.Errorf("registered masker Mask() = %q", got)
+	}
+	if LookupMasker("run-2") != nil {
+		t.Errorf("LookupMasker(run-2) = non-nil, want nil")
+	}
+
+	UnregisterMasker("run-1")
+	released := LookupMasker("run-1")
+	if released != nil {
+		t.Fatalf("LookupMasker after UnregisterMasker = non-nil")
+	}
+	// A missing mask set masks nothing, also when streaming.
+	if got := released.Mask("run-secret"); got != "run-secret" {
+		t.Errorf("nil Masker Mask() = %q", got)
+	}
+	s := NewStreamMasker(released)
+	if got := string(s.Write([]byte("run-"))) + string(s.Flush()); got != "run-" {
+		t.Errorf("nil StreamMasker output = %q", got)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"maps"
 	"sync"
+	"time"
 
 	"github.com/arcentrix/arcentra/pkg/log"
 	"github.com/fsnotify/fsnotify"
@@ -27,6 +28,8 @@ import (
 var (
 	pluginConfigs    map[string]any
 	builtinConfigs   map[string]any
+	pluginDir        string
+	executeTimeout   time.Duration
 	pluginConfigMu   sync.RWMutex
 	pluginConfigOnce sync.Once
 )
@@ -89,6 +92,8 @@ func loadPluginConfigFile(configPath string) (map[string]any, map[string]any, er
 		pluginConfigMu.Lock()
 		pluginConfigs = newConfigs
 		builtinConfigs = newBuiltinConfigs
+		pluginDir = config.GetString("external.dir")
+		executeTimeout = config.GetDuration("external.executeTimeout")
 		pluginConfigMu.Unlock()
 		log.Infow("plugin configuration reloaded successfully", "file", e.Name)
 	})
@@ -111,6 +116,12 @@ func loadPluginConfigFile(configPath string) (map[string]any, map[string]any, er
 		builtins = make(map[string]any)
 	}
 
+	// external.dir 是外部插件目录，external.executeTimeout 是外部插件单次调用超时，均可选
+	pluginConfigMu.Lock()
+	pluginDir = config.GetString("external.dir")
+	executeTimeout = config.GetDuration("external.executeTimeout")
+	pluginConfigMu.Unlock()
+
 	log.Infow("plugin config file loaded", "path", configPath, "plugin_count", len(configs), "builtin_plugin_count", len(builtins))
 	return configs, builtins, nil
 }
@@ -144,3 +155,19 @@ func GetBuiltinConfig() map[string]any {
 	maps.Copy(result, builtinConfigs)
 	return result
 }
+
+// GetPluginDir 获取外部插件目录（线程安全），未配置时返回空字符串
+// 外部插件目录只在启动时加载，修改后需要重启生效
+func GetPluginDir() string {
+	pluginConfigMu.RLock()
+	defer pluginConfigMu.RUnlock()
+	return pluginDir
+}
+
+// GetExecuteTimeout 获取外部插件单次调用的超时时间（线程安全），未配置时返回 0
+// 只在插件启动时读取，修改后需要重启生效
+func GetExecuteTimeout() time.Duration {
+	pluginConfigMu.RLock()
+	defer pluginConfigMu.RUnlock()
+	return executeTimeout
+}
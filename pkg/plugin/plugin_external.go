@@ -0,0 +1,423 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	pluginv1 "github.com/arcentrix/arcentra/api/plugin/v1"
+	"github.com/arcentrix/arcentra/pkg/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	// externalStopTimeout 插件进程收到 SIGTERM 后的退出等待时间
+	externalStopTimeout = 5 * time.Second
+	// externalRestartBackoff 每次重启前的等待时间基数（按重启次数线性增长）
+	externalRestartBackoff = 500 * time.Millisecond
+)
+
+// ExternalPlugin 通过子进程 + gRPC 运行的插件
+// 插件进程崩溃不会影响宿主进程；崩溃后的下一次调用会按 ManagerConfig.MaxRetries
+// 限制重启插件并重放 Init 配置
+type ExternalPlugin struct {
+	manifest *Manifest
+	// timeout 启动、握手、Init 和 Cleanup 的超时时间
+	timeout time.Duration
+	// executeTimeout 单次 Execute 的超时时间，0 表示不限制
+	executeTimeout time.Duration
+	// maxRestarts 连续重启的最大次数，执行成功后清零
+	maxRestarts int
+
+	mu           sync.Mutex
+	cmd          *exec.Cmd
+	conn         *grpc.ClientConn
+	client       pluginv1.PluginServiceClient
+	socketDir    string
+	exited       chan struct{} // 进程退出时关闭
+	info         *pluginv1.PluginInfo
+	capabilities []string
+	config       json.RawMessage // 最近一次 Init 的配置，重启后重放
+	restarts     int
+	closed       bool
+}
+
+// NewExternalPlugin 启动插件进程并完成握手
+func NewExternalPlugin(manifest *Manifest, config *ManagerConfig) (*ExternalPlugin, error) {
+	if manifest == nil {
+		return nil, errors.New("manifest is required")
+	}
+	p := &ExternalPlugin{
+		manifest:    manifest,
+		timeout:     30 * time.Second,
+		maxRestarts: 3,
+	}
+	if config != nil {
+		if config.Timeout > 0 {
+			p.timeout = config.Timeout
+		}
+		if config.MaxRetries > 0 {
+			p.maxRestarts = config.MaxRetries
+		}
+		p.executeTimeout = config.ExecuteTimeout
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.startLocked(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Name 返回插件名称
+func (p *ExternalPlugin) Name() string { return p.manifest.Name }
+
+// Description 返回插件描述
+func (p *ExternalPlugin) Description() string {
+	if d := p.handshakeInfo().GetDescription(); d != "" {
+		return d
+	}
+	return p.manifest.Description
+}
+
+// Version 返回插件版本
+func (p *ExternalPlugin) Version() string {
+	if v := p.handshakeInfo().GetVersion(); v != "" {
+		return v
+	}
+	return p.manifest.Version
+}
+
+// Type 返回插件类型
+func (p *ExternalPlugin) Type() Type {
+	if t := Type(p.handshakeInfo().GetType()); t != TypeUnspecified {
+		return t
+	}
+	return StringToPluginType(p.manifest.Type)
+}
+
+// Author 返回插件作者
+func (p *ExternalPlugin) Author() string { return p.handshakeInfo().GetAuthor() }
+
+// Repository 返回插件仓库地址
+func (p *ExternalPlugin) Repository() string { return p.handshakeInfo().GetRepository() }
+
+// Capabilities 返回插件声明支持的 action，为空表示不限制
+func (p *ExternalPlugin) Capabilities() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.capabilities)
+}
+
+// Init 初始化插件，配置会在插件重启后重放
+func (p *ExternalPlugin) Init(config json.RawMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.ensureRunningLocked(); err != nil {
+		return err
+	}
+	p.config = config
+	return p.initLocked()
+}
+
+// Execute 通过 gRPC 执行插件操作
+func (p *ExternalPlugin) Execute(action string, params json.RawMessage, opts json.RawMessage) (json.RawMessage, error) {
+	return p.ExecuteContext(context.Background(), action, params, opts)
+}
+
+// ExecuteContext 通过 gRPC 执行插件操作，ctx 取消或超过 ManagerConfig.ExecuteTimeout 时中止调用
+func (p *ExternalPlugin) ExecuteContext(
+	ctx context.Context,
+	action string,
+	params json.RawMessage,
+	opts json.RawMessage,
+) (json.RawMessage, error) {
+	p.mu.Lock()
+	if err := p.ensureRunningLocked(); err != nil {
+		p.mu.Unlock()
+		return nil, err
+	}
+	if len(p.capabilities) > 0 && !slices.Contains(p.capabilities, action) {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("action %s is not supported by plugin %s", action, p.manifest.Name)
+	}
+	client, exited := p.client, p.exited
+	p.mu.Unlock()
+
+	// 插件进程退出时立即结束调用，而不是等待 RPC 超时
+	var cancel context.CancelFunc
+	if p.executeTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, p.executeTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+	go func() {
+		select {
+		case <-exited:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	resp, err := client.Execute(ctx, &pluginv1.ExecuteRequest{Action: action, Params: params, Opts: opts})
+	if err != nil {
+		// 连接断开可能早于进程退出被观察到，短暂等待以区分崩溃和调用失败
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("call plugin %s action %s: %w", p.manifest.Name, action, ctxErr)
+		}
+		select {
+		case <-exited:
+			return nil, fmt.Errorf("plugin %s crashed during action %s: %w", p.manifest.Name, action, err)
+		case <-time.After(time.Second):
+			return nil, fmt.Errorf("call plugin %s: %w", p.manifest.Name, err)
+		}
+	}
+
+	p.mu.Lock()
+	p.restarts = 0
+	p.mu.Unlock()
+
+	if resp.GetError() != "" {
+		return resp.GetResult(), errors.New(resp.GetError())
+	}
+	return resp.GetResult(), nil
+}
+
+// Cleanup 调用插件清理并停止插件进程
+func (p *ExternalPlugin) Cleanup() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	var cleanupErr error
+	if p.running() {
+		ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+		resp, err := p.client.Cleanup(ctx, &pluginv1.CleanupRequest{})
+		cancel()
+		switch {
+		case err != nil:
+			cleanupErr = fmt.Errorf("cleanup plugin %s: %w", p.manifest.Name, err)
+		case resp.GetError() != "":
+			cleanupErr = errors.New(resp.GetError())
+		}
+	}
+	p.stopLocked()
+	return cleanupErr
+}
+
+func (p *ExternalPlugin) handshakeInfo() *pluginv1.PluginInfo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.info
+}
+
+// running 判断插件进程是否存活，调用方需持有锁
+func (p *ExternalPlugin) running() bool {
+	if p.exited == nil {
+		return false
+	}
+	select {
+	case <-p.exited:
+		return false
+	default:
+		return true
+	}
+}
+
+// ensureRunningLocked 插件进程已退出时重启插件
+func (p *ExternalPlugin) ensureRunningLocked() error {
+	if p.closed {
+		return fmt.Errorf("plugin %s is closed", p.manifest.Name)
+	}
+	if p.running() {
+		return nil
+	}
+	if p.restarts >= p.maxRestarts {
+		return fmt.Errorf("plugin %s crashed %d times in a row, giving up", p.manifest.Name, p.restarts)
+	}
+	p.restarts++
+	log.Warnw("plugin process exited, restarting", "plugin", p.manifest.Name, "attempt", p.restarts)
+	time.Sleep(time.Duration(p.restarts) * externalRestartBackoff)
+
+	p.stopLocked()
+	if err := p.startLocked(); err != nil {
+		return err
+	}
+	if p.config != nil {
+		return p.initLocked()
+	}
+	return nil
+}
+
+// startLocked 启动插件进程并握手
+func (p *ExternalPlugin) startLocked() error {
+	if err := p.manifest.VerifyChecksum(); err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "arcentra-plugin-")
+	if err != nil {
+		return fmt.Errorf("create plugin socket dir: %w", err)
+	}
+	socket := filepath.Join(dir, "plugin.sock")
+
+	cmd := exec.Command(p.manifest.EntrypointPath(), p.manifest.Args...)
+	cmd.Dir = p.manifest.dir
+	cmd.Env = pluginEnv(p.manifest, socket)
+	cmd.Stdout = &pluginLogWriter{plugin: p.manifest.Name, stream: "stdout"}
+	cmd.Stderr = &pluginLogWriter{plugin: p.manifest.Name, stream: "stderr"}
+	if err := cmd.Start(); err != nil {
+		_ = os.RemoveAll(dir)
+		return fmt.Errorf("start plugin %s: %w", p.manifest.Name, err)
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		err := cmd.Wait()
+		log.Infow("plugin process exited", "plugin", p.manifest.Name, "pid", cmd.Process.Pid, "error", err)
+		close(exited)
+	}()
+
+	p.cmd, p.exited, p.socketDir = cmd, exited, dir
+
+	conn, err := grpc.NewClient("unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		p.stopLocked()
+		return fmt.Errorf("connect plugin %s: %w", p.manifest.Name, err)
+	}
+	p.conn, p.client = conn, pluginv1.NewPluginServiceClient(conn)
+
+	if err := p.handshakeLocked(); err != nil {
+		p.stopLocked()
+		return err
+	}
+	log.Infow("plugin process started", "plugin", p.manifest.Name, "pid", cmd.Process.Pid, "version", p.info.GetVersion())
+	return nil
+}
+
+// pluginEnv 返回插件进程的环境变量：只传递 PATH、HOME、握手变量和 manifest
+// 声明的变量，不继承宿主进程的环境（其中可能包含凭证）
+func pluginEnv(manifest *Manifest, socket string) []string {
+	env := make([]string, 0, len(manifest.Env)+4)
+	for _, key := range []string{"PATH", "HOME"} {
+		if v, ok := os.LookupEnv(key); ok {
+			env = append(env, key+"="+v)
+		}
+	}
+	env = append(env, MagicCookieKey+"="+MagicCookieValue, SocketEnv+"="+socket)
+	for k, v := range manifest.Env {
+		env = append(env, k+"="+v)
+	}
+	return env
+}
+
+// handshakeLocked 等待插件监听 socket 并校验协议版本与名称
+func (p *ExternalPlugin) handshakeLocked() error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	exited := p.exited
+	go func() {
+		select {
+		case <-exited:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	resp, err := p.client.Handshake(ctx, &pluginv1.HandshakeRequest{ProtocolVersion: ProtocolVersion}, grpc.WaitForReady(true))
+	if err != nil {
+		return fmt.Errorf("handshake with plugin %s: %w", p.manifest.Name, err)
+	}
+	if resp.GetProtocolVersion() != ProtocolVersion {
+		return fmt.Errorf("plugin %s speaks protocol %d, expected %d", p.manifest.Name, resp.GetProtocolVersion(), ProtocolVersion)
+	}
+	if name := resp.GetInfo().GetName(); name != p.manifest.Name {
+		return fmt.Errorf("plugin name mismatch: manifest %s, plugin %s", p.manifest.Name, name)
+	}
+	p.info = resp.GetInfo()
+	p.capabilities = resp.GetCapabilities()
+	return nil
+}
+
+func (p *ExternalPlugin) initLocked() error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	resp, err := p.client.Init(ctx, &pluginv1.InitRequest{Config: p.config})
+	if err != nil {
+		return fmt.Errorf("init plugin %s: %w", p.manifest.Name, err)
+	}
+	if resp.GetError() != "" {
+		return errors.New(resp.GetError())
+	}
+	return nil
+}
+
+// stopLocked 关闭连接并终止插件进程
+func (p *ExternalPlugin) stopLocked() {
+	if p.conn != nil {
+		_ = p.conn.Close()
+		p.conn, p.client = nil, nil
+	}
+	if p.running() {
+		_ = p.cmd.Process.Signal(syscall.SIGTERM)
+		select {
+		case <-p.exited:
+		case <-time.After(externalStopTimeout):
+			_ = p.cmd.Process.Kill()
+			<-p.exited
+		}
+	}
+	if p.socketDir != "" {
+		_ = os.RemoveAll(p.socketDir)
+		p.socketDir = ""
+	}
+}
+
+// pluginLogWriter 将插件进程的输出按行写入宿主日志
+type pluginLogWriter struct {
+	plugin string
+	stream string
+	buf    []byte
+}
+
+func (w *pluginLogWriter) Write(b []byte) (int, error) {
+	w.buf = append(w.buf, b...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if line := bytes.TrimSpace(w.buf[:i]); len(line) > 0 {
+			log.Infow("plugin output", "plugin", w.plugin, "stream", w.stream, "line", string(line))
+		}
+		w.buf = w.buf[i+1:]
+	}
+	return len(b), nil
+}
@@ -0,0 +1,215 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// helperPlugin is served by the test binary itself when launched as a plugin.
+type helperPlugin struct{ prefix string }
+
+func (p *helperPlugin) Name() string        { return "echo" }
+func (p *helperPlugin) Description() string { return "echo plugin" }
+func (p *helperPlugin) Version() string     { return "1.2.3" }
+func (p *helperPlugin) Type() Type          { return TypeCustom }
+func (p *helperPlugin) Author() string      { return "arcentra" }
+func (p *helperPlugin) Repository() string  { return "" }
+func (p *helperPlugin) Cleanup() error      { return nil }
+
+func (p *helperPlugin) Init(config json.RawMessage) error {
+	var cfg struct {
+		Prefix string `json:"prefix"`
+	}
+	if err := json.Unmarshal(config, &cfg); err != nil {
+		return err
+	}
+	p.prefix = cfg.Prefix
+	return nil
+}
+
+func (p *helperPlugin) Execute(action string, params json.RawMessage, _ json.RawMessage) (json.RawMessage, error) {
+	switch action {
+	case "echo":
+		return json.RawMessage(`"` + p.prefix + strings.Trim(string(params), `"`) + `"`), nil
+	case "fail":
+		return nil, errors.New("boom")
+	case "crash":
+		os.Exit(2)
+	case "env":
+		return json.Marshal(os.Getenv(strings.Trim(string(params), `"`)))
+	case "sleep":
+		time.Sleep(2 * time.Second)
+		return nil, nil
+	}
+	return nil, errors.New("unknown action")
+}
+
+// TestHelperPlugin is not a real test: it runs the helper plugin when the
+// test binary is launched by an ExternalPlugin.
+func TestHelperPlugin(t *testing.T) {
+	if os.Getenv("ARCENTRA_TEST_PLUGIN") != "1" {
+		t.Skip("helper process")
+	}
+	if err := Serve(&helperPlugin{}, &ServeOptions{Capabilities: []string{"echo", "fail", "crash", "env", "sleep"}}); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func helperManifest(t *testing.T) *Manifest {
+	t.Helper()
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Manifest{
+		Name:       "echo",
+		Entrypoint: exe,
+		Args:       []string{"-test.run=^TestHelperPlugin$"},
+		Env:        map[string]string{"ARCENTRA_TEST_PLUGIN": "1"},
+		dir:        t.TempDir(),
+	}
+}
+
+func TestExternalPlugin(t *testing.T) {
+	p, err := NewExternalPlugin(helperManifest(t), &ManagerConfig{Timeout: 10 * time.Second, MaxRetries: 2})
+	if err != nil {
+		t.Fatalf("NewExternalPlugin() error = %v", err)
+	}
+	defer func() { _ = p.Cleanup() }()
+
+	if p.Version() != "1.2.3" || p.Type() != TypeCustom {
+		t.Errorf("handshake info = %s/%v", p.Version(), p.Type())
+	}
+	if err := p.Init(json.RawMessage(`{"prefix":"hi "}`)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	out, err := p.Execute("echo", json.RawMessage(`"there"`), nil)
+	if err != nil || string(out) != `"hi there"` {
+		t.Fatalf("Execute(echo) = %s, %v", out, err)
+	}
+	if _, err := p.Execute("fail", nil, nil); err == nil || err.Error() != "boom" {
+		t.Errorf("Execute(fail) error = %v, want boom", err)
+	}
+	if _, err := p.Execute("deploy", nil, nil); err == nil {
+		t.Errorf("Execute() expected error for unsupported action")
+	}
+
+	// A crash fails the call; the next call restarts the plugin and replays Init.
+	if _, err := p.Execute("crash", nil, nil); err == nil {
+		t.Fatalf("Execute(crash) expected error")
+	}
+	out, err = p.Execute("echo", json.RawMessage(`"again"`), nil)
+	if err != nil || string(out) != `"hi again"` {
+		t.Fatalf("Execute(echo) after crash = %s, %v", out, err)
+	}
+}
+
+func TestExternalPluginEnv(t *testing.T) {
+	t.Setenv("ARCENTRA_DB_PASSWORD", "s3cret")
+	p, err := NewExternalPlugin(helperManifest(t), &ManagerConfig{Timeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("NewExternalPlugin() error = %v", err)
+	}
+	defer func() { _ = p.Cleanup() }()
+
+	for key, want := range map[string]string{
+		"ARCENTRA_DB_PASSWORD": "",
+		"ARCENTRA_TEST_PLUGIN": "1",
+		"PATH":                 os.Getenv("PATH"),
+	} {
+		out, err := p.Execute("env", json.RawMessage(`"`+key+`"`), nil)
+		if err != nil || string(out) != `"`+want+`"` {
+			t.Errorf("plugin env %s = %s, %v; want %q", key, out, err, want)
+		}
+	}
+}
+
+func TestExternalPluginExecuteContext(t *testing.T) {
+	p, err := NewExternalPlugin(helperManifest(t), &ManagerConfig{
+		Timeout:        10 * time.Second,
+		ExecuteTimeout: 200 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewExternalPlugin() error = %v", err)
+	}
+	defer func() { _ = p.Cleanup() }()
+
+	if _, err := p.Execute("sleep", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Execute(sleep) error = %v, want context.DeadlineExceeded", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ExecuteContext(ctx, p, "echo", json.RawMessage(`"x"`), nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ExecuteContext(cancelled) error = %v, want context.Canceled", err)
+	}
+}
+
+func TestManagerLoadsExternalPlugins(t *testing.T) {
+	dir := t.TempDir()
+	manifest := helperManifest(t)
+	pluginDir := filepath.Join(dir, "echo")
+	if err := os.MkdirAll(pluginDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(manifest)
+	if err := os.WriteFile(filepath.Join(pluginDir, ManifestFile), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(&ManagerConfig{Timeout: 10 * time.Second, MaxRetries: 1, PluginDir: dir})
+	defer func() { _ = m.Clear() }()
+	if n, errs := m.registerExternalPlugins(dir, map[string]any{"echo": map[string]any{"prefix": "> "}}); n != 1 {
+		t.Fatalf("registerExternalPlugins() = %d, %v", n, errs)
+	}
+
+	out, err := m.SafeExecute("echo", "echo", json.RawMessage(`"x"`), nil)
+	if err != nil || string(out) != `"> x"` {
+		t.Fatalf("SafeExecute() = %s, %v", out, err)
+	}
+}
+
+func TestManifestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       Manifest
+		wantErr bool
+	}{
+		{"ok", Manifest{Name: "a", Entrypoint: "bin/a", Type: "notify"}, false},
+		{"missing name", Manifest{Entrypoint: "bin/a"}, true},
+		{"missing entrypoint", Manifest{Name: "a"}, true},
+		{"bad type", Manifest{Name: "a", Entrypoint: "bin/a", Type: "nope"}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.m.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	m := Manifest{Name: "a", Entrypoint: "bin/a", dir: "/opt/plugins/a"}
+	if got := m.EntrypointPath(); got != "/opt/plugins/a/bin/a" {
+		t.Errorf("EntrypointPath() = %s", got)
+	}
+}
@@ -15,6 +15,7 @@
 package plugin
 
 import (
+	"context"
 	"encoding/json"
 )
 
@@ -83,6 +84,19 @@ type Plugin interface {
 	Cleanup() error
 }
 
+// ContextExecutor 由支持取消的插件实现（如外部插件），ctx 取消或超时时中止调用
+type ContextExecutor interface {
+	ExecuteContext(ctx context.Context, action string, params json.RawMessage, opts json.RawMessage) (json.RawMessage, error)
+}
+
+// ExecuteContext 执行插件操作，插件实现 ContextExecutor 时传递 ctx
+func ExecuteContext(ctx context.Context, p Plugin, action string, params json.RawMessage, opts json.RawMessage) (json.RawMessage, error) {
+	if ce, ok := p.(ContextExecutor); ok {
+		return ce.ExecuteContext(ctx, action, params, opts)
+	}
+	return p.Execute(action, params, opts)
+}
+
 // Info 包含插件的元信息
 type Info struct {
 	Name        string `json:"name"`
@@ -43,8 +43,12 @@ type Manager struct {
 type ManagerConfig struct {
 	// 超时时间
 	Timeout time.Duration
-	// 最大重试次数
+	// 最大重试次数（外部插件进程崩溃后的最大连续重启次数）
 	MaxRetries int
+	// 外部插件单次 Execute 的超时时间，0 表示只受调用方 ctx 限制
+	ExecuteTimeout time.Duration
+	// 外部插件目录，每个子目录包含 manifest.json 和插件可执行文件，为空时不加载
+	PluginDir string
 }
 
 // NewManager 创建新的插件管理器
@@ -118,38 +122,18 @@ func (m *Manager) registerPluginLocked(name string, plugin Plugin, config *Runti
 }
 
 // RegisterPluginsFromRegistry 从全局注册表注册所有插件
-// 这个方法会从全局注册表中获取所有已注册的插件并初始化它们
+// 这个方法会从全局注册表中获取所有已注册的插件并初始化它们，
+// 配置了 PluginDir 时还会加载插件目录中的外部（进程外）插件
 // pluginConfigs 是从配置文件读取的插件配置，格式为 map[插件名称]配置对象
 func (m *Manager) RegisterPluginsFromRegistry(pluginConfigs map[string]any) error {
 	plugins := ListPlugins()
 	var errors []error
 
 	for name, plugin := range plugins {
-		// 从配置文件中读取该插件的配置
-		var configJSON json.RawMessage
-		if pluginConfigs != nil {
-			if pluginConfig, exists := pluginConfigs[name]; exists {
-				// 将配置对象转换为 JSON
-				configBytes, err := sonic.Marshal(pluginConfig)
-				if err != nil {
-					log.Warnw("failed to marshal plugin config", "plugin", name, "error", err)
-					configJSON = []byte("{}")
-				} else {
-					configJSON = configBytes
-				}
-			} else {
-				// 配置文件中没有该插件的配置，使用空配置
-				configJSON = []byte("{}")
-			}
-		} else {
-			// 没有提供配置文件，使用空配置
-			configJSON = []byte("{}")
-		}
-
 		// 创建运行时配置
 		config := &RuntimePluginConfig{
 			Name:   name,
-			Config: configJSON,
+			Config: pluginConfigJSON(pluginConfigs, name),
 		}
 
 		if err := m.RegisterPlugin(name, plugin, config); err != nil {
@@ -158,14 +142,76 @@ func (m *Manager) RegisterPluginsFromRegistry(pluginConfigs map[string]any) erro
 		}
 	}
 
+	external := 0
+	if m.config.PluginDir != "" {
+		var errs []error
+		external, errs = m.registerExternalPlugins(m.config.PluginDir, pluginConfigs)
+		errors = append(errors, errs...)
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("failed to register %d plugin(s): %v", len(errors), errors)
 	}
 
-	log.Infow("registered plugins from registry", "count", len(plugins))
+	log.Infow("registered plugins from registry", "count", len(plugins), "external", external)
 	return nil
 }
 
+// registerExternalPlugins 启动并注册插件目录中的外部插件，返回成功注册的数量
+func (m *Manager) registerExternalPlugins(dir string, pluginConfigs map[string]any) (int, []error) {
+	manifests, err := DiscoverManifests(dir)
+	var errors []error
+	if err != nil {
+		log.Errorw("failed to load external plugin manifests", "dir", dir, "error", err)
+		errors = append(errors, err)
+	}
+
+	count := 0
+	for _, manifest := range manifests {
+		name := manifest.Name
+		if _, err := m.GetPlugin(name); err == nil {
+			errors = append(errors, fmt.Errorf("plugin %s: already registered", name))
+			continue
+		}
+
+		plugin, err := NewExternalPlugin(manifest, m.config)
+		if err != nil {
+			log.Errorw("failed to start external plugin", "plugin", name, "error", err)
+			errors = append(errors, fmt.Errorf("plugin %s: %w", name, err))
+			continue
+		}
+
+		config := &RuntimePluginConfig{
+			Name:    name,
+			Version: manifest.Version,
+			Type:    manifest.Type,
+			Config:  pluginConfigJSON(pluginConfigs, name),
+		}
+		if err := m.RegisterPlugin(name, plugin, config); err != nil {
+			_ = plugin.Cleanup()
+			log.Errorw("failed to register external plugin", "plugin", name, "error", err)
+			errors = append(errors, fmt.Errorf("plugin %s: %w", name, err))
+			continue
+		}
+		count++
+	}
+	return count, errors
+}
+
+// pluginConfigJSON 从配置文件读取插件配置并转换为 JSON，没有配置时返回空对象
+func pluginConfigJSON(pluginConfigs map[string]any, name string) json.RawMessage {
+	pluginConfig, exists := pluginConfigs[name]
+	if !exists {
+		return []byte("{}")
+	}
+	configBytes, err := sonic.Marshal(pluginConfig)
+	if err != nil {
+		log.Warnw("failed to marshal plugin config", "plugin", name, "error", err)
+		return []byte("{}")
+	}
+	return configBytes
+}
+
 // UnregisterPlugin 取消注册一个插件
 func (m *Manager) UnregisterPlugin(name string) error {
 	m.mu.Lock()
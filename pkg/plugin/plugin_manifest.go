@@ -0,0 +1,145 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bytedance/sonic"
+)
+
+// ManifestFile 外部插件目录中的清单文件名
+const ManifestFile = "manifest.json"
+
+// Manifest 描述一个外部（进程外）插件
+// 插件目录结构：<dir>/<plugin>/manifest.json + 可执行文件
+type Manifest struct {
+	// Name 插件名称，必须与插件握手返回的名称一致
+	Name string `json:"name"`
+	// Version 插件版本
+	Version string `json:"version"`
+	// Description 插件描述
+	Description string `json:"description,omitempty"`
+	// Type 插件类型（source, build, notify 等）
+	Type string `json:"type,omitempty"`
+	// Entrypoint 可执行文件路径，相对路径基于清单所在目录
+	Entrypoint string `json:"entrypoint"`
+	// Args 启动参数
+	Args []string `json:"args,omitempty"`
+	// Env 额外的环境变量
+	Env map[string]string `json:"env,omitempty"`
+	// Checksum 可执行文件的 sha256（十六进制），设置后启动前校验
+	Checksum string `json:"checksum,omitempty"`
+
+	// dir 清单所在目录
+	dir string
+}
+
+// LoadManifest 读取并校验插件清单
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	var m Manifest
+	if err := sonic.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse manifest %s: %w", path, err)
+	}
+	m.dir = filepath.Dir(path)
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("manifest %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// Validate 校验清单字段
+func (m *Manifest) Validate() error {
+	if strings.TrimSpace(m.Name) == "" {
+		return errors.New("name is required")
+	}
+	if strings.TrimSpace(m.Entrypoint) == "" {
+		return errors.New("entrypoint is required")
+	}
+	if m.Type != "" && !IsValidPluginType(m.Type) {
+		return fmt.Errorf("invalid plugin type: %s", m.Type)
+	}
+	return nil
+}
+
+// EntrypointPath 返回可执行文件的绝对路径
+func (m *Manifest) EntrypointPath() string {
+	if filepath.IsAbs(m.Entrypoint) {
+		return m.Entrypoint
+	}
+	return filepath.Join(m.dir, m.Entrypoint)
+}
+
+// VerifyChecksum 校验可执行文件的 sha256，未设置 Checksum 时跳过
+func (m *Manifest) VerifyChecksum() error {
+	if m.Checksum == "" {
+		return nil
+	}
+	f, err := os.Open(m.EntrypointPath())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(got, m.Checksum) {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", m.Name, m.Checksum, got)
+	}
+	return nil
+}
+
+// DiscoverManifests 扫描插件目录，加载每个子目录中的 manifest.json
+// 单个插件清单无效时跳过并返回汇总错误，不影响其它插件
+func DiscoverManifests(dir string) ([]*Manifest, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read plugin dir: %w", err)
+	}
+
+	var (
+		manifests []*Manifest
+		errs      []error
+	)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name(), ManifestFile)
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		m, err := LoadManifest(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		manifests = append(manifests, m)
+	}
+	return manifests, errors.Join(errs...)
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	pluginv1 "github.com/arcentrix/arcentra/api/plugin/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// ProtocolVersion 进程外插件协议版本，握手时双方必须一致
+	ProtocolVersion = 1
+
+	// MagicCookieKey / MagicCookieValue 由 Manager 启动插件时注入，
+	// 用于防止插件二进制被直接执行
+	MagicCookieKey   = "ARCENTRA_PLUGIN_MAGIC_COOKIE"
+	MagicCookieValue = "6f1c0e0b8a1d4c7e9b2a3d5f7e8c9a1b"
+
+	// SocketEnv 插件需要监听的 unix socket 路径
+	SocketEnv = "ARCENTRA_PLUGIN_SOCKET"
+)
+
+// ServeOptions 插件服务可选项
+type ServeOptions struct {
+	// Capabilities 插件支持的 action 列表，为空表示不限制
+	Capabilities []string
+}
+
+// Serve 在插件二进制的 main 中调用，通过 gRPC 暴露插件实现
+// 阻塞直到 Manager 关闭连接或进程收到退出信号
+func Serve(p Plugin, opts *ServeOptions) error {
+	if os.Getenv(MagicCookieKey) != MagicCookieValue {
+		return errors.New("this binary is an arcentra plugin and must be launched by the plugin manager")
+	}
+	socket := os.Getenv(SocketEnv)
+	if socket == "" {
+		return fmt.Errorf("%s is not set", SocketEnv)
+	}
+	if opts == nil {
+		opts = &ServeOptions{}
+	}
+
+	_ = os.Remove(socket)
+	lis, err := net.Listen("unix", socket)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", socket, err)
+	}
+
+	srv := grpc.NewServer()
+	pluginv1.RegisterPluginServiceServer(srv, &pluginServer{plugin: p, capabilities: opts.Capabilities})
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		srv.GracefulStop()
+	}()
+
+	return srv.Serve(lis)
+}
+
+// pluginServer 将 Plugin 适配为 PluginService
+type pluginServer struct {
+	pluginv1.UnimplementedPluginServiceServer
+	plugin       Plugin
+	capabilities []string
+}
+
+func (s *pluginServer) Handshake(_ context.Context, req *pluginv1.HandshakeRequest) (*pluginv1.HandshakeResponse, error) {
+	if req.GetProtocolVersion() != ProtocolVersion {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"unsupported protocol version %d, plugin speaks %d", req.GetProtocolVersion(), ProtocolVersion)
+	}
+	return &pluginv1.HandshakeResponse{
+		ProtocolVersion: ProtocolVersion,
+		Info: &pluginv1.PluginInfo{
+			Name:        s.plugin.Name(),
+			Description: s.plugin.Description(),
+			Version:     s.plugin.Version(),
+			Type:        int32(s.plugin.Type()),
+			Author:      s.plugin.Author(),
+			Repository:  s.plugin.Repository(),
+		},
+		Capabilities: s.capabilities,
+	}, nil
+}
+
+func (s *pluginServer) Init(_ context.Context, req *pluginv1.InitRequest) (*pluginv1.InitResponse, error) {
+	if err := s.plugin.Init(req.GetConfig()); err != nil {
+		return &pluginv1.InitResponse{Error: err.Error()}, nil
+	}
+	return &pluginv1.InitResponse{}, nil
+}
+
+func (s *pluginServer) Execute(_ context.Context, req *pluginv1.ExecuteRequest) (resp *pluginv1.ExecuteResponse, err error) {
+	// 插件 panic 时返回错误而不是让进程退出
+	defer func() {
+		if r := recover(); r != nil {
+			resp = &pluginv1.ExecuteResponse{Error: fmt.Sprintf("plugin panicked during action %s: %v", req.GetAction(), r)}
+			err = nil
+		}
+	}()
+
+	result, execErr := s.plugin.Execute(req.GetAction(), req.GetParams(), req.GetOpts())
+	resp = &pluginv1.ExecuteResponse{Result: result}
+	if execErr != nil {
+		resp.Error = execErr.Error()
+	}
+	return resp, nil
+}
+
+func (s *pluginServer) Cleanup(context.Context, *pluginv1.CleanupRequest) (*pluginv1.CleanupResponse, error) {
+	if err := s.plugin.Cleanup(); err != nil {
+		return &pluginv1.CleanupResponse{Error: err.Error()}, nil
+	}
+	return &pluginv1.CleanupResponse{}, nil
+}
@@ -31,8 +31,10 @@ var ProviderSet = wire.NewSet(
 func ProvidePluginManager(pluginConfigs map[string]any) *Manager {
 	// Create plugin manager configuration
 	config := &ManagerConfig{
-		Timeout:    30 * time.Second,
-		MaxRetries: 3,
+		Timeout:        30 * time.Second,
+		MaxRetries:     3,
+		PluginDir:      GetPluginDir(),
+		ExecuteTimeout: GetExecuteTimeout(),
 	}
 
 	// Create plugin manager
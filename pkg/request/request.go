@@ -161,6 +161,10 @@ func (r *Request) do(ctx context.Context, method string) (*fasthttp.Response, er
 	resp := &fasthttp.Response{}
 	req.Header.SetMethod(method)
 	req.SetRequestURI(r.withQuery())
+	if len(req.URI().PathOriginal()) == 0 {
+		// the client sends the original path, which must not be empty
+		req.URI().SetPath("/")
+	}
 
 	for key, value := range r.Headers {
 		req.Header.Set(key, value)
@@ -287,10 +291,11 @@ func (r *Request) buildMultipartOrForm() ([]byte, string, error) {
 	return buf.Bytes(), writer.FormDataContentType(), nil
 }
 
-// client builds a fasthttp client with optional proxy.
+// client builds a fasthttp client with optional proxy. Path normalizing is
+// disabled so escaped segments such as GitLab's "group%2Fproject" survive.
 func client(proxy string) (*fasthttp.Client, error) {
 	if proxy == "" {
-		return &fasthttp.Client{}, nil
+		return &fasthttp.Client{DisablePathNormalizing: true}, nil
 	}
 	proxyURL, err := url.Parse(proxy)
 	if err != nil {
@@ -299,7 +304,10 @@ func client(proxy string) (*fasthttp.Client, error) {
 	if proxyURL.Scheme == "" {
 		return nil, fmt.Errorf("proxy url missing scheme")
 	}
-	return &fasthttp.Client{Dial: fasthttpproxy.FasthttpHTTPDialer(proxy)}, nil
+	return &fasthttp.Client{
+		Dial:                   fasthttpproxy.FasthttpHTTPDialer(proxy),
+		DisablePathNormalizing: true,
+	}, nil
 }
 
 // isValidMethod validates supported HTTP methods.
@@ -19,7 +19,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/arcentrix/arcentra/pkg/log"
@@ -39,9 +42,17 @@ type Containerd struct {
 	namespace  string
 	logger     log.Logger
 	config     *ContainerdConfig
+	mu         sync.RWMutex
 	containers map[string]containerd.Container
 }
 
+// containerLogDir is where container stdout/stderr is written; see GetLogs.
+const containerLogDir = "/var/log/containers"
+
+// containerHostsDir holds the /etc/hosts files of containers created with
+// CreateOptions.Hosts.
+const containerHostsDir = "/var/lib/arcentra/hosts"
+
 // ContainerdConfig containerd sandbox configuration
 type ContainerdConfig struct {
 	// UnixSocket is the containerd unix socket path
@@ -126,7 +137,10 @@ func (s *Containerd) Create(ctx context.Context, opts *CreateOptions) (string, e
 	// Prepare container spec
 	specOpts := []oci.SpecOpts{
 		oci.WithImageConfig(img),
-		oci.WithProcessArgs(append(opts.Command, opts.Args...)...),
+	}
+	// Without a command the image entrypoint runs
+	if args := append(opts.Command, opts.Args...); len(args) > 0 {
+		specOpts = append(specOpts, oci.WithProcessArgs(args...))
 	}
 
 	// Set environment variables
@@ -176,7 +190,18 @@ func (s *Containerd) Create(ctx context.Context, opts *CreateOptions) (string, e
 		// Default bridge network (containerd default)
 		// Additional network configuration can be added here
 	default:
-		return "", fmt.Errorf("unsupported network mode: %s", networkMode)
+		target, ok := strings.CutPrefix(networkMode, NetworkModeContainerPrefix)
+		if !ok {
+			return "", fmt.Errorf("unsupported network mode: %s", networkMode)
+		}
+		netnsPath, err := s.networkNamespacePath(ctx, target)
+		if err != nil {
+			return "", err
+		}
+		specOpts = append(specOpts, oci.WithLinuxNamespace(specs.LinuxNamespace{
+			Type: specs.NetworkNamespace,
+			Path: netnsPath,
+		}))
 	}
 
 	// Configure resources
@@ -189,20 +214,32 @@ func (s *Containerd) Create(ctx context.Context, opts *CreateOptions) (string, e
 	}
 
 	// Configure mounts
-	if len(opts.Mounts) > 0 {
-		mounts := make([]specs.Mount, 0, len(opts.Mounts))
-		for _, m := range opts.Mounts {
-			mountType := m.Type
-			if mountType == "" {
-				mountType = "bind"
-			}
-			mounts = append(mounts, specs.Mount{
-				Source:      m.Source,
-				Destination: m.Target,
-				Type:        mountType,
-				Options:     s.getMountOptions(m.ReadOnly),
-			})
+	mounts := make([]specs.Mount, 0, len(opts.Mounts)+1)
+	if len(opts.Hosts) > 0 {
+		hostsPath, err := writeHostsFile(containerID, opts.Hosts)
+		if err != nil {
+			return "", err
+		}
+		mounts = append(mounts, specs.Mount{
+			Source:      hostsPath,
+			Destination: "/etc/hosts",
+			Type:        "bind",
+			Options:     []string{"rbind", "ro"},
+		})
+	}
+	for _, m := range opts.Mounts {
+		mountType := m.Type
+		if mountType == "" {
+			mountType = "bind"
 		}
+		mounts = append(mounts, specs.Mount{
+			Source:      m.Source,
+			Destination: m.Target,
+			Type:        mountType,
+			Options:     s.getMountOptions(m.ReadOnly),
+		})
+	}
+	if len(mounts) > 0 {
 		specOpts = append(specOpts, oci.WithMounts(mounts))
 	}
 
@@ -213,10 +250,13 @@ func (s *Containerd) Create(ctx context.Context, opts *CreateOptions) (string, e
 		containerd.WithNewSpec(specOpts...),
 	)
 	if err != nil {
+		_ = os.Remove(containerHostsPath(containerID))
 		return "", fmt.Errorf("create container: %w", err)
 	}
 
+	s.mu.Lock()
 	s.containers[containerID] = container
+	s.mu.Unlock()
 
 	s.logger.Debugw("container created", "container_id", containerID, "image", image)
 
@@ -232,7 +272,11 @@ func (s *Containerd) Start(ctx context.Context, containerID string) error {
 
 	ctx = namespaces.WithNamespace(ctx, s.namespace)
 
-	task, err := container.NewTask(ctx, cio.NewCreator(cio.WithStdio))
+	// Write stdout/stderr to the log file read by GetLogs
+	if err := os.MkdirAll(containerLogDir, 0o755); err != nil {
+		return fmt.Errorf("create log dir: %w", err)
+	}
+	task, err := container.NewTask(ctx, cio.LogFile(containerLogPath(containerID)))
 	if err != nil {
 		return fmt.Errorf("create task: %w", err)
 	}
@@ -302,7 +346,8 @@ func (s *Containerd) Execute(ctx context.Context, containerID string, cmd []stri
 		result.Duration = result.EndTime.Sub(result.StartTime)
 		return result, err
 	}
-	defer func() { _, _ = task.Delete(ctx) }()
+	// Delete with a detached context so the task is reaped even after a timeout
+	defer func() { _, _ = task.Delete(context.WithoutCancel(ctx), containerd.WithProcessKill) }()
 
 	// Start task
 	if err = task.Start(ctx); err != nil {
@@ -313,6 +358,24 @@ func (s *Containerd) Execute(ctx context.Context, containerID string, cmd []stri
 		return result, err
 	}
 
+	if opts != nil && opts.TTY && opts.Resize != nil {
+		resizeDone := make(chan struct{})
+		defer close(resizeDone)
+		safe.Go(func() {
+			for {
+				select {
+				case size, ok := <-opts.Resize:
+					if !ok {
+						return
+					}
+					_ = task.Resize(ctx, uint32(size.Cols), uint32(size.Rows))
+				case <-resizeDone:
+					return
+				}
+			}
+		})
+	}
+
 	// Wait for task to complete
 	statusC, err := task.Wait(ctx)
 	if err != nil {
@@ -323,8 +386,18 @@ func (s *Containerd) Execute(ctx context.Context, containerID string, cmd []stri
 		return result, err
 	}
 
-	// Get exit status
-	status := <-statusC
+	// Get exit status, killing the task when the timeout expires
+	var status containerd.ExitStatus
+	select {
+	case status = <-statusC:
+	case <-ctx.Done():
+		_ = task.Kill(context.WithoutCancel(ctx), unix.SIGKILL)
+		result.ExitCode = -1
+		result.Stderr = fmt.Sprintf("wait task: %v", ctx.Err())
+		result.EndTime = time.Now()
+		result.Duration = result.EndTime.Sub(result.StartTime)
+		return result, ctx.Err()
+	}
 	result.ExitCode = int32(status.ExitCode())
 	result.EndTime = time.Now()
 	result.Duration = result.EndTime.Sub(result.StartTime)
@@ -400,7 +473,11 @@ func (s *Containerd) Remove(ctx context.Context, containerID string) error {
 		return fmt.Errorf("delete container: %w", err)
 	}
 
+	s.mu.Lock()
 	delete(s.containers, containerID)
+	s.mu.Unlock()
+	_ = os.Remove(containerLogPath(containerID))
+	_ = os.Remove(containerHostsPath(containerID))
 
 	s.logger.Debugw("container removed", "container_id", containerID)
 
@@ -425,9 +502,7 @@ func (s *Containerd) GetLogs(ctx context.Context, containerID string, _ *LogOpti
 	// Get log file path
 	// Note: containerd stores logs in different locations depending on configuration
 	// This is a simplified implementation. In production, you should use containerd's log API
-	logPath := fmt.Sprintf("/var/log/containers/%s.log", containerID)
-
-	file, err := os.Open(logPath)
+	file, err := os.Open(containerLogPath(containerID))
 	if err != nil {
 		return nil, fmt.Errorf("open log file: %w", err)
 	}
@@ -439,13 +514,22 @@ func (s *Containerd) GetLogs(ctx context.Context, containerID string, _ *LogOpti
 func (s *Containerd) Cleanup(ctx context.Context) error {
 	ctx = namespaces.WithNamespace(ctx, s.namespace)
 
+	s.mu.RLock()
+	ids := make([]string, 0, len(s.containers))
 	for containerID := range s.containers {
+		ids = append(ids, containerID)
+	}
+	s.mu.RUnlock()
+
+	for _, containerID := range ids {
 		// Try to stop and remove container
 		_ = s.Stop(ctx, containerID, 5*time.Second)
 		_ = s.Remove(ctx, containerID)
 	}
 
+	s.mu.Lock()
 	s.containers = make(map[string]containerd.Container)
+	s.mu.Unlock()
 
 	s.logger.Infow("sandbox cleanup completed")
 
@@ -467,13 +551,63 @@ func (s *Containerd) Close() error {
 
 // getContainer gets a container by ID
 func (s *Containerd) getContainer(containerID string) (containerd.Container, error) {
+	s.mu.RLock()
 	container, ok := s.containers[containerID]
+	s.mu.RUnlock()
 	if !ok {
 		return nil, fmt.Errorf("container not found: %s", containerID)
 	}
 	return container, nil
 }
 
+// networkNamespacePath returns the network namespace of a running container,
+// used to join it with the "container:<id>" network mode.
+func (s *Containerd) networkNamespacePath(ctx context.Context, containerID string) (string, error) {
+	container, err := s.getContainer(containerID)
+	if err != nil {
+		return "", err
+	}
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("get task of %s: %w", containerID, err)
+	}
+	return fmt.Sprintf("/proc/%d/ns/net", task.Pid()), nil
+}
+
+// containerLogPath returns the log file of a container.
+func containerLogPath(containerID string) string {
+	return filepath.Join(containerLogDir, containerID+".log")
+}
+
+// containerHostsPath returns the /etc/hosts file of a container.
+func containerHostsPath(containerID string) string {
+	return filepath.Join(containerHostsDir, containerID)
+}
+
+// writeHostsFile writes the /etc/hosts file of a container with the localhost
+// entries followed by hosts, sorted by hostname.
+func writeHostsFile(containerID string, hosts map[string]string) (string, error) {
+	if err := os.MkdirAll(containerHostsDir, 0o755); err != nil {
+		return "", fmt.Errorf("create hosts dir: %w", err)
+	}
+	names := make([]string, 0, len(hosts))
+	for name := range hosts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("127.0.0.1\tlocalhost\n::1\tlocalhost ip6-localhost ip6-loopback\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s\t%s\n", hosts[name], name)
+	}
+	path := containerHostsPath(containerID)
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return "", fmt.Errorf("write hosts file: %w", err)
+	}
+	return path, nil
+}
+
 // withResources configures resource limits
 func (s *Containerd) withResources(resources *Resources) oci.SpecOpts {
 	return func(_ context.Context, _ oci.Client, _ *containers.Container, spec *specs.Spec) error {
@@ -564,10 +698,12 @@ func parseCPU(cpu string) (int64, uint64) {
 	return 0, 0
 }
 
-// parseMemory parses memory string (e.g., "1G", "512M", "1024m") to bytes
+// parseMemory parses memory string (e.g., "1G", "512M", "1024m", "256Mi", "1GB") to bytes
 func parseMemory(memory string) int64 {
 	memory = strings.TrimSpace(memory)
 	memory = strings.ToUpper(memory)
+	// Accept Kubernetes ("Mi", "Gi") and byte ("MB", "GB") suffixes
+	memory = strings.TrimSuffix(strings.TrimSuffix(memory, "B"), "I")
 
 	var size int64
 	var unit string
@@ -154,6 +154,9 @@ func TestParseMemory(t *testing.T) {
 		{"512M", 512 * 1024 * 1024},
 		{"1024K", 1024 * 1024},
 		{"1024", 1024},
+		{"256Mi", 256 * 1024 * 1024},
+		{"2Gi", 2 * 1024 * 1024 * 1024},
+		{"1GB", 1024 * 1024 * 1024},
 	}
 
 	for _, tt := range tests {
@@ -40,7 +40,7 @@ func NewSandboxFromConfig(cfg *config.AgentConfig, logger log.Logger) (Sandbox,
 	case "containerd":
 		return NewContainerdSandboxFromConfig(&cfg.Agent.Sandbox.Containerd, logger)
 	case "kubernetes":
-		return nil, fmt.Errorf("kubernetes sandbox not implemented yet")
+		return NewKubernetesSandboxFromConfig(&cfg.Agent.Sandbox.Kubernetes, logger)
 	default:
 		return nil, fmt.Errorf("unsupported sandbox runtime: %s", runtime)
 	}
@@ -64,3 +64,23 @@ func NewContainerdSandboxFromConfig(cfg *config.ContainerdConfig, logger log.Log
 
 	return NewContainerdSandbox(sandboxConfig, logger)
 }
+
+// NewKubernetesSandboxFromConfig creates a kubernetes sandbox from configuration
+func NewKubernetesSandboxFromConfig(cfg *config.KubernetesConfig, logger log.Logger) (Sandbox, error) {
+	sandboxConfig := &KubernetesConfig{
+		Kubeconfig:    cfg.Kubeconfig,
+		Namespace:     cfg.Namespace,
+		PodNamePrefix: cfg.PodName,
+		DefaultImage:  cfg.Image,
+		NodeName:      cfg.NodeName,
+	}
+
+	if cfg.Resources.CPU != "" || cfg.Resources.Memory != "" {
+		sandboxConfig.Resources = &Resources{
+			CPU:    cfg.Resources.CPU,
+			Memory: cfg.Resources.Memory,
+		}
+	}
+
+	return NewKubernetesSandbox(sandboxConfig, logger)
+}
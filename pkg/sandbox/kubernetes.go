@@ -0,0 +1,1045 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arcentrix/arcentra/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// kubernetesContainerName is the name of the single container of a sandbox pod.
+	kubernetesContainerName = "sandbox"
+
+	// kubernetesManagedByLabel marks the pods created by the sandbox.
+	kubernetesManagedByLabel = "app.kubernetes.io/managed-by"
+	kubernetesManagedBy      = "arcentra-agent"
+
+	// kubernetesOwnerLabel identifies the agent that created a pod, so that
+	// Cleanup only removes pods of this agent.
+	kubernetesOwnerLabel = "arcentra.io/sandbox-owner"
+
+	// kubernetesPodStartTimeout bounds how long a pod may stay pending.
+	kubernetesPodStartTimeout = 5 * time.Minute
+	kubernetesPodPollInterval = time.Second
+
+	// kubernetesReapInterval is how often pods of this agent that it no
+	// longer tracks are deleted; kubernetesReapTimeout bounds one pass.
+	kubernetesReapInterval = 5 * time.Minute
+	kubernetesReapTimeout  = 30 * time.Second
+)
+
+// kubernetesIdleCommand keeps a pod running so that commands can be executed
+// in it. It exits on SIGTERM so pods are deleted without waiting for the
+// grace period.
+var kubernetesIdleCommand = []string{"sh", "-c", "trap 'exit 0' TERM; while :; do sleep 3600 & wait; done"}
+
+// Kubernetes implements Sandbox interface using Kubernetes pods. A sandbox
+// container is a pod with a single container, or an ephemeral container of a
+// running pod when created with the "container:<id>" network mode, so a job
+// runs its services and steps in one pod. Commands run through the pod exec
+// subresource and logs are read from the pod logs.
+type Kubernetes struct {
+	client     kubernetes.Interface
+	restConfig *rest.Config
+	namespace  string
+	owner      string
+	logger     log.Logger
+	config     *KubernetesConfig
+	mu         sync.RWMutex
+	pods       map[string]*kubernetesPod
+	stop       chan struct{}
+	stopOnce   sync.Once
+
+	// newExecutor opens a remote command stream for an exec URL.
+	newExecutor func(execURL *url.URL) (remotecommand.Executor, error)
+}
+
+// kubernetesPod is a sandbox container. The pod is submitted by Start, or by
+// the first Execute when the container is only used to run commands.
+// Ephemeral containers are added to the running pod podName instead.
+type kubernetesPod struct {
+	pod       *corev1.Pod
+	submitted bool
+	podName   string
+	ephemeral *corev1.EphemeralContainer
+}
+
+// target returns the pod and container names of the sandbox container.
+func (p *kubernetesPod) target() (string, string) {
+	if p.ephemeral != nil {
+		return p.podName, p.ephemeral.Name
+	}
+	return p.pod.Name, kubernetesContainerName
+}
+
+// KubernetesConfig kubernetes sandbox configuration
+type KubernetesConfig struct {
+	// Kubeconfig is the kubeconfig file; empty uses the in-cluster config,
+	// falling back to $KUBECONFIG and ~/.kube/config
+	Kubeconfig string
+
+	// Namespace is the namespace pods are created in
+	Namespace string
+
+	// PodNamePrefix prefixes the generated pod names
+	PodNamePrefix string
+
+	// DefaultImage is the default container image
+	DefaultImage string
+
+	// Resources are default resource limits
+	Resources *Resources
+
+	// Owner identifies this agent in the pod labels; defaults to the hostname
+	Owner string
+
+	// NodeName is the node the agent runs on; pods with hostPath volumes are
+	// pinned to it. Defaults to $NODE_NAME
+	NodeName string
+}
+
+// NewKubernetesSandbox creates a new kubernetes sandbox instance
+func NewKubernetesSandbox(config *KubernetesConfig, logger log.Logger) (*Kubernetes, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is required")
+	}
+
+	restConfig, err := kubernetesRESTConfig(config.Kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("load kubernetes config: %w", err)
+	}
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("create kubernetes client: %w", err)
+	}
+
+	return newKubernetesSandbox(client, restConfig, config, logger), nil
+}
+
+// newKubernetesSandbox creates a kubernetes sandbox on top of client.
+func newKubernetesSandbox(client kubernetes.Interface, restConfig *rest.Config, config *KubernetesConfig, logger log.Logger) *Kubernetes {
+	if config.Namespace == "" {
+		config.Namespace = "default"
+	}
+	if config.PodNamePrefix == "" {
+		config.PodNamePrefix = "arcentra"
+	}
+	if config.Owner == "" {
+		config.Owner, _ = os.Hostname()
+	}
+	if config.NodeName == "" {
+		config.NodeName = os.Getenv("NODE_NAME")
+	}
+
+	sb := &Kubernetes{
+		client:     client,
+		restConfig: restConfig,
+		namespace:  config.Namespace,
+		owner:      labelValue(config.Owner),
+		logger:     logger,
+		config:     config,
+		pods:       make(map[string]*kubernetesPod),
+		stop:       make(chan struct{}),
+	}
+	sb.newExecutor = func(execURL *url.URL) (remotecommand.Executor, error) {
+		return remotecommand.NewSPDYExecutor(sb.restConfig, "POST", execURL)
+	}
+
+	logger.Infow("kubernetes sandbox initialized",
+		"namespace", config.Namespace,
+		"owner", sb.owner,
+		"node", config.NodeName)
+
+	// Pods left by an earlier run of this agent are not tracked and would
+	// otherwise keep running until someone deletes them
+	sb.reap()
+	go sb.reapLoop()
+
+	return sb
+}
+
+// kubernetesRESTConfig loads the client configuration.
+func kubernetesRESTConfig(kubeconfig string) (*rest.Config, error) {
+	if kubeconfig != "" {
+		return clientcmd.BuildConfigFromFlags("", kubeconfig)
+	}
+	if cfg, err := rest.InClusterConfig(); err == nil {
+		return cfg, nil
+	}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{},
+	).ClientConfig()
+}
+
+// Create prepares a sandbox pod. The pod is submitted to the cluster by Start
+// or the first Execute.
+func (s *Kubernetes) Create(_ context.Context, opts *CreateOptions) (string, error) {
+	if opts == nil {
+		return "", fmt.Errorf("options are required")
+	}
+
+	image := opts.Image
+	if image == "" {
+		image = s.config.DefaultImage
+	}
+	if image == "" {
+		return "", fmt.Errorf("image is required")
+	}
+	if target, ok := strings.CutPrefix(opts.NetworkMode, NetworkModeContainerPrefix); ok {
+		return s.createEphemeral(target, image, opts)
+	}
+
+	podName := generatePodName(s.config.PodNamePrefix)
+	container := corev1.Container{
+		Name:       kubernetesContainerName,
+		Image:      image,
+		Command:    opts.Command,
+		Args:       opts.Args,
+		WorkingDir: opts.WorkingDir,
+		Env:        kubernetesEnv(opts.Env),
+	}
+	if opts.Privileged {
+		privileged := true
+		container.SecurityContext = &corev1.SecurityContext{Privileged: &privileged}
+	}
+
+	resources := opts.Resources
+	if resources == nil {
+		resources = s.config.Resources
+	}
+	if resources != nil {
+		requirements, err := kubernetesResources(resources)
+		if err != nil {
+			return "", err
+		}
+		container.Resources = requirements
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      podName,
+			Namespace: s.namespace,
+			Labels: map[string]string{
+				kubernetesManagedByLabel: kubernetesManagedBy,
+				kubernetesOwnerLabel:     s.owner,
+			},
+			// Caller labels may not be valid label values, so they are kept as annotations
+			Annotations: opts.Labels,
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers:    []corev1.Container{container},
+		},
+	}
+	if hostname := labelValue(opts.Hostname); hostname != "" && len(validation.IsDNS1123Label(hostname)) == 0 {
+		pod.Spec.Hostname = hostname
+	}
+
+	for i, m := range opts.Mounts {
+		volume, err := kubernetesVolume(fmt.Sprintf("mount-%d", i), m)
+		if err != nil {
+			return "", err
+		}
+		pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      volume.Name,
+			MountPath: m.Target,
+			ReadOnly:  m.ReadOnly,
+		})
+		if volume.HostPath != nil && pod.Spec.Affinity == nil {
+			// hostPath volumes are paths on the agent's node
+			if s.config.NodeName == "" {
+				return "", fmt.Errorf("mount %s needs the node name of the agent (set NodeName or $NODE_NAME)", m.Source)
+			}
+			pod.Spec.Affinity = nodeAffinity(s.config.NodeName)
+		}
+	}
+
+	if err := s.configureNetwork(pod, opts.NetworkMode); err != nil {
+		return "", err
+	}
+	pod.Spec.HostAliases = append(pod.Spec.HostAliases, kubernetesHostAliases(opts.Hosts)...)
+
+	s.mu.Lock()
+	s.pods[podName] = &kubernetesPod{pod: pod}
+	s.mu.Unlock()
+
+	s.logger.Debugw("pod prepared", "pod", podName, "image", image)
+
+	return podName, nil
+}
+
+// configureNetwork applies the network mode to the pod.
+func (s *Kubernetes) configureNetwork(pod *corev1.Pod, networkMode string) error {
+	switch networkMode {
+	case "", "bridge", "none":
+		return nil
+	case "host":
+		pod.Spec.HostNetwork = true
+		return nil
+	}
+	return fmt.Errorf("unsupported network mode: %s", networkMode)
+}
+
+// createEphemeral prepares a container that joins the running pod of target as
+// an ephemeral container, sharing its network namespace, volumes and host
+// aliases. Ephemeral containers take no resources of their own and end with
+// the pod.
+func (s *Kubernetes) createEphemeral(target, image string, opts *CreateOptions) (string, error) {
+	s.mu.RLock()
+	p, ok := s.pods[target]
+	if ok && p.ephemeral != nil {
+		p, ok = s.pods[p.podName]
+	}
+	var pod *corev1.Pod
+	if ok && p.submitted {
+		pod = p.pod
+	}
+	s.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("container not found: %s", target)
+	}
+	if pod == nil {
+		return "", fmt.Errorf("container %s is not started", target)
+	}
+
+	name := generatePodName(s.config.PodNamePrefix)
+	container := &corev1.EphemeralContainer{EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+		Name:       name,
+		Image:      image,
+		Command:    opts.Command,
+		Args:       opts.Args,
+		WorkingDir: opts.WorkingDir,
+		Env:        kubernetesEnv(opts.Env),
+	}}
+	if opts.Privileged {
+		privileged := true
+		container.SecurityContext = &corev1.SecurityContext{Privileged: &privileged}
+	}
+	for _, m := range opts.Mounts {
+		volume := podVolume(pod, m)
+		if volume == "" {
+			return "", fmt.Errorf("mount %s is not a volume of pod %s", m.Source, pod.Name)
+		}
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      volume,
+			MountPath: m.Target,
+			ReadOnly:  m.ReadOnly,
+		})
+	}
+
+	s.mu.Lock()
+	s.pods[name] = &kubernetesPod{podName: pod.Name, ephemeral: container}
+	s.mu.Unlock()
+
+	s.logger.Debugw("ephemeral container prepared", "pod", pod.Name, "container", name, "image", image)
+
+	return name, nil
+}
+
+// podVolume returns the pod volume that provides m: the hostPath volume of
+// its source, or the volume mounted at its target.
+func podVolume(pod *corev1.Pod, m Mount) string {
+	if m.Type == "" || m.Type == "bind" {
+		for _, v := range pod.Spec.Volumes {
+			if v.HostPath != nil && v.HostPath.Path == m.Source {
+				return v.Name
+			}
+		}
+		return ""
+	}
+	for _, vm := range pod.Spec.Containers[0].VolumeMounts {
+		if vm.MountPath == m.Target {
+			return vm.Name
+		}
+	}
+	return ""
+}
+
+// nodeAffinity requires the pod to run on node.
+func nodeAffinity(node string) *corev1.Affinity {
+	return &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchFields: []corev1.NodeSelectorRequirement{{
+					Key:      "metadata.name",
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{node},
+				}},
+			}},
+		},
+	}}
+}
+
+// kubernetesHostAliases groups hosts by IP, sorted for a stable pod spec.
+func kubernetesHostAliases(hosts map[string]string) []corev1.HostAlias {
+	byIP := make(map[string][]string)
+	for name, ip := range hosts {
+		byIP[ip] = append(byIP[ip], name)
+	}
+	aliases := make([]corev1.HostAlias, 0, len(byIP))
+	for ip, names := range byIP {
+		sort.Strings(names)
+		aliases = append(aliases, corev1.HostAlias{IP: ip, Hostnames: names})
+	}
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].IP < aliases[j].IP })
+	return aliases
+}
+
+// Start submits the pod, running its command, and waits until it is running
+func (s *Kubernetes) Start(ctx context.Context, containerID string) error {
+	p, err := s.getPod(containerID)
+	if err != nil {
+		return err
+	}
+	if err := s.submitContainer(ctx, p, false); err != nil {
+		return err
+	}
+
+	s.logger.Debugw("pod started", "pod", containerID)
+
+	return nil
+}
+
+// submitContainer submits a pod or an ephemeral container.
+func (s *Kubernetes) submitContainer(ctx context.Context, p *kubernetesPod, idle bool) error {
+	if p.ephemeral != nil {
+		return s.submitEphemeral(ctx, p, idle)
+	}
+	return s.submit(ctx, p, idle)
+}
+
+// submit creates the pod in the cluster unless it already exists and waits
+// until it is running. With idle the container command is replaced by a
+// command that keeps the pod running for Execute.
+func (s *Kubernetes) submit(ctx context.Context, p *kubernetesPod, idle bool) error {
+	s.mu.Lock()
+	if p.submitted {
+		s.mu.Unlock()
+		return nil
+	}
+	pod := p.pod.DeepCopy()
+	if idle {
+		pod.Spec.Containers[0].Command = kubernetesIdleCommand
+		pod.Spec.Containers[0].Args = nil
+	}
+	p.submitted = true
+	s.mu.Unlock()
+
+	created, err := s.client.CoreV1().Pods(s.namespace).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		s.mu.Lock()
+		p.submitted = false
+		s.mu.Unlock()
+		return fmt.Errorf("create pod: %w", err)
+	}
+
+	running, err := s.waitRunning(ctx, created.Name)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	p.pod = running
+	s.mu.Unlock()
+	return nil
+}
+
+// submitEphemeral adds the ephemeral container to its pod unless it was
+// already added and waits until it is running. With idle the container
+// command is replaced as in submit.
+func (s *Kubernetes) submitEphemeral(ctx context.Context, p *kubernetesPod, idle bool) error {
+	s.mu.Lock()
+	if p.submitted {
+		s.mu.Unlock()
+		return nil
+	}
+	container := p.ephemeral.DeepCopy()
+	if idle {
+		container.Command = kubernetesIdleCommand
+		container.Args = nil
+	}
+	p.submitted = true
+	s.mu.Unlock()
+
+	pods := s.client.CoreV1().Pods(s.namespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pod, err := pods.Get(ctx, p.podName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, *container)
+		_, err = pods.UpdateEphemeralContainers(ctx, p.podName, pod, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		s.mu.Lock()
+		p.submitted = false
+		s.mu.Unlock()
+		return fmt.Errorf("add ephemeral container: %w", err)
+	}
+	return s.waitEphemeralRunning(ctx, p.podName, container.Name)
+}
+
+// waitEphemeralRunning polls the pod until the ephemeral container is running.
+func (s *Kubernetes) waitEphemeralRunning(ctx context.Context, podName, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, kubernetesPodStartTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(kubernetesPodPollInterval)
+	defer ticker.Stop()
+	for {
+		pod, err := s.client.CoreV1().Pods(s.namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("get pod: %w", err)
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			return fmt.Errorf("pod %s exited: %s %s", podName, pod.Status.Phase, pod.Status.Message)
+		}
+		for _, st := range pod.Status.EphemeralContainerStatuses {
+			if st.Name != name {
+				continue
+			}
+			if st.State.Running != nil {
+				return nil
+			}
+			if t := st.State.Terminated; t != nil {
+				return fmt.Errorf("container %s exited before running: %s", name, t.Reason)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait for container %s: %w", name, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitRunning polls the pod until it is running.
+func (s *Kubernetes) waitRunning(ctx context.Context, podName string) (*corev1.Pod, error) {
+	ctx, cancel := context.WithTimeout(ctx, kubernetesPodStartTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(kubernetesPodPollInterval)
+	defer ticker.Stop()
+	for {
+		pod, err := s.client.CoreV1().Pods(s.namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("get pod: %w", err)
+		}
+		switch pod.Status.Phase {
+		case corev1.PodRunning:
+			return pod, nil
+		case corev1.PodSucceeded, corev1.PodFailed:
+			return nil, fmt.Errorf("pod %s exited before running: %s %s", podName, pod.Status.Phase, pod.Status.Message)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for pod %s: %w", podName, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// Execute executes a command in the sandbox pod through the exec subresource
+func (s *Kubernetes) Execute(ctx context.Context, containerID string, cmd []string, opts *ExecuteOptions) (*ExecuteResult, error) {
+	result := &ExecuteResult{
+		StartTime: time.Now(),
+	}
+	fail := func(err error) (*ExecuteResult, error) {
+		result.ExitCode = -1
+		result.Stderr = err.Error()
+		result.EndTime = time.Now()
+		result.Duration = result.EndTime.Sub(result.StartTime)
+		return result, err
+	}
+
+	if len(cmd) == 0 {
+		return fail(fmt.Errorf("command is required"))
+	}
+
+	p, err := s.getPod(containerID)
+	if err != nil {
+		return fail(err)
+	}
+
+	// Set execution timeout
+	if opts != nil && opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	if err := s.submitContainer(ctx, p, true); err != nil {
+		return fail(err)
+	}
+	podName, containerName := p.target()
+
+	streamOpts := remotecommand.StreamOptions{}
+	if opts != nil {
+		streamOpts.Stdin = opts.Stdin
+		streamOpts.Stdout = opts.Stdout
+		streamOpts.Stderr = opts.Stderr
+		streamOpts.Tty = opts.TTY
+		if opts.TTY && opts.Resize != nil {
+			streamOpts.TerminalSizeQueue = &terminalSizeQueue{ctx: ctx, sizes: opts.Resize}
+		}
+	}
+	executor, err := s.newExecutor(s.execURL(podName, containerName, execCommand(cmd, opts), streamOpts))
+	if err != nil {
+		return fail(fmt.Errorf("create executor: %w", err))
+	}
+
+	err = executor.StreamWithContext(ctx, streamOpts)
+	result.EndTime = time.Now()
+	result.Duration = result.EndTime.Sub(result.StartTime)
+
+	var exitErr utilexec.ExitError
+	switch {
+	case err == nil:
+		result.ExitCode = 0
+	case errors.As(err, &exitErr) && exitErr.Exited():
+		result.ExitCode = int32(exitErr.ExitStatus())
+	case ctx.Err() != nil:
+		return fail(fmt.Errorf("exec: %w", ctx.Err()))
+	default:
+		return fail(fmt.Errorf("exec: %w", err))
+	}
+
+	s.logger.Debugw("command executed",
+		"pod", podName,
+		"container", containerName,
+		"command", strings.Join(cmd, " "), "exit_code",
+		result.ExitCode, "duration", result.Duration)
+
+	return result, nil
+}
+
+// terminalSizeQueue feeds TTY size changes to the exec stream.
+type terminalSizeQueue struct {
+	ctx   context.Context
+	sizes <-chan TerminalSize
+}
+
+// Next blocks until the next size change; nil ends the queue.
+func (q *terminalSizeQueue) Next() *remotecommand.TerminalSize {
+	select {
+	case size, ok := <-q.sizes:
+		if !ok {
+			return nil
+		}
+		return &remotecommand.TerminalSize{Width: size.Cols, Height: size.Rows}
+	case <-q.ctx.Done():
+		return nil
+	}
+}
+
+// execURL returns the exec subresource URL of a pod container.
+func (s *Kubernetes) execURL(podName, container string, cmd []string, opts remotecommand.StreamOptions) *url.URL {
+	params := &corev1.PodExecOptions{
+		Container: container,
+		Command:   cmd,
+		Stdin:     opts.Stdin != nil,
+		Stdout:    opts.Stdout != nil,
+		Stderr:    opts.Stderr != nil && !opts.Tty,
+		TTY:       opts.Tty,
+	}
+	query, _ := scheme.ParameterCodec.EncodeParameters(params, corev1.SchemeGroupVersion)
+
+	u, err := url.Parse(s.restConfig.Host)
+	if err != nil || u.Host == "" {
+		u = &url.URL{Scheme: "https", Host: s.restConfig.Host}
+	}
+	u.Path = strings.TrimRight(u.Path, "/") + fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/exec", s.namespace, podName)
+	u.RawQuery = query.Encode()
+	return u
+}
+
+// execCommand applies the environment and working directory of opts to cmd;
+// the exec subresource supports neither.
+func execCommand(cmd []string, opts *ExecuteOptions) []string {
+	if opts == nil || (len(opts.Env) == 0 && opts.WorkingDir == "") {
+		return cmd
+	}
+	wrapped := []string{"env"}
+	keys := make([]string, 0, len(opts.Env))
+	for k := range opts.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		wrapped = append(wrapped, k+"="+opts.Env[k])
+	}
+	if opts.WorkingDir != "" {
+		wrapped = append(wrapped, "sh", "-c", `cd "$0" && exec "$@"`, opts.WorkingDir)
+	}
+	return append(wrapped, cmd...)
+}
+
+// Stop deletes the pod, giving its processes timeout to exit. An ephemeral
+// container cannot be deleted, so its main process is sent SIGTERM instead
+func (s *Kubernetes) Stop(ctx context.Context, containerID string, timeout time.Duration) error {
+	p, err := s.getPod(containerID)
+	if err != nil {
+		return err
+	}
+	if p.ephemeral != nil {
+		return s.stopEphemeral(ctx, p)
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	grace := int64(timeout.Seconds())
+	return s.deletePod(ctx, containerID, &grace)
+}
+
+// Remove removes a sandbox pod with its ephemeral containers. An ephemeral
+// container is stopped and forgotten; it is deleted with its pod
+func (s *Kubernetes) Remove(ctx context.Context, containerID string) error {
+	p, err := s.getPod(containerID)
+	if err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	submitted := p.submitted
+	s.mu.RUnlock()
+	switch {
+	case submitted && p.ephemeral != nil:
+		// The container may have exited already
+		_ = s.stopEphemeral(ctx, p)
+	case submitted:
+		grace := int64(0)
+		if err := s.deletePod(ctx, containerID, &grace); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	delete(s.pods, containerID)
+	for id, other := range s.pods {
+		if other.podName == containerID {
+			delete(s.pods, id)
+		}
+	}
+	s.mu.Unlock()
+
+	s.logger.Debugw("pod removed", "pod", containerID)
+
+	return nil
+}
+
+// stopEphemeral sends SIGTERM to the main process of an ephemeral container.
+func (s *Kubernetes) stopEphemeral(ctx context.Context, p *kubernetesPod) error {
+	s.mu.RLock()
+	submitted := p.submitted
+	s.mu.RUnlock()
+	if !submitted {
+		return nil
+	}
+	streamOpts := remotecommand.StreamOptions{Stderr: io.Discard}
+	executor, err := s.newExecutor(s.execURL(p.podName, p.ephemeral.Name, []string{"kill", "-TERM", "1"}, streamOpts))
+	if err != nil {
+		return fmt.Errorf("create executor: %w", err)
+	}
+	if err := executor.StreamWithContext(ctx, streamOpts); err != nil {
+		return fmt.Errorf("stop container %s: %w", p.ephemeral.Name, err)
+	}
+	return nil
+}
+
+// deletePod deletes a pod, ignoring pods that are already gone.
+func (s *Kubernetes) deletePod(ctx context.Context, podName string, grace *int64) error {
+	err := s.client.CoreV1().Pods(s.namespace).Delete(ctx, podName, metav1.DeleteOptions{GracePeriodSeconds: grace})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("delete pod: %w", err)
+	}
+	return nil
+}
+
+// GetLogs retrieves the pod logs
+func (s *Kubernetes) GetLogs(ctx context.Context, containerID string, opts *LogOptions) (io.ReadCloser, error) {
+	p, err := s.getPod(containerID)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	submitted := p.submitted
+	s.mu.RUnlock()
+	if !submitted {
+		return nil, fmt.Errorf("pod %s is not started", containerID)
+	}
+
+	podName, containerName := p.target()
+	logOpts := &corev1.PodLogOptions{Container: containerName}
+	if opts != nil {
+		logOpts.Follow = opts.Follow
+		logOpts.Timestamps = opts.Timestamps
+		if opts.Tail > 0 {
+			tail := int64(opts.Tail)
+			logOpts.TailLines = &tail
+		}
+		if !opts.Since.IsZero() {
+			since := metav1.NewTime(opts.Since)
+			logOpts.SinceTime = &since
+		}
+	}
+
+	stream, err := s.client.CoreV1().Pods(s.namespace).GetLogs(podName, logOpts).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("stream pod logs: %w", err)
+	}
+	return stream, nil
+}
+
+// Cleanup deletes every pod of this agent, including pods left behind by a
+// previous agent process
+func (s *Kubernetes) Cleanup(ctx context.Context) error {
+	selector := fmt.Sprintf("%s=%s,%s=%s", kubernetesManagedByLabel, kubernetesManagedBy, kubernetesOwnerLabel, s.owner)
+	list, err := s.client.CoreV1().Pods(s.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return fmt.Errorf("list sandbox pods: %w", err)
+	}
+
+	grace := int64(0)
+	var errs []error
+	for _, pod := range list.Items {
+		if err := s.deletePod(ctx, pod.Name, &grace); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	s.mu.Lock()
+	s.pods = make(map[string]*kubernetesPod)
+	s.mu.Unlock()
+
+	s.logger.Infow("sandbox cleanup completed", "pods", len(list.Items))
+
+	return errors.Join(errs...)
+}
+
+// reapStale deletes the pods labelled with the owner of this agent that it
+// does not track: pods of an earlier run that exited without Close, and pods
+// whose delete failed. It returns the number of deleted pods.
+func (s *Kubernetes) reapStale(ctx context.Context) (int, error) {
+	selector := fmt.Sprintf("%s=%s,%s=%s", kubernetesManagedByLabel, kubernetesManagedBy, kubernetesOwnerLabel, s.owner)
+	list, err := s.client.CoreV1().Pods(s.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return 0, fmt.Errorf("list sandbox pods: %w", err)
+	}
+
+	var stale []string
+	s.mu.RLock()
+	for _, pod := range list.Items {
+		if _, ok := s.pods[pod.Name]; !ok && pod.DeletionTimestamp == nil {
+			stale = append(stale, pod.Name)
+		}
+	}
+	s.mu.RUnlock()
+
+	grace := int64(0)
+	var errs []error
+	reaped := 0
+	for _, name := range stale {
+		if err := s.deletePod(ctx, name, &grace); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		reaped++
+	}
+	return reaped, errors.Join(errs...)
+}
+
+// reap runs one reapStale pass and logs its outcome
+func (s *Kubernetes) reap() {
+	ctx, cancel := context.WithTimeout(context.Background(), kubernetesReapTimeout)
+	defer cancel()
+
+	reaped, err := s.reapStale(ctx)
+	if err != nil {
+		s.logger.Warnw("failed to reap stale sandbox pods", "owner", s.owner, "error", err)
+	}
+	if reaped > 0 {
+		s.logger.Infow("reaped stale sandbox pods", "owner", s.owner, "pods", reaped)
+	}
+}
+
+// reapLoop reaps stale pods every kubernetesReapInterval until Close
+func (s *Kubernetes) reapLoop() {
+	ticker := time.NewTicker(kubernetesReapInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.reap()
+		}
+	}
+}
+
+// Close stops reaping and removes the pods of this agent
+func (s *Kubernetes) Close() error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	if err := s.Cleanup(context.Background()); err != nil {
+		s.logger.Warnw("cleanup failed during close", "error", err)
+	}
+	return nil
+}
+
+// getPod gets a sandbox pod by name
+func (s *Kubernetes) getPod(containerID string) (*kubernetesPod, error) {
+	s.mu.RLock()
+	p, ok := s.pods[containerID]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("container not found: %s", containerID)
+	}
+	return p, nil
+}
+
+// kubernetesEnv converts env to container env vars in a stable order.
+func kubernetesEnv(env map[string]string) []corev1.EnvVar {
+	if len(env) == 0 {
+		return nil
+	}
+	vars := make([]corev1.EnvVar, 0, len(env))
+	for k, v := range env {
+		vars = append(vars, corev1.EnvVar{Name: k, Value: v})
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
+// kubernetesResources converts resource limits. CPU shares become the CPU
+// request (1024 shares is one core) and the memory reservation the memory
+// request.
+func kubernetesResources(r *Resources) (corev1.ResourceRequirements, error) {
+	req := corev1.ResourceRequirements{}
+	set := func(list *corev1.ResourceList, name corev1.ResourceName, value string) error {
+		if value == "" {
+			return nil
+		}
+		if name == corev1.ResourceMemory {
+			value = memoryQuantity(value)
+		}
+		q, err := resource.ParseQuantity(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", name, value, err)
+		}
+		if *list == nil {
+			*list = corev1.ResourceList{}
+		}
+		(*list)[name] = q
+		return nil
+	}
+	if err := set(&req.Limits, corev1.ResourceCPU, r.CPU); err != nil {
+		return req, err
+	}
+	if err := set(&req.Limits, corev1.ResourceMemory, r.Memory); err != nil {
+		return req, err
+	}
+	if err := set(&req.Requests, corev1.ResourceMemory, r.MemoryReservation); err != nil {
+		return req, err
+	}
+	if r.CPUShares > 0 {
+		if req.Requests == nil {
+			req.Requests = corev1.ResourceList{}
+		}
+		req.Requests[corev1.ResourceCPU] = *resource.NewMilliQuantity(r.CPUShares*1000/1024, resource.DecimalSI)
+	}
+	return req, nil
+}
+
+// memoryQuantity turns the byte suffixes accepted by the containerd sandbox
+// ("1G", "512M", "1GB") into binary quantities ("1Gi", "512Mi").
+func memoryQuantity(value string) string {
+	value = strings.TrimSpace(value)
+	upper := strings.ToUpper(value)
+	for _, unit := range []string{"K", "M", "G", "T"} {
+		for _, suffix := range []string{unit + "B", unit} {
+			if strings.HasSuffix(upper, suffix) && !strings.HasSuffix(upper, unit+"I") {
+				return value[:len(value)-len(suffix)] + strings.ReplaceAll(unit, "K", "k") + "i"
+			}
+		}
+	}
+	return value
+}
+
+// kubernetesVolume converts a mount to a pod volume. Bind mounts become
+// hostPath volumes, so the pod is pinned to the agent's node.
+func kubernetesVolume(name string, m Mount) (corev1.Volume, error) {
+	volume := corev1.Volume{Name: name}
+	switch m.Type {
+	case "", "bind":
+		volume.HostPath = &corev1.HostPathVolumeSource{Path: m.Source}
+	case "tmpfs":
+		volume.EmptyDir = &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory}
+	case "volume":
+		volume.EmptyDir = &corev1.EmptyDirVolumeSource{}
+	default:
+		return volume, fmt.Errorf("unsupported mount type: %s", m.Type)
+	}
+	return volume, nil
+}
+
+// labelValue lowercases s and drops the characters a label value or DNS
+// label may not contain.
+func labelValue(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			b.WriteRune(r)
+		}
+	}
+	out := strings.Trim(b.String(), "-")
+	if len(out) > validation.DNS1123LabelMaxLength {
+		out = strings.Trim(out[:validation.DNS1123LabelMaxLength], "-")
+	}
+	return out
+}
+
+// generatePodName generates a unique pod name
+func generatePodName(prefix string) string {
+	// Leave room for the 19 digit suffix within the 63 character limit
+	name := labelValue(prefix)
+	if len(name) > 40 {
+		name = strings.Trim(name[:40], "-")
+	}
+	return fmt.Sprintf("%s-%d", name, time.Now().UnixNano())
+}
@@ -0,0 +1,354 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/arcentrix/arcentra/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// fakeExecutor records the exec URL and writes stdout.
+type fakeExecutor struct {
+	url      *url.URL
+	stdout   string
+	exitCode int
+}
+
+func (e *fakeExecutor) Stream(opts remotecommand.StreamOptions) error {
+	return e.StreamWithContext(context.Background(), opts)
+}
+
+func (e *fakeExecutor) StreamWithContext(_ context.Context, opts remotecommand.StreamOptions) error {
+	if opts.Stdout != nil {
+		_, _ = io.WriteString(opts.Stdout, e.stdout)
+	}
+	if e.exitCode != 0 {
+		return utilexec.CodeExitError{Err: io.EOF, Code: e.exitCode}
+	}
+	return nil
+}
+
+// newTestKubernetes returns a sandbox on a fake clientset whose pods and
+// ephemeral containers are running as soon as they are created.
+func newTestKubernetes(t *testing.T, exec *fakeExecutor, objects ...runtime.Object) (*Kubernetes, *fake.Clientset) {
+	t.Helper()
+	client := fake.NewClientset(objects...)
+	client.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		pod := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod)
+		pod.Status.Phase = corev1.PodRunning
+		pod.Status.PodIP = "10.0.0.7"
+		return false, nil, nil
+	})
+	client.PrependReactor("update", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "ephemeralcontainers" {
+			return false, nil, nil
+		}
+		pod := action.(k8stesting.UpdateAction).GetObject().(*corev1.Pod)
+		pod.Status.EphemeralContainerStatuses = nil
+		for _, c := range pod.Spec.EphemeralContainers {
+			pod.Status.EphemeralContainerStatuses = append(pod.Status.EphemeralContainerStatuses, corev1.ContainerStatus{
+				Name:  c.Name,
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			})
+		}
+		return false, nil, nil
+	})
+	sb := newKubernetesSandbox(client, &rest.Config{Host: "https://k8s.example.com"}, &KubernetesConfig{
+		Namespace:    "ci",
+		DefaultImage: "alpine:3",
+		Owner:        "agent-1",
+		NodeName:     "node-1",
+	}, log.Logger{SugaredLogger: log.GetLogger()})
+	sb.newExecutor = func(execURL *url.URL) (remotecommand.Executor, error) {
+		exec.url = execURL
+		return exec, nil
+	}
+	t.Cleanup(func() { _ = sb.Close() })
+	return sb, client
+}
+
+func TestKubernetesSandbox_Execute(t *testing.T) {
+	exec := &fakeExecutor{stdout: "hello\n", exitCode: 3}
+	sb, client := newTestKubernetes(t, exec)
+	ctx := context.Background()
+
+	id, err := sb.Create(ctx, &CreateOptions{
+		Command:    []string{"sh", "-lc", "make"},
+		Env:        map[string]string{"B": "2", "A": "1"},
+		WorkingDir: "/workspace",
+		Mounts:     []Mount{{Source: "/data/ws", Target: "/workspace", Type: "bind"}},
+		Resources:  &Resources{CPU: "500m", Memory: "1G", CPUShares: 512},
+		Labels:     map[string]string{"arcentra.step": "build step"},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if pods, _ := client.CoreV1().Pods("ci").List(ctx, metav1.ListOptions{}); len(pods.Items) != 0 {
+		t.Fatalf("pod submitted before Execute")
+	}
+
+	var stdout strings.Builder
+	res, err := sb.Execute(ctx, id, []string{"sh", "-lc", "make"}, &ExecuteOptions{Stdout: &stdout})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if res.ExitCode != 3 || stdout.String() != "hello\n" {
+		t.Errorf("result = %+v, stdout = %q", res, stdout.String())
+	}
+
+	pod, err := client.CoreV1().Pods("ci").Get(ctx, id, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get pod: %v", err)
+	}
+	c := pod.Spec.Containers[0]
+	if c.Image != "alpine:3" || c.Command[0] != "sh" || !strings.Contains(c.Command[2], "sleep") {
+		t.Errorf("container = %+v", c)
+	}
+	if len(c.Env) != 2 || c.Env[0].Name != "A" {
+		t.Errorf("env = %+v", c.Env)
+	}
+	if c.Resources.Limits.Memory().String() != "1Gi" || c.Resources.Limits.Cpu().String() != "500m" ||
+		c.Resources.Requests.Cpu().String() != "500m" {
+		t.Errorf("resources = %+v", c.Resources)
+	}
+	if pod.Spec.Volumes[0].HostPath == nil || pod.Spec.Volumes[0].HostPath.Path != "/data/ws" {
+		t.Errorf("volumes = %+v", pod.Spec.Volumes)
+	}
+	terms := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) != 1 || terms[0].MatchFields[0].Key != "metadata.name" || terms[0].MatchFields[0].Values[0] != "node-1" {
+		t.Errorf("node affinity = %+v, want the agent node", terms)
+	}
+	if pod.Labels[kubernetesOwnerLabel] != "agent-1" || pod.Annotations["arcentra.step"] != "build step" {
+		t.Errorf("labels = %v, annotations = %v", pod.Labels, pod.Annotations)
+	}
+
+	if exec.url.Path != "/api/v1/namespaces/ci/pods/"+id+"/exec" {
+		t.Errorf("exec path = %s", exec.url.Path)
+	}
+	if got := exec.url.Query()["command"]; strings.Join(got, " ") != "sh -lc make" {
+		t.Errorf("exec command = %v", got)
+	}
+
+	if err := sb.Remove(ctx, id); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, err := client.CoreV1().Pods("ci").Get(ctx, id, metav1.GetOptions{}); err == nil {
+		t.Error("pod not deleted")
+	}
+}
+
+func TestKubernetesSandbox_JobPod(t *testing.T) {
+	exec := &fakeExecutor{}
+	sb, client := newTestKubernetes(t, exec)
+	ctx := context.Background()
+
+	// The job network is a pod holding the workspace and the service hosts
+	job, err := sb.Create(ctx, &CreateOptions{
+		Image:  "registry.k8s.io/pause:3.10",
+		Hosts:  map[string]string{"postgres": "127.0.0.1", "redis": "127.0.0.1"},
+		Mounts: []Mount{{Source: "/data/ws", Target: "/workspace", Type: "bind"}},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := sb.Start(ctx, job); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	pod, _ := client.CoreV1().Pods("ci").Get(ctx, job, metav1.GetOptions{})
+	if len(pod.Spec.Containers[0].Command) != 0 {
+		t.Errorf("job pod command = %v, want the image entrypoint", pod.Spec.Containers[0].Command)
+	}
+	aliases := pod.Spec.HostAliases
+	if len(aliases) != 1 || aliases[0].IP != "127.0.0.1" || strings.Join(aliases[0].Hostnames, ",") != "postgres,redis" {
+		t.Errorf("host aliases = %+v", aliases)
+	}
+
+	// Services and steps join the pod as ephemeral containers
+	db, err := sb.Create(ctx, &CreateOptions{Image: "postgres:16", Args: []string{"-c", "fsync=off"}, NetworkMode: NetworkModeContainerPrefix + job})
+	if err != nil {
+		t.Fatalf("Create service: %v", err)
+	}
+	if err := sb.Start(ctx, db); err != nil {
+		t.Fatalf("Start service: %v", err)
+	}
+	step, err := sb.Create(ctx, &CreateOptions{
+		Image:       "golang:1.24",
+		Command:     []string{"sh", "-lc", "go test"},
+		NetworkMode: NetworkModeContainerPrefix + job,
+		Mounts:      []Mount{{Source: "/data/ws", Target: "/workspace", Type: "bind"}},
+	})
+	if err != nil {
+		t.Fatalf("Create step: %v", err)
+	}
+	if _, err := sb.Execute(ctx, step, []string{"sh", "-lc", "go test"}, nil); err != nil {
+		t.Fatalf("Execute step: %v", err)
+	}
+	if exec.url.Path != "/api/v1/namespaces/ci/pods/"+job+"/exec" || exec.url.Query().Get("container") != step {
+		t.Errorf("exec url = %s", exec.url)
+	}
+
+	pods, _ := client.CoreV1().Pods("ci").List(ctx, metav1.ListOptions{})
+	if len(pods.Items) != 1 {
+		t.Fatalf("pods = %d, want one job pod", len(pods.Items))
+	}
+	ephemeral := pods.Items[0].Spec.EphemeralContainers
+	if len(ephemeral) != 2 || ephemeral[0].Name != db || ephemeral[1].Name != step {
+		t.Fatalf("ephemeral containers = %+v", ephemeral)
+	}
+	if ephemeral[0].Args[1] != "fsync=off" || !strings.Contains(ephemeral[1].Command[2], "sleep") {
+		t.Errorf("ephemeral commands = %v %v", ephemeral[0].Args, ephemeral[1].Command)
+	}
+	if m := ephemeral[1].VolumeMounts; len(m) != 1 || m[0].Name != pod.Spec.Volumes[0].Name || m[0].MountPath != "/workspace" {
+		t.Errorf("step mounts = %+v, want the job pod workspace", m)
+	}
+
+	if _, err := sb.Create(ctx, &CreateOptions{
+		Image:       "alpine:3",
+		NetworkMode: NetworkModeContainerPrefix + job,
+		Mounts:      []Mount{{Source: "/other", Target: "/other"}},
+	}); err == nil {
+		t.Error("expected error for a mount that is not a job pod volume")
+	}
+
+	// Removing a step stops its container; removing the job deletes the pod
+	if err := sb.Remove(ctx, step); err != nil {
+		t.Fatalf("Remove step: %v", err)
+	}
+	if got := exec.url.Query()["command"]; strings.Join(got, " ") != "kill -TERM 1" {
+		t.Errorf("stop command = %v", got)
+	}
+	if err := sb.Remove(ctx, job); err != nil {
+		t.Fatalf("Remove job: %v", err)
+	}
+	if _, err := sb.getPod(db); err == nil {
+		t.Error("expected ephemeral containers to be forgotten with their pod")
+	}
+}
+
+func TestKubernetesSandbox_HostPathNeedsNode(t *testing.T) {
+	sb, _ := newTestKubernetes(t, &fakeExecutor{})
+	sb.config.NodeName = ""
+	if _, err := sb.Create(context.Background(), &CreateOptions{Mounts: []Mount{{Source: "/data/ws", Target: "/workspace"}}}); err == nil {
+		t.Error("expected error for a hostPath mount without the agent node")
+	}
+	if _, err := sb.Create(context.Background(), &CreateOptions{Mounts: []Mount{{Target: "/cache", Type: "volume"}}}); err != nil {
+		t.Errorf("Create with emptyDir: %v", err)
+	}
+}
+
+func TestKubernetesSandbox_CleanupRemovesOrphans(t *testing.T) {
+	orphan := func(name, owner string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ci",
+			Labels:    map[string]string{kubernetesManagedByLabel: kubernetesManagedBy, kubernetesOwnerLabel: owner},
+		}}
+	}
+	sb, client := newTestKubernetes(t, &fakeExecutor{}, orphan("old-1", "agent-1"), orphan("other-1", "agent-2"))
+	ctx := context.Background()
+
+	id, _ := sb.Create(ctx, &CreateOptions{Command: []string{"sleep", "60"}})
+	if err := sb.Start(ctx, id); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := sb.Cleanup(ctx); err != nil {
+		t.Fatalf("Cleanup: %v", err)
+	}
+
+	pods, _ := client.CoreV1().Pods("ci").List(ctx, metav1.ListOptions{})
+	if len(pods.Items) != 1 || pods.Items[0].Name != "other-1" {
+		t.Errorf("remaining pods = %v", pods.Items)
+	}
+	if _, err := sb.Execute(ctx, id, []string{"true"}, nil); err == nil {
+		t.Error("expected cleaned up pod to be forgotten")
+	}
+}
+
+func TestKubernetesSandbox_ReapsStalePods(t *testing.T) {
+	labelled := func(name, owner string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ci",
+			Labels:    map[string]string{kubernetesManagedByLabel: kubernetesManagedBy, kubernetesOwnerLabel: owner},
+		}}
+	}
+	unmanaged := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "postgres-0", Namespace: "ci"}}
+	sb, client := newTestKubernetes(t, &fakeExecutor{},
+		labelled("arcentra-build-7f3a", "agent-1"), labelled("arcentra-test-91c2", "agent-2"), unmanaged)
+	ctx := context.Background()
+
+	podNames := func() []string {
+		pods, err := client.CoreV1().Pods("ci").List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		var names []string
+		for _, pod := range pods.Items {
+			names = append(names, pod.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	// The fresh sandbox removes the pod an earlier run of agent-1 left behind
+	if got, want := podNames(), []string{"arcentra-test-91c2", "postgres-0"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("pods after startup = %v, want %v", got, want)
+	}
+
+	id, _ := sb.Create(ctx, &CreateOptions{Command: []string{"sleep", "60"}})
+	if err := sb.Start(ctx, id); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, err := client.CoreV1().Pods("ci").Create(ctx, labelled("arcentra-lint-0b5e", "agent-1"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// A periodic pass only removes the pods the sandbox does not track
+	reaped, err := sb.reapStale(ctx)
+	if err != nil {
+		t.Fatalf("reapStale: %v", err)
+	}
+	if reaped != 1 {
+		t.Errorf("reaped = %d, want 1", reaped)
+	}
+	want := []string{"arcentra-test-91c2", id, "postgres-0"}
+	sort.Strings(want)
+	if got := podNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("pods after reap = %v, want %v", got, want)
+	}
+}
+
+func TestExecCommand(t *testing.T) {
+	got := execCommand([]string{"make"}, &ExecuteOptions{Env: map[string]string{"X": "1"}, WorkingDir: "/src"})
+	want := []string{"env", "X=1", "sh", "-c", `cd "$0" && exec "$@"`, "/src", "make"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("execCommand = %q, want %q", got, want)
+	}
+}
@@ -20,6 +20,10 @@ import (
 	"time"
 )
 
+// NetworkModeContainerPrefix prefixes a container ID in CreateOptions.NetworkMode
+// to join that container's network namespace, e.g. "container:arcentra-123".
+const NetworkModeContainerPrefix = "container:"
+
 // Sandbox defines the interface for sandbox execution environments
 // Sandbox provides isolated execution environment for running tasks
 type Sandbox interface {
@@ -67,9 +71,13 @@ type CreateOptions struct {
 	// WorkingDir is the working directory in the container
 	WorkingDir string
 
-	// NetworkMode is the network mode (bridge, host, none)
+	// NetworkMode is the network mode (bridge, host, none, or
+	// "container:<id>" to join the network namespace of a running container)
 	NetworkMode string
 
+	// Hosts are extra /etc/hosts entries, hostname to IP
+	Hosts map[string]string
+
 	// Resources are resource limits
 	Resources *Resources
 
@@ -141,6 +149,15 @@ type ExecuteOptions struct {
 
 	// Timeout is the execution timeout
 	Timeout time.Duration
+
+	// Resize delivers terminal size changes while the command runs; used with TTY
+	Resize <-chan TerminalSize
+}
+
+// TerminalSize is the size of a TTY in characters
+type TerminalSize struct {
+	Cols uint16
+	Rows uint16
 }
 
 // ExecuteResult result of executing a command
@@ -0,0 +1,642 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azuredevops
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arcentrix/arcentra/pkg/request"
+	"github.com/arcentrix/arcentra/pkg/scm"
+	"github.com/bytedance/sonic"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	apiVersion = "7.1"
+	// defaultBaseURL serves the organizations of Azure DevOps Services.
+	defaultBaseURL = "https://dev.azure.com"
+	// TokenHeader carries the webhook secret when the service hook is
+	// configured with a custom HTTP header instead of basic authentication.
+	TokenHeader = "X-Arcentra-Token"
+)
+
+type Provider struct {
+	cfg scm.ProviderConfig
+}
+
+func New(cfg scm.ProviderConfig) (scm.Provider, error) {
+	return &Provider{cfg: cfg}, nil
+}
+
+func (p *Provider) Kind() scm.ProviderKind { return scm.ProviderKindAzure }
+
+func (p *Provider) Capabilities() scm.CapSet {
+	return scm.CapSet{
+		scm.CapWebhookVerify: true,
+		scm.CapWebhookParse:  true,
+		scm.CapPollEvents:    true,
+		scm.CapCommitStatus:  true,
+		scm.CapCompare:       true,
+	}
+}
+
+// VerifyWebhook checks the secret of an Azure DevOps service hook. Service
+// hooks cannot sign their payloads; the secret is configured either as the
+// basic authentication password or as the TokenHeader HTTP header.
+func (p *Provider) VerifyWebhook(_ context.Context, req scm.WebhookRequest, secret string) error {
+	if token := req.Header(TokenHeader); token != "" {
+		return scm.VerifyTokenHeader(secret, token)
+	}
+	auth := strings.TrimSpace(req.Header("Authorization"))
+	if len(auth) < len("Basic ") || !strings.EqualFold(auth[:len("Basic ")], "Basic ") {
+		return scm.VerifyTokenHeader(secret, "")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(auth[len("Basic "):]))
+	if err != nil {
+		return fmt.Errorf("invalid basic authorization encoding")
+	}
+	_, password, _ := strings.Cut(string(decoded), ":")
+	return scm.VerifyTokenHeader(secret, password)
+}
+
+func (p *Provider) ParseWebhook(_ context.Context, req scm.WebhookRequest) ([]scm.Event, error) {
+	var envelope struct {
+		EventType string `json:"eventType"`
+	}
+	if err := sonic.Unmarshal(req.Body, &envelope); err != nil {
+		return nil, err
+	}
+	switch envelope.EventType {
+	case "git.push":
+		return p.parsePush(req.Body)
+	case "git.pullrequest.created", "git.pullrequest.updated", "git.pullrequest.merged":
+		return p.parsePullRequest(envelope.EventType, req.Body)
+	default:
+		return nil, nil
+	}
+}
+
+// identity is an Azure DevOps user reference.
+type identity struct {
+	DisplayName string `json:"displayName"`
+	UniqueName  string `json:"uniqueName"`
+}
+
+func (i identity) name() string {
+	if i.UniqueName != "" {
+		return i.UniqueName
+	}
+	return i.DisplayName
+}
+
+// commitRef is an Azure DevOps commit reference.
+type commitRef struct {
+	CommitID string `json:"commitId"`
+}
+
+// refUpdate is one ref moved by a push.
+type refUpdate struct {
+	Name        string `json:"name"`
+	OldObjectID string `json:"oldObjectId"`
+	NewObjectID string `json:"newObjectId"`
+}
+
+// repository is the repository section of service hook payloads and API
+// responses.
+type repository struct {
+	Name      string `json:"name"`
+	RemoteURL string `json:"remoteUrl"`
+	WebURL    string `json:"webUrl"`
+	Project   struct {
+		Name string `json:"name"`
+	} `json:"project"`
+}
+
+func (r repository) repo() scm.Repo {
+	if repo, ok := ParseRepoURL(r.RemoteURL); ok {
+		return repo
+	}
+	return scm.Repo{
+		Owner:    r.Project.Name,
+		Name:     r.Name,
+		FullName: r.Project.Name + "/" + r.Name,
+		URL:      r.RemoteURL,
+	}
+}
+
+// pullRequest is a pull request of service hook payloads and API responses.
+type pullRequest struct {
+	PullRequestID         int        `json:"pullRequestId"`
+	Status                string     `json:"status"`
+	Title                 string     `json:"title"`
+	SourceRefName         string     `json:"sourceRefName"`
+	TargetRefName         string     `json:"targetRefName"`
+	CreationDate          string     `json:"creationDate"`
+	ClosedDate            string     `json:"closedDate"`
+	CreatedBy             identity   `json:"createdBy"`
+	LastMergeSourceCommit commitRef  `json:"lastMergeSourceCommit"`
+	LastMergeTargetCommit commitRef  `json:"lastMergeTargetCommit"`
+	LastMergeCommit       commitRef  `json:"lastMergeCommit"`
+	Repository            repository `json:"repository"`
+	Labels                []struct {
+		Name   string `json:"name"`
+		Active *bool  `json:"active"`
+	} `json:"labels"`
+}
+
+// event converts the pull request into a normalized event.
+func (pr pullRequest) event(repo scm.Repo, action string) scm.Event {
+	t := scm.EventTypePullRequest
+	occurred := parseTime(pr.CreationDate)
+	if pr.Status == "completed" {
+		t = scm.EventTypePullMerged
+		if closed := parseTime(pr.ClosedDate); !closed.IsZero() {
+			occurred = closed
+		}
+	}
+	if occurred.IsZero() {
+		occurred = time.Now()
+	}
+	labels := make([]string, 0, len(pr.Labels))
+	for _, l := range pr.Labels {
+		if l.Active == nil || *l.Active {
+			labels = append(labels, l.Name)
+		}
+	}
+	merged := t == scm.EventTypePullMerged
+	mergeCommit := ""
+	if merged {
+		mergeCommit = pr.LastMergeCommit.CommitID
+	}
+	return scm.Event{
+		ProviderKind: scm.ProviderKindAzure,
+		EventType:    t,
+		Repo:         repo,
+		ActorName:    pr.CreatedBy.name(),
+		CommitID:     mergeCommit,
+		OccurredAt:   occurred,
+		Change: &scm.Change{
+			Number:        pr.PullRequestID,
+			Title:         pr.Title,
+			Action:        action,
+			SourceBranch:  strings.TrimPrefix(pr.SourceRefName, "refs/heads/"),
+			TargetBranch:  strings.TrimPrefix(pr.TargetRefName, "refs/heads/"),
+			BaseCommitID:  pr.LastMergeTargetCommit.CommitID,
+			HeadCommitID:  pr.LastMergeSourceCommit.CommitID,
+			Labels:        labels,
+			State:         pr.Status,
+			IsMerged:      merged,
+			MergeCommitID: mergeCommit,
+		},
+	}
+}
+
+func (p *Provider) PollEvents(ctx context.Context, repo scm.Repo, cursor scm.Cursor) ([]scm.Event, scm.Cursor, error) {
+	if repo.Owner == "" || repo.Name == "" {
+		return nil, cursor, fmt.Errorf("repo owner/name is required")
+	}
+	since := cursor.Since
+	if since.IsZero() {
+		since = time.Now().Add(-30 * time.Minute)
+	}
+	next := since
+	events := make([]scm.Event, 0)
+
+	var pushes struct {
+		Value []struct {
+			Date       string      `json:"date"`
+			PushedBy   identity    `json:"pushedBy"`
+			RefUpdates []refUpdate `json:"refUpdates"`
+		} `json:"value"`
+	}
+	if err := p.get(ctx, p.repoAPI(repo)+"/pushes", map[string]string{
+		"searchCriteria.fromDate":          since.UTC().Format(time.RFC3339),
+		"searchCriteria.includeRefUpdates": "true",
+		"$top":                             "50",
+	}, &pushes); err != nil {
+		return nil, cursor, err
+	}
+	for _, push := range pushes.Value {
+		occurred := parseTime(push.Date)
+		if occurred.IsZero() || !occurred.After(since) {
+			continue
+		}
+		if occurred.After(next) {
+			next = occurred
+		}
+		for _, ru := range push.RefUpdates {
+			if ev, ok := refUpdateEvent(repo, ru, push.PushedBy.name(), occurred); ok {
+				events = append(events, ev)
+			}
+		}
+	}
+
+	var prs struct {
+		Value []pullRequest `json:"value"`
+	}
+	if err := p.get(ctx, p.repoAPI(repo)+"/pullrequests", map[string]string{
+		"searchCriteria.status": "all",
+		"$top":                  "50",
+	}, &prs); err != nil {
+		return nil, cursor, err
+	}
+	for _, pr := range prs.Value {
+		if parseTime(pr.CreationDate).IsZero() {
+			continue
+		}
+		action := scm.ChangeActionOpened
+		if pr.Status == "completed" {
+			action = scm.ChangeActionMerged
+		}
+		ev := pr.event(repo, action)
+		if !ev.OccurredAt.After(since) {
+			continue
+		}
+		if ev.OccurredAt.After(next) {
+			next = ev.OccurredAt
+		}
+		events = append(events, ev)
+	}
+
+	return events, scm.Cursor{Since: next}, nil
+}
+
+// CreateChangeRequest creates an Azure DevOps pull request and returns its
+// web URL.
+func (p *Provider) CreateChangeRequest(ctx context.Context, req scm.ChangeRequestInput) (string, error) {
+	repo, ok := ParseRepoURL(req.PipelineRepoURL)
+	if !ok {
+		return "", fmt.Errorf("invalid repository url: %s", req.PipelineRepoURL)
+	}
+	var out struct {
+		PullRequestID int        `json:"pullRequestId"`
+		Repository    repository `json:"repository"`
+	}
+	resp, err := request.NewRequest(
+		p.repoAPI(repo)+"/pullrequests",
+		fasthttp.MethodPost,
+		p.headers(),
+		nil,
+	).
+		WithQueryParams(map[string]string{"api-version": apiVersion}).
+		WithBodyJSON(map[string]any{
+			"sourceRefName": "refs/heads/" + req.SourceBranch,
+			"targetRefName": "refs/heads/" + req.TargetBranch,
+			"title":         req.Title,
+			"description":   "created by arcentra pipeline editor",
+		}).
+		WithResult(&out).
+		Do(ctx)
+	if err != nil {
+		return "", err
+	}
+	if resp == nil || resp.StatusCode() >= 400 {
+		return "", fmt.Errorf("azure devops create pr failed: %d", resp.StatusCode())
+	}
+	webURL := strings.TrimRight(out.Repository.WebURL, "/")
+	if webURL == "" {
+		webURL = p.baseURL() + "/" + escapePath(repo.Owner) + "/_git/" + url.PathEscape(repo.Name)
+	}
+	return webURL + "/pullrequest/" + strconv.Itoa(out.PullRequestID), nil
+}
+
+// ReportCommitStatus creates an Azure DevOps commit status. A context of the
+// form "genre/name" is split into the status genre and name.
+func (p *Provider) ReportCommitStatus(ctx context.Context, status scm.CommitStatus) error {
+	if err := status.Validate(); err != nil {
+		return err
+	}
+	genre, name := "", status.Context
+	if i := strings.LastIndex(status.Context, "/"); i > 0 {
+		genre, name = status.Context[:i], status.Context[i+1:]
+	}
+	resp, err := request.NewRequest(
+		p.repoAPI(status.Repo)+"/commits/"+url.PathEscape(status.SHA)+"/statuses",
+		fasthttp.MethodPost,
+		p.headers(),
+		nil,
+	).
+		WithQueryParams(map[string]string{"api-version": apiVersion}).
+		WithBodyJSON(map[string]any{
+			"state":       commitState(status.State),
+			"description": status.ShortDescription(),
+			"targetUrl":   status.TargetURL,
+			"context": map[string]string{
+				"genre": genre,
+				"name":  name,
+			},
+		}).
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+	if resp == nil || resp.StatusCode() >= 400 {
+		return fmt.Errorf("azure devops create commit status failed: %d", resp.StatusCode())
+	}
+	return nil
+}
+
+// commitState maps a normalized commit state to the Azure DevOps state.
+func commitState(state scm.CommitState) string {
+	switch state {
+	case scm.CommitStateSuccess:
+		return "succeeded"
+	case scm.CommitStateFailure:
+		return "failed"
+	default:
+		return string(state)
+	}
+}
+
+// ListChangedFiles lists the files changed between base and head through the
+// Azure DevOps commit diff API. base and head are commit SHAs or branches.
+func (p *Provider) ListChangedFiles(ctx context.Context, repo scm.Repo, base, head string) ([]string, error) {
+	if repo.Owner == "" || repo.Name == "" {
+		return nil, fmt.Errorf("repo owner/name is required")
+	}
+	var out struct {
+		Changes []struct {
+			ChangeType   string `json:"changeType"`
+			OriginalPath string `json:"originalPath"`
+			Item         struct {
+				Path     string `json:"path"`
+				IsFolder bool   `json:"isFolder"`
+			} `json:"item"`
+		} `json:"changes"`
+	}
+	if err := p.get(ctx, p.repoAPI(repo)+"/diffs/commits", map[string]string{
+		"baseVersion":       base,
+		"baseVersionType":   versionType(base),
+		"targetVersion":     head,
+		"targetVersionType": versionType(head),
+		"$top":              "2000",
+	}, &out); err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, c := range out.Changes {
+		if c.Item.IsFolder {
+			continue
+		}
+		files = append(files, strings.TrimPrefix(c.Item.Path, "/"))
+		if c.OriginalPath != "" {
+			files = append(files, strings.TrimPrefix(c.OriginalPath, "/"))
+		}
+	}
+	return scm.CollectChangedFiles(files), nil
+}
+
+// versionType tells commit SHAs from branch names for the diff API.
+func versionType(version string) string {
+	if len(version) != 40 {
+		return "branch"
+	}
+	for _, c := range strings.ToLower(version) {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return "branch"
+		}
+	}
+	return "commit"
+}
+
+// get sends an authenticated GET request to the Azure DevOps API.
+func (p *Provider) get(ctx context.Context, endpoint string, query map[string]string, out any) error {
+	params := map[string]string{"api-version": apiVersion}
+	for k, v := range query {
+		params[k] = v
+	}
+	resp, err := request.NewRequest(
+		endpoint,
+		fasthttp.MethodGet,
+		p.headers(),
+		nil,
+	).WithQueryParams(params).WithResult(out).Do(ctx)
+	if err != nil {
+		return err
+	}
+	if resp == nil || resp.StatusCode() >= 400 {
+		return fmt.Errorf("azure devops api error: %d", resp.StatusCode())
+	}
+	return nil
+}
+
+// headers authenticates with the personal access token through basic
+// authentication, the scheme Azure DevOps expects for PATs.
+func (p *Provider) headers() map[string]string {
+	token := base64.StdEncoding.EncodeToString([]byte(":" + strings.TrimSpace(p.cfg.Token)))
+	return map[string]string{
+		"Authorization": "Basic " + token,
+	}
+}
+
+// baseURL returns the collection root, e.g. https://dev.azure.com. SSH
+// hosts of Azure DevOps Services map to the HTTPS endpoint.
+func (p *Provider) baseURL() string {
+	base := strings.TrimSpace(p.cfg.APIBaseURL)
+	if base == "" {
+		base = strings.TrimSpace(p.cfg.BaseURL)
+	}
+	base = strings.TrimRight(base, "/")
+	if base == "" {
+		return defaultBaseURL
+	}
+	if u, err := url.Parse(base); err == nil && isSSHHost(u.Host) {
+		return defaultBaseURL
+	}
+	return base
+}
+
+// repoAPI returns the API root of repo. Repo owners hold the path to the
+// project, e.g. "org/project".
+func (p *Provider) repoAPI(repo scm.Repo) string {
+	return p.baseURL() + "/" + escapePath(repo.Owner) + "/_apis/git/repositories/" + url.PathEscape(repo.Name)
+}
+
+// escapePath escapes each segment of a slash separated path.
+func escapePath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+func isSSHHost(host string) bool {
+	host = strings.ToLower(host)
+	return host == "ssh.dev.azure.com" || strings.HasSuffix(host, "vs-ssh.visualstudio.com")
+}
+
+// ParseRepoURL parses an Azure DevOps repository URL. HTTPS URLs have the
+// form <base>/<owner...>/_git/<name>, SSH URLs of Azure DevOps Services the
+// form git@ssh.dev.azure.com:v3/<org>/<project>/<name>. The owner holds every
+// path segment up to the project.
+func ParseRepoURL(repoURL string) (scm.Repo, bool) {
+	repoURL = strings.TrimSpace(repoURL)
+	host, path := "", ""
+	switch {
+	case strings.HasPrefix(repoURL, "http://"), strings.HasPrefix(repoURL, "https://"), strings.HasPrefix(repoURL, "ssh://"):
+		u, err := url.Parse(repoURL)
+		if err != nil {
+			return scm.Repo{}, false
+		}
+		host, path = u.Hostname(), u.Path
+	case strings.Contains(repoURL, "@"):
+		rest := repoURL[strings.Index(repoURL, "@")+1:]
+		var ok bool
+		if host, path, ok = strings.Cut(rest, ":"); !ok {
+			return scm.Repo{}, false
+		}
+	default:
+		return scm.Repo{}, false
+	}
+	segments := strings.Split(strings.Trim(strings.TrimSuffix(path, ".git"), "/"), "/")
+	var owner, name string
+	switch n := len(segments); {
+	case n >= 3 && segments[n-2] == "_git":
+		owner, name = strings.Join(segments[:n-2], "/"), segments[n-1]
+	case n == 4 && segments[0] == "v3" && isSSHHost(host):
+		owner, name = segments[1]+"/"+segments[2], segments[3]
+	default:
+		return scm.Repo{}, false
+	}
+	if owner == "" || name == "" {
+		return scm.Repo{}, false
+	}
+	return scm.Repo{Host: host, Owner: owner, Name: name, FullName: owner + "/" + name, URL: repoURL}, true
+}
+
+func (p *Provider) parsePush(body []byte) ([]scm.Event, error) {
+	var payload struct {
+		CreatedDate string `json:"createdDate"`
+		Resource    struct {
+			Date       string      `json:"date"`
+			PushedBy   identity    `json:"pushedBy"`
+			RefUpdates []refUpdate `json:"refUpdates"`
+			Repository repository  `json:"repository"`
+			Commits    []struct {
+				CommitID string `json:"commitId"`
+				Comment  string `json:"comment"`
+			} `json:"commits"`
+		} `json:"resource"`
+	}
+	if err := sonic.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	res := payload.Resource
+	occurred := parseTime(res.Date)
+	if occurred.IsZero() {
+		occurred = parseTime(payload.CreatedDate)
+	}
+	if occurred.IsZero() {
+		occurred = time.Now()
+	}
+	repo := res.Repository.repo()
+	// Service hooks carry no file lists; the changed files are compared.
+	events := make([]scm.Event, 0, len(res.RefUpdates))
+	for _, ru := range res.RefUpdates {
+		ev, ok := refUpdateEvent(repo, ru, res.PushedBy.name(), occurred)
+		if !ok {
+			continue
+		}
+		for _, c := range res.Commits {
+			if c.CommitID == ru.NewObjectID {
+				ev.Message = c.Comment
+			}
+		}
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+// refUpdateEvent converts a pushed branch or tag into a push or tag event.
+func refUpdateEvent(repo scm.Repo, ru refUpdate, actor string, occurred time.Time) (scm.Event, bool) {
+	t := scm.EventTypePush
+	switch {
+	case strings.HasPrefix(ru.Name, "refs/tags/"):
+		t = scm.EventTypeTag
+	case !strings.HasPrefix(ru.Name, "refs/heads/"):
+		return scm.Event{}, false
+	}
+	return scm.Event{
+		ProviderKind:   scm.ProviderKindAzure,
+		EventType:      t,
+		Repo:           repo,
+		ActorName:      actor,
+		CommitID:       ru.NewObjectID,
+		BeforeCommitID: ru.OldObjectID,
+		Ref:            ru.Name,
+		OccurredAt:     occurred,
+	}, true
+}
+
+func (p *Provider) parsePullRequest(eventType string, body []byte) ([]scm.Event, error) {
+	var payload struct {
+		Message struct {
+			Text string `json:"text"`
+		} `json:"message"`
+		Resource pullRequest `json:"resource"`
+	}
+	if err := sonic.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	pr := payload.Resource
+	// git.pullrequest.merged also reports failed merge attempts.
+	if eventType == "git.pullrequest.merged" && pr.Status != "completed" {
+		return nil, nil
+	}
+	return []scm.Event{pr.event(pr.Repository.repo(), pullRequestAction(eventType, pr.Status, payload.Message.Text))}, nil
+}
+
+// pullRequestAction derives the normalized action of a pull request service
+// hook. Updates only tell what changed through their message.
+func pullRequestAction(eventType, status, message string) string {
+	switch {
+	case eventType == "git.pullrequest.created":
+		return scm.ChangeActionOpened
+	case status == "completed":
+		return scm.ChangeActionMerged
+	case status == "abandoned":
+		return scm.ChangeActionClosed
+	}
+	message = strings.ToLower(message)
+	switch {
+	case strings.Contains(message, "source branch"):
+		return scm.ChangeActionSynchronize
+	case strings.Contains(message, "reactivated"):
+		return scm.ChangeActionReopened
+	default:
+		return scm.ChangeActionEdited
+	}
+}
+
+// parseTime parses Azure DevOps timestamps, which omit the zone when unset.
+func parseTime(value string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		if t, err := time.Parse(layout, value); err == nil && t.Year() > 1 {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func init() {
+	scm.Register(scm.ProviderKindAzure, New)
+}
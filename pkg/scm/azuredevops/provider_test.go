@@ -0,0 +1,296 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azuredevops
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arcentrix/arcentra/pkg/scm"
+)
+
+// capturedRequest is what the stand-in API received.
+type capturedRequest struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   map[string]any
+}
+
+// apiServer stands in for the Azure DevOps API. It answers every request
+// with code and response and hands over each request it receives.
+func apiServer(t *testing.T, code int, response string) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+	reqs := make(chan capturedRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]any{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		reqs <- capturedRequest{
+			method: r.Method,
+			path:   r.URL.EscapedPath(),
+			query:  r.URL.Query(),
+			header: r.Header.Clone(),
+			body:   body,
+		}
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, reqs
+}
+
+var testRepo = scm.Repo{Owner: "acme/platform", Name: "app"}
+
+func basicAuth(user, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+password))
+}
+
+func TestParseRepoURL(t *testing.T) {
+	cases := []struct {
+		url   string
+		owner string
+		name  string
+	}{
+		{"https://dev.azure.com/acme/platform/_git/app", "acme/platform", "app"},
+		{"https://acme@dev.azure.com/acme/platform/_git/app", "acme/platform", "app"},
+		{"https://acme.visualstudio.com/platform/_git/app", "platform", "app"},
+		{"https://tfs.example.com/tfs/DefaultCollection/platform/_git/app", "tfs/DefaultCollection/platform", "app"},
+		{"git@ssh.dev.azure.com:v3/acme/platform/app", "acme/platform", "app"},
+	}
+	for _, tc := range cases {
+		repo, ok := ParseRepoURL(tc.url)
+		if !ok || repo.Owner != tc.owner || repo.Name != tc.name {
+			t.Fatalf("ParseRepoURL(%q) = %+v, %v", tc.url, repo, ok)
+		}
+	}
+	for _, bad := range []string{"https://github.com/acme/app.git", "git@github.com:acme/app.git", ""} {
+		if _, ok := ParseRepoURL(bad); ok {
+			t.Fatalf("ParseRepoURL(%q) should fail", bad)
+		}
+	}
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindAzure})
+	ctx := context.Background()
+	cases := []struct {
+		name    string
+		headers map[string]string
+		ok      bool
+	}{
+		{"basic", map[string]string{"Authorization": basicAuth("hook", "s3cret")}, true},
+		{"basic mismatch", map[string]string{"Authorization": basicAuth("hook", "other")}, false},
+		{"token header", map[string]string{TokenHeader: "s3cret"}, true},
+		{"token mismatch", map[string]string{TokenHeader: "other"}, false},
+		{"missing", map[string]string{}, false},
+	}
+	for _, tc := range cases {
+		err := p.VerifyWebhook(ctx, scm.WebhookRequest{Headers: tc.headers}, "s3cret")
+		if (err == nil) != tc.ok {
+			t.Fatalf("%s: VerifyWebhook err = %v", tc.name, err)
+		}
+	}
+}
+
+func TestParseWebhook_Push(t *testing.T) {
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindAzure})
+	body := `{"eventType":"git.push","resource":{
+		"date":"2026-03-01T10:00:00Z",
+		"pushedBy":{"displayName":"Jane","uniqueName":"jane@example.com"},
+		"refUpdates":[{"name":"refs/heads/main","oldObjectId":"b1","newObjectId":"h1"},
+			{"name":"refs/tags/v1.0.0","oldObjectId":"0000000000000000000000000000000000000000","newObjectId":"t1"}],
+		"commits":[{"commitId":"h1","comment":"fix build [skip ci]"}],
+		"repository":{"name":"app","remoteUrl":"https://dev.azure.com/acme/platform/_git/app","project":{"name":"platform"}}}}`
+	events, err := p.ParseWebhook(context.Background(), scm.WebhookRequest{Body: []byte(body)})
+	if err != nil || len(events) != 2 {
+		t.Fatalf("ParseWebhook: %v, %v", err, events)
+	}
+	push := events[0]
+	if push.EventType != scm.EventTypePush || push.Ref != "refs/heads/main" ||
+		push.CommitID != "h1" || push.BeforeCommitID != "b1" || push.Message != "fix build [skip ci]" {
+		t.Fatalf("unexpected push: %+v", push)
+	}
+	if push.Repo.Owner != "acme/platform" || push.Repo.Name != "app" || push.ActorName != "jane@example.com" {
+		t.Fatalf("unexpected repo/actor: %+v", push)
+	}
+	if push.ChangedFiles != nil {
+		t.Fatalf("changed files should be unknown: %v", push.ChangedFiles)
+	}
+	if events[1].EventType != scm.EventTypeTag || events[1].CommitID != "t1" {
+		t.Fatalf("unexpected tag: %+v", events[1])
+	}
+}
+
+func TestParseWebhook_PullRequest(t *testing.T) {
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindAzure})
+	pr := `"resource":{"pullRequestId":7,"status":"STATUS","title":"Add cache",
+		"sourceRefName":"refs/heads/feature","targetRefName":"refs/heads/main",
+		"creationDate":"2026-03-01T10:00:00Z","closedDate":"0001-01-01T00:00:00",
+		"lastMergeSourceCommit":{"commitId":"h1"},"lastMergeTargetCommit":{"commitId":"b1"},
+		"lastMergeCommit":{"commitId":"m1"},"labels":[{"name":"ci","active":true},{"name":"old","active":false}],
+		"repository":{"name":"app","remoteUrl":"https://dev.azure.com/acme/platform/_git/app"}}`
+	cases := []struct {
+		eventType string
+		status    string
+		message   string
+		want      scm.EventType
+		action    string
+	}{
+		{"git.pullrequest.created", "active", "", scm.EventTypePullRequest, scm.ChangeActionOpened},
+		{"git.pullrequest.updated", "active", "Jane updated the source branch of pull request 7", scm.EventTypePullRequest, scm.ChangeActionSynchronize},
+		{"git.pullrequest.updated", "abandoned", "Jane abandoned pull request 7", scm.EventTypePullRequest, scm.ChangeActionClosed},
+		{"git.pullrequest.merged", "completed", "", scm.EventTypePullMerged, scm.ChangeActionMerged},
+	}
+	for _, tc := range cases {
+		body := `{"eventType":"` + tc.eventType + `","message":{"text":"` + tc.message + `"},` +
+			strings.ReplaceAll(pr, "STATUS", tc.status) + `}`
+		events, err := p.ParseWebhook(context.Background(), scm.WebhookRequest{Body: []byte(body)})
+		if err != nil || len(events) != 1 || events[0].Change == nil {
+			t.Fatalf("%s/%s: ParseWebhook: %v, %v", tc.eventType, tc.status, err, events)
+		}
+		ev, c := events[0], events[0].Change
+		if ev.EventType != tc.want || c.Action != tc.action {
+			t.Fatalf("%s/%s: got %s/%s", tc.eventType, tc.status, ev.EventType, c.Action)
+		}
+		if c.SourceBranch != "feature" || c.TargetBranch != "main" || c.HeadCommitID != "h1" || c.BaseCommitID != "b1" {
+			t.Fatalf("unexpected change: %+v", c)
+		}
+		if !reflect.DeepEqual(c.Labels, []string{"ci"}) {
+			t.Fatalf("labels = %v", c.Labels)
+		}
+		if tc.want == scm.EventTypePullMerged && (c.MergeCommitID != "m1" || ev.CommitID != "m1") {
+			t.Fatalf("unexpected merge commit: %+v", ev)
+		}
+	}
+
+	// A failed merge attempt is not an event.
+	body := `{"eventType":"git.pullrequest.merged",` + strings.ReplaceAll(pr, "STATUS", "active") + `}`
+	events, err := p.ParseWebhook(context.Background(), scm.WebhookRequest{Body: []byte(body)})
+	if err != nil || len(events) != 0 {
+		t.Fatalf("merge attempt: %v, %v", err, events)
+	}
+}
+
+func TestReportCommitStatus(t *testing.T) {
+	srv, reqs := apiServer(t, http.StatusCreated, `{}`)
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindAzure, BaseURL: srv.URL, Token: "pat"})
+
+	err := p.ReportCommitStatus(context.Background(), scm.CommitStatus{
+		Repo:        testRepo,
+		SHA:         "0123abcd",
+		State:       scm.CommitStateSuccess,
+		Context:     "arcentra/build",
+		Description: "Pipeline succeeded",
+		TargetURL:   "https://ci.example.com/pipelines/p1/runs/r1",
+	})
+	if err != nil {
+		t.Fatalf("ReportCommitStatus: %v", err)
+	}
+	req := <-reqs
+	if req.method != http.MethodPost {
+		t.Fatalf("unexpected method: %s", req.method)
+	}
+	if req.path != "/acme/platform/_apis/git/repositories/app/commits/0123abcd/statuses" {
+		t.Fatalf("unexpected path: %s", req.path)
+	}
+	if req.query.Get("api-version") != apiVersion {
+		t.Fatalf("unexpected query: %v", req.query)
+	}
+	if got := req.header.Get("Authorization"); got != basicAuth("", "pat") {
+		t.Fatalf("unexpected authorization: %q", got)
+	}
+	if req.body["state"] != "succeeded" || req.body["targetUrl"] != "https://ci.example.com/pipelines/p1/runs/r1" {
+		t.Fatalf("unexpected body: %v", req.body)
+	}
+	statusContext, _ := req.body["context"].(map[string]any)
+	if statusContext["genre"] != "arcentra" || statusContext["name"] != "build" {
+		t.Fatalf("unexpected context: %v", req.body["context"])
+	}
+}
+
+func TestReportCommitStatus_APIError(t *testing.T) {
+	srv, _ := apiServer(t, http.StatusUnauthorized, `{}`)
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindAzure, BaseURL: srv.URL, Token: "pat"})
+	err := p.ReportCommitStatus(context.Background(), scm.CommitStatus{
+		Repo: testRepo, SHA: "0123abcd", State: scm.CommitStateFailure, Context: "arcentra",
+	})
+	if err == nil {
+		t.Fatalf("expected error for API failure")
+	}
+}
+
+func TestListChangedFiles(t *testing.T) {
+	srv, reqs := apiServer(t, http.StatusOK, `{"changes":[
+		{"item":{"path":"/src","isFolder":true},"changeType":"edit"},
+		{"item":{"path":"/src/a.go"},"changeType":"edit"},
+		{"item":{"path":"/src/b.go"},"changeType":"rename","originalPath":"/src/old.go"}]}`)
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindAzure, BaseURL: srv.URL, Token: "pat"})
+
+	files, err := p.ListChangedFiles(context.Background(), testRepo, "main", "0123456789abcdef0123456789abcdef01234567")
+	if err != nil {
+		t.Fatalf("ListChangedFiles: %v", err)
+	}
+	req := <-reqs
+	if req.path != "/acme/platform/_apis/git/repositories/app/diffs/commits" {
+		t.Fatalf("unexpected path: %s", req.path)
+	}
+	if req.query.Get("baseVersionType") != "branch" || req.query.Get("targetVersionType") != "commit" {
+		t.Fatalf("unexpected query: %v", req.query)
+	}
+	if want := []string{"src/a.go", "src/b.go", "src/old.go"}; !reflect.DeepEqual(files, want) {
+		t.Fatalf("files = %v, want %v", files, want)
+	}
+}
+
+func TestCreateChangeRequest(t *testing.T) {
+	srv, reqs := apiServer(t, http.StatusCreated,
+		`{"pullRequestId":12,"repository":{"webUrl":"https://dev.azure.com/acme/platform/_git/app"}}`)
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindAzure, BaseURL: srv.URL, Token: "pat"})
+
+	got, err := p.CreateChangeRequest(context.Background(), scm.ChangeRequestInput{
+		PipelineRepoURL: "https://dev.azure.com/acme/platform/_git/app",
+		SourceBranch:    "arcentra/edit",
+		TargetBranch:    "main",
+		Title:           "Update pipeline",
+	})
+	if err != nil {
+		t.Fatalf("CreateChangeRequest: %v", err)
+	}
+	if got != "https://dev.azure.com/acme/platform/_git/app/pullrequest/12" {
+		t.Fatalf("unexpected url: %s", got)
+	}
+	req := <-reqs
+	if req.path != "/acme/platform/_apis/git/repositories/app/pullrequests" {
+		t.Fatalf("unexpected path: %s", req.path)
+	}
+	if req.body["sourceRefName"] != "refs/heads/arcentra/edit" || req.body["targetRefName"] != "refs/heads/main" {
+		t.Fatalf("unexpected body: %v", req.body)
+	}
+}
+
+func TestBaseURL_SSHHost(t *testing.T) {
+	p := &Provider{cfg: scm.ProviderConfig{BaseURL: "https://ssh.dev.azure.com"}}
+	if got := p.baseURL(); got != defaultBaseURL {
+		t.Fatalf("baseURL = %s", got)
+	}
+}
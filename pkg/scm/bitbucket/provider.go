@@ -42,6 +42,8 @@ func (p *Provider) Capabilities() scm.CapSet {
 		scm.CapWebhookVerify: true,
 		scm.CapWebhookParse:  true,
 		scm.CapPollEvents:    true,
+		scm.CapCommitStatus:  true,
+		scm.CapCompare:       true,
 	}
 }
 
@@ -52,7 +54,7 @@ func (p *Provider) VerifyWebhook(_ context.Context, req scm.WebhookRequest, secr
 func (p *Provider) ParseWebhook(_ context.Context, req scm.WebhookRequest) ([]scm.Event, error) {
 	key := req.Header("X-Event-Key")
 	switch key {
-	case "pullrequest:created", "pullrequest:updated", "pullrequest:fulfilled":
+	case "pullrequest:created", "pullrequest:updated", "pullrequest:fulfilled", "pullrequest:rejected":
 		return p.parsePullRequest(req.Body, key)
 	case "repo:push":
 		return p.parsePush(req.Body)
@@ -264,6 +266,106 @@ func (p *Provider) CreateChangeRequest(ctx context.Context, req scm.ChangeReques
 	return out.Links.HTML.Href, nil
 }
 
+// ReportCommitStatus creates or updates a Bitbucket build status keyed by
+// the context. Errors are reported as stopped builds.
+func (p *Provider) ReportCommitStatus(ctx context.Context, status scm.CommitStatus) error {
+	if err := status.Validate(); err != nil {
+		return err
+	}
+	state := map[scm.CommitState]string{
+		scm.CommitStatePending: "INPROGRESS",
+		scm.CommitStateSuccess: "SUCCESSFUL",
+		scm.CommitStateFailure: "FAILED",
+		scm.CommitStateError:   "STOPPED",
+	}[status.State]
+	endpoint := p.apiBaseURL() + fmt.Sprintf(
+		"/repositories/%s/%s/commit/%s/statuses/build",
+		url.PathEscape(status.Repo.Owner),
+		url.PathEscape(status.Repo.Name),
+		url.PathEscape(status.SHA),
+	)
+	resp, err := request.NewRequest(
+		endpoint,
+		fasthttp.MethodPost,
+		map[string]string{
+			"Authorization": "Bearer " + strings.TrimSpace(p.cfg.Token),
+		},
+		nil,
+	).
+		WithBodyJSON(map[string]any{
+			"key":         status.Context,
+			"name":        status.Context,
+			"state":       state,
+			"url":         status.TargetURL,
+			"description": status.ShortDescription(),
+		}).
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+	if resp == nil || resp.StatusCode() >= 400 {
+		return fmt.Errorf("bitbucket create build status failed: %d", resp.StatusCode())
+	}
+	return nil
+}
+
+// bitbucketMaxDiffstatPages bounds the diffstat pages read per comparison.
+const bitbucketMaxDiffstatPages = 10
+
+// ListChangedFiles lists the files changed between base and head through the
+// Bitbucket diffstat API, following its pagination.
+func (p *Provider) ListChangedFiles(ctx context.Context, repo scm.Repo, base, head string) ([]string, error) {
+	if repo.Owner == "" || repo.Name == "" {
+		return nil, fmt.Errorf("repo owner/name is required")
+	}
+	endpoint := p.apiBaseURL() + fmt.Sprintf(
+		"/repositories/%s/%s/diffstat/%s",
+		url.PathEscape(repo.Owner),
+		url.PathEscape(repo.Name),
+		url.PathEscape(head+".."+base),
+	)
+	query := map[string]string{"pagelen": "500"}
+	var files []string
+	for page := 0; endpoint != "" && page < bitbucketMaxDiffstatPages; page++ {
+		var out struct {
+			Values []struct {
+				Old *struct {
+					Path string `json:"path"`
+				} `json:"old"`
+				New *struct {
+					Path string `json:"path"`
+				} `json:"new"`
+			} `json:"values"`
+			Next string `json:"next"`
+		}
+		resp, err := request.NewRequest(
+			endpoint,
+			fasthttp.MethodGet,
+			map[string]string{
+				"Authorization": "Bearer " + strings.TrimSpace(p.cfg.Token),
+			},
+			nil,
+		).WithQueryParams(query).WithResult(&out).Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if resp == nil || resp.StatusCode() >= 400 {
+			return nil, fmt.Errorf("bitbucket diffstat failed: %d", resp.StatusCode())
+		}
+		for _, v := range out.Values {
+			if v.Old != nil {
+				files = append(files, v.Old.Path)
+			}
+			if v.New != nil {
+				files = append(files, v.New.Path)
+			}
+		}
+		// the next link already carries the query
+		endpoint, query = out.Next, nil
+	}
+	return scm.CollectChangedFiles(files), nil
+}
+
 func (p *Provider) apiBaseURL() string {
 	apiBase := strings.TrimSpace(p.cfg.APIBaseURL)
 	if apiBase == "" {
@@ -288,11 +390,17 @@ func (p *Provider) parsePullRequest(body []byte, key string) ([]scm.Event, error
 				Branch struct {
 					Name string `json:"name"`
 				} `json:"branch"`
+				Commit struct {
+					Hash string `json:"hash"`
+				} `json:"commit"`
 			} `json:"source"`
 			Destination struct {
 				Branch struct {
 					Name string `json:"name"`
 				} `json:"branch"`
+				Commit struct {
+					Hash string `json:"hash"`
+				} `json:"commit"`
 			} `json:"destination"`
 			UpdatedOn time.Time `json:"updated_on"`
 		} `json:"pullrequest"`
@@ -340,8 +448,11 @@ func (p *Provider) parsePullRequest(body []byte, key string) ([]scm.Event, error
 		Change: &scm.Change{
 			Number:        payload.PullRequest.ID,
 			Title:         payload.PullRequest.Title,
+			Action:        pullRequestAction(key),
 			SourceBranch:  payload.PullRequest.Source.Branch.Name,
 			TargetBranch:  payload.PullRequest.Destination.Branch.Name,
+			BaseCommitID:  payload.PullRequest.Destination.Commit.Hash,
+			HeadCommitID:  payload.PullRequest.Source.Commit.Hash,
 			State:         payload.PullRequest.State,
 			IsMerged:      t == scm.EventTypePullMerged,
 			MergeCommitID: mergeSha,
@@ -349,6 +460,24 @@ func (p *Provider) parsePullRequest(body []byte, key string) ([]scm.Event, error
 	}}, nil
 }
 
+// pullRequestAction maps a Bitbucket pull request event key to the
+// normalized action. Bitbucket sends "updated" for new commits and edits
+// alike; both are treated as a synchronize.
+func pullRequestAction(key string) string {
+	switch key {
+	case "pullrequest:created":
+		return scm.ChangeActionOpened
+	case "pullrequest:updated":
+		return scm.ChangeActionSynchronize
+	case "pullrequest:fulfilled":
+		return scm.ChangeActionMerged
+	case "pullrequest:rejected":
+		return scm.ChangeActionClosed
+	default:
+		return ""
+	}
+}
+
 func (p *Provider) parsePush(body []byte) ([]scm.Event, error) {
 	var payload struct {
 		Repository struct {
@@ -364,10 +493,16 @@ func (p *Provider) parsePush(body []byte) ([]scm.Event, error) {
 					Type   string `json:"type"`
 					Name   string `json:"name"`
 					Target struct {
-						Hash string    `json:"hash"`
-						Date time.Time `json:"date"`
+						Hash    string    `json:"hash"`
+						Message string    `json:"message"`
+						Date    time.Time `json:"date"`
 					} `json:"target"`
 				} `json:"new"`
+				Old *struct {
+					Target struct {
+						Hash string `json:"hash"`
+					} `json:"target"`
+				} `json:"old"`
 			} `json:"changes"`
 		} `json:"push"`
 	}
@@ -390,13 +525,32 @@ func (p *Provider) parsePush(body []byte) ([]scm.Event, error) {
 		if c.New == nil {
 			continue
 		}
-		if strings.ToLower(c.New.Type) != "tag" {
-			continue
-		}
 		occurred := c.New.Target.Date
 		if occurred.IsZero() {
 			occurred = time.Now()
 		}
+		if strings.ToLower(c.New.Type) == "branch" {
+			// Bitbucket push payloads carry no file lists; they are compared.
+			before := ""
+			if c.Old != nil {
+				before = c.Old.Target.Hash
+			}
+			events = append(events, scm.Event{
+				ProviderKind:   scm.ProviderKindBitbucket,
+				EventType:      scm.EventTypePush,
+				Repo:           repo,
+				ActorName:      payload.Actor.DisplayName,
+				CommitID:       c.New.Target.Hash,
+				BeforeCommitID: before,
+				Message:        c.New.Target.Message,
+				Ref:            "refs/heads/" + c.New.Name,
+				OccurredAt:     occurred,
+			})
+			continue
+		}
+		if strings.ToLower(c.New.Type) != "tag" {
+			continue
+		}
 		events = append(events, scm.Event{
 			ProviderKind: scm.ProviderKindBitbucket,
 			EventType:    scm.EventTypeTag,
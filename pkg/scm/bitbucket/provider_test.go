@@ -0,0 +1,159 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/arcentrix/arcentra/pkg/scm"
+)
+
+// capturedRequest is what the stand-in API received.
+type capturedRequest struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   map[string]any
+}
+
+// apiServer stands in for the Bitbucket API. It answers every request with
+// code and response and hands over each request it receives.
+func apiServer(t *testing.T, code int, response string) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+	reqs := make(chan capturedRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]any{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		reqs <- capturedRequest{
+			method: r.Method,
+			path:   r.URL.EscapedPath(),
+			query:  r.URL.Query(),
+			header: r.Header.Clone(),
+			body:   body,
+		}
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, reqs
+}
+
+func statusServer(t *testing.T, code int) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+	return apiServer(t, code, `{}`)
+}
+
+func testStatus(state scm.CommitState) scm.CommitStatus {
+	return scm.CommitStatus{
+		Repo:        scm.Repo{Owner: "acme", Name: "app"},
+		SHA:         "0123abcd",
+		State:       state,
+		Context:     "arcentra/build",
+		Description: "Pipeline running",
+		TargetURL:   "https://ci.example.com/pipelines/p1/runs/r1",
+	}
+}
+
+func TestReportCommitStatus(t *testing.T) {
+	srv, reqs := statusServer(t, http.StatusCreated)
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindBitbucket, APIBaseURL: srv.URL, Token: "tkn"})
+
+	if err := p.ReportCommitStatus(context.Background(), testStatus(scm.CommitStatePending)); err != nil {
+		t.Fatalf("ReportCommitStatus: %v", err)
+	}
+	req := <-reqs
+	body := req.body
+	if req.method != http.MethodPost {
+		t.Fatalf("unexpected method: %s", req.method)
+	}
+	if req.path != "/repositories/acme/app/commit/0123abcd/statuses/build" {
+		t.Fatalf("unexpected path: %s", req.path)
+	}
+	if got := req.header.Get("Authorization"); got != "Bearer tkn" {
+		t.Fatalf("unexpected authorization: %q", got)
+	}
+	if body["state"] != "INPROGRESS" {
+		t.Fatalf("unexpected state: %v", body["state"])
+	}
+	if body["key"] != "arcentra/build" {
+		t.Fatalf("unexpected key: %v", body["key"])
+	}
+	if body["url"] != "https://ci.example.com/pipelines/p1/runs/r1" {
+		t.Fatalf("unexpected url: %v", body["url"])
+	}
+}
+
+func TestReportCommitStatus_APIError(t *testing.T) {
+	srv, _ := statusServer(t, http.StatusUnprocessableEntity)
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindBitbucket, APIBaseURL: srv.URL, Token: "tkn"})
+
+	if err := p.ReportCommitStatus(context.Background(), testStatus(scm.CommitStateSuccess)); err == nil {
+		t.Fatalf("expected error for API failure")
+	}
+}
+
+func TestReportCommitStatus_Invalid(t *testing.T) {
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindBitbucket, APIBaseURL: "http://127.0.0.1:1", Token: "tkn"})
+	st := testStatus(scm.CommitStateSuccess)
+	st.SHA = ""
+	if err := p.ReportCommitStatus(context.Background(), st); err == nil {
+		t.Fatalf("expected validation error")
+	}
+}
+
+func TestListChangedFiles(t *testing.T) {
+	srv, reqs := apiServer(t, http.StatusOK,
+		`{"values":[{"old":{"path":"old.go"},"new":{"path":"new.go"}},{"old":null,"new":{"path":"a.go"}}]}`)
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindBitbucket, APIBaseURL: srv.URL, Token: "tkn"})
+
+	files, err := p.ListChangedFiles(context.Background(), scm.Repo{Owner: "acme", Name: "app"}, "b1", "h1")
+	if err != nil {
+		t.Fatalf("ListChangedFiles: %v", err)
+	}
+	req := <-reqs
+	if req.path != "/repositories/acme/app/diffstat/h1..b1" {
+		t.Fatalf("unexpected path: %s", req.path)
+	}
+	if want := []string{"a.go", "new.go", "old.go"}; !reflect.DeepEqual(files, want) {
+		t.Fatalf("files = %v, want %v", files, want)
+	}
+}
+
+func TestParseWebhook_BranchPush(t *testing.T) {
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindBitbucket})
+	body := `{"repository":{"full_name":"acme/app"},"push":{"changes":[
+		{"new":{"type":"branch","name":"main","target":{"hash":"h1","message":"docs only"}},"old":{"target":{"hash":"b0"}}}]}}`
+	events, err := p.ParseWebhook(context.Background(), scm.WebhookRequest{
+		Headers: map[string]string{"X-Event-Key": "repo:push"},
+		Body:    []byte(body),
+	})
+	if err != nil || len(events) != 1 {
+		t.Fatalf("ParseWebhook: %v, %d events", err, len(events))
+	}
+	ev := events[0]
+	if ev.EventType != scm.EventTypePush || ev.Ref != "refs/heads/main" || ev.BeforeCommitID != "b0" {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+	if ev.ChangedFiles != nil {
+		t.Fatalf("expected files to be compared, got %v", ev.ChangedFiles)
+	}
+}
@@ -16,7 +16,9 @@ package builtin
 
 import (
 	// Register built-in SCM providers via side-effect imports.
+	_ "github.com/arcentrix/arcentra/pkg/scm/azuredevops"
 	_ "github.com/arcentrix/arcentra/pkg/scm/bitbucket"
+	_ "github.com/arcentrix/arcentra/pkg/scm/generic"
 	_ "github.com/arcentrix/arcentra/pkg/scm/gitea"
 	_ "github.com/arcentrix/arcentra/pkg/scm/gitee"
 	_ "github.com/arcentrix/arcentra/pkg/scm/github"
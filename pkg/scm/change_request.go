@@ -60,6 +60,8 @@ func providerKindFromRepoType(repoType string) (ProviderKind, error) {
 		return ProviderKindGitee, nil
 	case string(ProviderKindBitbucket):
 		return ProviderKindBitbucket, nil
+	case string(ProviderKindAzure):
+		return ProviderKindAzure, nil
 	default:
 		return "", fmt.Errorf("repo type '%s' does not support api pull request creation", repoType)
 	}
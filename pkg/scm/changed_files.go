@@ -0,0 +1,75 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scm
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// CollectChangedFiles merges file lists into a sorted list of unique,
+// non-empty paths. The result is never nil, so an event with no changed
+// files is distinguishable from one whose files are unknown.
+func CollectChangedFiles(lists ...[]string) []string {
+	out := []string{}
+	for _, files := range lists {
+		for _, f := range files {
+			f = strings.TrimPrefix(strings.TrimSpace(f), "/")
+			if f != "" {
+				out = append(out, f)
+			}
+		}
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// IsZeroCommitID reports whether sha is the all-zero id vendors send for the
+// missing side of a created or deleted ref.
+func IsZeroCommitID(sha string) bool {
+	sha = strings.TrimSpace(sha)
+	return sha != "" && strings.Trim(sha, "0") == ""
+}
+
+// FillChangedFiles populates ev.ChangedFiles through the provider's compare
+// API when the webhook payload did not carry them. Pushes are compared
+// against the previous head of the ref, PR/MR events against their target.
+// Events that cannot be compared (e.g. a newly created branch) are left
+// unchanged.
+func FillChangedFiles(ctx context.Context, p Provider, ev *Event) error {
+	if p == nil || ev == nil || ev.ChangedFiles != nil || !p.Capabilities().Has(CapCompare) {
+		return nil
+	}
+	base, head := ev.BeforeCommitID, ev.CommitID
+	if ev.Change != nil {
+		base, head = ev.Change.BaseCommitID, ev.Change.HeadCommitID
+		if base == "" {
+			base = ev.Change.TargetBranch
+		}
+		if head == "" {
+			head = ev.Change.SourceBranch
+		}
+	}
+	if base == "" || head == "" || IsZeroCommitID(base) || IsZeroCommitID(head) {
+		return nil
+	}
+	files, err := p.ListChangedFiles(ctx, ev.Repo, base, head)
+	if err != nil {
+		return err
+	}
+	ev.ChangedFiles = CollectChangedFiles(files)
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scm
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type compareProvider struct {
+	dummyProvider
+	calls int
+}
+
+func (p *compareProvider) Capabilities() CapSet {
+	return CapSet{CapCompare: true}
+}
+
+func (p *compareProvider) ListChangedFiles(ctx context.Context, repo Repo, base, head string) ([]string, error) {
+	p.calls++
+	return p.dummyProvider.ListChangedFiles(ctx, repo, base, head)
+}
+
+func TestCollectChangedFiles(t *testing.T) {
+	got := CollectChangedFiles([]string{"b.go", "/a.go", ""}, []string{"a.go", " c/d.go "})
+	want := []string{"a.go", "b.go", "c/d.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("CollectChangedFiles() = %v, want %v", got, want)
+	}
+	if got := CollectChangedFiles(); got == nil || len(got) != 0 {
+		t.Fatalf("expected empty non-nil list, got %#v", got)
+	}
+}
+
+func TestIsZeroCommitID(t *testing.T) {
+	if !IsZeroCommitID("0000000000000000000000000000000000000000") {
+		t.Fatalf("expected zero id")
+	}
+	if IsZeroCommitID("") || IsZeroCommitID("0a00") {
+		t.Fatalf("unexpected zero id")
+	}
+}
+
+func TestFillChangedFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		ev    Event
+		want  []string
+		calls int
+	}{
+		{
+			name:  "push",
+			ev:    Event{BeforeCommitID: "old", CommitID: "new"},
+			want:  []string{"new.txt", "old.txt"},
+			calls: 1,
+		},
+		{
+			name: "pull request falls back to branches",
+			ev: Event{Change: &Change{
+				TargetBranch: "main",
+				SourceBranch: "feature",
+			}},
+			want:  []string{"feature.txt", "main.txt"},
+			calls: 1,
+		},
+		{
+			name: "payload files kept",
+			ev:   Event{BeforeCommitID: "old", CommitID: "new", ChangedFiles: []string{"x"}},
+			want: []string{"x"},
+		},
+		{
+			name: "new branch",
+			ev:   Event{BeforeCommitID: "0000000", CommitID: "new"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &compareProvider{}
+			ev := tt.ev
+			if err := FillChangedFiles(context.Background(), p, &ev); err != nil {
+				t.Fatalf("FillChangedFiles: %v", err)
+			}
+			if !reflect.DeepEqual(ev.ChangedFiles, tt.want) {
+				t.Fatalf("ChangedFiles = %v, want %v", ev.ChangedFiles, tt.want)
+			}
+			if p.calls != tt.calls {
+				t.Fatalf("compare calls = %d, want %d", p.calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestFillChangedFiles_NoCapability(t *testing.T) {
+	ev := Event{BeforeCommitID: "old", CommitID: "new"}
+	if err := FillChangedFiles(context.Background(), &dummyProvider{}, &ev); err != nil {
+		t.Fatalf("FillChangedFiles: %v", err)
+	}
+	if ev.ChangedFiles != nil {
+		t.Fatalf("expected unknown files, got %v", ev.ChangedFiles)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scm
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// CommitState is the normalized state of a commit status.
+type CommitState string
+
+// Commit status states. Providers map them to their own vocabulary.
+const (
+	CommitStatePending CommitState = "pending"
+	CommitStateSuccess CommitState = "success"
+	CommitStateFailure CommitState = "failure"
+	CommitStateError   CommitState = "error"
+)
+
+// MaxStatusDescription is the longest description every provider accepts.
+const MaxStatusDescription = 140
+
+// CommitStatus is a build result reported on a commit, shown next to the
+// commit and on the pull/merge requests containing it.
+type CommitStatus struct {
+	Repo Repo
+	SHA  string
+	// State of the build.
+	State CommitState
+	// Context names the status. A later status with the same context
+	// replaces the earlier one, e.g. "arcentra/build".
+	Context     string
+	Description string
+	// TargetURL links the status to the build, e.g. the run page.
+	TargetURL string
+}
+
+// Validate checks the fields every provider requires.
+func (s CommitStatus) Validate() error {
+	if s.Repo.Owner == "" || s.Repo.Name == "" {
+		return fmt.Errorf("repo owner/name is required")
+	}
+	if strings.TrimSpace(s.SHA) == "" {
+		return fmt.Errorf("commit sha is required")
+	}
+	if strings.TrimSpace(s.Context) == "" {
+		return fmt.Errorf("status context is required")
+	}
+	switch s.State {
+	case CommitStatePending, CommitStateSuccess, CommitStateFailure, CommitStateError:
+		return nil
+	default:
+		return fmt.Errorf("invalid commit state: %q", s.State)
+	}
+}
+
+// ShortDescription returns the description cut to MaxStatusDescription
+// characters.
+func (s CommitStatus) ShortDescription() string {
+	if utf8.RuneCountInString(s.Description) <= MaxStatusDescription {
+		return s.Description
+	}
+	runes := []rune(s.Description)
+	return string(runes[:MaxStatusDescription-3]) + "..."
+}
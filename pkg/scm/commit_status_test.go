@@ -0,0 +1,63 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scm
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCommitStatus_Validate(t *testing.T) {
+	ok := CommitStatus{
+		Repo:    Repo{Owner: "o", Name: "r"},
+		SHA:     "abc123",
+		State:   CommitStateSuccess,
+		Context: "arcentra/build",
+	}
+	if err := ok.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := map[string]func(s *CommitStatus){
+		"missing repo":    func(s *CommitStatus) { s.Repo = Repo{} },
+		"missing sha":     func(s *CommitStatus) { s.SHA = " " },
+		"missing context": func(s *CommitStatus) { s.Context = "" },
+		"invalid state":   func(s *CommitStatus) { s.State = "running" },
+	}
+	for name, mutate := range cases {
+		s := ok
+		mutate(&s)
+		if err := s.Validate(); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestCommitStatus_ShortDescription(t *testing.T) {
+	s := CommitStatus{Description: "build passed"}
+	if got := s.ShortDescription(); got != "build passed" {
+		t.Fatalf("unexpected description: %q", got)
+	}
+
+	s.Description = strings.Repeat("构建", MaxStatusDescription)
+	got := s.ShortDescription()
+	if n := utf8.RuneCountInString(got); n != MaxStatusDescription {
+		t.Fatalf("expected %d runes, got %d", MaxStatusDescription, n)
+	}
+	if !strings.HasSuffix(got, "...") {
+		t.Fatalf("expected ellipsis, got %q", got)
+	}
+}
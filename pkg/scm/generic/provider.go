@@ -0,0 +1,131 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generic
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arcentrix/arcentra/pkg/scm"
+	"github.com/bytedance/sonic"
+)
+
+// Provider talks to any git server through the git protocol. It has no
+// webhooks; PollEvents diffs snapshots of the remote refs instead, so every
+// moved branch or tag between two polls becomes a push or tag event.
+type Provider struct {
+	cfg scm.ProviderConfig
+}
+
+func New(cfg scm.ProviderConfig) (scm.Provider, error) {
+	if cfg.Git.Token == "" && cfg.Token != "" {
+		cfg.Git.Token = cfg.Token
+	}
+	return &Provider{cfg: cfg}, nil
+}
+
+func (p *Provider) Kind() scm.ProviderKind { return scm.ProviderKindGit }
+
+func (p *Provider) Capabilities() scm.CapSet {
+	return scm.CapSet{
+		scm.CapPollEvents: true,
+		scm.CapCompare:    true,
+	}
+}
+
+func (p *Provider) VerifyWebhook(context.Context, scm.WebhookRequest, string) error {
+	return fmt.Errorf("generic git provider does not support webhooks")
+}
+
+func (p *Provider) ParseWebhook(context.Context, scm.WebhookRequest) ([]scm.Event, error) {
+	return nil, nil
+}
+
+// PollEvents lists the remote refs and compares them with the snapshot kept
+// in cursor.Opaque. The first poll only records the snapshot. Deleted refs
+// produce no event.
+func (p *Provider) PollEvents(ctx context.Context, repo scm.Repo, cursor scm.Cursor) ([]scm.Event, scm.Cursor, error) {
+	if strings.TrimSpace(repo.URL) == "" {
+		return nil, cursor, fmt.Errorf("repo url is required")
+	}
+	refs, err := scm.LsRemote(ctx, scm.GitLsRemoteRequest{RepoURL: repo.URL, Auth: p.cfg.Git})
+	if err != nil {
+		return nil, cursor, err
+	}
+	opaque, err := sonic.MarshalString(refs)
+	if err != nil {
+		return nil, cursor, err
+	}
+	now := time.Now()
+	next := scm.Cursor{Since: now, Opaque: opaque}
+	if cursor.Opaque == "" {
+		return nil, next, nil
+	}
+	var previous map[string]string
+	if err := sonic.UnmarshalString(cursor.Opaque, &previous); err != nil {
+		return nil, cursor, fmt.Errorf("invalid ref snapshot in cursor: %w", err)
+	}
+
+	names := make([]string, 0, len(refs))
+	for ref := range refs {
+		names = append(names, ref)
+	}
+	sort.Strings(names)
+	events := make([]scm.Event, 0)
+	for _, ref := range names {
+		sha, before := refs[ref], previous[ref]
+		if sha == before {
+			continue
+		}
+		t := scm.EventTypePush
+		if strings.HasPrefix(ref, "refs/tags/") {
+			t = scm.EventTypeTag
+		}
+		events = append(events, scm.Event{
+			ProviderKind:   scm.ProviderKindGit,
+			EventType:      t,
+			Repo:           repo,
+			CommitID:       sha,
+			BeforeCommitID: before,
+			Ref:            ref,
+			OccurredAt:     now,
+		})
+	}
+	return events, next, nil
+}
+
+func (p *Provider) CreateChangeRequest(context.Context, scm.ChangeRequestInput) (string, error) {
+	return "", fmt.Errorf("generic git provider does not support change requests")
+}
+
+func (p *Provider) ReportCommitStatus(context.Context, scm.CommitStatus) error {
+	return fmt.Errorf("generic git provider does not support commit statuses")
+}
+
+// ListChangedFiles fetches the trees of base and head and diffs them. Both
+// must be commit SHAs the server lets clients fetch directly.
+func (p *Provider) ListChangedFiles(ctx context.Context, repo scm.Repo, base, head string) ([]string, error) {
+	if strings.TrimSpace(repo.URL) == "" {
+		return nil, fmt.Errorf("repo url is required")
+	}
+	return scm.DiffNames(ctx, scm.GitDiffRequest{RepoURL: repo.URL, Base: base, Head: head, Auth: p.cfg.Git})
+}
+
+func init() {
+	scm.Register(scm.ProviderKindGit, New)
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generic
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arcentrix/arcentra/pkg/scm"
+)
+
+// gitRepo is a throwaway repository served over the file protocol.
+type gitRepo struct {
+	t   *testing.T
+	dir string
+}
+
+func newGitRepo(t *testing.T) *gitRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	r := &gitRepo{t: t, dir: t.TempDir()}
+	r.git("init", "-q", "-b", "main")
+	r.git("config", "user.email", "dev@example.com")
+	r.git("config", "user.name", "dev")
+	r.git("config", "uploadpack.allowAnySHA1InWant", "true")
+	return r
+}
+
+func (r *gitRepo) git(args ...string) string {
+	r.t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %v: %v: %s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// commit writes files and commits them, returning the commit SHA.
+func (r *gitRepo) commit(files map[string]string) string {
+	r.t.Helper()
+	for name, content := range files {
+		path := filepath.Join(r.dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			r.t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			r.t.Fatal(err)
+		}
+	}
+	r.git("add", "-A")
+	r.git("commit", "-q", "-m", "change")
+	return r.git("rev-parse", "HEAD")
+}
+
+func (r *gitRepo) url() string { return "file://" + r.dir }
+
+func TestPollEvents(t *testing.T) {
+	r := newGitRepo(t)
+	first := r.commit(map[string]string{"a.txt": "a"})
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGit})
+	repo := scm.Repo{URL: r.url()}
+	ctx := context.Background()
+
+	events, cursor, err := p.PollEvents(ctx, repo, scm.Cursor{})
+	if err != nil {
+		t.Fatalf("PollEvents: %v", err)
+	}
+	if len(events) != 0 || cursor.Opaque == "" {
+		t.Fatalf("first poll should only snapshot: %v, %+v", events, cursor)
+	}
+
+	events, cursor, err = p.PollEvents(ctx, repo, cursor)
+	if err != nil || len(events) != 0 {
+		t.Fatalf("unchanged poll: %v, %v", err, events)
+	}
+
+	second := r.commit(map[string]string{"b.txt": "b"})
+	r.git("tag", "-a", "v1", "-m", "release")
+	r.git("branch", "feature", first)
+	events, _, err = p.PollEvents(ctx, repo, cursor)
+	if err != nil {
+		t.Fatalf("PollEvents: %v", err)
+	}
+	got := map[string]scm.Event{}
+	for _, ev := range events {
+		got[ev.Ref] = ev
+	}
+	if len(got) != 3 {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	if ev := got["refs/heads/main"]; ev.EventType != scm.EventTypePush || ev.CommitID != second || ev.BeforeCommitID != first {
+		t.Fatalf("unexpected main push: %+v", ev)
+	}
+	if ev := got["refs/heads/feature"]; ev.EventType != scm.EventTypePush || ev.CommitID != first || ev.BeforeCommitID != "" {
+		t.Fatalf("unexpected new branch: %+v", ev)
+	}
+	// Annotated tags resolve to the tagged commit.
+	if ev := got["refs/tags/v1"]; ev.EventType != scm.EventTypeTag || ev.CommitID != second {
+		t.Fatalf("unexpected tag: %+v", ev)
+	}
+}
+
+func TestPollEvents_InvalidCursor(t *testing.T) {
+	r := newGitRepo(t)
+	r.commit(map[string]string{"a.txt": "a"})
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGit})
+	if _, _, err := p.PollEvents(context.Background(), scm.Repo{URL: r.url()}, scm.Cursor{Opaque: "not json"}); err == nil {
+		t.Fatalf("expected error for invalid cursor")
+	}
+}
+
+func TestPollEvents_Cancelled(t *testing.T) {
+	r := newGitRepo(t)
+	r.commit(map[string]string{"a.txt": "a"})
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGit})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cursor := scm.Cursor{Opaque: "{}"}
+	_, next, err := p.PollEvents(ctx, scm.Repo{URL: r.url()}, cursor)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if next != cursor {
+		t.Fatalf("cursor advanced on a cancelled poll: %+v", next)
+	}
+}
+
+func TestListChangedFiles(t *testing.T) {
+	r := newGitRepo(t)
+	base := r.commit(map[string]string{"a.txt": "a", "docs/x.md": "x"})
+	head := r.commit(map[string]string{"a.txt": "a2", "src/b.go": "package b"})
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGit})
+
+	files, err := p.ListChangedFiles(context.Background(), scm.Repo{URL: r.url()}, base, head)
+	if err != nil {
+		t.Fatalf("ListChangedFiles: %v", err)
+	}
+	if want := []string{"a.txt", "src/b.go"}; !reflect.DeepEqual(files, want) {
+		t.Fatalf("files = %v, want %v", files, want)
+	}
+}
+
+func TestWebhooksUnsupported(t *testing.T) {
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGit})
+	if p.Capabilities().Has(scm.CapWebhookParse) {
+		t.Fatalf("generic provider must not claim webhook support")
+	}
+	if err := p.VerifyWebhook(context.Background(), scm.WebhookRequest{}, "secret"); err == nil {
+		t.Fatalf("expected webhook verification to fail")
+	}
+}
@@ -15,6 +15,7 @@
 package scm
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -54,6 +55,91 @@ func HeadSHA(req GitHeadSHARequest) (string, error) {
 	return strings.TrimSpace(out), nil
 }
 
+// Fetch fetches a single ref or commit from remote without history.
+func Fetch(req GitFetchRequest) error {
+	remote := req.Remote
+	if remote == "" {
+		remote = "origin"
+	}
+	return runGit(req.Workdir, req.Auth, "fetch", "--depth", "1", remote, req.Ref)
+}
+
+// CheckoutDetached switches the worktree to ref without creating a branch.
+func CheckoutDetached(req GitCheckoutRequest) error {
+	return runGit(req.Workdir, GitAuth{}, "checkout", "--detach", req.Ref)
+}
+
+// LsRemote lists the branch and tag heads of a remote repository keyed by
+// ref name. Annotated tags resolve to the commit they point at. The git
+// process is killed when ctx is done.
+func LsRemote(ctx context.Context, req GitLsRemoteRequest) (map[string]string, error) {
+	out, err := runGitOutputContext(ctx, "", req.Auth, "ls-remote", "--heads", "--tags", req.RepoURL)
+	if err != nil {
+		return nil, err
+	}
+	refs := map[string]string{}
+	peeled := map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		sha, ref, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		// git may interleave warnings with the listing
+		if !ok || !isCommitID(sha) {
+			continue
+		}
+		if name, isPeeled := strings.CutSuffix(ref, "^{}"); isPeeled {
+			peeled[name] = sha
+			continue
+		}
+		refs[ref] = sha
+	}
+	for ref, sha := range peeled {
+		refs[ref] = sha
+	}
+	return refs, nil
+}
+
+// DiffNames lists the paths changed between two commits of a remote
+// repository. Only the trees of both commits are fetched. The git processes
+// are killed when ctx is done.
+func DiffNames(ctx context.Context, req GitDiffRequest) ([]string, error) {
+	workdir, err := os.MkdirTemp("", "arcentra-git-diff-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(workdir) }()
+
+	if _, err := runGitOutputContext(ctx, workdir, GitAuth{}, "init", "--bare", "-q"); err != nil {
+		return nil, err
+	}
+	_, err = runGitOutputContext(ctx, workdir, req.Auth, "fetch", "-q", "--depth", "1", "--filter=blob:none", req.RepoURL, req.Base, req.Head)
+	if err != nil {
+		return nil, err
+	}
+	out, err := runGitOutputContext(ctx, workdir, GitAuth{}, "diff", "--name-only", "--no-renames", req.Base, req.Head)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return CollectChangedFiles(files), nil
+}
+
+// isCommitID reports whether s is a full SHA-1 or SHA-256 object name.
+func isCommitID(s string) bool {
+	if len(s) != 40 && len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
 // Add stages file path into git index.
 func Add(req GitAddRequest) error {
 	return runGit(req.Workdir, GitAuth{}, "add", req.FilePath)
@@ -84,7 +170,11 @@ func runGit(workdir string, auth GitAuth, args ...string) error {
 }
 
 func runGitOutput(workdir string, auth GitAuth, args ...string) (string, error) {
-	cmd := exec.Command("git", args...)
+	return runGitOutputContext(context.Background(), workdir, auth, args...)
+}
+
+func runGitOutputContext(ctx context.Context, workdir string, auth GitAuth, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
 	cmd.Dir = workdir
 	cmd.Env = os.Environ()
 	cmd.Env = append(cmd.Env, "GIT_TERMINAL_PROMPT=0")
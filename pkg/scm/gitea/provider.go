@@ -49,6 +49,8 @@ func (p *Provider) Capabilities() scm.CapSet {
 		scm.CapWebhookVerify: true,
 		scm.CapWebhookParse:  true,
 		scm.CapPollEvents:    true,
+		scm.CapCommitStatus:  true,
+		scm.CapCompare:       true,
 	}
 }
 
@@ -292,6 +294,92 @@ func (p *Provider) tagCommitTime(ctx context.Context, repo scm.Repo, sha string)
 	return out2.Committer.Date, out2.Sha
 }
 
+// ReportCommitStatus creates a Gitea commit status.
+func (p *Provider) ReportCommitStatus(ctx context.Context, status scm.CommitStatus) error {
+	if err := status.Validate(); err != nil {
+		return err
+	}
+	apiBase := p.apiBaseURL()
+	if apiBase == "" {
+		return fmt.Errorf("gitea baseUrl or apiBaseUrl is required")
+	}
+	endpoint := apiBase + fmt.Sprintf(
+		"/repos/%s/%s/statuses/%s",
+		url.PathEscape(status.Repo.Owner),
+		url.PathEscape(status.Repo.Name),
+		url.PathEscape(status.SHA),
+	)
+	resp, err := request.NewRequest(
+		endpoint,
+		fasthttp.MethodPost,
+		map[string]string{
+			"Authorization": "Bearer " + strings.TrimSpace(p.cfg.Token),
+		},
+		nil,
+	).
+		WithBodyJSON(map[string]any{
+			"state":       string(status.State),
+			"target_url":  status.TargetURL,
+			"description": status.ShortDescription(),
+			"context":     status.Context,
+		}).
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+	if resp == nil || resp.StatusCode() >= 400 {
+		return fmt.Errorf("gitea create commit status failed: %d", resp.StatusCode())
+	}
+	return nil
+}
+
+// ListChangedFiles lists the files changed between base and head through the
+// Gitea compare API.
+func (p *Provider) ListChangedFiles(ctx context.Context, repo scm.Repo, base, head string) ([]string, error) {
+	if repo.Owner == "" || repo.Name == "" {
+		return nil, fmt.Errorf("repo owner/name is required")
+	}
+	apiBase := p.apiBaseURL()
+	if apiBase == "" {
+		return nil, fmt.Errorf("gitea baseUrl or apiBaseUrl is required")
+	}
+	endpoint := apiBase + fmt.Sprintf(
+		"/repos/%s/%s/compare/%s...%s",
+		url.PathEscape(repo.Owner),
+		url.PathEscape(repo.Name),
+		url.PathEscape(base),
+		url.PathEscape(head),
+	)
+	var out struct {
+		Commits []struct {
+			Files []struct {
+				Filename string `json:"filename"`
+			} `json:"files"`
+		} `json:"commits"`
+	}
+	resp, err := request.NewRequest(
+		endpoint,
+		fasthttp.MethodGet,
+		map[string]string{
+			"Authorization": "Bearer " + strings.TrimSpace(p.cfg.Token),
+		},
+		nil,
+	).WithResult(&out).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.StatusCode() >= 400 {
+		return nil, fmt.Errorf("gitea compare commits failed: %d", resp.StatusCode())
+	}
+	var files []string
+	for _, c := range out.Commits {
+		for _, f := range c.Files {
+			files = append(files, f.Filename)
+		}
+	}
+	return scm.CollectChangedFiles(files), nil
+}
+
 func (p *Provider) apiBaseURL() string {
 	apiBase := strings.TrimSpace(p.cfg.APIBaseURL)
 	if apiBase == "" {
@@ -325,11 +413,16 @@ func (p *Provider) parsePullRequest(body []byte) ([]scm.Event, error) {
 			MergedAt  *time.Time `json:"merged_at"`
 			MergeSha  string     `json:"merge_commit_sha"`
 			UpdatedAt *time.Time `json:"updated_at"`
-			Base      struct {
+			Labels    []struct {
+				Name string `json:"name"`
+			} `json:"labels"`
+			Base struct {
 				Ref string `json:"ref"`
+				Sha string `json:"sha"`
 			} `json:"base"`
 			Head struct {
 				Ref string `json:"ref"`
+				Sha string `json:"sha"`
 			} `json:"head"`
 		} `json:"pull_request"`
 	}
@@ -353,6 +446,10 @@ func (p *Provider) parsePullRequest(body []byte) ([]scm.Event, error) {
 	if payload.Action == "closed" && payload.PullRequest.Merged {
 		t = scm.EventTypePullMerged
 	}
+	labels := make([]string, 0, len(payload.PullRequest.Labels))
+	for _, l := range payload.PullRequest.Labels {
+		labels = append(labels, l.Name)
+	}
 	return []scm.Event{{
 		ProviderKind: scm.ProviderKindGitea,
 		EventType:    t,
@@ -363,8 +460,12 @@ func (p *Provider) parsePullRequest(body []byte) ([]scm.Event, error) {
 		Change: &scm.Change{
 			Number:        payload.PullRequest.Number,
 			Title:         payload.PullRequest.Title,
+			Action:        pullRequestAction(payload.Action, t == scm.EventTypePullMerged),
 			SourceBranch:  payload.PullRequest.Head.Ref,
 			TargetBranch:  payload.PullRequest.Base.Ref,
+			BaseCommitID:  payload.PullRequest.Base.Sha,
+			HeadCommitID:  payload.PullRequest.Head.Sha,
+			Labels:        labels,
 			State:         payload.PullRequest.State,
 			IsMerged:      t == scm.EventTypePullMerged,
 			MergeCommitID: payload.PullRequest.MergeSha,
@@ -372,10 +473,34 @@ func (p *Provider) parsePullRequest(body []byte) ([]scm.Event, error) {
 	}}, nil
 }
 
+// pullRequestAction maps a Gitea pull request action to the normalized action.
+func pullRequestAction(action string, merged bool) string {
+	switch action {
+	case "synchronized":
+		return scm.ChangeActionSynchronize
+	case "label_updated":
+		return scm.ChangeActionLabeled
+	case "label_cleared":
+		return scm.ChangeActionUnlabeled
+	case "closed":
+		if merged {
+			return scm.ChangeActionMerged
+		}
+		return scm.ChangeActionClosed
+	default:
+		return action
+	}
+}
+
 func (p *Provider) parsePush(body []byte) ([]scm.Event, error) {
 	var payload struct {
-		Ref        string `json:"ref"`
-		After      string `json:"after"`
+		Ref          string `json:"ref"`
+		Before       string `json:"before"`
+		After        string `json:"after"`
+		TotalCommits int    `json:"total_commits"`
+		HeadCommit   struct {
+			Message string `json:"message"`
+		} `json:"head_commit"`
 		Repository struct {
 			HTMLURL string `json:"html_url"`
 			Name    string `json:"name"`
@@ -390,6 +515,9 @@ func (p *Provider) parsePush(body []byte) ([]scm.Event, error) {
 		Commits []struct {
 			ID        string    `json:"id"`
 			Timestamp time.Time `json:"timestamp"`
+			Added     []string  `json:"added"`
+			Removed   []string  `json:"removed"`
+			Modified  []string  `json:"modified"`
 		} `json:"commits"`
 	}
 	if err := sonic.Unmarshal(body, &payload); err != nil {
@@ -413,14 +541,25 @@ func (p *Provider) parsePush(body []byte) ([]scm.Event, error) {
 	if actor == "" {
 		actor = payload.Pusher.FullName
 	}
+	// Gitea may list fewer commits than were pushed; leave files unknown then.
+	var files []string
+	if len(payload.Commits) > 0 && len(payload.Commits) >= payload.TotalCommits {
+		files = []string{}
+		for _, c := range payload.Commits {
+			files = scm.CollectChangedFiles(files, c.Added, c.Removed, c.Modified)
+		}
+	}
 	return []scm.Event{{
-		ProviderKind: scm.ProviderKindGitea,
-		EventType:    t,
-		Repo:         repo,
-		ActorName:    actor,
-		CommitID:     payload.After,
-		Ref:          payload.Ref,
-		OccurredAt:   occurred,
+		ProviderKind:   scm.ProviderKindGitea,
+		EventType:      t,
+		Repo:           repo,
+		ActorName:      actor,
+		CommitID:       payload.After,
+		BeforeCommitID: payload.Before,
+		Message:        payload.HeadCommit.Message,
+		ChangedFiles:   files,
+		Ref:            payload.Ref,
+		OccurredAt:     occurred,
 	}}, nil
 }
 
@@ -42,6 +42,8 @@ func (p *Provider) Capabilities() scm.CapSet {
 		scm.CapWebhookVerify: true,
 		scm.CapWebhookParse:  true,
 		scm.CapPollEvents:    true,
+		scm.CapCommitStatus:  true,
+		scm.CapCompare:       true,
 	}
 }
 
@@ -263,6 +265,94 @@ func (p *Provider) commitTime(ctx context.Context, repo scm.Repo, sha string) ti
 	return out.Commit.Committer.Date
 }
 
+// ReportCommitStatus reports the status as a Gitee check run named after
+// the context. Gitee has no commit status API; a new check run is created
+// per report and the latest one of a name is shown.
+func (p *Provider) ReportCommitStatus(ctx context.Context, status scm.CommitStatus) error {
+	if err := status.Validate(); err != nil {
+		return err
+	}
+	body := map[string]any{
+		"access_token": strings.TrimSpace(p.cfg.Token),
+		"name":         status.Context,
+		"head_sha":     status.SHA,
+		"details_url":  status.TargetURL,
+		"output": map[string]any{
+			"title":   status.Context,
+			"summary": status.ShortDescription(),
+		},
+	}
+	switch status.State {
+	case scm.CommitStatePending:
+		body["status"] = "in_progress"
+	case scm.CommitStateSuccess:
+		body["status"] = "completed"
+		body["conclusion"] = "success"
+	default:
+		body["status"] = "completed"
+		body["conclusion"] = "failure"
+	}
+	endpoint := p.apiBaseURL() + fmt.Sprintf(
+		"/repos/%s/%s/check-runs",
+		url.PathEscape(status.Repo.Owner),
+		url.PathEscape(status.Repo.Name),
+	)
+	resp, err := request.NewRequest(
+		endpoint,
+		fasthttp.MethodPost,
+		map[string]string{
+			"Content-Type": "application/json",
+		},
+		nil,
+	).
+		WithBodyJSON(body).
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+	if resp == nil || resp.StatusCode() >= 400 {
+		return fmt.Errorf("gitee create check run failed: %d", resp.StatusCode())
+	}
+	return nil
+}
+
+// ListChangedFiles lists the files changed between base and head through the
+// Gitee compare API.
+func (p *Provider) ListChangedFiles(ctx context.Context, repo scm.Repo, base, head string) ([]string, error) {
+	if repo.Owner == "" || repo.Name == "" {
+		return nil, fmt.Errorf("repo owner/name is required")
+	}
+	endpoint := p.apiBaseURL() + fmt.Sprintf(
+		"/repos/%s/%s/compare/%s...%s",
+		url.PathEscape(repo.Owner),
+		url.PathEscape(repo.Name),
+		url.PathEscape(base),
+		url.PathEscape(head),
+	)
+	var out struct {
+		Files []struct {
+			Filename string `json:"filename"`
+		} `json:"files"`
+	}
+	resp, err := request.NewRequest(endpoint, fasthttp.MethodGet, nil, nil).
+		WithQueryParams(map[string]string{
+			"access_token": strings.TrimSpace(p.cfg.Token),
+		}).
+		WithResult(&out).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.StatusCode() >= 400 {
+		return nil, fmt.Errorf("gitee compare commits failed: %d", resp.StatusCode())
+	}
+	files := make([]string, 0, len(out.Files))
+	for _, f := range out.Files {
+		files = append(files, f.Filename)
+	}
+	return scm.CollectChangedFiles(files), nil
+}
+
 func (p *Provider) apiBaseURL() string {
 	apiBase := strings.TrimSpace(p.cfg.APIBaseURL)
 	if apiBase == "" {
@@ -273,6 +363,8 @@ func (p *Provider) apiBaseURL() string {
 
 func (p *Provider) parsePullRequest(body []byte) ([]scm.Event, error) {
 	var payload struct {
+		Action      string `json:"action"`
+		ActionDesc  string `json:"action_desc"`
 		PullRequest struct {
 			Number    int        `json:"number"`
 			Title     string     `json:"title"`
@@ -281,11 +373,16 @@ func (p *Provider) parsePullRequest(body []byte) ([]scm.Event, error) {
 			MergedAt  *time.Time `json:"merged_at"`
 			MergeSha  string     `json:"merge_commit_sha"`
 			UpdatedAt *time.Time `json:"updated_at"`
-			Head      struct {
+			Labels    []struct {
+				Name string `json:"name"`
+			} `json:"labels"`
+			Head struct {
 				Ref string `json:"ref"`
+				Sha string `json:"sha"`
 			} `json:"head"`
 			Base struct {
 				Ref string `json:"ref"`
+				Sha string `json:"sha"`
 			} `json:"base"`
 		} `json:"pull_request"`
 		Repository struct {
@@ -323,6 +420,10 @@ func (p *Provider) parsePullRequest(body []byte) ([]scm.Event, error) {
 	if actor == "" {
 		actor = payload.Sender.Name
 	}
+	labels := make([]string, 0, len(payload.PullRequest.Labels))
+	for _, l := range payload.PullRequest.Labels {
+		labels = append(labels, l.Name)
+	}
 	return []scm.Event{{
 		ProviderKind: scm.ProviderKindGitee,
 		EventType:    t,
@@ -333,8 +434,12 @@ func (p *Provider) parsePullRequest(body []byte) ([]scm.Event, error) {
 		Change: &scm.Change{
 			Number:        payload.PullRequest.Number,
 			Title:         payload.PullRequest.Title,
+			Action:        pullRequestAction(payload.Action, payload.ActionDesc),
 			SourceBranch:  payload.PullRequest.Head.Ref,
 			TargetBranch:  payload.PullRequest.Base.Ref,
+			BaseCommitID:  payload.PullRequest.Base.Sha,
+			HeadCommitID:  payload.PullRequest.Head.Sha,
+			Labels:        labels,
 			State:         payload.PullRequest.State,
 			IsMerged:      t == scm.EventTypePullMerged,
 			MergeCommitID: payload.PullRequest.MergeSha,
@@ -342,11 +447,42 @@ func (p *Provider) parsePullRequest(body []byte) ([]scm.Event, error) {
 	}}, nil
 }
 
+// pullRequestAction maps a Gitee pull request action to the normalized
+// action; updates are told apart by their action description.
+func pullRequestAction(action, desc string) string {
+	switch action {
+	case "open":
+		return scm.ChangeActionOpened
+	case "reopen":
+		return scm.ChangeActionReopened
+	case "close":
+		return scm.ChangeActionClosed
+	case "merge":
+		return scm.ChangeActionMerged
+	case "update":
+		switch {
+		case desc == "source_branch_changed":
+			return scm.ChangeActionSynchronize
+		case strings.Contains(desc, "label"):
+			return scm.ChangeActionLabeled
+		default:
+			return scm.ChangeActionEdited
+		}
+	default:
+		return action
+	}
+}
+
 func (p *Provider) parsePush(body []byte) ([]scm.Event, error) {
 	var payload struct {
-		Ref        string `json:"ref"`
-		After      string `json:"after"`
-		HookName   string `json:"hook_name"`
+		Ref               string `json:"ref"`
+		Before            string `json:"before"`
+		After             string `json:"after"`
+		HookName          string `json:"hook_name"`
+		TotalCommitsCount int    `json:"total_commits_count"`
+		HeadCommit        struct {
+			Message string `json:"message"`
+		} `json:"head_commit"`
 		Repository struct {
 			PathWithNamespace string `json:"path_with_namespace"`
 			Namespace         string `json:"namespace"`
@@ -356,6 +492,9 @@ func (p *Provider) parsePush(body []byte) ([]scm.Event, error) {
 		Commits []struct {
 			ID        string    `json:"id"`
 			Timestamp time.Time `json:"timestamp"`
+			Added     []string  `json:"added"`
+			Removed   []string  `json:"removed"`
+			Modified  []string  `json:"modified"`
 		} `json:"commits"`
 		Sender struct {
 			UserName string `json:"user_name"`
@@ -390,14 +529,25 @@ func (p *Provider) parsePush(body []byte) ([]scm.Event, error) {
 	if actor == "" {
 		actor = payload.Sender.Name
 	}
+	// Gitee may list fewer commits than were pushed; leave files unknown then.
+	var files []string
+	if len(payload.Commits) > 0 && len(payload.Commits) >= payload.TotalCommitsCount {
+		files = []string{}
+		for _, c := range payload.Commits {
+			files = scm.CollectChangedFiles(files, c.Added, c.Removed, c.Modified)
+		}
+	}
 	return []scm.Event{{
-		ProviderKind: scm.ProviderKindGitee,
-		EventType:    t,
-		Repo:         repo,
-		ActorName:    actor,
-		CommitID:     payload.After,
-		Ref:          payload.Ref,
-		OccurredAt:   occurred,
+		ProviderKind:   scm.ProviderKindGitee,
+		EventType:      t,
+		Repo:           repo,
+		ActorName:      actor,
+		CommitID:       payload.After,
+		BeforeCommitID: payload.Before,
+		Message:        payload.HeadCommit.Message,
+		ChangedFiles:   files,
+		Ref:            payload.Ref,
+		OccurredAt:     occurred,
 	}}, nil
 }
 
@@ -0,0 +1,163 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitee
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/arcentrix/arcentra/pkg/scm"
+)
+
+// capturedRequest is what the stand-in API received.
+type capturedRequest struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   map[string]any
+}
+
+// apiServer stands in for the Gitee API. It answers every request with
+// code and response and hands over each request it receives.
+func apiServer(t *testing.T, code int, response string) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+	reqs := make(chan capturedRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]any{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		reqs <- capturedRequest{
+			method: r.Method,
+			path:   r.URL.EscapedPath(),
+			query:  r.URL.Query(),
+			header: r.Header.Clone(),
+			body:   body,
+		}
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, reqs
+}
+
+func statusServer(t *testing.T, code int) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+	return apiServer(t, code, `{}`)
+}
+
+func testStatus(state scm.CommitState) scm.CommitStatus {
+	return scm.CommitStatus{
+		Repo:        scm.Repo{Owner: "acme", Name: "app"},
+		SHA:         "0123abcd",
+		State:       state,
+		Context:     "arcentra/build",
+		Description: "Pipeline running",
+		TargetURL:   "https://ci.example.com/pipelines/p1/runs/r1",
+	}
+}
+
+func TestReportCommitStatus(t *testing.T) {
+	srv, reqs := statusServer(t, http.StatusCreated)
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGitee, APIBaseURL: srv.URL, Token: "tkn"})
+
+	if err := p.ReportCommitStatus(context.Background(), testStatus(scm.CommitStateSuccess)); err != nil {
+		t.Fatalf("ReportCommitStatus: %v", err)
+	}
+	req := <-reqs
+	body := req.body
+	if req.method != http.MethodPost {
+		t.Fatalf("unexpected method: %s", req.method)
+	}
+	if req.path != "/repos/acme/app/check-runs" {
+		t.Fatalf("unexpected path: %s", req.path)
+	}
+
+	if body["access_token"] != "tkn" {
+		t.Fatalf("unexpected access_token: %v", body["access_token"])
+	}
+	if body["name"] != "arcentra/build" {
+		t.Fatalf("unexpected name: %v", body["name"])
+	}
+	if body["head_sha"] != "0123abcd" {
+		t.Fatalf("unexpected head_sha: %v", body["head_sha"])
+	}
+	if body["status"] != "completed" {
+		t.Fatalf("unexpected status: %v", body["status"])
+	}
+	if body["conclusion"] != "success" {
+		t.Fatalf("unexpected conclusion: %v", body["conclusion"])
+	}
+	if body["details_url"] != "https://ci.example.com/pipelines/p1/runs/r1" {
+		t.Fatalf("unexpected details_url: %v", body["details_url"])
+	}
+}
+
+func TestReportCommitStatus_APIError(t *testing.T) {
+	srv, _ := statusServer(t, http.StatusUnprocessableEntity)
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGitee, APIBaseURL: srv.URL, Token: "tkn"})
+
+	if err := p.ReportCommitStatus(context.Background(), testStatus(scm.CommitStateSuccess)); err == nil {
+		t.Fatalf("expected error for API failure")
+	}
+}
+
+func TestReportCommitStatus_Invalid(t *testing.T) {
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGitee, APIBaseURL: "http://127.0.0.1:1", Token: "tkn"})
+	st := testStatus(scm.CommitStateSuccess)
+	st.SHA = ""
+	if err := p.ReportCommitStatus(context.Background(), st); err == nil {
+		t.Fatalf("expected validation error")
+	}
+}
+
+func TestListChangedFiles(t *testing.T) {
+	srv, reqs := apiServer(t, http.StatusOK, `{"files":[{"filename":"b.go"},{"filename":"a.go"}]}`)
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGitee, APIBaseURL: srv.URL, Token: "tkn"})
+
+	files, err := p.ListChangedFiles(context.Background(), scm.Repo{Owner: "acme", Name: "app"}, "b1", "h1")
+	if err != nil {
+		t.Fatalf("ListChangedFiles: %v", err)
+	}
+	req := <-reqs
+	if req.path != "/repos/acme/app/compare/b1...h1" {
+		t.Fatalf("unexpected path: %s", req.path)
+	}
+	if req.query.Get("access_token") != "tkn" {
+		t.Fatalf("unexpected query: %v", req.query)
+	}
+	if want := []string{"a.go", "b.go"}; !reflect.DeepEqual(files, want) {
+		t.Fatalf("files = %v, want %v", files, want)
+	}
+}
+
+func TestPullRequestAction(t *testing.T) {
+	tests := []struct{ action, desc, want string }{
+		{"open", "", scm.ChangeActionOpened},
+		{"update", "source_branch_changed", scm.ChangeActionSynchronize},
+		{"update", "update_label", scm.ChangeActionLabeled},
+		{"update", "", scm.ChangeActionEdited},
+		{"merge", "", scm.ChangeActionMerged},
+	}
+	for _, tt := range tests {
+		if got := pullRequestAction(tt.action, tt.desc); got != tt.want {
+			t.Errorf("pullRequestAction(%q, %q) = %q, want %q", tt.action, tt.desc, got, tt.want)
+		}
+	}
+}
@@ -28,6 +28,9 @@ import (
 	"github.com/valyala/fasthttp"
 )
 
+// githubMaxPushCommits is the number of commits a push payload lists at most.
+const githubMaxPushCommits = 2048
+
 type Provider struct {
 	cfg scm.ProviderConfig
 }
@@ -43,6 +46,8 @@ func (p *Provider) Capabilities() scm.CapSet {
 		scm.CapWebhookVerify: true,
 		scm.CapWebhookParse:  true,
 		scm.CapPollEvents:    true,
+		scm.CapCommitStatus:  true,
+		scm.CapCompare:       true,
 	}
 }
 
@@ -161,6 +166,83 @@ func (p *Provider) CreateChangeRequest(ctx context.Context, req scm.ChangeReques
 	return out.HTMLURL, nil
 }
 
+// ReportCommitStatus creates a GitHub commit status.
+func (p *Provider) ReportCommitStatus(ctx context.Context, status scm.CommitStatus) error {
+	if err := status.Validate(); err != nil {
+		return err
+	}
+	endpoint := p.apiBaseURL() + fmt.Sprintf(
+		"/repos/%s/%s/statuses/%s",
+		url.PathEscape(status.Repo.Owner),
+		url.PathEscape(status.Repo.Name),
+		url.PathEscape(status.SHA),
+	)
+	resp, err := request.NewRequest(
+		endpoint,
+		fasthttp.MethodPost,
+		map[string]string{
+			"Accept":        "application/vnd.github+json",
+			"Authorization": "Bearer " + strings.TrimSpace(p.cfg.Token),
+		},
+		nil,
+	).
+		WithBodyJSON(map[string]any{
+			"state":       string(status.State),
+			"target_url":  status.TargetURL,
+			"description": status.ShortDescription(),
+			"context":     status.Context,
+		}).
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+	if resp == nil || resp.StatusCode() >= 400 {
+		return fmt.Errorf("github create commit status failed: %d", resp.StatusCode())
+	}
+	return nil
+}
+
+// ListChangedFiles lists the files changed between base and head through the
+// GitHub compare API.
+func (p *Provider) ListChangedFiles(ctx context.Context, repo scm.Repo, base, head string) ([]string, error) {
+	if repo.Owner == "" || repo.Name == "" {
+		return nil, fmt.Errorf("repo owner/name is required")
+	}
+	endpoint := p.apiBaseURL() + fmt.Sprintf(
+		"/repos/%s/%s/compare/%s...%s",
+		url.PathEscape(repo.Owner),
+		url.PathEscape(repo.Name),
+		url.PathEscape(base),
+		url.PathEscape(head),
+	)
+	var out struct {
+		Files []struct {
+			Filename         string `json:"filename"`
+			PreviousFilename string `json:"previous_filename"`
+		} `json:"files"`
+	}
+	resp, err := request.NewRequest(
+		endpoint,
+		fasthttp.MethodGet,
+		map[string]string{
+			"Accept":        "application/vnd.github+json",
+			"Authorization": "Bearer " + strings.TrimSpace(p.cfg.Token),
+		},
+		nil,
+	).WithResult(&out).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.StatusCode() >= 400 {
+		return nil, fmt.Errorf("github compare commits failed: %d", resp.StatusCode())
+	}
+	files := make([]string, 0, len(out.Files))
+	for _, f := range out.Files {
+		files = append(files, f.Filename, f.PreviousFilename)
+	}
+	return scm.CollectChangedFiles(files), nil
+}
+
 func (p *Provider) apiBaseURL() string {
 	apiBase := strings.TrimSpace(p.cfg.APIBaseURL)
 	if apiBase == "" {
@@ -298,11 +380,16 @@ func (p *Provider) parsePullRequest(body []byte) ([]scm.Event, error) {
 			MergedAt  *time.Time `json:"merged_at"`
 			MergeSha  string     `json:"merge_commit_sha"`
 			UpdatedAt *time.Time `json:"updated_at"`
-			Base      struct {
+			Labels    []struct {
+				Name string `json:"name"`
+			} `json:"labels"`
+			Base struct {
 				Ref string `json:"ref"`
+				Sha string `json:"sha"`
 			} `json:"base"`
 			Head struct {
 				Ref string `json:"ref"`
+				Sha string `json:"sha"`
 			} `json:"head"`
 		} `json:"pull_request"`
 	}
@@ -327,8 +414,14 @@ func (p *Provider) parsePullRequest(body []byte) ([]scm.Event, error) {
 	}
 
 	t := scm.EventTypePullRequest
+	action := payload.Action
 	if payload.Action == "closed" && payload.PullRequest.Merged {
 		t = scm.EventTypePullMerged
+		action = scm.ChangeActionMerged
+	}
+	labels := make([]string, 0, len(payload.PullRequest.Labels))
+	for _, l := range payload.PullRequest.Labels {
+		labels = append(labels, l.Name)
 	}
 	return []scm.Event{{
 		ProviderKind: scm.ProviderKindGitHub,
@@ -340,8 +433,12 @@ func (p *Provider) parsePullRequest(body []byte) ([]scm.Event, error) {
 		Change: &scm.Change{
 			Number:        payload.PullRequest.Number,
 			Title:         payload.PullRequest.Title,
+			Action:        action,
 			SourceBranch:  payload.PullRequest.Head.Ref,
 			TargetBranch:  payload.PullRequest.Base.Ref,
+			BaseCommitID:  payload.PullRequest.Base.Sha,
+			HeadCommitID:  payload.PullRequest.Head.Sha,
+			Labels:        labels,
 			State:         payload.PullRequest.State,
 			IsMerged:      payload.PullRequest.Merged,
 			MergeCommitID: payload.PullRequest.MergeSha,
@@ -352,10 +449,17 @@ func (p *Provider) parsePullRequest(body []byte) ([]scm.Event, error) {
 func (p *Provider) parsePush(body []byte) ([]scm.Event, error) {
 	var payload struct {
 		Ref        string `json:"ref"`
+		Before     string `json:"before"`
 		HeadCommit struct {
 			ID        string    `json:"id"`
+			Message   string    `json:"message"`
 			Timestamp time.Time `json:"timestamp"`
 		} `json:"head_commit"`
+		Commits []struct {
+			Added    []string `json:"added"`
+			Removed  []string `json:"removed"`
+			Modified []string `json:"modified"`
+		} `json:"commits"`
 		Repository struct {
 			HTMLURL string `json:"html_url"`
 			Name    string `json:"name"`
@@ -391,14 +495,25 @@ func (p *Provider) parsePush(body []byte) ([]scm.Event, error) {
 	if !payload.HeadCommit.Timestamp.IsZero() {
 		occurred = payload.HeadCommit.Timestamp
 	}
+	// GitHub caps the commit list; leave files unknown so they are compared.
+	var files []string
+	if len(payload.Commits) > 0 && len(payload.Commits) < githubMaxPushCommits {
+		files = []string{}
+		for _, c := range payload.Commits {
+			files = scm.CollectChangedFiles(files, c.Added, c.Removed, c.Modified)
+		}
+	}
 	return []scm.Event{{
-		ProviderKind: scm.ProviderKindGitHub,
-		EventType:    t,
-		Repo:         repo,
-		ActorName:    payload.Pusher.Name,
-		CommitID:     payload.HeadCommit.ID,
-		Ref:          payload.Ref,
-		OccurredAt:   occurred,
+		ProviderKind:   scm.ProviderKindGitHub,
+		EventType:      t,
+		Repo:           repo,
+		ActorName:      payload.Pusher.Name,
+		CommitID:       payload.HeadCommit.ID,
+		BeforeCommitID: payload.Before,
+		Message:        payload.HeadCommit.Message,
+		ChangedFiles:   files,
+		Ref:            payload.Ref,
+		OccurredAt:     occurred,
 	}}, nil
 }
 
@@ -0,0 +1,184 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/arcentrix/arcentra/pkg/scm"
+)
+
+// capturedRequest is what the stand-in API received.
+type capturedRequest struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   map[string]any
+}
+
+// apiServer stands in for the GitHub API. It answers every request with
+// code and response and hands over each request it receives.
+func apiServer(t *testing.T, code int, response string) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+	reqs := make(chan capturedRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]any{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		reqs <- capturedRequest{
+			method: r.Method,
+			path:   r.URL.EscapedPath(),
+			query:  r.URL.Query(),
+			header: r.Header.Clone(),
+			body:   body,
+		}
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, reqs
+}
+
+func statusServer(t *testing.T, code int) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+	return apiServer(t, code, `{}`)
+}
+
+func testStatus(state scm.CommitState) scm.CommitStatus {
+	return scm.CommitStatus{
+		Repo:        scm.Repo{Owner: "acme", Name: "app"},
+		SHA:         "0123abcd",
+		State:       state,
+		Context:     "arcentra/build",
+		Description: "Pipeline running",
+		TargetURL:   "https://ci.example.com/pipelines/p1/runs/r1",
+	}
+}
+
+func TestReportCommitStatus(t *testing.T) {
+	srv, reqs := statusServer(t, http.StatusCreated)
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGitHub, APIBaseURL: srv.URL, Token: "tkn"})
+
+	if err := p.ReportCommitStatus(context.Background(), testStatus(scm.CommitStateFailure)); err != nil {
+		t.Fatalf("ReportCommitStatus: %v", err)
+	}
+	req := <-reqs
+	body := req.body
+	if req.method != http.MethodPost {
+		t.Fatalf("unexpected method: %s", req.method)
+	}
+	if req.path != "/repos/acme/app/statuses/0123abcd" {
+		t.Fatalf("unexpected path: %s", req.path)
+	}
+	if got := req.header.Get("Authorization"); got != "Bearer tkn" {
+		t.Fatalf("unexpected authorization: %q", got)
+	}
+	if body["state"] != "failure" {
+		t.Fatalf("unexpected state: %v", body["state"])
+	}
+	if body["context"] != "arcentra/build" {
+		t.Fatalf("unexpected context: %v", body["context"])
+	}
+	if body["target_url"] != "https://ci.example.com/pipelines/p1/runs/r1" {
+		t.Fatalf("unexpected target_url: %v", body["target_url"])
+	}
+	if body["description"] != "Pipeline running" {
+		t.Fatalf("unexpected description: %v", body["description"])
+	}
+}
+
+func TestReportCommitStatus_APIError(t *testing.T) {
+	srv, _ := statusServer(t, http.StatusUnprocessableEntity)
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGitHub, APIBaseURL: srv.URL, Token: "tkn"})
+
+	if err := p.ReportCommitStatus(context.Background(), testStatus(scm.CommitStateSuccess)); err == nil {
+		t.Fatalf("expected error for API failure")
+	}
+}
+
+func TestReportCommitStatus_Invalid(t *testing.T) {
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGitHub, APIBaseURL: "http://127.0.0.1:1", Token: "tkn"})
+	st := testStatus(scm.CommitStateSuccess)
+	st.SHA = ""
+	if err := p.ReportCommitStatus(context.Background(), st); err == nil {
+		t.Fatalf("expected validation error")
+	}
+}
+
+func TestListChangedFiles(t *testing.T) {
+	srv, reqs := apiServer(t, http.StatusOK,
+		`{"files":[{"filename":"b/new.go","previous_filename":"b/old.go"},{"filename":"a.go"}]}`)
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGitHub, APIBaseURL: srv.URL, Token: "tkn"})
+
+	files, err := p.ListChangedFiles(context.Background(), scm.Repo{Owner: "acme", Name: "app"}, "base1", "head2")
+	if err != nil {
+		t.Fatalf("ListChangedFiles: %v", err)
+	}
+	req := <-reqs
+	if req.path != "/repos/acme/app/compare/base1...head2" {
+		t.Fatalf("unexpected path: %s", req.path)
+	}
+	if want := []string{"a.go", "b/new.go", "b/old.go"}; !reflect.DeepEqual(files, want) {
+		t.Fatalf("files = %v, want %v", files, want)
+	}
+}
+
+func TestParseWebhook_Push(t *testing.T) {
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGitHub})
+	body := `{"ref":"refs/heads/main","before":"b0","head_commit":{"id":"h1","message":"fix [skip ci]"},
+		"commits":[{"added":["docs/a.md"],"modified":["src/x.go"]},{"removed":["src/y.go"]}],
+		"repository":{"name":"app","owner":{"login":"acme"}}}`
+	events, err := p.ParseWebhook(context.Background(), scm.WebhookRequest{
+		Headers: map[string]string{"X-GitHub-Event": "push"},
+		Body:    []byte(body),
+	})
+	if err != nil || len(events) != 1 {
+		t.Fatalf("ParseWebhook: %v, %d events", err, len(events))
+	}
+	ev := events[0]
+	if ev.BeforeCommitID != "b0" || ev.Message != "fix [skip ci]" {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+	if want := []string{"docs/a.md", "src/x.go", "src/y.go"}; !reflect.DeepEqual(ev.ChangedFiles, want) {
+		t.Fatalf("files = %v, want %v", ev.ChangedFiles, want)
+	}
+}
+
+func TestParseWebhook_PullRequest(t *testing.T) {
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGitHub})
+	body := `{"action":"labeled","repository":{"name":"app","owner":{"login":"acme"}},
+		"pull_request":{"number":7,"labels":[{"name":"run-e2e"}],
+		"base":{"ref":"main","sha":"b1"},"head":{"ref":"feature","sha":"h1"}}}`
+	events, err := p.ParseWebhook(context.Background(), scm.WebhookRequest{
+		Headers: map[string]string{"X-GitHub-Event": "pull_request"},
+		Body:    []byte(body),
+	})
+	if err != nil || len(events) != 1 || events[0].Change == nil {
+		t.Fatalf("ParseWebhook: %v, %v", err, events)
+	}
+	c := events[0].Change
+	if c.Action != scm.ChangeActionLabeled || c.BaseCommitID != "b1" || c.HeadCommitID != "h1" {
+		t.Fatalf("unexpected change: %+v", c)
+	}
+	if !reflect.DeepEqual(c.Labels, []string{"run-e2e"}) {
+		t.Fatalf("unexpected labels: %v", c.Labels)
+	}
+}
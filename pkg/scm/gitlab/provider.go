@@ -42,6 +42,8 @@ func (p *Provider) Capabilities() scm.CapSet {
 		scm.CapWebhookVerify: true,
 		scm.CapWebhookParse:  true,
 		scm.CapPollEvents:    true,
+		scm.CapCommitStatus:  true,
+		scm.CapCompare:       true,
 	}
 }
 
@@ -236,6 +238,88 @@ func (p *Provider) CreateChangeRequest(ctx context.Context, req scm.ChangeReques
 	return out.WebURL, nil
 }
 
+// ReportCommitStatus sets a GitLab commit status. GitLab has no "error"
+// state, errors are reported as failed.
+func (p *Provider) ReportCommitStatus(ctx context.Context, status scm.CommitStatus) error {
+	if err := status.Validate(); err != nil {
+		return err
+	}
+	state := map[scm.CommitState]string{
+		scm.CommitStatePending: "running",
+		scm.CommitStateSuccess: "success",
+		scm.CommitStateFailure: "failed",
+		scm.CommitStateError:   "failed",
+	}[status.State]
+	projectPath := status.Repo.FullName
+	if projectPath == "" {
+		projectPath = status.Repo.Owner + "/" + status.Repo.Name
+	}
+	endpoint := p.apiBaseURL() + "/projects/" + url.PathEscape(projectPath) + "/statuses/" + url.PathEscape(status.SHA)
+	resp, err := request.NewRequest(
+		endpoint,
+		fasthttp.MethodPost,
+		map[string]string{
+			"PRIVATE-TOKEN": strings.TrimSpace(p.cfg.Token),
+		},
+		nil,
+	).
+		WithBodyJSON(map[string]any{
+			"state":       state,
+			"name":        status.Context,
+			"target_url":  status.TargetURL,
+			"description": status.ShortDescription(),
+		}).
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+	if resp == nil || resp.StatusCode() >= 400 {
+		return fmt.Errorf("gitlab create commit status failed: %d", resp.StatusCode())
+	}
+	return nil
+}
+
+// ListChangedFiles lists the files changed between base and head through the
+// GitLab repository compare API.
+func (p *Provider) ListChangedFiles(ctx context.Context, repo scm.Repo, base, head string) ([]string, error) {
+	projectPath := repo.FullName
+	if projectPath == "" {
+		projectPath = repo.Owner + "/" + repo.Name
+	}
+	if strings.Trim(projectPath, "/") == "" {
+		return nil, fmt.Errorf("repo owner/name is required")
+	}
+	var out struct {
+		Diffs []struct {
+			OldPath string `json:"old_path"`
+			NewPath string `json:"new_path"`
+		} `json:"diffs"`
+	}
+	resp, err := request.NewRequest(
+		p.apiBaseURL()+"/projects/"+url.PathEscape(projectPath)+"/repository/compare",
+		fasthttp.MethodGet,
+		map[string]string{
+			"PRIVATE-TOKEN": strings.TrimSpace(p.cfg.Token),
+		},
+		nil,
+	).WithQueryParams(map[string]string{
+		"from":     base,
+		"to":       head,
+		"straight": "false",
+	}).WithResult(&out).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.StatusCode() >= 400 {
+		return nil, fmt.Errorf("gitlab compare commits failed: %d", resp.StatusCode())
+	}
+	files := make([]string, 0, len(out.Diffs)*2)
+	for _, d := range out.Diffs {
+		files = append(files, d.OldPath, d.NewPath)
+	}
+	return scm.CollectChangedFiles(files), nil
+}
+
 func (p *Provider) apiBaseURL() string {
 	apiBase := strings.TrimSpace(p.cfg.APIBaseURL)
 	if apiBase == "" {
@@ -257,11 +341,18 @@ func (p *Provider) parseMergeRequest(body []byte) ([]scm.Event, error) {
 			WebURL            string `json:"web_url"`
 			PathWithNamespace string `json:"path_with_namespace"`
 		} `json:"project"`
+		Labels []struct {
+			Title string `json:"title"`
+		} `json:"labels"`
+		Changes struct {
+			Labels *struct{} `json:"labels"`
+		} `json:"changes"`
 		ObjectAttributes struct {
 			Iid          int    `json:"iid"`
 			Title        string `json:"title"`
 			State        string `json:"state"`
 			Action       string `json:"action"`
+			Oldrev       string `json:"oldrev"`
 			SourceBranch string `json:"source_branch"`
 			TargetBranch string `json:"target_branch"`
 			LastCommit   struct {
@@ -302,6 +393,10 @@ func (p *Provider) parseMergeRequest(body []byte) ([]scm.Event, error) {
 	if commitID == "" {
 		commitID = payload.ObjectAttributes.LastCommit.ID
 	}
+	labels := make([]string, 0, len(payload.Labels))
+	for _, l := range payload.Labels {
+		labels = append(labels, l.Title)
+	}
 
 	return []scm.Event{{
 		ProviderKind: scm.ProviderKindGitLab,
@@ -313,8 +408,11 @@ func (p *Provider) parseMergeRequest(body []byte) ([]scm.Event, error) {
 		Change: &scm.Change{
 			Number:        payload.ObjectAttributes.Iid,
 			Title:         payload.ObjectAttributes.Title,
+			Action:        mergeRequestAction(payload.ObjectAttributes.Action, payload.ObjectAttributes.Oldrev, payload.Changes.Labels != nil),
 			SourceBranch:  payload.ObjectAttributes.SourceBranch,
 			TargetBranch:  payload.ObjectAttributes.TargetBranch,
+			HeadCommitID:  payload.ObjectAttributes.LastCommit.ID,
+			Labels:        labels,
 			State:         payload.ObjectAttributes.State,
 			IsMerged:      t == scm.EventTypeMergeRequestMerged,
 			MergeCommitID: payload.ObjectAttributes.MergeCommitSha,
@@ -322,14 +420,49 @@ func (p *Provider) parseMergeRequest(body []byte) ([]scm.Event, error) {
 	}}, nil
 }
 
+// mergeRequestAction maps a GitLab merge request action to the normalized
+// action. Updates that move the source branch report an oldrev.
+func mergeRequestAction(action, oldrev string, labelsChanged bool) string {
+	switch action {
+	case "open":
+		return scm.ChangeActionOpened
+	case "reopen":
+		return scm.ChangeActionReopened
+	case "close":
+		return scm.ChangeActionClosed
+	case "merge":
+		return scm.ChangeActionMerged
+	case "update":
+		switch {
+		case oldrev != "":
+			return scm.ChangeActionSynchronize
+		case labelsChanged:
+			return scm.ChangeActionLabeled
+		default:
+			return scm.ChangeActionEdited
+		}
+	default:
+		return action
+	}
+}
+
 type refPayload struct {
 	Ref     string `json:"ref"`
 	Project struct {
 		WebURL            string `json:"web_url"`
 		PathWithNamespace string `json:"path_with_namespace"`
 	} `json:"project"`
-	UserName string `json:"user_name"`
-	After    string `json:"after"`
+	UserName          string `json:"user_name"`
+	Before            string `json:"before"`
+	After             string `json:"after"`
+	TotalCommitsCount int    `json:"total_commits_count"`
+	Commits           []struct {
+		ID       string   `json:"id"`
+		Message  string   `json:"message"`
+		Added    []string `json:"added"`
+		Removed  []string `json:"removed"`
+		Modified []string `json:"modified"`
+	} `json:"commits"`
 }
 
 func (p *Provider) parseRefEvent(body []byte, eventType scm.EventType) ([]scm.Event, error) {
@@ -348,14 +481,31 @@ func (p *Provider) parseRefEvent(body []byte, eventType scm.EventType) ([]scm.Ev
 		FullName: payload.Project.PathWithNamespace,
 		URL:      payload.Project.WebURL,
 	}
+	// GitLab lists at most 20 commits; leave files unknown so they are compared.
+	var files []string
+	message := ""
+	if len(payload.Commits) > 0 && len(payload.Commits) >= payload.TotalCommitsCount {
+		files = []string{}
+	}
+	for _, c := range payload.Commits {
+		if files != nil {
+			files = scm.CollectChangedFiles(files, c.Added, c.Removed, c.Modified)
+		}
+		if c.ID == payload.After {
+			message = c.Message
+		}
+	}
 	return []scm.Event{{
-		ProviderKind: scm.ProviderKindGitLab,
-		EventType:    eventType,
-		Repo:         repo,
-		ActorName:    payload.UserName,
-		CommitID:     payload.After,
-		Ref:          payload.Ref,
-		OccurredAt:   time.Now(),
+		ProviderKind:   scm.ProviderKindGitLab,
+		EventType:      eventType,
+		Repo:           repo,
+		ActorName:      payload.UserName,
+		CommitID:       payload.After,
+		BeforeCommitID: payload.Before,
+		Message:        message,
+		ChangedFiles:   files,
+		Ref:            payload.Ref,
+		OccurredAt:     time.Now(),
 	}}, nil
 }
 
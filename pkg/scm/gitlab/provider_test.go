@@ -0,0 +1,199 @@
+// Copyright 2026 Arcentra Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/arcentrix/arcentra/pkg/scm"
+)
+
+// capturedRequest is what the stand-in API received.
+type capturedRequest struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   map[string]any
+}
+
+// apiServer stands in for the GitLab API. It answers every request with
+// code and response and hands over each request it receives.
+func apiServer(t *testing.T, code int, response string) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+	reqs := make(chan capturedRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]any{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		reqs <- capturedRequest{
+			method: r.Method,
+			path:   r.URL.EscapedPath(),
+			query:  r.URL.Query(),
+			header: r.Header.Clone(),
+			body:   body,
+		}
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, reqs
+}
+
+func statusServer(t *testing.T, code int) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+	return apiServer(t, code, `{}`)
+}
+
+func testStatus(state scm.CommitState) scm.CommitStatus {
+	return scm.CommitStatus{
+		Repo:        scm.Repo{Owner: "acme", Name: "app", FullName: "acme/app"},
+		SHA:         "0123abcd",
+		State:       state,
+		Context:     "arcentra/build",
+		Description: "Pipeline running",
+		TargetURL:   "https://ci.example.com/pipelines/p1/runs/r1",
+	}
+}
+
+func TestReportCommitStatus(t *testing.T) {
+	srv, reqs := statusServer(t, http.StatusCreated)
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGitLab, APIBaseURL: srv.URL, Token: "tkn"})
+
+	if err := p.ReportCommitStatus(context.Background(), testStatus(scm.CommitStateError)); err != nil {
+		t.Fatalf("ReportCommitStatus: %v", err)
+	}
+	req := <-reqs
+	body := req.body
+	if req.method != http.MethodPost {
+		t.Fatalf("unexpected method: %s", req.method)
+	}
+	if req.path != "/projects/acme%2Fapp/statuses/0123abcd" {
+		t.Fatalf("unexpected path: %s", req.path)
+	}
+	if got := req.header.Get("PRIVATE-TOKEN"); got != "tkn" {
+		t.Fatalf("unexpected token: %q", got)
+	}
+	if body["state"] != "failed" {
+		t.Fatalf("unexpected state: %v", body["state"])
+	}
+	if body["name"] != "arcentra/build" {
+		t.Fatalf("unexpected name: %v", body["name"])
+	}
+	if body["target_url"] != "https://ci.example.com/pipelines/p1/runs/r1" {
+		t.Fatalf("unexpected target_url: %v", body["target_url"])
+	}
+}
+
+func TestReportCommitStatus_APIError(t *testing.T) {
+	srv, _ := statusServer(t, http.StatusUnprocessableEntity)
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGitLab, APIBaseURL: srv.URL, Token: "tkn"})
+
+	if err := p.ReportCommitStatus(context.Background(), testStatus(scm.CommitStateSuccess)); err == nil {
+		t.Fatalf("expected error for API failure")
+	}
+}
+
+func TestReportCommitStatus_Invalid(t *testing.T) {
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGitLab, APIBaseURL: "http://127.0.0.1:1", Token: "tkn"})
+	st := testStatus(scm.CommitStateSuccess)
+	st.SHA = ""
+	if err := p.ReportCommitStatus(context.Background(), st); err == nil {
+		t.Fatalf("expected validation error")
+	}
+}
+
+func TestListChangedFiles(t *testing.T) {
+	srv, reqs := apiServer(t, http.StatusOK,
+		`{"diffs":[{"old_path":"a.go","new_path":"a.go"},{"old_path":"old.go","new_path":"new.go"}]}`)
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGitLab, APIBaseURL: srv.URL, Token: "tkn"})
+
+	files, err := p.ListChangedFiles(context.Background(),
+		scm.Repo{Owner: "acme", Name: "app", FullName: "acme/app"}, "main", "h1")
+	if err != nil {
+		t.Fatalf("ListChangedFiles: %v", err)
+	}
+	req := <-reqs
+	if req.path != "/projects/acme%2Fapp/repository/compare" {
+		t.Fatalf("unexpected path: %s", req.path)
+	}
+	if req.query.Get("from") != "main" || req.query.Get("to") != "h1" {
+		t.Fatalf("unexpected query: %v", req.query)
+	}
+	if want := []string{"a.go", "new.go", "old.go"}; !reflect.DeepEqual(files, want) {
+		t.Fatalf("files = %v, want %v", files, want)
+	}
+}
+
+func TestParseWebhook_Push(t *testing.T) {
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGitLab})
+	body := `{"ref":"refs/heads/main","before":"b0","after":"h1","total_commits_count":2,
+		"project":{"path_with_namespace":"acme/app"},
+		"commits":[{"id":"c0","message":"one","added":["a.go"]},{"id":"h1","message":"two","modified":["b.go"]}]}`
+	events, err := p.ParseWebhook(context.Background(), scm.WebhookRequest{
+		Headers: map[string]string{"X-Gitlab-Event": "Push Hook"},
+		Body:    []byte(body),
+	})
+	if err != nil || len(events) != 1 {
+		t.Fatalf("ParseWebhook: %v, %d events", err, len(events))
+	}
+	ev := events[0]
+	if ev.BeforeCommitID != "b0" || ev.Message != "two" {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+	if want := []string{"a.go", "b.go"}; !reflect.DeepEqual(ev.ChangedFiles, want) {
+		t.Fatalf("files = %v, want %v", ev.ChangedFiles, want)
+	}
+}
+
+func TestParseWebhook_PushTruncated(t *testing.T) {
+	p, _ := New(scm.ProviderConfig{Kind: scm.ProviderKindGitLab})
+	body := `{"ref":"refs/heads/main","after":"h1","total_commits_count":40,
+		"project":{"path_with_namespace":"acme/app"},"commits":[{"id":"h1","added":["a.go"]}]}`
+	events, err := p.ParseWebhook(context.Background(), scm.WebhookRequest{
+		Headers: map[string]string{"X-Gitlab-Event": "Push Hook"},
+		Body:    []byte(body),
+	})
+	if err != nil || len(events) != 1 {
+		t.Fatalf("ParseWebhook: %v, %d events", err, len(events))
+	}
+	if events[0].ChangedFiles != nil {
+		t.Fatalf("expected unknown files, got %v", events[0].ChangedFiles)
+	}
+}
+
+func TestMergeRequestAction(t *testing.T) {
+	tests := []struct {
+		action, oldrev string
+		labels         bool
+		want           string
+	}{
+		{"open", "", false, scm.ChangeActionOpened},
+		{"update", "abc", false, scm.ChangeActionSynchronize},
+		{"update", "", true, scm.ChangeActionLabeled},
+		{"update", "", false, scm.ChangeActionEdited},
+		{"merge", "", false, scm.ChangeActionMerged},
+	}
+	for _, tt := range tests {
+		if got := mergeRequestAction(tt.action, tt.oldrev, tt.labels); got != tt.want {
+			t.Errorf("mergeRequestAction(%q, %q, %v) = %q, want %q", tt.action, tt.oldrev, tt.labels, got, tt.want)
+		}
+	}
+}
@@ -23,6 +23,9 @@ type ProviderConfig struct {
 	BaseURL    string       `json:"baseUrl,omitempty"`
 	APIBaseURL string       `json:"apiBaseUrl,omitempty"`
 	Token      string       `json:"token,omitempty"`
+	// Git holds the credentials of providers talking to the git server
+	// itself rather than to an HTTP API.
+	Git GitAuth `json:"-"`
 }
 
 // WebhookRequest is a minimal, transport-agnostic webhook request envelope.
@@ -64,6 +67,10 @@ type Provider interface {
 	PollEvents(ctx context.Context, repo Repo, cursor Cursor) (events []Event, nextCursor Cursor, err error)
 	// CreateChangeRequest creates PR/MR and returns url
 	CreateChangeRequest(ctx context.Context, req ChangeRequestInput) (string, error)
+	// ReportCommitStatus sets a build status on a commit
+	ReportCommitStatus(ctx context.Context, status CommitStatus) error
+	// ListChangedFiles lists the paths changed between two commits or branches
+	ListChangedFiles(ctx context.Context, repo Repo, base, head string) ([]string, error)
 }
 
 // equalFold compares two strings case-insensitively
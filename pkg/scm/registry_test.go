@@ -64,6 +64,14 @@ func (p *dummyProvider) CreateChangeRequest(_ context.Context, _ ChangeRequestIn
 	return "https://example.com/pr/1", nil
 }
 
+func (p *dummyProvider) ReportCommitStatus(_ context.Context, status CommitStatus) error {
+	return status.Validate()
+}
+
+func (p *dummyProvider) ListChangedFiles(_ context.Context, _ Repo, base, head string) ([]string, error) {
+	return []string{base + ".txt", head + ".txt"}, nil
+}
+
 func TestNewProvider_NotRegistered(t *testing.T) {
 	_, err := NewProvider(ProviderConfig{Kind: ProviderKind("not-exists")})
 	if err == nil {
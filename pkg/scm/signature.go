@@ -53,6 +53,14 @@ func VerifyHmacSha256Hex(body []byte, secret, headerValue, headerPrefix string)
 	return nil
 }
 
+// SignHmacSha256Hex returns the hex-encoded HMAC-SHA256 of body keyed by secret.
+// It is the signing counterpart of VerifyHmacSha256Hex.
+func SignHmacSha256Hex(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(strings.TrimSpace(secret)))
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 // VerifyTokenHeader verifies a shared-secret token carried in a header.
 // This is commonly used by providers that send the secret token directly.
 func VerifyTokenHeader(secret, got string) error {
@@ -45,6 +45,19 @@ func TestVerifyHmacSha256Hex_NoPrefix(t *testing.T) {
 	}
 }
 
+func TestSignHmacSha256Hex_RoundTrip(t *testing.T) {
+	body := []byte(`{"event":"pipeline.completed"}`)
+	secret := "s3cr3t"
+	sig := "sha256=" + SignHmacSha256Hex(body, secret)
+
+	if err := VerifyHmacSha256Hex(body, secret, sig, "sha256="); err != nil {
+		t.Fatalf("expected ok, got error: %v", err)
+	}
+	if err := VerifyHmacSha256Hex(body, "other", sig, "sha256="); err == nil {
+		t.Fatalf("expected mismatch with a different secret")
+	}
+}
+
 func TestVerifyTokenHeader(t *testing.T) {
 	if err := VerifyTokenHeader("token", "token"); err != nil {
 		t.Fatalf("expected ok, got error: %v", err)
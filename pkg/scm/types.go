@@ -27,6 +27,8 @@ const (
 	ProviderKindBitbucket ProviderKind = "bitbucket"
 	ProviderKindGitee     ProviderKind = "gitee"
 	ProviderKindGitea     ProviderKind = "gitea"
+	ProviderKindAzure     ProviderKind = "azuredevops"
+	ProviderKindGit       ProviderKind = "git"
 )
 
 type EventType string
@@ -50,24 +52,46 @@ type Repo struct {
 	URL      string `json:"url,omitempty"`
 }
 
+// Normalized PR/MR actions.
+const (
+	ChangeActionOpened      = "opened"
+	ChangeActionReopened    = "reopened"
+	ChangeActionSynchronize = "synchronize"
+	ChangeActionEdited      = "edited"
+	ChangeActionLabeled     = "labeled"
+	ChangeActionUnlabeled   = "unlabeled"
+	ChangeActionClosed      = "closed"
+	ChangeActionMerged      = "merged"
+)
+
 // Change describes a PR/MR change in a normalized form.
 type Change struct {
-	Number        int    `json:"number,omitempty"`
-	Title         string `json:"title,omitempty"`
-	SourceBranch  string `json:"sourceBranch,omitempty"`
-	TargetBranch  string `json:"targetBranch,omitempty"`
-	State         string `json:"state,omitempty"`
-	IsMerged      bool   `json:"isMerged,omitempty"`
-	MergeCommitID string `json:"mergeCommitId,omitempty"`
+	Number        int      `json:"number,omitempty"`
+	Title         string   `json:"title,omitempty"`
+	Action        string   `json:"action,omitempty"` // one of the ChangeAction constants
+	SourceBranch  string   `json:"sourceBranch,omitempty"`
+	TargetBranch  string   `json:"targetBranch,omitempty"`
+	BaseCommitID  string   `json:"baseCommitId,omitempty"`
+	HeadCommitID  string   `json:"headCommitId,omitempty"`
+	Labels        []string `json:"labels,omitempty"`
+	State         string   `json:"state,omitempty"`
+	IsMerged      bool     `json:"isMerged,omitempty"`
+	MergeCommitID string   `json:"mergeCommitId,omitempty"`
 }
 
 // Event is a normalized SCM event.
 type Event struct {
-	ProviderKind ProviderKind   `json:"providerKind"`
-	EventType    EventType      `json:"eventType"`
-	Repo         Repo           `json:"repo"`
-	ActorName    string         `json:"actorName,omitempty"`
-	CommitID     string         `json:"commitId,omitempty"`
+	ProviderKind ProviderKind `json:"providerKind"`
+	EventType    EventType    `json:"eventType"`
+	Repo         Repo         `json:"repo"`
+	ActorName    string       `json:"actorName,omitempty"`
+	CommitID     string       `json:"commitId,omitempty"`
+	// BeforeCommitID is the previous head of a pushed ref.
+	BeforeCommitID string `json:"beforeCommitId,omitempty"`
+	// Message is the head commit message of a push.
+	Message string `json:"message,omitempty"`
+	// ChangedFiles lists the paths touched by the event; nil when unknown.
+	ChangedFiles []string       `json:"changedFiles,omitempty"`
 	Ref          string         `json:"ref,omitempty"`
 	OccurredAt   time.Time      `json:"occurredAt"`
 	Change       *Change        `json:"change,omitempty"`
@@ -81,6 +105,8 @@ const (
 	CapWebhookVerify Capability = "webhook.verify"
 	CapWebhookParse  Capability = "webhook.parse"
 	CapPollEvents    Capability = "events.poll"
+	CapCommitStatus  Capability = "commit.status"
+	CapCompare       Capability = "commits.compare"
 )
 
 type CapSet map[Capability]bool
@@ -122,6 +148,30 @@ type GitHeadSHARequest struct {
 	Workdir string
 }
 
+type GitFetchRequest struct {
+	Workdir string
+	Remote  string
+	Ref     string
+	Auth    GitAuth
+}
+
+type GitCheckoutRequest struct {
+	Workdir string
+	Ref     string
+}
+
+type GitLsRemoteRequest struct {
+	RepoURL string
+	Auth    GitAuth
+}
+
+type GitDiffRequest struct {
+	RepoURL string
+	Base    string
+	Head    string
+	Auth    GitAuth
+}
+
 type GitAddRequest struct {
 	Workdir  string
 	FilePath string
@@ -36,6 +36,19 @@ type JobRunTaskPayload struct {
 	Timeout       string            `json:"timeout,omitempty"`
 	ArtifactURIs  map[string]string `json:"artifactUris,omitempty"`
 	Source        *SourcePayload    `json:"source,omitempty"`
+	// Outputs declares the job outputs, keyed by name, as expressions over
+	// step outputs. The Agent resolves them and reports the values.
+	Outputs map[string]string `json:"outputs,omitempty"`
+	// Services are sidecar containers the Agent starts before the steps.
+	Services []ServicePayload `json:"services,omitempty"`
+	// Runtime is the pipeline runtime; steps run in its image when set.
+	Runtime *RuntimePayload `json:"runtime,omitempty"`
+	// Secrets are the secret values available to the job. The Agent adds
+	// them to its mask set so they never reach the build logs.
+	Secrets []string `json:"secrets,omitempty"`
+	// KeepAliveOnFailure keeps the workspace and services of a failed job
+	// for this long (e.g. "15m") so a debug terminal can be opened into it.
+	KeepAliveOnFailure string `json:"keepAliveOnFailure,omitempty"`
 }
 
 // StepPayload describes a single step inside a JobRunTaskPayload.
@@ -50,6 +63,7 @@ type StepPayload struct {
 	ContinueOnError bool              `json:"continueOnError,omitempty"`
 	Timeout         string            `json:"timeout,omitempty"`
 	When            string            `json:"when,omitempty"`
+	Outputs         map[string]string `json:"outputs,omitempty"`
 }
 
 // SourcePayload describes source code configuration for a job.
@@ -59,6 +73,40 @@ type SourcePayload struct {
 	Branch string `json:"branch,omitempty"`
 }
 
+// RuntimePayload describes the container runtime the steps run in.
+type RuntimePayload struct {
+	Type      string            `json:"type,omitempty"`
+	Image     string            `json:"image,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+	Resources *ResourcesPayload `json:"resources,omitempty"`
+}
+
+// ResourcesPayload describes the resource requests and limits of a runtime.
+type ResourcesPayload struct {
+	CPURequest    string `json:"cpuRequest,omitempty"`
+	CPULimit      string `json:"cpuLimit,omitempty"`
+	MemoryRequest string `json:"memoryRequest,omitempty"`
+	MemoryLimit   string `json:"memoryLimit,omitempty"`
+}
+
+// ServicePayload describes a service container started next to a job.
+type ServicePayload struct {
+	Name        string              `json:"name"`
+	Image       string              `json:"image"`
+	Env         map[string]string   `json:"env,omitempty"`
+	Command     []string            `json:"command,omitempty"`
+	Args        []string            `json:"args,omitempty"`
+	HealthCheck *HealthCheckPayload `json:"healthCheck,omitempty"`
+}
+
+// HealthCheckPayload describes the readiness probe of a service.
+type HealthCheckPayload struct {
+	Command  []string `json:"command"`
+	Interval string   `json:"interval,omitempty"`
+	Timeout  string   `json:"timeout,omitempty"`
+	Retries  int32    `json:"retries,omitempty"`
+}
+
 // JobRunKey returns a composite key for the job run task.
 func JobRunKey(payload *JobRunTaskPayload) string {
 	if payload == nil {
@@ -38,6 +38,9 @@ type StepRunTaskPayload struct {
 	Workspace     string            `json:"workspace,omitempty"`
 	Timeout       string            `json:"timeout,omitempty"`
 	AgentID       string            `json:"agentId,omitempty"`
+	Outputs       map[string]string `json:"outputs,omitempty"`
+	Runtime       *RuntimePayload   `json:"runtime,omitempty"`
+	Secrets       []string          `json:"secrets,omitempty"`
 }
 
 // StepRunKey returns a composite key for the step run task.
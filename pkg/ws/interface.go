@@ -46,6 +46,12 @@ type Conn interface {
 
 	// SetContext 设置连接的上下文
 	SetContext(ctx context.Context)
+
+	// Locals 返回升级请求上由中间件设置的本地变量（如认证信息）
+	Locals(key string) any
+
+	// Query 返回升级请求的查询参数
+	Query(key string, defaultValue ...string) string
 }
 
 // Hub 管理所有 WebSocket 连接